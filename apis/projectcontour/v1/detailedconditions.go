@@ -87,6 +87,14 @@ type SubCondition struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MaxLength=32768
 	Message string `json:"message" protobuf:"bytes,4,opt,name=message"`
+	// Route identifies the route this sub-condition applies to by its
+	// match conditions, for example `prefix: /api, header: x-env exact staging`.
+	//
+	// This is empty if the sub-condition does not apply to a single route.
+	//
+	// +optional
+	// +kubebuilder:validation:MaxLength=4096
+	Route string `json:"route,omitempty" protobuf:"bytes,5,opt,name=route"`
 }
 
 // DetailedCondition is an extension of the normal Kubernetes conditions, with two extra
@@ -122,6 +122,19 @@ func (dc *DetailedCondition) AddErrorf(errorType, reason, formatmsg string, args
 	dc.AddError(errorType, reason, fmt.Sprintf(formatmsg, args...))
 }
 
+// AddRouteError adds an error-level Subcondition for the route identified by
+// route to the DetailedCondition. It otherwise behaves like AddError.
+func (dc *DetailedCondition) AddRouteError(route, errorType, reason, message string) {
+	dc.AddError(errorType, reason, message)
+	dc.Errors[len(dc.Errors)-1].Route = route
+}
+
+// AddRouteErrorf adds an error-level Subcondition for the route identified by
+// route to the DetailedCondition, using fmt.Sprintf on the formatmsg and args params.
+func (dc *DetailedCondition) AddRouteErrorf(route, errorType, reason, formatmsg string, args ...any) {
+	dc.AddRouteError(route, errorType, reason, fmt.Sprintf(formatmsg, args...))
+}
+
 // GetError gets an error of the given errorType.
 // Similar to a hash lookup, will return true in the second value if a match is
 // found, and false otherwise.
@@ -272,6 +272,41 @@ func TestAddErrorConditions(t *testing.T) {
 	}
 }
 
+func TestAddRouteError(t *testing.T) {
+	dc := &DetailedCondition{
+		Condition: Condition{
+			Type: "Valid",
+		},
+	}
+
+	dc.AddError(ConditionTypeVirtualHostError, "VhostReason", "We had a vhost error")
+	dc.AddRouteErrorf("prefix: /foo", ConditionTypeServiceError, "ServiceReason", "service %q not found", "missing")
+
+	assert.Equal(t, &DetailedCondition{
+		Condition: Condition{
+			Type:    "Valid",
+			Status:  ConditionFalse,
+			Reason:  "ErrorPresent",
+			Message: "At least one error present, see Errors for details",
+		},
+		Errors: []SubCondition{
+			{
+				Type:    "VirtualHostError",
+				Reason:  "VhostReason",
+				Message: "We had a vhost error",
+				Status:  ConditionTrue,
+			},
+			{
+				Type:    "ServiceError",
+				Reason:  "ServiceReason",
+				Message: `service "missing" not found`,
+				Status:  ConditionTrue,
+				Route:   "prefix: /foo",
+			},
+		},
+	}, dc)
+}
+
 func TestAddWarningConditions(t *testing.T) {
 	tests := map[string]struct {
 		dc            *DetailedCondition
@@ -80,6 +80,12 @@ type Include struct {
 	// on includes.
 	// +optional
 	Conditions []MatchCondition `json:"conditions,omitempty"`
+	// RequestHeadersPolicy defines how headers are managed during forwarding
+	// for each route of the included HTTPProxy. It is merged with the
+	// RequestHeadersPolicy of each included route, with values on the
+	// route taking precedence. Rewriting the Host header is not supported.
+	// +optional
+	RequestHeadersPolicy *HeadersPolicy `json:"requestHeadersPolicy,omitempty"`
 }
 
 // MatchCondition are a general holder for matching rules for HTTPProxies.
@@ -201,7 +207,7 @@ type QueryParameterMatchCondition struct {
 	Contains string `json:"contains,omitempty"`
 
 	// IgnoreCase specifies that string matching should be case insensitive.
-	// Note that this has no effect on the Regex parameter.
+	// It cannot be used with the Regex or Present parameters.
 	// +optional
 	IgnoreCase bool `json:"ignoreCase,omitempty"`
 
@@ -274,11 +280,51 @@ type AuthorizationServer struct {
 	// WithRequestBody specifies configuration for sending the client request's body to authorization server.
 	// +optional
 	WithRequestBody *AuthorizationServerBufferSettings `json:"withRequestBody,omitempty"`
+
+	// AuthorizationRequest configures which client request headers are
+	// sent to the authorization server.
+	// +optional
+	AuthorizationRequest *AuthorizationRequestHeaders `json:"authorizationRequest,omitempty"`
+
+	// AuthorizationResponse configures which headers the authorization
+	// server may set on the client request.
+	// +optional
+	AuthorizationResponse *AuthorizationResponseHeaders `json:"authorizationResponse,omitempty"`
+}
+
+// AuthorizationRequestHeaders controls the client request headers that are
+// sent to the authorization server. Header names are matched case-insensitively.
+type AuthorizationRequestHeaders struct {
+	// AllowedHeaders lists the client request headers that are sent to
+	// the authorization server. If not set, all headers are sent.
+	// +optional
+	AllowedHeaders []string `json:"allowedHeaders,omitempty"`
+
+	// DisallowedHeaders lists client request headers that are never sent
+	// to the authorization server, even if they are also allowed.
+	// +optional
+	DisallowedHeaders []string `json:"disallowedHeaders,omitempty"`
+}
+
+// AuthorizationResponseHeaders controls the headers that the authorization
+// server may set on the client request. Header names are matched case-insensitively.
+type AuthorizationResponseHeaders struct {
+	// DisallowedHeaders lists headers that the authorization server is
+	// not permitted to add to or overwrite on the client request. Such
+	// header mutations are ignored.
+	// +optional
+	DisallowedHeaders []string `json:"disallowedHeaders,omitempty"`
 }
 
+// MaxAuthorizationRequestBytes is the largest MaxRequestBytes accepted in
+// AuthorizationServerBufferSettings, since Envoy buffers the request body
+// in memory.
+const MaxAuthorizationRequestBytes uint32 = 8 * 1024 * 1024
+
 // AuthorizationServerBufferSettings enables ExtAuthz filter to buffer client request data and send it as part of authorization request
 type AuthorizationServerBufferSettings struct {
 	// MaxRequestBytes sets the maximum size of message body ExtAuthz filter will hold in-memory.
+	// Values larger than 8MiB are rejected.
 	// +optional
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:default=1024
@@ -320,6 +366,17 @@ type VirtualHost struct {
 	// +kubebuilder:validation:Pattern="^(\\*\\.)?[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$"
 	Fqdn string `json:"fqdn"`
 
+	// FqdnRegex is an RE2 regular expression that further restricts the
+	// hostnames served by a wildcard Fqdn. When set, Fqdn must be a
+	// wildcard (e.g. `*.example.com`) and requests are only routed if
+	// the whole hostname, excluding any port, matches FqdnRegex. This
+	// replaces the single DNS label restriction of wildcard Fqdns.
+	// FqdnRegex cannot be used with TCPProxy.
+	//
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	FqdnRegex string `json:"fqdnRegex,omitempty"`
+
 	// If present the fields describes TLS properties of the virtual
 	// host. The SNI names that will be matched on are described in fqdn,
 	// the tls.secretName secret must contain a certificate that itself
@@ -358,6 +415,68 @@ type VirtualHost struct {
 	// Only one of IPAllowFilterPolicy and IPDenyFilterPolicy can be defined.
 	// The rules defined here may be overridden in a Route.
 	IPDenyFilterPolicy []IPFilterPolicy `json:"ipDenyPolicy,omitempty"`
+
+	// PathNormalizationPolicy overrides the Contour-wide request path
+	// normalization settings for this virtual host.
+	// +optional
+	PathNormalizationPolicy *PathNormalizationPolicy `json:"pathNormalizationPolicy,omitempty"`
+
+	// AllowHTTP01Challenge exempts requests for the ACME HTTP-01
+	// challenge path, /.well-known/acme-challenge/, from the HTTPS
+	// redirect applied to insecure requests, so certificate issuers
+	// such as cert-manager can complete challenges over HTTP.
+	// It requires TLS to be configured on the virtual host.
+	// +optional
+	AllowHTTP01Challenge bool `json:"allowHTTP01Challenge,omitempty"`
+
+	// PermitInsecure allows every route of this virtual host, including
+	// routes of included HTTPProxies, to respond to insecure requests
+	// over HTTP which are normally not permitted when a `tls` block is
+	// present. A route can opt out by setting requireTLS.
+	// +optional
+	PermitInsecure bool `json:"permitInsecure,omitempty"`
+
+	// NotFoundResponse defines the response returned for requests that
+	// match none of the virtual host's routes. If unset, Envoy returns
+	// an empty 404 response.
+	// +optional
+	NotFoundResponse *NotFoundResponse `json:"notFoundResponse,omitempty"`
+}
+
+// NotFoundResponse defines the response returned by a virtual host for
+// requests that match none of its routes.
+type NotFoundResponse struct {
+	// StatusCode is the HTTP response status to be returned.
+	// +required
+	// +kubebuilder:validation:Minimum=400
+	// +kubebuilder:validation:Maximum=599
+	StatusCode int `json:"statusCode"`
+
+	// Body is the content of the response body.
+	// If this setting is omitted, no body is included in the generated response.
+	// +optional
+	Body string `json:"body,omitempty"`
+
+	// ContentType sets the Content-Type header of the response,
+	// e.g. `application/json`.
+	// +optional
+	ContentType string `json:"contentType,omitempty"`
+}
+
+// PathNormalizationPolicy defines how request paths are normalized before
+// they are matched against routes.
+//
+// These settings are applied to Envoy's HTTP connection manager, so they only
+// take effect for requests terminated by this virtual host's own TLS filter
+// chain. Insecure requests and requests using the fallback certificate share a
+// connection manager with other virtual hosts and always use the Contour-wide
+// settings.
+type PathNormalizationPolicy struct {
+	// MergeSlashes controls whether adjacent slashes in the request path are
+	// merged into one, e.g. `//foo` becomes `/foo`. If unset, the
+	// Contour-wide `disableMergeSlashes` setting applies.
+	// +optional
+	MergeSlashes *bool `json:"mergeSlashes,omitempty"`
 }
 
 // JWTProvider defines how to verify JWTs on requests.
@@ -483,6 +602,45 @@ type TLS struct {
 	// EnableFallbackCertificate defines if the vhost should allow a default certificate to
 	// be applied which handles all requests which don't match the SNI defined in this vhost.
 	EnableFallbackCertificate bool `json:"enableFallbackCertificate,omitempty"`
+
+	// HSTS defines the Strict-Transport-Security header added to all
+	// responses from this vhost. It cannot be combined with Passthrough.
+	// +optional
+	HSTS *HSTSPolicy `json:"hsts,omitempty"`
+
+	// DisableSessionTickets stops Envoy from issuing TLS session tickets
+	// for this vhost, so clients cannot resume sessions without a full
+	// handshake. It cannot be combined with Passthrough.
+	// +optional
+	DisableSessionTickets bool `json:"disableSessionTickets,omitempty"`
+
+	// CipherSuites defines the TLS ciphers this vhost accepts when
+	// negotiating TLS 1.2, replacing the globally configured list.
+	// Ciphers are validated against the same set as the global list.
+	// It cannot be combined with Passthrough.
+	// +optional
+	CipherSuites []string `json:"cipherSuites,omitempty"`
+}
+
+// HSTSPolicy defines the HTTP Strict Transport Security (HSTS) policy
+// advertised to clients in the Strict-Transport-Security response header.
+type HSTSPolicy struct {
+	// MaxAge is the time, in seconds, that the client should remember
+	// that this host is only to be accessed using HTTPS. A value of
+	// 0 tells the client to forget the policy.
+	// +required
+	// +kubebuilder:validation:Minimum=0
+	MaxAge int64 `json:"maxAge"`
+
+	// IncludeSubDomains applies the policy to all subdomains of this host.
+	// +optional
+	IncludeSubDomains bool `json:"includeSubDomains,omitempty"`
+
+	// Preload signals consent to have this host included in browser
+	// HSTS preload lists. Preload requires IncludeSubDomains and a
+	// MaxAge of at least 31536000 seconds (one year).
+	// +optional
+	Preload bool `json:"preload,omitempty"`
 }
 
 // CORSHeaderValue specifies the value of the string headers returned by a cross-domain request.
@@ -548,6 +706,12 @@ type Route struct {
 	// not permitted when a `virtualhost.tls` block is present.
 	// +optional
 	PermitInsecure bool `json:"permitInsecure,omitempty"`
+	// RequireTLS redirects insecure requests over HTTP for this path to HTTPS,
+	// even when the virtual host sets permitInsecure. It cannot be combined
+	// with the route's own permitInsecure. When neither is set, the virtual
+	// host's permitInsecure setting is used.
+	// +optional
+	RequireTLS bool `json:"requireTLS,omitempty"`
 	// AuthPolicy updates the authorization policy that was set
 	// on the root HTTPProxy object for client requests that
 	// match this route.
@@ -625,6 +789,35 @@ type Route struct {
 	// Only one of IPAllowFilterPolicy and IPDenyFilterPolicy can be defined.
 	// The rules defined here override any rules set on the root HTTPProxy.
 	IPDenyFilterPolicy []IPFilterPolicy `json:"ipDenyPolicy,omitempty"`
+
+	// CORSPolicy specifies the cross-origin policy for this route.
+	// If set, it replaces the virtual host's CORS policy for requests
+	// matching this route. Routes without a CORS policy use the
+	// virtual host's CORS policy.
+	// +optional
+	CORSPolicy *CORSPolicy `json:"corsPolicy,omitempty"`
+
+	// FaultInjectionPolicy injects delays and aborts into a percentage
+	// of the requests to this route, for testing the resilience of clients.
+	// +optional
+	FaultInjectionPolicy *FaultInjectionPolicy `json:"faultInjectionPolicy,omitempty"`
+
+	// SessionPersistence sends requests that belong to the same session
+	// to the same upstream endpoint. Envoy stores the address of the
+	// endpoint chosen for the first request of a session in a cookie or
+	// header, and routes later requests carrying it to that endpoint.
+	// +optional
+	SessionPersistence *SessionPersistence `json:"sessionPersistence,omitempty"`
+
+	// Priority orders this route relative to other routes in the
+	// virtual host that have the same path match condition. Lower
+	// values are matched first, ahead of the method, header and
+	// query parameter conditions. Routes with equal priority fall
+	// back to ordering by those conditions. Defaults to 0.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=255
+	Priority int32 `json:"priority,omitempty"`
 }
 
 type JWTVerificationPolicy struct {
@@ -682,6 +875,146 @@ type HTTPDirectResponsePolicy struct {
 	//
 	// +optional
 	Body string `json:"body,omitempty"`
+
+	// WeightedBodies returns one of several response bodies, chosen for
+	// each request in proportion to the weight of the body. The weights
+	// must add up to 100. Only one of Body or WeightedBodies can be set.
+	//
+	// +optional
+	// +kubebuilder:validation:MinItems=2
+	WeightedBodies []WeightedDirectResponseBody `json:"weightedBodies,omitempty"`
+
+	// BodyConfigMapRef sources the response body from a key of a ConfigMap
+	// in the same namespace as the HTTPProxy. The ConfigMap must have the
+	// `projectcontour.io/direct-response` label. The body may contain the
+	// `%RESPONSE_CODE%` and `%REQ(header-name)%` command operators, which
+	// are replaced for each request. Only one of Body, WeightedBodies or
+	// BodyConfigMapRef can be set.
+	//
+	// +optional
+	BodyConfigMapRef *DirectResponseConfigMapRef `json:"bodyConfigMapRef,omitempty"`
+}
+
+// DirectResponseConfigMapRef refers to a key of a ConfigMap holding a
+// direct response body.
+type DirectResponseConfigMapRef struct {
+	// Name is the name of the ConfigMap.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Key is the key in the ConfigMap's data holding the body.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+}
+
+// WeightedDirectResponseBody is a direct response body served to a
+// percentage of requests.
+type WeightedDirectResponseBody struct {
+	// Body is the content of the response body.
+	// If this setting is omitted, no body is included in the generated response.
+	//
+	// +optional
+	Body string `json:"body,omitempty"`
+
+	// Weight is the percentage of requests that receive this body.
+	// +required
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Weight uint32 `json:"weight"`
+}
+
+// FaultInjectionPolicy defines the faults to inject into requests.
+// At least one of Delay or Abort must be set.
+type FaultInjectionPolicy struct {
+	// Delay delays a percentage of requests before forwarding them upstream.
+	// +optional
+	Delay *FaultDelay `json:"delay,omitempty"`
+
+	// Abort responds to a percentage of requests with an HTTP status
+	// instead of forwarding them upstream.
+	// +optional
+	Abort *FaultAbort `json:"abort,omitempty"`
+}
+
+// FaultDelay defines a fixed delay injected into requests.
+type FaultDelay struct {
+	// Percentage is the percentage of requests to delay.
+	// +required
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Percentage uint32 `json:"percentage"`
+
+	// Duration is how long requests are delayed for.
+	// Durations are expressed in the Go [Duration format](https://godoc.org/time#ParseDuration).
+	// +required
+	// +kubebuilder:validation:Pattern=`^(((\d*(\.\d*)?h)|(\d*(\.\d*)?m)|(\d*(\.\d*)?s)|(\d*(\.\d*)?ms)|(\d*(\.\d*)?us)|(\d*(\.\d*)?µs)|(\d*(\.\d*)?ns))+)$`
+	Duration string `json:"duration"`
+}
+
+// FaultAbort defines an HTTP status returned instead of forwarding requests.
+type FaultAbort struct {
+	// Percentage is the percentage of requests to abort.
+	// +required
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Percentage uint32 `json:"percentage"`
+
+	// StatusCode is the HTTP status returned for aborted requests.
+	// +required
+	// +kubebuilder:validation:Minimum=200
+	// +kubebuilder:validation:Maximum=599
+	StatusCode int `json:"statusCode"`
+}
+
+// SessionPersistence defines how the session of a request is tracked.
+// Exactly one of Cookie or Header must be set.
+type SessionPersistence struct {
+	// Cookie tracks sessions with a cookie that Envoy sets on the
+	// first response of a session.
+	// +optional
+	Cookie *SessionPersistenceCookie `json:"cookie,omitempty"`
+
+	// Header tracks sessions with a header that Envoy sets on the
+	// first response of a session, and that the client must send
+	// on later requests.
+	// +optional
+	Header *SessionPersistenceHeader `json:"header,omitempty"`
+
+	// Strict makes Envoy return a 503 response if the endpoint of a
+	// session is no longer available, instead of load balancing the
+	// request to another endpoint.
+	// +optional
+	Strict bool `json:"strict,omitempty"`
+}
+
+// SessionPersistenceCookie defines the cookie used to track sessions.
+type SessionPersistenceCookie struct {
+	// Name is the name of the cookie.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Path is the request path the cookie is valid for.
+	// Defaults to `/`.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// TTL is how long the cookie is valid for. If not set, a session
+	// cookie is used.
+	// Durations are expressed in the Go [Duration format](https://godoc.org/time#ParseDuration).
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(((\d*(\.\d*)?h)|(\d*(\.\d*)?m)|(\d*(\.\d*)?s)|(\d*(\.\d*)?ms)|(\d*(\.\d*)?us)|(\d*(\.\d*)?µs)|(\d*(\.\d*)?ns))+)$`
+	TTL string `json:"ttl,omitempty"`
+}
+
+// SessionPersistenceHeader defines the header used to track sessions.
+type SessionPersistenceHeader struct {
+	// Name is the name of the header.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
 }
 
 // HTTPRequestRedirectPolicy defines configuration for redirecting a request.
@@ -888,8 +1221,21 @@ type RateLimitDescriptor struct {
 	// +required
 	// +kubebuilder:validation:MinItems=1
 	Entries []RateLimitDescriptorEntry `json:"entries,omitempty" yaml:"entries,omitempty"`
+
+	// Methods restricts the descriptor to requests using one of the
+	// given HTTP methods. When set, a descriptor entry with a key of
+	// "method" and a value of the comma-separated list of methods is
+	// generated ahead of Entries, and the descriptor is not sent to the
+	// rate limit service for requests using any other method.
+	// +optional
+	// +kubebuilder:validation:MinItems=1
+	Methods []HTTPMethod `json:"methods,omitempty" yaml:"methods,omitempty"`
 }
 
+// HTTPMethod is an HTTP request method.
+// +kubebuilder:validation:Enum=GET;HEAD;POST;PUT;DELETE;CONNECT;OPTIONS;TRACE;PATCH
+type HTTPMethod string
+
 // RateLimitDescriptorEntry is a key-value pair generator. Exactly
 // one field on this struct must be non-nil.
 type RateLimitDescriptorEntry struct {
@@ -913,6 +1259,30 @@ type RateLimitDescriptorEntry struct {
 	// and a value equal to the client's IP address (from x-forwarded-for).
 	// +optional
 	RemoteAddress *RemoteAddressDescriptor `json:"remoteAddress,omitempty" yaml:"remoteAddress,omitempty"`
+
+	// SourceCluster defines a descriptor entry with a key of "source_cluster"
+	// and a value equal to the name of the Envoy cluster (i.e. the
+	// --service-cluster of the Envoy) that received the request.
+	// +optional
+	SourceCluster *SourceClusterDescriptor `json:"sourceCluster,omitempty" yaml:"sourceCluster,omitempty"`
+
+	// DestinationCluster defines a descriptor entry with a key of
+	// "destination_cluster" and a value equal to the name of the
+	// upstream cluster the request is routed to.
+	// +optional
+	DestinationCluster *DestinationClusterDescriptor `json:"destinationCluster,omitempty" yaml:"destinationCluster,omitempty"`
+
+	// Metadata defines a descriptor entry whose value is read from the
+	// request's dynamic metadata, for example the claims written by the
+	// JWT authentication filter.
+	// +optional
+	Metadata *MetadataDescriptor `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	// GRPCMethod defines a descriptor entry whose value is the gRPC
+	// service and method of the request, as carried in the request's
+	// :path pseudo-header (e.g. "/helloworld.Greeter/SayHello").
+	// +optional
+	GRPCMethod *GRPCMethodDescriptor `json:"grpcMethod,omitempty" yaml:"grpcMethod,omitempty"`
 }
 
 // GenericKeyDescriptor defines a descriptor entry with a static key and
@@ -971,10 +1341,59 @@ type RequestHeaderValueMatchDescriptor struct {
 // (from x-forwarded-for).
 type RemoteAddressDescriptor struct{}
 
+// SourceClusterDescriptor defines a descriptor entry with a key of
+// "source_cluster" and a value equal to the name of the local Envoy
+// cluster.
+type SourceClusterDescriptor struct{}
+
+// DestinationClusterDescriptor defines a descriptor entry with a key of
+// "destination_cluster" and a value equal to the name of the upstream
+// cluster the request is routed to.
+type DestinationClusterDescriptor struct{}
+
+// MetadataDescriptor defines a descriptor entry whose value is read from
+// the request's dynamic metadata. If the metadata is not present, the
+// DefaultValue is used; if there is no DefaultValue, the descriptor is
+// not generated.
+type MetadataDescriptor struct {
+	// DescriptorKey defines the key to use on the descriptor entry.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	DescriptorKey string `json:"descriptorKey,omitempty" yaml:"descriptorKey,omitempty"`
+
+	// Namespace is the dynamic metadata namespace to read from, usually
+	// the name of the filter that wrote it, for example
+	// "envoy.filters.http.jwt_authn".
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+
+	// Path is the list of keys used to walk the metadata struct
+	// within the namespace to the value to use.
+	// +required
+	// +kubebuilder:validation:MinItems=1
+	Path []string `json:"path,omitempty" yaml:"path,omitempty"`
+
+	// DefaultValue is the descriptor value to use if the metadata
+	// is not present.
+	// +optional
+	DefaultValue string `json:"defaultValue,omitempty" yaml:"defaultValue,omitempty"`
+}
+
+// GRPCMethodDescriptor defines a descriptor entry whose value is the
+// gRPC service and method of the request. gRPC requests always carry
+// the method in the :path pseudo-header, in the form "/<service>/<method>".
+type GRPCMethodDescriptor struct {
+	// DescriptorKey defines the key to use on the descriptor entry. If not
+	// set, the key is set to "grpc_method".
+	// +optional
+	DescriptorKey string `json:"descriptorKey,omitempty" yaml:"descriptorKey,omitempty"`
+}
+
 // TCPProxy contains the set of services to proxy TCP connections.
 type TCPProxy struct {
 	// The load balancing policy for the backend services. Note that the
-	// `Cookie` and `RequestHash` load balancing strategies cannot be used
+	// `Cookie`, `RequestHash` and `Maglev` load balancing strategies cannot be used
 	// here.
 	// +optional
 	LoadBalancerPolicy *LoadBalancerPolicy `json:"loadBalancerPolicy,omitempty"`
@@ -993,6 +1412,20 @@ type TCPProxy struct {
 	// The health check policy for this tcp proxy
 	// +optional
 	HealthCheckPolicy *TCPHealthCheckPolicy `json:"healthCheckPolicy,omitempty"`
+	// IdleTimeout is how long a connection through this tcp proxy
+	// may have no bytes sent or received before it is closed.
+	// If not supplied, Contour sets a timeout of 9001s (2.5 hours)
+	// instead of Envoy's default of 1h.
+	// A value of "infinity" disables the idle timeout.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(((\d*(\.\d*)?h)|(\d*(\.\d*)?m)|(\d*(\.\d*)?s)|(\d*(\.\d*)?ms)|(\d*(\.\d*)?us)|(\d*(\.\d*)?µs)|(\d*(\.\d*)?ns))+|infinity|infinite)$`
+	IdleTimeout string `json:"idleTimeout,omitempty"`
+	// MaxConnectAttempts is the maximum number of unsuccessful attempts
+	// to connect to an upstream before the downstream connection is closed.
+	// If not supplied, Envoy's default of 1 applies.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxConnectAttempts *uint32 `json:"maxConnectAttempts,omitempty"`
 }
 
 // TCPProxyInclude describes a target HTTPProxy document which contains the TCPProxy details.
@@ -1033,9 +1466,36 @@ type Service struct {
 	// +optional
 	// +kubebuilder:validation:Minimum=0
 	Weight int64 `json:"weight,omitempty"`
+	// FailoverPriority orders the services of a TCPProxy for failover.
+	// Connections are sent to the service with the lowest priority that
+	// has healthy endpoints. Each service of the TCPProxy must have a
+	// different priority, and Weight cannot also be set. It is only
+	// supported for TCPProxy services.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	FailoverPriority int32 `json:"failoverPriority,omitempty"`
 	// UpstreamValidation defines how to verify the backend service's certificate
 	// +optional
 	UpstreamValidation *UpstreamValidation `json:"validation,omitempty"`
+	// ALPNProtocols overrides the list of application protocols negotiated
+	// via ALPN when connecting to the backend service over TLS. It is only
+	// supported when Protocol is `tls` or `h2`; setting it with any other
+	// protocol makes the route invalid. If omitted, `h2` is negotiated for
+	// `h2` upstreams and no ALPN protocols are sent for `tls` upstreams.
+	// +optional
+	// +kubebuilder:validation:MinItems=1
+	ALPNProtocols []string `json:"alpnProtocols,omitempty"`
+	// UpstreamProxyProtocol, if set, makes Envoy send a PROXY protocol
+	// header of the given version when it connects to the backend service.
+	// Values may be v1 or v2. If omitted, no PROXY protocol header is sent.
+	// +kubebuilder:validation:Enum=v1;v2
+	// +optional
+	UpstreamProxyProtocol string `json:"upstreamProxyProtocol,omitempty"`
+	// HTTP2Options configures the HTTP/2 settings Envoy uses for
+	// connections to the backend service. It only applies when Protocol
+	// is `h2` or `h2c`.
+	// +optional
+	HTTP2Options *UpstreamHTTP2Options `json:"http2Options,omitempty"`
 	// If Mirror is true the Service will receive a read only mirror of the traffic for this route.
 	// If Mirror is true, then fractional mirroring can be enabled by optionally setting the Weight
 	// field. Legal values for Weight are 1-100. Omitting the Weight field will result in 100% mirroring.
@@ -1058,6 +1518,32 @@ type Service struct {
 	SlowStartPolicy *SlowStartPolicy `json:"slowStartPolicy,omitempty"`
 }
 
+// UpstreamHTTP2Options defines the HTTP/2 settings for connections
+// to a backend service.
+type UpstreamHTTP2Options struct {
+	// InitialStreamWindowSize is the initial flow-control window
+	// size, in bytes, of each stream. It must be between 65535 and
+	// 2147483647. If omitted, Envoy's default of 268435456 applies.
+	// +optional
+	// +kubebuilder:validation:Minimum=65535
+	// +kubebuilder:validation:Maximum=2147483647
+	InitialStreamWindowSize *uint32 `json:"initialStreamWindowSize,omitempty"`
+	// InitialConnectionWindowSize is the initial flow-control window
+	// size, in bytes, of each connection. It must be between 65535
+	// and 2147483647. If omitted, Envoy's default of 268435456 applies.
+	// +optional
+	// +kubebuilder:validation:Minimum=65535
+	// +kubebuilder:validation:Maximum=2147483647
+	InitialConnectionWindowSize *uint32 `json:"initialConnectionWindowSize,omitempty"`
+	// MaxConcurrentStreams is the maximum number of concurrent streams
+	// Envoy opens on each connection. It must be between 1 and
+	// 2147483647. If omitted, Envoy's default of 2147483647 applies.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=2147483647
+	MaxConcurrentStreams *uint32 `json:"maxConcurrentStreams,omitempty"`
+}
+
 // HTTPHealthCheckPolicy defines health checks on the upstream service.
 type HTTPHealthCheckPolicy struct {
 	// HTTP endpoint used to perform health checks on upstream service
@@ -1289,17 +1775,109 @@ type LoadBalancerPolicy struct {
 	// Strategy specifies the policy used to balance requests
 	// across the pool of backend pods. Valid policy names are
 	// `Random`, `RoundRobin`, `WeightedLeastRequest`, `Cookie`,
-	// and `RequestHash`. If an unknown strategy name is specified
+	// `RequestHash`, and `Maglev`. If an unknown strategy name is specified
 	// or no policy is supplied, the default `RoundRobin` policy
 	// is used.
 	Strategy string `json:"strategy,omitempty"`
 
 	// RequestHashPolicies contains a list of hash policies to apply when the
-	// `RequestHash` load balancing strategy is chosen. If an element of the
+	// `RequestHash` or `Maglev` load balancing strategy is chosen. If an element of the
 	// supplied list of hash policies is invalid, it will be ignored. If the
-	// list of hash policies is empty after validation, the load balancing
-	// strategy will fall back to the default `RoundRobin`.
+	// list of hash policies is empty after validation, the `RequestHash` load balancing
+	// strategy will fall back to the default `RoundRobin`, and the `Maglev` strategy is invalid.
 	RequestHashPolicies []RequestHashPolicy `json:"requestHashPolicies,omitempty"`
+
+	// LeastRequestPolicy contains additional settings for the
+	// `WeightedLeastRequest` load balancing strategy. It is ignored
+	// for other strategies.
+	// +optional
+	LeastRequestPolicy *LeastRequestPolicy `json:"leastRequestPolicy,omitempty"`
+
+	// CookiePolicy contains additional settings for the
+	// `Cookie` load balancing strategy. It is ignored
+	// for other strategies.
+	// +optional
+	CookiePolicy *CookiePolicy `json:"cookiePolicy,omitempty"`
+
+	// RingHashPolicy contains additional settings for the ring hash
+	// load balancer used by the `RequestHash` and `Cookie` load
+	// balancing strategies. It is ignored for other strategies.
+	// +optional
+	RingHashPolicy *RingHashPolicy `json:"ringHashPolicy,omitempty"`
+
+	// MaglevPolicy contains additional settings for the
+	// `Maglev` load balancing strategy. It is ignored
+	// for other strategies.
+	// +optional
+	MaglevPolicy *MaglevPolicy `json:"maglevPolicy,omitempty"`
+}
+
+// RingHashPolicy defines settings for the ring hash load balancer.
+type RingHashPolicy struct {
+	// MinimumRingSize is the minimum number of entries in the hash ring.
+	// Larger rings spread requests more evenly across endpoints, at the
+	// cost of memory. Defaults to 1024.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=8388608
+	MinimumRingSize *uint64 `json:"minimumRingSize,omitempty"`
+
+	// MaximumRingSize is the maximum number of entries in the hash ring.
+	// It must not be smaller than MinimumRingSize, or than 1024 if
+	// MinimumRingSize is not set. Defaults to 8388608.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=8388608
+	MaximumRingSize *uint64 `json:"maximumRingSize,omitempty"`
+}
+
+// MaglevPolicy defines settings for the Maglev load balancer.
+type MaglevPolicy struct {
+	// TableSize is the number of entries in the Maglev lookup table.
+	// It must be a prime number, and should be much larger than the
+	// number of endpoints. Defaults to 65537.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=5000011
+	TableSize *uint64 `json:"tableSize,omitempty"`
+}
+
+// CookiePolicy defines settings for the session affinity cookie
+// that Envoy generates for the Cookie load balancing strategy.
+type CookiePolicy struct {
+	// Name is the name of the generated cookie.
+	// Defaults to `X-Contour-Session-Affinity`.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// TTL is how long the generated cookie is valid for. Once the
+	// cookie expires the client may be balanced to a different endpoint.
+	// If not set, or set to zero, a session cookie is generated.
+	// Durations are expressed in the Go [Duration format](https://godoc.org/time#ParseDuration).
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(((\d*(\.\d*)?h)|(\d*(\.\d*)?m)|(\d*(\.\d*)?s)|(\d*(\.\d*)?ms)|(\d*(\.\d*)?us)|(\d*(\.\d*)?µs)|(\d*(\.\d*)?ns))+)$`
+	TTL string `json:"ttl,omitempty"`
+
+	// Path is the request path the generated cookie is valid for.
+	// Defaults to `/`.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// LeastRequestPolicy defines settings for the WeightedLeastRequest
+// load balancing strategy.
+type LeastRequestPolicy struct {
+	// ActiveRequestBias controls how much the number of active requests
+	// reduces the weight of an endpoint when endpoint weights differ.
+	// Larger values make the load balancer prefer endpoints with fewer
+	// active requests more strongly. A value of 0.0 makes the strategy
+	// behave like weighted round robin. Defaults to 1.0.
+	//
+	// More info: https://www.envoyproxy.io/docs/envoy/latest/api-v3/config/cluster/v3/cluster.proto#config-cluster-v3-cluster-leastrequestlbconfig
+	//
+	// +optional
+	// +kubebuilder:validation:Pattern=`^([0-9]+([.][0-9]+)?|[.][0-9]+)$`
+	ActiveRequestBias string `json:"activeRequestBias,omitempty"`
 }
 
 // HeadersPolicy defines how headers are managed during forwarding.
@@ -1330,15 +1908,18 @@ type HeaderValue struct {
 
 // UpstreamValidation defines how to verify the backend service's certificate
 // +kubebuilder:validation:XValidation:message="subjectNames[0] must equal subjectName if set",rule="has(self.subjectNames) ? self.subjectNames[0] == self.subjectName : true"
+// +kubebuilder:validation:XValidation:message="caSecret, verifyCertificateSpki or verifyCertificateHash must be set",rule="has(self.caSecret) || has(self.verifyCertificateSpki) || has(self.verifyCertificateHash)"
 type UpstreamValidation struct {
 	// Name or namespaced name of the Kubernetes secret used to validate the certificate presented by the backend.
 	// The secret must contain key named ca.crt.
 	// The name can be optionally prefixed with namespace "namespace/name".
 	// When cross-namespace reference is used, TLSCertificateDelegation resource must exist in the namespace to grant access to the secret.
 	// Max length should be the actual max possible length of a namespaced name (63 + 253 + 1 = 317)
+	// May be omitted if VerifyCertificateSpki or VerifyCertificateHash is set.
+	// +optional
 	// +kubebuilder:validation:MinLength=1
 	// +kubebuilder:validation:MaxLength=317
-	CACertificate string `json:"caSecret"`
+	CACertificate string `json:"caSecret,omitempty"`
 	// Key which is expected to be present in the 'subjectAltName' of the presented certificate.
 	// Deprecated: migrate to using the plural field subjectNames.
 	// +kubebuilder:validation:MinLength=1
@@ -1350,6 +1931,18 @@ type UpstreamValidation struct {
 	// +kubebuilder:validation:MinItems=1
 	// +kubebuilder:validation:MaxItems=8
 	SubjectNames []string `json:"subjectNames"`
+	// List of base64 encoded SHA-256 hashes of the Subject Public Key Information (SPKI)
+	// of the certificate presented by the backend. If set, the certificate must match
+	// one of these hashes or one of the VerifyCertificateHash entries.
+	// +optional
+	// +kubebuilder:validation:MaxItems=16
+	VerifyCertificateSpki []string `json:"verifyCertificateSpki,omitempty"`
+	// List of hex encoded SHA-256 hashes of the certificate presented by the backend.
+	// Bytes may optionally be separated by colons. If set, the certificate must match
+	// one of these hashes or one of the VerifyCertificateSpki entries.
+	// +optional
+	// +kubebuilder:validation:MaxItems=16
+	VerifyCertificateHash []string `json:"verifyCertificateHash,omitempty"`
 }
 
 // DownstreamValidation defines how to verify the client certificate.
@@ -1452,6 +2045,25 @@ type HTTPProxyStatus struct {
 	// +listType=map
 	// +listMapKey=type
 	Conditions []DetailedCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+	// +optional
+	// ValidationErrors lists the objects referenced by the HTTPProxy
+	// that could not be resolved, for example a missing Service or Secret.
+	ValidationErrors []ValidationError `json:"validationErrors,omitempty"`
+}
+
+// ValidationError identifies an object referenced by an HTTPProxy
+// that could not be resolved.
+type ValidationError struct {
+	// Kind of the referenced object, for example `Service` or `Secret`.
+	Kind string `json:"kind"`
+	// Namespace of the referenced object.
+	Namespace string `json:"namespace"`
+	// Name of the referenced object.
+	Name string `json:"name"`
+	// Message is a human readable description of why the reference
+	// could not be resolved.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +genclient
@@ -46,6 +46,51 @@ func (in *AuthorizationPolicy) DeepCopy() *AuthorizationPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthorizationRequestHeaders) DeepCopyInto(out *AuthorizationRequestHeaders) {
+	*out = *in
+	if in.AllowedHeaders != nil {
+		in, out := &in.AllowedHeaders, &out.AllowedHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DisallowedHeaders != nil {
+		in, out := &in.DisallowedHeaders, &out.DisallowedHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorizationRequestHeaders.
+func (in *AuthorizationRequestHeaders) DeepCopy() *AuthorizationRequestHeaders {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthorizationRequestHeaders)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthorizationResponseHeaders) DeepCopyInto(out *AuthorizationResponseHeaders) {
+	*out = *in
+	if in.DisallowedHeaders != nil {
+		in, out := &in.DisallowedHeaders, &out.DisallowedHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorizationResponseHeaders.
+func (in *AuthorizationResponseHeaders) DeepCopy() *AuthorizationResponseHeaders {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthorizationResponseHeaders)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuthorizationServer) DeepCopyInto(out *AuthorizationServer) {
 	*out = *in
@@ -60,6 +105,16 @@ func (in *AuthorizationServer) DeepCopyInto(out *AuthorizationServer) {
 		*out = new(AuthorizationServerBufferSettings)
 		**out = **in
 	}
+	if in.AuthorizationRequest != nil {
+		in, out := &in.AuthorizationRequest, &out.AuthorizationRequest
+		*out = new(AuthorizationRequestHeaders)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuthorizationResponse != nil {
+		in, out := &in.AuthorizationResponse, &out.AuthorizationResponse
+		*out = new(AuthorizationResponseHeaders)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorizationServer.
@@ -187,6 +242,21 @@ func (in *CookiePathRewrite) DeepCopy() *CookiePathRewrite {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CookiePolicy) DeepCopyInto(out *CookiePolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CookiePolicy.
+func (in *CookiePolicy) DeepCopy() *CookiePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CookiePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CookieRewritePolicy) DeepCopyInto(out *CookieRewritePolicy) {
 	*out = *in
@@ -222,6 +292,21 @@ func (in *CookieRewritePolicy) DeepCopy() *CookieRewritePolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DestinationClusterDescriptor) DeepCopyInto(out *DestinationClusterDescriptor) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DestinationClusterDescriptor.
+func (in *DestinationClusterDescriptor) DeepCopy() *DestinationClusterDescriptor {
+	if in == nil {
+		return nil
+	}
+	out := new(DestinationClusterDescriptor)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DetailedCondition) DeepCopyInto(out *DetailedCondition) {
 	*out = *in
@@ -229,12 +314,16 @@ func (in *DetailedCondition) DeepCopyInto(out *DetailedCondition) {
 	if in.Errors != nil {
 		in, out := &in.Errors, &out.Errors
 		*out = make([]SubCondition, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Warnings != nil {
 		in, out := &in.Warnings, &out.Warnings
 		*out = make([]SubCondition, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -248,6 +337,21 @@ func (in *DetailedCondition) DeepCopy() *DetailedCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DirectResponseConfigMapRef) DeepCopyInto(out *DirectResponseConfigMapRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DirectResponseConfigMapRef.
+func (in *DirectResponseConfigMapRef) DeepCopy() *DirectResponseConfigMapRef {
+	if in == nil {
+		return nil
+	}
+	out := new(DirectResponseConfigMapRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DownstreamValidation) DeepCopyInto(out *DownstreamValidation) {
 	*out = *in
@@ -283,6 +387,76 @@ func (in *ExtensionServiceReference) DeepCopy() *ExtensionServiceReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FaultAbort) DeepCopyInto(out *FaultAbort) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FaultAbort.
+func (in *FaultAbort) DeepCopy() *FaultAbort {
+	if in == nil {
+		return nil
+	}
+	out := new(FaultAbort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FaultDelay) DeepCopyInto(out *FaultDelay) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FaultDelay.
+func (in *FaultDelay) DeepCopy() *FaultDelay {
+	if in == nil {
+		return nil
+	}
+	out := new(FaultDelay)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FaultInjectionPolicy) DeepCopyInto(out *FaultInjectionPolicy) {
+	*out = *in
+	if in.Delay != nil {
+		in, out := &in.Delay, &out.Delay
+		*out = new(FaultDelay)
+		**out = **in
+	}
+	if in.Abort != nil {
+		in, out := &in.Abort, &out.Abort
+		*out = new(FaultAbort)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FaultInjectionPolicy.
+func (in *FaultInjectionPolicy) DeepCopy() *FaultInjectionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(FaultInjectionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GRPCMethodDescriptor) DeepCopyInto(out *GRPCMethodDescriptor) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GRPCMethodDescriptor.
+func (in *GRPCMethodDescriptor) DeepCopy() *GRPCMethodDescriptor {
+	if in == nil {
+		return nil
+	}
+	out := new(GRPCMethodDescriptor)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GenericKeyDescriptor) DeepCopyInto(out *GenericKeyDescriptor) {
 	*out = *in
@@ -320,9 +494,34 @@ func (in *GlobalRateLimitPolicy) DeepCopy() *GlobalRateLimitPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HSTSPolicy) DeepCopyInto(out *HSTSPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HSTSPolicy.
+func (in *HSTSPolicy) DeepCopy() *HSTSPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(HSTSPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPDirectResponsePolicy) DeepCopyInto(out *HTTPDirectResponsePolicy) {
 	*out = *in
+	if in.WeightedBodies != nil {
+		in, out := &in.WeightedBodies, &out.WeightedBodies
+		*out = make([]WeightedDirectResponseBody, len(*in))
+		copy(*out, *in)
+	}
+	if in.BodyConfigMapRef != nil {
+		in, out := &in.BodyConfigMapRef, &out.BodyConfigMapRef
+		*out = new(DirectResponseConfigMapRef)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPDirectResponsePolicy.
@@ -484,6 +683,11 @@ func (in *HTTPProxyStatus) DeepCopyInto(out *HTTPProxyStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ValidationErrors != nil {
+		in, out := &in.ValidationErrors, &out.ValidationErrors
+		*out = make([]ValidationError, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPProxyStatus.
@@ -651,6 +855,11 @@ func (in *Include) DeepCopyInto(out *Include) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RequestHeadersPolicy != nil {
+		in, out := &in.RequestHeadersPolicy, &out.RequestHeadersPolicy
+		*out = new(HeadersPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Include.
@@ -699,6 +908,21 @@ func (in *JWTVerificationPolicy) DeepCopy() *JWTVerificationPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LeastRequestPolicy) DeepCopyInto(out *LeastRequestPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeastRequestPolicy.
+func (in *LeastRequestPolicy) DeepCopy() *LeastRequestPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(LeastRequestPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoadBalancerPolicy) DeepCopyInto(out *LoadBalancerPolicy) {
 	*out = *in
@@ -709,6 +933,26 @@ func (in *LoadBalancerPolicy) DeepCopyInto(out *LoadBalancerPolicy) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LeastRequestPolicy != nil {
+		in, out := &in.LeastRequestPolicy, &out.LeastRequestPolicy
+		*out = new(LeastRequestPolicy)
+		**out = **in
+	}
+	if in.CookiePolicy != nil {
+		in, out := &in.CookiePolicy, &out.CookiePolicy
+		*out = new(CookiePolicy)
+		**out = **in
+	}
+	if in.RingHashPolicy != nil {
+		in, out := &in.RingHashPolicy, &out.RingHashPolicy
+		*out = new(RingHashPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaglevPolicy != nil {
+		in, out := &in.MaglevPolicy, &out.MaglevPolicy
+		*out = new(MaglevPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerPolicy.
@@ -741,6 +985,26 @@ func (in *LocalRateLimitPolicy) DeepCopy() *LocalRateLimitPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaglevPolicy) DeepCopyInto(out *MaglevPolicy) {
+	*out = *in
+	if in.TableSize != nil {
+		in, out := &in.TableSize, &out.TableSize
+		*out = new(uint64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaglevPolicy.
+func (in *MaglevPolicy) DeepCopy() *MaglevPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(MaglevPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MatchCondition) DeepCopyInto(out *MatchCondition) {
 	*out = *in
@@ -766,6 +1030,61 @@ func (in *MatchCondition) DeepCopy() *MatchCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataDescriptor) DeepCopyInto(out *MetadataDescriptor) {
+	*out = *in
+	if in.Path != nil {
+		in, out := &in.Path, &out.Path
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataDescriptor.
+func (in *MetadataDescriptor) DeepCopy() *MetadataDescriptor {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataDescriptor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotFoundResponse) DeepCopyInto(out *NotFoundResponse) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotFoundResponse.
+func (in *NotFoundResponse) DeepCopy() *NotFoundResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(NotFoundResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PathNormalizationPolicy) DeepCopyInto(out *PathNormalizationPolicy) {
+	*out = *in
+	if in.MergeSlashes != nil {
+		in, out := &in.MergeSlashes, &out.MergeSlashes
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PathNormalizationPolicy.
+func (in *PathNormalizationPolicy) DeepCopy() *PathNormalizationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PathNormalizationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PathRewritePolicy) DeepCopyInto(out *PathRewritePolicy) {
 	*out = *in
@@ -826,6 +1145,11 @@ func (in *RateLimitDescriptor) DeepCopyInto(out *RateLimitDescriptor) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Methods != nil {
+		in, out := &in.Methods, &out.Methods
+		*out = make([]HTTPMethod, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitDescriptor.
@@ -861,6 +1185,26 @@ func (in *RateLimitDescriptorEntry) DeepCopyInto(out *RateLimitDescriptorEntry)
 		*out = new(RemoteAddressDescriptor)
 		**out = **in
 	}
+	if in.SourceCluster != nil {
+		in, out := &in.SourceCluster, &out.SourceCluster
+		*out = new(SourceClusterDescriptor)
+		**out = **in
+	}
+	if in.DestinationCluster != nil {
+		in, out := &in.DestinationCluster, &out.DestinationCluster
+		*out = new(DestinationClusterDescriptor)
+		**out = **in
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(MetadataDescriptor)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GRPCMethod != nil {
+		in, out := &in.GRPCMethod, &out.GRPCMethod
+		*out = new(GRPCMethodDescriptor)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitDescriptorEntry.
@@ -1033,6 +1377,31 @@ func (in *RetryPolicy) DeepCopy() *RetryPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RingHashPolicy) DeepCopyInto(out *RingHashPolicy) {
+	*out = *in
+	if in.MinimumRingSize != nil {
+		in, out := &in.MinimumRingSize, &out.MinimumRingSize
+		*out = new(uint64)
+		**out = **in
+	}
+	if in.MaximumRingSize != nil {
+		in, out := &in.MaximumRingSize, &out.MaximumRingSize
+		*out = new(uint64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RingHashPolicy.
+func (in *RingHashPolicy) DeepCopy() *RingHashPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RingHashPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Route) DeepCopyInto(out *Route) {
 	*out = *in
@@ -1110,7 +1479,7 @@ func (in *Route) DeepCopyInto(out *Route) {
 	if in.DirectResponsePolicy != nil {
 		in, out := &in.DirectResponsePolicy, &out.DirectResponsePolicy
 		*out = new(HTTPDirectResponsePolicy)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.InternalRedirectPolicy != nil {
 		in, out := &in.InternalRedirectPolicy, &out.InternalRedirectPolicy
@@ -1132,6 +1501,21 @@ func (in *Route) DeepCopyInto(out *Route) {
 		*out = make([]IPFilterPolicy, len(*in))
 		copy(*out, *in)
 	}
+	if in.CORSPolicy != nil {
+		in, out := &in.CORSPolicy, &out.CORSPolicy
+		*out = new(CORSPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FaultInjectionPolicy != nil {
+		in, out := &in.FaultInjectionPolicy, &out.FaultInjectionPolicy
+		*out = new(FaultInjectionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SessionPersistence != nil {
+		in, out := &in.SessionPersistence, &out.SessionPersistence
+		*out = new(SessionPersistence)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Route.
@@ -1157,6 +1541,16 @@ func (in *Service) DeepCopyInto(out *Service) {
 		*out = new(UpstreamValidation)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ALPNProtocols != nil {
+		in, out := &in.ALPNProtocols, &out.ALPNProtocols
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HTTP2Options != nil {
+		in, out := &in.HTTP2Options, &out.HTTP2Options
+		*out = new(UpstreamHTTP2Options)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.RequestHeadersPolicy != nil {
 		in, out := &in.RequestHeadersPolicy, &out.RequestHeadersPolicy
 		*out = new(HeadersPolicy)
@@ -1191,6 +1585,61 @@ func (in *Service) DeepCopy() *Service {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionPersistence) DeepCopyInto(out *SessionPersistence) {
+	*out = *in
+	if in.Cookie != nil {
+		in, out := &in.Cookie, &out.Cookie
+		*out = new(SessionPersistenceCookie)
+		**out = **in
+	}
+	if in.Header != nil {
+		in, out := &in.Header, &out.Header
+		*out = new(SessionPersistenceHeader)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionPersistence.
+func (in *SessionPersistence) DeepCopy() *SessionPersistence {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionPersistence)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionPersistenceCookie) DeepCopyInto(out *SessionPersistenceCookie) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionPersistenceCookie.
+func (in *SessionPersistenceCookie) DeepCopy() *SessionPersistenceCookie {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionPersistenceCookie)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionPersistenceHeader) DeepCopyInto(out *SessionPersistenceHeader) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionPersistenceHeader.
+func (in *SessionPersistenceHeader) DeepCopy() *SessionPersistenceHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionPersistenceHeader)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SlowStartPolicy) DeepCopyInto(out *SlowStartPolicy) {
 	*out = *in
@@ -1206,6 +1655,21 @@ func (in *SlowStartPolicy) DeepCopy() *SlowStartPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceClusterDescriptor) DeepCopyInto(out *SourceClusterDescriptor) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceClusterDescriptor.
+func (in *SourceClusterDescriptor) DeepCopy() *SourceClusterDescriptor {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceClusterDescriptor)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubCondition) DeepCopyInto(out *SubCondition) {
 	*out = *in
@@ -1266,6 +1730,11 @@ func (in *TCPProxy) DeepCopyInto(out *TCPProxy) {
 		*out = new(TCPHealthCheckPolicy)
 		**out = **in
 	}
+	if in.MaxConnectAttempts != nil {
+		in, out := &in.MaxConnectAttempts, &out.MaxConnectAttempts
+		*out = new(uint32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPProxy.
@@ -1301,6 +1770,16 @@ func (in *TLS) DeepCopyInto(out *TLS) {
 		*out = new(DownstreamValidation)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HSTS != nil {
+		in, out := &in.HSTS, &out.HSTS
+		*out = new(HSTSPolicy)
+		**out = **in
+	}
+	if in.CipherSuites != nil {
+		in, out := &in.CipherSuites, &out.CipherSuites
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLS.
@@ -1431,6 +1910,36 @@ func (in *TimeoutPolicy) DeepCopy() *TimeoutPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpstreamHTTP2Options) DeepCopyInto(out *UpstreamHTTP2Options) {
+	*out = *in
+	if in.InitialStreamWindowSize != nil {
+		in, out := &in.InitialStreamWindowSize, &out.InitialStreamWindowSize
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.InitialConnectionWindowSize != nil {
+		in, out := &in.InitialConnectionWindowSize, &out.InitialConnectionWindowSize
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.MaxConcurrentStreams != nil {
+		in, out := &in.MaxConcurrentStreams, &out.MaxConcurrentStreams
+		*out = new(uint32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpstreamHTTP2Options.
+func (in *UpstreamHTTP2Options) DeepCopy() *UpstreamHTTP2Options {
+	if in == nil {
+		return nil
+	}
+	out := new(UpstreamHTTP2Options)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UpstreamValidation) DeepCopyInto(out *UpstreamValidation) {
 	*out = *in
@@ -1439,6 +1948,16 @@ func (in *UpstreamValidation) DeepCopyInto(out *UpstreamValidation) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.VerifyCertificateSpki != nil {
+		in, out := &in.VerifyCertificateSpki, &out.VerifyCertificateSpki
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.VerifyCertificateHash != nil {
+		in, out := &in.VerifyCertificateHash, &out.VerifyCertificateHash
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpstreamValidation.
@@ -1451,6 +1970,21 @@ func (in *UpstreamValidation) DeepCopy() *UpstreamValidation {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidationError) DeepCopyInto(out *ValidationError) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidationError.
+func (in *ValidationError) DeepCopy() *ValidationError {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidationError)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualHost) DeepCopyInto(out *VirtualHost) {
 	*out = *in
@@ -1491,6 +2025,16 @@ func (in *VirtualHost) DeepCopyInto(out *VirtualHost) {
 		*out = make([]IPFilterPolicy, len(*in))
 		copy(*out, *in)
 	}
+	if in.PathNormalizationPolicy != nil {
+		in, out := &in.PathNormalizationPolicy, &out.PathNormalizationPolicy
+		*out = new(PathNormalizationPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NotFoundResponse != nil {
+		in, out := &in.NotFoundResponse, &out.NotFoundResponse
+		*out = new(NotFoundResponse)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualHost.
@@ -1502,3 +2046,18 @@ func (in *VirtualHost) DeepCopy() *VirtualHost {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WeightedDirectResponseBody) DeepCopyInto(out *WeightedDirectResponseBody) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WeightedDirectResponseBody.
+func (in *WeightedDirectResponseBody) DeepCopy() *WeightedDirectResponseBody {
+	if in == nil {
+		return nil
+	}
+	out := new(WeightedDirectResponseBody)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -36,6 +36,11 @@ type ContourConfigurationSpec struct {
 	// +optional
 	Debug *DebugConfig `json:"debug,omitempty"`
 
+	// LeaderElection contains the parameters of the leader election
+	// Contour uses to decide which replica writes status.
+	// +optional
+	LeaderElection *LeaderElectionConfig `json:"leaderElection,omitempty"`
+
 	// Health defines the endpoints Contour uses to serve health checks.
 	//
 	// Contour's default is { address: "0.0.0.0", port: 8000 }.
@@ -140,6 +145,8 @@ type XDSServerConfig struct {
 	Type XDSServerType `json:"type,omitempty"`
 
 	// Defines the xDS gRPC API address which Contour will serve.
+	// An address of the form "unix:///path/to/socket" serves xDS on
+	// a Unix domain socket, in which case Port is ignored.
 	//
 	// Contour's default is "0.0.0.0".
 	// +kubebuilder:validation:MinLength=1
@@ -157,6 +164,125 @@ type XDSServerConfig struct {
 	// Contour's default is { caFile: "/certs/ca.crt", certFile: "/certs/tls.cert", keyFile: "/certs/tls.key", insecure: false }.
 	// +optional
 	TLS *TLS `json:"tls,omitempty"`
+
+	// Delta makes the resources served by the xDS server refer to the
+	// route and endpoint resources they depend on with the incremental
+	// (delta) xDS protocol, so Envoy only receives the resources that
+	// changed. Requires the `envoy` xDS server type.
+	//
+	// Contour's default is false.
+	// +optional
+	Delta *bool `json:"delta,omitempty"`
+
+	// ResourceTTL is how long Envoy keeps the runtime settings it
+	// received from the xDS server once the server stops refreshing
+	// them. When set, the server sends heartbeats at half the TTL, and
+	// Envoy reverts to its runtime defaults if they were not refreshed
+	// in time. Listeners, clusters, routes, endpoints and secrets never
+	// expire, since Envoy would remove them and stop serving their
+	// traffic. TTLs only apply to state-of-the-world xDS streams.
+	// Requires the `envoy` xDS server type.
+	//
+	// Contour's default is unset, so resources never expire.
+	// +optional
+	ResourceTTL *string `json:"resourceTTL,omitempty"`
+
+	// ShutdownDrainTime is how long Contour keeps serving xDS after it
+	// receives SIGTERM. During this time its /readyz endpoint fails, so
+	// it is removed from Service endpoints while Envoys that are still
+	// connected keep receiving configuration. Envoy's own listeners are
+	// not drained; that is done by the Envoy shutdown manager. Must be
+	// a Go duration string between 0s and 10m.
+	//
+	// Contour's default is 0s, so it stops serving xDS immediately.
+	// +optional
+	ShutdownDrainTime *string `json:"shutdownDrainTime,omitempty"`
+
+	// Keepalive holds the gRPC keepalive parameters of the xDS server,
+	// which let Contour and Envoy detect dead xDS connections.
+	// +optional
+	Keepalive *XDSServerKeepalive `json:"keepalive,omitempty"`
+}
+
+// XDSServerKeepalive holds the gRPC keepalive parameters of the xDS server.
+type XDSServerKeepalive struct {
+	// Time is how long the server waits without seeing any activity
+	// on a connection before pinging the client.
+	//
+	// Contour's default is 60s.
+	// +optional
+	Time *string `json:"time,omitempty"`
+
+	// Timeout is how long the server waits for a ping to be
+	// acknowledged before closing the connection.
+	//
+	// Contour's default is 20s.
+	// +optional
+	Timeout *string `json:"timeout,omitempty"`
+
+	// MinTime is the minimum time a client must wait between pings.
+	// Clients that ping more often have their connections closed.
+	//
+	// Contour's default is 5m.
+	// +optional
+	MinTime *string `json:"minTime,omitempty"`
+
+	// PermitWithoutStream allows clients to send pings when there
+	// are no active streams on the connection.
+	//
+	// Contour's default is true.
+	// +optional
+	PermitWithoutStream *bool `json:"permitWithoutStream,omitempty"`
+}
+
+// LeaderElectionResourceLock is the type of resource used
+// as the leader election lock.
+// +kubebuilder:validation:Enum=leases
+type LeaderElectionResourceLock string
+
+const (
+	// LeasesResourceLock uses a coordination.k8s.io Lease as the lock.
+	LeasesResourceLock LeaderElectionResourceLock = "leases"
+)
+
+// LeaderElectionConfig holds the leader election parameters. The
+// durations must satisfy leaseDuration > renewDeadline > retryPeriod.
+type LeaderElectionConfig struct {
+	// Disable disables leader election, so that Contour writes status
+	// and leader-only metrics as soon as it starts. Only disable it
+	// when running a single Contour replica.
+	//
+	// Contour's default is false.
+	// +optional
+	Disable *bool `json:"disable,omitempty"`
+
+	// LeaseDuration is how long non-leader replicas wait before
+	// trying to acquire a lease that has not been renewed.
+	//
+	// Contour's default is 15s.
+	// +optional
+	LeaseDuration *string `json:"leaseDuration,omitempty"`
+
+	// RenewDeadline is how long the leader keeps retrying to renew
+	// its lease before giving up leadership.
+	//
+	// Contour's default is 10s.
+	// +optional
+	RenewDeadline *string `json:"renewDeadline,omitempty"`
+
+	// RetryPeriod is how long replicas wait between attempts to
+	// acquire or renew the lease.
+	//
+	// Contour's default is 2s.
+	// +optional
+	RetryPeriod *string `json:"retryPeriod,omitempty"`
+
+	// ResourceLock is the type of resource used as the lock.
+	// Only `leases` is supported.
+	//
+	// Contour's default is leases.
+	// +optional
+	ResourceLock LeaderElectionResourceLock `json:"resourceLock,omitempty"`
 }
 
 // GatewayConfig holds the config for Gateway API controllers.
@@ -323,6 +449,28 @@ type EnvoyConfig struct {
 	// Network holds various configurable Envoy network values.
 	// +optional
 	Network *NetworkParameters `json:"network,omitempty"`
+
+	// StatsCollector configures Contour to poll an Envoy stats endpoint
+	// and re-export the number of active downstream connections of each
+	// Envoy listener as a Contour metric.
+	// +optional
+	StatsCollector *EnvoyStatsCollectorConfig `json:"statsCollector,omitempty"`
+}
+
+// EnvoyStatsCollectorConfig defines how Contour polls Envoy for stats.
+type EnvoyStatsCollectorConfig struct {
+	// URL is the address of an Envoy admin or stats `/stats` endpoint,
+	// for example "http://envoy.projectcontour:8002/stats".
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// PollInterval defines how often Contour polls the URL.
+	// Must be a valid Go duration string, or empty or "0s" to
+	// disable polling.
+	//
+	// Contour's default is to not poll Envoy.
+	// +optional
+	PollInterval string `json:"pollInterval,omitempty"`
 }
 
 // DebugConfig contains Contour specific troubleshooting options.
@@ -340,6 +488,56 @@ type DebugConfig struct {
 	Port int `json:"port,omitempty"`
 }
 
+// ProxyProtocolVersion is a version of the PROXY protocol.
+// +kubebuilder:validation:Enum=v1;v2
+type ProxyProtocolVersion string
+
+const (
+	ProxyProtocolVersion1 ProxyProtocolVersion = "v1"
+	ProxyProtocolVersion2 ProxyProtocolVersion = "v2"
+)
+
+// ProxyProtocolConfig holds the settings of the PROXY protocol listener filter.
+type ProxyProtocolConfig struct {
+	// AllowRequestsWithoutProxyProtocol accepts connections that do not
+	// start with a PROXY protocol header, for example health checks sent
+	// directly to Envoy rather than through the load balancer.
+	//
+	// Contour's default is false.
+	// +optional
+	AllowRequestsWithoutProxyProtocol *bool `json:"allowRequestsWithoutProxyProtocol,omitempty"`
+
+	// Versions lists the PROXY protocol versions to accept.
+	// Connections using any other version are rejected.
+	// Values: `v1`, `v2`.
+	//
+	// Contour's default is to accept both versions.
+	// +optional
+	Versions []ProxyProtocolVersion `json:"versions,omitempty"`
+}
+
+// HTTP3Config holds the settings for serving HTTP/3.
+type HTTP3Config struct {
+	// Enabled adds a UDP QUIC listener alongside each HTTPS listener and
+	// advertises it to clients with an alt-svc response header. HTTP/3
+	// requires TLS 1.3, so only virtual hosts that terminate TLS and
+	// allow TLS 1.3 are served over HTTP/3.
+	//
+	// Contour's default is false.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// AdvertisedPort is the UDP port advertised to clients in the alt-svc
+	// response header. This should be the port on which the Envoy Service
+	// exposes the QUIC listener.
+	//
+	// Contour's default is 443.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	AdvertisedPort *int32 `json:"advertisedPort,omitempty"`
+}
+
 // EnvoyListenerConfig hold various configurable Envoy listener values.
 type EnvoyListenerConfig struct {
 	// Use PROXY protocol for all listeners.
@@ -348,6 +546,37 @@ type EnvoyListenerConfig struct {
 	// +optional
 	UseProxyProto *bool `json:"useProxyProtocol,omitempty"`
 
+	// ProxyProtocol configures how the PROXY protocol listener filter
+	// handles connections when UseProxyProto is enabled. It is rejected
+	// when UseProxyProto is not enabled.
+	// +optional
+	ProxyProtocol *ProxyProtocolConfig `json:"proxyProtocol,omitempty"`
+
+	// HTTP3 configures Envoy to also serve TLS virtual hosts over
+	// HTTP/3 (QUIC).
+	// +optional
+	HTTP3 *HTTP3Config `json:"http3,omitempty"`
+
+	// ListenerFiltersTimeout is the maximum time the listener filters,
+	// such as the TLS inspector and the PROXY protocol filter, may take
+	// to inspect a new connection. Slow clients that do not send enough
+	// data within the timeout have their connection closed, unless
+	// ContinueOnListenerFiltersTimeout is set. Must be a valid Go
+	// duration string, or "infinity" to disable the timeout.
+	//
+	// Contour's default is Envoy's default of 15s.
+	// +optional
+	ListenerFiltersTimeout *string `json:"listenerFiltersTimeout,omitempty"`
+
+	// ContinueOnListenerFiltersTimeout passes connections whose listener
+	// filters time out on to a filter chain, instead of closing them.
+	// Such connections are matched as if the timed out filters had
+	// found nothing, e.g. without an SNI.
+	//
+	// Contour's default is false.
+	// +optional
+	ContinueOnListenerFiltersTimeout *bool `json:"continueOnListenerFiltersTimeout,omitempty"`
+
 	// DisableAllowChunkedLength disables the RFC-compliant Envoy behavior to
 	// strip the "Content-Length" header if "Transfer-Encoding: chunked" is
 	// also set. This is an emergency off-switch to revert back to Envoy's
@@ -366,6 +595,27 @@ type EnvoyListenerConfig struct {
 	// +optional
 	DisableMergeSlashes *bool `json:"disableMergeSlashes,omitempty"`
 
+	// StripPortFromHost removes any port from the Host/:authority header
+	// before virtual host matching, so a request for "example.com:443" is
+	// routed to the "example.com" virtual host.
+	// Cannot be combined with StripMatchingHostPort.
+	//
+	// Contour's default is false.
+	// +optional
+	StripPortFromHost *bool `json:"stripPortFromHost,omitempty"`
+
+	// StripMatchingHostPort removes the port from the Host/:authority header
+	// before virtual host matching only when it matches the port of the
+	// listener the request was received on. Envoy compares it with the port
+	// its listener binds to (8080 or 8443 by default), not the Service port
+	// clients connect to, so "example.com:443" is not stripped unless the
+	// listener itself binds to 443.
+	// Cannot be combined with StripPortFromHost.
+	//
+	// Contour's default is false.
+	// +optional
+	StripMatchingHostPort *bool `json:"stripMatchingHostPort,omitempty"`
+
 	// Defines the action to be applied to the Server header on the response path.
 	// When configured as overwrite, overwrites any Server header with "envoy".
 	// When configured as append_if_absent, if a Server header is present, pass it through, otherwise set it to "envoy".
@@ -378,6 +628,57 @@ type EnvoyListenerConfig struct {
 	// +optional
 	ServerHeaderTransformation ServerHeaderTransformationType `json:"serverHeaderTransformation,omitempty"`
 
+	// ServerName is the value Envoy uses for the Server response header
+	// when ServerHeaderTransformation overwrites or adds it. It must be a
+	// valid HTTP header value.
+	//
+	// Contour's default is "envoy".
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+
+	// UseRemoteAddress makes Envoy use the address of the downstream
+	// connection, rather than the x-forwarded-for header, as the client
+	// address, and append it to x-forwarded-for. Disable it when Envoy runs
+	// behind another proxy that sets x-forwarded-for; the client address is
+	// then taken from x-forwarded-for, skipping numTrustedHops addresses
+	// from the right.
+	//
+	// Contour's default is true.
+	// +optional
+	UseRemoteAddress *bool `json:"useRemoteAddress,omitempty"`
+
+	// Via is the value Envoy appends to the via header of requests and
+	// responses. It must be a valid HTTP header value. If unset, no via
+	// header is added.
+	// +optional
+	Via string `json:"via,omitempty"`
+
+	// AllowAbsoluteURL makes Envoy accept HTTP/1 requests whose target
+	// is an absolute URL, e.g. "GET http://example.com/ HTTP/1.1", and
+	// route them using the URL's host. If unset, Envoy's default is used.
+	// +optional
+	AllowAbsoluteURL *bool `json:"allowAbsoluteURL,omitempty"`
+
+	// DisableAcceptHTTP10 makes Envoy reject HTTP/1.0 requests with a
+	// 426 Upgrade Required response.
+	//
+	// Contour's default is false.
+	// +optional
+	DisableAcceptHTTP10 *bool `json:"disableAcceptHTTP10,omitempty"`
+
+	// HTTP10DefaultHost is the host used to route HTTP/1.0 requests that
+	// do not have a Host header. It may include a port, e.g.
+	// "example.com:8080". It cannot be set if DisableAcceptHTTP10 is true.
+	// If unset, HTTP/1.0 requests without a Host header are rejected.
+	// +optional
+	HTTP10DefaultHost string `json:"http10DefaultHost,omitempty"`
+
+	// LocalReplyPolicy customizes the bodies of responses generated by
+	// Envoy itself, such as a 503 when no upstream is healthy. It applies
+	// to every HTTP listener.
+	// +optional
+	LocalReplyPolicy *LocalReplyPolicy `json:"localReplyPolicy,omitempty"`
+
 	// ConnectionBalancer. If the value is exact, the listener will use the exact connection balancer
 	// See https://www.envoyproxy.io/docs/envoy/latest/api-v2/api/v2/listener.proto#envoy-api-msg-listener-connectionbalanceconfig
 	// for more information.
@@ -432,9 +733,25 @@ type EnvoyListenerConfig struct {
 	// unlimited.
 	//
 	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=2147483647
 	// +optional
 	HTTP2MaxConcurrentStreams *uint32 `json:"httpMaxConcurrentStreams,omitempty"`
 
+	// HTTP2KeepaliveInterval is how often Envoy sends an HTTP/2 PING
+	// frame on each idle downstream HTTP/2 connection. Connections whose
+	// peer does not respond within HTTP2KeepaliveTimeout are closed.
+	// HTTP2KeepaliveInterval and HTTP2KeepaliveTimeout must be set together,
+	// and each must be at least 1ms.
+	//
+	// Contour's default is to not send keepalive PINGs.
+	// +optional
+	HTTP2KeepaliveInterval *string `json:"http2KeepaliveInterval,omitempty"`
+
+	// HTTP2KeepaliveTimeout is how long Envoy waits for a response to an
+	// HTTP/2 keepalive PING before closing the connection.
+	// +optional
+	HTTP2KeepaliveTimeout *string `json:"http2KeepaliveTimeout,omitempty"`
+
 	// Defines the limit on number of active connections to a listener. The limit is applied
 	// per listener. The default value when this is not set is unlimited.
 	//
@@ -443,6 +760,52 @@ type EnvoyListenerConfig struct {
 	MaxConnectionsPerListener *uint32 `json:"maxConnectionsPerListener,omitempty"`
 }
 
+// LocalReplyPolicy defines how the bodies of responses generated by Envoy
+// are formatted. Direct responses configured on routes are never changed.
+type LocalReplyPolicy struct {
+	// Format is the body format of local replies that match none of
+	// the Overrides. If unset, Envoy's default body is used.
+	// +optional
+	Format *LocalReplyFormat `json:"format,omitempty"`
+
+	// Overrides set the body format of local replies with a given
+	// status code.
+	// +optional
+	Overrides []LocalReplyOverride `json:"overrides,omitempty"`
+}
+
+// LocalReplyOverride sets the body format of local replies with
+// the given status code.
+type LocalReplyOverride struct {
+	// StatusCode is the status code of the local replies to format.
+	// +kubebuilder:validation:Minimum=100
+	// +kubebuilder:validation:Maximum=599
+	StatusCode int `json:"statusCode"`
+
+	// Format is the body format to use.
+	Format LocalReplyFormat `json:"format"`
+}
+
+// LocalReplyFormat defines the body of a local reply. Exactly one of
+// Text or JSON must be set. Both may contain Envoy command operators,
+// e.g. `%RESPONSE_CODE%` or `%LOCAL_REPLY_BODY%`.
+type LocalReplyFormat struct {
+	// Text is a plain text body format.
+	// +optional
+	Text string `json:"text,omitempty"`
+
+	// JSON is a JSON object body format. Each value is a format
+	// string rendered as a string field of the object.
+	// +optional
+	JSON map[string]string `json:"json,omitempty"`
+
+	// ContentType overrides the Content-Type header of the reply.
+	// Envoy's default is `text/plain` for Text and `application/json`
+	// for JSON.
+	// +optional
+	ContentType string `json:"contentType,omitempty"`
+}
+
 // SocketOptions defines configurable socket options for Envoy listeners.
 type SocketOptions struct {
 	// Defines the value for IPv4 TOS field (including 6 bit DSCP field) for IP packets originating from Envoy listeners.
@@ -519,6 +882,15 @@ type EnvoyTLS struct {
 	// Note: This list is a superset of what is valid for stock Envoy builds and those using BoringSSL FIPS.
 	// +optional
 	CipherSuites []string `json:"cipherSuites,omitempty"`
+
+	// DisableSessionTickets stops Envoy TLS listeners issuing TLS session
+	// tickets, so clients cannot resume a session without a full handshake.
+	// Individual HTTPProxies can also disable session tickets. It is only
+	// supported in envoy.listener.tls, not envoy.cluster.upstreamTLS.
+	//
+	// Contour's default is false.
+	// +optional
+	DisableSessionTickets *bool `json:"disableSessionTickets,omitempty"`
 }
 
 // EnvoyListener defines parameters for an Envoy Listener.
@@ -732,6 +1104,15 @@ type HTTPProxyConfig struct {
 	// +optional
 	RootNamespaces []string `json:"rootNamespaces,omitempty"`
 
+	// RootNamespaceSelector is a label selector, e.g. "tenant=true",
+	// that also permits root ingress routes in the namespaces whose
+	// labels match it. It is evaluated whenever namespaces change, so
+	// labeling a namespace makes its HTTPProxies eligible as roots
+	// without restarting Contour. A namespace is permitted if it is
+	// listed in RootNamespaces or matches RootNamespaceSelector.
+	// +optional
+	RootNamespaceSelector *string `json:"rootNamespaceSelector,omitempty"`
+
 	// FallbackCertificate defines the namespace/name of the Kubernetes secret to
 	// use as fallback when a non-SNI request is received.
 	// +optional
@@ -861,6 +1242,35 @@ type PolicyConfig struct {
 	// Contour's default is false.
 	// +optional
 	ApplyToIngress *bool `json:"applyToIngress,omitempty"`
+
+	// ContourVersionHeader determines if an X-Contour-Version header,
+	// containing the version of Contour that generated the configuration,
+	// is added to all responses. This is intended to aid debugging.
+	//
+	// Contour's default is false.
+	// +optional
+	ContourVersionHeader *bool `json:"contourVersionHeader,omitempty"`
+
+	// RouteIdentifierHeader is the name of a request header, e.g.
+	// "x-contour-route", that is set on every proxied request to an
+	// identifier of the route that matched it. Backends can use it to
+	// tell which route a request was sent by. The identifier is stable
+	// across Contour restarts and has the form
+	// "<kind>/<namespace>/<name>/<conditions>", e.g.
+	// "HTTPProxy/default/kuard/prefix=/api,header:x-env:exact=prod".
+	//
+	// The conditions are a comma separated list of the path condition,
+	// one of "prefix=<path>", "segment-prefix=<path>", "exact=<path>" or
+	// "regex=<regex>", followed by each header condition as
+	// "header:<name>:<match type>=<value>" and each query parameter
+	// condition as "query:<name>:<match type>=<value>". The match type is
+	// prefixed with "not-" for inverted matches and suffixed with
+	// "-ignorecase" for case insensitive matches, and "=<value>" is
+	// omitted for present matches.
+	//
+	// Contour's default is to not set a route identifier header.
+	// +optional
+	RouteIdentifierHeader string `json:"routeIdentifierHeader,omitempty"`
 }
 
 type HeadersPolicy struct {
@@ -15,10 +15,18 @@ package v1alpha1
 
 import (
 	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 )
 
 const featureFlagUseEndpointSlices string = "useEndpointSlices"
@@ -39,16 +47,52 @@ func (c *ContourConfigurationSpec) Validate() error {
 
 	if c.XDSServer != nil {
 		validateFuncs = append(validateFuncs, c.XDSServer.Type.Validate)
+		if err := ValidateXDSAddress(c.XDSServer.Address); err != nil {
+			return err
+		}
+		if c.XDSServer.Delta != nil && *c.XDSServer.Delta && c.XDSServer.Type == ContourServerType {
+			return fmt.Errorf("xdsServer.delta requires the %q xDS server type", EnvoyServerType)
+		}
+		if c.XDSServer.ResourceTTL != nil {
+			if err := ValidateXDSResourceTTL(*c.XDSServer.ResourceTTL); err != nil {
+				return err
+			}
+			if c.XDSServer.Type == ContourServerType {
+				return fmt.Errorf("xdsServer.resourceTTL requires the %q xDS server type", EnvoyServerType)
+			}
+		}
+		if c.XDSServer.ShutdownDrainTime != nil {
+			if err := ValidateShutdownDrainTime(*c.XDSServer.ShutdownDrainTime); err != nil {
+				return err
+			}
+		}
+		if c.XDSServer.Keepalive != nil {
+			validateFuncs = append(validateFuncs, c.XDSServer.Keepalive.Validate)
+		}
 	}
 	if c.Envoy != nil {
 		validateFuncs = append(validateFuncs, c.Envoy.Validate)
 	}
+	if c.LeaderElection != nil {
+		validateFuncs = append(validateFuncs, c.LeaderElection.Validate)
+	}
+	if c.HTTPProxy != nil {
+		validateFuncs = append(validateFuncs, c.HTTPProxy.Validate)
+	}
 	if c.Gateway != nil {
 		validateFuncs = append(validateFuncs, c.Gateway.Validate)
 	}
 	if c.Tracing != nil {
 		validateFuncs = append(validateFuncs, c.Tracing.Validate)
 	}
+	if c.Policy != nil {
+		validateFuncs = append(validateFuncs, c.Policy.Validate)
+	}
+	if auth := c.GlobalExternalAuthorization; auth != nil && auth.WithRequestBody != nil &&
+		auth.WithRequestBody.MaxRequestBytes > contour_v1.MaxAuthorizationRequestBytes {
+		return fmt.Errorf("globalExtAuth.withRequestBody.maxRequestBytes %d exceeds the maximum of %d bytes",
+			auth.WithRequestBody.MaxRequestBytes, contour_v1.MaxAuthorizationRequestBytes)
+	}
 
 	for _, validate := range validateFuncs {
 		if err := validate(); err != nil {
@@ -59,6 +103,18 @@ func (c *ContourConfigurationSpec) Validate() error {
 	return nil
 }
 
+// Validate ensures that the root namespace selector, if set, is a valid
+// label selector.
+func (h *HTTPProxyConfig) Validate() error {
+	if h.RootNamespaceSelector == nil {
+		return nil
+	}
+	if _, err := labels.Parse(*h.RootNamespaceSelector); err != nil {
+		return fmt.Errorf("invalid httpproxy.rootNamespaceSelector %q: %v", *h.RootNamespaceSelector, err)
+	}
+	return nil
+}
+
 func (t *TracingConfig) Validate() error {
 	if t.ExtensionService == nil {
 		return fmt.Errorf("tracing.extensionService must be defined")
@@ -109,6 +165,83 @@ func (x XDSServerType) Validate() error {
 	}
 }
 
+// Validate ensures that the xDS server keepalive durations that are
+// set are positive Go duration strings.
+func (k *XDSServerKeepalive) Validate() error {
+	for _, setting := range []struct {
+		name  string
+		value *string
+	}{
+		{"xds keepalive time", k.Time},
+		{"xds keepalive timeout", k.Timeout},
+		{"xds keepalive min time", k.MinTime},
+	} {
+		if setting.value == nil {
+			continue
+		}
+		d, err := time.ParseDuration(*setting.value)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %v", setting.name, *setting.value, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("invalid %s %q: must be positive", setting.name, *setting.value)
+		}
+	}
+
+	return nil
+}
+
+// Validate ensures the leader election durations are positive Go
+// duration strings satisfying leaseDuration > renewDeadline > retryPeriod,
+// and that the resource lock type is supported.
+func (l *LeaderElectionConfig) Validate() error {
+	durations := map[string]time.Duration{}
+	for _, setting := range []struct {
+		name  string
+		value *string
+	}{
+		{"leaseDuration", l.LeaseDuration},
+		{"renewDeadline", l.RenewDeadline},
+		{"retryPeriod", l.RetryPeriod},
+	} {
+		if setting.value == nil {
+			continue
+		}
+		d, err := time.ParseDuration(*setting.value)
+		if err != nil {
+			return fmt.Errorf("invalid leader election %s %q: %v", setting.name, *setting.value, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("invalid leader election %s %q: must be positive", setting.name, *setting.value)
+		}
+		durations[setting.name] = d
+	}
+
+	for _, pair := range [][2]string{
+		{"leaseDuration", "renewDeadline"},
+		{"renewDeadline", "retryPeriod"},
+		{"leaseDuration", "retryPeriod"},
+	} {
+		longer, okLonger := durations[pair[0]]
+		shorter, okShorter := durations[pair[1]]
+		if okLonger && okShorter && longer <= shorter {
+			return fmt.Errorf("invalid leader election durations: %s (%s) must be greater than %s (%s)", pair[0], longer, pair[1], shorter)
+		}
+	}
+
+	return l.ResourceLock.Validate()
+}
+
+// Validate ensures the resource lock type is supported.
+func (r LeaderElectionResourceLock) Validate() error {
+	switch r {
+	case "", LeasesResourceLock:
+		return nil
+	default:
+		return fmt.Errorf("invalid leader election resource lock %q", r)
+	}
+}
+
 func (d ClusterDNSFamilyType) Validate() error {
 	switch d {
 	case AutoClusterDNSFamily, IPv4ClusterDNSFamily, IPv6ClusterDNSFamily, AllClusterDNSFamily:
@@ -147,6 +280,81 @@ func (e *EnvoyConfig) Validate() error {
 		if err := e.Cluster.DNSLookupFamily.Validate(); err != nil {
 			return err
 		}
+
+		// EnvoyTLS is shared with the listeners, but Envoy only
+		// issues session tickets as a TLS server.
+		if e.Cluster.UpstreamTLS != nil && e.Cluster.UpstreamTLS.DisableSessionTickets != nil {
+			return fmt.Errorf("invalid envoy cluster configuration: upstreamTLS.disableSessionTickets is only supported on listeners")
+		}
+	}
+
+	if e.Listener != nil && e.Listener.StripPortFromHost != nil && *e.Listener.StripPortFromHost &&
+		e.Listener.StripMatchingHostPort != nil && *e.Listener.StripMatchingHostPort {
+		return fmt.Errorf("invalid envoy listener configuration: stripPortFromHost and stripMatchingHostPort cannot both be enabled")
+	}
+
+	if e.Listener != nil {
+		if e.Listener.ProxyProtocol != nil && (e.Listener.UseProxyProto == nil || !*e.Listener.UseProxyProto) {
+			return fmt.Errorf("invalid envoy listener configuration: proxyProtocol requires useProxyProtocol to be enabled")
+		}
+
+		if err := e.Listener.ProxyProtocol.Validate(); err != nil {
+			return err
+		}
+
+		if t := e.Listener.ListenerFiltersTimeout; t != nil {
+			if err := ValidateTimeout("listener filters timeout", *t); err != nil {
+				return fmt.Errorf("invalid envoy listener configuration: %v", err)
+			}
+		}
+
+		if err := ValidateHTTP2MaxConcurrentStreams(e.Listener.HTTP2MaxConcurrentStreams); err != nil {
+			return fmt.Errorf("invalid envoy listener configuration: %v", err)
+		}
+
+		var keepaliveInterval, keepaliveTimeout string
+		if e.Listener.HTTP2KeepaliveInterval != nil {
+			keepaliveInterval = *e.Listener.HTTP2KeepaliveInterval
+		}
+		if e.Listener.HTTP2KeepaliveTimeout != nil {
+			keepaliveTimeout = *e.Listener.HTTP2KeepaliveTimeout
+		}
+		if err := ValidateHTTP2Keepalive(keepaliveInterval, keepaliveTimeout); err != nil {
+			return fmt.Errorf("invalid envoy listener configuration: %v", err)
+		}
+
+		if err := ValidateHTTP10DefaultHost(e.Listener.DisableAcceptHTTP10 == nil || !*e.Listener.DisableAcceptHTTP10, e.Listener.HTTP10DefaultHost); err != nil {
+			return fmt.Errorf("invalid envoy listener configuration: %v", err)
+		}
+
+		if e.Listener.ConnectionBalancer != "" && e.Listener.ConnectionBalancer != "exact" {
+			return fmt.Errorf("invalid envoy listener configuration: invalid connection balancer value %q, only 'exact' connection balancing is supported", e.Listener.ConnectionBalancer)
+		}
+
+		if err := e.Listener.ServerHeaderTransformation.Validate(); err != nil {
+			return fmt.Errorf("invalid envoy listener configuration: %v", err)
+		}
+
+		if err := ValidateHeaderValue("server name", e.Listener.ServerName); err != nil {
+			return fmt.Errorf("invalid envoy listener configuration: %v", err)
+		}
+
+		if err := ValidateHeaderValue("via", e.Listener.Via); err != nil {
+			return fmt.Errorf("invalid envoy listener configuration: %v", err)
+		}
+
+		if err := e.Listener.LocalReplyPolicy.Validate(); err != nil {
+			return fmt.Errorf("invalid envoy listener configuration: %v", err)
+		}
+
+		if h := e.Listener.HTTP3; h != nil && h.Enabled != nil && *h.Enabled &&
+			e.Listener.TLS != nil && e.Listener.TLS.MaximumProtocolVersion == "1.2" {
+			return fmt.Errorf("invalid envoy listener configuration: http3 requires TLS 1.3 but the maximum TLS protocol version is 1.2")
+		}
+	}
+
+	if err := e.StatsCollector.Validate(); err != nil {
+		return fmt.Errorf("invalid envoy stats collector configuration: %v", err)
 	}
 
 	// Envoy TLS configuration
@@ -157,6 +365,322 @@ func (e *EnvoyConfig) Validate() error {
 	return nil
 }
 
+// Validate ensures the stats collector has a valid URL
+// and poll interval when polling is enabled.
+func (s *EnvoyStatsCollectorConfig) Validate() error {
+	interval, err := s.Interval()
+	if err != nil {
+		return err
+	}
+	if interval == 0 {
+		return nil
+	}
+
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return fmt.Errorf("invalid url %q: %v", s.URL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid url %q: must be an absolute http or https URL", s.URL)
+	}
+
+	return nil
+}
+
+// Interval returns the parsed poll interval of the stats collector.
+// A zero interval means polling is disabled.
+func (s *EnvoyStatsCollectorConfig) Interval() (time.Duration, error) {
+	if s == nil || s.PollInterval == "" {
+		return 0, nil
+	}
+
+	interval, err := time.ParseDuration(s.PollInterval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid poll interval %q: %v", s.PollInterval, err)
+	}
+	if interval < 0 {
+		return 0, fmt.Errorf("invalid poll interval %q: must not be negative", s.PollInterval)
+	}
+
+	return interval, nil
+}
+
+// Validate ensures ServerHeaderTransformationType is valid. The
+// empty value selects the default.
+func (s ServerHeaderTransformationType) Validate() error {
+	switch s {
+	case "", OverwriteServerHeader, AppendIfAbsentServerHeader, PassThroughServerHeader:
+		return nil
+	default:
+		return fmt.Errorf("invalid server header transformation %q", s)
+	}
+}
+
+// Validate ensures the policy's route identifier header is valid.
+func (p *PolicyConfig) Validate() error {
+	if err := ValidateRouteIdentifierHeader(p.RouteIdentifierHeader); err != nil {
+		return fmt.Errorf("invalid policy configuration: %v", err)
+	}
+	return nil
+}
+
+// ValidateRouteIdentifierHeader ensures name is a valid HTTP header
+// name that can be set on requests. The empty name is valid and
+// disables the route identifier header.
+func ValidateRouteIdentifierHeader(name string) error {
+	if name == "" {
+		return nil
+	}
+	if msgs := validation.IsHTTPHeaderName(name); len(msgs) != 0 {
+		return fmt.Errorf("invalid route identifier header %q: %s", name, strings.Join(msgs, ", "))
+	}
+	if strings.EqualFold(name, "host") {
+		return fmt.Errorf("invalid route identifier header %q: the host header cannot be set", name)
+	}
+	return nil
+}
+
+// XDSUnixSocketPrefix marks an xDS server address as the path of a
+// Unix domain socket rather than a TCP address.
+const XDSUnixSocketPrefix = "unix://"
+
+// ValidateXDSAddress ensures that, if the xDS address is a Unix domain
+// socket, its path is absolute and clean.
+func ValidateXDSAddress(address string) error {
+	path, ok := strings.CutPrefix(address, XDSUnixSocketPrefix)
+	if !ok {
+		return nil
+	}
+
+	switch {
+	case !filepath.IsAbs(path):
+		return fmt.Errorf("invalid xds address %q: unix socket path must be absolute", address)
+	case filepath.Clean(path) != path:
+		return fmt.Errorf("invalid xds address %q: unix socket path must be clean", address)
+	case strings.ContainsRune(path, 0):
+		return fmt.Errorf("invalid xds address %q: unix socket path must not contain NUL characters", address)
+	}
+
+	return nil
+}
+
+// MinXDSResourceTTL is the shortest xDS resource TTL Contour accepts,
+// so that heartbeats are not sent more often than every 500ms.
+const MinXDSResourceTTL = time.Second
+
+// ValidateXDSResourceTTL ensures that, if set, the xDS resource TTL is
+// a Go duration string of at least MinXDSResourceTTL.
+func ValidateXDSResourceTTL(ttl string) error {
+	if ttl == "" {
+		return nil
+	}
+
+	d, err := time.ParseDuration(ttl)
+	if err != nil {
+		return fmt.Errorf("invalid xds resource ttl %q: %v", ttl, err)
+	}
+	if d < MinXDSResourceTTL {
+		return fmt.Errorf("invalid xds resource ttl %q: must be at least %s", ttl, MinXDSResourceTTL)
+	}
+
+	return nil
+}
+
+// MaxShutdownDrainTime is the longest shutdown drain time Contour accepts.
+const MaxShutdownDrainTime = 10 * time.Minute
+
+// ValidateShutdownDrainTime ensures that, if set, the shutdown drain time
+// is a Go duration string between zero and MaxShutdownDrainTime.
+func ValidateShutdownDrainTime(drainTime string) error {
+	if drainTime == "" {
+		return nil
+	}
+
+	d, err := time.ParseDuration(drainTime)
+	if err != nil {
+		return fmt.Errorf("invalid shutdown drain time %q: %v", drainTime, err)
+	}
+	if d < 0 || d > MaxShutdownDrainTime {
+		return fmt.Errorf("invalid shutdown drain time %q: must be between 0s and %s", drainTime, MaxShutdownDrainTime)
+	}
+
+	return nil
+}
+
+// ValidateTimeout ensures the value of the named timeout setting is
+// a non-negative Go duration string, or "infinity" or "infinite" to
+// disable the timeout. The empty value is valid and selects the
+// setting's default.
+func ValidateTimeout(setting, value string) error {
+	switch value {
+	case "", "infinity", "infinite":
+		return nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: %v", setting, value, err)
+	}
+	if d < 0 {
+		return fmt.Errorf("invalid %s %q: must not be negative", setting, value)
+	}
+
+	return nil
+}
+
+// MaxHTTP2ConcurrentStreams is the largest HTTP/2 max concurrent streams
+// value Envoy accepts.
+const MaxHTTP2ConcurrentStreams = 2147483647
+
+// ValidateHTTP2MaxConcurrentStreams ensures that, if set, the HTTP/2 max
+// concurrent streams value is within the range Envoy accepts.
+func ValidateHTTP2MaxConcurrentStreams(streams *uint32) error {
+	if streams == nil {
+		return nil
+	}
+	if *streams < 1 || *streams > MaxHTTP2ConcurrentStreams {
+		return fmt.Errorf("invalid max HTTP/2 concurrent streams value %d, must be between 1 and %d", *streams, MaxHTTP2ConcurrentStreams)
+	}
+	return nil
+}
+
+// ValidateHTTP2Keepalive ensures that the HTTP/2 keepalive interval and
+// timeout are either both unset, or both durations of at least 1ms.
+func ValidateHTTP2Keepalive(interval, timeout string) error {
+	if interval == "" && timeout == "" {
+		return nil
+	}
+	if interval == "" || timeout == "" {
+		return fmt.Errorf("http2 keepalive interval and timeout must be set together")
+	}
+
+	for _, setting := range []struct{ name, value string }{
+		{"http2 keepalive interval", interval},
+		{"http2 keepalive timeout", timeout},
+	} {
+		d, err := time.ParseDuration(setting.value)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %v", setting.name, setting.value, err)
+		}
+		if d < time.Millisecond {
+			return fmt.Errorf("invalid %s %q: must be at least 1ms", setting.name, setting.value)
+		}
+	}
+
+	return nil
+}
+
+// ValidateHTTP10DefaultHost ensures that, if set, the default host for
+// HTTP/1.0 requests is a hostname or IP address with an optional port,
+// and that HTTP/1.0 requests are accepted.
+func ValidateHTTP10DefaultHost(acceptHTTP10 bool, host string) error {
+	if host == "" {
+		return nil
+	}
+	if !acceptHTTP10 {
+		return fmt.Errorf("http/1.0 default host %q cannot be set when http/1.0 is not accepted", host)
+	}
+
+	name := host
+	if h, port, err := net.SplitHostPort(host); err == nil {
+		if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+			return fmt.Errorf("invalid http/1.0 default host %q: invalid port %q", host, port)
+		}
+		name = h
+	}
+	if net.ParseIP(name) != nil {
+		return nil
+	}
+	if errs := validation.IsDNS1123Subdomain(strings.ToLower(name)); len(errs) > 0 {
+		return fmt.Errorf("invalid http/1.0 default host %q: %s", host, strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+// ValidateHeaderValue ensures the value of the named setting is a valid
+// HTTP header value without surrounding whitespace. The empty value is
+// valid and selects the setting's default.
+func ValidateHeaderValue(setting, value string) error {
+	if strings.TrimSpace(value) != value {
+		return fmt.Errorf("invalid %s %q: must not begin or end with whitespace", setting, value)
+	}
+	for _, r := range value {
+		if r < 0x20 || r == 0x7f {
+			return fmt.Errorf("invalid %s %q: must not contain control characters", setting, value)
+		}
+	}
+	return nil
+}
+
+// Validate ensures LocalReplyPolicy is valid.
+func (l *LocalReplyPolicy) Validate() error {
+	if l == nil {
+		return nil
+	}
+
+	if l.Format != nil {
+		if err := l.Format.Validate(); err != nil {
+			return fmt.Errorf("invalid local reply format: %v", err)
+		}
+	}
+
+	seen := map[int]bool{}
+	for _, o := range l.Overrides {
+		if o.StatusCode < 100 || o.StatusCode > 599 {
+			return fmt.Errorf("invalid local reply override: status code %d must be between 100 and 599", o.StatusCode)
+		}
+		if seen[o.StatusCode] {
+			return fmt.Errorf("invalid local reply override: duplicate status code %d", o.StatusCode)
+		}
+		seen[o.StatusCode] = true
+
+		if err := o.Format.Validate(); err != nil {
+			return fmt.Errorf("invalid local reply override for status code %d: %v", o.StatusCode, err)
+		}
+	}
+
+	return nil
+}
+
+// Validate ensures LocalReplyFormat is valid.
+func (f *LocalReplyFormat) Validate() error {
+	switch {
+	case f.Text != "" && len(f.JSON) > 0:
+		return fmt.Errorf("only one of text or json can be set")
+	case f.Text == "" && len(f.JSON) == 0:
+		return fmt.Errorf("one of text or json must be set")
+	}
+
+	if err := parseAccessLogFormatString(f.Text); err != nil {
+		return err
+	}
+	for key, val := range f.JSON {
+		if err := parseAccessLogFormatString(val); err != nil {
+			return fmt.Errorf("invalid json field %q: %v", key, err)
+		}
+	}
+
+	return nil
+}
+
+// Validate ensures ProxyProtocolConfig is valid.
+func (p *ProxyProtocolConfig) Validate() error {
+	if p == nil {
+		return nil
+	}
+
+	for _, v := range p.Versions {
+		switch v {
+		case ProxyProtocolVersion1, ProxyProtocolVersion2:
+		default:
+			return fmt.Errorf("invalid PROXY protocol version %q", v)
+		}
+	}
+
+	return nil
+}
+
 func ValidateTLSProtocolVersions(min, max string) error {
 	parseVersion := func(version, tip, defVal string) (string, error) {
 		switch version {
@@ -212,8 +736,14 @@ func (e *EnvoyTLS) Validate() error {
 		return err
 	}
 
+	return ValidateTLSCiphers(e.CipherSuites)
+}
+
+// ValidateTLSCiphers returns an error listing any ciphers that are
+// not in the set of TLS ciphers Envoy supports by default.
+func ValidateTLSCiphers(ciphers []string) error {
 	var invalidCipherSuites []string
-	for _, c := range e.CipherSuites {
+	for _, c := range ciphers {
 		if !isValidTLSCipher(c) {
 			invalidCipherSuites = append(invalidCipherSuites, c)
 		}
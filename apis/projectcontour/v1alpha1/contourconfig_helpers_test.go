@@ -21,6 +21,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"k8s.io/utils/ptr"
 
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 )
 
@@ -38,6 +39,161 @@ func TestContourConfigurationSpecValidate(t *testing.T) {
 
 		c.XDSServer.Type = "foo"
 		require.Error(t, c.Validate())
+
+		c.XDSServer.Type = contour_v1alpha1.EnvoyServerType
+		c.XDSServer.Delta = ptr.To(true)
+		require.NoError(t, c.Validate())
+
+		c.XDSServer.Type = contour_v1alpha1.ContourServerType
+		require.Error(t, c.Validate())
+	})
+
+	t.Run("httpproxy root namespace selector validation", func(t *testing.T) {
+		c := contour_v1alpha1.ContourConfigurationSpec{
+			HTTPProxy: &contour_v1alpha1.HTTPProxyConfig{},
+		}
+		require.NoError(t, c.Validate())
+
+		c.HTTPProxy.RootNamespaceSelector = ptr.To("tenant=true")
+		require.NoError(t, c.Validate())
+
+		c.HTTPProxy.RootNamespaceSelector = ptr.To("tenant in (a,b),!legacy")
+		require.NoError(t, c.Validate())
+
+		c.HTTPProxy.RootNamespaceSelector = ptr.To("tenant in a")
+		require.Error(t, c.Validate())
+	})
+
+	t.Run("xds server address validation", func(t *testing.T) {
+		c := contour_v1alpha1.ContourConfigurationSpec{
+			XDSServer: &contour_v1alpha1.XDSServerConfig{
+				Type:    contour_v1alpha1.EnvoyServerType,
+				Address: "0.0.0.0",
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		c.XDSServer.Address = "unix:///var/run/contour/xds.sock"
+		require.NoError(t, c.Validate())
+
+		c.XDSServer.Address = "unix://xds.sock"
+		require.Error(t, c.Validate())
+
+		c.XDSServer.Address = "unix:///var/run/contour/../xds.sock"
+		require.Error(t, c.Validate())
+
+		c.XDSServer.Address = "unix://"
+		require.Error(t, c.Validate())
+	})
+
+	t.Run("xds server resource ttl validation", func(t *testing.T) {
+		c := contour_v1alpha1.ContourConfigurationSpec{
+			XDSServer: &contour_v1alpha1.XDSServerConfig{
+				Type: contour_v1alpha1.EnvoyServerType,
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		c.XDSServer.ResourceTTL = ptr.To("")
+		require.NoError(t, c.Validate())
+
+		c.XDSServer.ResourceTTL = ptr.To("30s")
+		require.NoError(t, c.Validate())
+
+		c.XDSServer.ResourceTTL = ptr.To("500ms")
+		require.Error(t, c.Validate())
+
+		c.XDSServer.ResourceTTL = ptr.To("forever")
+		require.Error(t, c.Validate())
+
+		c.XDSServer.ResourceTTL = ptr.To("30s")
+		c.XDSServer.Type = contour_v1alpha1.ContourServerType
+		require.Error(t, c.Validate())
+	})
+
+	t.Run("xds server shutdown drain time validation", func(t *testing.T) {
+		c := contour_v1alpha1.ContourConfigurationSpec{
+			XDSServer: &contour_v1alpha1.XDSServerConfig{
+				Type:              contour_v1alpha1.EnvoyServerType,
+				ShutdownDrainTime: ptr.To("0s"),
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		c.XDSServer.ShutdownDrainTime = ptr.To("45s")
+		require.NoError(t, c.Validate())
+
+		c.XDSServer.ShutdownDrainTime = ptr.To("10m")
+		require.NoError(t, c.Validate())
+
+		c.XDSServer.ShutdownDrainTime = ptr.To("-1s")
+		require.Error(t, c.Validate())
+
+		c.XDSServer.ShutdownDrainTime = ptr.To("11m")
+		require.Error(t, c.Validate())
+
+		c.XDSServer.ShutdownDrainTime = ptr.To("soon")
+		require.Error(t, c.Validate())
+	})
+
+	t.Run("leader election validation", func(t *testing.T) {
+		c := contour_v1alpha1.ContourConfigurationSpec{
+			LeaderElection: &contour_v1alpha1.LeaderElectionConfig{
+				LeaseDuration: ptr.To("15s"),
+				RenewDeadline: ptr.To("10s"),
+				RetryPeriod:   ptr.To("2s"),
+				ResourceLock:  contour_v1alpha1.LeasesResourceLock,
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		c.LeaderElection.RetryPeriod = ptr.To("10s")
+		require.Error(t, c.Validate())
+
+		c.LeaderElection.RetryPeriod = ptr.To("2s")
+		c.LeaderElection.RenewDeadline = ptr.To("20s")
+		require.Error(t, c.Validate())
+
+		c.LeaderElection.RenewDeadline = nil
+		c.LeaderElection.RetryPeriod = ptr.To("15s")
+		require.Error(t, c.Validate())
+
+		c.LeaderElection.RetryPeriod = ptr.To("-2s")
+		require.Error(t, c.Validate())
+
+		c.LeaderElection.RetryPeriod = ptr.To("often")
+		require.Error(t, c.Validate())
+
+		c.LeaderElection.RetryPeriod = ptr.To("2s")
+		require.NoError(t, c.Validate())
+
+		c.LeaderElection.ResourceLock = "configmaps"
+		require.Error(t, c.Validate())
+
+		c.LeaderElection.ResourceLock = "endpoints"
+		require.Error(t, c.Validate())
+	})
+
+	t.Run("xds server keepalive validation", func(t *testing.T) {
+		c := contour_v1alpha1.ContourConfigurationSpec{
+			XDSServer: &contour_v1alpha1.XDSServerConfig{
+				Type:      contour_v1alpha1.EnvoyServerType,
+				Keepalive: &contour_v1alpha1.XDSServerKeepalive{},
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		c.XDSServer.Keepalive.Time = ptr.To("30s")
+		c.XDSServer.Keepalive.Timeout = ptr.To("10s")
+		c.XDSServer.Keepalive.MinTime = ptr.To("10s")
+		require.NoError(t, c.Validate())
+
+		c.XDSServer.Keepalive.Timeout = ptr.To("0s")
+		require.Error(t, c.Validate())
+
+		c.XDSServer.Keepalive.Timeout = ptr.To("10s")
+		c.XDSServer.Keepalive.MinTime = ptr.To("soon")
+		require.Error(t, c.Validate())
 	})
 
 	t.Run("envoy validation", func(t *testing.T) {
@@ -105,6 +261,10 @@ func TestContourConfigurationSpecValidate(t *testing.T) {
 		c.Envoy.Cluster.DNSLookupFamily = "foo"
 		require.Error(t, c.Validate())
 
+		c.Envoy.Cluster.DNSLookupFamily = contour_v1alpha1.AutoClusterDNSFamily
+		c.Envoy.Cluster.UpstreamTLS = &contour_v1alpha1.EnvoyTLS{DisableSessionTickets: ptr.To(true)}
+		require.Error(t, c.Validate())
+
 		c = contour_v1alpha1.ContourConfigurationSpec{
 			Envoy: &contour_v1alpha1.EnvoyConfig{
 				Listener: &contour_v1alpha1.EnvoyListenerConfig{
@@ -179,6 +339,280 @@ func TestContourConfigurationSpecValidate(t *testing.T) {
 		require.Error(t, c.Validate())
 	})
 
+	t.Run("envoy listener strip port validation", func(t *testing.T) {
+		c := contour_v1alpha1.ContourConfigurationSpec{
+			Envoy: &contour_v1alpha1.EnvoyConfig{
+				Listener: &contour_v1alpha1.EnvoyListenerConfig{
+					StripPortFromHost: ptr.To(true),
+				},
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Listener.StripMatchingHostPort = ptr.To(false)
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Listener.StripMatchingHostPort = ptr.To(true)
+		require.Error(t, c.Validate())
+
+		c.Envoy.Listener.StripPortFromHost = nil
+		require.NoError(t, c.Validate())
+	})
+
+	t.Run("envoy listener proxy protocol validation", func(t *testing.T) {
+		c := contour_v1alpha1.ContourConfigurationSpec{
+			Envoy: &contour_v1alpha1.EnvoyConfig{
+				Listener: &contour_v1alpha1.EnvoyListenerConfig{
+					UseProxyProto: ptr.To(true),
+					ProxyProtocol: &contour_v1alpha1.ProxyProtocolConfig{
+						Versions: []contour_v1alpha1.ProxyProtocolVersion{contour_v1alpha1.ProxyProtocolVersion2},
+					},
+				},
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Listener.UseProxyProto = ptr.To(false)
+		require.Error(t, c.Validate())
+
+		c.Envoy.Listener.UseProxyProto = nil
+		require.Error(t, c.Validate())
+
+		c.Envoy.Listener.UseProxyProto = ptr.To(true)
+		c.Envoy.Listener.ProxyProtocol.Versions = append(c.Envoy.Listener.ProxyProtocol.Versions, "v3")
+		require.Error(t, c.Validate())
+	})
+
+	t.Run("envoy listener connection balancer validation", func(t *testing.T) {
+		c := contour_v1alpha1.ContourConfigurationSpec{
+			Envoy: &contour_v1alpha1.EnvoyConfig{
+				Listener: &contour_v1alpha1.EnvoyListenerConfig{
+					ConnectionBalancer: "exact",
+				},
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Listener.ConnectionBalancer = "round-robin"
+		require.Error(t, c.Validate())
+	})
+
+	t.Run("envoy listener http3 validation", func(t *testing.T) {
+		c := contour_v1alpha1.ContourConfigurationSpec{
+			Envoy: &contour_v1alpha1.EnvoyConfig{
+				Listener: &contour_v1alpha1.EnvoyListenerConfig{
+					HTTP3: &contour_v1alpha1.HTTP3Config{
+						Enabled: ptr.To(true),
+					},
+					TLS: &contour_v1alpha1.EnvoyTLS{
+						MaximumProtocolVersion: "1.3",
+					},
+				},
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Listener.TLS.MaximumProtocolVersion = "1.2"
+		require.Error(t, c.Validate())
+
+		c.Envoy.Listener.HTTP3.Enabled = ptr.To(false)
+		require.NoError(t, c.Validate())
+	})
+
+	t.Run("envoy listener server header validation", func(t *testing.T) {
+		c := contour_v1alpha1.ContourConfigurationSpec{
+			Envoy: &contour_v1alpha1.EnvoyConfig{
+				Listener: &contour_v1alpha1.EnvoyListenerConfig{
+					ServerHeaderTransformation: contour_v1alpha1.AppendIfAbsentServerHeader,
+					ServerName:                 "example server",
+				},
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Listener.ServerName = " example"
+		require.Error(t, c.Validate())
+
+		c.Envoy.Listener.ServerName = "example\r\nx-injected: true"
+		require.Error(t, c.Validate())
+
+		c.Envoy.Listener.ServerName = ""
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Listener.ServerHeaderTransformation = "remove"
+		require.Error(t, c.Validate())
+	})
+
+	t.Run("envoy stats collector validation", func(t *testing.T) {
+		c := contour_v1alpha1.ContourConfigurationSpec{
+			Envoy: &contour_v1alpha1.EnvoyConfig{
+				StatsCollector: &contour_v1alpha1.EnvoyStatsCollectorConfig{
+					URL:          "http://envoy.projectcontour:8002/stats",
+					PollInterval: "15s",
+				},
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		c.Envoy.StatsCollector.PollInterval = "often"
+		require.Error(t, c.Validate())
+
+		c.Envoy.StatsCollector.PollInterval = "-1s"
+		require.Error(t, c.Validate())
+
+		c.Envoy.StatsCollector.PollInterval = "15s"
+		c.Envoy.StatsCollector.URL = "envoy:8002/stats"
+		require.Error(t, c.Validate())
+
+		// The URL is not used when polling is disabled.
+		c.Envoy.StatsCollector.PollInterval = "0s"
+		require.NoError(t, c.Validate())
+	})
+
+	t.Run("envoy listener via header validation", func(t *testing.T) {
+		c := contour_v1alpha1.ContourConfigurationSpec{
+			Envoy: &contour_v1alpha1.EnvoyConfig{
+				Listener: &contour_v1alpha1.EnvoyListenerConfig{
+					UseRemoteAddress: ptr.To(false),
+					Via:              "1.1 contour",
+				},
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Listener.Via = "1.1 contour\r\nx-injected: true"
+		require.Error(t, c.Validate())
+
+		c.Envoy.Listener.Via = "1.1 contour "
+		require.Error(t, c.Validate())
+	})
+
+	t.Run("envoy listener filters timeout validation", func(t *testing.T) {
+		c := contour_v1alpha1.ContourConfigurationSpec{
+			Envoy: &contour_v1alpha1.EnvoyConfig{
+				Listener: &contour_v1alpha1.EnvoyListenerConfig{
+					ListenerFiltersTimeout: ptr.To("30s"),
+				},
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Listener.ListenerFiltersTimeout = ptr.To("infinity")
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Listener.ListenerFiltersTimeout = ptr.To("-1s")
+		require.Error(t, c.Validate())
+
+		c.Envoy.Listener.ListenerFiltersTimeout = ptr.To("30")
+		require.Error(t, c.Validate())
+	})
+
+	t.Run("envoy listener http2 validation", func(t *testing.T) {
+		c := contour_v1alpha1.ContourConfigurationSpec{
+			Envoy: &contour_v1alpha1.EnvoyConfig{
+				Listener: &contour_v1alpha1.EnvoyListenerConfig{
+					HTTP2MaxConcurrentStreams: ptr.To(uint32(100)),
+					HTTP2KeepaliveInterval:    ptr.To("30s"),
+					HTTP2KeepaliveTimeout:     ptr.To("5s"),
+				},
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Listener.HTTP2MaxConcurrentStreams = ptr.To(uint32(2147483648))
+		require.Error(t, c.Validate())
+
+		c.Envoy.Listener.HTTP2MaxConcurrentStreams = nil
+		c.Envoy.Listener.HTTP2KeepaliveTimeout = nil
+		require.Error(t, c.Validate())
+
+		c.Envoy.Listener.HTTP2KeepaliveTimeout = ptr.To("infinity")
+		require.Error(t, c.Validate())
+
+		c.Envoy.Listener.HTTP2KeepaliveTimeout = ptr.To("500us")
+		require.Error(t, c.Validate())
+	})
+
+	t.Run("envoy listener http/1.0 default host validation", func(t *testing.T) {
+		c := contour_v1alpha1.ContourConfigurationSpec{
+			Envoy: &contour_v1alpha1.EnvoyConfig{
+				Listener: &contour_v1alpha1.EnvoyListenerConfig{
+					HTTP10DefaultHost: "example.com",
+				},
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Listener.HTTP10DefaultHost = "example.com:8080"
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Listener.HTTP10DefaultHost = "10.0.0.1:80"
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Listener.HTTP10DefaultHost = "example.com:http"
+		require.Error(t, c.Validate())
+
+		c.Envoy.Listener.HTTP10DefaultHost = "not a host"
+		require.Error(t, c.Validate())
+
+		c.Envoy.Listener.HTTP10DefaultHost = "example.com"
+		c.Envoy.Listener.DisableAcceptHTTP10 = ptr.To(true)
+		require.Error(t, c.Validate())
+	})
+
+	t.Run("envoy listener local reply policy validation", func(t *testing.T) {
+		c := contour_v1alpha1.ContourConfigurationSpec{
+			Envoy: &contour_v1alpha1.EnvoyConfig{
+				Listener: &contour_v1alpha1.EnvoyListenerConfig{
+					LocalReplyPolicy: &contour_v1alpha1.LocalReplyPolicy{
+						Format: &contour_v1alpha1.LocalReplyFormat{
+							JSON: map[string]string{
+								"code":  "%RESPONSE_CODE%",
+								"error": "%LOCAL_REPLY_BODY%",
+							},
+						},
+						Overrides: []contour_v1alpha1.LocalReplyOverride{{
+							StatusCode: 503,
+							Format: contour_v1alpha1.LocalReplyFormat{
+								Text:        "unavailable (%RESPONSE_CODE_DETAILS%)",
+								ContentType: "text/plain; charset=utf-8",
+							},
+						}},
+					},
+				},
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Listener.LocalReplyPolicy.Format.Text = "%RESPONSE_CODE%"
+		require.Error(t, c.Validate())
+
+		c.Envoy.Listener.LocalReplyPolicy.Format = &contour_v1alpha1.LocalReplyFormat{}
+		require.Error(t, c.Validate())
+
+		c.Envoy.Listener.LocalReplyPolicy.Format = &contour_v1alpha1.LocalReplyFormat{
+			JSON: map[string]string{"code": "%NOT_AN_OPERATOR%"},
+		}
+		require.Error(t, c.Validate())
+
+		c.Envoy.Listener.LocalReplyPolicy.Format = nil
+		require.NoError(t, c.Validate())
+
+		c.Envoy.Listener.LocalReplyPolicy.Overrides[0].Format.Text = "%REQ%"
+		require.Error(t, c.Validate())
+
+		c.Envoy.Listener.LocalReplyPolicy.Overrides[0].Format.Text = "unavailable"
+		c.Envoy.Listener.LocalReplyPolicy.Overrides = append(c.Envoy.Listener.LocalReplyPolicy.Overrides,
+			contour_v1alpha1.LocalReplyOverride{StatusCode: 503, Format: contour_v1alpha1.LocalReplyFormat{Text: "again"}})
+		require.Error(t, c.Validate())
+
+		c.Envoy.Listener.LocalReplyPolicy.Overrides[1].StatusCode = 600
+		require.Error(t, c.Validate())
+
+		c.Envoy.Listener.LocalReplyPolicy.Overrides[1].StatusCode = 404
+		require.NoError(t, c.Validate())
+	})
+
 	t.Run("gateway validation", func(t *testing.T) {
 		c := contour_v1alpha1.ContourConfigurationSpec{
 			Gateway: &contour_v1alpha1.GatewayConfig{},
@@ -196,6 +630,36 @@ func TestContourConfigurationSpecValidate(t *testing.T) {
 		require.Error(t, c.Validate())
 	})
 
+	t.Run("policy route identifier header validation", func(t *testing.T) {
+		c := contour_v1alpha1.ContourConfigurationSpec{
+			Policy: &contour_v1alpha1.PolicyConfig{
+				RouteIdentifierHeader: "x-contour-route",
+			},
+		}
+		require.NoError(t, c.Validate())
+
+		c.Policy.RouteIdentifierHeader = "inv@lid-header"
+		require.Error(t, c.Validate())
+
+		c.Policy.RouteIdentifierHeader = "Host"
+		require.Error(t, c.Validate())
+	})
+
+	t.Run("global external authorization request body validation", func(t *testing.T) {
+		c := contour_v1alpha1.ContourConfigurationSpec{
+			GlobalExternalAuthorization: &contour_v1.AuthorizationServer{},
+		}
+		require.NoError(t, c.Validate())
+
+		c.GlobalExternalAuthorization.WithRequestBody = &contour_v1.AuthorizationServerBufferSettings{
+			MaxRequestBytes: contour_v1.MaxAuthorizationRequestBytes,
+		}
+		require.NoError(t, c.Validate())
+
+		c.GlobalExternalAuthorization.WithRequestBody.MaxRequestBytes = contour_v1.MaxAuthorizationRequestBytes + 1
+		require.Error(t, c.Validate())
+	})
+
 	t.Run("tracing validation", func(t *testing.T) {
 		c := contour_v1alpha1.ContourConfigurationSpec{
 			Tracing: &contour_v1alpha1.TracingConfig{},
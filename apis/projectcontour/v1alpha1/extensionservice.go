@@ -85,7 +85,7 @@ type ExtensionServiceSpec struct {
 	Protocol *string `json:"protocol,omitempty"`
 
 	// The policy for load balancing GRPC service requests. Note that the
-	// `Cookie` and `RequestHash` load balancing strategies cannot be used
+	// `Cookie`, `RequestHash` and `Maglev` load balancing strategies cannot be used
 	// here.
 	//
 	// +optional
@@ -174,6 +174,11 @@ func (in *ContourConfigurationSpec) DeepCopyInto(out *ContourConfigurationSpec)
 		*out = new(DebugConfig)
 		**out = **in
 	}
+	if in.LeaderElection != nil {
+		in, out := &in.LeaderElection, &out.LeaderElection
+		*out = new(LeaderElectionConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Health != nil {
 		in, out := &in.Health, &out.Health
 		*out = new(HealthConfig)
@@ -557,6 +562,11 @@ func (in *EnvoyConfig) DeepCopyInto(out *EnvoyConfig) {
 		*out = new(NetworkParameters)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.StatsCollector != nil {
+		in, out := &in.StatsCollector, &out.StatsCollector
+		*out = new(EnvoyStatsCollectorConfig)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoyConfig.
@@ -592,6 +602,26 @@ func (in *EnvoyListenerConfig) DeepCopyInto(out *EnvoyListenerConfig) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.ProxyProtocol != nil {
+		in, out := &in.ProxyProtocol, &out.ProxyProtocol
+		*out = new(ProxyProtocolConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTP3 != nil {
+		in, out := &in.HTTP3, &out.HTTP3
+		*out = new(HTTP3Config)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ListenerFiltersTimeout != nil {
+		in, out := &in.ListenerFiltersTimeout, &out.ListenerFiltersTimeout
+		*out = new(string)
+		**out = **in
+	}
+	if in.ContinueOnListenerFiltersTimeout != nil {
+		in, out := &in.ContinueOnListenerFiltersTimeout, &out.ContinueOnListenerFiltersTimeout
+		*out = new(bool)
+		**out = **in
+	}
 	if in.DisableAllowChunkedLength != nil {
 		in, out := &in.DisableAllowChunkedLength, &out.DisableAllowChunkedLength
 		*out = new(bool)
@@ -602,6 +632,36 @@ func (in *EnvoyListenerConfig) DeepCopyInto(out *EnvoyListenerConfig) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.StripPortFromHost != nil {
+		in, out := &in.StripPortFromHost, &out.StripPortFromHost
+		*out = new(bool)
+		**out = **in
+	}
+	if in.StripMatchingHostPort != nil {
+		in, out := &in.StripMatchingHostPort, &out.StripMatchingHostPort
+		*out = new(bool)
+		**out = **in
+	}
+	if in.UseRemoteAddress != nil {
+		in, out := &in.UseRemoteAddress, &out.UseRemoteAddress
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowAbsoluteURL != nil {
+		in, out := &in.AllowAbsoluteURL, &out.AllowAbsoluteURL
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisableAcceptHTTP10 != nil {
+		in, out := &in.DisableAcceptHTTP10, &out.DisableAcceptHTTP10
+		*out = new(bool)
+		**out = **in
+	}
+	if in.LocalReplyPolicy != nil {
+		in, out := &in.LocalReplyPolicy, &out.LocalReplyPolicy
+		*out = new(LocalReplyPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.MaxRequestsPerConnection != nil {
 		in, out := &in.MaxRequestsPerConnection, &out.MaxRequestsPerConnection
 		*out = new(uint32)
@@ -632,6 +692,16 @@ func (in *EnvoyListenerConfig) DeepCopyInto(out *EnvoyListenerConfig) {
 		*out = new(uint32)
 		**out = **in
 	}
+	if in.HTTP2KeepaliveInterval != nil {
+		in, out := &in.HTTP2KeepaliveInterval, &out.HTTP2KeepaliveInterval
+		*out = new(string)
+		**out = **in
+	}
+	if in.HTTP2KeepaliveTimeout != nil {
+		in, out := &in.HTTP2KeepaliveTimeout, &out.HTTP2KeepaliveTimeout
+		*out = new(string)
+		**out = **in
+	}
 	if in.MaxConnectionsPerListener != nil {
 		in, out := &in.MaxConnectionsPerListener, &out.MaxConnectionsPerListener
 		*out = new(uint32)
@@ -726,6 +796,21 @@ func (in *EnvoySettings) DeepCopy() *EnvoySettings {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvoyStatsCollectorConfig) DeepCopyInto(out *EnvoyStatsCollectorConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoyStatsCollectorConfig.
+func (in *EnvoyStatsCollectorConfig) DeepCopy() *EnvoyStatsCollectorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvoyStatsCollectorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvoyTLS) DeepCopyInto(out *EnvoyTLS) {
 	*out = *in
@@ -734,6 +819,11 @@ func (in *EnvoyTLS) DeepCopyInto(out *EnvoyTLS) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.DisableSessionTickets != nil {
+		in, out := &in.DisableSessionTickets, &out.DisableSessionTickets
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoyTLS.
@@ -922,6 +1012,31 @@ func (in *GatewayConfig) DeepCopy() *GatewayConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTP3Config) DeepCopyInto(out *HTTP3Config) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AdvertisedPort != nil {
+		in, out := &in.AdvertisedPort, &out.AdvertisedPort
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTP3Config.
+func (in *HTTP3Config) DeepCopy() *HTTP3Config {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTP3Config)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPProxyConfig) DeepCopyInto(out *HTTPProxyConfig) {
 	*out = *in
@@ -935,6 +1050,11 @@ func (in *HTTPProxyConfig) DeepCopyInto(out *HTTPProxyConfig) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.RootNamespaceSelector != nil {
+		in, out := &in.RootNamespaceSelector, &out.RootNamespaceSelector
+		*out = new(string)
+		**out = **in
+	}
 	if in.FallbackCertificate != nil {
 		in, out := &in.FallbackCertificate, &out.FallbackCertificate
 		*out = new(NamespacedName)
@@ -1014,6 +1134,106 @@ func (in *IngressConfig) DeepCopy() *IngressConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LeaderElectionConfig) DeepCopyInto(out *LeaderElectionConfig) {
+	*out = *in
+	if in.Disable != nil {
+		in, out := &in.Disable, &out.Disable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.LeaseDuration != nil {
+		in, out := &in.LeaseDuration, &out.LeaseDuration
+		*out = new(string)
+		**out = **in
+	}
+	if in.RenewDeadline != nil {
+		in, out := &in.RenewDeadline, &out.RenewDeadline
+		*out = new(string)
+		**out = **in
+	}
+	if in.RetryPeriod != nil {
+		in, out := &in.RetryPeriod, &out.RetryPeriod
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaderElectionConfig.
+func (in *LeaderElectionConfig) DeepCopy() *LeaderElectionConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LeaderElectionConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalReplyFormat) DeepCopyInto(out *LocalReplyFormat) {
+	*out = *in
+	if in.JSON != nil {
+		in, out := &in.JSON, &out.JSON
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalReplyFormat.
+func (in *LocalReplyFormat) DeepCopy() *LocalReplyFormat {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalReplyFormat)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalReplyOverride) DeepCopyInto(out *LocalReplyOverride) {
+	*out = *in
+	in.Format.DeepCopyInto(&out.Format)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalReplyOverride.
+func (in *LocalReplyOverride) DeepCopy() *LocalReplyOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalReplyOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalReplyPolicy) DeepCopyInto(out *LocalReplyPolicy) {
+	*out = *in
+	if in.Format != nil {
+		in, out := &in.Format, &out.Format
+		*out = new(LocalReplyFormat)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]LocalReplyOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalReplyPolicy.
+func (in *LocalReplyPolicy) DeepCopy() *LocalReplyPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalReplyPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetricsConfig) DeepCopyInto(out *MetricsConfig) {
 	*out = *in
@@ -1158,6 +1378,11 @@ func (in *PolicyConfig) DeepCopyInto(out *PolicyConfig) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.ContourVersionHeader != nil {
+		in, out := &in.ContourVersionHeader, &out.ContourVersionHeader
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyConfig.
@@ -1170,6 +1395,31 @@ func (in *PolicyConfig) DeepCopy() *PolicyConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyProtocolConfig) DeepCopyInto(out *ProxyProtocolConfig) {
+	*out = *in
+	if in.AllowRequestsWithoutProxyProtocol != nil {
+		in, out := &in.AllowRequestsWithoutProxyProtocol, &out.AllowRequestsWithoutProxyProtocol
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = make([]ProxyProtocolVersion, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyProtocolConfig.
+func (in *ProxyProtocolConfig) DeepCopy() *ProxyProtocolConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyProtocolConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RateLimitServiceConfig) DeepCopyInto(out *RateLimitServiceConfig) {
 	*out = *in
@@ -1350,6 +1600,26 @@ func (in *XDSServerConfig) DeepCopyInto(out *XDSServerConfig) {
 		*out = new(TLS)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Delta != nil {
+		in, out := &in.Delta, &out.Delta
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ResourceTTL != nil {
+		in, out := &in.ResourceTTL, &out.ResourceTTL
+		*out = new(string)
+		**out = **in
+	}
+	if in.ShutdownDrainTime != nil {
+		in, out := &in.ShutdownDrainTime, &out.ShutdownDrainTime
+		*out = new(string)
+		**out = **in
+	}
+	if in.Keepalive != nil {
+		in, out := &in.Keepalive, &out.Keepalive
+		*out = new(XDSServerKeepalive)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XDSServerConfig.
@@ -1361,3 +1631,38 @@ func (in *XDSServerConfig) DeepCopy() *XDSServerConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *XDSServerKeepalive) DeepCopyInto(out *XDSServerKeepalive) {
+	*out = *in
+	if in.Time != nil {
+		in, out := &in.Time, &out.Time
+		*out = new(string)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(string)
+		**out = **in
+	}
+	if in.MinTime != nil {
+		in, out := &in.MinTime, &out.MinTime
+		*out = new(string)
+		**out = **in
+	}
+	if in.PermitWithoutStream != nil {
+		in, out := &in.PermitWithoutStream, &out.PermitWithoutStream
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XDSServerKeepalive.
+func (in *XDSServerKeepalive) DeepCopy() *XDSServerKeepalive {
+	if in == nil {
+		return nil
+	}
+	out := new(XDSServerKeepalive)
+	in.DeepCopyInto(out)
+	return out
+}
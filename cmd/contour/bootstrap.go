@@ -27,6 +27,7 @@ func registerBootstrap(app *kingpin.Application) (*kingpin.CmdClause, *envoy.Boo
 	bootstrap := app.Command("bootstrap", "Generate bootstrap configuration.")
 	bootstrap.Arg("path", "Configuration file ('-' for standard output).").Required().StringVar(&config.Path)
 
+	bootstrap.Flag("admin-access-log-path", "Path to write the Envoy admin interface access log to.").Default("/dev/null").StringVar(&config.AdminAccessLogPath)
 	bootstrap.Flag("admin-address", "Path to Envoy admin unix domain socket.").Default("/admin/admin.sock").StringVar(&config.AdminAddress)
 	bootstrap.Flag("admin-port", "DEPRECATED: Envoy admin interface port.").IntVar(&config.AdminPort)
 	bootstrap.Flag("dns-lookup-family", "Defines what DNS Resolution Policy to use for Envoy -> Contour cluster name lookup. Either v4, v6, auto, or all.").StringVar(&config.DNSLookupFamily)
@@ -34,9 +35,13 @@ func registerBootstrap(app *kingpin.Application) (*kingpin.CmdClause, *envoy.Boo
 	bootstrap.Flag("envoy-cert-file", "Client certificate filename for Envoy secure xDS gRPC communication.").Envar("ENVOY_CERT_FILE").StringVar(&config.GrpcClientCert)
 	bootstrap.Flag("envoy-key-file", "Client key filename for Envoy secure xDS gRPC communication.").Envar("ENVOY_KEY_FILE").StringVar(&config.GrpcClientKey)
 	bootstrap.Flag("namespace", "The namespace the Envoy container will run in.").Envar("CONTOUR_NAMESPACE").Default("projectcontour").StringVar(&config.Namespace)
+	bootstrap.Flag("overload-cgroup-memory-threshold", "Fraction of the container's memory limit at which overload manager stops accepting new requests. Disabled if 0. Requires Envoy 1.35 or later.").Float64Var(&config.OverloadCgroupMemoryThreshold)
 	bootstrap.Flag("overload-max-heap", "Defines the maximum heap size in bytes until overload manager stops accepting new connections.").Uint64Var(&config.MaximumHeapSizeBytes)
+	bootstrap.Flag("overload-shrink-heap-threshold", "Fraction of the maximum heap size at which overload manager shrinks the heap.").Default("0.95").Float64Var(&config.OverloadShrinkHeapThreshold)
+	bootstrap.Flag("overload-stop-accepting-requests-threshold", "Fraction of the maximum heap size at which overload manager stops accepting new requests.").Default("0.98").Float64Var(&config.OverloadStopAcceptingRequestsThreshold)
 	bootstrap.Flag("resources-dir", "Directory where configuration files will be written to.").StringVar(&config.ResourcesDir)
-	bootstrap.Flag("xds-address", "xDS gRPC API address.").StringVar(&config.XDSAddress)
+	bootstrap.Flag("xds-address", "xDS gRPC API address, or unix:// followed by the path of a Unix domain socket.").StringVar(&config.XDSAddress)
+	bootstrap.Flag("xds-delta", "Use the incremental (delta) xDS protocol to fetch resources from Contour.").BoolVar(&config.XDSDelta)
 	bootstrap.Flag("xds-port", "xDS gRPC API port.").IntVar(&config.XDSGRPCPort)
 	bootstrap.Flag("xds-resource-version", "The versions of the xDS resources to request from Contour.").Default("v3").StringVar((*string)(&config.XDSResourceVersion))
 
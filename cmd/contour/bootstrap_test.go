@@ -0,0 +1,49 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBootstrapAdminAccessLogPath(t *testing.T) {
+	tests := map[string]struct {
+		args []string
+		want string
+	}{
+		"default": {
+			args: []string{"bootstrap", "-"},
+			want: "/dev/null",
+		},
+		"admin access log path set": {
+			args: []string{"bootstrap", "-", "--admin-access-log-path=/var/log/envoy/admin.log"},
+			want: "/var/log/envoy/admin.log",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			app := kingpin.New("contour_bootstrap_test", "")
+			_, config := registerBootstrap(app)
+
+			_, err := app.Parse(tc.args)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, config.GetAdminAccessLogPath())
+		})
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alecthomas/kingpin/v2"
+	core_v1 "k8s.io/api/core/v1"
+	api_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/k8s"
+)
+
+const (
+	certCheckStatusValid    = "Valid"
+	certCheckStatusExpiring = "Expiring"
+	certCheckStatusExpired  = "Expired"
+	certCheckStatusInvalid  = "Invalid"
+
+	certCheckUsageServing  = "serving"
+	certCheckUsageClientCA = "client-ca"
+	certCheckUsageFallback = "fallback"
+)
+
+// certCheckContext holds the parameters for the cert-check subcommand.
+type certCheckContext struct {
+	// kubeconfig is the path to the Kubeconfig file if we're not running in a cluster.
+	kubeconfig string
+
+	// inCluster means that we should assume we are running in a Kubernetes cluster.
+	inCluster bool
+
+	// namespace restricts the HTTPProxies checked to a single namespace.
+	// All namespaces are checked if it is empty.
+	namespace string
+
+	// fallbackCertificate is the namespace/name of the fallback
+	// certificate Secret configured for Contour, if any.
+	fallbackCertificate string
+
+	// expiryWindow is how far ahead of a certificate's expiry it is
+	// reported as expiring.
+	expiryWindow time.Duration
+
+	// format is the output format, either "table" or "json".
+	format string
+
+	// out is where the results are written.
+	out io.Writer
+}
+
+// certCheckResult is the outcome of checking one Secret
+// referenced by an HTTPProxy.
+type certCheckResult struct {
+	HTTPProxy string     `json:"httpproxy"`
+	Usage     string     `json:"usage"`
+	Secret    string     `json:"secret,omitempty"`
+	Status    string     `json:"status"`
+	NotAfter  *time.Time `json:"notAfter,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// registerCertCheck registers the cert-check subcommand and flags
+// with the Application provided.
+func registerCertCheck(app *kingpin.Application) (*kingpin.CmdClause, *certCheckContext) {
+	ctx := &certCheckContext{
+		out: os.Stdout,
+	}
+
+	certCheck := app.Command("cert-check", "Check the TLS Secrets referenced by HTTPProxies for validity and expiry.")
+	certCheck.Flag("expiry-window", "Report certificates expiring within this duration as failures.").Default("0s").DurationVar(&ctx.expiryWindow)
+	certCheck.Flag("fallback-certificate", "Namespace/name of the fallback certificate Secret configured for Contour.").StringVar(&ctx.fallbackCertificate)
+	certCheck.Flag("format", "Output format. Either table or json.").Default("table").EnumVar(&ctx.format, "table", "json")
+	certCheck.Flag("incluster", "Use in cluster configuration.").BoolVar(&ctx.inCluster)
+	certCheck.Flag("kubeconfig", "Path to kubeconfig (if not in running inside a cluster).").Default(filepath.Join(os.Getenv("HOME"), ".kube", "config")).StringVar(&ctx.kubeconfig)
+	certCheck.Flag("namespace", "Only check HTTPProxies in this namespace (default all namespaces).").StringVar(&ctx.namespace)
+
+	return certCheck, ctx
+}
+
+// doCertCheck checks the TLS Secrets referenced by the HTTPProxies in the
+// cluster and writes the results. It returns false if any certificate is
+// invalid, expired or expires within the configured window.
+func doCertCheck(ctx *certCheckContext) (bool, error) {
+	var fallbackCertificate *types.NamespacedName
+	if ctx.fallbackCertificate != "" {
+		name := k8s.NamespacedNameFrom(ctx.fallbackCertificate)
+		if name.Namespace == "" || name.Name == "" {
+			return false, fmt.Errorf("invalid fallback certificate %q: must be namespace/name", ctx.fallbackCertificate)
+		}
+		fallbackCertificate = &name
+	}
+
+	restConfig, err := k8s.NewRestConfig(ctx.kubeconfig, ctx.inCluster)
+	if err != nil {
+		return false, err
+	}
+
+	scheme, err := k8s.NewContourScheme()
+	if err != nil {
+		return false, err
+	}
+
+	cli, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return false, err
+	}
+
+	results, err := checkCertificates(context.Background(), cli, ctx.namespace, fallbackCertificate, time.Now(), ctx.expiryWindow)
+	if err != nil {
+		return false, err
+	}
+
+	if err := writeCertCheckResults(ctx.out, ctx.format, results); err != nil {
+		return false, err
+	}
+
+	for _, result := range results {
+		if result.Status != certCheckStatusValid {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// checkCertificates lists the HTTPProxies in the given namespace, or all
+// namespaces if it is empty, and checks each referenced serving, client CA
+// and fallback certificate Secret. Secrets in another namespace must be
+// delegated to the HTTPProxy's namespace by a TLSCertificateDelegation.
+func checkCertificates(ctx context.Context, cli client.Reader, namespace string, fallbackCertificate *types.NamespacedName, now time.Time, window time.Duration) ([]certCheckResult, error) {
+	var proxies contour_v1.HTTPProxyList
+	if err := cli.List(ctx, &proxies, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list HTTPProxies: %w", err)
+	}
+
+	var delegations contour_v1.TLSCertificateDelegationList
+	if err := cli.List(ctx, &delegations); err != nil {
+		return nil, fmt.Errorf("failed to list TLSCertificateDelegations: %w", err)
+	}
+
+	delegationPermitted := func(secretName types.NamespacedName, targetNamespace string) bool {
+		if secretName.Namespace == targetNamespace {
+			return true
+		}
+		for i := range delegations.Items {
+			if dag.CertificateDelegated(&delegations.Items[i], secretName, targetNamespace) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// check validates a single Secret referenced by proxy and
+	// classifies it by its expiry time.
+	check := func(proxy *contour_v1.HTTPProxy, usage string, secretName types.NamespacedName, validate func(*core_v1.Secret) (time.Time, error)) (certCheckResult, error) {
+		result := certCheckResult{
+			HTTPProxy: k8s.NamespacedNameOf(proxy).String(),
+			Usage:     usage,
+			Secret:    secretName.String(),
+		}
+
+		if !delegationPermitted(secretName, proxy.Namespace) {
+			result.Status = certCheckStatusInvalid
+			result.Error = "certificate delegation not permitted"
+			return result, nil
+		}
+
+		var secret core_v1.Secret
+		if err := cli.Get(ctx, secretName, &secret); err != nil {
+			if !api_errors.IsNotFound(err) {
+				return result, fmt.Errorf("failed to get Secret %s: %w", secretName, err)
+			}
+
+			result.Status = certCheckStatusInvalid
+			result.Error = "secret not found"
+			return result, nil
+		}
+
+		notAfter, err := validate(&secret)
+		switch {
+		case err != nil:
+			result.Status = certCheckStatusInvalid
+			result.Error = err.Error()
+		case !now.Before(notAfter):
+			result.Status = certCheckStatusExpired
+			result.NotAfter = &notAfter
+		case now.Add(window).After(notAfter):
+			result.Status = certCheckStatusExpiring
+			result.NotAfter = &notAfter
+		default:
+			result.Status = certCheckStatusValid
+			result.NotAfter = &notAfter
+		}
+
+		return result, nil
+	}
+
+	var results []certCheckResult
+
+	for i := range proxies.Items {
+		proxy := &proxies.Items[i]
+
+		if proxy.Spec.VirtualHost == nil || proxy.Spec.VirtualHost.TLS == nil {
+			continue
+		}
+		tls := proxy.Spec.VirtualHost.TLS
+
+		if tls.SecretName != "" {
+			secretName := k8s.NamespacedNameFrom(tls.SecretName, k8s.DefaultNamespace(proxy.Namespace))
+			result, err := check(proxy, certCheckUsageServing, secretName, dag.CheckTLSSecret)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, result)
+		}
+
+		if tls.ClientValidation != nil && tls.ClientValidation.CACertificate != "" {
+			secretName := k8s.NamespacedNameFrom(tls.ClientValidation.CACertificate, k8s.DefaultNamespace(proxy.Namespace))
+			result, err := check(proxy, certCheckUsageClientCA, secretName, dag.CheckCASecret)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, result)
+		}
+
+		if tls.EnableFallbackCertificate {
+			if fallbackCertificate == nil {
+				results = append(results, certCheckResult{
+					HTTPProxy: k8s.NamespacedNameOf(proxy).String(),
+					Usage:     certCheckUsageFallback,
+					Status:    certCheckStatusInvalid,
+					Error:     "fallback certificate is not configured",
+				})
+				continue
+			}
+
+			result, err := check(proxy, certCheckUsageFallback, *fallbackCertificate, dag.CheckTLSSecret)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, result)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].HTTPProxy < results[j].HTTPProxy
+	})
+
+	return results, nil
+}
+
+// writeCertCheckResults writes the results in the given format.
+func writeCertCheckResults(w io.Writer, format string, results []certCheckResult) error {
+	switch format {
+	case "json":
+		if results == nil {
+			results = []certCheckResult{}
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(tw, "HTTPPROXY\tUSAGE\tSECRET\tSTATUS\tNOT AFTER\tERROR")
+		for _, result := range results {
+			notAfter := "-"
+			if result.NotAfter != nil {
+				notAfter = result.NotAfter.UTC().Format(time.RFC3339)
+			}
+			secret := result.Secret
+			if secret == "" {
+				secret = "-"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", result.HTTPProxy, result.Usage, secret, result.Status, notAfter, result.Error)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unsupported output format %q", format)
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsaarni/certyaml"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/k8s"
+)
+
+func TestCheckCertificates(t *testing.T) {
+	now := time.Now()
+
+	expiredNotBefore := now.Add(-48 * time.Hour)
+	expiredNotAfter := now.Add(-24 * time.Hour)
+	expiringNotAfter := now.Add(24 * time.Hour)
+
+	ca := certyaml.Certificate{Subject: "CN=ca"}
+	valid := certyaml.Certificate{Subject: "CN=valid.example.com"}
+	expired := certyaml.Certificate{Subject: "CN=expired.example.com", NotBefore: &expiredNotBefore, NotAfter: &expiredNotAfter}
+	expiring := certyaml.Certificate{Subject: "CN=expiring.example.com", NotAfter: &expiringNotAfter}
+
+	tlsSecret := func(namespace, name string, cert *certyaml.Certificate) *core_v1.Secret {
+		certPEM, keyPEM, err := cert.PEM()
+		require.NoError(t, err)
+
+		return &core_v1.Secret{
+			ObjectMeta: meta_v1.ObjectMeta{Namespace: namespace, Name: name},
+			Type:       core_v1.SecretTypeTLS,
+			Data: map[string][]byte{
+				core_v1.TLSCertKey:       certPEM,
+				core_v1.TLSPrivateKeyKey: keyPEM,
+			},
+		}
+	}
+
+	proxy := func(namespace, name, secretName string) *contour_v1.HTTPProxy {
+		p := &contour_v1.HTTPProxy{
+			ObjectMeta: meta_v1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: contour_v1.HTTPProxySpec{
+				VirtualHost: &contour_v1.VirtualHost{Fqdn: name + ".example.com"},
+			},
+		}
+		if secretName != "" {
+			p.Spec.VirtualHost.TLS = &contour_v1.TLS{SecretName: secretName}
+		}
+		return p
+	}
+
+	validCert, err := valid.X509Certificate()
+	require.NoError(t, err)
+
+	invalidSecret := &core_v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "invalid"},
+		Type:       core_v1.SecretTypeTLS,
+		Data: map[string][]byte{
+			core_v1.TLSCertKey: valid.CertPEM(),
+		},
+	}
+
+	_, expiredKeyPEM, err := expired.PEM()
+	require.NoError(t, err)
+	mismatchedSecret := &core_v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "mismatched"},
+		Type:       core_v1.SecretTypeTLS,
+		Data: map[string][]byte{
+			core_v1.TLSCertKey:       valid.CertPEM(),
+			core_v1.TLSPrivateKeyKey: expiredKeyPEM,
+		},
+	}
+
+	caSecret := &core_v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "ca"},
+		Type:       core_v1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			dag.CACertificateKey: ca.CertPEM(),
+		},
+	}
+
+	clientValidation := proxy("default", "i-client-ca", "valid")
+	clientValidation.Spec.VirtualHost.TLS.ClientValidation = &contour_v1.DownstreamValidation{CACertificate: "ca"}
+
+	fallback := proxy("default", "j-fallback", "valid")
+	fallback.Spec.VirtualHost.TLS.EnableFallbackCertificate = true
+
+	scheme, err := k8s.NewContourScheme()
+	require.NoError(t, err)
+
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		tlsSecret("default", "valid", &valid),
+		tlsSecret("default", "expired", &expired),
+		tlsSecret("default", "expiring", &expiring),
+		tlsSecret("certs", "delegated", &valid),
+		tlsSecret("certs", "fallback", &valid),
+		invalidSecret,
+		mismatchedSecret,
+		caSecret,
+		&contour_v1.TLSCertificateDelegation{
+			ObjectMeta: meta_v1.ObjectMeta{Namespace: "certs", Name: "delegation"},
+			Spec: contour_v1.TLSCertificateDelegationSpec{
+				Delegations: []contour_v1.CertificateDelegation{{
+					SecretName:       "delegated",
+					TargetNamespaces: []string{"delegated"},
+				}, {
+					SecretName:       "fallback",
+					TargetNamespaces: []string{"*"},
+				}},
+			},
+		},
+		proxy("default", "a-valid", "valid"),
+		proxy("default", "b-expired", "expired"),
+		proxy("default", "c-expiring", "expiring"),
+		proxy("default", "d-invalid", "invalid"),
+		proxy("default", "e-missing", "missing"),
+		proxy("default", "f-insecure", ""),
+		proxy("other", "g-not-delegated", "certs/delegated"),
+		proxy("delegated", "h-delegated", "certs/delegated"),
+		clientValidation,
+		fallback,
+		proxy("default", "k-mismatched", "mismatched"),
+	).Build()
+
+	fallbackCertificate := &types.NamespacedName{Namespace: "certs", Name: "fallback"}
+	results, err := checkCertificates(context.Background(), cli, "", fallbackCertificate, now, 72*time.Hour)
+	require.NoError(t, err)
+
+	statuses := map[string]string{}
+	errs := map[string]string{}
+	for _, result := range results {
+		key := result.HTTPProxy + " " + result.Usage
+		statuses[key] = result.Status
+		if result.Error != "" {
+			errs[key] = result.Error
+		}
+	}
+
+	assert.Equal(t, map[string]string{
+		"default/a-valid serving":       certCheckStatusValid,
+		"default/b-expired serving":     certCheckStatusExpired,
+		"default/c-expiring serving":    certCheckStatusExpiring,
+		"default/d-invalid serving":     certCheckStatusInvalid,
+		"default/e-missing serving":     certCheckStatusInvalid,
+		"other/g-not-delegated serving": certCheckStatusInvalid,
+		"delegated/h-delegated serving": certCheckStatusValid,
+		"default/i-client-ca serving":   certCheckStatusValid,
+		"default/i-client-ca client-ca": certCheckStatusValid,
+		"default/j-fallback serving":    certCheckStatusValid,
+		"default/j-fallback fallback":   certCheckStatusValid,
+		"default/k-mismatched serving":  certCheckStatusInvalid,
+	}, statuses)
+
+	assert.Equal(t, "missing TLS private key", errs["default/d-invalid serving"])
+	assert.Equal(t, "secret not found", errs["default/e-missing serving"])
+	assert.Equal(t, "certificate delegation not permitted", errs["other/g-not-delegated serving"])
+	assert.Contains(t, errs["default/k-mismatched serving"], "invalid TLS key pair")
+
+	assert.Equal(t, "default/valid", results[0].Secret)
+	assert.Equal(t, validCert.NotAfter, *results[0].NotAfter)
+
+	// Without a window the expiring certificate is still valid, and
+	// without a configured fallback certificate the fallback is invalid.
+	results, err = checkCertificates(context.Background(), cli, "default", nil, now, 0)
+	require.NoError(t, err)
+	require.Len(t, results, 10)
+	assert.Equal(t, certCheckStatusValid, results[2].Status)
+	assert.Equal(t, certCheckResult{
+		HTTPProxy: "default/j-fallback",
+		Usage:     certCheckUsageFallback,
+		Status:    certCheckStatusInvalid,
+		Error:     "fallback certificate is not configured",
+	}, results[8])
+}
+
+func TestWriteCertCheckResults(t *testing.T) {
+	notAfter := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	results := []certCheckResult{{
+		HTTPProxy: "default/valid",
+		Usage:     certCheckUsageServing,
+		Secret:    "default/valid",
+		Status:    certCheckStatusValid,
+		NotAfter:  &notAfter,
+	}, {
+		HTTPProxy: "default/missing",
+		Usage:     certCheckUsageServing,
+		Secret:    "default/missing",
+		Status:    certCheckStatusInvalid,
+		Error:     "secret not found",
+	}}
+
+	var table bytes.Buffer
+	require.NoError(t, writeCertCheckResults(&table, "table", results))
+
+	lines := strings.Split(strings.TrimSpace(table.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, []string{"HTTPPROXY", "USAGE", "SECRET", "STATUS", "NOT", "AFTER", "ERROR"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"default/valid", "serving", "default/valid", "Valid", "2030-01-01T00:00:00Z"}, strings.Fields(lines[1]))
+	assert.Equal(t, []string{"default/missing", "serving", "default/missing", "Invalid", "-", "secret", "not", "found"}, strings.Fields(lines[2]))
+
+	var out bytes.Buffer
+	require.NoError(t, writeCertCheckResults(&out, "json", results))
+
+	var got []certCheckResult
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	assert.Equal(t, results, got)
+
+	out.Reset()
+	require.NoError(t, writeCertCheckResults(&out, "json", nil))
+	assert.JSONEq(t, "[]", out.String())
+
+	assert.Error(t, writeCertCheckResults(&out, "xml", results))
+}
@@ -0,0 +1,148 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
+	"github.com/projectcontour/contour/internal/k8s"
+	"github.com/projectcontour/contour/pkg/config"
+)
+
+// configDumpContext holds the parameters for the config-dump subcommand.
+type configDumpContext struct {
+	// serveCtx holds the configuration files, ContourConfiguration name
+	// and flags, which config-dump accepts exactly as serve does.
+	serveCtx *serveContext
+
+	// newReader returns the client used to read the ContourConfiguration
+	// named by --contour-config-name.
+	newReader func() (client.Reader, error)
+
+	// out is where the effective configuration is written.
+	out io.Writer
+
+	logrus.FieldLogger
+}
+
+// registerConfigDump registers the config-dump subcommand and flags
+// with the Application provided. It accepts the same flags as the
+// serve subcommand.
+func registerConfigDump(app *kingpin.Application, log logrus.FieldLogger) (*kingpin.CmdClause, *configDumpContext) {
+	ctx := &configDumpContext{
+		out:         os.Stdout,
+		FieldLogger: log.WithField("context", "config-dump"),
+	}
+
+	configDump := app.Command("config-dump", "Print the effective ContourConfiguration as YAML.")
+	ctx.serveCtx = registerServeFlags(configDump)
+
+	ctx.newReader = func() (client.Reader, error) {
+		restConfig, err := ctx.serveCtx.restConfig(ctx.FieldLogger)
+		if err != nil {
+			return nil, err
+		}
+
+		scheme, err := k8s.NewContourScheme()
+		if err != nil {
+			return nil, fmt.Errorf("unable to create scheme: %w", err)
+		}
+
+		return client.New(restConfig, client.Options{Scheme: scheme})
+	}
+
+	return configDump, ctx
+}
+
+// doConfigDump loads the configuration the same way the serve subcommand
+// does, from the named ContourConfiguration or from the configuration
+// files with command-line flags applied on top, overlays it on the Contour
+// defaults and writes the result to the configured output.
+func doConfigDump(ctx *configDumpContext) error {
+	// Validate the result of applying the command-line
+	// flags on top of the config file.
+	if err := ctx.serveCtx.Config.Validate(); err != nil {
+		return fmt.Errorf("invalid Contour configuration: %w", err)
+	}
+
+	var reader client.Reader
+	var deprecated []string
+	if len(ctx.serveCtx.contourConfigurationName) > 0 {
+		var err error
+		if reader, err = ctx.newReader(); err != nil {
+			return err
+		}
+	} else {
+		deprecated = deprecatedParameters(&ctx.serveCtx.Config)
+	}
+
+	userConfig, err := ctx.serveCtx.userConfig(reader)
+	if err != nil {
+		return err
+	}
+
+	if reader != nil {
+		deprecated = deprecatedFields(&userConfig)
+	}
+
+	for _, field := range deprecated {
+		ctx.WithField("field", field).Warn("deprecated configuration field in use")
+	}
+
+	contourConfiguration, err := effectiveConfig(userConfig)
+	if err != nil {
+		return err
+	}
+
+	out, err := yaml.Marshal(contourConfiguration)
+	if err != nil {
+		return err
+	}
+
+	_, err = ctx.out.Write(out)
+	return err
+}
+
+// deprecatedFields returns the paths of the deprecated fields
+// that are set in the given ContourConfigurationSpec.
+func deprecatedFields(spec *contour_v1alpha1.ContourConfigurationSpec) []string {
+	var fields []string
+
+	if spec.XDSServer != nil && spec.XDSServer.Type != "" {
+		fields = append(fields, "xdsServer.type")
+	}
+
+	return fields
+}
+
+// deprecatedParameters returns the keys of the deprecated configuration
+// file fields that are in use in the given Parameters. The file defaults
+// xds-server-type to envoy, so it is only flagged for the contour server.
+func deprecatedParameters(params *config.Parameters) []string {
+	var fields []string
+
+	if params.Server.XDSServerType == config.ContourServerType {
+		fields = append(fields, "server.xds-server-type")
+	}
+
+	return fields
+}
@@ -0,0 +1,179 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/sirupsen/logrus"
+	logrus_test "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
+	"github.com/projectcontour/contour/internal/contourconfig"
+	"github.com/projectcontour/contour/internal/k8s"
+)
+
+// parseConfigDump parses args for the config-dump subcommand twice,
+// as main does, so flags are applied on top of the config file.
+func parseConfigDump(t *testing.T, log logrus.FieldLogger, args ...string) *configDumpContext {
+	t.Helper()
+
+	app := kingpin.New("contour", "")
+	configDump, ctx := registerConfigDump(app, log)
+
+	args = append([]string{"config-dump"}, args...)
+	cmd, err := app.Parse(args)
+	require.NoError(t, err)
+	assert.Equal(t, configDump.FullCommand(), cmd)
+
+	_, err = app.Parse(args)
+	require.NoError(t, err)
+
+	return ctx
+}
+
+func TestConfigDump(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "contour.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+server:
+  xds-server-type: contour
+disablePermitInsecure: true
+accesslog-format: json
+timeouts:
+  request-timeout: 30s
+`), 0o600))
+
+	log, hook := logrus_test.NewNullLogger()
+	ctx := parseConfigDump(t, log, "-c", configFile, "--accesslog-format=envoy", "--envoy-service-name=my-envoy")
+
+	var out bytes.Buffer
+	ctx.out = &out
+	require.NoError(t, doConfigDump(ctx))
+
+	var got contour_v1alpha1.ContourConfigurationSpec
+	require.NoError(t, yaml.UnmarshalStrict(out.Bytes(), &got))
+
+	// Values from the config file are applied.
+	assert.Equal(t, contour_v1alpha1.ContourServerType, got.XDSServer.Type)
+	assert.True(t, *got.HTTPProxy.DisablePermitInsecure)
+	assert.Equal(t, "30s", *got.Envoy.Timeouts.RequestTimeout)
+
+	// Flags override the config file.
+	assert.Equal(t, contour_v1alpha1.EnvoyAccessLog, got.Envoy.Logging.AccessLogFormat)
+	assert.Equal(t, "my-envoy", got.Envoy.Service.Name)
+
+	// Everything else is defaulted.
+	defaults := contourconfig.Defaults()
+	assert.Equal(t, defaults.Envoy.HTTPListener, got.Envoy.HTTPListener)
+	assert.Equal(t, defaults.Envoy.HTTPSListener, got.Envoy.HTTPSListener)
+	assert.Equal(t, defaults.Health, got.Health)
+	assert.Equal(t, defaults.HTTPProxy.RootNamespaces, got.HTTPProxy.RootNamespaces)
+
+	// The deprecated xDS server type is flagged.
+	require.Len(t, hook.AllEntries(), 1)
+	assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+	assert.Equal(t, "server.xds-server-type", hook.LastEntry().Data["field"])
+}
+
+func TestConfigDumpContourConfiguration(t *testing.T) {
+	t.Setenv("CONTOUR_NAMESPACE", "projectcontour")
+
+	scheme, err := k8s.NewContourScheme()
+	require.NoError(t, err)
+
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&contour_v1alpha1.ContourConfiguration{
+			ObjectMeta: meta_v1.ObjectMeta{Namespace: "projectcontour", Name: "contour"},
+			Spec: contour_v1alpha1.ContourConfigurationSpec{
+				XDSServer: &contour_v1alpha1.XDSServerConfig{
+					Type: contour_v1alpha1.EnvoyServerType,
+				},
+				HTTPProxy: &contour_v1alpha1.HTTPProxyConfig{
+					DisablePermitInsecure: ptr.To(true),
+				},
+			},
+		},
+	).Build()
+
+	log, hook := logrus_test.NewNullLogger()
+	ctx := parseConfigDump(t, log, "--contour-config-name=contour")
+	ctx.newReader = func() (client.Reader, error) {
+		return reader, nil
+	}
+
+	var out bytes.Buffer
+	ctx.out = &out
+	require.NoError(t, doConfigDump(ctx))
+
+	var got contour_v1alpha1.ContourConfigurationSpec
+	require.NoError(t, yaml.UnmarshalStrict(out.Bytes(), &got))
+
+	// Values from the ContourConfiguration are applied.
+	assert.True(t, *got.HTTPProxy.DisablePermitInsecure)
+
+	// Everything else is defaulted.
+	defaults := contourconfig.Defaults()
+	assert.Equal(t, defaults.Envoy.HTTPListener, got.Envoy.HTTPListener)
+	assert.Equal(t, defaults.Health, got.Health)
+
+	// Setting the deprecated xDS server type at all is flagged.
+	require.Len(t, hook.AllEntries(), 1)
+	assert.Equal(t, "xdsServer.type", hook.LastEntry().Data["field"])
+
+	// A missing ContourConfiguration is an error.
+	ctx = parseConfigDump(t, log, "--contour-config-name=missing")
+	ctx.newReader = func() (client.Reader, error) {
+		return reader, nil
+	}
+	assert.Error(t, doConfigDump(ctx))
+}
+
+func TestConfigDumpNoConfigFile(t *testing.T) {
+	log, hook := logrus_test.NewNullLogger()
+	ctx := parseConfigDump(t, log)
+
+	var out bytes.Buffer
+	ctx.out = &out
+	require.NoError(t, doConfigDump(ctx))
+
+	var got contour_v1alpha1.ContourConfigurationSpec
+	require.NoError(t, yaml.UnmarshalStrict(out.Bytes(), &got))
+
+	assert.Equal(t, contour_v1alpha1.EnvoyServerType, got.XDSServer.Type)
+	assert.Empty(t, hook.AllEntries())
+}
+
+func TestConfigDumpInvalidConfigFile(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "contour.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+accesslog-format: xml
+`), 0o600))
+
+	app := kingpin.New("contour", "")
+	registerConfigDump(app, logrus.StandardLogger())
+
+	_, err := app.Parse([]string{"config-dump", "-c", configFile})
+	assert.Error(t, err)
+}
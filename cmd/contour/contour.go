@@ -21,6 +21,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"go.uber.org/automaxprocs/maxprocs"
 
+	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 	"github.com/projectcontour/contour/internal/build"
 	"github.com/projectcontour/contour/internal/envoy"
 	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
@@ -48,10 +49,14 @@ func main() {
 
 	bootstrap, bootstrapCtx := registerBootstrap(app)
 
+	certCheck, certCheckCtx := registerCertCheck(app)
+
 	certgenApp, certgenConfig := registerCertGen(app)
 
 	cli, client := registerCli(app, log)
 
+	configDump, configDumpCtx := registerConfigDump(app, log)
+
 	var resources []string
 	cds := cli.Command("cds", "Watch services.")
 	cds.Arg("resources", "CDS resource filter").StringsVar(&resources)
@@ -96,6 +101,9 @@ func main() {
 	case sdm.FullCommand():
 		doShutdownManager(shutdownManagerCtx)
 	case sdmShutdown.FullCommand():
+		if err := sdmShutdownCtx.validate(); err != nil {
+			log.WithField("flag", "--drain-time").WithError(err).Fatal("failed to parse shutdown args")
+		}
 		sdmShutdownCtx.shutdownHandler()
 	case bootstrap.FullCommand():
 		if err := bootstrapCtx.XDSResourceVersion.Validate(); err != nil {
@@ -104,11 +112,40 @@ func main() {
 		if err := envoy.ValidAdminAddress(bootstrapCtx.AdminAddress); err != nil {
 			log.WithField("flag", "--admin-address").WithError(err).Fatal("failed to parse bootstrap args")
 		}
+		if err := contour_v1alpha1.ValidateXDSAddress(bootstrapCtx.XDSAddress); err != nil {
+			log.WithField("flag", "--xds-address").WithError(err).Fatal("failed to parse bootstrap args")
+		}
+		if err := envoy.ValidAdminAccessLogPath(bootstrapCtx.AdminAccessLogPath); err != nil {
+			log.WithField("flag", "--admin-access-log-path").WithError(err).Fatal("failed to parse bootstrap args")
+		}
+		if err := envoy.ValidOverloadThresholds(bootstrapCtx.OverloadShrinkHeapThreshold, bootstrapCtx.OverloadStopAcceptingRequestsThreshold); err != nil {
+			log.WithError(err).Fatal("failed to parse bootstrap args")
+		}
+		if err := envoy.ValidOverloadCgroupMemoryThreshold(bootstrapCtx.OverloadCgroupMemoryThreshold); err != nil {
+			log.WithField("flag", "--overload-cgroup-memory-threshold").WithError(err).Fatal("failed to parse bootstrap args")
+		}
 		if err := envoy_v3.WriteBootstrap(bootstrapCtx); err != nil {
 			log.WithError(err).Fatal("failed to write bootstrap configuration")
 		}
+	case certCheck.FullCommand():
+		ok, err := doCertCheck(certCheckCtx)
+		if err != nil {
+			log.WithError(err).Fatal("failed to check certificates")
+		}
+		if !ok {
+			os.Exit(1)
+		}
 	case certgenApp.FullCommand():
 		doCertgen(certgenConfig, log)
+	case configDump.FullCommand():
+		// Parse args a second time so cli flags are applied
+		// on top of any values sourced from -c's config file,
+		// as for serve.
+		kingpin.MustParse(app.Parse(args))
+
+		if err := doConfigDump(configDumpCtx); err != nil {
+			log.WithError(err).Fatal("failed to dump configuration")
+		}
 	case cds.FullCommand():
 		if client.Delta {
 			stream := client.DeltaClusterStream()
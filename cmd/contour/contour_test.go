@@ -38,12 +38,18 @@ func TestOptionFlagsAreSorted(t *testing.T) {
 	bootstrap, _ := registerBootstrap(app)
 	assertOptionFlagsAreSorted(t, bootstrap)
 
+	certCheck, _ := registerCertCheck(app)
+	assertOptionFlagsAreSorted(t, certCheck)
+
 	certgen, _ := registerCertGen(app)
 	assertOptionFlagsAreSorted(t, certgen)
 
 	cli, _ := registerCli(app, log)
 	assertOptionFlagsAreSorted(t, cli)
 
+	configDump, _ := registerConfigDump(app, log)
+	assertOptionFlagsAreSorted(t, configDump)
+
 	envoyCmd := app.Command("envoy", "Sub-command for envoy actions.")
 
 	sdmShutdown, _ := registerShutdown(envoyCmd, log)
@@ -21,6 +21,7 @@ import (
 	"github.com/sirupsen/logrus"
 	core_v1 "k8s.io/api/core/v1"
 	networking_v1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -54,6 +55,7 @@ type loadBalancerStatusWriter struct {
 	lbStatus          chan core_v1.LoadBalancerStatus
 	statusUpdater     k8s.StatusUpdater
 	ingressClassNames []string
+	labelSelector     labels.Selector
 	gatewayRef        *types.NamespacedName
 }
 
@@ -74,6 +76,7 @@ func (isw *loadBalancerStatusWriter) Start(ctx context.Context) error {
 		}(),
 		Cache:             isw.cache,
 		IngressClassNames: isw.ingressClassNames,
+		LabelSelector:     isw.labelSelector,
 		GatewayRef:        isw.gatewayRef,
 		StatusUpdater:     isw.statusUpdater,
 	}
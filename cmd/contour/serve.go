@@ -16,10 +16,12 @@ package main
 import (
 	"context"
 	"fmt"
-	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/alecthomas/kingpin/v2"
@@ -29,17 +31,19 @@ import (
 	core_v1 "k8s.io/api/core/v1"
 	discovery_v1 "k8s.io/api/discovery/v1"
 	networking_v1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
+	typed_core_v1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
 	ctrl_cache "sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
-	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 	controller_runtime_metrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	controller_runtime_metrics_server "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	gatewayapi_v1 "sigs.k8s.io/gateway-api/apis/v1"
@@ -60,6 +64,7 @@ import (
 	"github.com/projectcontour/contour/internal/k8s"
 	"github.com/projectcontour/contour/internal/leadership"
 	"github.com/projectcontour/contour/internal/metrics"
+	"github.com/projectcontour/contour/internal/status"
 	"github.com/projectcontour/contour/internal/timeout"
 	"github.com/projectcontour/contour/internal/xds"
 	contour_xds_v3 "github.com/projectcontour/contour/internal/xds/v3"
@@ -77,6 +82,13 @@ const (
 func registerServe(app *kingpin.Application) (*kingpin.CmdClause, *serveContext) {
 	serve := app.Command("serve", "Serve xDS API traffic.")
 
+	return serve, registerServeFlags(serve)
+}
+
+// registerServeFlags registers the flags of the serve subcommand on cmd
+// and returns the serveContext they are parsed into. It is shared with
+// the config-dump subcommand so both load the configuration the same way.
+func registerServeFlags(serve *kingpin.CmdClause) *serveContext {
 	// The precedence of configuration for contour serve is as follows:
 	// If ContourConfiguration resource is specified, it takes precedence,
 	// otherwise config file, overridden by env vars, overridden by cli flags.
@@ -86,29 +98,23 @@ func registerServe(app *kingpin.Application) (*kingpin.CmdClause, *serveContext)
 	// parse our action will return early, resulting in the precedence order
 	// we want.
 	var (
-		configFile string
-		parsed     bool
+		configFiles []string
+		parsed      bool
 	)
 	ctx := newServeContext()
 
 	parseConfig := func(_ *kingpin.ParseContext) error {
-		if ctx.contourConfigurationName != "" && configFile != "" {
+		if ctx.contourConfigurationName != "" && len(configFiles) > 0 {
 			return fmt.Errorf("cannot specify both %s and %s", "--contour-config", "-c/--config-path")
 		}
 
-		if parsed || configFile == "" {
+		if parsed || len(configFiles) == 0 {
 			// if there is no config file supplied, or we've
 			// already parsed it, return immediately.
 			return nil
 		}
 
-		f, err := os.Open(configFile)
-		if err != nil {
-			return err
-		}
-		defer f.Close()
-
-		params, err := config.Parse(f)
+		params, err := config.ParseFiles(configFiles...)
 		if err != nil {
 			return err
 		}
@@ -125,7 +131,7 @@ func registerServe(app *kingpin.Application) (*kingpin.CmdClause, *serveContext)
 	}
 	serve.Flag("accesslog-format", "Format for Envoy access logs.").PlaceHolder("<envoy|json>").StringVar((*string)(&ctx.Config.AccessLogFormat))
 
-	serve.Flag("config-path", "Path to base configuration.").Short('c').PlaceHolder("/path/to/file").Action(parseConfig).ExistingFileVar(&configFile)
+	serve.Flag("config-path", "Path to base configuration. May be repeated, or name a directory, to merge several files in order.").Short('c').PlaceHolder("/path/to/file").Action(parseConfig).ExistingFilesOrDirsVar(&configFiles)
 	serve.Flag("contour-cafile", "CA bundle file name for serving gRPC with TLS.").Envar("CONTOUR_CAFILE").StringVar(&ctx.caFile)
 	serve.Flag("contour-cert-file", "Contour certificate file name for serving gRPC over TLS.").PlaceHolder("/path/to/file").Envar("CONTOUR_CERT_FILE").StringVar(&ctx.contourCert)
 	serve.Flag("contour-config-name", "Name of ContourConfiguration CRD.").PlaceHolder("contour").Action(parseConfig).StringVar(&ctx.contourConfigurationName)
@@ -134,6 +140,7 @@ func registerServe(app *kingpin.Application) (*kingpin.CmdClause, *serveContext)
 	serve.Flag("debug", "Enable debug logging.").Short('d').BoolVar(&ctx.Config.Debug)
 	serve.Flag("debug-http-address", "Address the debug http endpoint will bind to.").PlaceHolder("<ipaddr>").StringVar(&ctx.debugAddr)
 	serve.Flag("debug-http-port", "Port the debug http endpoint will bind to.").PlaceHolder("<port>").IntVar(&ctx.debugPort)
+	serve.Flag("debug-http-rebuild", "Serve POST /debug/rebuild on the debug http endpoint to force a DAG rebuild.").BoolVar(&ctx.debugRebuild)
 	serve.Flag("disable-feature", "Do not start an informer for the specified resources.").PlaceHolder("<extensionservices,tlsroutes,grpcroutes,tcproutes,backendtlspolicies>").EnumsVar(&ctx.disabledFeatures, "extensionservices", "tlsroutes", "grpcroutes", "tcproutes", "backendtlspolicies")
 	serve.Flag("disable-leader-election", "Disable leader election mechanism.").BoolVar(&ctx.LeaderElection.Disable)
 
@@ -167,6 +174,7 @@ func registerServe(app *kingpin.Application) (*kingpin.CmdClause, *serveContext)
 	serve.Flag("leader-election-resource-namespace", "The namespace of the resource (Lease) leader election will lease.").Default(config.GetenvOr("CONTOUR_NAMESPACE", "projectcontour")).StringVar(&ctx.LeaderElection.Namespace)
 	serve.Flag("leader-election-retry-period", "The interval which Contour will attempt to acquire leadership lease.").Default("2s").DurationVar(&ctx.LeaderElection.RetryPeriod)
 
+	serve.Flag("root-namespace-selector", "Also permit root ingress routes in namespaces matching this label selector.").PlaceHolder("<selector>").StringVar(&ctx.rootNamespaceSelector)
 	serve.Flag("root-namespaces", "Restrict contour to searching these namespaces for root ingress routes.").PlaceHolder("<ns,ns>").StringVar(&ctx.rootNamespaces)
 
 	serve.Flag("stats-address", "Envoy /stats interface address.").PlaceHolder("<ipaddr>").StringVar(&ctx.statsAddr)
@@ -174,12 +182,17 @@ func registerServe(app *kingpin.Application) (*kingpin.CmdClause, *serveContext)
 
 	serve.Flag("use-proxy-protocol", "Use PROXY protocol for all listeners.").BoolVar(&ctx.useProxyProto)
 
+	serve.Flag("watch-label-selector", "Restrict contour to processing HTTPProxies, Ingresses and Gateways matching this label selector.").PlaceHolder("<selector>").StringVar(&ctx.watchLabelSelector)
 	serve.Flag("watch-namespaces", "Restrict contour to watch resources in these namespaces only.").PlaceHolder("<ns,ns>").StringVar(&ctx.watchNamespaces)
 
-	serve.Flag("xds-address", "xDS gRPC API address.").PlaceHolder("<ipaddr>").StringVar(&ctx.xdsAddr)
+	serve.Flag("xds-address", "xDS gRPC API address, or unix:// followed by the path of a Unix domain socket.").PlaceHolder("<ipaddr>").StringVar(&ctx.xdsAddr)
+	serve.Flag("xds-keepalive-min-time", "Minimum time xDS clients must wait between gRPC keepalive pings.").PlaceHolder("<duration>").DurationVar(&ctx.xdsKeepaliveMinTime)
+	serve.Flag("xds-keepalive-permit-without-stream", "Allow xDS clients to send gRPC keepalive pings without active streams.").BoolVar(&ctx.xdsKeepalivePermitWithoutStream)
+	serve.Flag("xds-keepalive-time", "Time without activity after which the xDS server pings the client.").PlaceHolder("<duration>").DurationVar(&ctx.xdsKeepaliveTime)
+	serve.Flag("xds-keepalive-timeout", "Time the xDS server waits for a keepalive ping to be acknowledged.").PlaceHolder("<duration>").DurationVar(&ctx.xdsKeepaliveTimeout)
 	serve.Flag("xds-port", "xDS gRPC API port.").PlaceHolder("<port>").IntVar(&ctx.xdsPort)
 
-	return serve, ctx
+	return ctx
 }
 
 type Server struct {
@@ -189,6 +202,10 @@ type Server struct {
 	mgr               manager.Manager
 	registry          *prometheus.Registry
 	handlerCacheSyncs []cache.InformerSynced
+
+	// userConfig is the configuration the user specified, read once
+	// when the Server is created.
+	userConfig contour_v1alpha1.ContourConfigurationSpec
 }
 
 type EndpointsTranslator interface {
@@ -200,21 +217,10 @@ type EndpointsTranslator interface {
 // NewServer returns a Server object which contains the initial configuration
 // objects required to start an instance of Contour.
 func NewServer(log logrus.FieldLogger, ctx *serveContext) (*Server, error) {
-	var restConfigOpts []func(*rest.Config)
-
-	if qps := ctx.Config.KubeClientQPS; qps > 0 {
-		log.Debugf("Setting Kubernetes client QPS to %v", qps)
-		restConfigOpts = append(restConfigOpts, k8s.OptSetQPS(qps))
-	}
-	if burst := ctx.Config.KubeClientBurst; burst > 0 {
-		log.Debugf("Setting Kubernetes client burst to %v", burst)
-		restConfigOpts = append(restConfigOpts, k8s.OptSetBurst(burst))
-	}
-
 	// Establish k8s core client connection.
-	restConfig, err := k8s.NewRestConfig(ctx.Config.Kubeconfig, ctx.Config.InCluster, restConfigOpts...)
+	restConfig, err := ctx.restConfig(log)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create REST config for Kubernetes clients: %w", err)
+		return nil, err
 	}
 
 	coreClient, err := kubernetes.NewForConfig(restConfig)
@@ -274,6 +280,11 @@ func NewServer(log logrus.FieldLogger, ctx *serveContext) (*Server, error) {
 							return obj, nil
 						}
 
+						// Keep ConfigMaps that hold direct response bodies.
+						if k8s.IsDirectResponseConfigMap(configMap) {
+							return obj, nil
+						}
+
 						// Other types of ConfigMaps will never be referred to, so we can remove all data.
 						// Last-applied-configuration annotation might contain a copy of the complete data.
 						configMap.Data = map[string]string{}
@@ -308,19 +319,28 @@ func NewServer(log logrus.FieldLogger, ctx *serveContext) (*Server, error) {
 		}
 	}
 
+	// Leader election is set up when the manager is created, so the
+	// user configuration is read for its parameters first, with a
+	// client that does not depend on the manager's caches.
+	var reader client.Reader
+	if len(ctx.contourConfigurationName) > 0 {
+		if reader, err = client.New(restConfig, client.Options{Scheme: scheme}); err != nil {
+			return nil, fmt.Errorf("unable to create client: %w", err)
+		}
+	}
+	userConfig, err := ctx.userConfig(reader)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.LeaderElection.apply(userConfig.LeaderElection); err != nil {
+		return nil, err
+	}
+
 	if ctx.LeaderElection.Disable {
 		log.Info("Leader election disabled")
-		options.LeaderElection = false
-	} else {
-		options.LeaderElection = true
-		options.LeaderElectionResourceLock = "leases"
-		options.LeaderElectionNamespace = ctx.LeaderElection.Namespace
-		options.LeaderElectionID = ctx.LeaderElection.Name
-		options.LeaseDuration = &ctx.LeaderElection.LeaseDuration
-		options.RenewDeadline = &ctx.LeaderElection.RenewDeadline
-		options.RetryPeriod = &ctx.LeaderElection.RetryPeriod
-		options.LeaderElectionReleaseOnCancel = true
 	}
+	setLeaderElectionOptions(&options, ctx.LeaderElection)
+
 	mgr, err := manager.New(restConfig, options)
 	if err != nil {
 		return nil, fmt.Errorf("unable to set up controller manager: %w", err)
@@ -337,39 +357,93 @@ func NewServer(log logrus.FieldLogger, ctx *serveContext) (*Server, error) {
 		coreClient: coreClient,
 		mgr:        mgr,
 		registry:   registry,
+		userConfig: userConfig,
 	}, nil
 }
 
+// restConfig returns the REST config for the Kubernetes clients, using
+// the configured kubeconfig and client rate limits.
+func (ctx *serveContext) restConfig(log logrus.FieldLogger) (*rest.Config, error) {
+	var restConfigOpts []func(*rest.Config)
+
+	if qps := ctx.Config.KubeClientQPS; qps > 0 {
+		log.Debugf("Setting Kubernetes client QPS to %v", qps)
+		restConfigOpts = append(restConfigOpts, k8s.OptSetQPS(qps))
+	}
+	if burst := ctx.Config.KubeClientBurst; burst > 0 {
+		log.Debugf("Setting Kubernetes client burst to %v", burst)
+		restConfigOpts = append(restConfigOpts, k8s.OptSetBurst(burst))
+	}
+
+	restConfig, err := k8s.NewRestConfig(ctx.Config.Kubeconfig, ctx.Config.InCluster, restConfigOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create REST config for Kubernetes clients: %w", err)
+	}
+
+	return restConfig, nil
+}
+
+// setLeaderElectionOptions sets the leader election manager options
+// from the given parameters.
+func setLeaderElectionOptions(options *manager.Options, le LeaderElection) {
+	if le.Disable {
+		options.LeaderElection = false
+		return
+	}
+
+	options.LeaderElection = true
+	options.LeaderElectionResourceLock = string(le.ResourceLock)
+	options.LeaderElectionNamespace = le.Namespace
+	options.LeaderElectionID = le.Name
+	options.LeaseDuration = &le.LeaseDuration
+	options.RenewDeadline = &le.RenewDeadline
+	options.RetryPeriod = &le.RetryPeriod
+	options.LeaderElectionReleaseOnCancel = true
+}
+
+// contourConfigurationKey returns the name and namespace of the
+// named ContourConfiguration resource Contour is configured from.
+func contourConfigurationKey(name string) client.ObjectKey {
+	// Determine the name/namespace of the configuration resource utilizing the environment
+	// variable "CONTOUR_NAMESPACE" which should exist on the Contour deployment.
+	//
+	// If the env variable is not present, it will default to "projectcontour".
+	contourNamespace, found := os.LookupEnv("CONTOUR_NAMESPACE")
+	if !found {
+		contourNamespace = "projectcontour"
+	}
+
+	return client.ObjectKey{Namespace: contourNamespace, Name: name}
+}
+
 func (s *Server) getConfig() (contour_v1alpha1.ContourConfigurationSpec, error) {
-	var userConfig contour_v1alpha1.ContourConfigurationSpec
+	return effectiveConfig(s.userConfig)
+}
 
+// userConfig returns the ContourConfigurationSpec the user specified,
+// either the named ContourConfiguration read with reader or the
+// configuration file and flags converted into a ContourConfigurationSpec.
+func (ctx *serveContext) userConfig(reader client.Reader) (contour_v1alpha1.ContourConfigurationSpec, error) {
 	// Get the ContourConfiguration CRD if specified
-	if len(s.ctx.contourConfigurationName) > 0 {
-		// Determine the name/namespace of the configuration resource utilizing the environment
-		// variable "CONTOUR_NAMESPACE" which should exist on the Contour deployment.
-		//
-		// If the env variable is not present, it will default to "projectcontour".
-		contourNamespace, found := os.LookupEnv("CONTOUR_NAMESPACE")
-		if !found {
-			contourNamespace = "projectcontour"
-		}
-
+	if len(ctx.contourConfigurationName) > 0 {
 		contourConfig := &contour_v1alpha1.ContourConfiguration{}
-		key := client.ObjectKey{Namespace: contourNamespace, Name: s.ctx.contourConfigurationName}
+		key := contourConfigurationKey(ctx.contourConfigurationName)
 
-		// Using GetAPIReader() here because the manager's caches won't be started yet,
-		// so reads from the manager's client (which uses the caches for reads) will fail.
-		if err := s.mgr.GetAPIReader().Get(context.Background(), key, contourConfig); err != nil {
+		if err := reader.Get(context.Background(), key, contourConfig); err != nil {
 			return contour_v1alpha1.ContourConfigurationSpec{}, fmt.Errorf("error getting contour configuration %s: %v", key, err)
 		}
 
 		// Copy the Spec from the parsed Configuration
-		userConfig = contourConfig.Spec
-	} else {
-		// No contour configuration passed, so convert the ServeContext into a ContourConfigurationSpec.
-		userConfig = s.ctx.convertToContourConfigurationSpec()
+		return contourConfig.Spec, nil
 	}
 
+	// No contour configuration passed, so convert the ServeContext into a ContourConfigurationSpec.
+	return ctx.convertToContourConfigurationSpec(), nil
+}
+
+// effectiveConfig overlays the user-specified config onto the default
+// config and validates the result.
+func effectiveConfig(userConfig contour_v1alpha1.ContourConfigurationSpec) (contour_v1alpha1.ContourConfigurationSpec, error) {
 	// Overlay the user-specified config onto the default config to come up
 	// with the final set of config to use.
 	contourConfiguration, err := contourconfig.OverlayOnDefaults(userConfig)
@@ -418,11 +492,29 @@ func (s *Server) doServe() error {
 		}
 	}
 
+	labelSelector, err := s.ctx.watchedLabelSelector()
+	if err != nil {
+		return fmt.Errorf("invalid watch label selector: %w", err)
+	}
+	if labelSelector != nil {
+		s.log.WithField("context", "watch-label-selector").Infof("processing objects matching label selector %q", labelSelector)
+	}
+
+	var rootNamespaceSelector labels.Selector
+	if selector := ptr.Deref(contourConfiguration.HTTPProxy.RootNamespaceSelector, ""); selector != "" {
+		if rootNamespaceSelector, err = labels.Parse(selector); err != nil {
+			return fmt.Errorf("invalid root namespace selector: %w", err)
+		}
+		s.log.WithField("context", "root-namespace-selector").Infof("permitting roots in namespaces matching label selector %q", rootNamespaceSelector)
+	}
+
 	// secretNamespaces is a set of namespaces that we should start secret informer for.
 	// If empty, secret informer will be started for all namespaces.
 	secretNamespaces := sets.New[string]()
 
-	if len(rootNamespaces) > 0 {
+	// Namespaces matching the root namespace selector can appear at any
+	// time, so secrets must be watched in all namespaces.
+	if len(rootNamespaces) > 0 && rootNamespaceSelector == nil {
 		s.log.WithField("context", "root-namespaces").Infof("watching root namespaces %q", rootNamespaces)
 		secretNamespaces.Insert(rootNamespaces...)
 
@@ -446,29 +538,76 @@ func (s *Server) doServe() error {
 		return err
 	}
 
+	var allowRequestsWithoutProxyProto bool
+	var proxyProtoVersions []string
+	if pp := contourConfiguration.Envoy.Listener.ProxyProtocol; pp != nil {
+		allowRequestsWithoutProxyProto = ptr.Deref(pp.AllowRequestsWithoutProxyProtocol, false)
+		for _, v := range pp.Versions {
+			proxyProtoVersions = append(proxyProtoVersions, string(v))
+		}
+	}
+
+	listenerFiltersTimeout, err := timeout.Parse(ptr.Deref(contourConfiguration.Envoy.Listener.ListenerFiltersTimeout, ""))
+	if err != nil {
+		return fmt.Errorf("error parsing listener filters timeout: %w", err)
+	}
+
+	http2KeepaliveInterval, err := timeout.Parse(ptr.Deref(contourConfiguration.Envoy.Listener.HTTP2KeepaliveInterval, ""))
+	if err != nil {
+		return fmt.Errorf("error parsing http2 keepalive interval: %w", err)
+	}
+
+	http2KeepaliveTimeout, err := timeout.Parse(ptr.Deref(contourConfiguration.Envoy.Listener.HTTP2KeepaliveTimeout, ""))
+	if err != nil {
+		return fmt.Errorf("error parsing http2 keepalive timeout: %w", err)
+	}
+
+	var http3AdvertisedPort int
+	if h := contourConfiguration.Envoy.Listener.HTTP3; h != nil && ptr.Deref(h.Enabled, false) {
+		http3AdvertisedPort = int(ptr.Deref(h.AdvertisedPort, 443))
+	}
+
 	listenerConfig := xdscache_v3.ListenerConfig{
-		UseProxyProto:                 *contourConfiguration.Envoy.Listener.UseProxyProto,
-		HTTPAccessLog:                 contourConfiguration.Envoy.HTTPListener.AccessLog,
-		HTTPSAccessLog:                contourConfiguration.Envoy.HTTPSListener.AccessLog,
-		AccessLogType:                 contourConfiguration.Envoy.Logging.AccessLogFormat,
-		AccessLogJSONFields:           contourConfiguration.Envoy.Logging.AccessLogJSONFields,
-		AccessLogLevel:                contourConfiguration.Envoy.Logging.AccessLogLevel,
-		AccessLogFormatString:         contourConfiguration.Envoy.Logging.AccessLogFormatString,
-		AccessLogFormatterExtensions:  contourConfiguration.Envoy.Logging.AccessLogFormatterExtensions(),
-		MinimumTLSVersion:             annotation.TLSVersion(contourConfiguration.Envoy.Listener.TLS.MinimumProtocolVersion, "1.2"),
-		MaximumTLSVersion:             annotation.TLSVersion(contourConfiguration.Envoy.Listener.TLS.MaximumProtocolVersion, "1.3"),
-		CipherSuites:                  contourConfiguration.Envoy.Listener.TLS.SanitizedCipherSuites(),
-		Timeouts:                      timeouts,
-		DefaultHTTPVersions:           parseDefaultHTTPVersions(contourConfiguration.Envoy.DefaultHTTPVersions),
-		AllowChunkedLength:            !*contourConfiguration.Envoy.Listener.DisableAllowChunkedLength,
-		MergeSlashes:                  !*contourConfiguration.Envoy.Listener.DisableMergeSlashes,
-		ServerHeaderTransformation:    contourConfiguration.Envoy.Listener.ServerHeaderTransformation,
-		XffNumTrustedHops:             *contourConfiguration.Envoy.Network.XffNumTrustedHops,
-		ConnectionBalancer:            contourConfiguration.Envoy.Listener.ConnectionBalancer,
-		MaxRequestsPerConnection:      contourConfiguration.Envoy.Listener.MaxRequestsPerConnection,
-		HTTP2MaxConcurrentStreams:     contourConfiguration.Envoy.Listener.HTTP2MaxConcurrentStreams,
-		PerConnectionBufferLimitBytes: contourConfiguration.Envoy.Listener.PerConnectionBufferLimitBytes,
-		SocketOptions:                 contourConfiguration.Envoy.Listener.SocketOptions,
+		UseProxyProto:                    *contourConfiguration.Envoy.Listener.UseProxyProto,
+		AllowRequestsWithoutProxyProto:   allowRequestsWithoutProxyProto,
+		ProxyProtoVersions:               proxyProtoVersions,
+		HTTP3:                            http3AdvertisedPort > 0,
+		ListenerFiltersTimeout:           listenerFiltersTimeout,
+		ContinueOnListenerFiltersTimeout: ptr.Deref(contourConfiguration.Envoy.Listener.ContinueOnListenerFiltersTimeout, false),
+		DisableSessionTickets:            ptr.Deref(contourConfiguration.Envoy.Listener.TLS.DisableSessionTickets, false),
+		HTTPAccessLog:                    contourConfiguration.Envoy.HTTPListener.AccessLog,
+		HTTPSAccessLog:                   contourConfiguration.Envoy.HTTPSListener.AccessLog,
+		AccessLogType:                    contourConfiguration.Envoy.Logging.AccessLogFormat,
+		AccessLogJSONFields:              contourConfiguration.Envoy.Logging.AccessLogJSONFields,
+		AccessLogLevel:                   contourConfiguration.Envoy.Logging.AccessLogLevel,
+		AccessLogFormatString:            contourConfiguration.Envoy.Logging.AccessLogFormatString,
+		AccessLogFormatterExtensions:     contourConfiguration.Envoy.Logging.AccessLogFormatterExtensions(),
+		MinimumTLSVersion:                annotation.TLSVersion(contourConfiguration.Envoy.Listener.TLS.MinimumProtocolVersion, "1.2"),
+		MaximumTLSVersion:                annotation.TLSVersion(contourConfiguration.Envoy.Listener.TLS.MaximumProtocolVersion, "1.3"),
+		CipherSuites:                     contourConfiguration.Envoy.Listener.TLS.SanitizedCipherSuites(),
+		Timeouts:                         timeouts,
+		DefaultHTTPVersions:              parseDefaultHTTPVersions(contourConfiguration.Envoy.DefaultHTTPVersions),
+		AllowChunkedLength:               !*contourConfiguration.Envoy.Listener.DisableAllowChunkedLength,
+		AllowAbsoluteURL:                 contourConfiguration.Envoy.Listener.AllowAbsoluteURL,
+		DisableAcceptHTTP10:              ptr.Deref(contourConfiguration.Envoy.Listener.DisableAcceptHTTP10, false),
+		HTTP10DefaultHost:                contourConfiguration.Envoy.Listener.HTTP10DefaultHost,
+		DeltaXDS:                         ptr.Deref(contourConfiguration.XDSServer.Delta, false),
+		MergeSlashes:                     !*contourConfiguration.Envoy.Listener.DisableMergeSlashes,
+		StripAnyHostPort:                 ptr.Deref(contourConfiguration.Envoy.Listener.StripPortFromHost, false),
+		StripMatchingHostPort:            ptr.Deref(contourConfiguration.Envoy.Listener.StripMatchingHostPort, false),
+		ServerHeaderTransformation:       contourConfiguration.Envoy.Listener.ServerHeaderTransformation,
+		ServerName:                       contourConfiguration.Envoy.Listener.ServerName,
+		UseRemoteAddress:                 contourConfiguration.Envoy.Listener.UseRemoteAddress,
+		Via:                              contourConfiguration.Envoy.Listener.Via,
+		LocalReplyPolicy:                 contourConfiguration.Envoy.Listener.LocalReplyPolicy,
+		XffNumTrustedHops:                *contourConfiguration.Envoy.Network.XffNumTrustedHops,
+		ConnectionBalancer:               contourConfiguration.Envoy.Listener.ConnectionBalancer,
+		MaxRequestsPerConnection:         contourConfiguration.Envoy.Listener.MaxRequestsPerConnection,
+		HTTP2MaxConcurrentStreams:        contourConfiguration.Envoy.Listener.HTTP2MaxConcurrentStreams,
+		HTTP2KeepaliveInterval:           http2KeepaliveInterval,
+		HTTP2KeepaliveTimeout:            http2KeepaliveTimeout,
+		PerConnectionBufferLimitBytes:    contourConfiguration.Envoy.Listener.PerConnectionBufferLimitBytes,
+		SocketOptions:                    contourConfiguration.Envoy.Listener.SocketOptions,
 	}
 
 	if listenerConfig.TracingConfig, err = s.setupTracingService(contourConfiguration.Tracing); err != nil {
@@ -493,11 +632,18 @@ func (s *Server) doServe() error {
 		endpointHandler = xdscache_v3.NewEndpointsTranslator(s.log.WithField("context", "endpointstranslator"))
 	}
 
+	listenerCache := xdscache_v3.NewListenerCache(listenerConfig, *contourConfiguration.Envoy.Metrics, *contourConfiguration.Envoy.Health, *contourConfiguration.Envoy.Network.EnvoyAdminPort)
+	routeCache := &xdscache_v3.RouteCache{
+		ContourVersionHeader:  ptr.Deref(contourConfiguration.Policy.ContourVersionHeader, false),
+		RouteIdentifierHeader: contourConfiguration.Policy.RouteIdentifierHeader,
+		HTTP3AdvertisedPort:   http3AdvertisedPort,
+	}
+
 	resources := []xdscache.ResourceCache{
-		xdscache_v3.NewListenerCache(listenerConfig, *contourConfiguration.Envoy.Metrics, *contourConfiguration.Envoy.Health, *contourConfiguration.Envoy.Network.EnvoyAdminPort),
+		listenerCache,
 		xdscache_v3.NewSecretsCache(envoy_v3.StatsSecrets(contourConfiguration.Envoy.Metrics.TLS)),
-		&xdscache_v3.RouteCache{},
-		&xdscache_v3.ClusterCache{},
+		routeCache,
+		&xdscache_v3.ClusterCache{DeltaXDS: ptr.Deref(contourConfiguration.XDSServer.Delta, false)},
 		endpointHandler,
 		xdscache_v3.NewRuntimeCache(xdscache_v3.ConfigurableRuntimeSettings{
 			MaxRequestsPerIOCycle:     contourConfiguration.Envoy.Listener.MaxRequestsPerIOCycle,
@@ -505,13 +651,37 @@ func (s *Server) doServe() error {
 		}),
 	}
 
+	var shutdownDrainTime time.Duration
+	if drainTime := ptr.Deref(contourConfiguration.XDSServer.ShutdownDrainTime, ""); drainTime != "" {
+		if shutdownDrainTime, err = time.ParseDuration(drainTime); err != nil {
+			return fmt.Errorf("error parsing shutdown drain time: %w", err)
+		}
+	}
+
+	// On SIGTERM, fail readiness and keep serving xDS for the drain time,
+	// so no new Envoy xDS connections are sent to this replica. Envoys
+	// already connected keep their config and only reconnect elsewhere
+	// once it exits. Envoy's own listeners are drained by the
+	// `contour envoy shutdown` preStop hook, not here.
+	var draining atomic.Bool
+	shutdownSignals := make(chan os.Signal, 2)
+	signal.Notify(shutdownSignals, os.Interrupt, syscall.SIGTERM)
+	ctx := drainOnSignal(shutdownSignals, shutdownDrainTime, func() { draining.Store(true) }, s.log.WithField("context", "shutdown"))
+
 	// snapshotHandler triggers go-control-plane Snapshots based on
 	// the contents of the Contour xDS caches after the DAG is built.
 	var snapshotHandler *xdscache_v3.SnapshotHandler
 
 	// nolint:staticcheck
 	if contourConfiguration.XDSServer.Type == contour_v1alpha1.EnvoyServerType {
-		snapshotHandler = xdscache_v3.NewSnapshotHandler(resources, s.log.WithField("context", "snapshotHandler"))
+		var resourceTTL time.Duration
+		if ttl := ptr.Deref(contourConfiguration.XDSServer.ResourceTTL, ""); ttl != "" {
+			if resourceTTL, err = time.ParseDuration(ttl); err != nil {
+				return fmt.Errorf("error parsing xds resource ttl: %w", err)
+			}
+		}
+
+		snapshotHandler = xdscache_v3.NewSnapshotHandler(ctx, resources, resourceTTL, contourMetrics, s.log.WithField("context", "snapshotHandler"))
 
 		// register observer for endpoints updates.
 		endpointHandler.SetObserver(contour.ComposeObservers(snapshotHandler))
@@ -539,7 +709,10 @@ func (s *Server) doServe() error {
 		fallbackCert = &types.NamespacedName{Name: contourConfiguration.HTTPProxy.FallbackCertificate.Name, Namespace: contourConfiguration.HTTPProxy.FallbackCertificate.Namespace}
 	}
 
-	sh := k8s.NewStatusUpdateHandler(s.log.WithField("context", "StatusUpdateHandler"), s.mgr.GetClient(), contourMetrics)
+	// Status writes are limited to the Kubernetes client's QPS and burst,
+	// as set by the kubernetes-client-qps and kubernetes-client-burst flags.
+	restConfig := s.mgr.GetConfig()
+	sh := k8s.NewStatusUpdateHandler(s.log.WithField("context", "StatusUpdateHandler"), s.mgr.GetClient(), contourMetrics, restConfig.QPS, restConfig.Burst)
 	if err := s.mgr.Add(sh); err != nil {
 		return err
 	}
@@ -555,7 +728,9 @@ func (s *Server) doServe() error {
 
 	builder := s.getDAGBuilder(dagBuilderConfig{
 		ingressClassNames:                  ingressClassNames,
+		labelSelector:                      labelSelector,
 		rootNamespaces:                     contourConfiguration.HTTPProxy.RootNamespaces,
+		rootNamespaceSelector:              rootNamespaceSelector,
 		gatewayRef:                         gatewayRef,
 		disablePermitInsecure:              *contourConfiguration.HTTPProxy.DisablePermitInsecure,
 		enableExternalNameService:          *contourConfiguration.EnableExternalNameService,
@@ -580,6 +755,7 @@ func (s *Server) doServe() error {
 			MaximumProtocolVersion: annotation.TLSVersion(contourConfiguration.Envoy.Cluster.UpstreamTLS.MaximumProtocolVersion, "1.3"),
 			CipherSuites:           contourConfiguration.Envoy.Cluster.UpstreamTLS.SanitizedCipherSuites(),
 		},
+		disableMergeSlashes: *contourConfiguration.Envoy.Listener.DisableMergeSlashes,
 	})
 
 	// Build the core Kubernetes event handler.
@@ -590,6 +766,7 @@ func (s *Server) doServe() error {
 
 	observer := contour.NewRebuildMetricsObserver(
 		contourMetrics,
+		s.proxyEventRecorder(),
 		dag.ComposeObservers(xdsCaches...),
 	)
 
@@ -602,6 +779,20 @@ func (s *Server) doServe() error {
 		return true
 	}
 
+	// If Contour was configured from a ContourConfiguration resource,
+	// reload the fields that can be changed without a restart.
+	var configReloader *contour.ConfigReloader
+	if len(s.ctx.contourConfigurationName) > 0 {
+		configReloader = contour.NewConfigReloader(
+			contourConfigurationKey(s.ctx.contourConfigurationName),
+			contourConfiguration,
+			func(spec contour_v1alpha1.ContourConfigurationSpec) error {
+				return applyReloadableConfig(spec, listenerCache, routeCache, builder)
+			},
+			s.log.WithField("context", "configReloader"),
+		)
+	}
+
 	contourHandler := contour.NewEventHandler(contour.EventHandlerConfig{
 		Logger:          s.log.WithField("context", "contourEventHandler"),
 		HoldoffDelay:    100 * time.Millisecond,
@@ -609,6 +800,7 @@ func (s *Server) doServe() error {
 		Observer:        observer,
 		StatusUpdater:   sh.Writer(),
 		Builder:         builder,
+		ConfigReloader:  configReloader,
 	}, hasSynced)
 
 	// Wrap contourHandler in an EventRecorder which tracks API server events.
@@ -631,6 +823,12 @@ func (s *Server) doServe() error {
 		delete(informerResources, feat)
 	}
 
+	// The root namespace selector is evaluated against namespace labels.
+	// The Gateway API informers include namespaces already.
+	if rootNamespaceSelector != nil && contourConfiguration.Gateway == nil {
+		informerResources["namespaces"] = &core_v1.Namespace{}
+	}
+
 	// Inform on the remaining resources.
 	for name, r := range informerResources {
 		if err := s.informOnResource(r, eventHandler); err != nil {
@@ -638,6 +836,13 @@ func (s *Server) doServe() error {
 		}
 	}
 
+	// Inform on the ContourConfiguration resource for hot reload.
+	if configReloader != nil {
+		if err := s.informOnResource(&contour_v1alpha1.ContourConfiguration{}, eventHandler); err != nil {
+			s.log.WithError(err).WithField("resource", "contourconfigurations").Fatal("failed to create informer")
+		}
+	}
+
 	// Inform on Gateway API resources.
 	s.setupGatewayAPI(contourConfiguration, eventHandler)
 
@@ -675,18 +880,45 @@ func (s *Server) doServe() error {
 		return err
 	}
 
+	// Contour is ready once the initial DAG is built and, for the envoy
+	// xDS server, a snapshot of it is in the xDS cache. It stops being
+	// ready once it starts draining on shutdown.
+	ready := func() bool {
+		if draining.Load() || !contourHandler.HasBuiltInitialDag() {
+			return false
+		}
+		return snapshotHandler == nil || snapshotHandler.HasInitialSnapshot()
+	}
+
 	// Create metrics service.
-	if err := s.setupMetrics(*contourConfiguration.Metrics, *contourConfiguration.Health, s.registry); err != nil {
+	if err := s.setupMetrics(*contourConfiguration.Metrics, *contourConfiguration.Health, s.registry, ready); err != nil {
+		return err
+	}
+
+	// Poll Envoy for listener stats if configured.
+	if err := s.setupEnvoyStatsCollector(contourConfiguration.Envoy.StatsCollector, listenerCache, contourMetrics); err != nil {
 		return err
 	}
 
 	// Create a separate health service if required.
-	if err := s.setupHealth(*contourConfiguration.Health, *contourConfiguration.Metrics); err != nil {
+	if err := s.setupHealth(*contourConfiguration.Health, *contourConfiguration.Metrics, ready); err != nil {
 		return err
 	}
 
 	// Create debug service and register with mgr.
-	if err := s.setupDebugService(*contourConfiguration.Debug, builder); err != nil {
+	var rebuilder debug.Rebuilder
+	if s.ctx.debugRebuild {
+		rebuilder = debug.RebuilderFunc(func(ctx context.Context) (string, error) {
+			if err := contourHandler.Rebuild(ctx); err != nil {
+				return "", err
+			}
+			if snapshotHandler == nil {
+				return "", nil
+			}
+			return snapshotHandler.Version(), nil
+		})
+	}
+	if err := s.setupDebugService(*contourConfiguration.Debug, builder, rebuilder); err != nil {
 		return err
 	}
 
@@ -696,6 +928,7 @@ func (s *Server) doServe() error {
 		cache:             s.mgr.GetCache(),
 		lbStatus:          make(chan core_v1.LoadBalancerStatus, 1),
 		ingressClassNames: ingressClassNames,
+		labelSelector:     labelSelector,
 		gatewayRef:        gatewayRef,
 		statusUpdater:     sh.Writer(),
 	}
@@ -740,6 +973,11 @@ func (s *Server) doServe() error {
 		return err
 	}
 
+	// Refresh the TLS certificate expiry metric between DAG rebuilds.
+	if err := s.mgr.Add(observer); err != nil {
+		return err
+	}
+
 	notifier := &leadership.Notifier{
 		ToNotify: []leadership.NeedLeaderElectionNotification{contourHandler, observer},
 	}
@@ -748,7 +986,51 @@ func (s *Server) doServe() error {
 	}
 
 	// GO!
-	return s.mgr.Start(signals.SetupSignalHandler())
+	return s.mgr.Start(ctx)
+}
+
+// drainOnSignal returns a context that is cancelled drainTime after the
+// first signal is received on signals. When that signal arrives, drain is
+// called so that the caller can report itself as draining while it keeps
+// running. A second signal cancels the context immediately.
+func drainOnSignal(signals <-chan os.Signal, drainTime time.Duration, drain func(), log logrus.FieldLogger) context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		defer cancel()
+
+		sig := <-signals
+		drain()
+		if drainTime <= 0 {
+			return
+		}
+
+		log.WithField("signal", sig.String()).Infof("draining for %s before shutting down", drainTime)
+
+		timer := time.NewTimer(drainTime)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case sig := <-signals:
+			log.WithField("signal", sig.String()).Info("received second signal, shutting down immediately")
+		}
+	}()
+
+	return ctx
+}
+
+// proxyEventRecorder returns a ProxyEventRecorder that records Events
+// on HTTPProxies which become invalid. Events are rate limited per
+// HTTPProxy so that frequent DAG rebuilds do not spam the API server.
+func (s *Server) proxyEventRecorder() *status.ProxyEventRecorder {
+	broadcaster := record.NewBroadcasterWithCorrelatorOptions(record.CorrelatorOptions{
+		QPS:       1.0 / 60,
+		BurstSize: 10,
+	})
+	broadcaster.StartRecordingToSink(&typed_core_v1.EventSinkImpl{Interface: s.coreClient.CoreV1().Events("")})
+
+	return status.NewProxyEventRecorder(broadcaster.NewRecorder(s.mgr.GetScheme(), core_v1.EventSource{Component: "contour"}))
 }
 
 func (s *Server) getExtensionSvcConfig(name, namespace string) (xdscache_v3.ExtensionServiceConfig, error) {
@@ -883,17 +1165,26 @@ func (s *Server) setupGlobalExternalAuthentication(contourConfiguration contour_
 			MaxRequestBytes:     contourConfiguration.GlobalExternalAuthorization.WithRequestBody.MaxRequestBytes,
 		}
 	}
+
+	if authRequest := contourConfiguration.GlobalExternalAuthorization.AuthorizationRequest; authRequest != nil {
+		globalExternalAuthConfig.RequestAllowedHeaders = authRequest.AllowedHeaders
+		globalExternalAuthConfig.RequestDisallowedHeaders = authRequest.DisallowedHeaders
+	}
+	if authResponse := contourConfiguration.GlobalExternalAuthorization.AuthorizationResponse; authResponse != nil {
+		globalExternalAuthConfig.ResponseDisallowedHeaders = authResponse.DisallowedHeaders
+	}
 	return globalExternalAuthConfig, nil
 }
 
-func (s *Server) setupDebugService(debugConfig contour_v1alpha1.DebugConfig, builder *dag.Builder) error {
+func (s *Server) setupDebugService(debugConfig contour_v1alpha1.DebugConfig, builder *dag.Builder, rebuilder debug.Rebuilder) error {
 	debugsvc := &debug.Service{
 		Service: httpsvc.Service{
 			Addr:        debugConfig.Address,
 			Port:        debugConfig.Port,
 			FieldLogger: s.log.WithField("context", "debugsvc"),
 		},
-		Builder: builder,
+		Builder:   builder,
+		Rebuilder: rebuilder,
 	}
 	return s.mgr.Add(debugsvc)
 }
@@ -922,7 +1213,11 @@ func (x *xdsServer) Start(ctx context.Context) error {
 	}
 	log.Info("the initial dag is built")
 
-	grpcServer := xds.NewServer(x.registry, grpcOptions(log, x.config.TLS)...)
+	opts, err := grpcOptions(log, x.config.TLS, x.config.Keepalive)
+	if err != nil {
+		return err
+	}
+	grpcServer := xds.NewServer(x.registry, opts...)
 
 	// nolint:staticcheck
 	switch x.config.Type {
@@ -936,13 +1231,12 @@ func (x *xdsServer) Start(ctx context.Context) error {
 		log.Fatalf("invalid xDS server type %q", x.config.Type)
 	}
 
-	addr := net.JoinHostPort(x.config.Address, strconv.Itoa(x.config.Port))
-	l, err := net.Listen("tcp", addr)
+	l, err := xds.NewListener(x.config.Address, x.config.Port)
 	if err != nil {
 		return err
 	}
 
-	log = log.WithField("address", addr)
+	log = log.WithField("address", l.Addr().String())
 	if *x.config.TLS.Insecure {
 		log = log.WithField("insecure", true)
 	}
@@ -966,7 +1260,7 @@ func (x *xdsServer) Start(ctx context.Context) error {
 
 // setupMetrics creates metrics service for Contour.
 func (s *Server) setupMetrics(metricsConfig contour_v1alpha1.MetricsConfig, healthConfig contour_v1alpha1.HealthConfig,
-	registry *prometheus.Registry,
+	registry *prometheus.Registry, ready func() bool,
 ) error {
 	// Create metrics service and register with mgr.
 	metricsvc := &httpsvc.Service{
@@ -988,13 +1282,35 @@ func (s *Server) setupMetrics(metricsConfig contour_v1alpha1.MetricsConfig, heal
 		h := health.Handler(s.coreClient)
 		metricsvc.ServeMux.Handle("/health", h)
 		metricsvc.ServeMux.Handle("/healthz", h)
+		metricsvc.ServeMux.Handle("/readyz", health.ReadyHandler(ready))
 	}
 
 	return s.mgr.Add(metricsvc)
 }
 
+// setupEnvoyStatsCollector creates a collector polling Envoy
+// for listener stats, if polling is enabled.
+func (s *Server) setupEnvoyStatsCollector(collectorConfig *contour_v1alpha1.EnvoyStatsCollectorConfig, listenerCache *xdscache_v3.ListenerCache, contourMetrics *metrics.Metrics) error {
+	interval, err := collectorConfig.Interval()
+	if err != nil {
+		return err
+	}
+	if interval == 0 {
+		return nil
+	}
+
+	return s.mgr.Add(&metrics.EnvoyStatsCollector{
+		URL:           collectorConfig.URL,
+		Interval:      interval,
+		Client:        &http.Client{Timeout: interval},
+		ListenerNames: listenerCache.ListenerStatPrefixes,
+		Metrics:       contourMetrics,
+		FieldLogger:   s.log.WithField("context", "envoyStatsCollector"),
+	})
+}
+
 func (s *Server) setupHealth(healthConfig contour_v1alpha1.HealthConfig,
-	metricsConfig contour_v1alpha1.MetricsConfig,
+	metricsConfig contour_v1alpha1.MetricsConfig, ready func() bool,
 ) error {
 	if healthConfig.Address != metricsConfig.Address || healthConfig.Port != metricsConfig.Port {
 		healthsvc := &httpsvc.Service{
@@ -1006,6 +1322,7 @@ func (s *Server) setupHealth(healthConfig contour_v1alpha1.HealthConfig,
 		h := health.Handler(s.coreClient)
 		healthsvc.ServeMux.Handle("/health", h)
 		healthsvc.ServeMux.Handle("/healthz", h)
+		healthsvc.ServeMux.Handle("/readyz", health.ReadyHandler(ready))
 
 		return s.mgr.Add(healthsvc)
 	}
@@ -1049,7 +1366,9 @@ func (s *Server) setupGatewayAPI(contourConfiguration contour_v1alpha1.ContourCo
 
 type dagBuilderConfig struct {
 	ingressClassNames                  []string
+	labelSelector                      labels.Selector
 	rootNamespaces                     []string
+	rootNamespaceSelector              labels.Selector
 	gatewayRef                         *types.NamespacedName
 	disablePermitInsecure              bool
 	enableExternalNameService          bool
@@ -1070,44 +1389,11 @@ type dagBuilderConfig struct {
 	globalRateLimitService             *contour_v1alpha1.RateLimitServiceConfig
 	globalCircuitBreakerDefaults       *contour_v1alpha1.CircuitBreakers
 	upstreamTLS                        *dag.UpstreamTLS
+	disableMergeSlashes                bool
 }
 
 func (s *Server) getDAGBuilder(dbc dagBuilderConfig) *dag.Builder {
-	var (
-		requestHeadersPolicy       dag.HeadersPolicy
-		responseHeadersPolicy      dag.HeadersPolicy
-		applyHeaderPolicyToIngress bool
-	)
-
-	if dbc.headersPolicy != nil {
-		if dbc.headersPolicy.RequestHeadersPolicy != nil {
-			if dbc.headersPolicy.RequestHeadersPolicy.Set != nil {
-				requestHeadersPolicy.Set = make(map[string]string)
-				for k, v := range dbc.headersPolicy.RequestHeadersPolicy.Set {
-					requestHeadersPolicy.Set[k] = v
-				}
-			}
-			if dbc.headersPolicy.RequestHeadersPolicy.Remove != nil {
-				requestHeadersPolicy.Remove = make([]string, 0, len(dbc.headersPolicy.RequestHeadersPolicy.Remove))
-				requestHeadersPolicy.Remove = append(requestHeadersPolicy.Remove, dbc.headersPolicy.RequestHeadersPolicy.Remove...)
-			}
-		}
-
-		if dbc.headersPolicy.ResponseHeadersPolicy != nil {
-			if dbc.headersPolicy.ResponseHeadersPolicy.Set != nil {
-				responseHeadersPolicy.Set = make(map[string]string)
-				for k, v := range dbc.headersPolicy.ResponseHeadersPolicy.Set {
-					responseHeadersPolicy.Set[k] = v
-				}
-			}
-			if dbc.headersPolicy.ResponseHeadersPolicy.Remove != nil {
-				responseHeadersPolicy.Remove = make([]string, 0, len(dbc.headersPolicy.ResponseHeadersPolicy.Remove))
-				responseHeadersPolicy.Remove = append(responseHeadersPolicy.Remove, dbc.headersPolicy.ResponseHeadersPolicy.Remove...)
-			}
-		}
-
-		applyHeaderPolicyToIngress = *dbc.headersPolicy.ApplyToIngress
-	}
+	requestHeadersPolicy, responseHeadersPolicy, applyHeaderPolicyToIngress := headersPolicies(dbc.headersPolicy)
 
 	var requestHeadersPolicyIngress dag.HeadersPolicy
 	var responseHeadersPolicyIngress dag.HeadersPolicy
@@ -1166,6 +1452,7 @@ func (s *Server) getDAGBuilder(dbc dagBuilderConfig) *dag.Builder {
 			SetSourceMetadataOnRoutes:     true,
 			GlobalCircuitBreakerDefaults:  dbc.globalCircuitBreakerDefaults,
 			UpstreamTLS:                   dbc.upstreamTLS,
+			DisableMergeSlashes:           dbc.disableMergeSlashes,
 		},
 	}
 
@@ -1193,7 +1480,9 @@ func (s *Server) getDAGBuilder(dbc dagBuilderConfig) *dag.Builder {
 	builder := &dag.Builder{
 		Source: dag.KubernetesCache{
 			RootNamespaces:           dbc.rootNamespaces,
+			RootNamespaceSelector:    dbc.rootNamespaceSelector,
 			IngressClassNames:        dbc.ingressClassNames,
+			LabelSelector:            dbc.labelSelector,
 			ConfiguredGatewayToCache: dbc.gatewayRef,
 			ConfiguredSecretRefs:     configuredSecretRefs,
 			FieldLogger:              s.log.WithField("context", "KubernetesCache"),
@@ -1210,6 +1499,95 @@ func (s *Server) getDAGBuilder(dbc dagBuilderConfig) *dag.Builder {
 	return builder
 }
 
+// headersPolicies converts the global header policies in the
+// configuration to the request and response policies used by the
+// DAG processors.
+func headersPolicies(policy *contour_v1alpha1.PolicyConfig) (requestHeadersPolicy, responseHeadersPolicy dag.HeadersPolicy, applyToIngress bool) {
+	if policy != nil {
+		if policy.RequestHeadersPolicy != nil {
+			if policy.RequestHeadersPolicy.Set != nil {
+				requestHeadersPolicy.Set = make(map[string]string)
+				for k, v := range policy.RequestHeadersPolicy.Set {
+					requestHeadersPolicy.Set[k] = v
+				}
+			}
+			if policy.RequestHeadersPolicy.Remove != nil {
+				requestHeadersPolicy.Remove = make([]string, 0, len(policy.RequestHeadersPolicy.Remove))
+				requestHeadersPolicy.Remove = append(requestHeadersPolicy.Remove, policy.RequestHeadersPolicy.Remove...)
+			}
+		}
+
+		if policy.ResponseHeadersPolicy != nil {
+			if policy.ResponseHeadersPolicy.Set != nil {
+				responseHeadersPolicy.Set = make(map[string]string)
+				for k, v := range policy.ResponseHeadersPolicy.Set {
+					responseHeadersPolicy.Set[k] = v
+				}
+			}
+			if policy.ResponseHeadersPolicy.Remove != nil {
+				responseHeadersPolicy.Remove = make([]string, 0, len(policy.ResponseHeadersPolicy.Remove))
+				responseHeadersPolicy.Remove = append(responseHeadersPolicy.Remove, policy.ResponseHeadersPolicy.Remove...)
+			}
+		}
+
+		applyToIngress = ptr.Deref(policy.ApplyToIngress, false)
+	}
+
+	return requestHeadersPolicy, responseHeadersPolicy, applyToIngress
+}
+
+// applyReloadableConfig applies the reloadable fields of the given
+// configuration to the running xDS caches and DAG processors. See
+// contour.ConfigReloader for the set of reloadable fields.
+func applyReloadableConfig(
+	spec contour_v1alpha1.ContourConfigurationSpec,
+	listenerCache *xdscache_v3.ListenerCache,
+	routeCache *xdscache_v3.RouteCache,
+	builder *dag.Builder,
+) error {
+	timeouts, err := contourconfig.ParseTimeoutPolicy(spec.Envoy.Timeouts)
+	if err != nil {
+		return err
+	}
+
+	listenerCache.Config.Timeouts = timeouts
+	listenerCache.Config.AccessLogType = spec.Envoy.Logging.AccessLogFormat
+	listenerCache.Config.AccessLogJSONFields = spec.Envoy.Logging.AccessLogJSONFields
+	listenerCache.Config.AccessLogLevel = spec.Envoy.Logging.AccessLogLevel
+	listenerCache.Config.AccessLogFormatString = spec.Envoy.Logging.AccessLogFormatString
+	listenerCache.Config.AccessLogFormatterExtensions = spec.Envoy.Logging.AccessLogFormatterExtensions()
+
+	routeCache.ContourVersionHeader = ptr.Deref(spec.Policy.ContourVersionHeader, false)
+	routeCache.RouteIdentifierHeader = spec.Policy.RouteIdentifierHeader
+
+	requestHeadersPolicy, responseHeadersPolicy, applyToIngress := headersPolicies(spec.Policy)
+
+	var requestHeadersPolicyIngress, responseHeadersPolicyIngress dag.HeadersPolicy
+	if applyToIngress {
+		requestHeadersPolicyIngress = requestHeadersPolicy
+		responseHeadersPolicyIngress = responseHeadersPolicy
+	}
+
+	for _, processor := range builder.Processors {
+		switch p := processor.(type) {
+		case *dag.IngressProcessor:
+			p.ConnectTimeout = timeouts.ConnectTimeout
+			p.RequestHeadersPolicy = &requestHeadersPolicyIngress
+			p.ResponseHeadersPolicy = &responseHeadersPolicyIngress
+		case *dag.ExtensionServiceProcessor:
+			p.ConnectTimeout = timeouts.ConnectTimeout
+		case *dag.HTTPProxyProcessor:
+			p.ConnectTimeout = timeouts.ConnectTimeout
+			p.RequestHeadersPolicy = &requestHeadersPolicy
+			p.ResponseHeadersPolicy = &responseHeadersPolicy
+		case *dag.GatewayAPIProcessor:
+			p.ConnectTimeout = timeouts.ConnectTimeout
+		}
+	}
+
+	return nil
+}
+
 func (s *Server) informOnResource(obj client.Object, handler cache.ResourceEventHandler) error {
 	inf, err := s.mgr.GetCache().GetInformer(context.Background(), obj)
 	if err != nil {
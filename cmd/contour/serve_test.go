@@ -14,16 +14,42 @@
 package main
 
 import (
+	"context"
+	"os"
+	"sync/atomic"
+	"syscall"
 	"testing"
+	"time"
 
+	envoy_config_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_config_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_filter_network_http_connection_manager_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+	core_v1 "k8s.io/api/core/v1"
+	networking_v1 "k8s.io/api/networking/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	controller_runtime_metrics_server "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
+	"github.com/projectcontour/contour/internal/contour"
+	"github.com/projectcontour/contour/internal/contourconfig"
 	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/k8s"
+	"github.com/projectcontour/contour/internal/metrics"
+	"github.com/projectcontour/contour/internal/xdscache"
+	xdscache_v3 "github.com/projectcontour/contour/internal/xdscache/v3"
 )
 
 func TestGetDAGBuilder(t *testing.T) {
@@ -256,3 +282,379 @@ func mustGetIngressProcessor(t *testing.T, builder *dag.Builder) *dag.IngressPro
 	require.FailNow(t, "IngressProcessor not found in list of DAG builder's processors")
 	return nil
 }
+
+type discardStatusUpdater struct{}
+
+func (discardStatusUpdater) Send(k8s.StatusUpdate) {}
+
+func TestConfigReloadBuildsNewSnapshot(t *testing.T) {
+	log := fixture.NewTestLogger(t)
+
+	current := contourconfig.Defaults()
+	timeouts, err := contourconfig.ParseTimeoutPolicy(current.Envoy.Timeouts)
+	require.NoError(t, err)
+
+	listenerCache := xdscache_v3.NewListenerCache(xdscache_v3.ListenerConfig{
+		Timeouts:      timeouts,
+		AccessLogType: current.Envoy.Logging.AccessLogFormat,
+	}, *current.Envoy.Metrics, *current.Envoy.Health, 0)
+	routeCache := &xdscache_v3.RouteCache{}
+
+	serve := &Server{log: log}
+	builder := serve.getDAGBuilder(dagBuilderConfig{
+		rootNamespaces:  []string{},
+		dnsLookupFamily: contour_v1alpha1.AutoClusterDNSFamily,
+		headersPolicy:   current.Policy,
+		connectTimeout:  timeouts.ConnectTimeout,
+		httpPort:        8080,
+		httpsPort:       8443,
+	})
+	builder.Source.Insert(fixture.NewService("default/backend").
+		WithPorts(core_v1.ServicePort{Port: 80}))
+	builder.Source.Insert(fixture.NewProxy("default/simple").WithSpec(contour_v1.HTTPProxySpec{
+		VirtualHost: &contour_v1.VirtualHost{Fqdn: "example.com"},
+		Routes: []contour_v1.Route{{
+			Services: []contour_v1.Service{{Name: "backend", Port: 80}},
+		}},
+	}))
+
+	registry := prometheus.NewRegistry()
+	contourMetrics := metrics.NewMetrics(registry)
+	snapshotHandler := xdscache_v3.NewSnapshotHandler(context.Background(), []xdscache.ResourceCache{listenerCache, routeCache}, 0, contourMetrics, log)
+
+	name := types.NamespacedName{Namespace: "projectcontour", Name: "contour"}
+	reloader := contour.NewConfigReloader(name, current, func(spec contour_v1alpha1.ContourConfigurationSpec) error {
+		return applyReloadableConfig(spec, listenerCache, routeCache, builder)
+	}, log)
+
+	handler := contour.NewEventHandler(contour.EventHandlerConfig{
+		Logger:          log,
+		Builder:         builder,
+		Observer:        dag.ComposeObservers(listenerCache, routeCache, snapshotHandler),
+		HoldoffDelay:    time.Millisecond,
+		HoldoffMaxDelay: time.Millisecond,
+		StatusUpdater:   discardStatusUpdater{},
+		ConfigReloader:  reloader,
+	}, func() bool { return true })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.Start(ctx) // nolint:errcheck
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	snapshotVersion := func() string {
+		families, err := registry.Gather()
+		require.NoError(t, err)
+		for _, mf := range families {
+			if mf.GetName() != metrics.XDSSnapshotVersionInfoGauge {
+				continue
+			}
+			for _, m := range mf.Metric {
+				labels := map[string]string{}
+				for _, l := range m.Label {
+					labels[l.GetName()] = l.GetValue()
+				}
+				if labels["cache"] == "default" {
+					return labels["version"]
+				}
+			}
+		}
+		return ""
+	}
+
+	connectionIdleTimeout := func() *durationpb.Duration {
+		for _, resource := range listenerCache.Contents() {
+			listener := resource.(*envoy_config_listener_v3.Listener)
+			if listener.Name != xdscache_v3.ENVOY_HTTP_LISTENER {
+				continue
+			}
+			hcm := &envoy_filter_network_http_connection_manager_v3.HttpConnectionManager{}
+			require.NoError(t, listener.FilterChains[0].Filters[0].GetTypedConfig().UnmarshalTo(hcm))
+			return hcm.CommonHttpProtocolOptions.GetIdleTimeout()
+		}
+		t.Fatal("HTTP listener not found")
+		return nil
+	}
+
+	routeIdentifierHeader := func() string {
+		for _, resource := range routeCache.Contents() {
+			routeConfig := resource.(*envoy_config_route_v3.RouteConfiguration)
+			if routeConfig.Name != xdscache_v3.ENVOY_HTTP_LISTENER {
+				continue
+			}
+			for _, vhost := range routeConfig.VirtualHosts {
+				for _, route := range vhost.Routes {
+					for _, header := range route.RequestHeadersToAdd {
+						return header.GetHeader().GetKey()
+					}
+				}
+			}
+		}
+		return ""
+	}
+
+	waitForSequence := func() {
+		select {
+		case <-handler.Sequence():
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the event handler")
+		}
+	}
+
+	// Build the initial DAG.
+	initialVersion := snapshotVersion()
+	handler.OnElectedLeader()
+	waitForSequence()
+
+	builtVersion := snapshotVersion()
+	require.NotEqual(t, initialVersion, builtVersion)
+	require.Nil(t, connectionIdleTimeout())
+	require.Empty(t, routeIdentifierHeader())
+
+	config := &contour_v1alpha1.ContourConfiguration{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace:       name.Namespace,
+			Name:            name.Name,
+			Generation:      1,
+			ResourceVersion: "1",
+		},
+	}
+
+	// Change a reloadable field.
+	updated := config.DeepCopy()
+	updated.Generation, updated.ResourceVersion = 2, "2"
+	updated.Spec.Envoy = &contour_v1alpha1.EnvoyConfig{
+		Timeouts: &contour_v1alpha1.TimeoutParameters{
+			ConnectionIdleTimeout: ptr.To("120s"),
+		},
+	}
+	updated.Spec.Policy = &contour_v1alpha1.PolicyConfig{
+		RouteIdentifierHeader: "X-Route-Id",
+	}
+	handler.OnUpdate(config, updated)
+	waitForSequence()
+
+	reloadedVersion := snapshotVersion()
+	assert.NotEqual(t, builtVersion, reloadedVersion)
+	assert.Equal(t, durationpb.New(120*time.Second), connectionIdleTimeout())
+	assert.Equal(t, "X-Route-Id", routeIdentifierHeader())
+
+	// Changing a field that requires a restart does not
+	// produce a new snapshot.
+	restart := updated.DeepCopy()
+	restart.Generation, restart.ResourceVersion = 3, "3"
+	restart.Spec.Envoy.HTTPListener = &contour_v1alpha1.EnvoyListener{Port: 9090}
+	handler.OnUpdate(updated, restart)
+	waitForSequence()
+
+	assert.Equal(t, reloadedVersion, snapshotVersion())
+}
+
+func TestLeaderElectionOptions(t *testing.T) {
+	tests := map[string]struct {
+		config  *contour_v1alpha1.LeaderElectionConfig
+		want    manager.Options
+		wantErr bool
+	}{
+		"defaults": {
+			want: manager.Options{
+				LeaderElection:                true,
+				LeaderElectionResourceLock:    "leases",
+				LeaderElectionNamespace:       "projectcontour",
+				LeaderElectionID:              "leader-elect",
+				LeaseDuration:                 ptr.To(15 * time.Second),
+				RenewDeadline:                 ptr.To(10 * time.Second),
+				RetryPeriod:                   ptr.To(2 * time.Second),
+				LeaderElectionReleaseOnCancel: true,
+			},
+		},
+		"durations from the configuration": {
+			config: &contour_v1alpha1.LeaderElectionConfig{
+				LeaseDuration: ptr.To("1m"),
+				RenewDeadline: ptr.To("45s"),
+				RetryPeriod:   ptr.To("5s"),
+				ResourceLock:  contour_v1alpha1.LeasesResourceLock,
+			},
+			want: manager.Options{
+				LeaderElection:                true,
+				LeaderElectionResourceLock:    "leases",
+				LeaderElectionNamespace:       "projectcontour",
+				LeaderElectionID:              "leader-elect",
+				LeaseDuration:                 ptr.To(time.Minute),
+				RenewDeadline:                 ptr.To(45 * time.Second),
+				RetryPeriod:                   ptr.To(5 * time.Second),
+				LeaderElectionReleaseOnCancel: true,
+			},
+		},
+		"unset durations keep their defaults": {
+			config: &contour_v1alpha1.LeaderElectionConfig{
+				LeaseDuration: ptr.To("30s"),
+			},
+			want: manager.Options{
+				LeaderElection:                true,
+				LeaderElectionResourceLock:    "leases",
+				LeaderElectionNamespace:       "projectcontour",
+				LeaderElectionID:              "leader-elect",
+				LeaseDuration:                 ptr.To(30 * time.Second),
+				RenewDeadline:                 ptr.To(10 * time.Second),
+				RetryPeriod:                   ptr.To(2 * time.Second),
+				LeaderElectionReleaseOnCancel: true,
+			},
+		},
+		"renew deadline not shorter than the lease duration": {
+			config: &contour_v1alpha1.LeaderElectionConfig{
+				LeaseDuration: ptr.To("10s"),
+				RenewDeadline: ptr.To("10s"),
+			},
+			wantErr: true,
+		},
+		"renew deadline longer than the default lease duration": {
+			config: &contour_v1alpha1.LeaderElectionConfig{
+				RenewDeadline: ptr.To("20s"),
+			},
+			wantErr: true,
+		},
+		"disabled": {
+			config: &contour_v1alpha1.LeaderElectionConfig{
+				Disable: ptr.To(true),
+			},
+			want: manager.Options{
+				LeaderElection: false,
+			},
+		},
+		"configmaps lock": {
+			config: &contour_v1alpha1.LeaderElectionConfig{
+				ResourceLock: "configmaps",
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			le := newServeContext().LeaderElection
+			le.Namespace = "projectcontour"
+			le.Name = "leader-elect"
+
+			err := le.apply(tc.config)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var got manager.Options
+			setLeaderElectionOptions(&got, le)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestStatusWrittenWithLeaderElectionDisabled(t *testing.T) {
+	ingress := &networking_v1.Ingress{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "default",
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(ingress).WithStatusSubresource(ingress).Build()
+
+	le := newServeContext().LeaderElection
+	require.NoError(t, le.apply(&contour_v1alpha1.LeaderElectionConfig{Disable: ptr.To(true)}))
+
+	options := manager.Options{
+		Metrics: controller_runtime_metrics_server.Options{
+			BindAddress: "0",
+		},
+	}
+	setLeaderElectionOptions(&options, le)
+
+	// There is no API server to hold a lease, so status is only
+	// written if the manager does not wait to be elected.
+	mgr, err := manager.New(&rest.Config{Host: "http://127.0.0.1:1"}, options)
+	require.NoError(t, err)
+
+	suh := k8s.NewStatusUpdateHandler(fixture.NewTestLogger(t), c, metrics.NewMetrics(prometheus.NewRegistry()), 0, 0)
+	require.NoError(t, mgr.Add(suh))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = mgr.Start(ctx)
+	}()
+
+	require.Eventually(t, func() bool {
+		suh.Writer().Send(k8s.NewStatusUpdate(ingress.Name, ingress.Namespace, &networking_v1.Ingress{},
+			k8s.StatusMutatorFunc(func(obj client.Object) client.Object {
+				i := obj.DeepCopyObject().(*networking_v1.Ingress)
+				i.Status.LoadBalancer.Ingress = []networking_v1.IngressLoadBalancerIngress{{IP: "1.1.1.1"}}
+				return i
+			})))
+
+		got := &networking_v1.Ingress{}
+		if err := c.Get(context.Background(), client.ObjectKeyFromObject(ingress), got); err != nil {
+			return false
+		}
+		return len(got.Status.LoadBalancer.Ingress) == 1
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestDrainOnSignal(t *testing.T) {
+	t.Run("drains before cancelling", func(t *testing.T) {
+		signals := make(chan os.Signal, 2)
+		var drained atomic.Bool
+		ctx := drainOnSignal(signals, 200*time.Millisecond, func() { drained.Store(true) }, fixture.NewTestLogger(t))
+
+		signals <- syscall.SIGTERM
+		require.Eventually(t, drained.Load, time.Second, 10*time.Millisecond)
+
+		// The context stays open while draining.
+		select {
+		case <-ctx.Done():
+			t.Fatal("context cancelled before the drain time elapsed")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("context not cancelled after the drain time elapsed")
+		}
+	})
+
+	t.Run("zero drain time cancels immediately", func(t *testing.T) {
+		signals := make(chan os.Signal, 2)
+		var drained atomic.Bool
+		ctx := drainOnSignal(signals, 0, func() { drained.Store(true) }, fixture.NewTestLogger(t))
+
+		assert.False(t, drained.Load())
+		signals <- syscall.SIGTERM
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("context not cancelled")
+		}
+		assert.True(t, drained.Load())
+	})
+
+	t.Run("second signal skips the drain", func(t *testing.T) {
+		signals := make(chan os.Signal, 2)
+		ctx := drainOnSignal(signals, time.Hour, func() {}, fixture.NewTestLogger(t))
+
+		signals <- syscall.SIGTERM
+		signals <- os.Interrupt
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("context not cancelled by the second signal")
+		}
+	})
+}
@@ -28,6 +28,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/utils/ptr"
 
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
@@ -53,6 +54,9 @@ type serveContext struct {
 	debugAddr string
 	debugPort int
 
+	// debugRebuild serves /debug/rebuild on the debug handler.
+	debugRebuild bool
+
 	// contour's metrics handler parameters
 	metricsAddr string
 	metricsPort int
@@ -64,9 +68,15 @@ type serveContext struct {
 	// httpproxy root namespaces
 	rootNamespaces string
 
+	// label selector for additional httpproxy root namespaces
+	rootNamespaceSelector string
+
 	// Watch only these namespaces to allow running with limited RBAC permissions.
 	watchNamespaces string
 
+	// Process only HTTPProxies, Ingresses and Gateways matching this label selector.
+	watchLabelSelector string
+
 	// ingress class
 	ingressClassName string
 
@@ -102,6 +112,12 @@ type ServerConfig struct {
 	xdsAddr                         string
 	xdsPort                         int
 	caFile, contourCert, contourKey string
+
+	// contour's xds service gRPC keepalive parameters
+	xdsKeepaliveTime                time.Duration
+	xdsKeepaliveTimeout             time.Duration
+	xdsKeepaliveMinTime             time.Duration
+	xdsKeepalivePermitWithoutStream bool
 }
 
 type LeaderElection struct {
@@ -109,10 +125,59 @@ type LeaderElection struct {
 	LeaseDuration time.Duration
 	RenewDeadline time.Duration
 	RetryPeriod   time.Duration
+	ResourceLock  contour_v1alpha1.LeaderElectionResourceLock
 	Namespace     string
 	Name          string
 }
 
+// config returns the leader election parameters as a LeaderElectionConfig.
+func (l *LeaderElection) config() *contour_v1alpha1.LeaderElectionConfig {
+	return &contour_v1alpha1.LeaderElectionConfig{
+		Disable:       ptr.To(l.Disable),
+		LeaseDuration: ptr.To(l.LeaseDuration.String()),
+		RenewDeadline: ptr.To(l.RenewDeadline.String()),
+		RetryPeriod:   ptr.To(l.RetryPeriod.String()),
+		ResourceLock:  l.ResourceLock,
+	}
+}
+
+// apply overrides the leader election parameters with those set in
+// the given LeaderElectionConfig, and validates the result.
+func (l *LeaderElection) apply(cfg *contour_v1alpha1.LeaderElectionConfig) error {
+	if cfg != nil {
+		if err := cfg.Validate(); err != nil {
+			return err
+		}
+
+		for _, setting := range []struct {
+			value *string
+			dest  *time.Duration
+		}{
+			{cfg.LeaseDuration, &l.LeaseDuration},
+			{cfg.RenewDeadline, &l.RenewDeadline},
+			{cfg.RetryPeriod, &l.RetryPeriod},
+		} {
+			if setting.value == nil {
+				continue
+			}
+			d, err := time.ParseDuration(*setting.value)
+			if err != nil {
+				return err
+			}
+			*setting.dest = d
+		}
+
+		if cfg.Disable != nil {
+			l.Disable = *cfg.Disable
+		}
+		if cfg.ResourceLock != "" {
+			l.ResourceLock = cfg.ResourceLock
+		}
+	}
+
+	return l.config().Validate()
+}
+
 // newServeContext returns a serveContext initialized to defaults.
 func newServeContext() *serveContext {
 	// Set defaults for parameters which are then overridden via flags, ENV, or ConfigFile
@@ -134,11 +199,21 @@ func newServeContext() *serveContext {
 		httpsPort:          8443,
 		PermitInsecureGRPC: false,
 		ServerConfig: ServerConfig{
-			xdsAddr:     "127.0.0.1",
-			xdsPort:     8001,
-			caFile:      "",
-			contourCert: "",
-			contourKey:  "",
+			xdsAddr:                         "127.0.0.1",
+			xdsPort:                         8001,
+			caFile:                          "",
+			contourCert:                     "",
+			contourKey:                      "",
+			xdsKeepaliveTime:                60 * time.Second,
+			xdsKeepaliveTimeout:             20 * time.Second,
+			xdsKeepaliveMinTime:             5 * time.Minute,
+			xdsKeepalivePermitWithoutStream: true,
+		},
+		LeaderElection: LeaderElection{
+			LeaseDuration: 15 * time.Second,
+			RenewDeadline: 10 * time.Second,
+			RetryPeriod:   2 * time.Second,
+			ResourceLock:  contour_v1alpha1.LeasesResourceLock,
 		},
 	}
 }
@@ -146,7 +221,12 @@ func newServeContext() *serveContext {
 // grpcOptions returns a slice of grpc.ServerOptions.
 // if ctx.PermitInsecureGRPC is false, the option set will
 // include TLS configuration.
-func grpcOptions(log logrus.FieldLogger, contourXDSConfig *contour_v1alpha1.TLS) []grpc.ServerOption {
+func grpcOptions(log logrus.FieldLogger, contourXDSConfig *contour_v1alpha1.TLS, keepaliveConfig *contour_v1alpha1.XDSServerKeepalive) ([]grpc.ServerOption, error) {
+	params, policy, err := grpcKeepalive(keepaliveConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	opts := []grpc.ServerOption{
 		// By default the Go grpc library defaults to a value of ~100 streams per
 		// connection. This number is likely derived from the HTTP/2 spec:
@@ -159,13 +239,8 @@ func grpcOptions(log logrus.FieldLogger, contourXDSConfig *contour_v1alpha1.TLS)
 		grpc.MaxConcurrentStreams(1 << 20),
 		// Set gRPC keepalive params.
 		// See https://github.com/projectcontour/contour/issues/1756 for background.
-		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
-			PermitWithoutStream: true,
-		}),
-		grpc.KeepaliveParams(keepalive.ServerParameters{
-			Time:    60 * time.Second,
-			Timeout: 20 * time.Second,
-		}),
+		grpc.KeepaliveEnforcementPolicy(policy),
+		grpc.KeepaliveParams(params),
 	}
 
 	if !ptr.Deref(contourXDSConfig.Insecure, false) {
@@ -173,7 +248,46 @@ func grpcOptions(log logrus.FieldLogger, contourXDSConfig *contour_v1alpha1.TLS)
 		creds := credentials.NewTLS(tlsconfig)
 		opts = append(opts, grpc.Creds(creds))
 	}
-	return opts
+	return opts, nil
+}
+
+// grpcKeepalive returns the gRPC keepalive parameters and enforcement
+// policy for the xDS server. Unset values keep Contour's defaults.
+func grpcKeepalive(k *contour_v1alpha1.XDSServerKeepalive) (keepalive.ServerParameters, keepalive.EnforcementPolicy, error) {
+	params := keepalive.ServerParameters{
+		Time:    60 * time.Second,
+		Timeout: 20 * time.Second,
+	}
+	policy := keepalive.EnforcementPolicy{
+		MinTime:             5 * time.Minute,
+		PermitWithoutStream: true,
+	}
+
+	if k == nil {
+		return params, policy, nil
+	}
+
+	for _, setting := range []struct {
+		name  string
+		value *string
+		dst   *time.Duration
+	}{
+		{"xds keepalive time", k.Time, &params.Time},
+		{"xds keepalive timeout", k.Timeout, &params.Timeout},
+		{"xds keepalive min time", k.MinTime, &policy.MinTime},
+	} {
+		if setting.value == nil {
+			continue
+		}
+		d, err := time.ParseDuration(*setting.value)
+		if err != nil {
+			return params, policy, fmt.Errorf("error parsing %s: %w", setting.name, err)
+		}
+		*setting.dst = d
+	}
+	policy.PermitWithoutStream = ptr.Deref(k.PermitWithoutStream, policy.PermitWithoutStream)
+
+	return params, policy, nil
 }
 
 // tlsconfig returns a new *tls.Config. If the TLS parameters passed are not properly configured
@@ -266,6 +380,16 @@ func (ctx *serveContext) watchedNamespaces() []string {
 	return ns
 }
 
+// watchedLabelSelector returns the parsed label selector restricting
+// the HTTPProxies, Ingresses and Gateways Contour processes, or nil if
+// none is set.
+func (ctx *serveContext) watchedLabelSelector() (labels.Selector, error) {
+	if strings.TrimSpace(ctx.watchLabelSelector) == "" {
+		return nil, nil
+	}
+	return labels.Parse(ctx.watchLabelSelector)
+}
+
 // parseDefaultHTTPVersions parses a list of supported HTTP versions
 // (of the form "HTTP/xx") into a slice of unique version constants.
 func parseDefaultHTTPVersions(versions []contour_v1alpha1.HTTPVersionType) []envoy_v3.HTTPVersionType {
@@ -431,6 +555,37 @@ func (ctx *serveContext) convertToContourConfigurationSpec() contour_v1alpha1.Co
 		serverHeaderTransformation = contour_v1alpha1.PassThroughServerHeader
 	}
 
+	var proxyProtocol *contour_v1alpha1.ProxyProtocolConfig
+	if pp := ctx.Config.Listener.ProxyProtocol; pp.AllowRequestsWithoutProxyProtocol || len(pp.Versions) > 0 {
+		proxyProtocol = &contour_v1alpha1.ProxyProtocolConfig{
+			AllowRequestsWithoutProxyProtocol: ptr.To(pp.AllowRequestsWithoutProxyProtocol),
+		}
+		for _, v := range pp.Versions {
+			proxyProtocol.Versions = append(proxyProtocol.Versions, contour_v1alpha1.ProxyProtocolVersion(v))
+		}
+	}
+
+	var listenerFiltersTimeout *string
+	if t := ctx.Config.Listener.ListenerFiltersTimeout; t != "" {
+		listenerFiltersTimeout = ptr.To(t)
+	}
+
+	var http2KeepaliveInterval, http2KeepaliveTimeout *string
+	if ctx.Config.Listener.HTTP2KeepaliveInterval != "" || ctx.Config.Listener.HTTP2KeepaliveTimeout != "" {
+		http2KeepaliveInterval = ptr.To(ctx.Config.Listener.HTTP2KeepaliveInterval)
+		http2KeepaliveTimeout = ptr.To(ctx.Config.Listener.HTTP2KeepaliveTimeout)
+	}
+
+	var http3 *contour_v1alpha1.HTTP3Config
+	if h := ctx.Config.Listener.HTTP3; h.Enabled || h.AdvertisedPort != 0 {
+		http3 = &contour_v1alpha1.HTTP3Config{
+			Enabled: ptr.To(h.Enabled),
+		}
+		if h.AdvertisedPort != 0 {
+			http3.AdvertisedPort = ptr.To(int32(h.AdvertisedPort)) //nolint:gosec // disable G115
+		}
+	}
+
 	var globalExtAuth *contour_v1.AuthorizationServer
 	if ctx.Config.GlobalExternalAuthorization.ExtensionService != "" {
 		nsedName := k8s.NamespacedNameFrom(ctx.Config.GlobalExternalAuthorization.ExtensionService)
@@ -468,7 +623,9 @@ func (ctx *serveContext) convertToContourConfigurationSpec() contour_v1alpha1.Co
 			Set:    ctx.Config.Policy.ResponseHeadersPolicy.Set,
 			Remove: ctx.Config.Policy.ResponseHeadersPolicy.Remove,
 		},
-		ApplyToIngress: ptr.To(ctx.Config.Policy.ApplyToIngress),
+		ApplyToIngress:        ptr.To(ctx.Config.Policy.ApplyToIngress),
+		ContourVersionHeader:  ptr.To(ctx.Config.Policy.ContourVersionHeader),
+		RouteIdentifierHeader: ctx.Config.Policy.RouteIdentifierHeader,
 	}
 
 	var clientCertificate *contour_v1alpha1.NamespacedName
@@ -479,6 +636,11 @@ func (ctx *serveContext) convertToContourConfigurationSpec() contour_v1alpha1.Co
 		}
 	}
 
+	var rootNamespaceSelector *string
+	if selector := strings.TrimSpace(ctx.rootNamespaceSelector); selector != "" {
+		rootNamespaceSelector = ptr.To(selector)
+	}
+
 	var fallbackCertificate *contour_v1alpha1.NamespacedName
 	if len(ctx.Config.TLS.FallbackCertificate.Name) > 0 {
 		fallbackCertificate = &contour_v1alpha1.NamespacedName{
@@ -507,6 +669,14 @@ func (ctx *serveContext) convertToContourConfigurationSpec() contour_v1alpha1.Co
 	setMetricsFromConfig(ctx.Config.Metrics.Contour, &contourMetrics)
 	setMetricsFromConfig(ctx.Config.Metrics.Envoy, &envoyMetrics)
 
+	var envoyStatsCollector *contour_v1alpha1.EnvoyStatsCollectorConfig
+	if ctx.Config.Metrics.EnvoyStats.PollInterval != "" {
+		envoyStatsCollector = &contour_v1alpha1.EnvoyStatsCollectorConfig{
+			URL:          ctx.Config.Metrics.EnvoyStats.URL,
+			PollInterval: ctx.Config.Metrics.EnvoyStats.PollInterval,
+		}
+	}
+
 	// Convert serveContext to a ContourConfiguration
 	contourConfiguration := contour_v1alpha1.ContourConfigurationSpec{
 		Ingress: ingress,
@@ -514,26 +684,42 @@ func (ctx *serveContext) convertToContourConfigurationSpec() contour_v1alpha1.Co
 			Address: ctx.debugAddr,
 			Port:    ctx.debugPort,
 		},
+		LeaderElection: ctx.LeaderElection.config(),
 		Health: &contour_v1alpha1.HealthConfig{
 			Address: ctx.healthAddr,
 			Port:    ctx.healthPort,
 		},
 		Envoy: &contour_v1alpha1.EnvoyConfig{
 			Listener: &contour_v1alpha1.EnvoyListenerConfig{
-				UseProxyProto:                 &ctx.useProxyProto,
-				DisableAllowChunkedLength:     &ctx.Config.DisableAllowChunkedLength,
-				DisableMergeSlashes:           &ctx.Config.DisableMergeSlashes,
-				ServerHeaderTransformation:    serverHeaderTransformation,
-				ConnectionBalancer:            ctx.Config.Listener.ConnectionBalancer,
-				PerConnectionBufferLimitBytes: ctx.Config.Listener.PerConnectionBufferLimitBytes,
-				MaxRequestsPerConnection:      ctx.Config.Listener.MaxRequestsPerConnection,
-				MaxRequestsPerIOCycle:         ctx.Config.Listener.MaxRequestsPerIOCycle,
-				HTTP2MaxConcurrentStreams:     ctx.Config.Listener.HTTP2MaxConcurrentStreams,
-				MaxConnectionsPerListener:     ctx.Config.Listener.MaxConnectionsPerListener,
+				UseProxyProto:                    &ctx.useProxyProto,
+				ProxyProtocol:                    proxyProtocol,
+				HTTP3:                            http3,
+				ListenerFiltersTimeout:           listenerFiltersTimeout,
+				ContinueOnListenerFiltersTimeout: &ctx.Config.Listener.ContinueOnListenerFiltersTimeout,
+				DisableAllowChunkedLength:        &ctx.Config.DisableAllowChunkedLength,
+				DisableMergeSlashes:              &ctx.Config.DisableMergeSlashes,
+				ServerHeaderTransformation:       serverHeaderTransformation,
+				ServerName:                       ctx.Config.ServerName,
+				UseRemoteAddress:                 ctx.Config.Listener.UseRemoteAddress,
+				Via:                              ctx.Config.Listener.Via,
+				ConnectionBalancer:               ctx.Config.Listener.ConnectionBalancer,
+				PerConnectionBufferLimitBytes:    ctx.Config.Listener.PerConnectionBufferLimitBytes,
+				MaxRequestsPerConnection:         ctx.Config.Listener.MaxRequestsPerConnection,
+				MaxRequestsPerIOCycle:            ctx.Config.Listener.MaxRequestsPerIOCycle,
+				HTTP2MaxConcurrentStreams:        ctx.Config.Listener.HTTP2MaxConcurrentStreams,
+				HTTP2KeepaliveInterval:           http2KeepaliveInterval,
+				HTTP2KeepaliveTimeout:            http2KeepaliveTimeout,
+				AllowAbsoluteURL:                 ctx.Config.Listener.AllowAbsoluteURL,
+				DisableAcceptHTTP10:              &ctx.Config.Listener.DisableAcceptHTTP10,
+				HTTP10DefaultHost:                ctx.Config.Listener.HTTP10DefaultHost,
+				MaxConnectionsPerListener:        ctx.Config.Listener.MaxConnectionsPerListener,
+				StripPortFromHost:                &ctx.Config.Listener.StripPortFromHost,
+				StripMatchingHostPort:            &ctx.Config.Listener.StripMatchingHostPort,
 				TLS: &contour_v1alpha1.EnvoyTLS{
 					MinimumProtocolVersion: ctx.Config.TLS.MinimumProtocolVersion,
 					MaximumProtocolVersion: ctx.Config.TLS.MaximumProtocolVersion,
 					CipherSuites:           cipherSuites,
+					DisableSessionTickets:  &ctx.Config.TLS.DisableSessionTickets,
 				},
 				SocketOptions: &contour_v1alpha1.SocketOptions{
 					TOS:          ctx.Config.Listener.SocketOptions.TOS,
@@ -583,11 +769,13 @@ func (ctx *serveContext) convertToContourConfigurationSpec() contour_v1alpha1.Co
 				XffNumTrustedHops: &ctx.Config.Network.XffNumTrustedHops,
 				EnvoyAdminPort:    &ctx.Config.Network.EnvoyAdminPort,
 			},
+			StatsCollector: envoyStatsCollector,
 		},
 		Gateway: gatewayConfig,
 		HTTPProxy: &contour_v1alpha1.HTTPProxyConfig{
 			DisablePermitInsecure: &ctx.Config.DisablePermitInsecure,
 			RootNamespaces:        ctx.proxyRootNamespaces(),
+			RootNamespaceSelector: rootNamespaceSelector,
 			FallbackCertificate:   fallbackCertificate,
 		},
 		EnableExternalNameService:   &ctx.Config.EnableExternalNameService,
@@ -604,16 +792,36 @@ func (ctx *serveContext) convertToContourConfigurationSpec() contour_v1alpha1.Co
 		xdsServerType = contour_v1alpha1.EnvoyServerType
 	}
 
+	var xdsResourceTTL *string
+	if ctx.Config.Server.XDSResourceTTL != "" {
+		xdsResourceTTL = ptr.To(ctx.Config.Server.XDSResourceTTL)
+	}
+
+	var shutdownDrainTime *string
+	if ctx.Config.Server.ShutdownDrainTime != "" {
+		shutdownDrainTime = ptr.To(ctx.Config.Server.ShutdownDrainTime)
+	}
+
 	contourConfiguration.XDSServer = &contour_v1alpha1.XDSServerConfig{
 		Type:    xdsServerType,
 		Address: ctx.xdsAddr,
 		Port:    ctx.xdsPort,
+		Delta:   &ctx.Config.Server.XDSDelta,
+
+		ResourceTTL:       xdsResourceTTL,
+		ShutdownDrainTime: shutdownDrainTime,
 		TLS: &contour_v1alpha1.TLS{
 			CAFile:   ctx.caFile,
 			CertFile: ctx.contourCert,
 			KeyFile:  ctx.contourKey,
 			Insecure: &ctx.PermitInsecureGRPC,
 		},
+		Keepalive: &contour_v1alpha1.XDSServerKeepalive{
+			Time:                ptr.To(ctx.xdsKeepaliveTime.String()),
+			Timeout:             ptr.To(ctx.xdsKeepaliveTimeout.String()),
+			MinTime:             ptr.To(ctx.xdsKeepaliveMinTime.String()),
+			PermitWithoutStream: ptr.To(ctx.xdsKeepalivePermitWithoutStream),
+		},
 	}
 
 	return contourConfiguration
@@ -25,12 +25,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/alecthomas/kingpin/v2"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tsaarni/certyaml"
 	"golang.org/x/net/http2"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 	"k8s.io/utils/ptr"
 
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
@@ -87,6 +89,50 @@ func TestServeContextProxyRootNamespaces(t *testing.T) {
 	}
 }
 
+func TestServeContextWatchedLabelSelector(t *testing.T) {
+	tests := map[string]struct {
+		selector string
+		want     string
+		wantErr  bool
+	}{
+		"empty": {
+			selector: "",
+		},
+		"blank-ish": {
+			selector: " \t ",
+		},
+		"equality": {
+			selector: "shard=a",
+			want:     "shard=a",
+		},
+		"set based": {
+			selector: "shard in (a,b),!canary",
+			want:     "!canary,shard in (a,b)",
+		},
+		"invalid": {
+			selector: "shard in a",
+			wantErr:  true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := serveContext{watchLabelSelector: tc.selector}
+			got, err := ctx.watchedLabelSelector()
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tc.want == "" {
+				assert.Nil(t, got)
+				return
+			}
+			assert.Equal(t, tc.want, got.String())
+		})
+	}
+}
+
 func TestServeContextTLSParams(t *testing.T) {
 	tests := map[string]struct {
 		tls         *contour_v1alpha1.TLS
@@ -207,7 +253,8 @@ func TestServeContextCertificateHandling(t *testing.T) {
 
 	// Start a dummy server.
 	log := fixture.NewTestLogger(t)
-	opts := grpcOptions(log, contourTLS)
+	opts, err := grpcOptions(log, contourTLS, nil)
+	require.NoError(t, err)
 	g := grpc.NewServer(opts...)
 	require.NotNil(t, g)
 
@@ -364,16 +411,55 @@ func TestParseHTTPVersions(t *testing.T) {
 	}
 }
 
+func TestServeContextXDSKeepaliveFlags(t *testing.T) {
+	tests := map[string]struct {
+		args       []string
+		wantParams keepalive.ServerParameters
+		wantPolicy keepalive.EnforcementPolicy
+	}{
+		"defaults": {
+			args:       []string{"serve"},
+			wantParams: keepalive.ServerParameters{Time: 60 * time.Second, Timeout: 20 * time.Second},
+			wantPolicy: keepalive.EnforcementPolicy{MinTime: 5 * time.Minute, PermitWithoutStream: true},
+		},
+		"all keepalive flags set": {
+			args: []string{
+				"serve",
+				"--xds-keepalive-time=30s",
+				"--xds-keepalive-timeout=5s",
+				"--xds-keepalive-min-time=10s",
+				"--no-xds-keepalive-permit-without-stream",
+			},
+			wantParams: keepalive.ServerParameters{Time: 30 * time.Second, Timeout: 5 * time.Second},
+			wantPolicy: keepalive.EnforcementPolicy{MinTime: 10 * time.Second, PermitWithoutStream: false},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			app := kingpin.New("contour_xds_keepalive_test", "")
+			_, ctx := registerServe(app)
+
+			_, err := app.Parse(tc.args)
+			require.NoError(t, err)
+
+			spec := ctx.convertToContourConfigurationSpec()
+			require.NoError(t, spec.Validate())
+
+			params, policy, err := grpcKeepalive(spec.XDSServer.Keepalive)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantParams, params)
+			assert.Equal(t, tc.wantPolicy, policy)
+		})
+	}
+}
+
 func TestConvertServeContext(t *testing.T) {
 	defaultContext := func() *serveContext {
 		ctx := newServeContext()
-		ctx.ServerConfig = ServerConfig{
-			xdsAddr:     "127.0.0.1",
-			xdsPort:     8001,
-			caFile:      "/certs/ca.crt",
-			contourCert: "/certs/cert.crt",
-			contourKey:  "/certs/cert.key",
-		}
+		ctx.ServerConfig.caFile = "/certs/ca.crt"
+		ctx.ServerConfig.contourCert = "/certs/cert.crt"
+		ctx.ServerConfig.contourKey = "/certs/cert.key"
 		return ctx
 	}
 
@@ -383,12 +469,19 @@ func TestConvertServeContext(t *testing.T) {
 				Type:    contour_v1alpha1.EnvoyServerType,
 				Address: "127.0.0.1",
 				Port:    8001,
+				Delta:   ptr.To(false),
 				TLS: &contour_v1alpha1.TLS{
 					CAFile:   "/certs/ca.crt",
 					CertFile: "/certs/cert.crt",
 					KeyFile:  "/certs/cert.key",
 					Insecure: ptr.To(false),
 				},
+				Keepalive: &contour_v1alpha1.XDSServerKeepalive{
+					Time:                ptr.To("1m0s"),
+					Timeout:             ptr.To("20s"),
+					MinTime:             ptr.To("5m0s"),
+					PermitWithoutStream: ptr.To(true),
+				},
 			},
 			Ingress: &contour_v1alpha1.IngressConfig{
 				ClassNames:    nil,
@@ -398,6 +491,13 @@ func TestConvertServeContext(t *testing.T) {
 				Address: "127.0.0.1",
 				Port:    6060,
 			},
+			LeaderElection: &contour_v1alpha1.LeaderElectionConfig{
+				Disable:       ptr.To(false),
+				LeaseDuration: ptr.To("15s"),
+				RenewDeadline: ptr.To("10s"),
+				RetryPeriod:   ptr.To("2s"),
+				ResourceLock:  contour_v1alpha1.LeasesResourceLock,
+			},
 			Health: &contour_v1alpha1.HealthConfig{
 				Address: "0.0.0.0",
 				Port:    8000,
@@ -408,13 +508,18 @@ func TestConvertServeContext(t *testing.T) {
 					Namespace: "projectcontour",
 				},
 				Listener: &contour_v1alpha1.EnvoyListenerConfig{
-					UseProxyProto:              ptr.To(false),
-					DisableAllowChunkedLength:  ptr.To(false),
-					DisableMergeSlashes:        ptr.To(false),
-					ServerHeaderTransformation: contour_v1alpha1.OverwriteServerHeader,
+					UseProxyProto:                    ptr.To(false),
+					ContinueOnListenerFiltersTimeout: ptr.To(false),
+					DisableAllowChunkedLength:        ptr.To(false),
+					DisableAcceptHTTP10:              ptr.To(false),
+					DisableMergeSlashes:              ptr.To(false),
+					StripPortFromHost:                ptr.To(false),
+					StripMatchingHostPort:            ptr.To(false),
+					ServerHeaderTransformation:       contour_v1alpha1.OverwriteServerHeader,
 					TLS: &contour_v1alpha1.EnvoyTLS{
 						MinimumProtocolVersion: "",
 						MaximumProtocolVersion: "",
+						DisableSessionTickets:  ptr.To(false),
 					},
 					SocketOptions: &contour_v1alpha1.SocketOptions{
 						TOS:          0,
@@ -500,6 +605,7 @@ func TestConvertServeContext(t *testing.T) {
 				RequestHeadersPolicy:  &contour_v1alpha1.HeadersPolicy{},
 				ResponseHeadersPolicy: &contour_v1alpha1.HeadersPolicy{},
 				ApplyToIngress:        ptr.To(false),
+				ContourVersionHeader:  ptr.To(false),
 			},
 			Metrics: &contour_v1alpha1.MetricsConfig{
 				Address: "0.0.0.0",
@@ -531,7 +637,8 @@ func TestConvertServeContext(t *testing.T) {
 						Set:    map[string]string{"custom-response-header-set": "foo-bar", "Host": "response-bar.com"},
 						Remove: []string{"custom-response-header-remove"},
 					},
-					ApplyToIngress: true,
+					ApplyToIngress:       true,
+					ContourVersionHeader: true,
 				}
 				return ctx
 			},
@@ -545,7 +652,8 @@ func TestConvertServeContext(t *testing.T) {
 						Set:    map[string]string{"custom-response-header-set": "foo-bar", "Host": "response-bar.com"},
 						Remove: []string{"custom-response-header-remove"},
 					},
-					ApplyToIngress: ptr.To(true),
+					ApplyToIngress:       ptr.To(true),
+					ContourVersionHeader: ptr.To(true),
 				}
 				return cfg
 			},
@@ -756,6 +864,60 @@ func TestConvertServeContext(t *testing.T) {
 				return cfg
 			},
 		},
+		"strip port from host": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.Listener.StripPortFromHost = true
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_v1alpha1.ContourConfigurationSpec) contour_v1alpha1.ContourConfigurationSpec {
+				cfg.Envoy.Listener.StripPortFromHost = ptr.To(true)
+				return cfg
+			},
+		},
+		"proxy protocol": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.useProxyProto = true
+				ctx.Config.Listener.ProxyProtocol = config.ProxyProtocolParameters{
+					AllowRequestsWithoutProxyProtocol: true,
+					Versions:                          []string{"v2"},
+				}
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_v1alpha1.ContourConfigurationSpec) contour_v1alpha1.ContourConfigurationSpec {
+				cfg.Envoy.Listener.UseProxyProto = ptr.To(true)
+				cfg.Envoy.Listener.ProxyProtocol = &contour_v1alpha1.ProxyProtocolConfig{
+					AllowRequestsWithoutProxyProtocol: ptr.To(true),
+					Versions:                          []contour_v1alpha1.ProxyProtocolVersion{contour_v1alpha1.ProxyProtocolVersion2},
+				}
+				return cfg
+			},
+		},
+		"disable session tickets": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.TLS.DisableSessionTickets = true
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_v1alpha1.ContourConfigurationSpec) contour_v1alpha1.ContourConfigurationSpec {
+				cfg.Envoy.Listener.TLS.DisableSessionTickets = ptr.To(true)
+				return cfg
+			},
+		},
+		"http3": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.Listener.HTTP3 = config.HTTP3Parameters{
+					Enabled:        true,
+					AdvertisedPort: 8443,
+				}
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_v1alpha1.ContourConfigurationSpec) contour_v1alpha1.ContourConfigurationSpec {
+				cfg.Envoy.Listener.HTTP3 = &contour_v1alpha1.HTTP3Config{
+					Enabled:        ptr.To(true),
+					AdvertisedPort: ptr.To(int32(8443)),
+				}
+				return cfg
+			},
+		},
 		"server header transformation": {
 			getServeContext: func(ctx *serveContext) *serveContext {
 				ctx.Config.ServerHeaderTransformation = config.AppendIfAbsentServerHeader
@@ -766,6 +928,142 @@ func TestConvertServeContext(t *testing.T) {
 				return cfg
 			},
 		},
+		"server name": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.ServerName = "example"
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_v1alpha1.ContourConfigurationSpec) contour_v1alpha1.ContourConfigurationSpec {
+				cfg.Envoy.Listener.ServerName = "example"
+				return cfg
+			},
+		},
+		"root namespace selector": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.rootNamespaceSelector = "tenant=true"
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_v1alpha1.ContourConfigurationSpec) contour_v1alpha1.ContourConfigurationSpec {
+				cfg.HTTPProxy.RootNamespaceSelector = ptr.To("tenant=true")
+				return cfg
+			},
+		},
+		"xds resource ttl": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.Server.XDSResourceTTL = "60s"
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_v1alpha1.ContourConfigurationSpec) contour_v1alpha1.ContourConfigurationSpec {
+				cfg.XDSServer.ResourceTTL = ptr.To("60s")
+				return cfg
+			},
+		},
+		"leader election": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.LeaderElection.LeaseDuration = time.Minute
+				ctx.LeaderElection.RenewDeadline = 45 * time.Second
+				ctx.LeaderElection.RetryPeriod = 5 * time.Second
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_v1alpha1.ContourConfigurationSpec) contour_v1alpha1.ContourConfigurationSpec {
+				cfg.LeaderElection = &contour_v1alpha1.LeaderElectionConfig{
+					Disable:       ptr.To(false),
+					LeaseDuration: ptr.To("1m0s"),
+					RenewDeadline: ptr.To("45s"),
+					RetryPeriod:   ptr.To("5s"),
+					ResourceLock:  contour_v1alpha1.LeasesResourceLock,
+				}
+				return cfg
+			},
+		},
+		"leader election disabled": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.LeaderElection.Disable = true
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_v1alpha1.ContourConfigurationSpec) contour_v1alpha1.ContourConfigurationSpec {
+				cfg.LeaderElection = &contour_v1alpha1.LeaderElectionConfig{
+					Disable:       ptr.To(true),
+					LeaseDuration: ptr.To("15s"),
+					RenewDeadline: ptr.To("10s"),
+					RetryPeriod:   ptr.To("2s"),
+					ResourceLock:  contour_v1alpha1.LeasesResourceLock,
+				}
+				return cfg
+			},
+		},
+		"shutdown drain time": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.Server.ShutdownDrainTime = "45s"
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_v1alpha1.ContourConfigurationSpec) contour_v1alpha1.ContourConfigurationSpec {
+				cfg.XDSServer.ShutdownDrainTime = ptr.To("45s")
+				return cfg
+			},
+		},
+		"listener filters timeout": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.Listener.ListenerFiltersTimeout = "30s"
+				ctx.Config.Listener.ContinueOnListenerFiltersTimeout = true
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_v1alpha1.ContourConfigurationSpec) contour_v1alpha1.ContourConfigurationSpec {
+				cfg.Envoy.Listener.ListenerFiltersTimeout = ptr.To("30s")
+				cfg.Envoy.Listener.ContinueOnListenerFiltersTimeout = ptr.To(true)
+				return cfg
+			},
+		},
+		"http/1 protocol options": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.Listener.AllowAbsoluteURL = ptr.To(true)
+				ctx.Config.Listener.HTTP10DefaultHost = "example.com"
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_v1alpha1.ContourConfigurationSpec) contour_v1alpha1.ContourConfigurationSpec {
+				cfg.Envoy.Listener.AllowAbsoluteURL = ptr.To(true)
+				cfg.Envoy.Listener.HTTP10DefaultHost = "example.com"
+				return cfg
+			},
+		},
+		"route identifier header": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.Policy.RouteIdentifierHeader = "x-contour-route"
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_v1alpha1.ContourConfigurationSpec) contour_v1alpha1.ContourConfigurationSpec {
+				cfg.Policy.RouteIdentifierHeader = "x-contour-route"
+				return cfg
+			},
+		},
+		"envoy stats collector": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.Metrics.EnvoyStats = config.EnvoyStatsParameters{
+					URL:          "http://envoy:8002/stats",
+					PollInterval: "30s",
+				}
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_v1alpha1.ContourConfigurationSpec) contour_v1alpha1.ContourConfigurationSpec {
+				cfg.Envoy.StatsCollector = &contour_v1alpha1.EnvoyStatsCollectorConfig{
+					URL:          "http://envoy:8002/stats",
+					PollInterval: "30s",
+				}
+				return cfg
+			},
+		},
+		"use remote address and via": {
+			getServeContext: func(ctx *serveContext) *serveContext {
+				ctx.Config.Listener.UseRemoteAddress = ptr.To(false)
+				ctx.Config.Listener.Via = "1.1 contour"
+				return ctx
+			},
+			getContourConfiguration: func(cfg contour_v1alpha1.ContourConfigurationSpec) contour_v1alpha1.ContourConfigurationSpec {
+				cfg.Envoy.Listener.UseRemoteAddress = ptr.To(false)
+				cfg.Envoy.Listener.Via = "1.1 contour"
+				return cfg
+			},
+		},
 		"global circuit breaker defaults": {
 			getServeContext: func(ctx *serveContext) *serveContext {
 				ctx.Config.Cluster.GlobalCircuitBreakerDefaults = &contour_v1alpha1.CircuitBreakers{
@@ -893,12 +1191,16 @@ func TestConvertServeContext(t *testing.T) {
 				ctx.Config.Listener.MaxRequestsPerIOCycle = ptr.To(uint32(10))
 				ctx.Config.Listener.HTTP2MaxConcurrentStreams = ptr.To(uint32(30))
 				ctx.Config.Listener.MaxConnectionsPerListener = ptr.To(uint32(50))
+				ctx.Config.Listener.HTTP2KeepaliveInterval = "30s"
+				ctx.Config.Listener.HTTP2KeepaliveTimeout = "5s"
 				return ctx
 			},
 			getContourConfiguration: func(cfg contour_v1alpha1.ContourConfigurationSpec) contour_v1alpha1.ContourConfigurationSpec {
 				cfg.Envoy.Listener.MaxRequestsPerIOCycle = ptr.To(uint32(10))
 				cfg.Envoy.Listener.HTTP2MaxConcurrentStreams = ptr.To(uint32(30))
 				cfg.Envoy.Listener.MaxConnectionsPerListener = ptr.To(uint32(50))
+				cfg.Envoy.Listener.HTTP2KeepaliveInterval = ptr.To("30s")
+				cfg.Envoy.Listener.HTTP2KeepaliveTimeout = ptr.To("5s")
 				return cfg
 			},
 		},
@@ -65,6 +65,11 @@ type shutdownContext struct {
 	// drainDelay defines time to wait before draining Envoy connections
 	drainDelay time.Duration
 
+	// drainTime defines the maximum time to wait for Envoy connections
+	// to drain after failing its healthchecks. Zero waits until
+	// minOpenConnections is reached.
+	drainTime time.Duration
+
 	// minOpenConnections defines the minimum amount of connections
 	// that can be open when polling for active connections in Envoy
 	minOpenConnections int
@@ -95,10 +100,19 @@ func newShutdownContext() *shutdownContext {
 		checkInterval:      5 * time.Second,
 		checkDelay:         0,
 		drainDelay:         0,
+		drainTime:          0,
 		minOpenConnections: 0,
 	}
 }
 
+// validate returns an error if the shutdown flags are out of range.
+func (s *shutdownContext) validate() error {
+	if s.drainTime < 0 {
+		return fmt.Errorf("drain time %s must not be negative", s.drainTime)
+	}
+	return nil
+}
+
 // healthzHandler handles the /healthz endpoint which is used for the shutdown-manager's liveness probe.
 func (s *shutdownmanagerContext) healthzHandler(w http.ResponseWriter, _ *http.Request) {
 	if _, err := w.Write([]byte(http.StatusText(http.StatusOK))); err != nil {
@@ -169,10 +183,25 @@ func (s *shutdownContext) shutdownHandler() {
 		s.WithField("context", "shutdownHandler").Errorf("error sending envoy healthcheck fail after 4 attempts: %v", err)
 	}
 
+	// Envoy's listeners are draining from here on, so start the drain
+	// time now rather than after the check delay.
+	var drainDeadline time.Time
+	if s.drainTime > 0 {
+		drainDeadline = time.Now().Add(s.drainTime)
+	}
+
 	s.WithField("context", "shutdownHandler").Infof("waiting %s before polling for draining connections", s.checkDelay)
 	time.Sleep(s.checkDelay)
 
 	for {
+		if !drainDeadline.IsZero() && !time.Now().Before(drainDeadline) {
+			s.WithField("context", "shutdownHandler").
+				WithField("drain_time", s.drainTime).
+				Info("drain time elapsed, shutting down")
+			s.writeShutdownReadyFile()
+			return
+		}
+
 		openConnections, err := getOpenConnections(s.adminAddress)
 		if err != nil {
 			s.Error(err)
@@ -182,11 +211,7 @@ func (s *shutdownContext) shutdownHandler() {
 					WithField("open_connections", openConnections).
 					WithField("min_connections", s.minOpenConnections).
 					Info("min number of open connections found, shutting down")
-				file, err := os.Create(s.shutdownReadyFile)
-				if err != nil {
-					s.Error(err)
-				}
-				defer file.Close()
+				s.writeShutdownReadyFile()
 				return
 			}
 			s.WithField("context", "shutdownHandler").
@@ -198,6 +223,16 @@ func (s *shutdownContext) shutdownHandler() {
 	}
 }
 
+// writeShutdownReadyFile signals to the shutdown-manager that Envoy can terminate.
+func (s *shutdownContext) writeShutdownReadyFile() {
+	file, err := os.Create(s.shutdownReadyFile)
+	if err != nil {
+		s.Error(err)
+		return
+	}
+	file.Close()
+}
+
 // shutdownEnvoy sends a POST request to /healthcheck/fail to tell Envoy to start draining connections
 func shutdownEnvoy(adminAddress string) error {
 	httpClient := http.Client{
@@ -316,6 +351,7 @@ func registerShutdown(cmd *kingpin.CmdClause, log logrus.FieldLogger) (*kingpin.
 	shutdown.Flag("check-delay", "Time to wait before polling Envoy for open connections.").Default("0s").DurationVar(&ctx.checkDelay)
 	shutdown.Flag("check-interval", "Time to poll Envoy for open connections.").DurationVar(&ctx.checkInterval)
 	shutdown.Flag("drain-delay", "Time to wait before draining Envoy connections.").Default("0s").DurationVar(&ctx.drainDelay)
+	shutdown.Flag("drain-time", "Max time to wait for Envoy connections to drain after failing healthchecks, 0 waits for min-open-connections.").Default("0s").DurationVar(&ctx.drainTime)
 	shutdown.Flag("min-open-connections", "Min number of open connections when polling Envoy.").IntVar(&ctx.minOpenConnections)
 	shutdown.Flag("ready-file", "File to write when shutdown is completed.").Default(shutdownReadyFile).StringVar(&ctx.shutdownReadyFile)
 
@@ -17,15 +17,18 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/projectcontour/contour/internal/fixture"
 )
@@ -134,6 +137,65 @@ func TestShutdownManager_ShutdownReadyHandler_ClientCancel(t *testing.T) {
 	handler.ServeHTTP(rr, req)
 }
 
+func TestShutdownHandler_DrainTime(t *testing.T) {
+	tmpdir, err := os.MkdirTemp("", "shutdown-*")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	// Fake Envoy admin interface whose connections never drain.
+	var healthcheckFailed atomic.Bool
+	admin := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/healthcheck/fail":
+			healthcheckFailed.Store(true)
+		case "/stats/prometheus":
+			if !healthcheckFailed.Load() {
+				t.Error("polled open connections before failing healthchecks")
+			}
+			_, _ = io.WriteString(w, VALIDHTTP)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	admin.Listener, err = net.Listen("unix", path.Join(tmpdir, "admin.sock"))
+	require.NoError(t, err)
+	admin.Start()
+	defer admin.Close()
+
+	s := newShutdownContext()
+	s.FieldLogger = fixture.NewTestLogger(t)
+	s.adminAddress = path.Join(tmpdir, "admin.sock")
+	s.shutdownReadyFile = path.Join(tmpdir, "ok")
+	s.checkInterval = 10 * time.Millisecond
+	s.drainTime = 100 * time.Millisecond
+	require.NoError(t, s.validate())
+
+	start := time.Now()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.shutdownHandler()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown did not finish after the drain time")
+	}
+
+	assert.True(t, healthcheckFailed.Load())
+	assert.GreaterOrEqual(t, time.Since(start), s.drainTime)
+	assert.FileExists(t, s.shutdownReadyFile)
+}
+
+func TestShutdownContext_Validate(t *testing.T) {
+	s := newShutdownContext()
+	require.NoError(t, s.validate())
+
+	s.drainTime = -time.Second
+	require.Error(t, s.validate())
+}
+
 func TestParseOpenConnections(t *testing.T) {
 	type testcase struct {
 		stats           io.Reader
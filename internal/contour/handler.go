@@ -18,6 +18,7 @@ package contour
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"sync/atomic"
 	"time"
@@ -27,6 +28,7 @@ import (
 	"k8s.io/client-go/tools/cache/synctrack"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 	"github.com/projectcontour/contour/internal/dag"
 	"github.com/projectcontour/contour/internal/k8s"
 )
@@ -37,6 +39,7 @@ type EventHandlerConfig struct {
 	Observer                      dag.Observer
 	HoldoffDelay, HoldoffMaxDelay time.Duration
 	StatusUpdater                 k8s.StatusUpdater
+	ConfigReloader                *ConfigReloader
 }
 
 // EventHandler implements cache.ResourceEventHandler, filters k8s events towards
@@ -50,10 +53,16 @@ type EventHandler struct {
 
 	statusUpdater k8s.StatusUpdater
 
+	configReloader *ConfigReloader
+
 	logrus.FieldLogger
 
 	update chan any
 
+	// rebuild receives requests to rebuild the DAG immediately. The
+	// result of each rebuild is sent on the request's channel.
+	rebuild chan chan error
+
 	sequence chan int
 
 	// seq is the sequence counter of the number of times
@@ -76,7 +85,9 @@ func NewEventHandler(config EventHandlerConfig, upstreamHasSynced cache.Informer
 		holdoffDelay:    config.HoldoffDelay,
 		holdoffMaxDelay: config.HoldoffMaxDelay,
 		statusUpdater:   config.StatusUpdater,
+		configReloader:  config.ConfigReloader,
 		update:          make(chan any),
+		rebuild:         make(chan chan error),
 		sequence:        make(chan int, 1),
 		syncTracker:     &synctrack.SingleFileTracker{UpstreamHasSynced: upstreamHasSynced},
 	}
@@ -126,6 +137,30 @@ func (e *EventHandler) OnElectedLeader() {
 	e.update <- true
 }
 
+// ErrCacheNotSynced is returned by Rebuild when the informer caches
+// have not yet synced, so a DAG cannot be built.
+var ErrCacheNotSynced = errors.New("informer caches are not synced")
+
+// Rebuild rebuilds the DAG immediately, without waiting for the holdoff
+// timer, and sends it to the Observer. It returns once the rebuild has
+// completed, or the context is done.
+func (e *EventHandler) Rebuild(ctx context.Context) error {
+	result := make(chan error, 1)
+
+	select {
+	case e.rebuild <- result:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (e *EventHandler) Start(ctx context.Context) error {
 	e.Info("started event handler")
 	defer e.Info("stopped event handler")
@@ -213,17 +248,25 @@ func (e *EventHandler) Start(ctx context.Context) error {
 
 			e.WithField("last_update", time.Since(lastDAGRebuild)).WithField("outstanding", reset()).Info("performing delayed update")
 
-			// Build a new DAG and sends it to the Observer.
-			latestDAG := e.builder.Build()
-			e.observer.OnChange(latestDAG)
+			e.rebuildDAG()
+			lastDAGRebuild = time.Now()
+		case result := <-e.rebuild:
+			if !e.syncTracker.HasSynced() {
+				result <- ErrCacheNotSynced
+				break
+			}
+
+			e.WithField("last_update", time.Since(lastDAGRebuild)).WithField("outstanding", reset()).Info("performing requested update")
 
-			// Update the status on objects.
-			for _, upd := range latestDAG.StatusCache.GetStatusUpdates() {
-				e.statusUpdater.Send(upd)
+			// Outstanding events are included in this rebuild.
+			if timer != nil {
+				timer.Stop()
+				pending = nil
 			}
 
-			e.incSequence()
+			e.rebuildDAG()
 			lastDAGRebuild = time.Now()
+			result <- nil
 		case <-initialSyncPoll:
 			if e.syncTracker.HasSynced() {
 				// Informer caches are synced, stop the polling and allow xDS server to start.
@@ -237,12 +280,29 @@ func (e *EventHandler) Start(ctx context.Context) error {
 	}
 }
 
+// rebuildDAG builds a new DAG, sends it to the Observer and
+// updates the status on objects.
+func (e *EventHandler) rebuildDAG() {
+	latestDAG := e.builder.Build()
+	e.observer.OnChange(latestDAG)
+
+	// Update the status on objects.
+	for _, upd := range latestDAG.StatusCache.GetStatusUpdates() {
+		e.statusUpdater.Send(upd)
+	}
+
+	e.incSequence()
+}
+
 // onUpdate processes the event received. onUpdate returns
 // true if the event changed the cache in a way that requires
 // notifying the Observer.
 func (e *EventHandler) onUpdate(op any) bool {
 	switch op := op.(type) {
 	case opAdd:
+		if config, ok := op.obj.(*contour_v1alpha1.ContourConfiguration); ok {
+			return e.configReloader.Reload(config)
+		}
 		return e.builder.Source.Insert(op.obj)
 	case opUpdate:
 		oldO, oldOk := op.oldObj.(client.Object)
@@ -263,6 +323,9 @@ func (e *EventHandler) onUpdate(op any) bool {
 					WithField("gvk", reflect.TypeOf(newO)).Debugf("skipping update, no changes to relevant fields")
 				return false
 			}
+			if config, ok := newO.(*contour_v1alpha1.ContourConfiguration); ok {
+				return e.configReloader.Reload(config)
+			}
 			remove := e.builder.Source.Remove(op.oldObj)
 			insert := e.builder.Source.Insert(op.newObj)
 			return remove || insert
@@ -271,6 +334,10 @@ func (e *EventHandler) onUpdate(op any) bool {
 		e.WithField("op", "update").Errorf("%T skipping update, object is not a client.Object", op.newObj)
 		return false
 	case opDelete:
+		if _, ok := op.obj.(*contour_v1alpha1.ContourConfiguration); ok {
+			// Keep running with the current configuration.
+			return false
+		}
 		return e.builder.Source.Remove(op.obj)
 	case bool:
 		return op
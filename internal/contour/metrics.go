@@ -17,9 +17,14 @@
 package contour
 
 import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
 
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
@@ -59,11 +64,21 @@ func (e *EventRecorder) recordOperation(op string, obj any) {
 	e.Counter.WithLabelValues(op, kind).Inc()
 }
 
+// certificateExpiryRefreshInterval is how often the TLS certificate expiry
+// buckets are recomputed between DAG rebuilds, as certificates move into
+// them while the DAG does not change.
+const certificateExpiryRefreshInterval = time.Minute
+
 // RebuildMetricsObserver is a dag.Observer that emits metrics for DAG rebuilds.
+// When leader, it also records Events for HTTPProxies that become invalid.
 type RebuildMetricsObserver struct {
 	// Metrics to emit.
 	metrics *metrics.Metrics
 
+	// proxyEvents records Events for HTTPProxies that become invalid.
+	// It may be nil, in which case no Events are recorded.
+	proxyEvents *status.ProxyEventRecorder
+
 	// httpProxyMetricsEnabled will become ready to read when this EventHandler becomes
 	// the leader. If httpProxyMetricsEnabled is not readable, or nil, status events will
 	// be suppressed.
@@ -71,11 +86,17 @@ type RebuildMetricsObserver struct {
 
 	// NextObserver contains the stack of dag.Observers that act on DAG rebuilds.
 	nextObserver dag.Observer
+
+	// certificateExpiries holds the expiry times of the TLS certificates
+	// referenced by the last DAG.
+	certificateExpiriesLock sync.Mutex
+	certificateExpiries     []time.Time
 }
 
-func NewRebuildMetricsObserver(metrics *metrics.Metrics, nextObserver dag.Observer) *RebuildMetricsObserver {
+func NewRebuildMetricsObserver(metrics *metrics.Metrics, proxyEvents *status.ProxyEventRecorder, nextObserver dag.Observer) *RebuildMetricsObserver {
 	return &RebuildMetricsObserver{
 		metrics:                 metrics,
+		proxyEvents:             proxyEvents,
 		nextObserver:            nextObserver,
 		httpProxyMetricsEnabled: make(chan struct{}),
 	}
@@ -88,6 +109,10 @@ func (m *RebuildMetricsObserver) OnElectedLeader() {
 func (m *RebuildMetricsObserver) OnChange(d *dag.DAG) {
 	m.metrics.SetDAGLastRebuilt(time.Now())
 	m.metrics.SetDAGRebuiltTotal()
+	m.certificateExpiriesLock.Lock()
+	m.certificateExpiries = certificateExpiries(d)
+	m.certificateExpiriesLock.Unlock()
+	m.refreshCertificateExpiryMetric(time.Now())
 
 	timer := prometheus.NewTimer(m.metrics.CacheHandlerOnUpdateSummary)
 	m.nextObserver.OnChange(d)
@@ -95,11 +120,97 @@ func (m *RebuildMetricsObserver) OnChange(d *dag.DAG) {
 
 	select {
 	case <-m.httpProxyMetricsEnabled:
-		m.metrics.SetHTTPProxyMetric(calculateRouteMetric(d.StatusCache.GetProxyUpdates()))
+		proxyUpdates := d.StatusCache.GetProxyUpdates()
+		m.metrics.SetHTTPProxyMetric(calculateRouteMetric(proxyUpdates))
+		m.metrics.SetHTTPProxyInvalidReasonMetric(status.InvalidProxyReasons(proxyUpdates))
+		m.proxyEvents.Record(proxyUpdates)
 	default:
 	}
 }
 
+// NeedLeaderElection is included to implement manager.LeaderElectionRunnable
+func (m *RebuildMetricsObserver) NeedLeaderElection() bool {
+	return false
+}
+
+// Start refreshes the TLS certificate expiry metric every
+// certificateExpiryRefreshInterval until the context is done.
+func (m *RebuildMetricsObserver) Start(ctx context.Context) error {
+	ticker := time.NewTicker(certificateExpiryRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			m.refreshCertificateExpiryMetric(now)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// refreshCertificateExpiryMetric buckets the certificate expiry times
+// of the last DAG relative to now.
+func (m *RebuildMetricsObserver) refreshCertificateExpiryMetric(now time.Time) {
+	m.certificateExpiriesLock.Lock()
+	defer m.certificateExpiriesLock.Unlock()
+
+	m.metrics.SetTLSCertificateExpiringMetric(calculateCertificateExpiryMetric(m.certificateExpiries, now))
+}
+
+// certificateExpiries returns the expiry times of the leaf certificates
+// of the distinct TLS secrets referenced by secure virtual hosts.
+func certificateExpiries(d *dag.DAG) []time.Time {
+	secrets := map[types.NamespacedName]*dag.Secret{}
+	for _, listener := range d.Listeners {
+		for _, svhost := range listener.SecureVirtualHosts {
+			for _, secret := range []*dag.Secret{svhost.Secret, svhost.FallbackCertificate} {
+				if secret == nil || secret.Object == nil {
+					continue
+				}
+				secrets[types.NamespacedName{Namespace: secret.Namespace(), Name: secret.Name()}] = secret
+			}
+		}
+	}
+
+	var expiries []time.Time
+	for _, secret := range secrets {
+		if notAfter, ok := certificateNotAfter(secret.Cert()); ok {
+			expiries = append(expiries, notAfter)
+		}
+	}
+	return expiries
+}
+
+// calculateCertificateExpiryMetric counts the certificate expiry times
+// that fall within each of the metrics.TLSCertificateExpiryBuckets
+// windows from now.
+func calculateCertificateExpiryMetric(expiries []time.Time, now time.Time) map[string]int {
+	counts := map[string]int{}
+	for _, notAfter := range expiries {
+		for bucket, window := range metrics.TLSCertificateExpiryBuckets {
+			if notAfter.Before(now.Add(window)) {
+				counts[bucket]++
+			}
+		}
+	}
+	return counts
+}
+
+// certificateNotAfter returns the expiry time of the first certificate
+// in the supplied PEM bundle.
+func certificateNotAfter(data []byte) (time.Time, bool) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return time.Time{}, false
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return cert.NotAfter, true
+}
+
 func calculateRouteMetric(updates []*status.ProxyUpdate) metrics.RouteMetric {
 	proxyMetricTotal := make(map[metrics.Meta]int)
 	proxyMetricValid := make(map[metrics.Meta]int)
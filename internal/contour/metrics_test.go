@@ -15,8 +15,11 @@ package contour
 
 import (
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	core_v1 "k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -24,6 +27,7 @@ import (
 	"github.com/projectcontour/contour/internal/dag"
 	"github.com/projectcontour/contour/internal/fixture"
 	"github.com/projectcontour/contour/internal/metrics"
+	"github.com/projectcontour/contour/pkg/certs"
 )
 
 func TestHTTPProxyMetrics(t *testing.T) {
@@ -510,3 +514,139 @@ func TestHTTPProxyMetrics(t *testing.T) {
 		},
 	})
 }
+
+func TestHTTPProxyInvalidReasonMetric(t *testing.T) {
+	builder := dag.Builder{
+		Source: dag.KubernetesCache{
+			FieldLogger: fixture.NewTestLogger(t),
+		},
+		Processors: []dag.Processor{
+			&dag.ListenerProcessor{},
+			&dag.HTTPProxyProcessor{},
+		},
+	}
+
+	// invalid is invalid because its service does not exist.
+	builder.Source.Insert(&contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "invalid",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: "missing",
+					Port: 8080,
+				}},
+			}},
+		},
+	})
+
+	// orphaned is not included by any root.
+	builder.Source.Insert(&contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "orphaned",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: "missing",
+					Port: 8080,
+				}},
+			}},
+		},
+	})
+
+	registry := prometheus.NewRegistry()
+	observer := NewRebuildMetricsObserver(metrics.NewMetrics(registry), nil, dag.ObserverFunc(func(*dag.DAG) {}))
+	observer.OnElectedLeader()
+	observer.OnChange(builder.Build())
+
+	got := map[string]float64{}
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	for _, mf := range families {
+		if mf.GetName() != metrics.HTTPProxyInvalidReasonGauge {
+			continue
+		}
+		for _, m := range mf.Metric {
+			got[m.Label[0].GetValue()] = m.GetGauge().GetValue()
+		}
+	}
+
+	assert.Equal(t, map[string]float64{
+		"ServiceUnresolvedReference": 1,
+		"Orphaned":                   1,
+	}, got)
+
+	// A rebuild without invalid proxies removes the stale series.
+	empty := dag.Builder{
+		Source: dag.KubernetesCache{
+			FieldLogger: fixture.NewTestLogger(t),
+		},
+	}
+	observer.OnChange(empty.Build())
+
+	families, err = registry.Gather()
+	require.NoError(t, err)
+	for _, mf := range families {
+		assert.NotEqual(t, metrics.HTTPProxyInvalidReasonGauge, mf.GetName())
+	}
+}
+
+func TestCertificateExpiryMetric(t *testing.T) {
+	secret := func(name string, lifetime uint) *dag.Secret {
+		t.Helper()
+		generated, err := certs.GenerateCerts(&certs.Configuration{Lifetime: lifetime})
+		require.NoError(t, err)
+		return &dag.Secret{
+			Object: &core_v1.Secret{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Namespace: "default",
+					Name:      name,
+				},
+				Type: core_v1.SecretTypeTLS,
+				Data: map[string][]byte{
+					core_v1.TLSCertKey:       generated.EnvoyCertificate,
+					core_v1.TLSPrivateKeyKey: generated.EnvoyPrivateKey,
+				},
+			},
+		}
+	}
+
+	expiringSoon := secret("expiring-soon", 3)
+	expiringThisMonth := secret("expiring-this-month", 20)
+	longLived := secret("long-lived", 365)
+
+	d := &dag.DAG{
+		Listeners: map[string]*dag.Listener{
+			"https": {
+				SecureVirtualHosts: []*dag.SecureVirtualHost{
+					{Secret: expiringSoon},
+					// A secret shared between virtual hosts is only counted once.
+					{Secret: expiringSoon},
+					{Secret: expiringThisMonth, FallbackCertificate: longLived},
+					{Secret: longLived},
+				},
+			},
+		},
+	}
+
+	expiries := certificateExpiries(d)
+	assert.Len(t, expiries, 3)
+
+	assert.Equal(t, map[string]int{
+		"7d":  1,
+		"30d": 2,
+	}, calculateCertificateExpiryMetric(expiries, time.Now()))
+
+	// Without a DAG rebuild, certificates move into the buckets as time passes.
+	assert.Equal(t, map[string]int{
+		"7d":  3,
+		"30d": 3,
+	}, calculateCertificateExpiryMetric(expiries, time.Now().Add(400*24*time.Hour)))
+}
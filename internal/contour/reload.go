@@ -0,0 +1,166 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contour
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/types"
+
+	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
+	"github.com/projectcontour/contour/internal/contourconfig"
+	"github.com/projectcontour/contour/internal/k8s"
+)
+
+// ConfigReloader applies changes to the reloadable fields of a
+// ContourConfiguration while Contour is running. The reloadable
+// fields are the Envoy timeouts, the Envoy access log settings and
+// the global header policies. Changes to any other field are logged
+// as requiring a restart and otherwise ignored.
+type ConfigReloader struct {
+	// Name is the ContourConfiguration resource Contour was started with.
+	Name types.NamespacedName
+
+	// Apply applies the reloadable fields of the given configuration,
+	// which has been overlaid on the defaults and validated, to the
+	// running components. It is called from the event handler before
+	// the next DAG rebuild.
+	Apply func(contour_v1alpha1.ContourConfigurationSpec) error
+
+	// current is the configuration Contour is running with.
+	current contour_v1alpha1.ContourConfigurationSpec
+
+	logrus.FieldLogger
+}
+
+// NewConfigReloader returns a ConfigReloader for the named
+// ContourConfiguration, which is currently running with the
+// given configuration.
+func NewConfigReloader(
+	name types.NamespacedName,
+	current contour_v1alpha1.ContourConfigurationSpec,
+	apply func(contour_v1alpha1.ContourConfigurationSpec) error,
+	log logrus.FieldLogger,
+) *ConfigReloader {
+	return &ConfigReloader{
+		Name:        name,
+		Apply:       apply,
+		current:     *current.DeepCopy(),
+		FieldLogger: log,
+	}
+}
+
+// Reload applies any changes to the reloadable fields of the given
+// ContourConfiguration. It returns true if the changes were applied
+// and the DAG needs to be rebuilt.
+func (r *ConfigReloader) Reload(config *contour_v1alpha1.ContourConfiguration) bool {
+	if r == nil || k8s.NamespacedNameOf(config) != r.Name {
+		return false
+	}
+
+	log := r.WithField("name", config.Name).WithField("namespace", config.Namespace)
+
+	desired, err := contourconfig.OverlayOnDefaults(config.Spec)
+	if err != nil {
+		log.WithError(err).Error("failed to apply defaults to updated configuration, ignoring update")
+		return false
+	}
+
+	if err := desired.Validate(); err != nil {
+		log.WithError(err).Error("invalid updated configuration, ignoring update")
+		return false
+	}
+
+	if fields := restartRequiredFields(r.current, desired); len(fields) > 0 {
+		log.WithField("fields", fields).Warn("configuration changes require a restart of Contour to take effect")
+	}
+
+	if reflect.DeepEqual(reloadableFieldsOf(r.current), reloadableFieldsOf(desired)) {
+		return false
+	}
+
+	if err := r.Apply(desired); err != nil {
+		log.WithError(err).Error("failed to reload configuration")
+		return false
+	}
+
+	// Only the reloadable fields have been applied, so keep comparing
+	// other fields against the configuration Contour was started with.
+	r.current.Envoy.Timeouts = desired.Envoy.Timeouts
+	r.current.Envoy.Logging = desired.Envoy.Logging
+	r.current.Policy = desired.Policy
+
+	log.Info("reloaded configuration")
+
+	return true
+}
+
+type reloadableFields struct {
+	timeouts *contour_v1alpha1.TimeoutParameters
+	logging  *contour_v1alpha1.EnvoyLogging
+	policy   *contour_v1alpha1.PolicyConfig
+}
+
+func reloadableFieldsOf(spec contour_v1alpha1.ContourConfigurationSpec) reloadableFields {
+	return reloadableFields{
+		timeouts: spec.Envoy.Timeouts,
+		logging:  spec.Envoy.Logging,
+		policy:   spec.Policy,
+	}
+}
+
+// restartRequiredFields returns the JSON paths of the fields
+// that differ between the two configurations and cannot be
+// reloaded.
+func restartRequiredFields(current, desired contour_v1alpha1.ContourConfigurationSpec) []string {
+	// Blank out the reloadable fields so they do not show up as differences.
+	current.Envoy = current.Envoy.DeepCopy()
+	desired.Envoy = desired.Envoy.DeepCopy()
+	current.Envoy.Timeouts, desired.Envoy.Timeouts = nil, nil
+	current.Envoy.Logging, desired.Envoy.Logging = nil, nil
+	current.Policy, desired.Policy = nil, nil
+
+	var fields []string
+
+	currentValue, desiredValue := reflect.ValueOf(current), reflect.ValueOf(desired)
+	for i := 0; i < currentValue.NumField(); i++ {
+		name := jsonName(currentValue.Type().Field(i))
+
+		if name == "envoy" {
+			currentEnvoy, desiredEnvoy := currentValue.Field(i).Elem(), desiredValue.Field(i).Elem()
+			for j := 0; j < currentEnvoy.NumField(); j++ {
+				if !reflect.DeepEqual(currentEnvoy.Field(j).Interface(), desiredEnvoy.Field(j).Interface()) {
+					fields = append(fields, "envoy."+jsonName(currentEnvoy.Type().Field(j)))
+				}
+			}
+			continue
+		}
+
+		if !reflect.DeepEqual(currentValue.Field(i).Interface(), desiredValue.Field(i).Interface()) {
+			fields = append(fields, name)
+		}
+	}
+
+	return fields
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
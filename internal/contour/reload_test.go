@@ -0,0 +1,202 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contour
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	logrus_test "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
+	"github.com/projectcontour/contour/internal/contourconfig"
+)
+
+func TestConfigReloader(t *testing.T) {
+	name := types.NamespacedName{Namespace: "projectcontour", Name: "contour"}
+
+	newConfig := func(spec contour_v1alpha1.ContourConfigurationSpec) *contour_v1alpha1.ContourConfiguration {
+		return &contour_v1alpha1.ContourConfiguration{
+			ObjectMeta: meta_v1.ObjectMeta{Namespace: name.Namespace, Name: name.Name},
+			Spec:       spec,
+		}
+	}
+
+	setup := func() (*ConfigReloader, *[]contour_v1alpha1.ContourConfigurationSpec, *logrus_test.Hook) {
+		log, hook := logrus_test.NewNullLogger()
+
+		var applied []contour_v1alpha1.ContourConfigurationSpec
+		r := NewConfigReloader(name, contourconfig.Defaults(), func(spec contour_v1alpha1.ContourConfigurationSpec) error {
+			applied = append(applied, spec)
+			return nil
+		}, log)
+
+		return r, &applied, hook
+	}
+
+	t.Run("unchanged configuration is not reloaded", func(t *testing.T) {
+		r, applied, hook := setup()
+
+		assert.False(t, r.Reload(newConfig(contour_v1alpha1.ContourConfigurationSpec{})))
+		assert.Empty(t, *applied)
+		assert.Empty(t, hook.AllEntries())
+	})
+
+	t.Run("reloadable fields are applied", func(t *testing.T) {
+		r, applied, hook := setup()
+
+		spec := contour_v1alpha1.ContourConfigurationSpec{
+			Envoy: &contour_v1alpha1.EnvoyConfig{
+				Timeouts: &contour_v1alpha1.TimeoutParameters{
+					RequestTimeout: ptr.To("30s"),
+				},
+				Logging: &contour_v1alpha1.EnvoyLogging{
+					AccessLogFormat: contour_v1alpha1.JSONAccessLog,
+				},
+			},
+			Policy: &contour_v1alpha1.PolicyConfig{
+				RequestHeadersPolicy: &contour_v1alpha1.HeadersPolicy{
+					Set: map[string]string{"X-Env": "staging"},
+				},
+			},
+		}
+
+		require.True(t, r.Reload(newConfig(spec)))
+		require.Len(t, *applied, 1)
+		assert.Equal(t, "30s", *(*applied)[0].Envoy.Timeouts.RequestTimeout)
+		assert.Equal(t, contour_v1alpha1.JSONAccessLog, (*applied)[0].Envoy.Logging.AccessLogFormat)
+		assert.Equal(t, map[string]string{"X-Env": "staging"}, (*applied)[0].Policy.RequestHeadersPolicy.Set)
+
+		// Defaults are filled in before the configuration is applied.
+		assert.Equal(t, contourconfig.Defaults().Envoy.Timeouts.ConnectTimeout, (*applied)[0].Envoy.Timeouts.ConnectTimeout)
+
+		require.Len(t, hook.AllEntries(), 1)
+		assert.Equal(t, "reloaded configuration", hook.LastEntry().Message)
+
+		// Applying the same configuration again is a no-op.
+		assert.False(t, r.Reload(newConfig(spec)))
+		assert.Len(t, *applied, 1)
+	})
+
+	t.Run("unsafe fields require a restart", func(t *testing.T) {
+		r, applied, hook := setup()
+
+		spec := contour_v1alpha1.ContourConfigurationSpec{
+			Envoy: &contour_v1alpha1.EnvoyConfig{
+				HTTPListener: &contour_v1alpha1.EnvoyListener{
+					Port: 9090,
+				},
+			},
+			EnableExternalNameService: ptr.To(true),
+		}
+
+		assert.False(t, r.Reload(newConfig(spec)))
+		assert.Empty(t, *applied)
+
+		require.Len(t, hook.AllEntries(), 1)
+		assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+		assert.Equal(t, []string{"envoy.http", "enableExternalNameService"}, hook.LastEntry().Data["fields"])
+	})
+
+	t.Run("mixed changes apply the reloadable fields", func(t *testing.T) {
+		r, applied, hook := setup()
+
+		spec := contour_v1alpha1.ContourConfigurationSpec{
+			Envoy: &contour_v1alpha1.EnvoyConfig{
+				HTTPListener: &contour_v1alpha1.EnvoyListener{
+					Port: 9090,
+				},
+				Timeouts: &contour_v1alpha1.TimeoutParameters{
+					ConnectTimeout: ptr.To("5s"),
+				},
+			},
+		}
+
+		assert.True(t, r.Reload(newConfig(spec)))
+		assert.Len(t, *applied, 1)
+
+		require.Len(t, hook.AllEntries(), 2)
+		assert.Equal(t, []string{"envoy.http"}, hook.AllEntries()[0].Data["fields"])
+
+		// The unsafe change is still reported on later updates.
+		hook.Reset()
+		assert.False(t, r.Reload(newConfig(spec)))
+		require.Len(t, hook.AllEntries(), 1)
+		assert.Equal(t, []string{"envoy.http"}, hook.LastEntry().Data["fields"])
+	})
+
+	t.Run("invalid configuration is ignored", func(t *testing.T) {
+		r, applied, hook := setup()
+
+		spec := contour_v1alpha1.ContourConfigurationSpec{
+			Envoy: &contour_v1alpha1.EnvoyConfig{
+				Logging: &contour_v1alpha1.EnvoyLogging{
+					AccessLogFormat: "xml",
+				},
+			},
+		}
+
+		assert.False(t, r.Reload(newConfig(spec)))
+		assert.Empty(t, *applied)
+		require.Len(t, hook.AllEntries(), 1)
+		assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+	})
+
+	t.Run("apply errors are not recorded", func(t *testing.T) {
+		log, hook := logrus_test.NewNullLogger()
+		r := NewConfigReloader(name, contourconfig.Defaults(), func(contour_v1alpha1.ContourConfigurationSpec) error {
+			return errors.New("boom")
+		}, log)
+
+		spec := contour_v1alpha1.ContourConfigurationSpec{
+			Envoy: &contour_v1alpha1.EnvoyConfig{
+				Timeouts: &contour_v1alpha1.TimeoutParameters{
+					RequestTimeout: ptr.To("30s"),
+				},
+			},
+		}
+
+		assert.False(t, r.Reload(newConfig(spec)))
+		assert.Equal(t, "failed to reload configuration", hook.LastEntry().Message)
+
+		// The change is retried on the next update.
+		r.Apply = func(contour_v1alpha1.ContourConfigurationSpec) error { return nil }
+		assert.True(t, r.Reload(newConfig(spec)))
+	})
+
+	t.Run("other resources are ignored", func(t *testing.T) {
+		r, applied, _ := setup()
+
+		config := newConfig(contour_v1alpha1.ContourConfigurationSpec{
+			Envoy: &contour_v1alpha1.EnvoyConfig{
+				Timeouts: &contour_v1alpha1.TimeoutParameters{
+					RequestTimeout: ptr.To("30s"),
+				},
+			},
+		})
+		config.Name = "other"
+
+		assert.False(t, r.Reload(config))
+		assert.Empty(t, *applied)
+
+		var nilReloader *ConfigReloader
+		assert.False(t, nilReloader.Reload(config))
+	})
+}
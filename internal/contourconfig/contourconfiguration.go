@@ -44,6 +44,7 @@ func Defaults() contour_v1alpha1.ContourConfigurationSpec {
 			Type:    contour_v1alpha1.EnvoyServerType,
 			Address: "0.0.0.0",
 			Port:    8001,
+			Delta:   ptr.To(false),
 			TLS: &contour_v1alpha1.TLS{
 				CAFile:   "/certs/ca.crt",
 				CertFile: "/certs/tls.crt",
@@ -59,21 +60,33 @@ func Defaults() contour_v1alpha1.ContourConfigurationSpec {
 			Address: "127.0.0.1",
 			Port:    6060,
 		},
+		LeaderElection: &contour_v1alpha1.LeaderElectionConfig{
+			Disable:       ptr.To(false),
+			LeaseDuration: ptr.To("15s"),
+			RenewDeadline: ptr.To("10s"),
+			RetryPeriod:   ptr.To("2s"),
+			ResourceLock:  contour_v1alpha1.LeasesResourceLock,
+		},
 		Health: &contour_v1alpha1.HealthConfig{
 			Address: "0.0.0.0",
 			Port:    8000,
 		},
 		Envoy: &contour_v1alpha1.EnvoyConfig{
 			Listener: &contour_v1alpha1.EnvoyListenerConfig{
-				UseProxyProto:              ptr.To(false),
-				DisableAllowChunkedLength:  ptr.To(false),
-				DisableMergeSlashes:        ptr.To(false),
-				ServerHeaderTransformation: contour_v1alpha1.OverwriteServerHeader,
-				ConnectionBalancer:         "",
+				UseProxyProto:                    ptr.To(false),
+				ContinueOnListenerFiltersTimeout: ptr.To(false),
+				DisableAllowChunkedLength:        ptr.To(false),
+				DisableAcceptHTTP10:              ptr.To(false),
+				DisableMergeSlashes:              ptr.To(false),
+				StripPortFromHost:                ptr.To(false),
+				StripMatchingHostPort:            ptr.To(false),
+				ServerHeaderTransformation:       contour_v1alpha1.OverwriteServerHeader,
+				ConnectionBalancer:               "",
 				TLS: &contour_v1alpha1.EnvoyTLS{
 					MinimumProtocolVersion: "1.2",
 					MaximumProtocolVersion: "1.3",
 					CipherSuites:           contour_v1alpha1.DefaultTLSCiphers,
+					DisableSessionTickets:  ptr.To(false),
 				},
 			},
 			Service: &contour_v1alpha1.NamespacedName{
@@ -136,6 +149,7 @@ func Defaults() contour_v1alpha1.ContourConfigurationSpec {
 		HTTPProxy: &contour_v1alpha1.HTTPProxyConfig{
 			DisablePermitInsecure: ptr.To(false),
 			RootNamespaces:        nil,
+			RootNamespaceSelector: nil,
 			FallbackCertificate:   nil,
 		},
 		EnableExternalNameService: ptr.To(false),
@@ -144,6 +158,7 @@ func Defaults() contour_v1alpha1.ContourConfigurationSpec {
 			RequestHeadersPolicy:  &contour_v1alpha1.HeadersPolicy{},
 			ResponseHeadersPolicy: &contour_v1alpha1.HeadersPolicy{},
 			ApplyToIngress:        ptr.To(false),
+			ContourVersionHeader:  ptr.To(false),
 		},
 		Metrics: &contour_v1alpha1.MetricsConfig{
 			Address: "0.0.0.0",
@@ -33,6 +33,7 @@ func TestOverlayOnDefaults(t *testing.T) {
 			Type:    contour_v1alpha1.EnvoyServerType,
 			Address: "7.7.7.7",
 			Port:    7777,
+			Delta:   ptr.To(true),
 			TLS: &contour_v1alpha1.TLS{
 				CAFile:   "/foo/ca.crt",
 				CertFile: "/foo/tls.crt",
@@ -48,19 +49,36 @@ func TestOverlayOnDefaults(t *testing.T) {
 			Address: "1.2.3.4",
 			Port:    6789,
 		},
+		LeaderElection: &contour_v1alpha1.LeaderElectionConfig{
+			Disable:       ptr.To(true),
+			LeaseDuration: ptr.To("60s"),
+			RenewDeadline: ptr.To("45s"),
+			RetryPeriod:   ptr.To("5s"),
+			ResourceLock:  contour_v1alpha1.LeasesResourceLock,
+		},
 		Health: &contour_v1alpha1.HealthConfig{
 			Address: "2.3.4.5",
 			Port:    8888,
 		},
 		Envoy: &contour_v1alpha1.EnvoyConfig{
 			Listener: &contour_v1alpha1.EnvoyListenerConfig{
-				UseProxyProto:              ptr.To(true),
-				DisableAllowChunkedLength:  ptr.To(true),
-				DisableMergeSlashes:        ptr.To(true),
-				MaxRequestsPerConnection:   ptr.To(uint32(1)),
-				HTTP2MaxConcurrentStreams:  ptr.To(uint32(10)),
-				ServerHeaderTransformation: contour_v1alpha1.PassThroughServerHeader,
-				ConnectionBalancer:         "yesplease",
+				UseProxyProto:                    ptr.To(true),
+				ListenerFiltersTimeout:           ptr.To("30s"),
+				ContinueOnListenerFiltersTimeout: ptr.To(true),
+				DisableAllowChunkedLength:        ptr.To(true),
+				DisableAcceptHTTP10:              ptr.To(true),
+				AllowAbsoluteURL:                 ptr.To(true),
+				DisableMergeSlashes:              ptr.To(true),
+				StripPortFromHost:                ptr.To(true),
+				StripMatchingHostPort:            ptr.To(true),
+				MaxRequestsPerConnection:         ptr.To(uint32(1)),
+				HTTP2MaxConcurrentStreams:        ptr.To(uint32(10)),
+				HTTP2KeepaliveInterval:           ptr.To("30s"),
+				HTTP2KeepaliveTimeout:            ptr.To("5s"),
+				ServerHeaderTransformation:       contour_v1alpha1.PassThroughServerHeader,
+				ConnectionBalancer:               "yesplease",
+				UseRemoteAddress:                 ptr.To(false),
+				Via:                              "1.1 contour",
 				TLS: &contour_v1alpha1.EnvoyTLS{
 					MinimumProtocolVersion: "1.7",
 					MaximumProtocolVersion: "1.7",
@@ -68,6 +86,7 @@ func TestOverlayOnDefaults(t *testing.T) {
 						"foo",
 						"bar",
 					},
+					DisableSessionTickets: ptr.To(true),
 				},
 			},
 			Service: &contour_v1alpha1.NamespacedName{
@@ -134,6 +153,10 @@ func TestOverlayOnDefaults(t *testing.T) {
 				XffNumTrustedHops: ptr.To(uint32(77)),
 				EnvoyAdminPort:    ptr.To(9997),
 			},
+			StatsCollector: &contour_v1alpha1.EnvoyStatsCollectorConfig{
+				URL:          "http://envoy:8002/stats",
+				PollInterval: "10s",
+			},
 		},
 		Gateway: &contour_v1alpha1.GatewayConfig{
 			GatewayRef: contour_v1alpha1.NamespacedName{
@@ -182,7 +205,8 @@ func TestOverlayOnDefaults(t *testing.T) {
 				Set:    map[string]string{"set": "val"},
 				Remove: []string{"remove"},
 			},
-			ApplyToIngress: ptr.To(true),
+			ApplyToIngress:       ptr.To(true),
+			ContourVersionHeader: ptr.To(true),
 		},
 		Metrics: &contour_v1alpha1.MetricsConfig{
 			Address: "9.8.7.6",
@@ -39,7 +39,7 @@ func (d *DAG) EnsureService(meta types.NamespacedName, port, healthPort int, cac
 	if healthPort != 0 && healthPort != port {
 		_, healthSvcPort, err = cache.LookupService(meta, intstr.FromInt(healthPort))
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("health port: %w", err)
 		}
 	}
 
@@ -258,6 +258,26 @@ func (d *DAG) GetClusters() []*Cluster {
 	return res
 }
 
+// GetFailoverTCPProxies returns the TCPProxies in the DAG
+// that fail over between their clusters.
+func (d *DAG) GetFailoverTCPProxies() []*TCPProxy {
+	var res []*TCPProxy
+
+	for _, listener := range d.Listeners {
+		if listener.TCPProxy != nil && listener.TCPProxy.Failover {
+			res = append(res, listener.TCPProxy)
+		}
+
+		for _, vhost := range listener.SecureVirtualHosts {
+			if vhost.TCPProxy != nil && vhost.TCPProxy.Failover {
+				res = append(res, vhost.TCPProxy)
+			}
+		}
+	}
+
+	return res
+}
+
 func (d *DAG) GetDNSNameClusters() []*DNSNameCluster {
 	var res []*DNSNameCluster
 
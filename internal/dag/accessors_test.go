@@ -15,6 +15,7 @@ package dag
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -169,7 +170,11 @@ func TestBuilderLookupService(t *testing.T) {
 		"when service does not exist an error is returned": {
 			NamespacedName: types.NamespacedName{Name: "nonexistent-service", Namespace: "default"},
 			port:           8080,
-			wantErr:        errors.New(`service "default/nonexistent-service" not found`),
+			wantErr: NotFoundError{
+				Kind:  "Service",
+				Name:  types.NamespacedName{Name: "nonexistent-service", Namespace: "default"},
+				error: errors.New(`service "default/nonexistent-service" not found`),
+			},
 		},
 		"when service port does not exist an error is returned": {
 			NamespacedName: types.NamespacedName{Name: "service1", Namespace: "default"},
@@ -186,7 +191,7 @@ func TestBuilderLookupService(t *testing.T) {
 			NamespacedName: types.NamespacedName{Name: "servicehealthcheck", Namespace: "default"},
 			port:           8080,
 			healthPort:     8999,
-			wantErr:        errors.New(`port "8999" on service "default/servicehealthcheck" not matched`),
+			wantErr:        fmt.Errorf("health port: %w", errors.New(`port "8999" on service "default/servicehealthcheck" not matched`)),
 		},
 		"When ExternalName Services are not disabled no error is returned": {
 			NamespacedName: types.NamespacedName{Name: "externalnamevalid", Namespace: "default"},
@@ -19,7 +19,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	core_v1 "k8s.io/api/core/v1"
 	networking_v1 "k8s.io/api/networking/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,6 +36,7 @@ import (
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	"github.com/projectcontour/contour/internal/fixture"
 	"github.com/projectcontour/contour/internal/gatewayapi"
+	"github.com/projectcontour/contour/internal/metrics"
 	"github.com/projectcontour/contour/internal/status"
 	"github.com/projectcontour/contour/internal/timeout"
 )
@@ -6915,6 +6918,20 @@ func TestDAGInsert(t *testing.T) {
 		},
 	}
 
+	// s1c is like s1 but has an additional health check port
+	s1c := &core_v1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: core_v1.ServiceSpec{
+			Ports: []core_v1.ServicePort{
+				makeServicePort("http", "TCP", 8080, 8080),
+				makeServicePort("health", "TCP", 8081, 8081),
+			},
+		},
+	}
+
 	// s2 is like s1 but with a different name
 	s2 := &core_v1.Service{
 		ObjectMeta: meta_v1.ObjectMeta{
@@ -7189,6 +7206,33 @@ func TestDAGInsert(t *testing.T) {
 		},
 	}
 
+	// proxy1i tcp forwards secure traffic to default/kuard:8080, failing
+	// over to default/kuarder:8080 when kuard has no healthy endpoints.
+	proxy1i := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "kuard-tcp",
+			Namespace: s1.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "kuard.example.com",
+				TLS: &contour_v1.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &contour_v1.TCPProxy{
+				Services: []contour_v1.Service{{
+					Name:             s2.Name,
+					Port:             8080,
+					FailoverPriority: 1,
+				}, {
+					Name: s1.Name,
+					Port: 8080,
+				}},
+			},
+		},
+	}
+
 	// proxy1e tcp forwards secure traffic to default/kuard:8080 by TLS pass-through it,
 	// insecure traffic is not 301 upgraded because of the permitInsecure: true annotation.
 	proxy1e := &contour_v1.HTTPProxy{
@@ -7324,6 +7368,119 @@ func TestDAGInsert(t *testing.T) {
 		},
 	}
 
+	proxy2g := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "example-com",
+			Namespace: "kubesystem",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Includes: []contour_v1.Include{{
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/kuard",
+				}, {
+					QueryParameter: &contour_v1.QueryParameterMatchCondition{
+						Name:  "version",
+						Exact: "v2",
+					},
+				}},
+				Name:      "kuard",
+				Namespace: "default",
+			}},
+		},
+	}
+
+	proxy2h := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			Routes: []contour_v1.Route{{
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/",
+				}, {
+					QueryParameter: &contour_v1.QueryParameterMatchCondition{
+						Name:    "debug",
+						Present: true,
+					},
+				}},
+				Services: []contour_v1.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}, {
+				Services: []contour_v1.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	proxy2i := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "example-com",
+			Namespace: "kubesystem",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Includes: []contour_v1.Include{{
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/kuard",
+				}},
+				RequestHeadersPolicy: &contour_v1.HeadersPolicy{
+					Set: []contour_v1.HeaderValue{{
+						Name:  "x-tenant",
+						Value: "team-a",
+					}, {
+						Name:  "x-env",
+						Value: "production",
+					}},
+					Remove: []string{"x-debug"},
+				},
+				Name:      "kuard",
+				Namespace: "default",
+			}},
+		},
+	}
+
+	proxy2j := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			Routes: []contour_v1.Route{{
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/staging",
+				}},
+				RequestHeadersPolicy: &contour_v1.HeadersPolicy{
+					Set: []contour_v1.HeaderValue{{
+						Name:  "x-env",
+						Value: "staging",
+					}, {
+						Name:  "x-debug",
+						Value: "true",
+					}},
+				},
+				Services: []contour_v1.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}, {
+				Services: []contour_v1.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
 	proxy2c := &contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
 			Name:      "example-com",
@@ -7348,6 +7505,32 @@ func TestDAGInsert(t *testing.T) {
 		},
 	}
 
+	// proxy2f is like proxy2c but health checks a different port of the service
+	proxy2f := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "example-com",
+			Namespace: "default",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{{
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/",
+				}},
+				HealthCheckPolicy: &contour_v1.HTTPHealthCheckPolicy{
+					Path: "/healthz",
+				},
+				Services: []contour_v1.Service{{
+					Name:       "kuard",
+					Port:       8080,
+					HealthPort: 8081,
+				}},
+			}},
+		},
+	}
+
 	// proxy2d is a proxy with two routes that have the same prefix and a Contains header
 	// condition on the same header, differing only in the value of the condition.
 	proxy2d := &contour_v1.HTTPProxy{
@@ -10955,6 +11138,67 @@ func TestDAGInsert(t *testing.T) {
 				},
 			),
 		},
+		"insert httproxy w/ included query parameter conditions": {
+			objs: []any{
+				proxy2g, proxy2h, s1,
+			},
+			want: listeners(
+				&Listener{
+					Name: HTTP_LISTENER_NAME,
+					Port: 8080,
+					VirtualHosts: virtualhosts(
+						virtualhost("example.com", &Route{
+							PathMatchCondition: prefixString("/kuard/"),
+							QueryParamMatchConditions: []QueryParamMatchCondition{
+								{Name: "version", Value: "v2", MatchType: QueryParamMatchTypeExact},
+								{Name: "debug", MatchType: QueryParamMatchTypePresent},
+							},
+							Clusters: clusters(service(s1)),
+						}, &Route{
+							PathMatchCondition: prefixString("/kuard"),
+							QueryParamMatchConditions: []QueryParamMatchCondition{
+								{Name: "version", Value: "v2", MatchType: QueryParamMatchTypeExact},
+							},
+							Clusters: clusters(service(s1)),
+						}),
+					),
+				},
+			),
+		},
+		"insert httproxy w/ included request headers policy": {
+			objs: []any{
+				proxy2i, proxy2j, s1,
+			},
+			want: listeners(
+				&Listener{
+					Name: HTTP_LISTENER_NAME,
+					Port: 8080,
+					VirtualHosts: virtualhosts(
+						virtualhost("example.com", &Route{
+							PathMatchCondition: prefixString("/kuard/staging"),
+							RequestHeadersPolicy: &HeadersPolicy{
+								Set: map[string]string{
+									"X-Tenant": "team-a",
+									"X-Env":    "staging",
+									"X-Debug":  "true",
+								},
+							},
+							Clusters: clusters(service(s1)),
+						}, &Route{
+							PathMatchCondition: prefixString("/kuard"),
+							RequestHeadersPolicy: &HeadersPolicy{
+								Set: map[string]string{
+									"X-Tenant": "team-a",
+									"X-Env":    "production",
+								},
+								Remove: []string{"X-Debug"},
+							},
+							Clusters: clusters(service(s1)),
+						}),
+					),
+				},
+			),
+		},
 		"insert httpproxy w/ healthcheck": {
 			objs: []any{
 				proxy2c, s1,
@@ -10976,6 +11220,35 @@ func TestDAGInsert(t *testing.T) {
 				},
 			),
 		},
+		"insert httpproxy w/ healthcheck on a different port": {
+			objs: []any{
+				proxy2f, s1c,
+			},
+			want: listeners(
+				&Listener{
+					Name: HTTP_LISTENER_NAME,
+					Port: 8080,
+					VirtualHosts: virtualhosts(
+						virtualhost("example.com",
+							routeCluster("/", &Cluster{
+								Upstream: &Service{
+									Weighted: WeightedService{
+										Weight:           1,
+										ServiceName:      s1c.Name,
+										ServiceNamespace: s1c.Namespace,
+										ServicePort:      s1c.Spec.Ports[0],
+										HealthPort:       s1c.Spec.Ports[1],
+									},
+								},
+								HTTPHealthCheckPolicy: &HTTPHealthCheckPolicy{
+									Path: "/healthz",
+								},
+							}),
+						),
+					),
+				},
+			),
+		},
 		"insert httpproxy with mirroring route": {
 			objs: []any{
 				proxy12, s1, s2,
@@ -12119,6 +12392,31 @@ func TestDAGInsert(t *testing.T) {
 				},
 			),
 		},
+		"insert proxy with tcp forward w/ failover priorities": {
+			objs: []any{
+				proxy1i, s1, s2,
+			},
+			want: listeners(
+				&Listener{
+					Name: HTTPS_LISTENER_NAME,
+					Port: 8443,
+					SecureVirtualHosts: securevirtualhosts(
+						&SecureVirtualHost{
+							VirtualHost: VirtualHost{
+								Name: "kuard.example.com",
+							},
+							TCPProxy: &TCPProxy{
+								Clusters: clusters(
+									service(s1),
+									service(s2),
+								),
+								Failover: true,
+							},
+						},
+					),
+				},
+			),
+		},
 		"insert proxy with tcp forward without TLS termination w/ passthrough without 301 upgrade of port 80": {
 			objs: []any{
 				proxy1e, s10,
@@ -15186,7 +15484,7 @@ func TestHTTPProxyConficts(t *testing.T) {
 		),
 		wantStatus: map[types.NamespacedName]contour_v1.DetailedCondition{
 			{Name: "root-proxy", Namespace: "default"}: fixture.NewValidCondition().
-				WithError(contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Spec.Routes unresolved service reference: service "default/missing-service" not found`),
+				WithRouteError("prefix: /", contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Spec.Routes unresolved service reference: service "default/missing-service" not found`),
 		},
 	})
 
@@ -15294,7 +15592,7 @@ func TestHTTPProxyConficts(t *testing.T) {
 		),
 		wantStatus: map[types.NamespacedName]contour_v1.DetailedCondition{
 			{Name: "root-proxy", Namespace: "default"}: fixture.NewValidCondition().
-				WithError(contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Spec.Routes unresolved service reference: service "default/missing-service" not found`),
+				WithRouteError("prefix: /", contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Spec.Routes unresolved service reference: service "default/missing-service" not found`),
 		},
 	})
 
@@ -15353,7 +15651,7 @@ func TestHTTPProxyConficts(t *testing.T) {
 		),
 		wantStatus: map[types.NamespacedName]contour_v1.DetailedCondition{
 			{Name: "root-proxy", Namespace: "default"}: fixture.NewValidCondition().
-				WithError(contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Spec.Routes unresolved service reference: service "default/missing-service" not found`),
+				WithRouteError("prefix: /", contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Spec.Routes unresolved service reference: service "default/missing-service" not found`),
 		},
 	})
 
@@ -15462,7 +15760,7 @@ func TestHTTPProxyConficts(t *testing.T) {
 		),
 		wantStatus: map[types.NamespacedName]contour_v1.DetailedCondition{
 			{Name: "invalid-child-proxy", Namespace: "default"}: fixture.NewValidCondition().
-				WithError(contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Spec.Routes unresolved service reference: service "default/missing-service" not found`),
+				WithRouteError("prefix: /", contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Spec.Routes unresolved service reference: service "default/missing-service" not found`),
 			{Name: "root-proxy", Namespace: "default"}: fixture.NewValidCondition().Valid(),
 		},
 	})
@@ -15532,7 +15830,7 @@ func TestHTTPProxyConficts(t *testing.T) {
 		),
 		wantStatus: map[types.NamespacedName]contour_v1.DetailedCondition{
 			{Name: "invalid-child-proxy", Namespace: "default"}: fixture.NewValidCondition().
-				WithError(contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Spec.Routes unresolved service reference: service "default/missing-service" not found`),
+				WithRouteError("prefix: /", contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Spec.Routes unresolved service reference: service "default/missing-service" not found`),
 			{Name: "valid-child-proxy", Namespace: "default"}: fixture.NewValidCondition().Valid(),
 			{Name: "root-proxy", Namespace: "default"}:        fixture.NewValidCondition().Valid(),
 		},
@@ -16170,3 +16468,27 @@ func makeHTTPRouteRule(pathType gatewayapi_v1.PathMatchType, pathValue, serviceN
 		BackendRefs: gatewayapi.HTTPBackendRef(serviceName, port, weight),
 	}
 }
+
+func TestBuildObservesDAGRebuildSeconds(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	builder := Builder{
+		Source: KubernetesCache{
+			FieldLogger: fixture.NewTestLogger(t),
+		},
+		Metrics: metrics.NewMetrics(registry),
+	}
+
+	builder.Build()
+	builder.Build()
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	var count uint64
+	for _, mf := range families {
+		if mf.GetName() == metrics.DAGRebuildSeconds {
+			count = mf.Metric[0].GetSummary().GetSampleCount()
+		}
+	}
+	assert.Equal(t, uint64(2), count)
+}
@@ -15,14 +15,19 @@ package dag
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/sirupsen/logrus"
 	core_v1 "k8s.io/api/core/v1"
 	networking_v1 "k8s.io/api/networking/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/cache"
@@ -50,11 +55,22 @@ type KubernetesCache struct {
 	// namespace.
 	RootNamespaces []string
 
+	// RootNamespaceSelector additionally permits root HTTPProxies in
+	// the namespaces whose labels match it. Namespaces are looked up
+	// in the cache, so label changes take effect on the next rebuild.
+	RootNamespaceSelector labels.Selector
+
 	// Names of ingress classes to cache HTTPProxies/Ingresses for. If not
 	// set, objects with no ingress class or DEFAULT_INGRESS_CLASS will be
 	// cached.
 	IngressClassNames []string
 
+	// LabelSelector restricts the HTTPProxies, Ingresses and Gateways
+	// cached to those whose labels match. Objects that do not match
+	// are ignored rather than reported as invalid. If nil, all
+	// objects are cached.
+	LabelSelector labels.Selector
+
 	// ConfiguredGatewayToCache is the optional name of the specific Gateway to cache.
 	// If set, only the Gateway with this namespace/name will be kept.
 	ConfiguredGatewayToCache *types.NamespacedName
@@ -66,6 +82,7 @@ type KubernetesCache struct {
 	httpproxies               map[types.NamespacedName]*contour_v1.HTTPProxy
 	secrets                   map[types.NamespacedName]*Secret
 	configmapsecrets          map[types.NamespacedName]*Secret
+	directresponses           map[types.NamespacedName]*core_v1.ConfigMap
 	tlscertificatedelegations map[types.NamespacedName]*contour_v1.TLSCertificateDelegation
 	services                  map[types.NamespacedName]*core_v1.Service
 	namespaces                map[string]*core_v1.Namespace
@@ -98,12 +115,25 @@ func NewDelegationNotPermittedError(err error) DelegationNotPermittedError {
 	return DelegationNotPermittedError{err}
 }
 
+// NotFoundError is returned by KubernetesCache's accessor methods when the
+// referenced object does not exist.
+type NotFoundError struct {
+	// Kind is the kind of the referenced object, for example "Service".
+	Kind string
+
+	// Name is the name of the referenced object.
+	Name types.NamespacedName
+
+	error
+}
+
 // init creates the internal cache storage. It is called implicitly from the public API.
 func (kc *KubernetesCache) init() {
 	kc.ingresses = make(map[types.NamespacedName]*networking_v1.Ingress)
 	kc.httpproxies = make(map[types.NamespacedName]*contour_v1.HTTPProxy)
 	kc.secrets = make(map[types.NamespacedName]*Secret)
 	kc.configmapsecrets = make(map[types.NamespacedName]*Secret)
+	kc.directresponses = make(map[types.NamespacedName]*core_v1.ConfigMap)
 	kc.tlscertificatedelegations = make(map[types.NamespacedName]*contour_v1.TLSCertificateDelegation)
 	kc.services = make(map[types.NamespacedName]*core_v1.Service)
 	kc.namespaces = make(map[string]*core_v1.Namespace)
@@ -132,13 +162,25 @@ func (kc *KubernetesCache) Insert(obj any) bool {
 			return kc.secretTriggersRebuild(obj), len(kc.secrets)
 
 		case *core_v1.ConfigMap:
+			// Direct response bodies are opted in with a label. The same
+			// ConfigMap may also hold a CA certificate, so both roles are
+			// tracked independently.
+			m := k8s.NamespacedNameOf(obj)
+			_, rebuild := kc.directresponses[m]
+			if k8s.IsDirectResponseConfigMap(obj) {
+				kc.directresponses[m] = obj
+				rebuild = true
+			} else {
+				delete(kc.directresponses, m)
+			}
+
 			// Only insert configmaps that are CA certs, i.e has 'ca.crt' key,
 			// into cache.
 			if secret, isCA := kc.convertCACertConfigMapToSecret(obj); isCA {
-				kc.configmapsecrets[k8s.NamespacedNameOf(obj)] = &Secret{Object: secret}
-				return kc.configMapTriggersRebuild(obj), len(kc.configmapsecrets)
+				kc.configmapsecrets[m] = &Secret{Object: secret}
+				rebuild = kc.configMapTriggersRebuild(obj) || rebuild
 			}
-			return false, len(kc.configmapsecrets)
+			return rebuild, kc.configMapCount()
 
 		case *core_v1.Service:
 			kc.services[k8s.NamespacedNameOf(obj)] = obj
@@ -149,6 +191,9 @@ func (kc *KubernetesCache) Insert(obj any) bool {
 			return true, len(kc.namespaces)
 
 		case *networking_v1.Ingress:
+			if !kc.matchesLabelSelector(obj) {
+				return false, len(kc.ingresses)
+			}
 			if !ingressclass.MatchesIngress(obj, kc.IngressClassNames) {
 				// We didn't get a match so report this object is being ignored.
 				kc.WithField("name", obj.GetName()).
@@ -164,6 +209,9 @@ func (kc *KubernetesCache) Insert(obj any) bool {
 			return true, len(kc.ingresses)
 
 		case *contour_v1.HTTPProxy:
+			if !kc.matchesLabelSelector(obj) {
+				return false, len(kc.httpproxies)
+			}
 			if !ingressclass.MatchesHTTPProxy(obj, kc.IngressClassNames) {
 				// We didn't get a match so report this object is being ignored.
 				kc.WithField("name", obj.GetName()).
@@ -204,6 +252,13 @@ func (kc *KubernetesCache) Insert(obj any) bool {
 			}
 
 		case *gatewayapi_v1.Gateway:
+			if !kc.matchesLabelSelector(obj) {
+				if kc.gateway == nil {
+					return false, 0
+				}
+				return false, 1
+			}
+
 			switch {
 			// Specific gateway configured: make sure the incoming gateway
 			// matches, and get its gateway class.
@@ -299,6 +354,22 @@ func (kc *KubernetesCache) Insert(obj any) bool {
 	return false
 }
 
+// matchesLabelSelector returns true if the labels of obj match the
+// cache's LabelSelector, or if no LabelSelector is set.
+func (kc *KubernetesCache) matchesLabelSelector(obj client.Object) bool {
+	if kc.LabelSelector == nil || kc.LabelSelector.Matches(labels.Set(obj.GetLabels())) {
+		return true
+	}
+
+	// We didn't get a match so report this object is being ignored.
+	kc.WithField("name", obj.GetName()).
+		WithField("namespace", obj.GetNamespace()).
+		WithField("kind", k8s.KindOf(obj)).
+		WithField("label-selector", kc.LabelSelector.String()).
+		Debug("ignoring object with unmatched labels")
+	return false
+}
+
 // Remove removes obj from the KubernetesCache.
 // Remove returns a boolean indicating if the cache changed after the remove operation.
 func (kc *KubernetesCache) Remove(obj any) bool {
@@ -324,8 +395,10 @@ func (kc *KubernetesCache) remove(obj any) (bool, int) {
 
 	case *core_v1.ConfigMap:
 		m := k8s.NamespacedNameOf(obj)
+		_, rebuild := kc.directresponses[m]
+		delete(kc.directresponses, m)
 		delete(kc.configmapsecrets, m)
-		return kc.configMapTriggersRebuild(obj), len(kc.configmapsecrets)
+		return kc.configMapTriggersRebuild(obj) || rebuild, kc.configMapCount()
 
 	case *core_v1.Service:
 		m := k8s.NamespacedNameOf(obj)
@@ -670,7 +743,7 @@ func (kc *KubernetesCache) LookupCASecret(name types.NamespacedName, targetNames
 
 	sec, ok := kc.secrets[name]
 	if !ok {
-		return nil, fmt.Errorf("Secret not found")
+		return nil, NotFoundError{Kind: "Secret", Name: name, error: fmt.Errorf("Secret not found")}
 	}
 
 	// Compute and store the validation result if not
@@ -687,6 +760,18 @@ func (kc *KubernetesCache) LookupCASecret(name types.NamespacedName, targetNames
 	return sec, nil
 }
 
+// configMapCount returns the number of distinct ConfigMaps in the cache,
+// whether they hold direct response bodies, CA certificates or both.
+func (kc *KubernetesCache) configMapCount() int {
+	count := len(kc.directresponses)
+	for m := range kc.configmapsecrets {
+		if _, ok := kc.directresponses[m]; !ok {
+			count++
+		}
+	}
+	return count
+}
+
 // LookupCAConfigMap returns ConfigMap converted into dag.Secret with CA certificate from cache.
 func (kc *KubernetesCache) LookupCAConfigMap(name types.NamespacedName) (*Secret, error) {
 	sec, ok := kc.configmapsecrets[name]
@@ -708,6 +793,21 @@ func (kc *KubernetesCache) LookupCAConfigMap(name types.NamespacedName) (*Secret
 	return sec, nil
 }
 
+// LookupDirectResponseBody returns the direct response body held in the
+// given key of a ConfigMap from the cache.
+func (kc *KubernetesCache) LookupDirectResponseBody(name types.NamespacedName, key string) (string, error) {
+	cm, ok := kc.directresponses[name]
+	if !ok {
+		return "", fmt.Errorf("ConfigMap not found or missing the %q label", k8s.DirectResponseConfigMapLabel)
+	}
+
+	body, ok := cm.Data[key]
+	if !ok {
+		return "", fmt.Errorf("ConfigMap has no key %q", key)
+	}
+	return body, nil
+}
+
 // LookupCRLSecret returns Secret with CRL from the cache.
 // If name (referred Secret) is in different namespace than targetNamespace (the referring object),
 // then delegation check is performed.
@@ -718,7 +818,7 @@ func (kc *KubernetesCache) LookupCRLSecret(name types.NamespacedName, targetName
 
 	sec, ok := kc.secrets[name]
 	if !ok {
-		return nil, fmt.Errorf("Secret not found")
+		return nil, NotFoundError{Kind: "Secret", Name: name, error: fmt.Errorf("Secret not found")}
 	}
 
 	// Compute and store the validation result if not
@@ -735,7 +835,8 @@ func (kc *KubernetesCache) LookupCRLSecret(name types.NamespacedName, targetName
 	return sec, nil
 }
 
-// LookupUpstreamValidation constructs PeerValidationContext with CA certificate from the cache.
+// LookupUpstreamValidation constructs PeerValidationContext with CA certificate from the cache
+// and the certificate pins from uv.
 // If name (referred Secret) is in different namespace than targetNamespace (the referring object),
 // then delegation check is performed.
 func (kc *KubernetesCache) LookupUpstreamValidation(uv *contour_v1.UpstreamValidation, caCertificate types.NamespacedName, targetNamespace string) (*PeerValidationContext, error) {
@@ -746,15 +847,34 @@ func (kc *KubernetesCache) LookupUpstreamValidation(uv *contour_v1.UpstreamValid
 
 	pvc := &PeerValidationContext{}
 
-	cacert, err := kc.LookupCASecret(caCertificate, targetNamespace)
-	if err != nil {
-		if _, ok := err.(DelegationNotPermittedError); ok {
+	for _, spki := range uv.VerifyCertificateSpki {
+		if err := validateCertificateSpki(spki); err != nil {
 			return nil, err
 		}
-		return nil, fmt.Errorf("invalid CA Secret %q: %s", caCertificate, err)
 	}
-	pvc.CACertificates = []*Secret{
-		cacert,
+	pvc.VerifyCertificateSpki = uv.VerifyCertificateSpki
+
+	for _, hash := range uv.VerifyCertificateHash {
+		if err := validateCertificateHash(hash); err != nil {
+			return nil, err
+		}
+	}
+	pvc.VerifyCertificateHash = uv.VerifyCertificateHash
+
+	switch {
+	case uv.CACertificate != "":
+		cacert, err := kc.LookupCASecret(caCertificate, targetNamespace)
+		if err != nil {
+			if _, ok := err.(DelegationNotPermittedError); ok {
+				return nil, err
+			}
+			return nil, fmt.Errorf("invalid CA Secret %q: %w", caCertificate, err)
+		}
+		pvc.CACertificates = []*Secret{
+			cacert,
+		}
+	case len(pvc.VerifyCertificateSpki) == 0 && len(pvc.VerifyCertificateHash) == 0:
+		return nil, errors.New("a CA Secret or certificate pins must be specified")
 	}
 
 	// CEL validation should enforce that SubjectName must be set if SubjectNames is used. So, SubjectName will always be present.
@@ -777,12 +897,31 @@ func (kc *KubernetesCache) LookupUpstreamValidation(uv *contour_v1.UpstreamValid
 	return pvc, nil
 }
 
+// validateCertificateSpki checks that spki is a base64 encoded SHA-256 hash.
+func validateCertificateSpki(spki string) error {
+	b, err := base64.StdEncoding.DecodeString(spki)
+	if err != nil || len(b) != sha256.Size {
+		return fmt.Errorf("invalid certificate SPKI %q: must be a base64 encoded SHA-256 hash", spki)
+	}
+	return nil
+}
+
+// validateCertificateHash checks that hash is a hex encoded SHA-256 hash,
+// optionally with its bytes separated by colons.
+func validateCertificateHash(hash string) error {
+	b, err := hex.DecodeString(strings.ReplaceAll(hash, ":", ""))
+	if err != nil || len(b) != sha256.Size {
+		return fmt.Errorf("invalid certificate hash %q: must be a hex encoded SHA-256 hash", hash)
+	}
+	return nil
+}
+
 // LookupTLSSecretInsecure returns Secret with TLS certificate and private key from cache.
 // No delegation check is performed.
 func (kc *KubernetesCache) LookupTLSSecretInsecure(name types.NamespacedName) (*Secret, error) {
 	sec, ok := kc.secrets[name]
 	if !ok {
-		return nil, fmt.Errorf("Secret not found")
+		return nil, NotFoundError{Kind: "Secret", Name: name, error: fmt.Errorf("Secret not found")}
 	}
 
 	// Compute and store the validation result if not
@@ -802,6 +941,22 @@ func (kc *KubernetesCache) LookupTLSSecretInsecure(name types.NamespacedName) (*
 // delegationPermitted returns true if the referenced secret has been delegated
 // to the namespace where the ingress object is located.
 func (kc *KubernetesCache) delegationPermitted(secret types.NamespacedName, targetNamespace string) bool {
+	if secret.Namespace == targetNamespace {
+		// secret is in the same namespace as target
+		return true
+	}
+
+	for _, d := range kc.tlscertificatedelegations {
+		if CertificateDelegated(d, secret, targetNamespace) {
+			return true
+		}
+	}
+	return false
+}
+
+// CertificateDelegated returns true if the TLSCertificateDelegation delegates
+// the referenced secret to targetNamespace.
+func CertificateDelegated(delegation *contour_v1.TLSCertificateDelegation, secret types.NamespacedName, targetNamespace string) bool {
 	contains := func(haystack []string, needle string) bool {
 		if len(haystack) == 1 && haystack[0] == "*" {
 			return true
@@ -814,20 +969,14 @@ func (kc *KubernetesCache) delegationPermitted(secret types.NamespacedName, targ
 		return false
 	}
 
-	if secret.Namespace == targetNamespace {
-		// secret is in the same namespace as target
-		return true
+	if delegation.Namespace != secret.Namespace {
+		return false
 	}
 
-	for _, d := range kc.tlscertificatedelegations {
-		if d.Namespace != secret.Namespace {
-			continue
-		}
-		for _, d := range d.Spec.Delegations {
-			if contains(d.TargetNamespaces, targetNamespace) {
-				if secret.Name == d.SecretName {
-					return true
-				}
+	for _, d := range delegation.Spec.Delegations {
+		if contains(d.TargetNamespaces, targetNamespace) {
+			if secret.Name == d.SecretName {
+				return true
 			}
 		}
 	}
@@ -839,7 +988,7 @@ func (kc *KubernetesCache) delegationPermitted(secret types.NamespacedName, targ
 func (kc *KubernetesCache) LookupService(meta types.NamespacedName, port intstr.IntOrString) (*core_v1.Service, core_v1.ServicePort, error) {
 	svc, ok := kc.services[meta]
 	if !ok {
-		return nil, core_v1.ServicePort{}, fmt.Errorf("service %q not found", meta)
+		return nil, core_v1.ServicePort{}, NotFoundError{Kind: "Service", Name: meta, error: fmt.Errorf("service %q not found", meta)}
 	}
 
 	for i := range svc.Spec.Ports {
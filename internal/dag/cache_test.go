@@ -23,6 +23,7 @@ import (
 	core_v1 "k8s.io/api/core/v1"
 	networking_v1 "k8s.io/api/networking/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
@@ -37,14 +38,16 @@ import (
 	"github.com/projectcontour/contour/internal/fixture"
 	"github.com/projectcontour/contour/internal/gatewayapi"
 	"github.com/projectcontour/contour/internal/ingressclass"
+	"github.com/projectcontour/contour/internal/k8s"
 )
 
 func TestKubernetesCacheInsert(t *testing.T) {
 	tests := map[string]struct {
-		cacheGateway *types.NamespacedName
-		pre          []any
-		obj          any
-		want         bool
+		cacheGateway  *types.NamespacedName
+		labelSelector labels.Selector
+		pre           []any
+		obj           any
+		want          bool
 	}{
 		"insert TLS secret not referenced": {
 			obj: &core_v1.Secret{
@@ -693,7 +696,7 @@ func TestKubernetesCacheInsert(t *testing.T) {
 			},
 			want: true,
 		},
-		"insert httpproxy ingress class annotation overrides spec incorrect": {
+		"insert httpproxy ingress class spec overrides annotation correct": {
 			obj: &contour_v1.HTTPProxy{
 				ObjectMeta: meta_v1.ObjectMeta{
 					Name:      "override",
@@ -706,9 +709,9 @@ func TestKubernetesCacheInsert(t *testing.T) {
 					IngressClassName: "contour",
 				},
 			},
-			want: false,
+			want: true,
 		},
-		"insert httpproxy ingress class annotation overrides spec correct": {
+		"insert httpproxy ingress class spec overrides annotation incorrect": {
 			obj: &contour_v1.HTTPProxy{
 				ObjectMeta: meta_v1.ObjectMeta{
 					Name:      "override",
@@ -721,7 +724,7 @@ func TestKubernetesCacheInsert(t *testing.T) {
 					IngressClassName: "nginx",
 				},
 			},
-			want: true,
+			want: false,
 		},
 		"insert tls contour_v1/v1.certificatedelegation": {
 			obj: &contour_v1.TLSCertificateDelegation{
@@ -1253,12 +1256,66 @@ func TestKubernetesCacheInsert(t *testing.T) {
 			},
 			want: true,
 		},
+		"label selector configured, insert httpproxy, labels match": {
+			labelSelector: labels.SelectorFromSet(labels.Set{"shard": "a"}),
+			obj: &contour_v1.HTTPProxy{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "proxy",
+					Namespace: "default",
+					Labels:    map[string]string{"shard": "a"},
+				},
+			},
+			want: true,
+		},
+		"label selector configured, insert httpproxy, labels don't match": {
+			labelSelector: labels.SelectorFromSet(labels.Set{"shard": "a"}),
+			obj: &contour_v1.HTTPProxy{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "proxy",
+					Namespace: "default",
+					Labels:    map[string]string{"shard": "b"},
+				},
+			},
+			want: false,
+		},
+		"label selector configured, insert ingress, labels don't match": {
+			labelSelector: labels.SelectorFromSet(labels.Set{"shard": "a"}),
+			obj: &networking_v1.Ingress{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "ingress",
+					Namespace: "default",
+				},
+			},
+			want: false,
+		},
+		"label selector configured, insert gateway, labels match": {
+			labelSelector: labels.SelectorFromSet(labels.Set{"shard": "a"}),
+			obj: &gatewayapi_v1.Gateway{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Namespace: "gateway-namespace",
+					Name:      "gateway-name",
+					Labels:    map[string]string{"shard": "a"},
+				},
+			},
+			want: true,
+		},
+		"label selector configured, insert gateway, labels don't match": {
+			labelSelector: labels.SelectorFromSet(labels.Set{"shard": "a"}),
+			obj: &gatewayapi_v1.Gateway{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Namespace: "gateway-namespace",
+					Name:      "gateway-name",
+				},
+			},
+			want: false,
+		},
 	}
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			cache := KubernetesCache{
 				ConfiguredGatewayToCache: tc.cacheGateway,
+				LabelSelector:            tc.labelSelector,
 				ConfiguredSecretRefs: []*types.NamespacedName{
 					{Name: "secretReferredByConfigFile", Namespace: "default"},
 				},
@@ -2978,6 +3035,65 @@ func TestLookupUpstreamValidation(t *testing.T) {
 			meta:    types.NamespacedName{Namespace: "default", Name: "ca"},
 			wantPvc: pvc([]string{"example.com"}),
 		},
+		"certificate pins without CA": {
+			cache: cache(),
+			uv: &contour_v1.UpstreamValidation{
+				SubjectName:           "example.com",
+				VerifyCertificateSpki: []string{"b+7MjBbFVR2f6z61934tp3O/aL2e+cUpJ86yyG5WiSs="},
+				VerifyCertificateHash: []string{"06:29:84:32:e8:06:6b:29:e2:22:3b:cc:23:aa:95:04:b5:6a:e5:08:fa:bf:34:35:50:88:69:b9:c3:19:0e:22"},
+			},
+			meta: types.NamespacedName{Namespace: "default"},
+			wantPvc: &PeerValidationContext{
+				SubjectNames:          []string{"example.com"},
+				VerifyCertificateSpki: []string{"b+7MjBbFVR2f6z61934tp3O/aL2e+cUpJ86yyG5WiSs="},
+				VerifyCertificateHash: []string{"06:29:84:32:e8:06:6b:29:e2:22:3b:cc:23:aa:95:04:b5:6a:e5:08:fa:bf:34:35:50:88:69:b9:c3:19:0e:22"},
+			},
+		},
+		"certificate hash without colons and CA": {
+			cache: cache(secret()),
+			uv: &contour_v1.UpstreamValidation{
+				CACertificate:         "ca",
+				SubjectName:           "example.com",
+				VerifyCertificateHash: []string{"06298432e8066b29e2223bcc23aa9504b56ae508fabf3435508869b9c3190e22"},
+			},
+			meta: types.NamespacedName{Namespace: "default", Name: "ca"},
+			wantPvc: &PeerValidationContext{
+				CACertificates: []*Secret{
+					{
+						Object:        secret(),
+						ValidCASecret: &SecretValidationStatus{},
+					},
+				},
+				SubjectNames:          []string{"example.com"},
+				VerifyCertificateHash: []string{"06298432e8066b29e2223bcc23aa9504b56ae508fabf3435508869b9c3190e22"},
+			},
+		},
+		"invalid certificate SPKI": {
+			cache: cache(),
+			uv: &contour_v1.UpstreamValidation{
+				SubjectName:           "example.com",
+				VerifyCertificateSpki: []string{"c3BraQ=="},
+			},
+			meta:    types.NamespacedName{Namespace: "default"},
+			wantErr: errors.New(`invalid certificate SPKI "c3BraQ==": must be a base64 encoded SHA-256 hash`),
+		},
+		"invalid certificate hash": {
+			cache: cache(),
+			uv: &contour_v1.UpstreamValidation{
+				SubjectName:           "example.com",
+				VerifyCertificateHash: []string{"not-hex"},
+			},
+			meta:    types.NamespacedName{Namespace: "default"},
+			wantErr: errors.New(`invalid certificate hash "not-hex": must be a hex encoded SHA-256 hash`),
+		},
+		"neither CA nor certificate pins": {
+			cache: cache(),
+			uv: &contour_v1.UpstreamValidation{
+				SubjectName: "example.com",
+			},
+			meta:    types.NamespacedName{Namespace: "default"},
+			wantErr: errors.New("a CA Secret or certificate pins must be specified"),
+		},
 	}
 
 	for name, tc := range tests {
@@ -3212,6 +3328,30 @@ func TestLookupCAConfigMap(t *testing.T) {
 			meta:       types.NamespacedName{Namespace: "default", Name: "ca"},
 			wantSecret: secret("ca", "default", fixture.CA_CERT),
 		},
+		"finds configmap that also holds direct response bodies": {
+			cache: cache(
+				&core_v1.ConfigMap{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "ca",
+						Namespace: "default",
+						Labels: map[string]string{
+							k8s.DirectResponseConfigMapLabel: "",
+						},
+					},
+					Data: map[string]string{
+						CACertificateKey: fixture.CA_CERT,
+					},
+				},
+			),
+			meta: types.NamespacedName{Namespace: "default", Name: "ca"},
+			wantSecret: func() *Secret {
+				s := secret("ca", "default", fixture.CA_CERT)
+				s.Object.Labels = map[string]string{
+					k8s.DirectResponseConfigMapLabel: "",
+				}
+				return s
+			}(),
+		},
 		"returns an error if configmap secret is not a valid cert": {
 			cache: cache(
 				configmap("ca", "default", "invalid-ca-data"),
@@ -361,6 +361,7 @@ func headerMatchConditionsValid(conditions []contour_v1.MatchCondition) error {
 //   - more than one condition is set in the same match condition branch
 //   - more than 1 'exact' condition for the same query parameter
 //   - invalid regular expression is specified for the Regex condition
+//   - ignoreCase is specified with a Regex or Present condition
 func queryParameterMatchConditionsValid(conditions []contour_v1.MatchCondition) error {
 	queryParametersWithExactMatch := map[string]bool{}
 
@@ -402,6 +403,10 @@ func queryParameterMatchConditionsValid(conditions []contour_v1.MatchCondition)
 				return errors.New("invalid regular expression specified for 'regex' condition")
 			}
 		}
+
+		if v.QueryParameter.IgnoreCase && (v.QueryParameter.Regex != "" || v.QueryParameter.Present) {
+			return errors.New("cannot specify 'ignoreCase' with a 'regex' or 'present' condition")
+		}
 	}
 
 	return nil
@@ -826,6 +826,51 @@ func TestValidateQueryParameterMatchConditions(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		"prefix, suffix and contains on separate conditions is valid": {
+			matchconditions: []contour_v1.MatchCondition{
+				{
+					QueryParameter: &contour_v1.QueryParameterMatchCondition{
+						Name:   "param",
+						Prefix: "abc",
+					},
+				}, {
+					QueryParameter: &contour_v1.QueryParameterMatchCondition{
+						Name:   "param",
+						Suffix: "xyz",
+					},
+				}, {
+					QueryParameter: &contour_v1.QueryParameterMatchCondition{
+						Name:     "param",
+						Contains: "mno",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		"prefix and contains in the same branch is invalid": {
+			matchconditions: []contour_v1.MatchCondition{
+				{
+					QueryParameter: &contour_v1.QueryParameterMatchCondition{
+						Name:     "param",
+						Prefix:   "abc",
+						Contains: "mno",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		"suffix and contains in the same branch is invalid": {
+			matchconditions: []contour_v1.MatchCondition{
+				{
+					QueryParameter: &contour_v1.QueryParameterMatchCondition{
+						Name:     "param",
+						Suffix:   "xyz",
+						Contains: "mno",
+					},
+				},
+			},
+			wantErr: true,
+		},
 		"more than one 'exact' condition for the same query parameter is invalid": {
 			matchconditions: []contour_v1.MatchCondition{
 				{
@@ -871,6 +916,54 @@ func TestValidateQueryParameterMatchConditions(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		"ignoreCase with exact, prefix and suffix is valid": {
+			matchconditions: []contour_v1.MatchCondition{
+				{
+					QueryParameter: &contour_v1.QueryParameterMatchCondition{
+						Name:       "param1",
+						Exact:      "abc",
+						IgnoreCase: true,
+					},
+				}, {
+					QueryParameter: &contour_v1.QueryParameterMatchCondition{
+						Name:       "param2",
+						Prefix:     "abc",
+						IgnoreCase: true,
+					},
+				}, {
+					QueryParameter: &contour_v1.QueryParameterMatchCondition{
+						Name:       "param3",
+						Suffix:     "abc",
+						IgnoreCase: true,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		"ignoreCase with regex is invalid": {
+			matchconditions: []contour_v1.MatchCondition{
+				{
+					QueryParameter: &contour_v1.QueryParameterMatchCondition{
+						Name:       "param",
+						Regex:      "^abc$",
+						IgnoreCase: true,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		"ignoreCase with present is invalid": {
+			matchconditions: []contour_v1.MatchCondition{
+				{
+					QueryParameter: &contour_v1.QueryParameterMatchCondition{
+						Name:       "param",
+						Present:    true,
+						IgnoreCase: true,
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for name, tc := range tests {
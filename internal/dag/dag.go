@@ -213,6 +213,21 @@ type DirectResponse struct {
 	StatusCode uint32
 	// Body is the content of the response body.
 	Body string
+	// WeightedBodies, if set, replaces Body with one of several
+	// bodies chosen per request according to their weights.
+	WeightedBodies []WeightedDirectResponseBody
+	// BodyTemplate is true if Body contains command operators
+	// to be replaced for each request.
+	BodyTemplate bool
+}
+
+// WeightedDirectResponseBody is a direct response body served to
+// Weight percent of requests.
+type WeightedDirectResponseBody struct {
+	// Body is the content of the response body.
+	Body string
+	// Weight is the percentage of requests that receive Body.
+	Weight uint32
 }
 
 // Redirect allows for a 301/302 redirect to be the response
@@ -293,6 +308,12 @@ type Route struct {
 	// Route has a higher priority.
 	Priority uint8
 
+	// MatchPriority orders the Route relative to other Routes with the same
+	// path match condition, before their method, header and query parameter
+	// conditions are compared. A lower value here means the Route has a
+	// higher priority.
+	MatchPriority uint8
+
 	Clusters []*Cluster
 
 	// Should this route generate a 301 upgrade if accessed
@@ -370,12 +391,78 @@ type Route struct {
 	// by IPFilterAllow.
 	IPFilterRules []IPFilterRule
 
+	// CORSPolicy is the cross-origin policy to apply to the route,
+	// replacing the VirtualHost's policy.
+	CORSPolicy *CORSPolicy
+
+	// FaultInjectionPolicy defines the faults to inject into
+	// requests to the route.
+	FaultInjectionPolicy *FaultInjectionPolicy
+
+	// SessionPersistence defines how requests to the route are kept
+	// on the upstream endpoint of their session.
+	SessionPersistence *SessionPersistence
+
 	// Metadata fields that can be used for access logging.
 	Kind      string
 	Namespace string
 	Name      string
 }
 
+// Identifier returns a stable identifier for the route of the form
+// "<kind>/<namespace>/<name>/<conditions>", e.g.
+// "HTTPProxy/default/kuard/prefix=/api,header:x-env:exact=prod".
+//
+// The conditions are a comma separated list of the path condition,
+// one of "prefix=<path>", "segment-prefix=<path>", "exact=<path>" or
+// "regex=<regex>", followed by each header condition as
+// "header:<name>:<match type>=<value>" and each query parameter
+// condition as "query:<name>:<match type>=<value>". The match type is
+// prefixed with "not-" for inverted matches and suffixed with
+// "-ignorecase" for case insensitive matches, and "=<value>" is
+// omitted for present matches.
+func (r *Route) Identifier() string {
+	var conds []string
+	switch c := r.PathMatchCondition.(type) {
+	case *PrefixMatchCondition:
+		if c.PrefixMatchType == PrefixMatchSegment {
+			conds = append(conds, "segment-prefix="+c.Prefix)
+		} else {
+			conds = append(conds, "prefix="+c.Prefix)
+		}
+	case *ExactMatchCondition:
+		conds = append(conds, "exact="+c.Path)
+	case *RegexMatchCondition:
+		conds = append(conds, "regex="+c.Regex)
+	}
+	for _, h := range r.HeaderMatchConditions {
+		conds = append(conds, matchIdentifier("header", h.Name, h.MatchType, h.Value, h.Invert, h.IgnoreCase))
+	}
+	for _, q := range r.QueryParamMatchConditions {
+		conds = append(conds, matchIdentifier("query", q.Name, q.MatchType, q.Value, false, q.IgnoreCase))
+	}
+
+	return strings.Join([]string{r.Kind, r.Namespace, r.Name, strings.Join(conds, ",")}, "/")
+}
+
+// matchIdentifier formats a header or query parameter condition for
+// Route.Identifier.
+func matchIdentifier(kind, name, matchType, value string, invert, ignoreCase bool) string {
+	id := matchType
+	if invert {
+		id = "not-" + id
+	}
+	if ignoreCase {
+		id += "-ignorecase"
+	}
+	id = kind + ":" + name + ":" + id
+	// Header and query parameter present matches have no value.
+	if matchType != HeaderMatchTypePresent {
+		id += "=" + value
+	}
+	return id
+}
+
 // HasPathPrefix returns whether this route has a PrefixPathCondition.
 func (r *Route) HasPathPrefix() bool {
 	_, ok := r.PathMatchCondition.(*PrefixMatchCondition)
@@ -432,6 +519,43 @@ type RetryPolicy struct {
 	PerTryTimeout timeout.Setting
 }
 
+// FaultInjectionPolicy defines the faults to inject into
+// requests for a route.
+type FaultInjectionPolicy struct {
+	// Delay, if set, delays a percentage of requests.
+	Delay *FaultDelay
+
+	// Abort, if set, aborts a percentage of requests.
+	Abort *FaultAbort
+}
+
+// FaultDelay defines a fixed delay for a percentage of requests.
+type FaultDelay struct {
+	Percentage uint32
+	Duration   time.Duration
+}
+
+// FaultAbort defines an HTTP status to return for a percentage
+// of requests.
+type FaultAbort struct {
+	Percentage uint32
+	StatusCode uint32
+}
+
+// SessionPersistence defines how the session of a request is
+// tracked. Exactly one of CookieName or HeaderName is set.
+type SessionPersistence struct {
+	CookieName string
+	CookiePath string
+	// CookieTTL is zero for a session cookie.
+	CookieTTL time.Duration
+
+	HeaderName string
+
+	// Strict rejects requests whose session endpoint is unavailable.
+	Strict bool
+}
+
 // PathRewritePolicy defines a policy for rewriting the path of
 // the request during forwarding. At most one field should be populated.
 type PathRewritePolicy struct {
@@ -547,15 +671,23 @@ type GlobalRateLimitPolicy struct {
 // RateLimitDescriptor is a list of rate limit descriptor entries.
 type RateLimitDescriptor struct {
 	Entries []RateLimitDescriptorEntry
+
+	// Methods, if set, restricts the descriptor to requests
+	// using one of these HTTP methods.
+	Methods []string
 }
 
 // RateLimitDescriptorEntry is an entry in a rate limit descriptor.
 // Exactly one field should be non-nil.
 type RateLimitDescriptorEntry struct {
-	GenericKey       *GenericKeyDescriptorEntry
-	HeaderMatch      *HeaderMatchDescriptorEntry
-	HeaderValueMatch *HeaderValueMatchDescriptorEntry
-	RemoteAddress    *RemoteAddressDescriptorEntry
+	GenericKey         *GenericKeyDescriptorEntry
+	HeaderMatch        *HeaderMatchDescriptorEntry
+	HeaderValueMatch   *HeaderValueMatchDescriptorEntry
+	RemoteAddress      *RemoteAddressDescriptorEntry
+	SourceCluster      *SourceClusterDescriptorEntry
+	DestinationCluster *DestinationClusterDescriptorEntry
+	Metadata           *MetadataDescriptorEntry
+	GRPCMethod         *GRPCMethodDescriptorEntry
 }
 
 // GenericKeyDescriptorEntry  configures a descriptor entry
@@ -601,6 +733,29 @@ type RateLimitPerRoute struct {
 // that contains the remote address (i.e. client IP).
 type RemoteAddressDescriptorEntry struct{}
 
+// SourceClusterDescriptorEntry configures a descriptor entry
+// that contains the local Envoy cluster name.
+type SourceClusterDescriptorEntry struct{}
+
+// DestinationClusterDescriptorEntry configures a descriptor entry
+// that contains the upstream cluster name the request is routed to.
+type DestinationClusterDescriptorEntry struct{}
+
+// MetadataDescriptorEntry configures a descriptor entry
+// whose value is read from the request's dynamic metadata.
+type MetadataDescriptorEntry struct {
+	Key          string
+	Namespace    string
+	Path         []string
+	DefaultValue string
+}
+
+// GRPCMethodDescriptorEntry configures a descriptor entry
+// whose value is the gRPC service and method of the request.
+type GRPCMethodDescriptorEntry struct {
+	Key string
+}
+
 // CORSAllowOriginMatchType differentiates different CORS origin matching
 // methods.
 type CORSAllowOriginMatchType int
@@ -688,6 +843,12 @@ type PeerValidationContext struct {
 	// OptionalClientCertificate when set to true will ensure Envoy does not require
 	// that the client sends a certificate but if one is sent it will process it.
 	OptionalClientCertificate bool
+	// VerifyCertificateSpki holds base64 encoded SHA-256 hashes of the Subject
+	// Public Key Information of the peer certificates that are accepted.
+	VerifyCertificateSpki []string
+	// VerifyCertificateHash holds hex encoded SHA-256 hashes of the peer
+	// certificates that are accepted.
+	VerifyCertificateHash []string
 }
 
 // GetCACertificate returns the CA certificate from PeerValidationContext.
@@ -725,6 +886,15 @@ func (pvc *PeerValidationContext) GetCRL() []byte {
 	return pvc.CRL.Object.Data[CRLKey]
 }
 
+// HasCertificatePins returns true if the peer certificate is
+// pinned by its SPKI or certificate hash.
+func (pvc *PeerValidationContext) HasCertificatePins() bool {
+	if pvc == nil {
+		return false
+	}
+	return len(pvc.VerifyCertificateSpki) > 0 || len(pvc.VerifyCertificateHash) > 0
+}
+
 // A VirtualHost represents a named L4/L7 service.
 type VirtualHost struct {
 	// Name is the fully qualified domain name of a network host,
@@ -748,9 +918,43 @@ type VirtualHost struct {
 	// by IPFilterAllow.
 	IPFilterRules []IPFilterRule
 
+	// HSTS is the Strict-Transport-Security policy added to
+	// responses. It is only set on secure virtual hosts.
+	HSTS *HSTSPolicy
+
+	// NotFoundResponse, if set, is returned for requests that
+	// match none of the virtual host's routes.
+	NotFoundResponse *NotFoundResponse
+
 	Routes map[string]*Route
 }
 
+// NotFoundResponse is the response returned by a virtual host
+// for requests that match none of its routes.
+type NotFoundResponse struct {
+	// StatusCode is the HTTP response status to be returned.
+	StatusCode uint32
+
+	// Body is the content of the response body.
+	Body string
+
+	// ContentType is the value of the Content-Type response header.
+	ContentType string
+}
+
+// HSTSPolicy defines the Strict-Transport-Security response header.
+type HSTSPolicy struct {
+	// MaxAge is the number of seconds clients should
+	// remember to only access the host over HTTPS.
+	MaxAge int64
+
+	// IncludeSubDomains applies the policy to subdomains.
+	IncludeSubDomains bool
+
+	// Preload allows the host to be preloaded by browsers.
+	Preload bool
+}
+
 // Add route to VirtualHosts.Routes map.
 func (v *VirtualHost) AddRoute(route *Route) {
 	if v.Routes == nil {
@@ -814,6 +1018,18 @@ type SecureVirtualHost struct {
 
 	// JWTProviders specify how to verify JWTs.
 	JWTProviders []JWTProvider
+
+	// MergeSlashes overrides the global merge slashes setting for
+	// this vhost's HTTP connection manager, if set.
+	MergeSlashes *bool
+
+	// DisableSessionTickets stops Envoy issuing TLS session
+	// tickets for this vhost.
+	DisableSessionTickets bool
+
+	// CipherSuites overrides the global TLS 1.2 cipher suites
+	// for this vhost, if set.
+	CipherSuites []string
 }
 
 type JWTProvider struct {
@@ -879,6 +1095,19 @@ type ExternalAuthorization struct {
 	// AuthorizationServerWithRequestBody specifies configuration
 	// for buffering request data sent to AuthorizationServer
 	AuthorizationServerWithRequestBody *AuthorizationServerBufferSettings
+
+	// AuthorizationRequestAllowedHeaders lists the client request
+	// headers sent to the authorization server. If empty, all
+	// headers are sent.
+	AuthorizationRequestAllowedHeaders []string
+
+	// AuthorizationRequestDisallowedHeaders lists the client request
+	// headers that are never sent to the authorization server.
+	AuthorizationRequestDisallowedHeaders []string
+
+	// AuthorizationResponseDisallowedHeaders lists the headers that the
+	// authorization server may not set on the client request.
+	AuthorizationResponseDisallowedHeaders []string
 }
 
 // AuthorizationServerBufferSettings enables ExtAuthz filter to buffer client
@@ -960,6 +1189,20 @@ type TCPProxy struct {
 	// Clusters is the, possibly weighted, set
 	// of upstream services to forward decrypted traffic.
 	Clusters []*Cluster
+
+	// IdleTimeout is the timeout for connections that have
+	// no bytes sent or received.
+	IdleTimeout timeout.Setting
+
+	// MaxConnectAttempts is the maximum number of unsuccessful
+	// attempts to connect to an upstream. Zero means use the
+	// Envoy default.
+	MaxConnectAttempts uint32
+
+	// Failover, if true, sends connections to the first of
+	// Clusters that has healthy endpoints, in order, rather
+	// than balancing them across Clusters by weight.
+	Failover bool
 }
 
 // Service represents a single Kubernetes' Service's Port.
@@ -1042,6 +1285,18 @@ type Cluster struct {
 
 	SlowStartConfig *SlowStartConfig
 
+	// LeastRequestConfig holds additional settings for the
+	// WeightedLeastRequest load balancer policy.
+	LeastRequestConfig *LeastRequestConfig
+
+	// RingHashConfig holds additional settings for the ring hash
+	// load balancer used by the RequestHash and Cookie policies.
+	RingHashConfig *RingHashConfig
+
+	// MaglevConfig holds additional settings for the
+	// Maglev load balancer policy.
+	MaglevConfig *MaglevConfig
+
 	// MaxRequestsPerConnection defines the maximum number of requests per connection to the upstream before it is closed.
 	MaxRequestsPerConnection *uint32
 
@@ -1050,6 +1305,18 @@ type Cluster struct {
 
 	// UpstreamTLS contains the TLS version and cipher suite configurations for upstream connections
 	UpstreamTLS *UpstreamTLS
+
+	// ALPNProtocols overrides the ALPN protocols negotiated with the upstream
+	// over TLS. If empty, the protocols are derived from Protocol.
+	ALPNProtocols []string
+
+	// UpstreamProxyProtocol is the PROXY protocol version, either
+	// "v1" or "v2", to send to the upstream. If empty, no PROXY
+	// protocol header is sent.
+	UpstreamProxyProtocol string
+
+	// HTTP2Config holds the HTTP/2 settings for h2 and h2c upstreams.
+	HTTP2Config *HTTP2Config
 }
 
 // WeightedService represents the load balancing weight of a
@@ -1266,6 +1533,16 @@ func wildcardDomainHeaderMatch(fqdn string) HeaderMatchCondition {
 	}
 }
 
+// regexDomainHeaderMatch returns a header match on the ":authority" header
+// for hostnames matching the supplied regex, ignoring any port.
+func regexDomainHeaderMatch(regex string) HeaderMatchCondition {
+	return HeaderMatchCondition{
+		Name:      ":authority",
+		MatchType: HeaderMatchTypeRegex,
+		Value:     "(?:" + regex + ")" + ignorePortRegex,
+	}
+}
+
 // SlowStartConfig holds configuration for gradually increasing amount of traffic to a newly added endpoint.
 type SlowStartConfig struct {
 	Window           time.Duration
@@ -1277,6 +1554,53 @@ func (s *SlowStartConfig) String() string {
 	return fmt.Sprintf("%s%f%d", s.Window.String(), s.Aggression, s.MinWeightPercent)
 }
 
+// LeastRequestConfig holds configuration for the least request load balancer.
+type LeastRequestConfig struct {
+	ActiveRequestBias float64
+}
+
+func (l *LeastRequestConfig) String() string {
+	return fmt.Sprintf("%f", l.ActiveRequestBias)
+}
+
+// RingHashConfig holds configuration for the ring hash load balancer.
+// A zero value means the Envoy default is used.
+type RingHashConfig struct {
+	MinimumRingSize uint64
+	MaximumRingSize uint64
+}
+
+func (r *RingHashConfig) String() string {
+	return fmt.Sprintf("%d/%d", r.MinimumRingSize, r.MaximumRingSize)
+}
+
+// MaglevConfig holds configuration for the Maglev load balancer.
+type MaglevConfig struct {
+	TableSize uint64
+}
+
+func (m *MaglevConfig) String() string {
+	return fmt.Sprintf("%d", m.TableSize)
+}
+
+// HTTP2Config holds HTTP/2 settings for upstream connections.
+// A nil field means Envoy's default is used.
+type HTTP2Config struct {
+	InitialStreamWindowSize     *uint32
+	InitialConnectionWindowSize *uint32
+	MaxConcurrentStreams        *uint32
+}
+
+func (h *HTTP2Config) String() string {
+	str := func(v *uint32) string {
+		if v == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%d", *v)
+	}
+	return str(h.InitialStreamWindowSize) + "/" + str(h.InitialConnectionWindowSize) + "/" + str(h.MaxConcurrentStreams)
+}
+
 // UpstreamTLS holds the TLS configuration for upstream connections
 type UpstreamTLS struct {
 	MinimumProtocolVersion string
@@ -494,3 +494,64 @@ func TestHasConflictRouteForVirtualHost(t *testing.T) {
 		})
 	}
 }
+
+func TestRouteIdentifier(t *testing.T) {
+	tests := map[string]struct {
+		route Route
+		want  string
+	}{
+		"prefix": {
+			route: Route{
+				Kind:               "HTTPProxy",
+				Namespace:          "default",
+				Name:               "kuard",
+				PathMatchCondition: prefixString("/api"),
+			},
+			want: "HTTPProxy/default/kuard/prefix=/api",
+		},
+		"segment prefix": {
+			route: Route{
+				Kind:               KindHTTPRoute,
+				Namespace:          "default",
+				Name:               "kuard",
+				PathMatchCondition: prefixSegment("/api"),
+			},
+			want: "HTTPRoute/default/kuard/segment-prefix=/api",
+		},
+		"exact": {
+			route: Route{
+				Kind:               "HTTPProxy",
+				Namespace:          "default",
+				Name:               "kuard",
+				PathMatchCondition: exact("/api"),
+			},
+			want: "HTTPProxy/default/kuard/exact=/api",
+		},
+		"regex with header and query parameter conditions": {
+			route: Route{
+				Kind:               "HTTPProxy",
+				Namespace:          "default",
+				Name:               "kuard",
+				PathMatchCondition: regex("/api/.*"),
+				HeaderMatchConditions: []HeaderMatchCondition{
+					{Name: "x-env", MatchType: HeaderMatchTypeExact, Value: "prod"},
+					{Name: "x-canary", MatchType: HeaderMatchTypePresent, Invert: true},
+					{Name: "x-team", MatchType: HeaderMatchTypeContains, Value: "A", IgnoreCase: true},
+				},
+				QueryParamMatchConditions: []QueryParamMatchCondition{
+					{Name: "debug", MatchType: QueryParamMatchTypePresent},
+					{Name: "v", MatchType: QueryParamMatchTypePrefix, Value: "2"},
+				},
+			},
+			want: "HTTPProxy/default/kuard/regex=/api/.*," +
+				"header:x-env:exact=prod,header:x-canary:not-present,header:x-team:contains-ignorecase=A," +
+				"query:debug:present,query:v:prefix=2",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.route.Identifier())
+		})
+	}
+}
@@ -147,7 +147,7 @@ func (p *ExtensionServiceProcessor) buildExtensionService(
 
 	lbPolicy := loadBalancerPolicy(ext.Spec.LoadBalancerPolicy)
 	switch lbPolicy {
-	case LoadBalancerPolicyCookie, LoadBalancerPolicyRequestHash:
+	case LoadBalancerPolicyCookie, LoadBalancerPolicyRequestHash, LoadBalancerPolicyMaglev:
 		validCondition.AddWarningf(contour_v1.ConditionTypeSpecError, "IgnoredField",
 			"ignoring field %q; %s load balancer policy is not supported for ExtensionClusters",
 			".Spec.LoadBalancerPolicy", lbPolicy)
@@ -1014,6 +1014,7 @@ func (p *GatewayAPIProcessor) computeGatewayConditions(gwAccessor *status.Gatewa
 
 func (p *GatewayAPIProcessor) computeTLSRouteForListener(route *gatewayapi_v1alpha2.TLSRoute, routeAccessor *status.RouteParentStatusUpdate, listener *listenerInfo, hosts sets.Set[string]) bool {
 	var programmed bool
+	conflicts := sets.New[string]()
 	for _, rule := range route.Spec.Rules {
 		if len(rule.BackendRefs) == 0 {
 			routeAccessor.AddCondition(gatewayapi_v1.RouteConditionResolvedRefs, meta_v1.ConditionFalse, status.ReasonDegraded, "At least one Spec.Rules.BackendRef must be specified.")
@@ -1067,6 +1068,14 @@ func (p *GatewayAPIProcessor) computeTLSRouteForListener(route *gatewayapi_v1alp
 		}
 
 		for host := range hosts {
+			// Passthrough and terminating virtual hosts share a listener
+			// and are told apart by SNI, so a hostname that already
+			// terminates TLS with routes cannot also be passed through.
+			if existing := p.dag.GetSecureVirtualHost(listener.dagListenerName, host); existing != nil && existing.TCPProxy == nil && len(existing.Routes) > 0 {
+				conflicts.Insert(host)
+				continue
+			}
+
 			secure := p.dag.EnsureSecureVirtualHost(listener.dagListenerName, host)
 
 			if listener.tlsSecret != nil {
@@ -1079,9 +1088,46 @@ func (p *GatewayAPIProcessor) computeTLSRouteForListener(route *gatewayapi_v1alp
 		}
 	}
 
+	addHostnameConflictCondition(routeAccessor, status.MessageHostnameConflict, conflicts, programmed)
+
 	return programmed
 }
 
+// passthroughHostConflicts splits hosts into those that can terminate TLS
+// on the listener and those that are already proxied by a TLSRoute.
+// Passthrough and terminating virtual hosts share a listener and are told
+// apart by SNI, so a hostname cannot be both.
+func (p *GatewayAPIProcessor) passthroughHostConflicts(listener *listenerInfo, hosts sets.Set[string]) (sets.Set[string], sets.Set[string]) {
+	conflicts := sets.New[string]()
+	if listener.tlsSecret == nil {
+		return hosts, conflicts
+	}
+
+	for host := range hosts {
+		if existing := p.dag.GetSecureVirtualHost(listener.dagListenerName, host); existing != nil && existing.TCPProxy != nil {
+			conflicts.Insert(host)
+		}
+	}
+
+	return hosts.Difference(conflicts), conflicts
+}
+
+// addHostnameConflictCondition reports hostnames that were skipped because
+// they conflict with another route on the listener. The route is partially
+// invalid if any other hostname was programmed and not accepted otherwise.
+func addHostnameConflictCondition(routeAccessor *status.RouteParentStatusUpdate, format string, conflicts sets.Set[string], programmed bool) {
+	if conflicts.Len() == 0 {
+		return
+	}
+
+	msg := fmt.Sprintf(format, strings.Join(sets.List(conflicts), ", "))
+	if programmed {
+		routeAccessor.AddCondition(gatewayapi_v1.RouteConditionPartiallyInvalid, meta_v1.ConditionTrue, status.ReasonHostnameConflict, msg)
+	} else {
+		routeAccessor.AddCondition(gatewayapi_v1.RouteConditionAccepted, meta_v1.ConditionFalse, status.ReasonHostnameConflict, msg)
+	}
+}
+
 // Resolve route references for a route and do not program any routes.
 func (p *GatewayAPIProcessor) resolveRouteRefs(route any, routeAccessor *status.RouteParentStatusUpdate) {
 	switch route := route.(type) {
@@ -1202,6 +1248,8 @@ func (p *GatewayAPIProcessor) computeHTTPRouteForListener(
 	listener *listenerInfo,
 	hosts sets.Set[string],
 ) {
+	hosts, conflicts := p.passthroughHostConflicts(listener, hosts)
+
 	// Count number of rules under this Route that are invalid.
 	invalidRuleCnt := 0
 	for ruleIndex, rule := range route.Spec.Rules {
@@ -1510,6 +1558,8 @@ func (p *GatewayAPIProcessor) computeHTTPRouteForListener(
 		// Some of the rules are conflicted, mark it as partially invalid.
 		addRoutePartiallyInvalidConditionDueToMatchPartiallyConflict(routeAccessor, KindHTTPRoute)
 	}
+
+	addHostnameConflictCondition(routeAccessor, status.MessagePassthroughHostnameConflict, conflicts, hosts.Len() > 0)
 }
 
 func (p *GatewayAPIProcessor) hasConflictRoute(listener *listenerInfo, hosts sets.Set[string], routes []*Route) bool {
@@ -1535,6 +1585,9 @@ func (p *GatewayAPIProcessor) hasConflictRoute(listener *listenerInfo, hosts set
 
 func (p *GatewayAPIProcessor) computeGRPCRouteForListener(route *gatewayapi_v1.GRPCRoute, routeAccessor *status.RouteParentStatusUpdate, listener *listenerInfo, hosts sets.Set[string]) bool {
 	var programmed bool
+
+	hosts, conflicts := p.passthroughHostConflicts(listener, hosts)
+
 	invalidRuleCnt := 0
 	for ruleIndex, rule := range route.Spec.Rules {
 		// Get match conditions for the rule.
@@ -1689,6 +1742,8 @@ func (p *GatewayAPIProcessor) computeGRPCRouteForListener(route *gatewayapi_v1.G
 		addRoutePartiallyInvalidConditionDueToMatchPartiallyConflict(routeAccessor, KindGRPCRoute)
 	}
 
+	addHostnameConflictCondition(routeAccessor, status.MessagePassthroughHostnameConflict, conflicts, hosts.Len() > 0)
+
 	return programmed
 }
 
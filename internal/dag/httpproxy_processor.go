@@ -14,23 +14,28 @@
 package dag
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
+	"math"
 	"net"
 	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 	"github.com/projectcontour/contour/internal/annotation"
+	"github.com/projectcontour/contour/internal/ingressclass"
 	"github.com/projectcontour/contour/internal/k8s"
 	"github.com/projectcontour/contour/internal/status"
 	"github.com/projectcontour/contour/internal/timeout"
@@ -119,6 +124,11 @@ type HTTPProxyProcessor struct {
 	// UpstreamTLS defines the TLS settings like min/max version
 	// and cipher suites for upstream connections.
 	UpstreamTLS *UpstreamTLS
+
+	// DisableMergeSlashes is the global setting for Envoy's merge_slashes
+	// option, used to detect HTTPProxies whose path normalization policy
+	// can't be honored on shared listeners.
+	DisableMergeSlashes bool
 }
 
 // Run translates HTTPProxies into DAG objects and
@@ -182,12 +192,40 @@ func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_v1.HTTPProxy) {
 		return
 	}
 
+	if ingressclass.HTTPProxyConflict(proxy) {
+		addIngressClassConflictError(validCond, proxy)
+		return
+	}
+
 	if len(proxy.Spec.Routes) == 0 && len(proxy.Spec.Includes) == 0 && proxy.Spec.TCPProxy == nil {
 		validCond.AddError(contour_v1.ConditionTypeSpecError, "NothingDefined",
 			"HTTPProxy.Spec must have at least one Route, Include, or a TCPProxy")
 		return
 	}
 
+	if fqdnRegex := proxy.Spec.VirtualHost.FqdnRegex; fqdnRegex != "" {
+		if !strings.HasPrefix(host, "*.") {
+			validCond.AddError(contour_v1.ConditionTypeVirtualHostError, "FQDNRegexNotValid",
+				"Spec.VirtualHost.FqdnRegex requires Spec.VirtualHost.Fqdn to be a wildcard")
+			return
+		}
+		if proxy.Spec.TCPProxy != nil {
+			validCond.AddError(contour_v1.ConditionTypeVirtualHostError, "FQDNRegexNotValid",
+				"Spec.VirtualHost.FqdnRegex cannot be used with Spec.TCPProxy")
+			return
+		}
+		if err := ValidateRegex(fqdnRegex); err != nil {
+			validCond.AddErrorf(contour_v1.ConditionTypeVirtualHostError, "FQDNRegexNotValid",
+				"Spec.VirtualHost.FqdnRegex %q is invalid: %s", fqdnRegex, err)
+			return
+		}
+		if conflicts := p.fqdnRegexConflicts(proxy); len(conflicts) > 0 {
+			validCond.AddWarningf(contour_v1.ConditionTypeVirtualHostError, "FQDNRegexConflict",
+				"Spec.VirtualHost.FqdnRegex %q matches hosts served by other HTTPProxies, which take precedence: %s",
+				fqdnRegex, strings.Join(conflicts, ", "))
+		}
+	}
+
 	if len(proxy.Spec.VirtualHost.JWTProviders) > 0 {
 		if proxy.Spec.VirtualHost.TLS == nil || len(proxy.Spec.VirtualHost.TLS.SecretName) == 0 {
 			validCond.AddError(contour_v1.ConditionTypeJWTVerificationError, "JWTVerificationNotPermitted",
@@ -208,6 +246,12 @@ func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_v1.HTTPProxy) {
 		return
 	}
 
+	if proxy.Spec.VirtualHost.AllowHTTP01Challenge && proxy.Spec.VirtualHost.TLS == nil {
+		validCond.AddError(contour_v1.ConditionTypeVirtualHostError, "HTTP01ChallengeNotValid",
+			"Spec.VirtualHost.AllowHTTP01Challenge requires Spec.VirtualHost.TLS to be configured")
+		return
+	}
+
 	var tlsEnabled bool
 	if tls := proxy.Spec.VirtualHost.TLS; tls != nil {
 		if tls.Passthrough && tls.EnableFallbackCertificate {
@@ -232,6 +276,24 @@ func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_v1.HTTPProxy) {
 			return
 		}
 
+		if tls.Passthrough && tls.HSTS != nil {
+			validCond.AddError(contour_v1.ConditionTypeTLSError, "TLSIncompatibleFeatures",
+				"Spec.VirtualHost.TLS passthrough cannot be combined with tls.hsts")
+			return
+		}
+
+		if tls.Passthrough && tls.DisableSessionTickets {
+			validCond.AddError(contour_v1.ConditionTypeTLSError, "TLSIncompatibleFeatures",
+				"Spec.VirtualHost.TLS passthrough cannot be combined with tls.disableSessionTickets")
+			return
+		}
+
+		if tls.Passthrough && len(tls.CipherSuites) > 0 {
+			validCond.AddError(contour_v1.ConditionTypeTLSError, "TLSIncompatibleFeatures",
+				"Spec.VirtualHost.TLS passthrough cannot be combined with tls.cipherSuites")
+			return
+		}
+
 		tlsEnabled = true
 
 		// Attach secrets to TLS enabled vhosts.
@@ -245,10 +307,16 @@ func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_v1.HTTPProxy) {
 				} else {
 					validCond.AddErrorf(contour_v1.ConditionTypeTLSError, "SecretNotValid",
 						"Spec.VirtualHost.TLS Secret %q is invalid: %s", tls.SecretName, err)
+					addValidationError(pa, err)
 				}
 				return
 			}
 
+			if !certificateCoversHostname(sec.Cert(), host) {
+				validCond.AddWarningf(contour_v1.ConditionTypeTLSError, "CertificateHostnameMismatch",
+					"Spec.VirtualHost.TLS Secret %q certificate does not cover fqdn %q", tls.SecretName, host)
+			}
+
 			listener, err := p.dag.GetSingleListener("https")
 			if err != nil {
 				validCond.AddError(contour_v1.ConditionTypeListenerError, "ErrorIdentifyingListener", err.Error())
@@ -266,10 +334,29 @@ func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_v1.HTTPProxy) {
 				return
 			}
 
+			hsts, err := hstsPolicy(tls.HSTS)
+			if err != nil {
+				validCond.AddErrorf(contour_v1.ConditionTypeTLSError, "HSTSPolicyNotValid",
+					"Spec.VirtualHost.TLS.HSTS: %s", err)
+				return
+			}
+
+			if err := contour_v1alpha1.ValidateTLSCiphers(tls.CipherSuites); err != nil {
+				validCond.AddErrorf(contour_v1.ConditionTypeTLSError, "CipherSuitesNotValid",
+					"Spec.VirtualHost.TLS.CipherSuites: %s", err)
+				return
+			}
+
 			svhost := p.dag.EnsureSecureVirtualHost(listener.Name, host)
 			svhost.Secret = sec
 			svhost.MinTLSVersion = minTLSVer
 			svhost.MaxTLSVersion = maxTLSVer
+			svhost.HSTS = hsts
+			svhost.DisableSessionTickets = tls.DisableSessionTickets
+			svhost.CipherSuites = tls.CipherSuites
+			if pnp := proxy.Spec.VirtualHost.PathNormalizationPolicy; pnp != nil {
+				svhost.MergeSlashes = pnp.MergeSlashes
+			}
 
 			// Check if FallbackCertificate && ClientValidation are both enabled in the same vhost
 			if tls.EnableFallbackCertificate && tls.ClientValidation != nil {
@@ -326,6 +413,10 @@ func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_v1.HTTPProxy) {
 						DNS:     tls.ClientValidation.ForwardClientCertificate.DNS,
 						URI:     tls.ClientValidation.ForwardClientCertificate.URI,
 					}
+					if *dv.ForwardClientCertificate == (ClientCertificateDetails{}) {
+						validCond.AddWarning(contour_v1.ConditionTypeTLSError, "ClientCertificateDetailsNotSelected",
+							"Spec.VirtualHost.TLS.ClientValidation.ForwardClientCertificate does not select any certificate details, only the certificate hash is forwarded")
+					}
 				}
 				if tls.ClientValidation.CACertificate != "" {
 					secretName := k8s.NamespacedNameFrom(tls.ClientValidation.CACertificate, k8s.DefaultNamespace(proxy.Namespace))
@@ -338,6 +429,7 @@ func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_v1.HTTPProxy) {
 							// PeerValidationContext is requested, but cert is missing or not configured.
 							validCond.AddErrorf(contour_v1.ConditionTypeTLSError, "ClientValidationInvalid",
 								"Spec.VirtualHost.TLS client validation is invalid: invalid CA Secret %q: %s", secretName, err)
+							addValidationError(pa, err)
 						}
 						return
 					}
@@ -359,11 +451,15 @@ func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_v1.HTTPProxy) {
 							// CRL is missing or not configured.
 							validCond.AddErrorf(contour_v1.ConditionTypeTLSError, "ClientValidationInvalid",
 								"Spec.VirtualHost.TLS client validation is invalid: invalid CRL Secret %q: %s", secretName, err)
+							addValidationError(pa, err)
 						}
 						return
 					}
 					dv.CRL = crl
 					dv.OnlyVerifyLeafCertCrl = tls.ClientValidation.OnlyVerifyLeafCertCrl
+				} else if tls.ClientValidation.OnlyVerifyLeafCertCrl {
+					validCond.AddWarningf(contour_v1.ConditionTypeTLSError, "IgnoredField",
+						"ignoring field %q; it requires a CRL Secret", "Spec.VirtualHost.TLS.ClientValidation.OnlyVerifyLeafCertCrl")
 				}
 				svhost.DownstreamValidation = dv
 			}
@@ -403,6 +499,12 @@ func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_v1.HTTPProxy) {
 					return
 				}
 
+				if len(jwksURL.Hostname()) == 0 {
+					validCond.AddErrorf(contour_v1.ConditionTypeJWTVerificationError, "RemoteJWKSHostMissing",
+						"Spec.VirtualHost.JWTProviders.RemoteJWKS.URI %q must include a host", jwtProvider.RemoteJWKS.URI)
+					return
+				}
+
 				var uv *PeerValidationContext
 
 				if jwtProvider.RemoteJWKS.UpstreamValidation != nil {
@@ -514,12 +616,14 @@ func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_v1.HTTPProxy) {
 				"Spec.TCPProxy requires that either Spec.TLS.Passthrough or Spec.TLS.SecretName be set")
 			return
 		}
-		if !p.processHTTPProxyTCPProxy(validCond, proxy, nil, host) {
+		if !p.processHTTPProxyTCPProxy(pa, proxy, nil, host) {
 			return
 		}
 	}
 
-	routes := p.computeRoutes(validCond, proxy, proxy, nil, nil, tlsEnabled, defaultJWTProvider)
+	routes := p.computeRoutes(validCond, pa, proxy, proxy, nil, nil, nil, tlsEnabled, defaultJWTProvider)
+
+	p.checkPathNormalizationPolicy(validCond, proxy, routes)
 
 	listener, err := p.dag.GetSingleListener("http")
 	if err != nil {
@@ -537,6 +641,14 @@ func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_v1.HTTPProxy) {
 	}
 	insecure.CORSPolicy = cp
 
+	nfr, err := toNotFoundResponse(proxy.Spec.VirtualHost.NotFoundResponse)
+	if err != nil {
+		validCond.AddErrorf(contour_v1.ConditionTypeVirtualHostError, "NotFoundResponseNotValid",
+			"Spec.VirtualHost.NotFoundResponse: %s", err)
+		return
+	}
+	insecure.NotFoundResponse = nfr
+
 	var isValidRLP bool
 	insecure.RateLimitPolicy, isValidRLP = computeVirtualHostRateLimitPolicy(proxy, p.GlobalRateLimitService, validCond)
 	if !isValidRLP {
@@ -556,6 +668,10 @@ func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_v1.HTTPProxy) {
 
 	addRoutes(insecure, routes)
 
+	if proxy.Spec.VirtualHost.AllowHTTP01Challenge {
+		addHTTP01ChallengeRoutes(insecure, routes)
+	}
+
 	// if TLS is enabled for this virtual host and there is no tcp proxy defined,
 	// then add routes to the secure virtualhost definition.
 	if tlsEnabled && proxy.Spec.TCPProxy == nil {
@@ -567,6 +683,7 @@ func (p *HTTPProxyProcessor) computeHTTPProxy(proxy *contour_v1.HTTPProxy) {
 
 		secure := p.dag.EnsureSecureVirtualHost(listener.Name, host)
 		secure.CORSPolicy = cp
+		secure.NotFoundResponse = nfr
 
 		secure.RateLimitPolicy, isValidRLP = computeVirtualHostRateLimitPolicy(proxy, p.GlobalRateLimitService, validCond)
 		if !isValidRLP {
@@ -628,6 +745,81 @@ func addRoutes(vhost vhost, routes []*Route) {
 	}
 }
 
+// http01ChallengePrefix is the path prefix ACME issuers request when
+// solving HTTP-01 challenges.
+const http01ChallengePrefix = "/.well-known/acme-challenge/"
+
+// addHTTP01ChallengeRoutes serves requests for the ACME HTTP-01 challenge
+// path over HTTP instead of redirecting them to HTTPS. Routes matching
+// only paths under the challenge path are replaced by copies that do not
+// redirect, as they take precedence over the challenge route. For routes
+// whose prefix covers the whole challenge path, a copy restricted to the
+// challenge path is added, taken from the route with the longest prefix
+// as that is the route that would have matched the request.
+func addHTTP01ChallengeRoutes(vhost *VirtualHost, routes []*Route) {
+	var covering []*Route
+	for _, route := range routes {
+		if !route.HTTPSUpgrade {
+			continue
+		}
+
+		switch {
+		case withinHTTP01Challenge(route.PathMatchCondition):
+			challenge := *route
+			challenge.HTTPSUpgrade = false
+			vhost.AddRoute(&challenge)
+		case matchesHTTP01Challenge(route.PathMatchCondition):
+			covering = append(covering, route)
+		}
+	}
+
+	sort.SliceStable(covering, func(i, j int) bool {
+		return len(covering[i].PathMatchCondition.(*PrefixMatchCondition).Prefix) >
+			len(covering[j].PathMatchCondition.(*PrefixMatchCondition).Prefix)
+	})
+
+	for _, route := range covering {
+		challenge := *route
+		challenge.PathMatchCondition = &PrefixMatchCondition{Prefix: http01ChallengePrefix}
+		challenge.HTTPSUpgrade = false
+
+		if _, ok := vhost.Routes[conditionsToString(&challenge)]; ok {
+			continue
+		}
+		vhost.AddRoute(&challenge)
+	}
+}
+
+// withinHTTP01Challenge returns true if the path match condition only
+// matches requests under the ACME HTTP-01 challenge path.
+func withinHTTP01Challenge(cond MatchCondition) bool {
+	switch cond := cond.(type) {
+	case *PrefixMatchCondition:
+		return strings.HasPrefix(cond.Prefix, http01ChallengePrefix)
+	case *ExactMatchCondition:
+		return strings.HasPrefix(cond.Path, http01ChallengePrefix)
+	default:
+		return false
+	}
+}
+
+// matchesHTTP01Challenge returns true if the path match condition covers
+// every request for the ACME HTTP-01 challenge path.
+func matchesHTTP01Challenge(cond MatchCondition) bool {
+	prefix, ok := cond.(*PrefixMatchCondition)
+	if !ok || !strings.HasPrefix(http01ChallengePrefix, prefix.Prefix) {
+		return false
+	}
+
+	switch prefix.PrefixMatchType {
+	case PrefixMatchSegment:
+		rest := strings.TrimPrefix(http01ChallengePrefix, prefix.Prefix)
+		return strings.HasSuffix(prefix.Prefix, "/") || strings.HasPrefix(rest, "/")
+	default:
+		return true
+	}
+}
+
 func (p *HTTPProxyProcessor) addStatusBadGatewayRoute(routes []*Route, conds []contour_v1.MatchCondition, proxy *contour_v1.HTTPProxy) []*Route {
 	if len(conds) > 0 {
 		route := &Route{
@@ -648,15 +840,44 @@ func (p *HTTPProxyProcessor) addStatusBadGatewayRoute(routes []*Route, conds []c
 	return routes
 }
 
+// checkPathNormalizationPolicy adds a warning to validCond if the proxy's
+// path normalization policy disagrees with the global settings used by the
+// listeners it shares with other virtual hosts. Path normalization is an HTTP
+// connection manager setting, so it can only be overridden on the vhost's own
+// TLS filter chain.
+func (p *HTTPProxyProcessor) checkPathNormalizationPolicy(validCond *contour_v1.DetailedCondition, proxy *contour_v1.HTTPProxy, routes []*Route) {
+	pnp := proxy.Spec.VirtualHost.PathNormalizationPolicy
+	if pnp == nil || pnp.MergeSlashes == nil || *pnp.MergeSlashes == !p.DisableMergeSlashes {
+		return
+	}
+
+	for _, route := range routes {
+		if !route.HTTPSUpgrade {
+			validCond.AddWarning(contour_v1.ConditionTypeVirtualHostError, "PathNormalizationPolicyNotApplied",
+				"Spec.VirtualHost.PathNormalizationPolicy.MergeSlashes is not applied to insecure requests, the HTTP listener is shared and uses the global setting")
+			break
+		}
+	}
+
+	if tls := proxy.Spec.VirtualHost.TLS; tls != nil && tls.EnableFallbackCertificate {
+		validCond.AddWarning(contour_v1.ConditionTypeVirtualHostError, "PathNormalizationPolicyNotApplied",
+			"Spec.VirtualHost.PathNormalizationPolicy.MergeSlashes is not applied to requests using the fallback certificate, its filter chain is shared and uses the global setting")
+	}
+}
+
 func (p *HTTPProxyProcessor) computeRoutes(
-	validCond *contour_v1.DetailedCondition,
+	rootCond *contour_v1.DetailedCondition,
+	pa *status.ProxyUpdate,
 	rootProxy *contour_v1.HTTPProxy,
 	proxy *contour_v1.HTTPProxy,
 	conditions []contour_v1.MatchCondition,
+	includeRequestHeadersPolicy *HeadersPolicy,
 	visited []*contour_v1.HTTPProxy,
 	enforceTLS bool,
 	defaultJWTProvider string,
 ) []*Route {
+	validCond := pa.ConditionFor(status.ValidCondition)
+
 	for _, v := range visited {
 		// ensure we are not following an edge that produces a cycle
 		var path []string
@@ -665,15 +886,35 @@ func (p *HTTPProxyProcessor) computeRoutes(
 		}
 		if v.Name == proxy.Name && v.Namespace == proxy.Namespace {
 			path = append(path, fmt.Sprintf("%s/%s", proxy.Namespace, proxy.Name))
-			validCond.AddErrorf(contour_v1.ConditionTypeIncludeError, "IncludeCreatesCycle",
-				"include creates an include cycle: %s", strings.Join(path, " -> "))
+			msg := fmt.Sprintf("include creates an include cycle: %s", strings.Join(path, " -> "))
+			validCond.AddError(contour_v1.ConditionTypeIncludeError, "IncludeCreatesCycle", msg)
+
+			// Also mark the root invalid, since the cycle is otherwise
+			// only reported on the proxy that closes it. The same cycle
+			// can be reached through several includes, so only report
+			// each path once.
+			if rootCond != validCond && !slices.ContainsFunc(rootCond.Errors, func(e contour_v1.SubCondition) bool {
+				return e.Reason == "IncludeCreatesCycle" && e.Message == msg
+			}) {
+				rootCond.AddError(contour_v1.ConditionTypeIncludeError, "IncludeCreatesCycle", msg)
+			}
 			return nil
 		}
 	}
 
+	// The root proxy's ingress class was checked in computeHTTPProxy.
+	if proxy != rootProxy && ingressclass.HTTPProxyConflict(proxy) {
+		addIngressClassConflictError(validCond, proxy)
+		return nil
+	}
+
 	visited = append(visited, proxy)
 	var routes []*Route
 
+	dynamicHeaders := map[string]string{
+		"CONTOUR_NAMESPACE": proxy.Namespace,
+	}
+
 	// Loop over and process all includes, including checking for duplicate conditions.
 	seenConds := map[string][]matchConditionAggregate{}
 	for _, include := range proxy.Spec.Includes {
@@ -731,27 +972,40 @@ func (p *HTTPProxyProcessor) computeRoutes(
 			continue
 		}
 
+		incHP, err := headersPolicyRoute(include.RequestHeadersPolicy, false /* disallow Host */, dynamicHeaders)
+		if err != nil {
+			validCond.AddErrorf(contour_v1.ConditionTypeIncludeError, "RequestHeadersPolicyInvalid",
+				"include %s/%s: %s on request headers", namespace, include.Name, err)
+
+			// The included proxy is not orphaned, it is only unreachable
+			// through this include, so report the error on it as well.
+			inc, incCommit := p.dag.StatusCache.ProxyAccessor(includedProxy)
+			inc.ConditionFor(status.ValidCondition).AddErrorf(contour_v1.ConditionTypeIncludeError, "RequestHeadersPolicyInvalid",
+				"included by %s/%s: %s on request headers", proxy.Namespace, proxy.Name, err)
+			incCommit()
+			delete(p.orphaned, types.NamespacedName{Name: includedProxy.Name, Namespace: includedProxy.Namespace})
+			continue
+		}
+
 		inc, incCommit := p.dag.StatusCache.ProxyAccessor(includedProxy)
-		incValidCond := inc.ConditionFor(status.ValidCondition)
-		routes = append(routes, p.computeRoutes(incValidCond, rootProxy, includedProxy, append(conditions, include.Conditions...), visited, enforceTLS, defaultJWTProvider)...)
+		routes = append(routes, p.computeRoutes(rootCond, inc, rootProxy, includedProxy, append(conditions, include.Conditions...),
+			mergeHeadersPolicies(includeRequestHeadersPolicy, incHP), visited, enforceTLS, defaultJWTProvider)...)
 		incCommit()
 
 		// dest is not an orphaned httpproxy, as there is an httpproxy that points to it
 		delete(p.orphaned, types.NamespacedName{Name: includedProxy.Name, Namespace: includedProxy.Namespace})
 	}
 
-	dynamicHeaders := map[string]string{
-		"CONTOUR_NAMESPACE": proxy.Namespace,
-	}
-
 	for _, route := range proxy.Spec.Routes {
+		routeMatch := status.RouteMatch(route.Conditions)
+
 		if err := routeActionCountValid(route); err != nil {
-			validCond.AddError(contour_v1.ConditionTypeRouteError, "RouteActionCountNotValid", err.Error())
+			validCond.AddRouteError(routeMatch, contour_v1.ConditionTypeRouteError, "RouteActionCountNotValid", err.Error())
 			return nil
 		}
 
 		if err := pathMatchConditionsValid(route.Conditions); err != nil {
-			validCond.AddErrorf(contour_v1.ConditionTypeRouteError, "PathMatchConditionsNotValid",
+			validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeRouteError, "PathMatchConditionsNotValid",
 				"route: %s", err)
 			return nil
 		}
@@ -761,49 +1015,56 @@ func (p *HTTPProxyProcessor) computeRoutes(
 
 		// Look for invalid header conditions on this route
 		if err := headerMatchConditionsValid(routeConditions); err != nil {
-			validCond.AddError(contour_v1.ConditionTypeRouteError, "HeaderMatchConditionsNotValid",
+			validCond.AddRouteError(routeMatch, contour_v1.ConditionTypeRouteError, "HeaderMatchConditionsNotValid",
 				err.Error())
 			return nil
 		}
 
 		// Look for invalid query parameter conditions on this route
 		if err := queryParameterMatchConditionsValid(routeConditions); err != nil {
-			validCond.AddError(contour_v1.ConditionTypeRouteError, "QueryParameterMatchConditionsNotValid",
+			validCond.AddRouteError(routeMatch, contour_v1.ConditionTypeRouteError, "QueryParameterMatchConditionsNotValid",
 				err.Error())
 			return nil
 		}
 
+		if route.PermitInsecure && route.RequireTLS {
+			validCond.AddRouteError(routeMatch, contour_v1.ConditionTypeRouteError, "RequireTLSNotValid",
+				"route cannot set both permitInsecure and requireTLS")
+			return nil
+		}
+
 		reqHP, err := headersPolicyRoute(route.RequestHeadersPolicy, true /* allow Host */, dynamicHeaders)
 		if err != nil {
-			validCond.AddErrorf(contour_v1.ConditionTypeRouteError, "RequestHeadersPolicyInvalid",
+			validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeRouteError, "RequestHeadersPolicyInvalid",
 				"%s on request headers", err)
 			return nil
 		}
+		reqHP = mergeHeadersPolicies(includeRequestHeadersPolicy, reqHP)
 
 		respHP, err := headersPolicyRoute(route.ResponseHeadersPolicy, false /* disallow Host */, dynamicHeaders)
 		if err != nil {
-			validCond.AddErrorf(contour_v1.ConditionTypeRouteError, "ResponseHeaderPolicyInvalid",
+			validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeRouteError, "ResponseHeaderPolicyInvalid",
 				"%s on response headers", err)
 			return nil
 		}
 
 		cookieRP, err := cookieRewritePolicies(route.CookieRewritePolicies)
 		if err != nil {
-			validCond.AddErrorf(contour_v1.ConditionTypeRouteError, "CookieRewritePoliciesInvalid",
+			validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeRouteError, "CookieRewritePoliciesInvalid",
 				"%s on route cookie rewrite rules", err)
 			return nil
 		}
 
 		rtp, ctp, err := timeoutPolicy(route.TimeoutPolicy, p.ConnectTimeout)
 		if err != nil {
-			validCond.AddErrorf(contour_v1.ConditionTypeRouteError, "TimeoutPolicyNotValid",
+			validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeRouteError, "TimeoutPolicyNotValid",
 				"route.timeoutPolicy failed to parse: %s", err)
 			return nil
 		}
 
 		rlp, err := rateLimitPolicy(route.RateLimitPolicy)
 		if err != nil {
-			validCond.AddErrorf(contour_v1.ConditionTypeRouteError, "RateLimitPolicyNotValid",
+			validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeRouteError, "RateLimitPolicyNotValid",
 				"route.rateLimitPolicy is invalid: %s", err)
 			return nil
 		}
@@ -811,24 +1072,78 @@ func (p *HTTPProxyProcessor) computeRoutes(
 		vrl := rateLimitPerRoute(route.RateLimitPolicy)
 
 		requestHashPolicies, lbPolicy := loadBalancerRequestHashPolicies(route.LoadBalancerPolicy, validCond)
+		if lbPolicy == LoadBalancerPolicyMaglev && len(requestHashPolicies) == 0 {
+			validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeRouteError, "LoadBalancerPolicyNotValid",
+				"route.loadBalancerPolicy is invalid: %s strategy requires at least one valid request hash policy", LoadBalancerPolicyMaglev)
+			return nil
+		}
 
 		redirectPolicy, err := redirectRoutePolicy(route.RequestRedirectPolicy)
 		if err != nil {
-			validCond.AddErrorf(contour_v1.ConditionTypeRouteError, "RequestRedirectPolicy",
+			validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeRouteError, "RequestRedirectPolicy",
 				"route.requestRedirectPolicy is invalid: %s", err)
 			return nil
 		}
 
 		irp := internalRedirectPolicy(route.InternalRedirectPolicy)
 
-		directPolicy := directResponsePolicy(route.DirectResponsePolicy)
+		cp, err := toCORSPolicy(route.CORSPolicy)
+		if err != nil {
+			validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeCORSError, "PolicyDidNotParse",
+				"Spec.Routes.CORSPolicy: %s", err)
+			return nil
+		}
+
+		fip, err := faultInjectionPolicy(route.FaultInjectionPolicy)
+		if err != nil {
+			validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeRouteError, "FaultInjectionPolicyNotValid",
+				"route.faultInjectionPolicy is invalid: %s", err)
+			return nil
+		}
+
+		sp, err := sessionPersistence(route.SessionPersistence)
+		if err != nil {
+			validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeRouteError, "SessionPersistenceNotValid",
+				"route.sessionPersistence is invalid: %s", err)
+			return nil
+		}
+
+		directPolicy, err := directResponsePolicy(route.DirectResponsePolicy)
+		if err != nil {
+			validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeRouteError, "DirectResponsePolicy",
+				"route.directResponsePolicy is invalid: %s", err)
+			return nil
+		}
+
+		if directPolicy != nil && route.DirectResponsePolicy.BodyConfigMapRef != nil {
+			ref := route.DirectResponsePolicy.BodyConfigMapRef
+			body, err := p.source.LookupDirectResponseBody(types.NamespacedName{Namespace: proxy.Namespace, Name: ref.Name}, ref.Key)
+			if err != nil {
+				validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeRouteError, "DirectResponseBodyNotFound",
+					"route.directResponsePolicy.bodyConfigMapRef %s/%s is invalid: %s", proxy.Namespace, ref.Name, err)
+				return nil
+			}
+			if err := validDirectResponseBodyTemplate(body); err != nil {
+				validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeRouteError, "DirectResponsePolicy",
+					"route.directResponsePolicy is invalid: %s", err)
+				return nil
+			}
+			directPolicy.Body = body
+			directPolicy.BodyTemplate = strings.Contains(body, "%")
+		}
+
+		if route.Priority < 0 || route.Priority > math.MaxUint8 {
+			validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeRouteError, "PriorityNotValid",
+				"route.priority %d must be between 0 and %d", route.Priority, math.MaxUint8)
+			return nil
+		}
 
 		r := &Route{
 			PathMatchCondition:        mergePathMatchConditions(routeConditions),
 			HeaderMatchConditions:     mergeHeaderMatchConditions(routeConditions),
 			QueryParamMatchConditions: mergeQueryParamMatchConditions(routeConditions),
 			Websocket:                 route.EnableWebsockets,
-			HTTPSUpgrade:              routeEnforceTLS(enforceTLS, route.PermitInsecure && !p.DisablePermitInsecure),
+			HTTPSUpgrade:              routeEnforceTLS(enforceTLS, routePermitInsecure(route, rootProxy.Spec.VirtualHost) && !p.DisablePermitInsecure),
 			TimeoutPolicy:             rtp,
 			RetryPolicy:               retryPolicy(route.RetryPolicy),
 			RequestHeadersPolicy:      reqHP,
@@ -840,6 +1155,10 @@ func (p *HTTPProxyProcessor) computeRoutes(
 			Redirect:                  redirectPolicy,
 			DirectResponse:            directPolicy,
 			InternalRedirectPolicy:    irp,
+			CORSPolicy:                cp,
+			FaultInjectionPolicy:      fip,
+			SessionPersistence:        sp,
+			MatchPriority:             uint8(route.Priority), //nolint:gosec // disable G115
 		}
 
 		if p.SetSourceMetadataOnRoutes {
@@ -892,13 +1211,13 @@ func (p *HTTPProxyProcessor) computeRoutes(
 
 		if len(route.GetPrefixReplacements()) > 0 {
 			if !r.HasPathPrefix() {
-				validCond.AddError(contour_v1.ConditionTypePrefixReplaceError, "MustHavePrefix",
+				validCond.AddRouteError(routeMatch, contour_v1.ConditionTypePrefixReplaceError, "MustHavePrefix",
 					"cannot specify prefix replacements without a prefix condition")
 				return nil
 			}
 
 			if reason, err := prefixReplacementsAreValid(route.GetPrefixReplacements()); err != nil {
-				validCond.AddError(contour_v1.ConditionTypePrefixReplaceError, reason, err.Error())
+				validCond.AddRouteError(routeMatch, contour_v1.ConditionTypePrefixReplaceError, reason, err.Error())
 				return nil
 			}
 
@@ -933,17 +1252,23 @@ func (p *HTTPProxyProcessor) computeRoutes(
 
 		healthPolicy, err := httpHealthCheckPolicy(route.HealthCheckPolicy)
 		if err != nil {
-			validCond.AddError(contour_v1.ConditionTypeRouteError, "HealthCheckPolicyInvalid", err.Error())
+			validCond.AddRouteError(routeMatch, contour_v1.ConditionTypeRouteError, "HealthCheckPolicyInvalid", err.Error())
 			return nil
 		}
 
 		for _, service := range route.Services {
 			if service.Port < 1 || service.Port > 65535 {
-				validCond.AddErrorf(contour_v1.ConditionTypeServiceError, "ServicePortInvalid",
+				validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeServiceError, "ServicePortInvalid",
 					"service %q: port must be in the range 1-65535", service.Name)
 				return nil
 			}
 
+			if service.FailoverPriority > 0 {
+				validCond.AddWarningf(contour_v1.ConditionTypeServiceError, "IgnoredField",
+					"ignoring field %q on service %q; failover priority is only supported for TCPProxy services",
+					"FailoverPriority", service.Name)
+			}
+
 			var healthPort int
 			if healthPolicy != nil && service.HealthPort > 0 {
 				healthPort = service.HealthPort
@@ -954,8 +1279,9 @@ func (p *HTTPProxyProcessor) computeRoutes(
 			m := types.NamespacedName{Name: service.Name, Namespace: proxy.Namespace}
 			s, err := p.dag.EnsureService(m, service.Port, healthPort, p.source, p.EnableExternalNameService)
 			if err != nil {
-				validCond.AddErrorf(contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference",
+				validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference",
 					"Spec.Routes unresolved service reference: %s", err)
+				addValidationError(pa, err)
 				continue
 			}
 			s = serviceCircuitBreakerPolicy(s, p.GlobalCircuitBreakerDefaults)
@@ -963,7 +1289,19 @@ func (p *HTTPProxyProcessor) computeRoutes(
 			// Determine the protocol to use to speak to this Cluster.
 			protocol, err := getProtocol(service, s)
 			if err != nil {
-				validCond.AddError(contour_v1.ConditionTypeServiceError, "UnsupportedProtocol", err.Error())
+				validCond.AddRouteError(routeMatch, contour_v1.ConditionTypeServiceError, "UnsupportedProtocol", err.Error())
+				return nil
+			}
+
+			if len(service.ALPNProtocols) > 0 && protocol != "tls" && protocol != "h2" {
+				validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeServiceError, "UnsupportedALPNProtocols",
+					"Service [%s:%d] alpnProtocols is only supported with the tls or h2 protocol", service.Name, service.Port)
+				return nil
+			}
+
+			proxyProtocol, err := getUpstreamProxyProtocol(service)
+			if err != nil {
+				validCond.AddRouteError(routeMatch, contour_v1.ConditionTypeServiceError, "UnsupportedUpstreamProxyProtocol", err.Error())
 				return nil
 			}
 
@@ -974,11 +1312,12 @@ func (p *HTTPProxyProcessor) computeRoutes(
 				uv, err = p.source.LookupUpstreamValidation(service.UpstreamValidation, caCertNamespacedName, proxy.Namespace)
 				if err != nil {
 					if _, ok := err.(DelegationNotPermittedError); ok {
-						validCond.AddErrorf(contour_v1.ConditionTypeTLSError, "CACertificateNotDelegated",
+						validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeTLSError, "CACertificateNotDelegated",
 							"service.UpstreamValidation.CACertificate Secret %q is not configured for certificate delegation", caCertNamespacedName)
 					} else {
-						validCond.AddErrorf(contour_v1.ConditionTypeServiceError, "TLSUpstreamValidation",
+						validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeServiceError, "TLSUpstreamValidation",
 							"Service [%s:%d] TLS upstream validation policy error: %s", service.Name, service.Port, err)
+						addValidationError(pa, err)
 					}
 					return nil
 				}
@@ -989,20 +1328,20 @@ func (p *HTTPProxyProcessor) computeRoutes(
 
 			reqHP, err := headersPolicyService(p.RequestHeadersPolicy, service.RequestHeadersPolicy, true, dynamicHeaders)
 			if err != nil {
-				validCond.AddErrorf(contour_v1.ConditionTypeServiceError, "RequestHeadersPolicyInvalid",
+				validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeServiceError, "RequestHeadersPolicyInvalid",
 					"%s on request headers", err)
 				return nil
 			}
 			respHP, err := headersPolicyService(p.ResponseHeadersPolicy, service.ResponseHeadersPolicy, false, dynamicHeaders)
 			if err != nil {
-				validCond.AddErrorf(contour_v1.ConditionTypeServiceError, "ResponseHeadersPolicyInvalid",
+				validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeServiceError, "ResponseHeadersPolicyInvalid",
 					"%s on response headers", err)
 				return nil
 			}
 
 			cookieRP, err := cookieRewritePolicies(service.CookieRewritePolicies)
 			if err != nil {
-				validCond.AddErrorf(contour_v1.ConditionTypeRouteError, "CookieRewritePoliciesInvalid",
+				validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeRouteError, "CookieRewritePoliciesInvalid",
 					"%s on service cookie rewrite rules", err)
 				return nil
 			}
@@ -1012,7 +1351,7 @@ func (p *HTTPProxyProcessor) computeRoutes(
 				// Since the client certificate is configured by admin, explicit delegation is not required.
 				clientCertSecret, err = p.source.LookupTLSSecretInsecure(*p.ClientCertificate)
 				if err != nil {
-					validCond.AddErrorf(contour_v1.ConditionTypeTLSError, "SecretNotValid",
+					validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeTLSError, "SecretNotValid",
 						"tls.envoy-client-certificate Secret %q is invalid: %s", p.ClientCertificate, err)
 					return nil
 				}
@@ -1022,19 +1361,57 @@ func (p *HTTPProxyProcessor) computeRoutes(
 			if service.SlowStartPolicy != nil {
 				// Currently Envoy implements slow start only for RoundRobin and WeightedLeastRequest LB strategies.
 				if lbPolicy != "" && lbPolicy != LoadBalancerPolicyRoundRobin && lbPolicy != LoadBalancerPolicyWeightedLeastRequest {
-					validCond.AddErrorf(contour_v1.ConditionTypeServiceError, "SlowStartInvalid",
+					validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeServiceError, "SlowStartInvalid",
 						"slow start is only supported with RoundRobin or WeightedLeastRequest load balancer strategy")
 					return nil
 				}
 
 				slowStart, err = slowStartConfig(service.SlowStartPolicy)
 				if err != nil {
-					validCond.AddErrorf(contour_v1.ConditionTypeServiceError, "SlowStartInvalid",
+					validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeServiceError, "SlowStartInvalid",
 						"%s on slow start", err)
 					return nil
 				}
 			}
 
+			var leastRequest *LeastRequestConfig
+			if route.LoadBalancerPolicy != nil && route.LoadBalancerPolicy.LeastRequestPolicy != nil && lbPolicy == LoadBalancerPolicyWeightedLeastRequest {
+				leastRequest, err = leastRequestConfig(route.LoadBalancerPolicy.LeastRequestPolicy)
+				if err != nil {
+					validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeRouteError, "LeastRequestPolicyInvalid",
+						"%s on least request policy", err)
+					return nil
+				}
+			}
+
+			var ringHash *RingHashConfig
+			if route.LoadBalancerPolicy != nil && route.LoadBalancerPolicy.RingHashPolicy != nil &&
+				(lbPolicy == LoadBalancerPolicyRequestHash || lbPolicy == LoadBalancerPolicyCookie) {
+				ringHash, err = ringHashConfig(route.LoadBalancerPolicy.RingHashPolicy)
+				if err != nil {
+					validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeRouteError, "RingHashPolicyInvalid",
+						"%s on ring hash policy", err)
+					return nil
+				}
+			}
+
+			var maglev *MaglevConfig
+			if route.LoadBalancerPolicy != nil && route.LoadBalancerPolicy.MaglevPolicy != nil && lbPolicy == LoadBalancerPolicyMaglev {
+				maglev, err = maglevConfig(route.LoadBalancerPolicy.MaglevPolicy)
+				if err != nil {
+					validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeRouteError, "MaglevPolicyInvalid",
+						"%s on maglev policy", err)
+					return nil
+				}
+			}
+
+			http2, err := http2Config(service.HTTP2Options, protocol)
+			if err != nil {
+				validCond.AddRouteErrorf(routeMatch, contour_v1.ConditionTypeServiceError, "HTTP2OptionsInvalid",
+					"Service [%s:%d] %s on http2 options", service.Name, service.Port, err)
+				return nil
+			}
+
 			c := &Cluster{
 				Upstream:                      s,
 				LoadBalancerPolicy:            lbPolicy,
@@ -1050,12 +1427,18 @@ func (p *HTTPProxyProcessor) computeRoutes(
 				ClientCertificate:             clientCertSecret,
 				TimeoutPolicy:                 ctp,
 				SlowStartConfig:               slowStart,
+				LeastRequestConfig:            leastRequest,
+				RingHashConfig:                ringHash,
+				MaglevConfig:                  maglev,
 				MaxRequestsPerConnection:      p.MaxRequestsPerConnection,
 				PerConnectionBufferLimitBytes: p.PerConnectionBufferLimitBytes,
 				UpstreamTLS:                   p.UpstreamTLS,
+				ALPNProtocols:                 service.ALPNProtocols,
+				UpstreamProxyProtocol:         proxyProtocol,
+				HTTP2Config:                   http2,
 			}
 			if service.Mirror && len(r.MirrorPolicies) > 0 {
-				validCond.AddError(contour_v1.ConditionTypeServiceError, "OnlyOneMirror",
+				validCond.AddRouteError(routeMatch, contour_v1.ConditionTypeServiceError, "OnlyOneMirror",
 					"only one service per route may be nominated as mirror")
 				return nil
 			}
@@ -1087,14 +1470,18 @@ func (p *HTTPProxyProcessor) computeRoutes(
 		// hostname so we can be sure to only match one DNS label. This is required
 		// as Envoy's virtualhost hostname wildcard matching can match multiple
 		// labels. This match ignores a port in the hostname in case it is present.
-		if strings.HasPrefix(rootProxy.Spec.VirtualHost.Fqdn, "*.") {
+		// If the root proxy supplies its own hostname regex, that is used instead.
+		switch {
+		case rootProxy.Spec.VirtualHost.FqdnRegex != "":
+			r.HeaderMatchConditions = append(r.HeaderMatchConditions, regexDomainHeaderMatch(rootProxy.Spec.VirtualHost.FqdnRegex))
+		case strings.HasPrefix(rootProxy.Spec.VirtualHost.Fqdn, "*."):
 			r.HeaderMatchConditions = append(r.HeaderMatchConditions, wildcardDomainHeaderMatch(rootProxy.Spec.VirtualHost.Fqdn))
 		}
 
 		jwt := route.JWTVerificationPolicy
 		switch {
 		case jwt != nil && len(route.JWTVerificationPolicy.Require) > 0 && route.JWTVerificationPolicy.Disabled:
-			validCond.AddError(contour_v1.ConditionTypeJWTVerificationError, "InvalidJWTVerificationPolicy",
+			validCond.AddRouteError(routeMatch, contour_v1.ConditionTypeJWTVerificationError, "InvalidJWTVerificationPolicy",
 				"route's JWT verification policy cannot specify both require and disabled")
 			return nil
 		case jwt != nil && len(route.JWTVerificationPolicy.Require) > 0:
@@ -1172,13 +1559,23 @@ func toIPFilterRules(allowPolicy, denyPolicy []contour_v1.IPFilterPolicy, validC
 // following the chain of spec.tcpproxy.include references. It returns true if processing
 // was successful, otherwise false if an error was encountered. The details of the error
 // will be recorded on the status of the relevant HTTPProxy object,
-func (p *HTTPProxyProcessor) processHTTPProxyTCPProxy(validCond *contour_v1.DetailedCondition, httpproxy *contour_v1.HTTPProxy, visited []*contour_v1.HTTPProxy, host string) bool {
+func (p *HTTPProxyProcessor) processHTTPProxyTCPProxy(pa *status.ProxyUpdate, httpproxy *contour_v1.HTTPProxy, visited []*contour_v1.HTTPProxy, host string) bool {
 	tcpproxy := httpproxy.Spec.TCPProxy
 	if tcpproxy == nil {
 		// nothing to do
 		return true
 	}
 
+	validCond := pa.ConditionFor(status.ValidCondition)
+
+	// Only the tcpproxy of an included HTTPProxy is used, so its
+	// routes would never be served.
+	if len(visited) > 0 && len(httpproxy.Spec.Routes) > 0 {
+		validCond.AddError(contour_v1.ConditionTypeTCPProxyError, "RoutesNotAllowed",
+			"Spec.Routes cannot be combined with Spec.TCPProxy on an HTTPProxy included by a tcpproxy")
+		return false
+	}
+
 	visited = append(visited, httpproxy)
 
 	// #2218 Allow support for both plural and singular "Include" for TCPProxy for the v1 API Spec
@@ -1196,7 +1593,7 @@ func (p *HTTPProxyProcessor) processHTTPProxyTCPProxy(validCond *contour_v1.Deta
 
 	lbPolicy := loadBalancerPolicy(tcpproxy.LoadBalancerPolicy)
 	switch lbPolicy {
-	case LoadBalancerPolicyCookie, LoadBalancerPolicyRequestHash:
+	case LoadBalancerPolicyCookie, LoadBalancerPolicyRequestHash, LoadBalancerPolicyMaglev:
 		validCond.AddWarningf(contour_v1.ConditionTypeTCPProxyError, "IgnoredField",
 			"ignoring field %q; %s load balancer policy is not supported for TCPProxies",
 			"Spec.TCPProxy.LoadBalancerPolicy", lbPolicy)
@@ -1205,8 +1602,33 @@ func (p *HTTPProxyProcessor) processHTTPProxyTCPProxy(validCond *contour_v1.Deta
 	}
 
 	if len(tcpproxy.Services) > 0 {
-		var proxy TCPProxy
-		for _, service := range httpproxy.Spec.TCPProxy.Services {
+		idleTimeout, err := timeout.Parse(tcpproxy.IdleTimeout)
+		if err != nil {
+			validCond.AddErrorf(contour_v1.ConditionTypeTCPProxyError, "IdleTimeoutNotValid",
+				"Spec.TCPProxy.IdleTimeout is invalid: %s", err)
+			return false
+		}
+		if idleTimeout.Duration() < 0 {
+			validCond.AddErrorf(contour_v1.ConditionTypeTCPProxyError, "IdleTimeoutNotValid",
+				"Spec.TCPProxy.IdleTimeout %q must not be negative", tcpproxy.IdleTimeout)
+			return false
+		}
+
+		services, failover, err := tcpProxyFailoverServices(tcpproxy.Services)
+		if err != nil {
+			validCond.AddErrorf(contour_v1.ConditionTypeTCPProxyError, "FailoverPriorityNotValid",
+				"Spec.TCPProxy.Services is invalid: %s", err)
+			return false
+		}
+
+		proxy := TCPProxy{
+			IdleTimeout: idleTimeout,
+			Failover:    failover,
+		}
+		if tcpproxy.MaxConnectAttempts != nil {
+			proxy.MaxConnectAttempts = *tcpproxy.MaxConnectAttempts
+		}
+		for _, service := range services {
 			var healthPort int
 			healthPolicy := tcpHealthCheckPolicy(tcpproxy.HealthCheckPolicy)
 			if healthPolicy != nil && service.HealthPort > 0 {
@@ -1220,6 +1642,7 @@ func (p *HTTPProxyProcessor) processHTTPProxyTCPProxy(validCond *contour_v1.Deta
 			if err != nil {
 				validCond.AddErrorf(contour_v1.ConditionTypeTCPProxyError, "ServiceUnresolvedReference",
 					"Spec.TCPProxy unresolved service reference: %s", err)
+				addValidationError(pa, err)
 				return false
 			}
 
@@ -1230,6 +1653,18 @@ func (p *HTTPProxyProcessor) processHTTPProxyTCPProxy(validCond *contour_v1.Deta
 				return false
 			}
 
+			if len(service.ALPNProtocols) > 0 && protocol != "tls" && protocol != "h2" {
+				validCond.AddErrorf(contour_v1.ConditionTypeServiceError, "UnsupportedALPNProtocols",
+					"Service [%s:%d] alpnProtocols is only supported with the tls or h2 protocol", service.Name, service.Port)
+				return false
+			}
+
+			proxyProtocol, err := getUpstreamProxyProtocol(service)
+			if err != nil {
+				validCond.AddError(contour_v1.ConditionTypeServiceError, "UnsupportedUpstreamProxyProtocol", err.Error())
+				return false
+			}
+
 			var uv *PeerValidationContext
 			if (protocol == "tls" || protocol == "h2") && service.UpstreamValidation != nil {
 				uv = p.peerValidationContext(validCond, httpproxy, service)
@@ -1250,16 +1685,18 @@ func (p *HTTPProxyProcessor) processHTTPProxyTCPProxy(validCond *contour_v1.Deta
 			}
 
 			proxy.Clusters = append(proxy.Clusters, &Cluster{
-				Upstream:             s,
-				Weight:               uint32(service.Weight), //nolint:gosec // disable G115
-				Protocol:             protocol,
-				LoadBalancerPolicy:   lbPolicy,
-				TCPHealthCheckPolicy: healthPolicy,
-				SNI:                  s.ExternalName,
-				TimeoutPolicy:        ClusterTimeoutPolicy{ConnectTimeout: p.ConnectTimeout},
-				UpstreamTLS:          p.UpstreamTLS,
-				UpstreamValidation:   uv,
-				ClientCertificate:    clientCertSecret,
+				Upstream:              s,
+				Weight:                uint32(service.Weight), //nolint:gosec // disable G115
+				Protocol:              protocol,
+				LoadBalancerPolicy:    lbPolicy,
+				TCPHealthCheckPolicy:  healthPolicy,
+				SNI:                   s.ExternalName,
+				TimeoutPolicy:         ClusterTimeoutPolicy{ConnectTimeout: p.ConnectTimeout},
+				UpstreamTLS:           p.UpstreamTLS,
+				UpstreamValidation:    uv,
+				ClientCertificate:     clientCertSecret,
+				ALPNProtocols:         service.ALPNProtocols,
+				UpstreamProxyProtocol: proxyProtocol,
 			})
 		}
 
@@ -1303,6 +1740,12 @@ func (p *HTTPProxyProcessor) processHTTPProxyTCPProxy(validCond *contour_v1.Deta
 		return false
 	}
 
+	if dest.Spec.TCPProxy == nil {
+		validCond.AddErrorf(contour_v1.ConditionTypeTCPProxyIncludeError, "IncludeNotTCPProxy",
+			"include %s/%s does not define a tcpproxy", dest.Namespace, dest.Name)
+		return false
+	}
+
 	// dest is no longer an orphan
 	delete(p.orphaned, k8s.NamespacedNameOf(dest))
 
@@ -1322,12 +1765,48 @@ func (p *HTTPProxyProcessor) processHTTPProxyTCPProxy(validCond *contour_v1.Deta
 
 	// follow the link and process the target tcpproxy
 	inc, commit := p.dag.StatusCache.ProxyAccessor(dest)
-	incValidCond := inc.ConditionFor(status.ValidCondition)
 	defer commit()
-	ok = p.processHTTPProxyTCPProxy(incValidCond, dest, visited, host)
+	ok = p.processHTTPProxyTCPProxy(inc, dest, visited, host)
 	return ok
 }
 
+// tcpProxyFailoverServices returns the services of a TCPProxy in the
+// order they should be used, and whether connections should fail over
+// between them rather than being balanced across them. Services fail
+// over if any of them has a failover priority.
+func tcpProxyFailoverServices(services []contour_v1.Service) ([]contour_v1.Service, bool, error) {
+	if !slices.ContainsFunc(services, func(s contour_v1.Service) bool { return s.FailoverPriority > 0 }) {
+		return services, false, nil
+	}
+
+	priorities := map[int32]string{}
+	for _, s := range services {
+		if s.Weight > 0 {
+			return nil, false, fmt.Errorf("service %q cannot specify both weight and failoverPriority", s.Name)
+		}
+		if other, ok := priorities[s.FailoverPriority]; ok {
+			return nil, false, fmt.Errorf("services %q and %q have the same failoverPriority %d", other, s.Name, s.FailoverPriority)
+		}
+		priorities[s.FailoverPriority] = s.Name
+	}
+
+	sorted := slices.Clone(services)
+	slices.SortFunc(sorted, func(a, b contour_v1.Service) int {
+		return cmp.Compare(a.FailoverPriority, b.FailoverPriority)
+	})
+
+	return sorted, true, nil
+}
+
+// addValidationError records the object on the HTTPProxy's status
+// if err is because a referenced object does not exist.
+func addValidationError(pa *status.ProxyUpdate, err error) {
+	var notFound NotFoundError
+	if errors.As(err, &notFound) {
+		pa.AddValidationError(notFound.Kind, notFound.Name, err.Error())
+	}
+}
+
 // validHTTPProxies returns a slice of *contour_v1.HTTPProxy objects.
 // invalid HTTPProxy objects are excluded from the slice and their status
 // updated accordingly.
@@ -1369,14 +1848,41 @@ func (p *HTTPProxyProcessor) validHTTPProxies() []*contour_v1.HTTPProxy {
 	return valid
 }
 
+// fqdnRegexConflicts returns the sorted fqdns of other root HTTPProxies
+// that the supplied proxy's fqdnRegex also matches. Envoy prefers their
+// exact virtual hosts over the wildcard, so the regex never sees them.
+func (p *HTTPProxyProcessor) fqdnRegexConflicts(proxy *contour_v1.HTTPProxy) []string {
+	re, err := regexp.Compile("^(?:" + proxy.Spec.VirtualHost.FqdnRegex + ")$")
+	if err != nil {
+		return nil
+	}
+
+	var conflicts []string
+	for _, other := range p.source.httpproxies {
+		if other == proxy || other.Spec.VirtualHost == nil {
+			continue
+		}
+		fqdn := strings.ToLower(other.Spec.VirtualHost.Fqdn)
+		if isBlank(fqdn) || strings.HasPrefix(fqdn, "*.") || !re.MatchString(fqdn) {
+			continue
+		}
+		conflicts = append(conflicts, fqdn)
+	}
+	sort.Strings(conflicts) // sort for test stability
+	return conflicts
+}
+
 // rootAllowed returns true if the HTTPProxy lives in a permitted root namespace.
 func (p *HTTPProxyProcessor) rootAllowed(namespace string) bool {
-	if len(p.source.RootNamespaces) == 0 {
+	if len(p.source.RootNamespaces) == 0 && p.source.RootNamespaceSelector == nil {
 		return true
 	}
-	for _, ns := range p.source.RootNamespaces {
-		if ns == namespace {
-			return true
+	if slices.Contains(p.source.RootNamespaces, namespace) {
+		return true
+	}
+	if p.source.RootNamespaceSelector != nil {
+		if ns := p.source.namespaces[namespace]; ns != nil {
+			return p.source.RootNamespaceSelector.Matches(labels.Set(ns.Labels))
 		}
 	}
 	return false
@@ -1408,12 +1914,26 @@ func (p *HTTPProxyProcessor) computeVirtualHostAuthorization(auth *contour_v1.Au
 		if auth.WithRequestBody.MaxRequestBytes != 0 {
 			maxRequestBytes = auth.WithRequestBody.MaxRequestBytes
 		}
+		if maxRequestBytes > contour_v1.MaxAuthorizationRequestBytes {
+			validCond.AddErrorf(contour_v1.ConditionTypeAuthError, "AuthRequestBodyTooLarge",
+				"Spec.Virtualhost.Authorization.WithRequestBody.MaxRequestBytes %d exceeds the maximum of %d bytes",
+				maxRequestBytes, contour_v1.MaxAuthorizationRequestBytes)
+			return nil
+		}
 		globalExternalAuthorization.AuthorizationServerWithRequestBody = &AuthorizationServerBufferSettings{
 			MaxRequestBytes:     maxRequestBytes,
 			AllowPartialMessage: auth.WithRequestBody.AllowPartialMessage,
 			PackAsBytes:         auth.WithRequestBody.PackAsBytes,
 		}
 	}
+
+	if auth.AuthorizationRequest != nil {
+		globalExternalAuthorization.AuthorizationRequestAllowedHeaders = auth.AuthorizationRequest.AllowedHeaders
+		globalExternalAuthorization.AuthorizationRequestDisallowedHeaders = auth.AuthorizationRequest.DisallowedHeaders
+	}
+	if auth.AuthorizationResponse != nil {
+		globalExternalAuthorization.AuthorizationResponseDisallowedHeaders = auth.AuthorizationResponse.DisallowedHeaders
+	}
 	return globalExternalAuthorization
 }
 
@@ -1655,6 +2175,17 @@ func getProtocol(service contour_v1.Service, s *Service) (string, error) {
 	return protocol, nil
 }
 
+// getUpstreamProxyProtocol returns the PROXY protocol version to send
+// to this Service, or an empty string if none is configured.
+func getUpstreamProxyProtocol(service contour_v1.Service) (string, error) {
+	switch service.UpstreamProxyProtocol {
+	case "", "v1", "v2":
+		return service.UpstreamProxyProtocol, nil
+	default:
+		return "", fmt.Errorf("unsupported upstream proxy protocol version: %v", service.UpstreamProxyProtocol)
+	}
+}
+
 // determineSNI decides what the SNI should be on the request. It is configured via RequestHeadersPolicy.Host key.
 // Policies set on service are used before policies set on a route. Otherwise the value of the externalService
 // is used if the route is configured to proxy to an externalService type.
@@ -1900,6 +2431,38 @@ func routeEnforceTLS(enforceTLS, permitInsecure bool) bool {
 	return enforceTLS && !permitInsecure
 }
 
+// routePermitInsecure returns whether the route allows insecure requests,
+// falling back to the root virtual host's setting when the route sets
+// neither permitInsecure nor requireTLS.
+func routePermitInsecure(route contour_v1.Route, vhost *contour_v1.VirtualHost) bool {
+	switch {
+	case route.PermitInsecure:
+		return true
+	case route.RequireTLS:
+		return false
+	default:
+		return vhost != nil && vhost.PermitInsecure
+	}
+}
+
+// toNotFoundResponse validates the virtual host's not found response
+// and converts it to its DAG representation.
+func toNotFoundResponse(nfr *contour_v1.NotFoundResponse) (*NotFoundResponse, error) {
+	if nfr == nil {
+		return nil, nil
+	}
+
+	if nfr.StatusCode < 400 || nfr.StatusCode > 599 {
+		return nil, fmt.Errorf("status code %d must be between 400 and 599", nfr.StatusCode)
+	}
+
+	return &NotFoundResponse{
+		StatusCode:  uint32(nfr.StatusCode), //nolint:gosec // disable G115
+		Body:        nfr.Body,
+		ContentType: nfr.ContentType,
+	}, nil
+}
+
 func directResponse(statusCode uint32, body string) *DirectResponse {
 	return &DirectResponse{
 		StatusCode: statusCode,
@@ -1981,12 +2544,52 @@ func redirectRoutePolicy(redirect *contour_v1.HTTPRequestRedirectPolicy) (*Redir
 	}, nil
 }
 
-func directResponsePolicy(direct *contour_v1.HTTPDirectResponsePolicy) *DirectResponse {
+func directResponsePolicy(direct *contour_v1.HTTPDirectResponsePolicy) (*DirectResponse, error) {
 	if direct == nil {
-		return nil
+		return nil, nil
+	}
+
+	dr := directResponse(uint32(direct.StatusCode), direct.Body) //nolint:gosec // disable G115
+
+	if direct.BodyConfigMapRef != nil && (direct.Body != "" || len(direct.WeightedBodies) > 0) {
+		return nil, fmt.Errorf("cannot specify bodyConfigMapRef with body or weightedBodies")
+	}
+
+	if len(direct.WeightedBodies) == 0 {
+		return dr, nil
+	}
+
+	if direct.Body != "" {
+		return nil, fmt.Errorf("cannot specify both body and weightedBodies")
 	}
 
-	return directResponse(uint32(direct.StatusCode), direct.Body) //nolint:gosec // disable G115
+	var total uint32
+	for _, wb := range direct.WeightedBodies {
+		total += wb.Weight
+		dr.WeightedBodies = append(dr.WeightedBodies, WeightedDirectResponseBody{
+			Body:   wb.Body,
+			Weight: wb.Weight,
+		})
+	}
+	if total != 100 {
+		return nil, fmt.Errorf("weightedBodies weights must add up to 100, got %d", total)
+	}
+
+	return dr, nil
+}
+
+// match the command operators permitted in a direct response body template,
+// "%RESPONSE_CODE%" and "%REQ(<header>)%".
+var directResponseCommandRegex = regexp.MustCompile(`%RESPONSE_CODE%|%REQ\(:?[\w-]+\)%`)
+
+// validDirectResponseBodyTemplate returns an error if the body contains
+// a '%' that doesn't start a supported command operator, since Envoy
+// would reject the resulting body format.
+func validDirectResponseBodyTemplate(body string) error {
+	if strings.Contains(directResponseCommandRegex.ReplaceAllString(body, ""), "%") {
+		return errors.New("body contains an unsupported command operator, only %RESPONSE_CODE% and %REQ(<header>)% are allowed")
+	}
+	return nil
 }
 
 func internalRedirectPolicy(internal *contour_v1.HTTPInternalRedirectPolicy) *InternalRedirectPolicy {
@@ -2037,6 +2640,123 @@ func slowStartConfig(slowStart *contour_v1.SlowStartPolicy) (*SlowStartConfig, e
 	}, nil
 }
 
+func leastRequestConfig(leastRequest *contour_v1.LeastRequestPolicy) (*LeastRequestConfig, error) {
+	if leastRequest.ActiveRequestBias == "" {
+		return nil, nil
+	}
+
+	bias, err := strconv.ParseFloat(leastRequest.ActiveRequestBias, 64)
+	if err != nil || math.IsNaN(bias) || math.IsInf(bias, 0) || bias < 0 {
+		return nil, fmt.Errorf("error parsing activeRequestBias: \"%s\" is not a non-negative decimal number", leastRequest.ActiveRequestBias)
+	}
+
+	return &LeastRequestConfig{
+		ActiveRequestBias: bias,
+	}, nil
+}
+
+const (
+	// defaultMinRingHashSize is Envoy's minimum ring size
+	// when minimumRingSize is not set.
+	defaultMinRingHashSize = 1024
+
+	// maxRingHashSize is the largest ring size Envoy accepts,
+	// and its maximum ring size when maximumRingSize is not set.
+	maxRingHashSize = 8388608
+)
+
+func ringHashConfig(ringHash *contour_v1.RingHashPolicy) (*RingHashConfig, error) {
+	if ringHash.MinimumRingSize == nil && ringHash.MaximumRingSize == nil {
+		return nil, nil
+	}
+
+	rc := &RingHashConfig{}
+	if ringHash.MinimumRingSize != nil {
+		rc.MinimumRingSize = *ringHash.MinimumRingSize
+		if rc.MinimumRingSize == 0 || rc.MinimumRingSize > maxRingHashSize {
+			return nil, fmt.Errorf("minimumRingSize %d must be between 1 and %d", rc.MinimumRingSize, maxRingHashSize)
+		}
+	}
+	if ringHash.MaximumRingSize != nil {
+		rc.MaximumRingSize = *ringHash.MaximumRingSize
+		if rc.MaximumRingSize == 0 || rc.MaximumRingSize > maxRingHashSize {
+			return nil, fmt.Errorf("maximumRingSize %d must be between 1 and %d", rc.MaximumRingSize, maxRingHashSize)
+		}
+	}
+	switch {
+	case rc.MinimumRingSize > 0 && rc.MaximumRingSize > 0 && rc.MinimumRingSize > rc.MaximumRingSize:
+		return nil, fmt.Errorf("minimumRingSize %d must not be greater than maximumRingSize %d", rc.MinimumRingSize, rc.MaximumRingSize)
+	case rc.MinimumRingSize == 0 && rc.MaximumRingSize < defaultMinRingHashSize:
+		// Envoy rejects a maximum below its default minimum.
+		return nil, fmt.Errorf("maximumRingSize %d must not be less than the default minimumRingSize %d", rc.MaximumRingSize, defaultMinRingHashSize)
+	}
+
+	return rc, nil
+}
+
+// maxMaglevTableSize is the largest Maglev table size Envoy accepts.
+const maxMaglevTableSize = 5000011
+
+func maglevConfig(maglev *contour_v1.MaglevPolicy) (*MaglevConfig, error) {
+	if maglev.TableSize == nil {
+		return nil, nil
+	}
+
+	size := *maglev.TableSize
+	if size > maxMaglevTableSize || !isPrime(size) {
+		return nil, fmt.Errorf("tableSize %d must be a prime number no larger than %d", size, maxMaglevTableSize)
+	}
+
+	return &MaglevConfig{
+		TableSize: size,
+	}, nil
+}
+
+const (
+	minHTTP2WindowSize = 65535
+	maxHTTP2Setting    = 2147483647
+)
+
+// http2Config returns the HTTP/2 settings for a service, validating
+// that the service speaks HTTP/2 and that each setting is in range.
+func http2Config(opts *contour_v1.UpstreamHTTP2Options, protocol string) (*HTTP2Config, error) {
+	if opts == nil {
+		return nil, nil
+	}
+
+	if protocol != "h2" && protocol != "h2c" {
+		return nil, fmt.Errorf("protocol must be h2 or h2c, got %q", protocol)
+	}
+
+	if v := opts.InitialStreamWindowSize; v != nil && (*v < minHTTP2WindowSize || *v > maxHTTP2Setting) {
+		return nil, fmt.Errorf("initialStreamWindowSize %d must be between %d and %d", *v, minHTTP2WindowSize, maxHTTP2Setting)
+	}
+	if v := opts.InitialConnectionWindowSize; v != nil && (*v < minHTTP2WindowSize || *v > maxHTTP2Setting) {
+		return nil, fmt.Errorf("initialConnectionWindowSize %d must be between %d and %d", *v, minHTTP2WindowSize, maxHTTP2Setting)
+	}
+	if v := opts.MaxConcurrentStreams; v != nil && (*v < 1 || *v > maxHTTP2Setting) {
+		return nil, fmt.Errorf("maxConcurrentStreams %d must be between 1 and %d", *v, maxHTTP2Setting)
+	}
+
+	return &HTTP2Config{
+		InitialStreamWindowSize:     opts.InitialStreamWindowSize,
+		InitialConnectionWindowSize: opts.InitialConnectionWindowSize,
+		MaxConcurrentStreams:        opts.MaxConcurrentStreams,
+	}, nil
+}
+
+func isPrime(n uint64) bool {
+	if n < 2 {
+		return false
+	}
+	for i := uint64(2); i*i <= n; i++ {
+		if n%i == 0 {
+			return false
+		}
+	}
+	return true
+}
+
 func rateLimitPerRoute(in *contour_v1.RateLimitPolicy) *RateLimitPerRoute {
 	// Ignore the virtual host global rate limit policy if disabled is true
 	if in != nil && in.Global != nil && in.Global.Disabled {
@@ -2047,3 +2767,11 @@ func rateLimitPerRoute(in *contour_v1.RateLimitPolicy) *RateLimitPerRoute {
 
 	return nil
 }
+
+// addIngressClassConflictError adds an error to cond for an HTTPProxy
+// whose Spec.IngressClassName and ingress class annotation differ.
+func addIngressClassConflictError(cond *contour_v1.DetailedCondition, proxy *contour_v1.HTTPProxy) {
+	cond.AddErrorf(contour_v1.ConditionTypeSpecError, "IngressClassConflict",
+		"Spec.IngressClassName %q conflicts with ingress class annotation %q, only one of them should be set",
+		proxy.Spec.IngressClassName, annotation.IngressClass(proxy))
+}
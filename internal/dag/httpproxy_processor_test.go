@@ -351,6 +351,63 @@ func TestSlowStart(t *testing.T) {
 	}
 }
 
+func TestLeastRequest(t *testing.T) {
+	tests := map[string]struct {
+		input   *contour_v1.LeastRequestPolicy
+		want    *LeastRequestConfig
+		wantErr bool
+	}{
+		"empty": {
+			input: &contour_v1.LeastRequestPolicy{},
+			want:  nil,
+		},
+		"active request bias": {
+			input: &contour_v1.LeastRequestPolicy{
+				ActiveRequestBias: "0.5",
+			},
+			want: &LeastRequestConfig{
+				ActiveRequestBias: 0.5,
+			},
+		},
+		"invalid active request bias, not float": {
+			input: &contour_v1.LeastRequestPolicy{
+				ActiveRequestBias: "not-a-float",
+			},
+			wantErr: true,
+		},
+		"invalid active request bias, negative": {
+			input: &contour_v1.LeastRequestPolicy{
+				ActiveRequestBias: "-1.0",
+			},
+			wantErr: true,
+		},
+		"invalid active request bias, NaN": {
+			input: &contour_v1.LeastRequestPolicy{
+				ActiveRequestBias: "NaN",
+			},
+			wantErr: true,
+		},
+		"invalid active request bias, infinite": {
+			input: &contour_v1.LeastRequestPolicy{
+				ActiveRequestBias: "+Inf",
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, gotErr := leastRequestConfig(tc.input)
+			if tc.wantErr {
+				require.Error(t, gotErr)
+			} else {
+				require.NoError(t, gotErr)
+			}
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func TestIncludeMatchConditionsIdentical(t *testing.T) {
 	tests := map[string]struct {
 		includeConds []contour_v1.MatchCondition
@@ -1523,8 +1580,123 @@ func TestDetermineUpstreamTLS(t *testing.T) {
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			got := (*UpstreamTLS)(tc.envoyTLS)
+			var got *UpstreamTLS
+			if tc.envoyTLS != nil {
+				got = &UpstreamTLS{
+					MinimumProtocolVersion: tc.envoyTLS.MinimumProtocolVersion,
+					MaximumProtocolVersion: tc.envoyTLS.MaximumProtocolVersion,
+					CipherSuites:           tc.envoyTLS.CipherSuites,
+				}
+			}
 			assert.Equal(t, tc.want, got)
 		})
 	}
 }
+
+func TestValidDirectResponseBodyTemplate(t *testing.T) {
+	tests := map[string]struct {
+		body    string
+		wantErr bool
+	}{
+		"plain body": {
+			body: "Service Unavailable",
+		},
+		"response code": {
+			body: "Error %RESPONSE_CODE%",
+		},
+		"request header": {
+			body: `{"host": "%REQ(:authority)%", "id": "%REQ(x-request-id)%"}`,
+		},
+		"unsupported operator": {
+			body:    "upstream %UPSTREAM_HOST%",
+			wantErr: true,
+		},
+		"stray percent": {
+			body:    "100% unavailable",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validDirectResponseBodyTemplate(tc.body)
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
+}
+
+func TestMatchesHTTP01Challenge(t *testing.T) {
+	tests := map[string]struct {
+		cond MatchCondition
+		want bool
+	}{
+		"root prefix": {
+			cond: &PrefixMatchCondition{Prefix: "/"},
+			want: true,
+		},
+		"string prefix": {
+			cond: &PrefixMatchCondition{Prefix: "/.well"},
+			want: true,
+		},
+		"segment prefix": {
+			cond: &PrefixMatchCondition{Prefix: "/.well-known", PrefixMatchType: PrefixMatchSegment},
+			want: true,
+		},
+		"partial segment prefix": {
+			cond: &PrefixMatchCondition{Prefix: "/.well", PrefixMatchType: PrefixMatchSegment},
+			want: false,
+		},
+		"unrelated prefix": {
+			cond: &PrefixMatchCondition{Prefix: "/api"},
+			want: false,
+		},
+		"longer prefix": {
+			cond: &PrefixMatchCondition{Prefix: "/.well-known/acme-challenge/token"},
+			want: false,
+		},
+		"exact match": {
+			cond: &ExactMatchCondition{Path: "/.well-known/acme-challenge/"},
+			want: false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, matchesHTTP01Challenge(tc.cond))
+		})
+	}
+}
+
+func TestWithinHTTP01Challenge(t *testing.T) {
+	tests := map[string]struct {
+		cond MatchCondition
+		want bool
+	}{
+		"challenge prefix": {
+			cond: &PrefixMatchCondition{Prefix: "/.well-known/acme-challenge/"},
+			want: true,
+		},
+		"longer prefix": {
+			cond: &PrefixMatchCondition{Prefix: "/.well-known/acme-challenge/token"},
+			want: true,
+		},
+		"exact match under challenge path": {
+			cond: &ExactMatchCondition{Path: "/.well-known/acme-challenge/token"},
+			want: true,
+		},
+		"covering prefix": {
+			cond: &PrefixMatchCondition{Prefix: "/.well-known/"},
+			want: false,
+		},
+		"regex": {
+			cond: &RegexMatchCondition{Regex: "/.well-known/acme-challenge/.*"},
+			want: false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, withinHTTP01Challenge(tc.cond))
+		})
+	}
+}
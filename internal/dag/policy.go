@@ -55,11 +55,18 @@ const (
 	// LoadBalancerPolicyRequestHash denotes request attribute hashing is used
 	// to make load balancing decisions.
 	LoadBalancerPolicyRequestHash = "RequestHash"
+
+	// LoadBalancerPolicyMaglev denotes request attribute hashing is used
+	// with a Maglev consistent hashing load balancer.
+	LoadBalancerPolicyMaglev = "Maglev"
 )
 
 // match "%REQ(<X-Foo-Bar>)%"
 var hostRewriteHeaderRegex = regexp.MustCompile(`%REQ\(([A-Za-z0-9-]+)\)%`)
 
+// match a dynamic metadata namespace such as "envoy.filters.http.jwt_authn"
+var metadataNamespaceRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)*$`)
+
 // retryOn transforms a slice of retry on values to a comma-separated string.
 // CRD validation ensures that all retry on values are valid.
 func retryOn(ron []contour_v1.RetryOn) string {
@@ -109,6 +116,132 @@ func retryPolicy(rp *contour_v1.RetryPolicy) *RetryPolicy {
 	}
 }
 
+func faultInjectionPolicy(fp *contour_v1.FaultInjectionPolicy) (*FaultInjectionPolicy, error) {
+	if fp == nil {
+		return nil, nil
+	}
+
+	if fp.Delay == nil && fp.Abort == nil {
+		return nil, errors.New("at least one of delay or abort must be specified")
+	}
+
+	policy := &FaultInjectionPolicy{}
+
+	if fp.Delay != nil {
+		if fp.Delay.Percentage > 100 {
+			return nil, fmt.Errorf("delay percentage %d must be between 0 and 100", fp.Delay.Percentage)
+		}
+		d, err := time.ParseDuration(fp.Delay.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing delay duration: %w", err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("delay duration %q must be positive", fp.Delay.Duration)
+		}
+		policy.Delay = &FaultDelay{
+			Percentage: fp.Delay.Percentage,
+			Duration:   d,
+		}
+	}
+
+	if fp.Abort != nil {
+		if fp.Abort.Percentage > 100 {
+			return nil, fmt.Errorf("abort percentage %d must be between 0 and 100", fp.Abort.Percentage)
+		}
+		if fp.Abort.StatusCode < 200 || fp.Abort.StatusCode > 599 {
+			return nil, fmt.Errorf("abort status code %d must be between 200 and 599", fp.Abort.StatusCode)
+		}
+		policy.Abort = &FaultAbort{
+			Percentage: fp.Abort.Percentage,
+			StatusCode: uint32(fp.Abort.StatusCode), //nolint:gosec // disable G115
+		}
+	}
+
+	return policy, nil
+}
+
+// sessionPersistence validates and returns the session persistence
+// of a route.
+func sessionPersistence(sp *contour_v1.SessionPersistence) (*SessionPersistence, error) {
+	if sp == nil {
+		return nil, nil
+	}
+
+	if (sp.Cookie == nil) == (sp.Header == nil) {
+		return nil, errors.New("exactly one of cookie or header must be specified")
+	}
+
+	if sp.Header != nil {
+		if msgs := validation.IsHTTPHeaderName(sp.Header.Name); len(msgs) != 0 {
+			return nil, fmt.Errorf("invalid header name %q: %s", sp.Header.Name, strings.Join(msgs, ", "))
+		}
+		return &SessionPersistence{
+			HeaderName: sp.Header.Name,
+			Strict:     sp.Strict,
+		}, nil
+	}
+
+	// Cookie names have the same syntax as header names.
+	if msgs := validation.IsHTTPHeaderName(sp.Cookie.Name); len(msgs) != 0 {
+		return nil, fmt.Errorf("invalid cookie name %q: %s", sp.Cookie.Name, strings.Join(msgs, ", "))
+	}
+
+	policy := &SessionPersistence{
+		CookieName: sp.Cookie.Name,
+		CookiePath: "/",
+		Strict:     sp.Strict,
+	}
+
+	if sp.Cookie.Path != "" {
+		if !strings.HasPrefix(sp.Cookie.Path, "/") {
+			return nil, fmt.Errorf("cookie path %q must start with '/'", sp.Cookie.Path)
+		}
+		policy.CookiePath = sp.Cookie.Path
+	}
+
+	if sp.Cookie.TTL != "" {
+		ttl, err := time.ParseDuration(sp.Cookie.TTL)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing cookie ttl: %w", err)
+		}
+		if ttl < 0 {
+			return nil, fmt.Errorf("cookie ttl %q must not be negative", sp.Cookie.TTL)
+		}
+		policy.CookieTTL = ttl
+	}
+
+	return policy, nil
+}
+
+// hstsPreloadMinMaxAge is the minimum max-age, in seconds, that browser
+// HSTS preload lists accept.
+const hstsPreloadMinMaxAge = 31536000
+
+func hstsPolicy(hp *contour_v1.HSTSPolicy) (*HSTSPolicy, error) {
+	if hp == nil {
+		return nil, nil
+	}
+
+	if hp.MaxAge < 0 {
+		return nil, fmt.Errorf("max age %d must not be negative", hp.MaxAge)
+	}
+
+	if hp.Preload {
+		if !hp.IncludeSubDomains {
+			return nil, errors.New("preload requires includeSubDomains")
+		}
+		if hp.MaxAge < hstsPreloadMinMaxAge {
+			return nil, fmt.Errorf("preload requires a max age of at least %d, got %d", hstsPreloadMinMaxAge, hp.MaxAge)
+		}
+	}
+
+	return &HSTSPolicy{
+		MaxAge:            hp.MaxAge,
+		IncludeSubDomains: hp.IncludeSubDomains,
+		Preload:           hp.Preload,
+	}, nil
+}
+
 func headersPolicyService(defaultPolicy *HeadersPolicy, policy *contour_v1.HeadersPolicy, allowHostRewrite bool, dynamicHeaders map[string]string) (*HeadersPolicy, error) {
 	if defaultPolicy == nil {
 		return headersPolicyRoute(policy, allowHostRewrite, dynamicHeaders)
@@ -224,6 +357,53 @@ func headersPolicyRoute(policy *contour_v1.HeadersPolicy, allowHostRewrite bool,
 	}, nil
 }
 
+// mergeHeadersPolicies returns the result of applying policy on top of
+// defaults. Headers set or removed by policy take precedence over those
+// set or removed by defaults. Neither argument is modified.
+func mergeHeadersPolicies(defaults, policy *HeadersPolicy) *HeadersPolicy {
+	if defaults == nil {
+		return policy
+	}
+	if policy == nil {
+		policy = &HeadersPolicy{}
+	}
+
+	removed := sets.NewString(policy.Remove...)
+
+	set := make(map[string]string, len(defaults.Set)+len(policy.Set))
+	for k, v := range defaults.Set {
+		if !removed.Has(k) {
+			set[k] = v
+		}
+	}
+	for k, v := range policy.Set {
+		set[k] = v
+	}
+
+	remove := sets.NewString(policy.Remove...)
+	for _, k := range defaults.Remove {
+		if _, ok := policy.Set[k]; !ok {
+			remove.Insert(k)
+		}
+	}
+
+	merged := &HeadersPolicy{
+		HostRewrite:       policy.HostRewrite,
+		HostRewriteHeader: policy.HostRewriteHeader,
+		Add:               policy.Add,
+		Set:               set,
+		Remove:            remove.List(),
+	}
+	if len(merged.Set) == 0 {
+		merged.Set = nil
+	}
+	if len(merged.Remove) == 0 {
+		merged.Remove = nil
+	}
+
+	return merged
+}
+
 // extractHostRewriteHeaderValue returns the value of the header
 func extractHostRewriteHeaderValue(s string) string {
 	matches := hostRewriteHeaderRegex.FindStringSubmatch(s)
@@ -538,7 +718,7 @@ func loadBalancerPolicy(lbp *contour_v1.LoadBalancerPolicy) string {
 		return ""
 	}
 	switch lbp.Strategy {
-	case LoadBalancerPolicyWeightedLeastRequest, LoadBalancerPolicyRandom, LoadBalancerPolicyCookie, LoadBalancerPolicyRequestHash:
+	case LoadBalancerPolicyWeightedLeastRequest, LoadBalancerPolicyRandom, LoadBalancerPolicyCookie, LoadBalancerPolicyRequestHash, LoadBalancerPolicyMaglev:
 		return lbp.Strategy
 	default:
 		return ""
@@ -643,6 +823,12 @@ func globalRateLimitPolicy(in *contour_v1.GlobalRateLimitPolicy) (*GlobalRateLim
 	for _, d := range in.Descriptors {
 		var rld RateLimitDescriptor
 
+		for _, method := range d.Methods {
+			rld.Methods = append(rld.Methods, string(method))
+		}
+
+		var remoteAddress bool
+
 		for _, entry := range d.Entries {
 			// ensure exactly one field is populated on the entry
 			var set int
@@ -684,11 +870,78 @@ func globalRateLimitPolicy(in *contour_v1.GlobalRateLimitPolicy) (*GlobalRateLim
 			if entry.RemoteAddress != nil {
 				set++
 
+				// Envoy would emit the same client address twice
+				// for the descriptor, which never matches a
+				// rate limit service configuration usefully.
+				if remoteAddress {
+					return nil, errors.New("rate limit descriptor must not have more than one remoteAddress entry")
+				}
+				remoteAddress = true
+
 				rld.Entries = append(rld.Entries, RateLimitDescriptorEntry{
 					RemoteAddress: &RemoteAddressDescriptorEntry{},
 				})
 			}
 
+			if entry.SourceCluster != nil {
+				set++
+
+				rld.Entries = append(rld.Entries, RateLimitDescriptorEntry{
+					SourceCluster: &SourceClusterDescriptorEntry{},
+				})
+			}
+
+			if entry.DestinationCluster != nil {
+				set++
+
+				rld.Entries = append(rld.Entries, RateLimitDescriptorEntry{
+					DestinationCluster: &DestinationClusterDescriptorEntry{},
+				})
+			}
+
+			if entry.Metadata != nil {
+				set++
+
+				if entry.Metadata.DescriptorKey == "" {
+					return nil, errors.New("rate limit descriptor metadata descriptor key must not be empty")
+				}
+				if !metadataNamespaceRegex.MatchString(entry.Metadata.Namespace) {
+					return nil, fmt.Errorf("rate limit descriptor metadata namespace %q is invalid", entry.Metadata.Namespace)
+				}
+				if len(entry.Metadata.Path) == 0 {
+					return nil, errors.New("rate limit descriptor metadata path must not be empty")
+				}
+				for _, key := range entry.Metadata.Path {
+					if key == "" {
+						return nil, errors.New("rate limit descriptor metadata path must not contain empty keys")
+					}
+				}
+
+				rld.Entries = append(rld.Entries, RateLimitDescriptorEntry{
+					Metadata: &MetadataDescriptorEntry{
+						Key:          entry.Metadata.DescriptorKey,
+						Namespace:    entry.Metadata.Namespace,
+						Path:         entry.Metadata.Path,
+						DefaultValue: entry.Metadata.DefaultValue,
+					},
+				})
+			}
+
+			if entry.GRPCMethod != nil {
+				set++
+
+				key := entry.GRPCMethod.DescriptorKey
+				if key == "" {
+					key = "grpc_method"
+				}
+
+				rld.Entries = append(rld.Entries, RateLimitDescriptorEntry{
+					GRPCMethod: &GRPCMethodDescriptorEntry{
+						Key: key,
+					},
+				})
+			}
+
 			if set != 1 {
 				return nil, errors.New("rate limit descriptor entry must have exactly one field set")
 			}
@@ -710,14 +963,49 @@ func loadBalancerRequestHashPolicies(lbp *contour_v1.LoadBalancerPolicy, validCo
 	strategy := loadBalancerPolicy(lbp)
 	switch strategy {
 	case LoadBalancerPolicyCookie:
+		// Cookie hashing can't be combined with other request hash
+		// policies, e.g. on the source IP, so they are dropped.
+		if len(lbp.RequestHashPolicies) > 0 {
+			validCond.AddWarningf(contour_v1.ConditionTypeSpecError, "IgnoredField",
+				"ignoring field %q; request hash policies cannot be combined with the %s load balancer strategy",
+				"LoadBalancerPolicy.RequestHashPolicies", LoadBalancerPolicyCookie)
+		}
+		cookieHashOptions := &CookieHashOptions{
+			CookieName: "X-Contour-Session-Affinity",
+			TTL:        time.Duration(0),
+			Path:       "/",
+		}
+		if cp := lbp.CookiePolicy; cp != nil {
+			if cp.Name != "" {
+				if msgs := validation.IsHTTPHeaderName(cp.Name); len(msgs) != 0 {
+					validCond.AddWarningf(contour_v1.ConditionTypeSpecError, "IgnoredField",
+						"ignoring invalid cookie policy name %q: %v", cp.Name, msgs)
+				} else {
+					cookieHashOptions.CookieName = cp.Name
+				}
+			}
+			if cp.TTL != "" {
+				ttl, err := time.ParseDuration(cp.TTL)
+				if err != nil || ttl < 0 {
+					validCond.AddWarningf(contour_v1.ConditionTypeSpecError, "IgnoredField",
+						"ignoring invalid cookie policy ttl %q", cp.TTL)
+				} else {
+					cookieHashOptions.TTL = ttl
+				}
+			}
+			if cp.Path != "" {
+				if !strings.HasPrefix(cp.Path, "/") {
+					validCond.AddWarningf(contour_v1.ConditionTypeSpecError, "IgnoredField",
+						"ignoring invalid cookie policy path %q, must start with '/'", cp.Path)
+				} else {
+					cookieHashOptions.Path = cp.Path
+				}
+			}
+		}
 		return []RequestHashPolicy{
-			{CookieHashOptions: &CookieHashOptions{
-				CookieName: "X-Contour-Session-Affinity",
-				TTL:        time.Duration(0),
-				Path:       "/",
-			}},
+			{CookieHashOptions: cookieHashOptions},
 		}, LoadBalancerPolicyCookie
-	case LoadBalancerPolicyRequestHash:
+	case LoadBalancerPolicyRequestHash, LoadBalancerPolicyMaglev:
 		rhps := []RequestHashPolicy{}
 		actualStrategy := strategy
 		hashSourceIPSet := false
@@ -798,13 +1086,24 @@ func loadBalancerRequestHashPolicies(lbp *contour_v1.LoadBalancerPolicy, validCo
 			rhps = append(rhps, rhp)
 		}
 		if len(rhps) == 0 {
-			validCond.AddWarningf(contour_v1.ConditionTypeSpecError, "IgnoredField",
-				"ignoring invalid request hash policy options, setting load balancer strategy to default %s", LoadBalancerPolicyRoundRobin)
 			rhps = nil
-			actualStrategy = LoadBalancerPolicyRoundRobin
+			// Maglev has no fallback; the caller rejects it
+			// when there are no hash policies.
+			if strategy == LoadBalancerPolicyRequestHash {
+				validCond.AddWarningf(contour_v1.ConditionTypeSpecError, "IgnoredField",
+					"ignoring invalid request hash policy options, setting load balancer strategy to default %s", LoadBalancerPolicyRoundRobin)
+				actualStrategy = LoadBalancerPolicyRoundRobin
+			}
 		}
 		return rhps, actualStrategy
 	default:
+		// Request hash policies only take effect with a hashing
+		// load balancer, so don't silently drop them.
+		if len(lbp.RequestHashPolicies) > 0 {
+			validCond.AddWarningf(contour_v1.ConditionTypeSpecError, "IgnoredField",
+				"ignoring field %q; request hash policies require the %s or %s load balancer strategy",
+				"LoadBalancerPolicy.RequestHashPolicies", LoadBalancerPolicyRequestHash, LoadBalancerPolicyMaglev)
+		}
 		return nil, strategy
 	}
 }
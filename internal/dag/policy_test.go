@@ -195,6 +195,211 @@ func TestRetryPolicy(t *testing.T) {
 	}
 }
 
+func TestFaultInjectionPolicy(t *testing.T) {
+	tests := map[string]struct {
+		fp      *contour_v1.FaultInjectionPolicy
+		want    *FaultInjectionPolicy
+		wantErr string
+	}{
+		"nil policy": {
+			fp:   nil,
+			want: nil,
+		},
+		"empty policy": {
+			fp:      &contour_v1.FaultInjectionPolicy{},
+			wantErr: "at least one of delay or abort must be specified",
+		},
+		"delay and abort": {
+			fp: &contour_v1.FaultInjectionPolicy{
+				Delay: &contour_v1.FaultDelay{Percentage: 50, Duration: "1.5s"},
+				Abort: &contour_v1.FaultAbort{Percentage: 100, StatusCode: 503},
+			},
+			want: &FaultInjectionPolicy{
+				Delay: &FaultDelay{Percentage: 50, Duration: 1500 * time.Millisecond},
+				Abort: &FaultAbort{Percentage: 100, StatusCode: 503},
+			},
+		},
+		"zero percentage": {
+			fp: &contour_v1.FaultInjectionPolicy{
+				Abort: &contour_v1.FaultAbort{Percentage: 0, StatusCode: 500},
+			},
+			want: &FaultInjectionPolicy{
+				Abort: &FaultAbort{Percentage: 0, StatusCode: 500},
+			},
+		},
+		"delay percentage too large": {
+			fp: &contour_v1.FaultInjectionPolicy{
+				Delay: &contour_v1.FaultDelay{Percentage: 101, Duration: "1s"},
+			},
+			wantErr: "delay percentage 101 must be between 0 and 100",
+		},
+		"invalid delay duration": {
+			fp: &contour_v1.FaultInjectionPolicy{
+				Delay: &contour_v1.FaultDelay{Percentage: 10, Duration: "forever"},
+			},
+			wantErr: `error parsing delay duration: time: invalid duration "forever"`,
+		},
+		"zero delay duration": {
+			fp: &contour_v1.FaultInjectionPolicy{
+				Delay: &contour_v1.FaultDelay{Percentage: 10, Duration: "0s"},
+			},
+			wantErr: `delay duration "0s" must be positive`,
+		},
+		"abort percentage too large": {
+			fp: &contour_v1.FaultInjectionPolicy{
+				Abort: &contour_v1.FaultAbort{Percentage: 200, StatusCode: 503},
+			},
+			wantErr: "abort percentage 200 must be between 0 and 100",
+		},
+		"invalid abort status code": {
+			fp: &contour_v1.FaultInjectionPolicy{
+				Abort: &contour_v1.FaultAbort{Percentage: 10, StatusCode: 99},
+			},
+			wantErr: "abort status code 99 must be between 200 and 599",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := faultInjectionPolicy(tc.fp)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestSessionPersistence(t *testing.T) {
+	tests := map[string]struct {
+		sp      *contour_v1.SessionPersistence
+		want    *SessionPersistence
+		wantErr string
+	}{
+		"nil policy": {
+			sp:   nil,
+			want: nil,
+		},
+		"neither cookie nor header": {
+			sp:      &contour_v1.SessionPersistence{},
+			wantErr: "exactly one of cookie or header must be specified",
+		},
+		"both cookie and header": {
+			sp: &contour_v1.SessionPersistence{
+				Cookie: &contour_v1.SessionPersistenceCookie{Name: "session"},
+				Header: &contour_v1.SessionPersistenceHeader{Name: "x-session"},
+			},
+			wantErr: "exactly one of cookie or header must be specified",
+		},
+		"cookie defaults": {
+			sp: &contour_v1.SessionPersistence{
+				Cookie: &contour_v1.SessionPersistenceCookie{Name: "session"},
+			},
+			want: &SessionPersistence{CookieName: "session", CookiePath: "/"},
+		},
+		"cookie with path and ttl": {
+			sp: &contour_v1.SessionPersistence{
+				Cookie: &contour_v1.SessionPersistenceCookie{Name: "session", Path: "/app", TTL: "1h"},
+				Strict: true,
+			},
+			want: &SessionPersistence{CookieName: "session", CookiePath: "/app", CookieTTL: time.Hour, Strict: true},
+		},
+		"invalid cookie name": {
+			sp: &contour_v1.SessionPersistence{
+				Cookie: &contour_v1.SessionPersistenceCookie{Name: "my session"},
+			},
+			wantErr: `invalid cookie name "my session": a valid HTTP header must consist of alphanumeric characters or '-' (e.g. 'X-Header-Name', regex used for validation is '[-A-Za-z0-9]+')`,
+		},
+		"relative cookie path": {
+			sp: &contour_v1.SessionPersistence{
+				Cookie: &contour_v1.SessionPersistenceCookie{Name: "session", Path: "app"},
+			},
+			wantErr: `cookie path "app" must start with '/'`,
+		},
+		"invalid cookie ttl": {
+			sp: &contour_v1.SessionPersistence{
+				Cookie: &contour_v1.SessionPersistenceCookie{Name: "session", TTL: "forever"},
+			},
+			wantErr: `error parsing cookie ttl: time: invalid duration "forever"`,
+		},
+		"header": {
+			sp: &contour_v1.SessionPersistence{
+				Header: &contour_v1.SessionPersistenceHeader{Name: "x-session"},
+			},
+			want: &SessionPersistence{HeaderName: "x-session"},
+		},
+		"invalid header name": {
+			sp: &contour_v1.SessionPersistence{
+				Header: &contour_v1.SessionPersistenceHeader{Name: "x:session"},
+			},
+			wantErr: `invalid header name "x:session": a valid HTTP header must consist of alphanumeric characters or '-' (e.g. 'X-Header-Name', regex used for validation is '[-A-Za-z0-9]+')`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := sessionPersistence(tc.sp)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestHSTSPolicy(t *testing.T) {
+	tests := map[string]struct {
+		hp      *contour_v1.HSTSPolicy
+		want    *HSTSPolicy
+		wantErr string
+	}{
+		"nil policy": {
+			hp:   nil,
+			want: nil,
+		},
+		"max age only": {
+			hp:   &contour_v1.HSTSPolicy{MaxAge: 86400},
+			want: &HSTSPolicy{MaxAge: 86400},
+		},
+		"zero max age": {
+			hp:   &contour_v1.HSTSPolicy{MaxAge: 0},
+			want: &HSTSPolicy{MaxAge: 0},
+		},
+		"preload": {
+			hp:   &contour_v1.HSTSPolicy{MaxAge: 63072000, IncludeSubDomains: true, Preload: true},
+			want: &HSTSPolicy{MaxAge: 63072000, IncludeSubDomains: true, Preload: true},
+		},
+		"negative max age": {
+			hp:      &contour_v1.HSTSPolicy{MaxAge: -1},
+			wantErr: "max age -1 must not be negative",
+		},
+		"preload without includeSubDomains": {
+			hp:      &contour_v1.HSTSPolicy{MaxAge: 63072000, Preload: true},
+			wantErr: "preload requires includeSubDomains",
+		},
+		"preload with short max age": {
+			hp:      &contour_v1.HSTSPolicy{MaxAge: 86400, IncludeSubDomains: true, Preload: true},
+			wantErr: "preload requires a max age of at least 31536000, got 86400",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := hstsPolicy(tc.hp)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func TestTimeoutPolicy(t *testing.T) {
 	tests := map[string]struct {
 		tp                       *contour_v1.TimeoutPolicy
@@ -896,6 +1101,25 @@ func TestRateLimitPolicy(t *testing.T) {
 			},
 			wantErr: "rate limit descriptor entry must have exactly one field set",
 		},
+		"global - multiple remote address entries in a descriptor": {
+			in: &contour_v1.RateLimitPolicy{
+				Global: &contour_v1.GlobalRateLimitPolicy{
+					Descriptors: []contour_v1.RateLimitDescriptor{
+						{
+							Entries: []contour_v1.RateLimitDescriptorEntry{
+								{
+									RemoteAddress: &contour_v1.RemoteAddressDescriptor{},
+								},
+								{
+									RemoteAddress: &contour_v1.RemoteAddressDescriptor{},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: "rate limit descriptor must not have more than one remoteAddress entry",
+		},
 		"global - no descriptor entries set": {
 			in: &contour_v1.RateLimitPolicy{
 				Global: &contour_v1.GlobalRateLimitPolicy{
@@ -957,6 +1181,224 @@ func TestRateLimitPolicy(t *testing.T) {
 				},
 			},
 		},
+		"global - methods": {
+			in: &contour_v1.RateLimitPolicy{
+				Global: &contour_v1.GlobalRateLimitPolicy{
+					Descriptors: []contour_v1.RateLimitDescriptor{
+						{
+							Methods: []contour_v1.HTTPMethod{"POST", "DELETE"},
+							Entries: []contour_v1.RateLimitDescriptorEntry{
+								{
+									RemoteAddress: &contour_v1.RemoteAddressDescriptor{},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: &RateLimitPolicy{
+				Global: &GlobalRateLimitPolicy{
+					Descriptors: []*RateLimitDescriptor{
+						{
+							Methods: []string{"POST", "DELETE"},
+							Entries: []RateLimitDescriptorEntry{
+								{
+									RemoteAddress: &RemoteAddressDescriptorEntry{},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"global - source and destination cluster": {
+			in: &contour_v1.RateLimitPolicy{
+				Global: &contour_v1.GlobalRateLimitPolicy{
+					Descriptors: []contour_v1.RateLimitDescriptor{
+						{
+							Entries: []contour_v1.RateLimitDescriptorEntry{
+								{
+									SourceCluster: &contour_v1.SourceClusterDescriptor{},
+								},
+								{
+									DestinationCluster: &contour_v1.DestinationClusterDescriptor{},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: &RateLimitPolicy{
+				Global: &GlobalRateLimitPolicy{
+					Descriptors: []*RateLimitDescriptor{
+						{
+							Entries: []RateLimitDescriptorEntry{
+								{
+									SourceCluster: &SourceClusterDescriptorEntry{},
+								},
+								{
+									DestinationCluster: &DestinationClusterDescriptorEntry{},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"global - entry with source cluster and remote address set": {
+			in: &contour_v1.RateLimitPolicy{
+				Global: &contour_v1.GlobalRateLimitPolicy{
+					Descriptors: []contour_v1.RateLimitDescriptor{
+						{
+							Entries: []contour_v1.RateLimitDescriptorEntry{
+								{
+									SourceCluster: &contour_v1.SourceClusterDescriptor{},
+									RemoteAddress: &contour_v1.RemoteAddressDescriptor{},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: "rate limit descriptor entry must have exactly one field set",
+		},
+		"global - metadata": {
+			in: &contour_v1.RateLimitPolicy{
+				Global: &contour_v1.GlobalRateLimitPolicy{
+					Descriptors: []contour_v1.RateLimitDescriptor{
+						{
+							Entries: []contour_v1.RateLimitDescriptorEntry{
+								{
+									Metadata: &contour_v1.MetadataDescriptor{
+										DescriptorKey: "subject",
+										Namespace:     "envoy.filters.http.jwt_authn",
+										Path:          []string{"jwt_payload", "sub"},
+										DefaultValue:  "anonymous",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: &RateLimitPolicy{
+				Global: &GlobalRateLimitPolicy{
+					Descriptors: []*RateLimitDescriptor{
+						{
+							Entries: []RateLimitDescriptorEntry{
+								{
+									Metadata: &MetadataDescriptorEntry{
+										Key:          "subject",
+										Namespace:    "envoy.filters.http.jwt_authn",
+										Path:         []string{"jwt_payload", "sub"},
+										DefaultValue: "anonymous",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"global - grpc method": {
+			in: &contour_v1.RateLimitPolicy{
+				Global: &contour_v1.GlobalRateLimitPolicy{
+					Descriptors: []contour_v1.RateLimitDescriptor{
+						{
+							Entries: []contour_v1.RateLimitDescriptorEntry{
+								{
+									GRPCMethod: &contour_v1.GRPCMethodDescriptor{},
+								},
+								{
+									GRPCMethod: &contour_v1.GRPCMethodDescriptor{
+										DescriptorKey: "rpc",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: &RateLimitPolicy{
+				Global: &GlobalRateLimitPolicy{
+					Descriptors: []*RateLimitDescriptor{
+						{
+							Entries: []RateLimitDescriptorEntry{
+								{
+									GRPCMethod: &GRPCMethodDescriptorEntry{
+										Key: "grpc_method",
+									},
+								},
+								{
+									GRPCMethod: &GRPCMethodDescriptorEntry{
+										Key: "rpc",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"global - metadata with empty descriptor key": {
+			in: &contour_v1.RateLimitPolicy{
+				Global: &contour_v1.GlobalRateLimitPolicy{
+					Descriptors: []contour_v1.RateLimitDescriptor{
+						{
+							Entries: []contour_v1.RateLimitDescriptorEntry{
+								{
+									Metadata: &contour_v1.MetadataDescriptor{
+										Namespace: "envoy.filters.http.jwt_authn",
+										Path:      []string{"jwt_payload", "sub"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: `rate limit descriptor metadata descriptor key must not be empty`,
+		},
+		"global - metadata with invalid namespace": {
+			in: &contour_v1.RateLimitPolicy{
+				Global: &contour_v1.GlobalRateLimitPolicy{
+					Descriptors: []contour_v1.RateLimitDescriptor{
+						{
+							Entries: []contour_v1.RateLimitDescriptorEntry{
+								{
+									Metadata: &contour_v1.MetadataDescriptor{
+										DescriptorKey: "subject",
+										Namespace:     "envoy..jwt_authn",
+										Path:          []string{"sub"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: `rate limit descriptor metadata namespace "envoy..jwt_authn" is invalid`,
+		},
+		"global - metadata with empty path key": {
+			in: &contour_v1.RateLimitPolicy{
+				Global: &contour_v1.GlobalRateLimitPolicy{
+					Descriptors: []contour_v1.RateLimitDescriptor{
+						{
+							Entries: []contour_v1.RateLimitDescriptorEntry{
+								{
+									Metadata: &contour_v1.MetadataDescriptor{
+										DescriptorKey: "subject",
+										Namespace:     "envoy.filters.http.jwt_authn",
+										Path:          []string{"jwt_payload", ""},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: `rate limit descriptor metadata path must not contain empty keys`,
+		},
 		"global and local": {
 			in: &contour_v1.RateLimitPolicy{
 				Local: &contour_v1.LocalRateLimitPolicy{
@@ -1477,3 +1919,56 @@ func TestHeadersPolicyRoute(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeHeadersPolicies(t *testing.T) {
+	tests := map[string]struct {
+		defaults *HeadersPolicy
+		policy   *HeadersPolicy
+		want     *HeadersPolicy
+	}{
+		"no defaults": {
+			policy: &HeadersPolicy{Set: map[string]string{"X-Header": "route"}},
+			want:   &HeadersPolicy{Set: map[string]string{"X-Header": "route"}},
+		},
+		"no policy": {
+			defaults: &HeadersPolicy{
+				Set:    map[string]string{"X-Tenant": "team-a"},
+				Remove: []string{"X-Debug"},
+			},
+			want: &HeadersPolicy{
+				Set:    map[string]string{"X-Tenant": "team-a"},
+				Remove: []string{"X-Debug"},
+			},
+		},
+		"policy set wins": {
+			defaults: &HeadersPolicy{Set: map[string]string{"X-Tenant": "team-a", "X-Env": "prod"}},
+			policy: &HeadersPolicy{
+				HostRewrite: "example.com",
+				Set:         map[string]string{"X-Tenant": "team-b"},
+			},
+			want: &HeadersPolicy{
+				HostRewrite: "example.com",
+				Set:         map[string]string{"X-Tenant": "team-b", "X-Env": "prod"},
+			},
+		},
+		"policy remove wins over default set": {
+			defaults: &HeadersPolicy{Set: map[string]string{"X-Tenant": "team-a"}},
+			policy:   &HeadersPolicy{Remove: []string{"X-Tenant"}},
+			want:     &HeadersPolicy{Remove: []string{"X-Tenant"}},
+		},
+		"policy set wins over default remove": {
+			defaults: &HeadersPolicy{Remove: []string{"X-Tenant", "X-Debug"}},
+			policy:   &HeadersPolicy{Set: map[string]string{"X-Tenant": "team-b"}, Remove: []string{"X-Debug"}},
+			want: &HeadersPolicy{
+				Set:    map[string]string{"X-Tenant": "team-b"},
+				Remove: []string{"X-Debug"},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, mergeHeadersPolicies(tc.defaults, tc.policy))
+		})
+	}
+}
@@ -15,11 +15,13 @@ package dag
 
 import (
 	"bytes"
+	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	core_v1 "k8s.io/api/core/v1"
 )
@@ -61,6 +63,55 @@ func validTLSSecret(secret *core_v1.Secret) error {
 	return nil
 }
 
+// CheckTLSSecret returns the expiry time of the serving certificate in
+// the Secret, or an error if the Secret does not hold a valid certificate
+// and private key pair. It applies the same checks Contour uses before
+// programming a Secret into Envoy, and also checks that the private key
+// matches the certificate.
+func CheckTLSSecret(secret *core_v1.Secret) (time.Time, error) {
+	if err := validTLSSecret(secret); err != nil {
+		return time.Time{}, err
+	}
+
+	pair, err := tls.X509KeyPair(secret.Data[core_v1.TLSCertKey], secret.Data[core_v1.TLSPrivateKeyKey])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid TLS key pair: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid TLS certificate: %v", err)
+	}
+
+	return cert.NotAfter, nil
+}
+
+// CheckCASecret returns the earliest expiry time of the certificates in
+// the Secret's CA bundle, or an error if the Secret does not hold a valid
+// CA bundle.
+func CheckCASecret(secret *core_v1.Secret) (time.Time, error) {
+	if err := validCASecret(secret); err != nil {
+		return time.Time{}, err
+	}
+
+	var notAfter time.Time
+	data := secret.Data[CACertificateKey]
+	for containsPEMHeader(data) {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid CA certificate bundle: %v", err)
+		}
+		if notAfter.IsZero() || cert.NotAfter.Before(notAfter) {
+			notAfter = cert.NotAfter
+		}
+	}
+
+	return notAfter, nil
+}
+
 // validCASecret returns an error if the Secret is not of type TLS or Opaque or
 // if it doesn't contain a valid CA bundle in the ca.crt key.
 func validCASecret(secret *core_v1.Secret) error {
@@ -189,6 +240,23 @@ func validateCABundle(data []byte) error {
 	return nil
 }
 
+// certificateCoversHostname reports whether the first certificate in the
+// serving bundle has a DNS subject alt name matching hostname, including
+// wildcard names such as "*.example.com". Certificates without DNS subject
+// alt names are assumed to cover any hostname.
+func certificateCoversHostname(data []byte, hostname string) bool {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return true
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil || len(cert.DNSNames) == 0 {
+		return true
+	}
+
+	return cert.VerifyHostname(hostname) == nil
+}
+
 func hasCommonName(c *x509.Certificate) bool {
 	return strings.TrimSpace(c.Subject.CommonName) != ""
 }
@@ -17,8 +17,10 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	core_v1 "k8s.io/api/core/v1"
 
 	"github.com/projectcontour/contour/internal/fixture"
@@ -246,6 +248,66 @@ func TestValidSecrets(t *testing.T) {
 	}
 }
 
+func TestCertificateCoversHostname(t *testing.T) {
+	tests := map[string]struct {
+		cert     string
+		hostname string
+		want     bool
+	}{
+		"wildcard SAN covers single label subdomain": {
+			cert:     fixture.WILDCARD_EXAMPLE_COM_CERT,
+			hostname: "foo.example.com",
+			want:     true,
+		},
+		"wildcard SAN is case insensitive": {
+			cert:     fixture.WILDCARD_EXAMPLE_COM_CERT,
+			hostname: "FOO.example.com",
+			want:     true,
+		},
+		"wildcard SAN does not cover nested subdomain": {
+			cert:     fixture.WILDCARD_EXAMPLE_COM_CERT,
+			hostname: "foo.bar.example.com",
+			want:     false,
+		},
+		"wildcard SAN does not cover apex domain": {
+			cert:     fixture.WILDCARD_EXAMPLE_COM_CERT,
+			hostname: "example.com",
+			want:     false,
+		},
+		"wildcard SAN does not cover other domain": {
+			cert:     fixture.WILDCARD_EXAMPLE_COM_CERT,
+			hostname: "foo.example.org",
+			want:     false,
+		},
+		"wildcard fqdn matches identical wildcard SAN": {
+			cert:     fixture.WILDCARD_EXAMPLE_COM_CERT,
+			hostname: "*.example.com",
+			want:     true,
+		},
+		"exact SAN": {
+			cert:     fixture.EC_CERTIFICATE,
+			hostname: "www.example.com",
+			want:     true,
+		},
+		"exact SAN does not cover other hostname": {
+			cert:     fixture.EC_CERTIFICATE,
+			hostname: "api.example.com",
+			want:     false,
+		},
+		"certificate without SANs": {
+			cert:     fixture.CERTIFICATE,
+			hostname: "foo.example.org",
+			want:     true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, certificateCoversHostname([]byte(tc.cert), tc.hostname))
+		})
+	}
+}
+
 func secretdata(cert, key string) map[string][]byte {
 	return map[string][]byte{
 		core_v1.TLSCertKey:       []byte(cert),
@@ -295,3 +357,50 @@ func makeTLSSecret(data map[string][]byte) *core_v1.Secret {
 func makeOpaqueSecret(data map[string][]byte) *core_v1.Secret {
 	return &core_v1.Secret{Type: core_v1.SecretTypeOpaque, Data: data}
 }
+
+func TestCheckTLSSecret(t *testing.T) {
+	notAfter, err := CheckTLSSecret(&core_v1.Secret{
+		Type: core_v1.SecretTypeTLS,
+		Data: map[string][]byte{
+			core_v1.TLSCertKey:       []byte(pemBundle(fixture.CERTIFICATE, fixture.CA_CERT)),
+			core_v1.TLSPrivateKeyKey: []byte(fixture.RSA_PRIVATE_KEY),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2072, time.August, 6, 11, 9, 15, 0, time.UTC), notAfter.UTC())
+
+	_, err = CheckTLSSecret(&core_v1.Secret{
+		Type: core_v1.SecretTypeTLS,
+		Data: map[string][]byte{
+			core_v1.TLSCertKey: []byte(fixture.CERTIFICATE),
+		},
+	})
+	assert.EqualError(t, err, "missing TLS private key")
+
+	_, err = CheckTLSSecret(&core_v1.Secret{
+		Type: core_v1.SecretTypeTLS,
+		Data: map[string][]byte{
+			core_v1.TLSCertKey:       []byte(fixture.CERTIFICATE),
+			core_v1.TLSPrivateKeyKey: []byte(fixture.EC_PRIVATE_KEY),
+		},
+	})
+	assert.ErrorContains(t, err, "invalid TLS key pair")
+}
+
+func TestCheckCASecret(t *testing.T) {
+	notAfter, err := CheckCASecret(&core_v1.Secret{
+		Type: core_v1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			CACertificateKey: []byte(pemBundle(fixture.CERTIFICATE, fixture.CA_CERT)),
+		},
+	})
+	require.NoError(t, err)
+	// The CA certificate expires before the serving certificate.
+	assert.Equal(t, time.Date(2026, time.November, 7, 10, 26, 43, 0, time.UTC), notAfter.UTC())
+
+	_, err = CheckCASecret(&core_v1.Secret{
+		Type: core_v1.SecretTypeOpaque,
+		Data: map[string][]byte{},
+	})
+	assert.EqualError(t, err, `empty "ca.crt" key`)
+}
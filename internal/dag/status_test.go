@@ -21,6 +21,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	core_v1 "k8s.io/api/core/v1"
 	networking_v1 "k8s.io/api/networking/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -227,7 +228,7 @@ func TestDAGStatus(t *testing.T) {
 				Valid(),
 			{Name: proxyChildInvalidBadPort.Name, Namespace: proxyChildInvalidBadPort.Namespace}: fixture.NewValidCondition().
 				WithGeneration(proxyChildInvalidBadPort.Generation).
-				WithError(contour_v1.ConditionTypeServiceError, "ServicePortInvalid", `service "foo3": port must be in the range 1-65535`),
+				WithRouteError("prefix: /", contour_v1.ConditionTypeServiceError, "ServicePortInvalid", `service "foo3": port must be in the range 1-65535`),
 			{Name: proxyMultiIncludeOneInvalid.Name, Namespace: proxyMultiIncludeOneInvalid.Namespace}: fixture.NewValidCondition().
 				WithGeneration(proxyMultiIncludeOneInvalid.Generation).
 				Valid(),
@@ -614,7 +615,7 @@ func TestDAGStatus(t *testing.T) {
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
 			{Name: proxyInvalidNegativePortHomeService.Name, Namespace: proxyInvalidNegativePortHomeService.Namespace}: fixture.NewValidCondition().
 				WithGeneration(proxyInvalidNegativePortHomeService.Generation).
-				WithError(contour_v1.ConditionTypeServiceError, "ServicePortInvalid", `service "home": port must be in the range 1-65535`),
+				WithRouteError("prefix: /foo", contour_v1.ConditionTypeServiceError, "ServicePortInvalid", `service "home": port must be in the range 1-65535`),
 		},
 	})
 
@@ -649,6 +650,88 @@ func TestDAGStatus(t *testing.T) {
 		},
 	})
 
+	// proxyIngressClassConflict is invalid because its spec field and annotation
+	// specify different ingress classes
+	proxyIngressClassConflict := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "example",
+			Annotations: map[string]string{
+				"projectcontour.io/ingress.class": "other",
+			},
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			IngressClassName: "contour",
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{{
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/foo",
+				}},
+				Services: []contour_v1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	run(t, "root proxy spec ingress class conflicts with annotation", testcase{
+		objs: []any{proxyIngressClassConflict, fixture.ServiceRootsHome},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: proxyIngressClassConflict.Name, Namespace: proxyIngressClassConflict.Namespace}: fixture.NewValidCondition().
+				WithError(contour_v1.ConditionTypeSpecError, "IngressClassConflict", `Spec.IngressClassName "contour" conflicts with ingress class annotation "other", only one of them should be set`),
+		},
+	})
+
+	proxyIncludesIngressClassConflict := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "parent",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Includes: []contour_v1.Include{{
+				Name: "child",
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/child",
+				}},
+			}},
+		},
+	}
+
+	proxyChildIngressClassConflict := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "child",
+			Annotations: map[string]string{
+				"kubernetes.io/ingress.class": "other",
+			},
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			IngressClassName: "contour",
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	run(t, "included proxy spec ingress class conflicts with annotation", testcase{
+		objs: []any{proxyIncludesIngressClassConflict, proxyChildIngressClassConflict, fixture.ServiceRootsHome},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: proxyIncludesIngressClassConflict.Name, Namespace: proxyIncludesIngressClassConflict.Namespace}: fixture.NewValidCondition().
+				Valid(),
+			{Name: proxyChildIngressClassConflict.Name, Namespace: proxyChildIngressClassConflict.Namespace}: fixture.NewValidCondition().
+				WithError(contour_v1.ConditionTypeSpecError, "IngressClassConflict", `Spec.IngressClassName "contour" conflicts with ingress class annotation "other", only one of them should be set`),
+		},
+	})
+
 	// proxyInvalidIncludeCycle is invalid because it delegates to itself, producing a cycle
 	proxyInvalidIncludeCycle := &contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
@@ -724,13 +807,90 @@ func TestDAGStatus(t *testing.T) {
 		objs: []any{proxyIncludesProxyWithIncludeCycle, proxyIncludedChildInvalidIncludeCycle},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
 			{Name: proxyIncludesProxyWithIncludeCycle.Name, Namespace: proxyIncludesProxyWithIncludeCycle.Namespace}: fixture.NewValidCondition().
-				WithGeneration(proxyIncludesProxyWithIncludeCycle.Generation).Valid(),
+				WithGeneration(proxyIncludesProxyWithIncludeCycle.Generation).
+				WithError(contour_v1.ConditionTypeIncludeError, "IncludeCreatesCycle", "include creates an include cycle: roots/parent -> roots/child -> roots/child"),
 			{Name: proxyIncludedChildInvalidIncludeCycle.Name, Namespace: proxyIncludedChildInvalidIncludeCycle.Namespace}: fixture.NewValidCondition().
 				WithGeneration(proxyIncludedChildInvalidIncludeCycle.Generation).
 				WithError(contour_v1.ConditionTypeIncludeError, "IncludeCreatesCycle", "include creates an include cycle: roots/parent -> roots/child -> roots/child"),
 		},
 	})
 
+	// proxyIncludesIndirectCycle includes child-a, which includes child-b, which
+	// includes child-a again. The cycle is reached through two includes, but is
+	// only reported once on the root.
+	proxyIncludesIndirectCycle := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "parent",
+			Namespace: "roots",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Includes: []contour_v1.Include{{
+				Name: "child-a",
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/foo",
+				}},
+			}, {
+				Name: "child-a",
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/bar",
+				}},
+			}},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	proxyIndirectCycleA := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "child-a",
+			Namespace: "roots",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			Includes: []contour_v1.Include{{
+				Name: "child-b",
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/b",
+				}},
+			}},
+		},
+	}
+
+	proxyIndirectCycleB := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "child-b",
+			Namespace: "roots",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			Includes: []contour_v1.Include{{
+				Name: "child-a",
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/a",
+				}},
+			}},
+		},
+	}
+
+	run(t, "proxy includes an indirect cycle", testcase{
+		objs: []any{proxyIncludesIndirectCycle, proxyIndirectCycleA, proxyIndirectCycleB, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: proxyIncludesIndirectCycle.Name, Namespace: proxyIncludesIndirectCycle.Namespace}: fixture.NewValidCondition().
+				WithGeneration(proxyIncludesIndirectCycle.Generation).
+				WithError(contour_v1.ConditionTypeIncludeError, "IncludeCreatesCycle", "include creates an include cycle: roots/parent -> roots/child-a -> roots/child-b -> roots/child-a"),
+			{Name: proxyIndirectCycleA.Name, Namespace: proxyIndirectCycleA.Namespace}: fixture.NewValidCondition().
+				WithGeneration(proxyIndirectCycleA.Generation).
+				WithError(contour_v1.ConditionTypeIncludeError, "IncludeCreatesCycle", "include creates an include cycle: roots/parent -> roots/child-a -> roots/child-b -> roots/child-a"),
+			{Name: proxyIndirectCycleB.Name, Namespace: proxyIndirectCycleB.Namespace}: fixture.NewValidCondition().
+				WithGeneration(proxyIndirectCycleB.Generation).Valid(),
+		},
+	})
+
 	run(t, "proxy orphaned route", testcase{
 		objs: []any{proxyIncludedChildInvalidIncludeCycle},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
@@ -843,7 +1003,51 @@ func TestDAGStatus(t *testing.T) {
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
 			{Name: proxyInvalidServiceInvalid.Name, Namespace: proxyInvalidServiceInvalid.Namespace}: fixture.NewValidCondition().
 				WithGeneration(proxyInvalidServiceInvalid.Generation).
-				WithError(contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Spec.Routes unresolved service reference: service "roots/invalid" not found`),
+				WithRouteError("prefix: /foo", contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Spec.Routes unresolved service reference: service "roots/invalid" not found`),
+		},
+	})
+
+	// proxyOneRouteInvalid has one valid route and one route that references a missing service.
+	proxyOneRouteInvalid := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "one-route-invalid",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{{
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/foo",
+				}},
+				Services: []contour_v1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			}, {
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/bar",
+				}, {
+					Header: &contour_v1.HeaderMatchCondition{
+						Name:  "x-env",
+						Exact: "staging",
+					},
+				}},
+				Services: []contour_v1.Service{{
+					Name: "invalid",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	run(t, "proxy with one invalid route identifies the route", testcase{
+		objs: []any{proxyOneRouteInvalid, fixture.ServiceRootsHome},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: proxyOneRouteInvalid.Name, Namespace: proxyOneRouteInvalid.Namespace}: fixture.NewValidCondition().
+				WithGeneration(proxyOneRouteInvalid.Generation).
+				WithRouteError("prefix: /bar, header: x-env exact staging", contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Spec.Routes unresolved service reference: service "roots/invalid" not found`),
 		},
 	})
 
@@ -874,7 +1078,42 @@ func TestDAGStatus(t *testing.T) {
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
 			{Name: proxyInvalidServicePortInvalid.Name, Namespace: proxyInvalidServicePortInvalid.Namespace}: fixture.NewValidCondition().
 				WithGeneration(proxyInvalidServiceInvalid.Generation).
-				WithError(contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Spec.Routes unresolved service reference: port "9999" on service "roots/home" not matched`),
+				WithRouteError("prefix: /foo", contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Spec.Routes unresolved service reference: port "9999" on service "roots/home" not matched`),
+		},
+	})
+
+	// proxyInvalidHealthPortInvalid is invalid because its health check port is not a port on the service
+	proxyInvalidHealthPortInvalid := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "invalidhealthport",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{{
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/foo",
+				}},
+				HealthCheckPolicy: &contour_v1.HTTPHealthCheckPolicy{
+					Path: "/healthz",
+				},
+				Services: []contour_v1.Service{{
+					Name:       "home",
+					Port:       8080,
+					HealthPort: 9999,
+				}},
+			}},
+		},
+	}
+
+	run(t, "proxy with service missing health port is invalid", testcase{
+		objs: []any{proxyInvalidHealthPortInvalid, fixture.ServiceRootsHome},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: proxyInvalidHealthPortInvalid.Name, Namespace: proxyInvalidHealthPortInvalid.Namespace}: fixture.NewValidCondition().
+				WithGeneration(proxyInvalidHealthPortInvalid.Generation).
+				WithRouteError("prefix: /foo", contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Spec.Routes unresolved service reference: health port: port "9999" on service "roots/home" not matched`),
 		},
 	})
 
@@ -1174,7 +1413,7 @@ func TestDAGStatus(t *testing.T) {
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
 			{Name: proxyInvalidTwoMirrors.Name, Namespace: proxyInvalidTwoMirrors.Namespace}: fixture.NewValidCondition().
 				WithGeneration(proxyInvalidTwoMirrors.Generation).
-				WithError(contour_v1.ConditionTypeServiceError, "OnlyOneMirror", "only one service per route may be nominated as mirror"),
+				WithRouteError("prefix: /", contour_v1.ConditionTypeServiceError, "OnlyOneMirror", "only one service per route may be nominated as mirror"),
 		},
 	})
 
@@ -1214,7 +1453,7 @@ func TestDAGStatus(t *testing.T) {
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
 			{Name: proxyInvalidDuplicateMatchConditionHeaders.Name, Namespace: proxyInvalidDuplicateMatchConditionHeaders.Namespace}: fixture.NewValidCondition().
 				WithGeneration(proxyInvalidDuplicateMatchConditionHeaders.Generation).
-				WithError(contour_v1.ConditionTypeRouteError, "HeaderMatchConditionsNotValid", "cannot specify duplicate header 'exact match' conditions in the same route"),
+				WithRouteError("prefix: /foo, header: x-header exact abc, header: x-header exact 1234", contour_v1.ConditionTypeRouteError, "HeaderMatchConditionsNotValid", "cannot specify duplicate header 'exact match' conditions in the same route"),
 		},
 	})
 
@@ -1254,7 +1493,95 @@ func TestDAGStatus(t *testing.T) {
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
 			{Name: proxyInvalidDuplicateMatchConditionQueryParameters.Name, Namespace: proxyInvalidDuplicateMatchConditionQueryParameters.Namespace}: fixture.NewValidCondition().
 				WithGeneration(proxyInvalidDuplicateMatchConditionQueryParameters.Generation).
-				WithError(contour_v1.ConditionTypeRouteError, "QueryParameterMatchConditionsNotValid", "cannot specify duplicate query parameter 'exact match' conditions in the same route"),
+				WithRouteError("prefix: /foo, queryParameter: param exact abc, queryParameter: param exact 1234", contour_v1.ConditionTypeRouteError, "QueryParameterMatchConditionsNotValid", "cannot specify duplicate query parameter 'exact match' conditions in the same route"),
+		},
+	})
+
+	proxyIncludeQueryParameterParent := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "parent",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Includes: []contour_v1.Include{{
+				Name: "child",
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/foo",
+				}, {
+					QueryParameter: &contour_v1.QueryParameterMatchCondition{
+						Name:  "param",
+						Exact: "abc",
+					},
+				}},
+			}},
+		},
+	}
+
+	proxyIncludeQueryParameterChild := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "child",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			Routes: []contour_v1.Route{{
+				Conditions: []contour_v1.MatchCondition{{
+					QueryParameter: &contour_v1.QueryParameterMatchCondition{
+						Name:  "param",
+						Exact: "1234",
+					},
+				}},
+				Services: []contour_v1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	run(t, "duplicate query parameters inherited from an include", testcase{
+		objs: []any{proxyIncludeQueryParameterParent, proxyIncludeQueryParameterChild, fixture.ServiceRootsHome},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: proxyIncludeQueryParameterParent.Name, Namespace: proxyIncludeQueryParameterParent.Namespace}: fixture.NewValidCondition().
+				Valid(),
+			{Name: proxyIncludeQueryParameterChild.Name, Namespace: proxyIncludeQueryParameterChild.Namespace}: fixture.NewValidCondition().
+				WithRouteError("prefix: /, queryParameter: param exact 1234", contour_v1.ConditionTypeRouteError, "QueryParameterMatchConditionsNotValid", "cannot specify duplicate query parameter 'exact match' conditions in the same route"),
+		},
+	})
+
+	proxyIncludeHostRewrite := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "parent",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Includes: []contour_v1.Include{{
+				Name: "child",
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/foo",
+				}},
+				RequestHeadersPolicy: &contour_v1.HeadersPolicy{
+					Set: []contour_v1.HeaderValue{{
+						Name:  "Host",
+						Value: "other.example.com",
+					}},
+				},
+			}},
+		},
+	}
+
+	run(t, "include request headers policy rewrites host", testcase{
+		objs: []any{proxyIncludeHostRewrite, proxyIncludeQueryParameterChild, fixture.ServiceRootsHome},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: proxyIncludeHostRewrite.Name, Namespace: proxyIncludeHostRewrite.Namespace}: fixture.NewValidCondition().
+				WithError(contour_v1.ConditionTypeIncludeError, "RequestHeadersPolicyInvalid", `include roots/child: rewriting "Host" header is not supported on request headers`),
+			{Name: proxyIncludeQueryParameterChild.Name, Namespace: proxyIncludeQueryParameterChild.Namespace}: fixture.NewValidCondition().
+				WithError(contour_v1.ConditionTypeIncludeError, "RequestHeadersPolicyInvalid", `included by roots/parent: rewriting "Host" header is not supported on request headers`),
 		},
 	})
 
@@ -1393,7 +1720,7 @@ func TestDAGStatus(t *testing.T) {
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
 			{Name: proxyInvalidMultiplePrefixes.Name, Namespace: proxyInvalidMultiplePrefixes.Namespace}: fixture.NewValidCondition().
 				WithGeneration(proxyInvalidMultiplePrefixes.Generation).
-				WithError(contour_v1.ConditionTypeRouteError, "PathMatchConditionsNotValid", "route: more than one prefix, exact or regex is not allowed in a condition block"),
+				WithRouteError("prefix: /v1", contour_v1.ConditionTypeRouteError, "PathMatchConditionsNotValid", "route: more than one prefix, exact or regex is not allowed in a condition block"),
 		},
 	})
 
@@ -1481,7 +1808,7 @@ func TestDAGStatus(t *testing.T) {
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
 			{Name: proxyInvalidPrefixNoSlash.Name, Namespace: proxyInvalidPrefixNoSlash.Namespace}: fixture.NewValidCondition().
 				WithGeneration(proxyInvalidPrefixNoSlash.Generation).
-				WithError(contour_v1.ConditionTypeRouteError, "PathMatchConditionsNotValid", "route: prefix conditions must start with /, api was supplied"),
+				WithRouteError("prefix: api", contour_v1.ConditionTypeRouteError, "PathMatchConditionsNotValid", "route: prefix conditions must start with /, api was supplied"),
 		},
 	})
 
@@ -1661,21 +1988,151 @@ func TestDAGStatus(t *testing.T) {
 		},
 	})
 
-	proxyInvalidConflictingIncludeConditionsSimple := &contour_v1.HTTPProxy{
+	proxyTCPFailoverSamePriority := &contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
-			Namespace: "roots",
-			Name:      "example",
+			Name:      "failover",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_v1.HTTPProxySpec{
 			VirtualHost: &contour_v1.VirtualHost{
-				Fqdn: "example.com",
+				Fqdn: "tcp.example.com",
+				TLS: &contour_v1.TLS{
+					Passthrough: true,
+				},
 			},
-			Includes: []contour_v1.Include{{
-				Name:      "blogteama",
-				Namespace: "teama",
-				Conditions: []contour_v1.MatchCondition{{
-					Prefix: "/blog",
-				}},
+			TCPProxy: &contour_v1.TCPProxy{
+				Services: []contour_v1.Service{{
+					Name:             fixture.ServiceRootsKuard.Name,
+					Port:             8080,
+					FailoverPriority: 1,
+				}, {
+					Name:             fixture.ServiceRootsHome.Name,
+					Port:             8080,
+					FailoverPriority: 1,
+				}},
+			},
+		},
+	}
+
+	run(t, "tcpproxy services with the same failover priority", testcase{
+		objs: []any{proxyTCPFailoverSamePriority, fixture.ServiceRootsKuard, fixture.ServiceRootsHome},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: proxyTCPFailoverSamePriority.Name, Namespace: proxyTCPFailoverSamePriority.Namespace}: fixture.NewValidCondition().
+				WithError(contour_v1.ConditionTypeTCPProxyError, "FailoverPriorityNotValid", `Spec.TCPProxy.Services is invalid: services "kuard" and "home" have the same failoverPriority 1`),
+		},
+	})
+
+	proxyTCPFailoverWithWeight := proxyTCPFailoverSamePriority.DeepCopy()
+	proxyTCPFailoverWithWeight.Spec.TCPProxy.Services[0].FailoverPriority = 0
+	proxyTCPFailoverWithWeight.Spec.TCPProxy.Services[0].Weight = 50
+
+	run(t, "tcpproxy services with failover priority and weight", testcase{
+		objs: []any{proxyTCPFailoverWithWeight, fixture.ServiceRootsKuard, fixture.ServiceRootsHome},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: proxyTCPFailoverWithWeight.Name, Namespace: proxyTCPFailoverWithWeight.Namespace}: fixture.NewValidCondition().
+				WithError(contour_v1.ConditionTypeTCPProxyError, "FailoverPriorityNotValid", `Spec.TCPProxy.Services is invalid: service "kuard" cannot specify both weight and failoverPriority`),
+		},
+	})
+
+	proxyRouteFailoverPriority := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "failover",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name:             fixture.ServiceRootsKuard.Name,
+					Port:             8080,
+					FailoverPriority: 1,
+				}},
+			}},
+		},
+	}
+
+	routeFailoverPriorityCond := fixture.NewValidCondition().Valid()
+	routeFailoverPriorityCond.AddWarning(contour_v1.ConditionTypeServiceError, "IgnoredField",
+		`ignoring field "FailoverPriority" on service "kuard"; failover priority is only supported for TCPProxy services`)
+
+	run(t, "route service with failover priority", testcase{
+		objs: []any{proxyRouteFailoverPriority, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: proxyRouteFailoverPriority.Name, Namespace: proxyRouteFailoverPriority.Namespace}: routeFailoverPriorityCond,
+		},
+	})
+
+	proxyHTTPOnlyChildFoo := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "foo",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: fixture.ServiceRootsKuard.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	run(t, "tcpproxy includes child without tcpproxy", testcase{
+		objs: []any{proxyTCPIncludesFoo, proxyHTTPOnlyChildFoo, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: proxyTCPIncludesFoo.Name, Namespace: proxyTCPIncludesFoo.Namespace}: fixture.NewValidCondition().
+				WithError(contour_v1.ConditionTypeTCPProxyIncludeError, "IncludeNotTCPProxy", "include roots/foo does not define a tcpproxy"),
+			{Name: proxyHTTPOnlyChildFoo.Name, Namespace: proxyHTTPOnlyChildFoo.Namespace}: fixture.NewValidCondition().Orphaned(),
+		},
+	})
+
+	proxyTCPAndRoutesChildFoo := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "foo",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: fixture.ServiceRootsKuard.Name,
+					Port: 8080,
+				}},
+			}},
+			TCPProxy: &contour_v1.TCPProxy{
+				Services: []contour_v1.Service{{
+					Name: fixture.ServiceRootsKuard.Name,
+					Port: 8080,
+				}},
+			},
+		},
+	}
+
+	run(t, "tcpproxy includes child with routes and tcpproxy", testcase{
+		objs: []any{proxyTCPIncludesFoo, proxyTCPAndRoutesChildFoo, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: proxyTCPIncludesFoo.Name, Namespace: proxyTCPIncludesFoo.Namespace}: fixture.NewValidCondition().Valid(),
+			{Name: proxyTCPAndRoutesChildFoo.Name, Namespace: proxyTCPAndRoutesChildFoo.Namespace}: fixture.NewValidCondition().
+				WithError(contour_v1.ConditionTypeTCPProxyError, "RoutesNotAllowed", "Spec.Routes cannot be combined with Spec.TCPProxy on an HTTPProxy included by a tcpproxy"),
+		},
+	})
+
+	proxyInvalidConflictingIncludeConditionsSimple := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "example",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Includes: []contour_v1.Include{{
+				Name:      "blogteama",
+				Namespace: "teama",
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/blog",
+				}},
 			}, {
 				Name:      "blogteamb",
 				Namespace: "teamb",
@@ -2493,6 +2950,209 @@ func TestDAGStatus(t *testing.T) {
 		},
 	})
 
+	proxyTCPInvalidIdleTimeout := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "tcp-proxy-invalid-idle-timeout",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "tcpproxy.example.com",
+				TLS: &contour_v1.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &contour_v1.TCPProxy{
+				IdleTimeout: "-1s",
+				Services: []contour_v1.Service{{
+					Name: fixture.ServiceRootsKuard.Name,
+					Port: 8080,
+				}},
+			},
+		},
+	}
+
+	run(t, "httpproxy w/ tcpproxy w/ negative idle timeout", testcase{
+		objs: []any{proxyTCPInvalidIdleTimeout, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: proxyTCPInvalidIdleTimeout.Name, Namespace: proxyTCPInvalidIdleTimeout.Namespace}: fixture.NewValidCondition().
+				WithError(contour_v1.ConditionTypeTCPProxyError, "IdleTimeoutNotValid", `Spec.TCPProxy.IdleTimeout "-1s" must not be negative`),
+		},
+	})
+
+	proxyInvalidUpstreamProxyProtocol := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "invalid-upstream-proxy-protocol",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name:                  fixture.ServiceRootsKuard.Name,
+					Port:                  8080,
+					UpstreamProxyProtocol: "v3",
+				}},
+			}},
+		},
+	}
+
+	run(t, "httpproxy w/ invalid upstream proxy protocol version", testcase{
+		objs: []any{proxyInvalidUpstreamProxyProtocol, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: proxyInvalidUpstreamProxyProtocol.Name, Namespace: proxyInvalidUpstreamProxyProtocol.Namespace}: fixture.NewValidCondition().
+				WithRouteError("prefix: /", contour_v1.ConditionTypeServiceError, "UnsupportedUpstreamProxyProtocol", "unsupported upstream proxy protocol version: v3"),
+		},
+	})
+
+	proxyALPNProtocolsWithoutTLS := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "alpn-protocols-without-tls",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name:          fixture.ServiceRootsKuard.Name,
+					Port:          8080,
+					ALPNProtocols: []string{"http/1.1"},
+				}},
+			}},
+		},
+	}
+
+	run(t, "httpproxy w/ alpn protocols without tls upstream protocol", testcase{
+		objs: []any{proxyALPNProtocolsWithoutTLS, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: proxyALPNProtocolsWithoutTLS.Name, Namespace: proxyALPNProtocolsWithoutTLS.Namespace}: fixture.NewValidCondition().
+				WithRouteError("prefix: /", contour_v1.ConditionTypeServiceError, "UnsupportedALPNProtocols", "Service [kuard:8080] alpnProtocols is only supported with the tls or h2 protocol"),
+		},
+	})
+
+	proxyTCPALPNProtocolsWithoutTLS := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "tcp-proxy-alpn-protocols-without-tls",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "tcpproxy.example.com",
+				TLS: &contour_v1.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &contour_v1.TCPProxy{
+				Services: []contour_v1.Service{{
+					Name:          fixture.ServiceRootsKuard.Name,
+					Port:          8080,
+					ALPNProtocols: []string{"http/1.1"},
+				}},
+			},
+		},
+	}
+
+	run(t, "httpproxy w/ tcpproxy w/ alpn protocols without tls upstream protocol", testcase{
+		objs: []any{proxyTCPALPNProtocolsWithoutTLS, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: proxyTCPALPNProtocolsWithoutTLS.Name, Namespace: proxyTCPALPNProtocolsWithoutTLS.Namespace}: fixture.NewValidCondition().
+				WithError(contour_v1.ConditionTypeServiceError, "UnsupportedALPNProtocols", "Service [kuard:8080] alpnProtocols is only supported with the tls or h2 protocol"),
+		},
+	})
+
+	proxyHTTP2OptionsWithoutH2 := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "http2-options-without-h2",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: fixture.ServiceRootsKuard.Name,
+					Port: 8080,
+					HTTP2Options: &contour_v1.UpstreamHTTP2Options{
+						MaxConcurrentStreams: ptr.To(uint32(100)),
+					},
+				}},
+			}},
+		},
+	}
+
+	run(t, "httpproxy w/ http2 options on a non-h2 service", testcase{
+		objs: []any{proxyHTTP2OptionsWithoutH2, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: proxyHTTP2OptionsWithoutH2.Name, Namespace: proxyHTTP2OptionsWithoutH2.Namespace}: fixture.NewValidCondition().
+				WithRouteError("prefix: /", contour_v1.ConditionTypeServiceError, "HTTP2OptionsInvalid", `Service [kuard:8080] protocol must be h2 or h2c, got "" on http2 options`),
+		},
+	})
+
+	proxyHTTP2OptionsInvalidWindowSize := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "http2-options-invalid-window-size",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name:     fixture.ServiceRootsKuard.Name,
+					Port:     8080,
+					Protocol: ptr.To("h2c"),
+					HTTP2Options: &contour_v1.UpstreamHTTP2Options{
+						InitialStreamWindowSize: ptr.To(uint32(1024)),
+					},
+				}},
+			}},
+		},
+	}
+
+	run(t, "httpproxy w/ http2 options w/ invalid window size", testcase{
+		objs: []any{proxyHTTP2OptionsInvalidWindowSize, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: proxyHTTP2OptionsInvalidWindowSize.Name, Namespace: proxyHTTP2OptionsInvalidWindowSize.Namespace}: fixture.NewValidCondition().
+				WithRouteError("prefix: /", contour_v1.ConditionTypeServiceError, "HTTP2OptionsInvalid", "Service [kuard:8080] initialStreamWindowSize 1024 must be between 65535 and 2147483647 on http2 options"),
+		},
+	})
+
+	proxyInvalidSessionPersistence := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "invalid-session-persistence",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{{
+				SessionPersistence: &contour_v1.SessionPersistence{
+					Cookie: &contour_v1.SessionPersistenceCookie{Name: "session"},
+					Header: &contour_v1.SessionPersistenceHeader{Name: "x-session"},
+				},
+				Services: []contour_v1.Service{{
+					Name: fixture.ServiceRootsKuard.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	run(t, "httpproxy w/ both cookie and header session persistence", testcase{
+		objs: []any{proxyInvalidSessionPersistence, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: proxyInvalidSessionPersistence.Name, Namespace: proxyInvalidSessionPersistence.Namespace}: fixture.NewValidCondition().
+				WithRouteError("prefix: /", contour_v1.ConditionTypeRouteError, "SessionPersistenceNotValid", "route.sessionPersistence is invalid: exactly one of cookie or header must be specified"),
+		},
+	})
+
 	proxyTCPInvalidMissingTLS := &contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
 			Name:      "missing-tls",
@@ -2549,7 +3209,7 @@ func TestDAGStatus(t *testing.T) {
 		objs: []any{fixture.SecretRootsCert, fixture.ServiceRootsKuard, proxyInvalidMissingServiceWithTCPProxy},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
 			{Name: proxyInvalidMissingServiceWithTCPProxy.Name, Namespace: proxyInvalidMissingServiceWithTCPProxy.Namespace}: fixture.NewValidCondition().
-				WithError(contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Spec.Routes unresolved service reference: service "roots/missing" not found`),
+				WithRouteError("prefix: /", contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Spec.Routes unresolved service reference: service "roots/missing" not found`),
 		},
 	})
 
@@ -2583,7 +3243,7 @@ func TestDAGStatus(t *testing.T) {
 		objs: []any{fixture.SecretRootsCert, fixture.ServiceRootsKuard, proxyRoutePortNotMatchedWithTCP},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
 			{Name: proxyRoutePortNotMatchedWithTCP.Name, Namespace: proxyRoutePortNotMatchedWithTCP.Namespace}: fixture.NewValidCondition().
-				WithError(contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Spec.Routes unresolved service reference: port "9999" on service "roots/kuard" not matched`),
+				WithRouteError("prefix: /", contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Spec.Routes unresolved service reference: port "9999" on service "roots/kuard" not matched`),
 		},
 	})
 
@@ -2695,7 +3355,7 @@ func TestDAGStatus(t *testing.T) {
 		objs: []any{proxyInvalidNoServices, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
 			{Name: proxyInvalidNoServices.Name, Namespace: proxyInvalidNoServices.Namespace}: fixture.NewValidCondition().
-				WithError(contour_v1.ConditionTypeRouteError, "RouteActionCountNotValid", "must set exactly one of route.services or route.requestRedirectPolicy or route.directResponsePolicy"),
+				WithRouteError("prefix: /", contour_v1.ConditionTypeRouteError, "RouteActionCountNotValid", "must set exactly one of route.services or route.requestRedirectPolicy or route.directResponsePolicy"),
 		},
 	})
 
@@ -2855,26 +3515,164 @@ func TestDAGStatus(t *testing.T) {
 		},
 	})
 
-	tlsPassthroughAndValidation := &contour_v1.HTTPProxy{
+	invalidNotFoundResponse := &contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
-			Name:      "invalid",
-			Namespace: fixture.ServiceRootsKuard.Namespace,
+			Namespace: "roots",
+			Name:      "example",
 		},
 		Spec: contour_v1.HTTPProxySpec{
 			VirtualHost: &contour_v1.VirtualHost{
-				Fqdn: "tcpproxy.example.com",
-				TLS: &contour_v1.TLS{
-					Passthrough: true,
-					ClientValidation: &contour_v1.DownstreamValidation{
-						CACertificate: "aCAcert",
-					},
+				Fqdn: "example.com",
+				NotFoundResponse: &contour_v1.NotFoundResponse{
+					StatusCode: 200,
+					Body:       "not found",
 				},
 			},
-			TCPProxy: &contour_v1.TCPProxy{},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			}},
 		},
 	}
 
-	run(t, "passthrough and client auth are incompatible tlsPassthroughAndValidation", testcase{
+	run(t, "not found response status must be an error", testcase{
+		objs: []any{invalidNotFoundResponse, fixture.ServiceRootsHome},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: invalidNotFoundResponse.Name, Namespace: invalidNotFoundResponse.Namespace}: fixture.NewValidCondition().
+				WithError(contour_v1.ConditionTypeVirtualHostError, "NotFoundResponseNotValid", "Spec.VirtualHost.NotFoundResponse: status code 200 must be between 400 and 599"),
+		},
+	})
+
+	invalidCipherSuites := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "example",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &contour_v1.TLS{
+					SecretName:   "ssl-cert",
+					CipherSuites: []string{"ECDHE-RSA-AES256-GCM-SHA384", "NOTVALID"},
+				},
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	run(t, "invalid cipher suites", testcase{
+		objs: []any{invalidCipherSuites, fixture.SecretRootsCert, fixture.ServiceRootsHome},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: invalidCipherSuites.Name, Namespace: invalidCipherSuites.Namespace}: fixture.NewValidCondition().
+				WithError(contour_v1.ConditionTypeTLSError, "CipherSuitesNotValid", `Spec.VirtualHost.TLS.CipherSuites: invalid cipher suites ["NOTVALID"]`),
+		},
+	})
+
+	forwardClientCertificateWithoutDetails := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "example",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &contour_v1.TLS{
+					SecretName: "ssl-cert",
+					ClientValidation: &contour_v1.DownstreamValidation{
+						SkipClientCertValidation: true,
+						ForwardClientCertificate: &contour_v1.ClientCertificateDetails{},
+					},
+				},
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	forwardClientCertificateWithoutDetailsCondition := fixture.NewValidCondition().Valid()
+	forwardClientCertificateWithoutDetailsCondition.AddWarning(contour_v1.ConditionTypeTLSError, "ClientCertificateDetailsNotSelected",
+		"Spec.VirtualHost.TLS.ClientValidation.ForwardClientCertificate does not select any certificate details, only the certificate hash is forwarded")
+
+	run(t, "forwardClientCertificate without any certificate details selected", testcase{
+		objs: []any{forwardClientCertificateWithoutDetails, fixture.SecretRootsCert, fixture.ServiceRootsHome},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{
+				Name:      forwardClientCertificateWithoutDetails.Name,
+				Namespace: forwardClientCertificateWithoutDetails.Namespace,
+			}: forwardClientCertificateWithoutDetailsCondition,
+		},
+	})
+
+	onlyVerifyLeafCertCrlWithoutCRL := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "example",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &contour_v1.TLS{
+					SecretName: "ssl-cert",
+					ClientValidation: &contour_v1.DownstreamValidation{
+						SkipClientCertValidation: true,
+						OnlyVerifyLeafCertCrl:    true,
+					},
+				},
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	onlyVerifyLeafCertCrlWithoutCRLCondition := fixture.NewValidCondition().Valid()
+	onlyVerifyLeafCertCrlWithoutCRLCondition.AddWarning(contour_v1.ConditionTypeTLSError, "IgnoredField",
+		`ignoring field "Spec.VirtualHost.TLS.ClientValidation.OnlyVerifyLeafCertCrl"; it requires a CRL Secret`)
+
+	run(t, "crlOnlyVerifyLeafCert without crlSecret", testcase{
+		objs: []any{onlyVerifyLeafCertCrlWithoutCRL, fixture.SecretRootsCert, fixture.ServiceRootsHome},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{
+				Name:      onlyVerifyLeafCertCrlWithoutCRL.Name,
+				Namespace: onlyVerifyLeafCertCrlWithoutCRL.Namespace,
+			}: onlyVerifyLeafCertCrlWithoutCRLCondition,
+		},
+	})
+
+	tlsPassthroughAndValidation := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "invalid",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "tcpproxy.example.com",
+				TLS: &contour_v1.TLS{
+					Passthrough: true,
+					ClientValidation: &contour_v1.DownstreamValidation{
+						CACertificate: "aCAcert",
+					},
+				},
+			},
+			TCPProxy: &contour_v1.TCPProxy{},
+		},
+	}
+
+	run(t, "passthrough and client auth are incompatible tlsPassthroughAndValidation", testcase{
 		objs: []any{fixture.SecretRootsCert, tlsPassthroughAndValidation},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
 			{Name: tlsPassthroughAndValidation.Name, Namespace: tlsPassthroughAndValidation.Namespace}: fixture.NewValidCondition().
@@ -2882,6 +3680,195 @@ func TestDAGStatus(t *testing.T) {
 		},
 	})
 
+	tlsPassthroughAndHSTS := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "invalid",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "tcpproxy.example.com",
+				TLS: &contour_v1.TLS{
+					Passthrough: true,
+					HSTS: &contour_v1.HSTSPolicy{
+						MaxAge: 31536000,
+					},
+				},
+			},
+			TCPProxy: &contour_v1.TCPProxy{},
+		},
+	}
+
+	run(t, "passthrough and hsts are incompatible", testcase{
+		objs: []any{fixture.SecretRootsCert, tlsPassthroughAndHSTS},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: tlsPassthroughAndHSTS.Name, Namespace: tlsPassthroughAndHSTS.Namespace}: fixture.NewValidCondition().
+				WithError(contour_v1.ConditionTypeTLSError, "TLSIncompatibleFeatures", "Spec.VirtualHost.TLS passthrough cannot be combined with tls.hsts"),
+		},
+	})
+
+	tlsPassthroughAndSessionTickets := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "invalid",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "tcpproxy.example.com",
+				TLS: &contour_v1.TLS{
+					Passthrough:           true,
+					DisableSessionTickets: true,
+				},
+			},
+			TCPProxy: &contour_v1.TCPProxy{},
+		},
+	}
+
+	run(t, "passthrough and disableSessionTickets are incompatible", testcase{
+		objs: []any{fixture.SecretRootsCert, tlsPassthroughAndSessionTickets},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: tlsPassthroughAndSessionTickets.Name, Namespace: tlsPassthroughAndSessionTickets.Namespace}: fixture.NewValidCondition().
+				WithError(contour_v1.ConditionTypeTLSError, "TLSIncompatibleFeatures", "Spec.VirtualHost.TLS passthrough cannot be combined with tls.disableSessionTickets"),
+		},
+	})
+
+	tlsPassthroughAndCipherSuites := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "invalid",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "tcpproxy.example.com",
+				TLS: &contour_v1.TLS{
+					Passthrough:  true,
+					CipherSuites: []string{"ECDHE-RSA-AES256-GCM-SHA384"},
+				},
+			},
+			TCPProxy: &contour_v1.TCPProxy{},
+		},
+	}
+
+	run(t, "passthrough and cipherSuites are incompatible", testcase{
+		objs: []any{fixture.SecretRootsCert, tlsPassthroughAndCipherSuites},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: tlsPassthroughAndCipherSuites.Name, Namespace: tlsPassthroughAndCipherSuites.Namespace}: fixture.NewValidCondition().
+				WithError(contour_v1.ConditionTypeTLSError, "TLSIncompatibleFeatures", "Spec.VirtualHost.TLS passthrough cannot be combined with tls.cipherSuites"),
+		},
+	})
+
+	fqdnRegexWithoutWildcard := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "fqdnregex",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn:      "example.com",
+				FqdnRegex: `[a-z]+\.example\.com`,
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: fixture.ServiceRootsKuard.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	run(t, "fqdnRegex requires a wildcard fqdn", testcase{
+		objs: []any{fqdnRegexWithoutWildcard, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: fqdnRegexWithoutWildcard.Name, Namespace: fqdnRegexWithoutWildcard.Namespace}: fixture.NewValidCondition().
+				WithError(contour_v1.ConditionTypeVirtualHostError, "FQDNRegexNotValid", "Spec.VirtualHost.FqdnRegex requires Spec.VirtualHost.Fqdn to be a wildcard"),
+		},
+	})
+
+	fqdnRegexWithTCPProxy := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "fqdnregex",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn:      "*.example.com",
+				FqdnRegex: `[a-z]+\.example\.com`,
+				TLS: &contour_v1.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &contour_v1.TCPProxy{
+				Services: []contour_v1.Service{{
+					Name: fixture.ServiceRootsKuard.Name,
+					Port: 8080,
+				}},
+			},
+		},
+	}
+
+	run(t, "fqdnRegex cannot be used with tcpproxy", testcase{
+		objs: []any{fqdnRegexWithTCPProxy, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: fqdnRegexWithTCPProxy.Name, Namespace: fqdnRegexWithTCPProxy.Namespace}: fixture.NewValidCondition().
+				WithError(contour_v1.ConditionTypeVirtualHostError, "FQDNRegexNotValid", "Spec.VirtualHost.FqdnRegex cannot be used with Spec.TCPProxy"),
+		},
+	})
+
+	fqdnRegexOverlap := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "fqdnregex",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn:      "*.example.com",
+				FqdnRegex: `[a-z]+\.example\.com`,
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: fixture.ServiceRootsKuard.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	fqdnRegexOverlapped := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "exact",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "foo.example.com",
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: fixture.ServiceRootsKuard.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	fqdnRegexNotOverlapped := fqdnRegexOverlapped.DeepCopy()
+	fqdnRegexNotOverlapped.Name = "numbered"
+	fqdnRegexNotOverlapped.Spec.VirtualHost.Fqdn = "foo1.example.com"
+
+	// The exact fqdn wins in Envoy, so the regex proxy stays valid with a warning.
+	fqdnRegexOverlapCondition := fixture.NewValidCondition().Valid()
+	fqdnRegexOverlapCondition.AddWarning(contour_v1.ConditionTypeVirtualHostError, "FQDNRegexConflict",
+		`Spec.VirtualHost.FqdnRegex "[a-z]+\\.example\\.com" matches hosts served by other HTTPProxies, which take precedence: foo.example.com`)
+
+	run(t, "fqdnRegex overlapping another root proxy fqdn", testcase{
+		objs: []any{fqdnRegexOverlap, fqdnRegexOverlapped, fqdnRegexNotOverlapped, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: fqdnRegexOverlap.Name, Namespace: fqdnRegexOverlap.Namespace}:             fqdnRegexOverlapCondition,
+			{Name: fqdnRegexOverlapped.Name, Namespace: fqdnRegexOverlapped.Namespace}:       fixture.NewValidCondition().Valid(),
+			{Name: fqdnRegexNotOverlapped.Name, Namespace: fqdnRegexNotOverlapped.Namespace}: fixture.NewValidCondition().Valid(),
+		},
+	})
+
 	tlsPassthroughAndSecretName := &contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
 			Name:      "invalid",
@@ -2950,17 +3937,244 @@ func TestDAGStatus(t *testing.T) {
 		},
 	}
 
-	run(t, "proxy with no routes, includes, or tcpproxy is invalid", testcase{
-		objs: []any{emptyProxy},
+	run(t, "proxy with no routes, includes, or tcpproxy is invalid", testcase{
+		objs: []any{emptyProxy},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: emptyProxy.Name, Namespace: emptyProxy.Namespace}: fixture.NewValidCondition().
+				WithError(contour_v1.ConditionTypeSpecError, "NothingDefined", "HTTPProxy.Spec must have at least one Route, Include, or a TCPProxy"),
+		},
+	})
+
+	invalidResponseHeadersPolicyService := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "invalidRHPService",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{
+					{
+						Name: fixture.ServiceRootsKuard.Name,
+						Port: 8080,
+						ResponseHeadersPolicy: &contour_v1.HeadersPolicy{
+							Set: []contour_v1.HeaderValue{{
+								Name:  "Host",
+								Value: "external.com",
+							}},
+						},
+					},
+				},
+			}},
+		},
+	}
+
+	run(t, "responseHeadersPolicy, Host header invalid on Service", testcase{
+		objs: []any{invalidResponseHeadersPolicyService, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: invalidResponseHeadersPolicyService.Name, Namespace: invalidResponseHeadersPolicyService.Namespace}: fixture.NewValidCondition().
+				WithRouteError("prefix: /", contour_v1.ConditionTypeServiceError, "ResponseHeadersPolicyInvalid", `rewriting "Host" header is not supported on response headers`),
+		},
+	})
+
+	invalidResponseHeadersPolicyRoute := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "invalidRHPRoute",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{
+					{
+						Name: fixture.ServiceRootsKuard.Name,
+						Port: 8080,
+					},
+				},
+				ResponseHeadersPolicy: &contour_v1.HeadersPolicy{
+					Set: []contour_v1.HeaderValue{{
+						Name:  "Host",
+						Value: "external.com",
+					}},
+				},
+			}},
+		},
+	}
+
+	run(t, "responseHeadersPolicy, Host header invalid on Route", testcase{
+		objs: []any{invalidResponseHeadersPolicyRoute, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: invalidResponseHeadersPolicyRoute.Name, Namespace: invalidResponseHeadersPolicyRoute.Namespace}: fixture.NewValidCondition().
+				WithRouteError("prefix: /", contour_v1.ConditionTypeRouteError, "ResponseHeaderPolicyInvalid", `rewriting "Host" header is not supported on response headers`),
+		},
+	})
+
+	invalidCookiePolicyTTL := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "invalidCookiePolicyTTL",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{
+					{
+						Name: fixture.ServiceRootsKuard.Name,
+						Port: 8080,
+					},
+				},
+				LoadBalancerPolicy: &contour_v1.LoadBalancerPolicy{
+					Strategy: "Cookie",
+					CookiePolicy: &contour_v1.CookiePolicy{
+						TTL: "-1h",
+					},
+				},
+			}},
+		},
+	}
+
+	// An invalid TTL is ignored, so the proxy remains valid.
+	invalidCookiePolicyTTLCondition := fixture.NewValidCondition().Valid()
+	invalidCookiePolicyTTLCondition.AddWarning(contour_v1.ConditionTypeSpecError, "IgnoredField", `ignoring invalid cookie policy ttl "-1h"`)
+
+	run(t, "cookie load balancer policy with negative ttl", testcase{
+		objs: []any{invalidCookiePolicyTTL, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: invalidCookiePolicyTTL.Name, Namespace: invalidCookiePolicyTTL.Namespace}: invalidCookiePolicyTTLCondition,
+		},
+	})
+
+	cookiePolicyWithSourceIPHash := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cookiePolicyWithSourceIPHash",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{
+					{
+						Name: fixture.ServiceRootsKuard.Name,
+						Port: 8080,
+					},
+				},
+				LoadBalancerPolicy: &contour_v1.LoadBalancerPolicy{
+					Strategy: "Cookie",
+					RequestHashPolicies: []contour_v1.RequestHashPolicy{
+						{HashSourceIP: true},
+					},
+				},
+			}},
+		},
+	}
+
+	// Source IP hashing can't be combined with cookie hashing, so it is ignored.
+	cookiePolicyWithSourceIPHashCondition := fixture.NewValidCondition().Valid()
+	cookiePolicyWithSourceIPHashCondition.AddWarning(contour_v1.ConditionTypeSpecError, "IgnoredField",
+		`ignoring field "LoadBalancerPolicy.RequestHashPolicies"; request hash policies cannot be combined with the Cookie load balancer strategy`)
+
+	run(t, "cookie load balancer policy with source ip hash policy", testcase{
+		objs: []any{cookiePolicyWithSourceIPHash, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: cookiePolicyWithSourceIPHash.Name, Namespace: cookiePolicyWithSourceIPHash.Namespace}: cookiePolicyWithSourceIPHashCondition,
+		},
+	})
+
+	roundRobinWithRequestHashPolicies := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "roundRobinWithRequestHashPolicies",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{
+					{
+						Name: fixture.ServiceRootsKuard.Name,
+						Port: 8080,
+					},
+				},
+				LoadBalancerPolicy: &contour_v1.LoadBalancerPolicy{
+					Strategy: "RoundRobin",
+					RequestHashPolicies: []contour_v1.RequestHashPolicy{
+						{
+							HeaderHashOptions: &contour_v1.HeaderHashOptions{HeaderName: "X-Some-Header"},
+							Terminal:          true,
+						},
+						{HashSourceIP: true},
+					},
+				},
+			}},
+		},
+	}
+
+	roundRobinWithRequestHashPoliciesCondition := fixture.NewValidCondition().Valid()
+	roundRobinWithRequestHashPoliciesCondition.AddWarning(contour_v1.ConditionTypeSpecError, "IgnoredField",
+		`ignoring field "LoadBalancerPolicy.RequestHashPolicies"; request hash policies require the RequestHash or Maglev load balancer strategy`)
+
+	run(t, "round robin load balancer policy with request hash policies", testcase{
+		objs: []any{roundRobinWithRequestHashPolicies, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: roundRobinWithRequestHashPolicies.Name, Namespace: roundRobinWithRequestHashPolicies.Namespace}: roundRobinWithRequestHashPoliciesCondition,
+		},
+	})
+
+	emptyQueryParameterHashPolicy := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "emptyQueryParameterHashPolicy",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{
+					{
+						Name: fixture.ServiceRootsKuard.Name,
+						Port: 8080,
+					},
+				},
+				LoadBalancerPolicy: &contour_v1.LoadBalancerPolicy{
+					Strategy: "RequestHash",
+					RequestHashPolicies: []contour_v1.RequestHashPolicy{
+						{
+							QueryParameterHashOptions: &contour_v1.QueryParameterHashOptions{ParameterName: ""},
+						},
+						{
+							QueryParameterHashOptions: &contour_v1.QueryParameterHashOptions{ParameterName: "shard"},
+						},
+					},
+				},
+			}},
+		},
+	}
+
+	// The empty query parameter hash policy is dropped, the valid one is kept.
+	emptyQueryParameterHashPolicyCondition := fixture.NewValidCondition().Valid()
+	emptyQueryParameterHashPolicyCondition.AddWarning(contour_v1.ConditionTypeSpecError, "IgnoredField",
+		"ignoring invalid query parameter hash policy options with an invalid empty query parameter name")
+
+	run(t, "request hash load balancer policy with empty query parameter name", testcase{
+		objs: []any{emptyQueryParameterHashPolicy, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
-			{Name: emptyProxy.Name, Namespace: emptyProxy.Namespace}: fixture.NewValidCondition().
-				WithError(contour_v1.ConditionTypeSpecError, "NothingDefined", "HTTPProxy.Spec must have at least one Route, Include, or a TCPProxy"),
+			{Name: emptyQueryParameterHashPolicy.Name, Namespace: emptyQueryParameterHashPolicy.Namespace}: emptyQueryParameterHashPolicyCondition,
 		},
 	})
 
-	invalidResponseHeadersPolicyService := &contour_v1.HTTPProxy{
+	ringHashPolicyMinGreaterThanMax := &contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
-			Name:      "invalidRHPService",
+			Name:      "ringHashPolicyMinGreaterThanMax",
 			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_v1.HTTPProxySpec{
@@ -2972,29 +4186,47 @@ func TestDAGStatus(t *testing.T) {
 					{
 						Name: fixture.ServiceRootsKuard.Name,
 						Port: 8080,
-						ResponseHeadersPolicy: &contour_v1.HeadersPolicy{
-							Set: []contour_v1.HeaderValue{{
-								Name:  "Host",
-								Value: "external.com",
-							}},
-						},
+					},
+				},
+				LoadBalancerPolicy: &contour_v1.LoadBalancerPolicy{
+					Strategy: "RequestHash",
+					RequestHashPolicies: []contour_v1.RequestHashPolicy{
+						{HashSourceIP: true},
+					},
+					RingHashPolicy: &contour_v1.RingHashPolicy{
+						MinimumRingSize: ptr.To(uint64(4096)),
+						MaximumRingSize: ptr.To(uint64(1024)),
 					},
 				},
 			}},
 		},
 	}
 
-	run(t, "responseHeadersPolicy, Host header invalid on Service", testcase{
-		objs: []any{invalidResponseHeadersPolicyService, fixture.ServiceRootsKuard},
+	run(t, "ring hash policy with minimum ring size greater than maximum", testcase{
+		objs: []any{ringHashPolicyMinGreaterThanMax, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
-			{Name: invalidResponseHeadersPolicyService.Name, Namespace: invalidResponseHeadersPolicyService.Namespace}: fixture.NewValidCondition().
-				WithError(contour_v1.ConditionTypeServiceError, "ResponseHeadersPolicyInvalid", `rewriting "Host" header is not supported on response headers`),
+			{Name: ringHashPolicyMinGreaterThanMax.Name, Namespace: ringHashPolicyMinGreaterThanMax.Namespace}: fixture.NewValidCondition().
+				WithRouteError("prefix: /", contour_v1.ConditionTypeRouteError, "RingHashPolicyInvalid", "minimumRingSize 4096 must not be greater than maximumRingSize 1024 on ring hash policy"),
 		},
 	})
 
-	invalidResponseHeadersPolicyRoute := &contour_v1.HTTPProxy{
+	ringHashPolicyMaxBelowDefaultMin := ringHashPolicyMinGreaterThanMax.DeepCopy()
+	ringHashPolicyMaxBelowDefaultMin.Name = "ringHashPolicyMaxBelowDefaultMin"
+	ringHashPolicyMaxBelowDefaultMin.Spec.Routes[0].LoadBalancerPolicy.RingHashPolicy = &contour_v1.RingHashPolicy{
+		MaximumRingSize: ptr.To(uint64(512)),
+	}
+
+	run(t, "ring hash policy with only a maximum ring size below the default minimum", testcase{
+		objs: []any{ringHashPolicyMaxBelowDefaultMin, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: ringHashPolicyMaxBelowDefaultMin.Name, Namespace: ringHashPolicyMaxBelowDefaultMin.Namespace}: fixture.NewValidCondition().
+				WithRouteError("prefix: /", contour_v1.ConditionTypeRouteError, "RingHashPolicyInvalid", "maximumRingSize 512 must not be less than the default minimumRingSize 1024 on ring hash policy"),
+		},
+	})
+
+	maglevPolicyTableSizeNotPrime := &contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
-			Name:      "invalidRHPRoute",
+			Name:      "maglevPolicyTableSizeNotPrime",
 			Namespace: fixture.ServiceRootsKuard.Namespace,
 		},
 		Spec: contour_v1.HTTPProxySpec{
@@ -3008,21 +4240,24 @@ func TestDAGStatus(t *testing.T) {
 						Port: 8080,
 					},
 				},
-				ResponseHeadersPolicy: &contour_v1.HeadersPolicy{
-					Set: []contour_v1.HeaderValue{{
-						Name:  "Host",
-						Value: "external.com",
-					}},
+				LoadBalancerPolicy: &contour_v1.LoadBalancerPolicy{
+					Strategy: "Maglev",
+					RequestHashPolicies: []contour_v1.RequestHashPolicy{
+						{HashSourceIP: true},
+					},
+					MaglevPolicy: &contour_v1.MaglevPolicy{
+						TableSize: ptr.To(uint64(65536)),
+					},
 				},
 			}},
 		},
 	}
 
-	run(t, "responseHeadersPolicy, Host header invalid on Route", testcase{
-		objs: []any{invalidResponseHeadersPolicyRoute, fixture.ServiceRootsKuard},
+	run(t, "maglev policy with table size that is not prime", testcase{
+		objs: []any{maglevPolicyTableSizeNotPrime, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
-			{Name: invalidResponseHeadersPolicyRoute.Name, Namespace: invalidResponseHeadersPolicyRoute.Namespace}: fixture.NewValidCondition().
-				WithError(contour_v1.ConditionTypeRouteError, "ResponseHeaderPolicyInvalid", `rewriting "Host" header is not supported on response headers`),
+			{Name: maglevPolicyTableSizeNotPrime.Name, Namespace: maglevPolicyTableSizeNotPrime.Namespace}: fixture.NewValidCondition().
+				WithRouteError("prefix: /", contour_v1.ConditionTypeRouteError, "MaglevPolicyInvalid", "tableSize 65536 must be a prime number no larger than 5000011 on maglev policy"),
 		},
 	})
 
@@ -3060,7 +4295,7 @@ func TestDAGStatus(t *testing.T) {
 		objs: []any{duplicateCookieRewritePolicyRoute, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
 			{Name: duplicateCookieRewritePolicyRoute.Name, Namespace: duplicateCookieRewritePolicyRoute.Namespace}: fixture.NewValidCondition().
-				WithError(contour_v1.ConditionTypeRouteError, "CookieRewritePoliciesInvalid", `duplicate cookie rewrite rule for cookie "a-cookie" on route cookie rewrite rules`),
+				WithRouteError("prefix: /", contour_v1.ConditionTypeRouteError, "CookieRewritePoliciesInvalid", `duplicate cookie rewrite rule for cookie "a-cookie" on route cookie rewrite rules`),
 		},
 	})
 
@@ -3098,7 +4333,7 @@ func TestDAGStatus(t *testing.T) {
 		objs: []any{duplicateCookieRewritePolicyService, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
 			{Name: duplicateCookieRewritePolicyService.Name, Namespace: duplicateCookieRewritePolicyService.Namespace}: fixture.NewValidCondition().
-				WithError(contour_v1.ConditionTypeRouteError, "CookieRewritePoliciesInvalid", `duplicate cookie rewrite rule for cookie "a-cookie" on service cookie rewrite rules`),
+				WithRouteError("prefix: /", contour_v1.ConditionTypeRouteError, "CookieRewritePoliciesInvalid", `duplicate cookie rewrite rule for cookie "a-cookie" on service cookie rewrite rules`),
 		},
 	})
 
@@ -3131,7 +4366,7 @@ func TestDAGStatus(t *testing.T) {
 		objs: []any{emptyCookieRewritePolicyRoute, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
 			{Name: emptyCookieRewritePolicyRoute.Name, Namespace: emptyCookieRewritePolicyRoute.Namespace}: fixture.NewValidCondition().
-				WithError(contour_v1.ConditionTypeRouteError, "CookieRewritePoliciesInvalid", `no attributes rewritten for cookie "a-cookie" on route cookie rewrite rules`),
+				WithRouteError("prefix: /", contour_v1.ConditionTypeRouteError, "CookieRewritePoliciesInvalid", `no attributes rewritten for cookie "a-cookie" on route cookie rewrite rules`),
 		},
 	})
 
@@ -3164,7 +4399,7 @@ func TestDAGStatus(t *testing.T) {
 		objs: []any{emptyCookieRewritePolicyService, fixture.ServiceRootsKuard},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
 			{Name: emptyCookieRewritePolicyService.Name, Namespace: emptyCookieRewritePolicyService.Namespace}: fixture.NewValidCondition().
-				WithError(contour_v1.ConditionTypeRouteError, "CookieRewritePoliciesInvalid", `no attributes rewritten for cookie "a-cookie" on service cookie rewrite rules`),
+				WithRouteError("prefix: /", contour_v1.ConditionTypeRouteError, "CookieRewritePoliciesInvalid", `no attributes rewritten for cookie "a-cookie" on service cookie rewrite rules`),
 		},
 	})
 
@@ -3244,154 +4479,396 @@ func TestDAGStatus(t *testing.T) {
 		},
 	}
 
-	run(t, "proxy with invalid response timeout value is invalid", testcase{
-		objs: []any{invalidResponseTimeout, fixture.ServiceRootsKuard},
+	run(t, "proxy with invalid response timeout value is invalid", testcase{
+		objs: []any{invalidResponseTimeout, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{
+				Name:      invalidResponseTimeout.Name,
+				Namespace: invalidResponseTimeout.Namespace,
+			}: fixture.NewValidCondition().WithRouteError("prefix: /", contour_v1.ConditionTypeRouteError, "TimeoutPolicyNotValid",
+				`route.timeoutPolicy failed to parse: error parsing response timeout: unable to parse timeout string "invalid-val": time: invalid duration "invalid-val"`),
+		},
+	})
+
+	invalidIdleTimeout := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+			Name:      "invalid-timeouts",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{
+				{
+					Services: []contour_v1.Service{
+						{
+							Name: fixture.ServiceRootsKuard.Name,
+						},
+					},
+					TimeoutPolicy: &contour_v1.TimeoutPolicy{
+						Idle: "invalid-val",
+					},
+				},
+			},
+		},
+	}
+
+	run(t, "proxy with invalid idle timeout value is invalid", testcase{
+		objs: []any{invalidIdleTimeout, fixture.ServiceRootsKuard},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{
+				Name:      invalidIdleTimeout.Name,
+				Namespace: invalidIdleTimeout.Namespace,
+			}: fixture.NewValidCondition().WithRouteError("prefix: /", contour_v1.ConditionTypeRouteError, "TimeoutPolicyNotValid",
+				`route.timeoutPolicy failed to parse: error parsing idle timeout: unable to parse timeout string "invalid-val": time: invalid duration "invalid-val"`),
+		},
+	})
+
+	// issue 3197: Fallback and passthrough HTTPProxy directive should emit a config error
+	tlsPassthroughAndFallback := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace:  "roots",
+			Name:       "example",
+			Generation: 24,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				TLS: &contour_v1.TLS{
+					Passthrough:               true,
+					EnableFallbackCertificate: true,
+				},
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{{
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/foo",
+				}},
+				Services: []contour_v1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	run(t, "TLS with passthrough and fallback cert enabled is invalid", testcase{
+		objs: []any{tlsPassthroughAndFallback, fixture.ServiceRootsHome},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: tlsPassthroughAndFallback.Name, Namespace: tlsPassthroughAndFallback.Namespace}: fixture.NewValidCondition().
+				WithGeneration(tlsPassthroughAndFallback.Generation).WithError(
+				contour_v1.ConditionTypeTLSError, "TLSIncompatibleFeatures",
+				`Spec.VirtualHost.TLS: both Passthrough and enableFallbackCertificate were specified`,
+			),
+		},
+	})
+	tlsPassthrough := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace:  "roots",
+			Name:       "example",
+			Generation: 24,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				TLS: &contour_v1.TLS{
+					Passthrough:               true,
+					EnableFallbackCertificate: false,
+				},
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{{
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/foo",
+				}},
+				Services: []contour_v1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	run(t, "valid TLS passthrough", testcase{
+		objs: []any{tlsPassthrough, fixture.ServiceRootsHome},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: tlsPassthrough.Name, Namespace: tlsPassthrough.Namespace}: fixture.NewValidCondition().
+				WithGeneration(tlsPassthrough.Generation).
+				Valid(),
+		},
+	})
+
+	multipleRouteAction := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "multipleRouteAction",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{{
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/foo",
+				}},
+				Services: []contour_v1.Service{{
+					Name: "home",
+					Port: 8080,
+				}},
+				DirectResponsePolicy: &contour_v1.HTTPDirectResponsePolicy{
+					StatusCode: 200,
+					Body:       "success",
+				},
+			}},
+		},
+	}
+	run(t, "Selecting more than one routeAction is invalid", testcase{
+		objs: []any{multipleRouteAction},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: multipleRouteAction.Name, Namespace: multipleRouteAction.Namespace}: fixture.NewValidCondition().
+				WithRouteError("prefix: /foo", contour_v1.ConditionTypeRouteError, "RouteActionCountNotValid",
+					"must set exactly one of route.services or route.requestRedirectPolicy or route.directResponsePolicy"),
+		},
+	})
+
+	invalidWeightedBodies := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+			Name:      "invalid-weighted-bodies",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{{
+				DirectResponsePolicy: &contour_v1.HTTPDirectResponsePolicy{
+					StatusCode: 200,
+					WeightedBodies: []contour_v1.WeightedDirectResponseBody{
+						{Body: "A", Weight: 50},
+						{Body: "B", Weight: 40},
+					},
+				},
+			}},
+		},
+	}
+
+	run(t, "direct response weighted bodies not adding up to 100 is invalid", testcase{
+		objs: []any{invalidWeightedBodies},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: invalidWeightedBodies.Name, Namespace: invalidWeightedBodies.Namespace}: fixture.NewValidCondition().
+				WithRouteError("prefix: /", contour_v1.ConditionTypeRouteError, "DirectResponsePolicy",
+					"route.directResponsePolicy is invalid: weightedBodies weights must add up to 100, got 90"),
+		},
+	})
+
+	bodyAndWeightedBodies := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+			Name:      "body-and-weighted-bodies",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{{
+				DirectResponsePolicy: &contour_v1.HTTPDirectResponsePolicy{
+					StatusCode: 200,
+					Body:       "C",
+					WeightedBodies: []contour_v1.WeightedDirectResponseBody{
+						{Body: "A", Weight: 50},
+						{Body: "B", Weight: 50},
+					},
+				},
+			}},
+		},
+	}
+
+	run(t, "direct response with both body and weighted bodies is invalid", testcase{
+		objs: []any{bodyAndWeightedBodies},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: bodyAndWeightedBodies.Name, Namespace: bodyAndWeightedBodies.Namespace}: fixture.NewValidCondition().
+				WithRouteError("prefix: /", contour_v1.ConditionTypeRouteError, "DirectResponsePolicy",
+					"route.directResponsePolicy is invalid: cannot specify both body and weightedBodies"),
+		},
+	})
+
+	wildcardCertSecret := &core_v1.Secret{
+		ObjectMeta: fixture.ObjectMeta("roots/wildcard"),
+		Type:       core_v1.SecretTypeTLS,
+		Data:       secretdata(fixture.WILDCARD_EXAMPLE_COM_CERT, fixture.WILDCARD_EXAMPLE_COM_KEY),
+	}
+
+	proxyWildcardCertCoversFQDN := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+			Name:      "wildcard-cert-covers-fqdn",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "foo.example.com",
+				TLS: &contour_v1.TLS{
+					SecretName: wildcardCertSecret.Name,
+				},
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: fixture.ServiceRootsKuard.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	run(t, "wildcard certificate covering the fqdn is valid", testcase{
+		objs: []any{wildcardCertSecret, fixture.ServiceRootsKuard, proxyWildcardCertCoversFQDN},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
-			{
-				Name:      invalidResponseTimeout.Name,
-				Namespace: invalidResponseTimeout.Namespace,
-			}: fixture.NewValidCondition().WithError(contour_v1.ConditionTypeRouteError, "TimeoutPolicyNotValid",
-				`route.timeoutPolicy failed to parse: error parsing response timeout: unable to parse timeout string "invalid-val": time: invalid duration "invalid-val"`),
+			{Name: proxyWildcardCertCoversFQDN.Name, Namespace: proxyWildcardCertCoversFQDN.Namespace}: fixture.NewValidCondition().
+				Valid(),
 		},
 	})
 
-	invalidIdleTimeout := &contour_v1.HTTPProxy{
+	proxyWildcardCertMismatch := &contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
 			Namespace: fixture.ServiceRootsKuard.Namespace,
-			Name:      "invalid-timeouts",
+			Name:      "wildcard-cert-mismatch",
 		},
 		Spec: contour_v1.HTTPProxySpec{
 			VirtualHost: &contour_v1.VirtualHost{
-				Fqdn: "example.com",
-			},
-			Routes: []contour_v1.Route{
-				{
-					Services: []contour_v1.Service{
-						{
-							Name: fixture.ServiceRootsKuard.Name,
-						},
-					},
-					TimeoutPolicy: &contour_v1.TimeoutPolicy{
-						Idle: "invalid-val",
-					},
+				Fqdn: "foo.bar.example.com",
+				TLS: &contour_v1.TLS{
+					SecretName: wildcardCertSecret.Name,
 				},
 			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: fixture.ServiceRootsKuard.Name,
+					Port: 8080,
+				}},
+			}},
 		},
 	}
 
-	run(t, "proxy with invalid idle timeout value is invalid", testcase{
-		objs: []any{invalidIdleTimeout, fixture.ServiceRootsKuard},
+	run(t, "wildcard certificate not covering the fqdn has a warning", testcase{
+		objs: []any{wildcardCertSecret, fixture.ServiceRootsKuard, proxyWildcardCertMismatch},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
-			{
-				Name:      invalidIdleTimeout.Name,
-				Namespace: invalidIdleTimeout.Namespace,
-			}: fixture.NewValidCondition().WithError(contour_v1.ConditionTypeRouteError, "TimeoutPolicyNotValid",
-				`route.timeoutPolicy failed to parse: error parsing idle timeout: unable to parse timeout string "invalid-val": time: invalid duration "invalid-val"`),
+			{Name: proxyWildcardCertMismatch.Name, Namespace: proxyWildcardCertMismatch.Namespace}: func() contour_v1.DetailedCondition {
+				dc := fixture.NewValidCondition().Valid()
+				dc.AddWarning(contour_v1.ConditionTypeTLSError, "CertificateHostnameMismatch",
+					`Spec.VirtualHost.TLS Secret "wildcard" certificate does not cover fqdn "foo.bar.example.com"`)
+				return dc
+			}(),
 		},
 	})
 
-	// issue 3197: Fallback and passthrough HTTPProxy directive should emit a config error
-	tlsPassthroughAndFallback := &contour_v1.HTTPProxy{
+	proxyMergeSlashesInsecure := &contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
-			Namespace:  "roots",
-			Name:       "example",
-			Generation: 24,
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+			Name:      "merge-slashes-insecure",
 		},
 		Spec: contour_v1.HTTPProxySpec{
 			VirtualHost: &contour_v1.VirtualHost{
-				TLS: &contour_v1.TLS{
-					Passthrough:               true,
-					EnableFallbackCertificate: true,
-				},
 				Fqdn: "example.com",
+				PathNormalizationPolicy: &contour_v1.PathNormalizationPolicy{
+					MergeSlashes: ptr.To(false),
+				},
 			},
 			Routes: []contour_v1.Route{{
-				Conditions: []contour_v1.MatchCondition{{
-					Prefix: "/foo",
-				}},
 				Services: []contour_v1.Service{{
-					Name: "home",
+					Name: fixture.ServiceRootsKuard.Name,
 					Port: 8080,
 				}},
 			}},
 		},
 	}
 
-	run(t, "TLS with passthrough and fallback cert enabled is invalid", testcase{
-		objs: []any{tlsPassthroughAndFallback, fixture.ServiceRootsHome},
+	run(t, "path normalization policy disagreeing with the shared HTTP listener has a warning", testcase{
+		objs: []any{fixture.ServiceRootsKuard, proxyMergeSlashesInsecure},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
-			{Name: tlsPassthroughAndFallback.Name, Namespace: tlsPassthroughAndFallback.Namespace}: fixture.NewValidCondition().
-				WithGeneration(tlsPassthroughAndFallback.Generation).WithError(
-				contour_v1.ConditionTypeTLSError, "TLSIncompatibleFeatures",
-				`Spec.VirtualHost.TLS: both Passthrough and enableFallbackCertificate were specified`,
-			),
+			{Name: proxyMergeSlashesInsecure.Name, Namespace: proxyMergeSlashesInsecure.Namespace}: func() contour_v1.DetailedCondition {
+				dc := fixture.NewValidCondition().Valid()
+				dc.AddWarning(contour_v1.ConditionTypeVirtualHostError, "PathNormalizationPolicyNotApplied",
+					"Spec.VirtualHost.PathNormalizationPolicy.MergeSlashes is not applied to insecure requests, the HTTP listener is shared and uses the global setting")
+				return dc
+			}(),
 		},
 	})
-	tlsPassthrough := &contour_v1.HTTPProxy{
+
+	proxyMergeSlashesSecure := &contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
-			Namespace:  "roots",
-			Name:       "example",
-			Generation: 24,
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+			Name:      "merge-slashes-secure",
 		},
 		Spec: contour_v1.HTTPProxySpec{
 			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
 				TLS: &contour_v1.TLS{
-					Passthrough:               true,
-					EnableFallbackCertificate: false,
+					SecretName: fixture.SecretRootsCert.Name,
+				},
+				PathNormalizationPolicy: &contour_v1.PathNormalizationPolicy{
+					MergeSlashes: ptr.To(false),
 				},
-				Fqdn: "example.com",
 			},
 			Routes: []contour_v1.Route{{
-				Conditions: []contour_v1.MatchCondition{{
-					Prefix: "/foo",
-				}},
 				Services: []contour_v1.Service{{
-					Name: "home",
+					Name: fixture.ServiceRootsKuard.Name,
 					Port: 8080,
 				}},
 			}},
 		},
 	}
 
-	run(t, "valid TLS passthrough", testcase{
-		objs: []any{tlsPassthrough, fixture.ServiceRootsHome},
+	run(t, "path normalization policy on a TLS virtual host is valid", testcase{
+		objs: []any{fixture.SecretRootsCert, fixture.ServiceRootsKuard, proxyMergeSlashesSecure},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
-			{Name: tlsPassthrough.Name, Namespace: tlsPassthrough.Namespace}: fixture.NewValidCondition().
-				WithGeneration(tlsPassthrough.Generation).
+			{Name: proxyMergeSlashesSecure.Name, Namespace: proxyMergeSlashesSecure.Namespace}: fixture.NewValidCondition().
 				Valid(),
 		},
 	})
 
-	multipleRouteAction := &contour_v1.HTTPProxy{
+	proxyMergeSlashesPermitInsecure := proxyMergeSlashesSecure.DeepCopy()
+	proxyMergeSlashesPermitInsecure.Name = "merge-slashes-permit-insecure"
+	proxyMergeSlashesPermitInsecure.Spec.Routes[0].PermitInsecure = true
+
+	run(t, "path normalization policy on a TLS virtual host with insecure routes has a warning", testcase{
+		objs: []any{fixture.SecretRootsCert, fixture.ServiceRootsKuard, proxyMergeSlashesPermitInsecure},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			{Name: proxyMergeSlashesPermitInsecure.Name, Namespace: proxyMergeSlashesPermitInsecure.Namespace}: func() contour_v1.DetailedCondition {
+				dc := fixture.NewValidCondition().Valid()
+				dc.AddWarning(contour_v1.ConditionTypeVirtualHostError, "PathNormalizationPolicyNotApplied",
+					"Spec.VirtualHost.PathNormalizationPolicy.MergeSlashes is not applied to insecure requests, the HTTP listener is shared and uses the global setting")
+				return dc
+			}(),
+		},
+	})
+
+	proxyPermitInsecureAndRequireTLS := &contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
-			Namespace: "roots",
-			Name:      "multipleRouteAction",
+			Namespace: fixture.ServiceRootsKuard.Namespace,
+			Name:      "permit-insecure-and-require-tls",
 		},
 		Spec: contour_v1.HTTPProxySpec{
 			VirtualHost: &contour_v1.VirtualHost{
-				Fqdn: "example.com",
+				Fqdn:           "example.com",
+				PermitInsecure: true,
+				TLS: &contour_v1.TLS{
+					SecretName: fixture.SecretRootsCert.Name,
+				},
 			},
 			Routes: []contour_v1.Route{{
-				Conditions: []contour_v1.MatchCondition{{
-					Prefix: "/foo",
-				}},
+				PermitInsecure: true,
+				RequireTLS:     true,
 				Services: []contour_v1.Service{{
-					Name: "home",
+					Name: fixture.ServiceRootsKuard.Name,
 					Port: 8080,
 				}},
-				DirectResponsePolicy: &contour_v1.HTTPDirectResponsePolicy{
-					StatusCode: 200,
-					Body:       "success",
-				},
 			}},
 		},
 	}
-	run(t, "Selecting more than one routeAction is invalid", testcase{
-		objs: []any{multipleRouteAction},
+
+	run(t, "route with both permitInsecure and requireTLS", testcase{
+		objs: []any{fixture.SecretRootsCert, fixture.ServiceRootsKuard, proxyPermitInsecureAndRequireTLS},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
-			{Name: multipleRouteAction.Name, Namespace: multipleRouteAction.Namespace}: fixture.NewValidCondition().
-				WithError(contour_v1.ConditionTypeRouteError, "RouteActionCountNotValid",
-					"must set exactly one of route.services or route.requestRedirectPolicy or route.directResponsePolicy"),
+			{Name: proxyPermitInsecureAndRequireTLS.Name, Namespace: proxyPermitInsecureAndRequireTLS.Namespace}: fixture.NewValidCondition().
+				WithRouteError("prefix: /", contour_v1.ConditionTypeRouteError, "RequireTLSNotValid", "route cannot set both permitInsecure and requireTLS"),
 		},
 	})
 
@@ -3696,6 +5173,56 @@ func TestDAGStatus(t *testing.T) {
 		},
 	})
 
+	jwtVerificationRemoteJWKSMissingHost := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "jwt-verification-remote-jwks-missing-host",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &contour_v1.TLS{
+					SecretName: fixture.SecretRootsCert.Name,
+				},
+				JWTProviders: []contour_v1.JWTProvider{
+					{
+						Name: "provider-1",
+						RemoteJWKS: contour_v1.RemoteJWKS{
+							URI: "https:///jwks.json",
+						},
+					},
+				},
+			},
+			Routes: []contour_v1.Route{
+				{
+					Conditions: []contour_v1.MatchCondition{{
+						Prefix: "/foo",
+					}},
+					Services: []contour_v1.Service{{
+						Name: "home",
+						Port: 8080,
+					}},
+				},
+			},
+		},
+	}
+
+	run(t, "JWT verification remote JWKS URI missing host", testcase{
+		objs: []any{
+			jwtVerificationRemoteJWKSMissingHost,
+			fixture.SecretRootsCert,
+			fixture.ServiceRootsHome,
+		},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			k8s.NamespacedNameOf(jwtVerificationRemoteJWKSMissingHost): fixture.NewValidCondition().
+				WithError(
+					contour_v1.ConditionTypeJWTVerificationError,
+					"RemoteJWKSHostMissing",
+					"Spec.VirtualHost.JWTProviders.RemoteJWKS.URI \"https:///jwks.json\" must include a host",
+				),
+		},
+	})
+
 	jwtVerificationInvalidRemoteJWKSTimeout := &contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
 			Namespace: "roots",
@@ -4136,7 +5663,8 @@ func TestDAGStatus(t *testing.T) {
 		},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
 			k8s.NamespacedNameOf(jwtVerificationInvalidRequireAndDisabledSpecified): fixture.NewValidCondition().
-				WithError(
+				WithRouteError(
+					"prefix: /foo",
 					contour_v1.ConditionTypeJWTVerificationError,
 					"InvalidJWTVerificationPolicy",
 					"route's JWT verification policy cannot specify both require and disabled",
@@ -4536,6 +6064,94 @@ func TestDAGStatus(t *testing.T) {
 		},
 	})
 
+	ipFilterRouteFilterRulesInvalidProxy := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "ip-filter-route-invalid-filter-rules-proxy",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{
+				{
+					Conditions: []contour_v1.MatchCondition{{
+						Prefix: "/admin",
+					}},
+					Services: []contour_v1.Service{{
+						Name: "home",
+						Port: 8080,
+					}},
+					IPAllowFilterPolicy: []contour_v1.IPFilterPolicy{{
+						Source: contour_v1.IPFilterSourceRemote,
+						CIDR:   "10.0.0.0/33",
+					}},
+				},
+			},
+		},
+	}
+
+	run(t, "route ip-filter invalid filter rules proxy", testcase{
+		objs: []any{
+			ipFilterRouteFilterRulesInvalidProxy,
+			fixture.ServiceRootsHome,
+		},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			k8s.NamespacedNameOf(ipFilterRouteFilterRulesInvalidProxy): fixture.NewValidCondition().
+				WithError(
+					contour_v1.ConditionTypeIPFilterError,
+					"InvalidCIDR",
+					"10.0.0.0/33 failed to parse: invalid CIDR address: 10.0.0.0/33",
+				),
+		},
+	})
+
+	ipFilterRouteAllowAndDenyProxy := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "ip-filter-route-allow-and-deny-proxy",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []contour_v1.Route{
+				{
+					Conditions: []contour_v1.MatchCondition{{
+						Prefix: "/admin",
+					}},
+					Services: []contour_v1.Service{{
+						Name: "home",
+						Port: 8080,
+					}},
+					IPAllowFilterPolicy: []contour_v1.IPFilterPolicy{{
+						Source: contour_v1.IPFilterSourcePeer,
+						CIDR:   "10.0.0.0/8",
+					}},
+					IPDenyFilterPolicy: []contour_v1.IPFilterPolicy{{
+						Source: contour_v1.IPFilterSourcePeer,
+						CIDR:   "10.1.0.0/16",
+					}},
+				},
+			},
+		},
+	}
+
+	run(t, "route ip-filter invalid allow and deny proxy", testcase{
+		objs: []any{
+			ipFilterRouteAllowAndDenyProxy,
+			fixture.ServiceRootsHome,
+		},
+		want: map[types.NamespacedName]contour_v1.DetailedCondition{
+			k8s.NamespacedNameOf(ipFilterRouteAllowAndDenyProxy): fixture.NewValidCondition().
+				WithError(
+					contour_v1.ConditionTypeIPFilterError,
+					"IncompatibleIPAddressFilters",
+					"cannot specify both `ipAllowPolicy` and `ipDenyPolicy`",
+				),
+		},
+	})
+
 	// proxyWithInvalidSlowStartWindow is invalid because it has invalid window size syntax.
 	proxyWithInvalidSlowStartWindow := &contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
@@ -4613,7 +6229,8 @@ func TestDAGStatus(t *testing.T) {
 		},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
 			k8s.NamespacedNameOf(proxyWithInvalidSlowStartWindow): fixture.NewValidCondition().
-				WithError(
+				WithRouteError(
+					"prefix: /",
 					contour_v1.ConditionTypeServiceError,
 					"SlowStartInvalid",
 					"error parsing window: time: invalid duration \"invalid\" on slow start",
@@ -4628,7 +6245,8 @@ func TestDAGStatus(t *testing.T) {
 		},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
 			k8s.NamespacedNameOf(proxyWithInvalidSlowStartAggression): fixture.NewValidCondition().
-				WithError(
+				WithRouteError(
+					"prefix: /",
 					contour_v1.ConditionTypeServiceError,
 					"SlowStartInvalid",
 					"error parsing aggression: \"invalid\" is not a decimal number on slow start",
@@ -4643,7 +6261,8 @@ func TestDAGStatus(t *testing.T) {
 		},
 		want: map[types.NamespacedName]contour_v1.DetailedCondition{
 			k8s.NamespacedNameOf(proxyWithInvalidSlowStartLBStrategy): fixture.NewValidCondition().
-				WithError(
+				WithRouteError(
+					"prefix: /",
 					contour_v1.ConditionTypeServiceError,
 					"SlowStartInvalid",
 					"slow start is only supported with RoundRobin or WeightedLeastRequest load balancer strategy",
@@ -5215,16 +6834,109 @@ func validGatewayStatusUpdate(listenerName string, listenerProtocol gatewayapi_v
 					Message: status.MessageValidGateway,
 				},
 			},
-			ListenerStatus: map[string]*gatewayapi_v1.ListenerStatus{
-				listenerName: {
-					Name:           gatewayapi_v1.SectionName(listenerName),
-					AttachedRoutes: attachedRoutes,
-					SupportedKinds: supportedKinds,
-					Conditions:     listenerValidConditions(),
+			ListenerStatus: map[string]*gatewayapi_v1.ListenerStatus{
+				listenerName: {
+					Name:           gatewayapi_v1.SectionName(listenerName),
+					AttachedRoutes: attachedRoutes,
+					SupportedKinds: supportedKinds,
+					Conditions:     listenerValidConditions(),
+				},
+			},
+		},
+	}
+}
+
+func TestDAGStatusValidationErrors(t *testing.T) {
+	type testcase struct {
+		objs []any
+		want []contour_v1.ValidationError
+	}
+
+	run := func(t *testing.T, desc string, tc testcase) {
+		t.Helper()
+		t.Run(desc, func(t *testing.T) {
+			builder := Builder{
+				Source: KubernetesCache{
+					FieldLogger: fixture.NewTestLogger(t),
+				},
+				Processors: []Processor{
+					&ListenerProcessor{},
+					&HTTPProxyProcessor{},
+				},
+			}
+			for _, o := range tc.objs {
+				builder.Source.Insert(o)
+			}
+			dag := builder.Build()
+
+			updates := dag.StatusCache.GetProxyUpdates()
+			require.Len(t, updates, 1)
+			assert.Equal(t, tc.want, updates[0].ValidationErrors)
+		})
+	}
+
+	proxy := func(secretName string, services ...string) *contour_v1.HTTPProxy {
+		p := &contour_v1.HTTPProxy{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Namespace: "roots",
+				Name:      "example",
+			},
+			Spec: contour_v1.HTTPProxySpec{
+				VirtualHost: &contour_v1.VirtualHost{
+					Fqdn: "example.com",
 				},
 			},
-		},
+		}
+		if secretName != "" {
+			p.Spec.VirtualHost.TLS = &contour_v1.TLS{SecretName: secretName}
+		}
+		for _, svc := range services {
+			p.Spec.Routes = append(p.Spec.Routes, contour_v1.Route{
+				Conditions: []contour_v1.MatchCondition{{Prefix: "/" + svc}},
+				Services:   []contour_v1.Service{{Name: svc, Port: 8080}},
+			})
+		}
+		return p
+	}
+
+	run(t, "valid proxy has no validation errors", testcase{
+		objs: []any{proxy("", "home"), fixture.ServiceRootsHome},
+	})
+
+	run(t, "missing service", testcase{
+		objs: []any{proxy("", "home", "missing", "missing"), fixture.ServiceRootsHome},
+		want: []contour_v1.ValidationError{{
+			Kind:      "Service",
+			Namespace: "roots",
+			Name:      "missing",
+			Message:   `service "roots/missing" not found`,
+		}},
+	})
+
+	run(t, "missing secret", testcase{
+		objs: []any{proxy("missing-cert", "home"), fixture.ServiceRootsHome},
+		want: []contour_v1.ValidationError{{
+			Kind:      "Secret",
+			Namespace: "roots",
+			Name:      "missing-cert",
+			Message:   "Secret not found",
+		}},
+	})
+
+	clientValidation := proxy(fixture.SecretRootsCert.Name, "home")
+	clientValidation.Spec.VirtualHost.TLS.ClientValidation = &contour_v1.DownstreamValidation{
+		CACertificate: "missing-ca",
 	}
+
+	run(t, "missing client validation CA secret", testcase{
+		objs: []any{clientValidation, fixture.SecretRootsCert, fixture.ServiceRootsHome},
+		want: []contour_v1.ValidationError{{
+			Kind:      "Secret",
+			Namespace: "roots",
+			Name:      "missing-ca",
+			Message:   "Secret not found",
+		}},
+	})
 }
 
 func TestGatewayAPIHTTPRouteDAGStatus(t *testing.T) {
@@ -9953,6 +11665,153 @@ func TestGatewayAPITLSRouteDAGStatus(t *testing.T) {
 		wantGatewayStatusUpdate: validGatewayStatusUpdate(string(gw.Spec.Listeners[0].Name), gw.Spec.Listeners[0].Protocol, 1),
 	})
 
+	// HTTPProxies also require an insecure listener on the Gateway.
+	gwWithHTTP := gw.DeepCopy()
+	gwWithHTTP.Spec.Listeners = append(gwWithHTTP.Spec.Listeners, gatewayapi_v1.Listener{
+		Name:     "http",
+		Port:     80,
+		Protocol: gatewayapi_v1.HTTPProtocolType,
+		AllowedRoutes: &gatewayapi_v1.AllowedRoutes{
+			Namespaces: &gatewayapi_v1.RouteNamespaces{
+				From: ptr.To(gatewayapi_v1.NamespacesFromAll),
+			},
+		},
+	})
+	gwWithHTTPStatusUpdate := validGatewayStatusUpdate("tls-passthrough", gatewayapi_v1.TLSProtocolType, 1)
+	gwWithHTTPStatusUpdate[0].ListenerStatus["http"] = validGatewayStatusUpdate("http", gatewayapi_v1.HTTPProtocolType, 0)[0].ListenerStatus["http"]
+
+	run(t, "TLSRoute: hostname already terminated by an HTTPProxy", testcase{
+		gateway: gwWithHTTP,
+		objs: []any{
+			kuardService,
+			fixture.SecretRootsCert,
+			&core_v1.Service{
+				ObjectMeta: fixture.ObjectMeta("roots/home"),
+				Spec: core_v1.ServiceSpec{
+					Ports: []core_v1.ServicePort{makeServicePort("http", "TCP", 8080, 8080)},
+				},
+			},
+			&contour_v1.HTTPProxy{
+				ObjectMeta: fixture.ObjectMeta("roots/terminate"),
+				Spec: contour_v1.HTTPProxySpec{
+					VirtualHost: &contour_v1.VirtualHost{
+						Fqdn: "test.projectcontour.io",
+						TLS: &contour_v1.TLS{
+							SecretName: fixture.SecretRootsCert.Name,
+						},
+					},
+					Routes: []contour_v1.Route{{
+						Services: []contour_v1.Service{{Name: "home", Port: 8080}},
+					}},
+				},
+			},
+			&gatewayapi_v1alpha2.TLSRoute{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "basic",
+					Namespace: "default",
+				},
+				Spec: gatewayapi_v1alpha2.TLSRouteSpec{
+					CommonRouteSpec: gatewayapi_v1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1.ParentReference{
+							gatewayapi.GatewayParentRef("projectcontour", "contour"),
+						},
+					},
+					Hostnames: []gatewayapi_v1.Hostname{"test.projectcontour.io", "passthrough.projectcontour.io"},
+					Rules: []gatewayapi_v1alpha2.TLSRouteRule{{
+						BackendRefs: gatewayapi.TLSRouteBackendRef("kuard", 8080, nil),
+					}},
+				},
+			},
+		},
+		wantRouteConditions: []*status.RouteStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "default", Name: "basic"},
+			RouteParentStatuses: []*gatewayapi_v1.RouteParentStatus{
+				{
+					ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+					Conditions: []meta_v1.Condition{
+						routeResolvedRefsCondition(),
+						{
+							Type:    string(gatewayapi_v1.RouteConditionPartiallyInvalid),
+							Status:  contour_v1.ConditionTrue,
+							Reason:  string(status.ReasonHostnameConflict),
+							Message: "hostname(s) test.projectcontour.io already terminate TLS on this listener and cannot also be passed through",
+						},
+						{
+							Type:    string(gatewayapi_v1.RouteConditionAccepted),
+							Status:  contour_v1.ConditionTrue,
+							Reason:  string(gatewayapi_v1.RouteReasonAccepted),
+							Message: "Accepted TLSRoute",
+						},
+					},
+				},
+			},
+		}},
+		wantGatewayStatusUpdate: gwWithHTTPStatusUpdate,
+	})
+
+	run(t, "TLSRoute: all hostnames already terminated by an HTTPProxy", testcase{
+		gateway: gwWithHTTP,
+		objs: []any{
+			kuardService,
+			fixture.SecretRootsCert,
+			&core_v1.Service{
+				ObjectMeta: fixture.ObjectMeta("roots/home"),
+				Spec: core_v1.ServiceSpec{
+					Ports: []core_v1.ServicePort{makeServicePort("http", "TCP", 8080, 8080)},
+				},
+			},
+			&contour_v1.HTTPProxy{
+				ObjectMeta: fixture.ObjectMeta("roots/terminate"),
+				Spec: contour_v1.HTTPProxySpec{
+					VirtualHost: &contour_v1.VirtualHost{
+						Fqdn: "test.projectcontour.io",
+						TLS: &contour_v1.TLS{
+							SecretName: fixture.SecretRootsCert.Name,
+						},
+					},
+					Routes: []contour_v1.Route{{
+						Services: []contour_v1.Service{{Name: "home", Port: 8080}},
+					}},
+				},
+			},
+			&gatewayapi_v1alpha2.TLSRoute{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "basic",
+					Namespace: "default",
+				},
+				Spec: gatewayapi_v1alpha2.TLSRouteSpec{
+					CommonRouteSpec: gatewayapi_v1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1.ParentReference{
+							gatewayapi.GatewayParentRef("projectcontour", "contour"),
+						},
+					},
+					Hostnames: []gatewayapi_v1.Hostname{"test.projectcontour.io"},
+					Rules: []gatewayapi_v1alpha2.TLSRouteRule{{
+						BackendRefs: gatewayapi.TLSRouteBackendRef("kuard", 8080, nil),
+					}},
+				},
+			},
+		},
+		wantRouteConditions: []*status.RouteStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "default", Name: "basic"},
+			RouteParentStatuses: []*gatewayapi_v1.RouteParentStatus{
+				{
+					ParentRef: gatewayapi.GatewayParentRef("projectcontour", "contour"),
+					Conditions: []meta_v1.Condition{
+						routeResolvedRefsCondition(),
+						{
+							Type:    string(gatewayapi_v1.RouteConditionAccepted),
+							Status:  contour_v1.ConditionFalse,
+							Reason:  string(status.ReasonHostnameConflict),
+							Message: "hostname(s) test.projectcontour.io already terminate TLS on this listener and cannot also be passed through",
+						},
+					},
+				},
+			},
+		}},
+		wantGatewayStatusUpdate: gwWithHTTPStatusUpdate,
+	})
+
 	run(t, "TLSRoute: spec.rules.backendRef.name invalid on two matches", testcase{
 		gateway: gw,
 		objs: []any{
@@ -11439,6 +13298,163 @@ func TestGatewayAPIGRPCRouteDAGStatus(t *testing.T) {
 		// is it ok to show the listeners are attached, just it's not accepted because of the conflict
 		wantGatewayStatusUpdate: validGatewayStatusUpdate("http", gatewayapi_v1.HTTPProtocolType, 3),
 	})
+
+	gwWithHTTPS := &gatewayapi_v1.Gateway{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "contour",
+			Namespace: "projectcontour",
+		},
+		Spec: gatewayapi_v1.GatewaySpec{
+			Listeners: []gatewayapi_v1.Listener{
+				{
+					Name:     "http",
+					Port:     80,
+					Protocol: gatewayapi_v1.HTTPProtocolType,
+					AllowedRoutes: &gatewayapi_v1.AllowedRoutes{
+						Namespaces: &gatewayapi_v1.RouteNamespaces{
+							From: ptr.To(gatewayapi_v1.NamespacesFromAll),
+						},
+					},
+				},
+				{
+					Name:     "https",
+					Port:     443,
+					Protocol: gatewayapi_v1.HTTPSProtocolType,
+					TLS: &gatewayapi_v1.GatewayTLSConfig{
+						Mode: ptr.To(gatewayapi_v1.TLSModeTerminate),
+						CertificateRefs: []gatewayapi_v1.SecretObjectReference{
+							gatewayapi.CertificateRef("tlscert", ""),
+						},
+					},
+					AllowedRoutes: &gatewayapi_v1.AllowedRoutes{
+						Namespaces: &gatewayapi_v1.RouteNamespaces{
+							From: ptr.To(gatewayapi_v1.NamespacesFromAll),
+						},
+					},
+				},
+			},
+		},
+	}
+	gwWithHTTPSStatusUpdate := validGatewayStatusUpdate("https", gatewayapi_v1.HTTPSProtocolType, 1)
+	gwWithHTTPSStatusUpdate[0].ListenerStatus["http"] = validGatewayStatusUpdate("http", gatewayapi_v1.HTTPProtocolType, 0)[0].ListenerStatus["http"]
+
+	// passthroughProxy passes test.projectcontour.io through on the
+	// same port as the Gateway's HTTPS listener.
+	passthroughProxy := &contour_v1.HTTPProxy{
+		ObjectMeta: fixture.ObjectMeta("roots/passthrough"),
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "test.projectcontour.io",
+				TLS: &contour_v1.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &contour_v1.TCPProxy{
+				Services: []contour_v1.Service{{Name: "home", Port: 8080}},
+			},
+		},
+	}
+	tlsCert := &core_v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "tlscert",
+			Namespace: "projectcontour",
+		},
+		Type: core_v1.SecretTypeTLS,
+		Data: secretdata(fixture.CERTIFICATE, fixture.RSA_PRIVATE_KEY),
+	}
+	homeService := &core_v1.Service{
+		ObjectMeta: fixture.ObjectMeta("roots/home"),
+		Spec: core_v1.ServiceSpec{
+			Ports: []core_v1.ServicePort{makeServicePort("http", "TCP", 8080, 8080)},
+		},
+	}
+
+	run(t, "GRPCRoute: hostname already passed through", testcase{
+		gateway: gwWithHTTPS,
+		objs: []any{
+			kuardService,
+			homeService,
+			tlsCert,
+			passthroughProxy,
+			&gatewayapi_v1.GRPCRoute{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "basic",
+					Namespace: "default",
+				},
+				Spec: gatewayapi_v1.GRPCRouteSpec{
+					CommonRouteSpec: gatewayapi_v1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1.ParentReference{
+							gatewayapi.GatewayListenerParentRef("projectcontour", "contour", "https", 443),
+						},
+					},
+					Hostnames: []gatewayapi_v1.Hostname{"test.projectcontour.io", "terminate.projectcontour.io"},
+					Rules: []gatewayapi_v1.GRPCRouteRule{{
+						Matches: []gatewayapi_v1.GRPCRouteMatch{{
+							Method: gatewayapi.GRPCMethodMatch(gatewayapi_v1.GRPCMethodMatchExact, "foo.com.example.service", "Login"),
+						}},
+						BackendRefs: gatewayapi.GRPCRouteBackendRef("kuard", 8080, 1),
+					}},
+				},
+			},
+		},
+		wantRouteConditions: []*status.RouteStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "default", Name: "basic"},
+			RouteParentStatuses: []*gatewayapi_v1.RouteParentStatus{
+				{
+					ParentRef: gatewayapi.GatewayListenerParentRef("projectcontour", "contour", "https", 443),
+					Conditions: []meta_v1.Condition{
+						routeResolvedRefsCondition(),
+						routeAcceptedGRPCRouteCondition(),
+						routePartialMatchConflict(status.ReasonHostnameConflict, "hostname(s) test.projectcontour.io are already passed through on this listener and cannot also terminate TLS"),
+					},
+				},
+			},
+		}},
+		wantGatewayStatusUpdate: gwWithHTTPSStatusUpdate,
+	})
+
+	run(t, "GRPCRoute: all hostnames already passed through", testcase{
+		gateway: gwWithHTTPS,
+		objs: []any{
+			kuardService,
+			homeService,
+			tlsCert,
+			passthroughProxy,
+			&gatewayapi_v1.GRPCRoute{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "basic",
+					Namespace: "default",
+				},
+				Spec: gatewayapi_v1.GRPCRouteSpec{
+					CommonRouteSpec: gatewayapi_v1.CommonRouteSpec{
+						ParentRefs: []gatewayapi_v1.ParentReference{
+							gatewayapi.GatewayListenerParentRef("projectcontour", "contour", "https", 443),
+						},
+					},
+					Hostnames: []gatewayapi_v1.Hostname{"test.projectcontour.io"},
+					Rules: []gatewayapi_v1.GRPCRouteRule{{
+						Matches: []gatewayapi_v1.GRPCRouteMatch{{
+							Method: gatewayapi.GRPCMethodMatch(gatewayapi_v1.GRPCMethodMatchExact, "foo.com.example.service", "Login"),
+						}},
+						BackendRefs: gatewayapi.GRPCRouteBackendRef("kuard", 8080, 1),
+					}},
+				},
+			},
+		},
+		wantRouteConditions: []*status.RouteStatusUpdate{{
+			FullName: types.NamespacedName{Namespace: "default", Name: "basic"},
+			RouteParentStatuses: []*gatewayapi_v1.RouteParentStatus{
+				{
+					ParentRef: gatewayapi.GatewayListenerParentRef("projectcontour", "contour", "https", 443),
+					Conditions: []meta_v1.Condition{
+						routeResolvedRefsCondition(),
+						routeAcceptedFalse(status.ReasonHostnameConflict, "hostname(s) test.projectcontour.io are already passed through on this listener and cannot also terminate TLS"),
+					},
+				},
+			},
+		}},
+		wantGatewayStatusUpdate: gwWithHTTPSStatusUpdate,
+	})
 }
 
 func TestGatewayAPITCPRouteDAGStatus(t *testing.T) {
@@ -17,6 +17,7 @@ package debug
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/pprof"
 
@@ -29,6 +30,9 @@ type Service struct {
 	httpsvc.Service
 
 	Builder *dag.Builder
+
+	// Rebuilder, if set, is used to serve /debug/rebuild.
+	Rebuilder Rebuilder
 }
 
 func (svc *Service) NeedLeaderElection() bool {
@@ -40,6 +44,9 @@ func (svc *Service) NeedLeaderElection() bool {
 func (svc *Service) Start(ctx context.Context) error {
 	registerProfile(&svc.ServeMux)
 	registerDotWriter(&svc.ServeMux, svc.Builder)
+	if svc.Rebuilder != nil {
+		registerRebuild(&svc.ServeMux, svc.Rebuilder)
+	}
 	return svc.Service.Start(ctx)
 }
 
@@ -55,11 +62,26 @@ func registerProfile(mux *http.ServeMux) {
 	mux.Handle("/debug/pprof/threadcreate", pprof.Handler("threadcreate"))
 }
 
-func registerDotWriter(mux *http.ServeMux, builder *dag.Builder) {
-	mux.HandleFunc("/debug/dag", func(w http.ResponseWriter, _ *http.Request) {
-		dw := &dotWriter{
-			Builder: builder,
+// registerDotWriter serves the DAG on /debug/dag, as a dot graph by
+// default or as JSON with ?format=json. JSON output can be limited to a
+// single virtual host with ?vhost=<fqdn>.
+func registerDotWriter(mux *http.ServeMux, builder DagBuilder) {
+	mux.HandleFunc("/debug/dag", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		switch query.Get("format") {
+		case "", "dot":
+			dw := &dotWriter{
+				Builder: builder,
+			}
+			dw.writeDot(w)
+		case "json":
+			w.Header().Set("Content-Type", "application/json")
+			if err := writeJSON(w, builder, query.Get("vhost")); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+		default:
+			http.Error(w, fmt.Sprintf("unsupported format %q", query.Get("format")), http.StatusBadRequest)
 		}
-		dw.writeDot(w)
 	})
 }
@@ -0,0 +1,231 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/envoy"
+)
+
+// The types below are a stable JSON representation of the DAG,
+// suitable for diffing the configuration Contour generates.
+
+type dagJSON struct {
+	Listeners []listenerJSON `json:"listeners"`
+}
+
+type listenerJSON struct {
+	Name               string                  `json:"name"`
+	Protocol           string                  `json:"protocol"`
+	Address            string                  `json:"address"`
+	Port               int                     `json:"port"`
+	VirtualHosts       []virtualHostJSON       `json:"virtualHosts,omitempty"`
+	SecureVirtualHosts []secureVirtualHostJSON `json:"secureVirtualHosts,omitempty"`
+	TCPProxy           *tcpProxyJSON           `json:"tcpProxy,omitempty"`
+}
+
+type virtualHostJSON struct {
+	Name   string      `json:"name"`
+	Routes []routeJSON `json:"routes,omitempty"`
+}
+
+type secureVirtualHostJSON struct {
+	virtualHostJSON
+	Secret        string        `json:"secret,omitempty"`
+	MinTLSVersion string        `json:"minTLSVersion,omitempty"`
+	MaxTLSVersion string        `json:"maxTLSVersion,omitempty"`
+	TCPProxy      *tcpProxyJSON `json:"tcpProxy,omitempty"`
+}
+
+type routeJSON struct {
+	PathMatch      string        `json:"pathMatch"`
+	HeaderMatches  []string      `json:"headerMatches,omitempty"`
+	QueryMatches   []string      `json:"queryParamMatches,omitempty"`
+	HTTPSUpgrade   bool          `json:"httpsUpgrade,omitempty"`
+	Redirect       *redirectJSON `json:"redirect,omitempty"`
+	DirectResponse *uint32       `json:"directResponseStatus,omitempty"`
+	Clusters       []clusterJSON `json:"clusters,omitempty"`
+	MirrorClusters []clusterJSON `json:"mirrorClusters,omitempty"`
+	AuthDisabled   bool          `json:"authDisabled,omitempty"`
+	Timeout        string        `json:"timeout,omitempty"`
+	Websockets     bool          `json:"websockets,omitempty"`
+}
+
+type redirectJSON struct {
+	Hostname   string `json:"hostname,omitempty"`
+	Scheme     string `json:"scheme,omitempty"`
+	Port       uint32 `json:"port,omitempty"`
+	StatusCode int    `json:"statusCode,omitempty"`
+}
+
+type clusterJSON struct {
+	Name     string `json:"name"`
+	Service  string `json:"service,omitempty"`
+	Weight   uint32 `json:"weight,omitempty"`
+	Protocol string `json:"protocol,omitempty"`
+}
+
+type tcpProxyJSON struct {
+	Clusters []clusterJSON `json:"clusters,omitempty"`
+}
+
+// writeJSON writes the DAG built by b as JSON to w. If vhost is not
+// empty, only virtual hosts with that name are included.
+func writeJSON(w io.Writer, b DagBuilder, vhost string) error {
+	d := b.Build()
+
+	out := dagJSON{
+		Listeners: []listenerJSON{},
+	}
+
+	for _, listener := range d.Listeners {
+		lj := listenerJSON{
+			Name:     listener.Name,
+			Protocol: listener.Protocol,
+			Address:  listener.Address,
+			Port:     listener.Port,
+		}
+
+		for _, vh := range listener.VirtualHosts {
+			if vhost != "" && vh.Name != vhost {
+				continue
+			}
+			lj.VirtualHosts = append(lj.VirtualHosts, toVirtualHostJSON(vh))
+		}
+
+		for _, svh := range listener.SecureVirtualHosts {
+			if vhost != "" && svh.Name != vhost {
+				continue
+			}
+			sj := secureVirtualHostJSON{
+				virtualHostJSON: toVirtualHostJSON(&svh.VirtualHost),
+				MinTLSVersion:   svh.MinTLSVersion,
+				MaxTLSVersion:   svh.MaxTLSVersion,
+				TCPProxy:        toTCPProxyJSON(svh.TCPProxy),
+			}
+			if svh.Secret != nil {
+				sj.Secret = fmt.Sprintf("%s/%s", svh.Secret.Namespace(), svh.Secret.Name())
+			}
+			lj.SecureVirtualHosts = append(lj.SecureVirtualHosts, sj)
+		}
+
+		if vhost == "" {
+			lj.TCPProxy = toTCPProxyJSON(listener.TCPProxy)
+		}
+
+		out.Listeners = append(out.Listeners, lj)
+	}
+
+	sort.Slice(out.Listeners, func(i, j int) bool {
+		return out.Listeners[i].Name < out.Listeners[j].Name
+	})
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func toVirtualHostJSON(vh *dag.VirtualHost) virtualHostJSON {
+	vj := virtualHostJSON{
+		Name: vh.Name,
+	}
+
+	keys := make([]string, 0, len(vh.Routes))
+	for k := range vh.Routes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		vj.Routes = append(vj.Routes, toRouteJSON(vh.Routes[k]))
+	}
+
+	return vj
+}
+
+func toRouteJSON(route *dag.Route) routeJSON {
+	rj := routeJSON{
+		HTTPSUpgrade: route.HTTPSUpgrade,
+		AuthDisabled: route.AuthDisabled,
+		Websockets:   route.Websocket,
+		Clusters:     toClustersJSON(route.Clusters),
+	}
+
+	if route.PathMatchCondition != nil {
+		rj.PathMatch = route.PathMatchCondition.String()
+	}
+	for i := range route.HeaderMatchConditions {
+		rj.HeaderMatches = append(rj.HeaderMatches, route.HeaderMatchConditions[i].String())
+	}
+	for i := range route.QueryParamMatchConditions {
+		rj.QueryMatches = append(rj.QueryMatches, route.QueryParamMatchConditions[i].String())
+	}
+	if route.TimeoutPolicy.ResponseTimeout.IsDisabled() {
+		rj.Timeout = "infinity"
+	} else if d := route.TimeoutPolicy.ResponseTimeout.Duration(); d > 0 {
+		rj.Timeout = d.String()
+	}
+	if r := route.Redirect; r != nil {
+		rj.Redirect = &redirectJSON{
+			Hostname:   r.Hostname,
+			Scheme:     r.Scheme,
+			Port:       r.PortNumber,
+			StatusCode: r.StatusCode,
+		}
+	}
+	if dr := route.DirectResponse; dr != nil {
+		rj.DirectResponse = &dr.StatusCode
+	}
+	for _, mp := range route.MirrorPolicies {
+		if mp.Cluster != nil {
+			rj.MirrorClusters = append(rj.MirrorClusters, toClusterJSON(mp.Cluster))
+		}
+	}
+
+	return rj
+}
+
+func toTCPProxyJSON(proxy *dag.TCPProxy) *tcpProxyJSON {
+	if proxy == nil {
+		return nil
+	}
+	return &tcpProxyJSON{
+		Clusters: toClustersJSON(proxy.Clusters),
+	}
+}
+
+func toClustersJSON(clusters []*dag.Cluster) []clusterJSON {
+	var out []clusterJSON
+	for _, c := range clusters {
+		out = append(out, toClusterJSON(c))
+	}
+	return out
+}
+
+func toClusterJSON(c *dag.Cluster) clusterJSON {
+	cj := clusterJSON{
+		Name:     envoy.Clustername(c),
+		Weight:   c.Weight,
+		Protocol: c.Protocol,
+	}
+	if s := c.Upstream; s != nil {
+		cj.Service = fmt.Sprintf("%s/%s:%d", s.Weighted.ServiceNamespace, s.Weighted.ServiceName, s.Weighted.ServicePort.Port)
+	}
+	return cj
+}
@@ -0,0 +1,108 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/fixture"
+)
+
+func TestWriteJSON(t *testing.T) {
+	builder := &dag.Builder{
+		Source: dag.KubernetesCache{
+			FieldLogger: fixture.NewTestLogger(t),
+		},
+		Processors: []dag.Processor{
+			&dag.ListenerProcessor{},
+			&dag.HTTPProxyProcessor{},
+		},
+	}
+	builder.Source.Insert(fixture.NewService("kuard").
+		WithPorts(core_v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)}))
+	for _, fqdn := range []string{"a.example.com", "b.example.com"} {
+		builder.Source.Insert(fixture.NewProxy(fqdn).WithSpec(contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: fqdn,
+			},
+			Routes: []contour_v1.Route{{
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/api",
+				}},
+				Services: []contour_v1.Service{{
+					Name: "kuard",
+					Port: 80,
+				}},
+			}},
+		}))
+	}
+
+	mux := http.NewServeMux()
+	registerDotWriter(mux, builder)
+
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	rec := get("/debug/dag?format=json&vhost=a.example.com")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var got dagJSON
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, dagJSON{
+		Listeners: []listenerJSON{{
+			Name:     dag.HTTP_LISTENER_NAME,
+			Protocol: "http",
+			Port:     8080,
+			VirtualHosts: []virtualHostJSON{{
+				Name: "a.example.com",
+				Routes: []routeJSON{{
+					PathMatch: "prefix: /api type: string",
+					Clusters: []clusterJSON{{
+						Name:    "default/kuard/80/da39a3ee5e",
+						Service: "default/kuard:80",
+					}},
+				}},
+			}},
+		}},
+	}, got)
+
+	// Without a vhost filter, every virtual host is included.
+	rec = get("/debug/dag?format=json")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	require.Len(t, got.Listeners, 1)
+	assert.Len(t, got.Listeners[0].VirtualHosts, 2)
+
+	// The dot graph remains the default.
+	rec = get("/debug/dag")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "digraph DAG")
+
+	rec = get("/debug/dag?format=yaml")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,62 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// Rebuilder forces a DAG rebuild.
+type Rebuilder interface {
+	// Rebuild rebuilds the DAG and returns the version of the
+	// resulting xDS snapshot.
+	Rebuild(ctx context.Context) (string, error)
+}
+
+// RebuilderFunc adapts a function to the Rebuilder interface.
+type RebuilderFunc func(ctx context.Context) (string, error)
+
+// Rebuild calls f(ctx).
+func (f RebuilderFunc) Rebuild(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+type rebuildJSON struct {
+	Version string `json:"version"`
+}
+
+// registerRebuild serves /debug/rebuild, which rebuilds the DAG on POST
+// and responds with the version of the resulting xDS snapshot.
+func registerRebuild(mux *http.ServeMux, rebuilder Rebuilder) {
+	mux.HandleFunc("/debug/rebuild", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		version, err := rebuilder.Rebuild(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(rebuildJSON{Version: version}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
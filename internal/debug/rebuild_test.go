@@ -0,0 +1,117 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectcontour/contour/internal/contour"
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/k8s"
+	"github.com/projectcontour/contour/internal/metrics"
+)
+
+func TestRebuild(t *testing.T) {
+	run := func(t *testing.T, synced bool) (*http.ServeMux, *prometheus.Registry) {
+		t.Helper()
+
+		registry := prometheus.NewRegistry()
+		contourMetrics := metrics.NewMetrics(registry)
+		eh := contour.NewEventHandler(contour.EventHandlerConfig{
+			Logger: fixture.NewTestLogger(t),
+			Builder: &dag.Builder{
+				Source: dag.KubernetesCache{
+					FieldLogger: fixture.NewTestLogger(t),
+				},
+			},
+			Observer:        contour.NewRebuildMetricsObserver(contourMetrics, nil, dag.ObserverFunc(func(*dag.DAG) {})),
+			HoldoffDelay:    time.Hour,
+			HoldoffMaxDelay: time.Hour,
+			StatusUpdater:   &k8s.StatusUpdateCacher{},
+		}, func() bool { return synced })
+
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		go func() {
+			_ = eh.Start(ctx)
+		}()
+
+		mux := http.NewServeMux()
+		registerRebuild(mux, RebuilderFunc(func(ctx context.Context) (string, error) {
+			if err := eh.Rebuild(ctx); err != nil {
+				return "", err
+			}
+			return "version-1", nil
+		}))
+
+		return mux, registry
+	}
+
+	rebuilds := func(t *testing.T, registry *prometheus.Registry) float64 {
+		t.Helper()
+
+		families, err := registry.Gather()
+		require.NoError(t, err)
+		for _, mf := range families {
+			if mf.GetName() == metrics.DAGRebuildTotal {
+				return mf.Metric[0].GetCounter().GetValue()
+			}
+		}
+		return 0
+	}
+
+	t.Run("rebuilds the dag", func(t *testing.T) {
+		mux, registry := run(t, true)
+
+		for i := 1; i <= 2; i++ {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/rebuild", nil))
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			var got rebuildJSON
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, "version-1", got.Version)
+			assert.Equal(t, float64(i), rebuilds(t, registry))
+		}
+	})
+
+	t.Run("requires POST", func(t *testing.T) {
+		mux, registry := run(t, true)
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/rebuild", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+		assert.Zero(t, rebuilds(t, registry))
+	})
+
+	t.Run("waits for the caches to sync", func(t *testing.T) {
+		mux, registry := run(t, false)
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/rebuild", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Contains(t, rec.Body.String(), contour.ErrCacheNotSynced.Error())
+		assert.Zero(t, rebuilds(t, registry))
+	})
+}
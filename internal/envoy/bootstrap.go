@@ -19,6 +19,8 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
@@ -59,6 +61,10 @@ type BootstrapConfig struct {
 	// Defaults to 8001.
 	XDSGRPCPort int
 
+	// XDSDelta makes Envoy fetch listeners, clusters and runtime
+	// with the incremental (delta) xDS protocol.
+	XDSDelta bool
+
 	// XDSResourceVersion defines the XDS Server Version to use.
 	// Defaults to "v3"
 	XDSResourceVersion config.ResourceVersion
@@ -95,6 +101,22 @@ type BootstrapConfig struct {
 	// MaximumHeapSizeBytes specifies the number of bytes that overload manager allows heap to grow to.
 	// When reaching the set threshold, new connections are denied.
 	MaximumHeapSizeBytes uint64
+
+	// OverloadShrinkHeapThreshold is the fraction of MaximumHeapSizeBytes at which
+	// the overload manager asks Envoy to release free memory to the system.
+	// Defaults to 0.95.
+	OverloadShrinkHeapThreshold float64
+
+	// OverloadStopAcceptingRequestsThreshold is the fraction of MaximumHeapSizeBytes
+	// at which the overload manager makes Envoy stop accepting new requests.
+	// Defaults to 0.98.
+	OverloadStopAcceptingRequestsThreshold float64
+
+	// OverloadCgroupMemoryThreshold is the fraction of the container's cgroup
+	// memory limit at which the overload manager makes Envoy stop accepting
+	// new requests. Zero disables the cgroup memory monitor, which requires
+	// Envoy 1.35 or later.
+	OverloadCgroupMemoryThreshold float64
 }
 
 // GetXdsAddress returns the address configured or defaults to "127.0.0.1"
@@ -114,6 +136,17 @@ func (c *BootstrapConfig) GetAdminAccessLogPath() string {
 	return stringOrDefault(c.AdminAccessLogPath, "/dev/null")
 }
 
+// GetOverloadShrinkHeapThreshold returns the configured shrink heap threshold or defaults to 0.95
+func (c *BootstrapConfig) GetOverloadShrinkHeapThreshold() float64 {
+	return floatOrDefault(c.OverloadShrinkHeapThreshold, 0.95)
+}
+
+// GetOverloadStopAcceptingRequestsThreshold returns the configured stop accepting requests
+// threshold or defaults to 0.98
+func (c *BootstrapConfig) GetOverloadStopAcceptingRequestsThreshold() float64 {
+	return floatOrDefault(c.OverloadStopAcceptingRequestsThreshold, 0.98)
+}
+
 // GetDNSLookupFamily returns the configured dns lookup family or defaults to "auto"
 func (c *BootstrapConfig) GetDNSLookupFamily() string {
 	return stringOrDefault(c.DNSLookupFamily, "auto")
@@ -130,6 +163,49 @@ func ValidAdminAddress(address string) error {
 	return nil
 }
 
+// ValidAdminAccessLogPath checks if the path supplied looks like
+// a file Envoy can write the admin access log to. An empty path
+// selects the default of /dev/null.
+func ValidAdminAccessLogPath(path string) error {
+	switch {
+	case path == "":
+		return nil
+	case !filepath.IsAbs(path):
+		return fmt.Errorf("invalid value %q, must be an absolute path", path)
+	case strings.HasSuffix(path, "/"):
+		return fmt.Errorf("invalid value %q, must be a file, not a directory", path)
+	case strings.ContainsRune(path, 0):
+		return fmt.Errorf("invalid value %q, must not contain NUL characters", path)
+	}
+	return nil
+}
+
+// ValidOverloadThresholds checks if the overload manager thresholds
+// supplied are fractions in the range (0, 1], and that the heap is
+// shrunk no later than new requests are refused.
+func ValidOverloadThresholds(shrinkHeap, stopAcceptingRequests float64) error {
+	for _, v := range []float64{shrinkHeap, stopAcceptingRequests} {
+		if v <= 0 || v > 1 {
+			return fmt.Errorf("invalid threshold %v, must be greater than 0 and at most 1", v)
+		}
+	}
+	if shrinkHeap > stopAcceptingRequests {
+		return fmt.Errorf("invalid shrink heap threshold %v, must not be greater than the stop accepting requests threshold %v",
+			shrinkHeap, stopAcceptingRequests)
+	}
+	return nil
+}
+
+// ValidOverloadCgroupMemoryThreshold checks if the cgroup memory
+// threshold supplied is zero, which disables the monitor, or a
+// fraction in the range (0, 1].
+func ValidOverloadCgroupMemoryThreshold(threshold float64) error {
+	if threshold < 0 || threshold > 1 {
+		return fmt.Errorf("invalid threshold %v, must be greater than 0 and at most 1, or 0 to disable", threshold)
+	}
+	return nil
+}
+
 func stringOrDefault(s, def string) string {
 	if s == "" {
 		return def
@@ -144,6 +220,13 @@ func intOrDefault(i, def int) int {
 	return i
 }
 
+func floatOrDefault(f, def float64) float64 {
+	if f == 0 {
+		return def
+	}
+	return f
+}
+
 func WriteConfig(filename string, config proto.Message) (err error) {
 	var out *os.File
 
@@ -39,3 +39,68 @@ func TestValidAdminAddress(t *testing.T) {
 		})
 	}
 }
+
+func TestValidAdminAccessLogPath(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want error
+	}{
+		{name: "default", path: "", want: nil},
+		{name: "dev null", path: "/dev/null", want: nil},
+		{name: "log file", path: "/var/log/envoy/admin.log", want: nil},
+		{name: "relative path invalid", path: "admin.log", want: fmt.Errorf("invalid value %q, must be an absolute path", "admin.log")},
+		{name: "directory invalid", path: "/var/log/", want: fmt.Errorf("invalid value %q, must be a file, not a directory", "/var/log/")},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ValidAdminAccessLogPath(tc.path)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestValidOverloadThresholds(t *testing.T) {
+	tests := []struct {
+		name                  string
+		shrinkHeap            float64
+		stopAcceptingRequests float64
+		want                  error
+	}{
+		{name: "defaults", shrinkHeap: 0.95, stopAcceptingRequests: 0.98, want: nil},
+		{name: "equal thresholds", shrinkHeap: 0.9, stopAcceptingRequests: 0.9, want: nil},
+		{name: "full heap", shrinkHeap: 0.9, stopAcceptingRequests: 1, want: nil},
+		{name: "zero threshold invalid", shrinkHeap: 0, stopAcceptingRequests: 0.98, want: fmt.Errorf("invalid threshold %v, must be greater than 0 and at most 1", 0.0)},
+		{name: "threshold above one invalid", shrinkHeap: 0.95, stopAcceptingRequests: 1.5, want: fmt.Errorf("invalid threshold %v, must be greater than 0 and at most 1", 1.5)},
+		{name: "shrink after stop invalid", shrinkHeap: 0.99, stopAcceptingRequests: 0.9, want: fmt.Errorf("invalid shrink heap threshold %v, must not be greater than the stop accepting requests threshold %v", 0.99, 0.9)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ValidOverloadThresholds(tc.shrinkHeap, tc.stopAcceptingRequests)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestValidOverloadCgroupMemoryThreshold(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold float64
+		want      error
+	}{
+		{name: "disabled", threshold: 0, want: nil},
+		{name: "fraction", threshold: 0.9, want: nil},
+		{name: "full limit", threshold: 1, want: nil},
+		{name: "negative invalid", threshold: -0.5, want: fmt.Errorf("invalid threshold %v, must be greater than 0 and at most 1, or 0 to disable", -0.5)},
+		{name: "above one invalid", threshold: 1.5, want: fmt.Errorf("invalid threshold %v, must be greater than 0 and at most 1, or 0 to disable", 1.5)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ValidOverloadCgroupMemoryThreshold(tc.threshold)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
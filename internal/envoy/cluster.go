@@ -53,14 +53,36 @@ func Clustername(cluster *dag.Cluster) string {
 		if len(uv.SubjectNames) > 0 {
 			buf += uv.SubjectNames[0]
 		}
+		if len(uv.VerifyCertificateSpki) > 0 {
+			buf += strings.Join(uv.VerifyCertificateSpki, ",")
+		}
+		if len(uv.VerifyCertificateHash) > 0 {
+			buf += strings.Join(uv.VerifyCertificateHash, ",")
+		}
 	}
 	buf += cluster.Protocol + cluster.SNI
+	if len(cluster.ALPNProtocols) > 0 {
+		buf += strings.Join(cluster.ALPNProtocols, ",")
+	}
+	buf += cluster.UpstreamProxyProtocol
 	if !cluster.TimeoutPolicy.IdleConnectionTimeout.UseDefault() {
 		buf += cluster.TimeoutPolicy.IdleConnectionTimeout.Duration().String()
 	}
 	if cluster.SlowStartConfig != nil {
 		buf += cluster.SlowStartConfig.String()
 	}
+	if cluster.LeastRequestConfig != nil {
+		buf += cluster.LeastRequestConfig.String()
+	}
+	if cluster.RingHashConfig != nil {
+		buf += cluster.RingHashConfig.String()
+	}
+	if cluster.MaglevConfig != nil {
+		buf += cluster.MaglevConfig.String()
+	}
+	if cluster.HTTP2Config != nil {
+		buf += cluster.HTTP2Config.String()
+	}
 
 	// This isn't a crypto hash, we just want a unique name.
 	hash := sha1.Sum([]byte(buf)) // nolint:gosec
@@ -70,6 +92,21 @@ func Clustername(cluster *dag.Cluster) string {
 	return Hashname(60, ns, name, strconv.Itoa(int(service.Weighted.ServicePort.Port)), fmt.Sprintf("%x", hash[:5]))
 }
 
+// FailoverClustername returns the name of the aggregate cluster that
+// fails over between the clusters of the given TCPProxy, in order.
+func FailoverClustername(proxy *dag.TCPProxy) string {
+	names := make([]string, 0, len(proxy.Clusters))
+	for _, c := range proxy.Clusters {
+		names = append(names, Clustername(c))
+	}
+
+	// This isn't a crypto hash, we just want a unique name.
+	hash := sha1.Sum([]byte(strings.Join(names, ","))) // nolint:gosec
+
+	primary := proxy.Clusters[0].Upstream.Weighted
+	return Hashname(60, "failover", primary.ServiceNamespace, primary.ServiceName, fmt.Sprintf("%x", hash[:5]))
+}
+
 // AltStatName generates an alternative stat name for the service
 // using format ns_name_port
 func AltStatName(service *dag.Service) string {
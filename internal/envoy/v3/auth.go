@@ -51,7 +51,7 @@ func UpstreamTLSContext(peerValidationContext *dag.PeerValidationContext, sni st
 		}
 	}
 
-	if peerValidationContext.GetCACertificate() != nil && len(peerValidationContext.GetSubjectNames()) > 0 {
+	if (peerValidationContext.GetCACertificate() != nil || peerValidationContext.HasCertificatePins()) && len(peerValidationContext.GetSubjectNames()) > 0 {
 		// We have to explicitly assign the value from validationContext
 		// to context.CommonTlsContext.ValidationContextType because the
 		// latter is an interface. Returning nil from validationContext
@@ -60,6 +60,8 @@ func UpstreamTLSContext(peerValidationContext *dag.PeerValidationContext, sni st
 		// to explode later on.
 		vc := validationContext(peerValidationContext.GetCACertificate(), peerValidationContext.GetSubjectNames(), false, nil, false)
 		if vc != nil {
+			vc.ValidationContext.VerifyCertificateSpki = peerValidationContext.VerifyCertificateSpki
+			vc.ValidationContext.VerifyCertificateHash = peerValidationContext.VerifyCertificateHash
 			// TODO: update this for SDS (CommonTlsContext_ValidationContextSdsSecretConfig) instead of inlining it.
 			context.CommonTlsContext.ValidationContextType = vc
 		}
@@ -141,3 +143,13 @@ func DownstreamTLSContext(serverSecret *dag.Secret, tlsMinProtoVersion, tlsMaxPr
 
 	return context
 }
+
+// DisableSessionTickets stops Envoy issuing TLS session tickets on the
+// supplied DownstreamTlsContext, so clients cannot resume a session
+// without a full handshake. Stateful session resumption is unaffected.
+func DisableSessionTickets(context *envoy_transport_socket_tls_v3.DownstreamTlsContext) *envoy_transport_socket_tls_v3.DownstreamTlsContext {
+	context.SessionTicketKeysType = &envoy_transport_socket_tls_v3.DownstreamTlsContext_DisableStatelessSessionResumption{
+		DisableStatelessSessionResumption: true,
+	}
+	return context
+}
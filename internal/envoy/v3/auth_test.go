@@ -107,6 +107,33 @@ func TestUpstreamTLSContext(t *testing.T) {
 				},
 			},
 		},
+		"no alpn, certificate pins without ca": {
+			validation: &dag.PeerValidationContext{
+				SubjectNames:          []string{"www.example.com"},
+				VerifyCertificateSpki: []string{"b+7MjBbFVR2f6z61934tp3O/aL2e+cUpJ86yyG5WiSs="},
+				VerifyCertificateHash: []string{"06298432e8066b29e2223bcc23aa9504b56ae508fabf3435508869b9c3190e22"},
+			},
+			want: &envoy_transport_socket_tls_v3.UpstreamTlsContext{
+				CommonTlsContext: &envoy_transport_socket_tls_v3.CommonTlsContext{
+					ValidationContextType: &envoy_transport_socket_tls_v3.CommonTlsContext_ValidationContext{
+						ValidationContext: &envoy_transport_socket_tls_v3.CertificateValidationContext{
+							MatchTypedSubjectAltNames: []*envoy_transport_socket_tls_v3.SubjectAltNameMatcher{
+								{
+									SanType: envoy_transport_socket_tls_v3.SubjectAltNameMatcher_DNS,
+									Matcher: &envoy_matcher_v3.StringMatcher{
+										MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{
+											Exact: "www.example.com",
+										},
+									},
+								},
+							},
+							VerifyCertificateSpki: []string{"b+7MjBbFVR2f6z61934tp3O/aL2e+cUpJ86yyG5WiSs="},
+							VerifyCertificateHash: []string{"06298432e8066b29e2223bcc23aa9504b56ae508fabf3435508869b9c3190e22"},
+						},
+					},
+				},
+			},
+		},
 		"external name sni": {
 			externalName: "projectcontour.local",
 			want: &envoy_transport_socket_tls_v3.UpstreamTlsContext{
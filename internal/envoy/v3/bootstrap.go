@@ -32,6 +32,7 @@ import (
 	envoy_config_overload_v3 "github.com/envoyproxy/go-control-plane/envoy/config/overload/v3"
 	envoy_access_logger_file_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/file/v3"
 	envoy_regex_engines_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/regex_engines/v3"
+	envoy_cgroup_memory_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/resource_monitors/cgroup_memory/v3"
 	envoy_fixed_heap_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/resource_monitors/fixed_heap/v3"
 	envoy_transport_socket_tls_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
@@ -41,6 +42,7 @@ import (
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 
+	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 	"github.com/projectcontour/contour/internal/envoy"
 	"github.com/projectcontour/contour/internal/protobuf"
 	"github.com/projectcontour/contour/internal/timeout"
@@ -161,6 +163,19 @@ func bootstrap(c *envoy.BootstrapConfig) ([]bootstrapf, error) {
 }
 
 func bootstrapConfig(c *envoy.BootstrapConfig) *envoy_config_bootstrap_v3.Bootstrap {
+	configSource := ConfigSource
+	if c.XDSDelta {
+		configSource = DeltaConfigSource
+	}
+
+	// Contour may serve xDS on a Unix domain socket shared with Envoy.
+	xdsClusterType := ClusterDiscoveryTypeForAddress(c.GetXdsAddress(), envoy_config_cluster_v3.Cluster_STRICT_DNS)
+	xdsAddress := SocketAddress(c.GetXdsAddress(), c.GetXdsGRPCPort())
+	if path, ok := strings.CutPrefix(c.GetXdsAddress(), contour_v1alpha1.XDSUnixSocketPrefix); ok {
+		xdsClusterType = &envoy_config_cluster_v3.Cluster_Type{Type: envoy_config_cluster_v3.Cluster_STATIC}
+		xdsAddress = UnixSocketAddress(path)
+	}
+
 	bootstrap := &envoy_config_bootstrap_v3.Bootstrap{
 		LayeredRuntime: &envoy_config_bootstrap_v3.LayeredRuntime{
 			Layers: []*envoy_config_bootstrap_v3.RuntimeLayer{
@@ -169,7 +184,7 @@ func bootstrapConfig(c *envoy.BootstrapConfig) *envoy_config_bootstrap_v3.Bootst
 					LayerSpecifier: &envoy_config_bootstrap_v3.RuntimeLayer_RtdsLayer_{
 						RtdsLayer: &envoy_config_bootstrap_v3.RuntimeLayer_RtdsLayer{
 							Name:       DynamicRuntimeLayerName,
-							RtdsConfig: ConfigSource("contour"),
+							RtdsConfig: configSource("contour"),
 						},
 					},
 				},
@@ -187,8 +202,8 @@ func bootstrapConfig(c *envoy.BootstrapConfig) *envoy_config_bootstrap_v3.Bootst
 			},
 		},
 		DynamicResources: &envoy_config_bootstrap_v3.Bootstrap_DynamicResources{
-			LdsConfig: ConfigSource("contour"),
-			CdsConfig: ConfigSource("contour"),
+			LdsConfig: configSource("contour"),
+			CdsConfig: configSource("contour"),
 		},
 		StaticResources: &envoy_config_bootstrap_v3.Bootstrap_StaticResources{
 			Clusters: []*envoy_config_cluster_v3.Cluster{{
@@ -196,12 +211,12 @@ func bootstrapConfig(c *envoy.BootstrapConfig) *envoy_config_bootstrap_v3.Bootst
 				Name:                 "contour",
 				AltStatName:          strings.Join([]string{c.Namespace, "contour", strconv.Itoa(c.GetXdsGRPCPort())}, "_"),
 				ConnectTimeout:       durationpb.New(5 * time.Second),
-				ClusterDiscoveryType: ClusterDiscoveryTypeForAddress(c.GetXdsAddress(), envoy_config_cluster_v3.Cluster_STRICT_DNS),
+				ClusterDiscoveryType: xdsClusterType,
 				LbPolicy:             envoy_config_cluster_v3.Cluster_ROUND_ROBIN,
 				LoadAssignment: &envoy_config_endpoint_v3.ClusterLoadAssignment{
 					ClusterName: "contour",
 					Endpoints: Endpoints(
-						SocketAddress(c.GetXdsAddress(), c.GetXdsGRPCPort()),
+						xdsAddress,
 					),
 				},
 				UpstreamConnectionOptions: &envoy_config_cluster_v3.UpstreamConnectionOptions{
@@ -211,7 +226,7 @@ func bootstrapConfig(c *envoy.BootstrapConfig) *envoy_config_bootstrap_v3.Bootst
 						KeepaliveInterval: wrapperspb.UInt32(5),
 					},
 				},
-				TypedExtensionProtocolOptions: protocolOptions(HTTPVersion2, timeout.DefaultSetting(), nil),
+				TypedExtensionProtocolOptions: protocolOptions(HTTPVersion2, timeout.DefaultSetting(), nil, nil),
 				CircuitBreakers: &envoy_config_cluster_v3.CircuitBreakers{
 					Thresholds: []*envoy_config_cluster_v3.CircuitBreakers_Thresholds{{
 						Priority:           envoy_config_core_v3.RoutingPriority_HIGH,
@@ -252,51 +267,73 @@ func bootstrapConfig(c *envoy.BootstrapConfig) *envoy_config_bootstrap_v3.Bootst
 			Address:   UnixSocketAddress(c.GetAdminAddress()),
 		},
 	}
+	bootstrap.OverloadManager = overloadManager(c)
+	return bootstrap
+}
+
+// overloadManager returns the overload manager configuration for the
+// enabled resource monitors, or nil if no monitor is enabled.
+func overloadManager(c *envoy.BootstrapConfig) *envoy_config_overload_v3.OverloadManager {
+	var monitors []*envoy_config_overload_v3.ResourceMonitor
+	var shrinkHeap, stopAcceptingRequests []*envoy_config_overload_v3.Trigger
+
 	if c.MaximumHeapSizeBytes > 0 {
-		bootstrap.OverloadManager = &envoy_config_overload_v3.OverloadManager{
-			RefreshInterval: durationpb.New(250 * time.Millisecond),
-			ResourceMonitors: []*envoy_config_overload_v3.ResourceMonitor{
-				{
-					Name: "envoy.resource_monitors.fixed_heap",
-					ConfigType: &envoy_config_overload_v3.ResourceMonitor_TypedConfig{
-						TypedConfig: protobuf.MustMarshalAny(
-							&envoy_fixed_heap_v3.FixedHeapConfig{
-								MaxHeapSizeBytes: c.MaximumHeapSizeBytes,
-							}),
-					},
-				},
+		monitors = append(monitors, &envoy_config_overload_v3.ResourceMonitor{
+			Name: "envoy.resource_monitors.fixed_heap",
+			ConfigType: &envoy_config_overload_v3.ResourceMonitor_TypedConfig{
+				TypedConfig: protobuf.MustMarshalAny(
+					&envoy_fixed_heap_v3.FixedHeapConfig{
+						MaxHeapSizeBytes: c.MaximumHeapSizeBytes,
+					}),
 			},
-			Actions: []*envoy_config_overload_v3.OverloadAction{
-				{
-					Name: "envoy.overload_actions.shrink_heap",
-					Triggers: []*envoy_config_overload_v3.Trigger{
-						{
-							Name: "envoy.resource_monitors.fixed_heap",
-							TriggerOneof: &envoy_config_overload_v3.Trigger_Threshold{
-								Threshold: &envoy_config_overload_v3.ThresholdTrigger{
-									Value: 0.95,
-								},
-							},
-						},
-					},
-				},
-				{
-					Name: "envoy.overload_actions.stop_accepting_requests",
-					Triggers: []*envoy_config_overload_v3.Trigger{
-						{
-							Name: "envoy.resource_monitors.fixed_heap",
-							TriggerOneof: &envoy_config_overload_v3.Trigger_Threshold{
-								Threshold: &envoy_config_overload_v3.ThresholdTrigger{
-									Value: 0.98,
-								},
-							},
-						},
-					},
-				},
+		})
+		shrinkHeap = append(shrinkHeap, thresholdTrigger("envoy.resource_monitors.fixed_heap", c.GetOverloadShrinkHeapThreshold()))
+		stopAcceptingRequests = append(stopAcceptingRequests, thresholdTrigger("envoy.resource_monitors.fixed_heap", c.GetOverloadStopAcceptingRequestsThreshold()))
+	}
+
+	if c.OverloadCgroupMemoryThreshold > 0 {
+		// Without max_memory_bytes the monitor uses the cgroup's memory limit.
+		monitors = append(monitors, &envoy_config_overload_v3.ResourceMonitor{
+			Name: "envoy.resource_monitors.cgroup_memory",
+			ConfigType: &envoy_config_overload_v3.ResourceMonitor_TypedConfig{
+				TypedConfig: protobuf.MustMarshalAny(&envoy_cgroup_memory_v3.CgroupMemoryConfig{}),
 			},
-		}
+		})
+		stopAcceptingRequests = append(stopAcceptingRequests, thresholdTrigger("envoy.resource_monitors.cgroup_memory", c.OverloadCgroupMemoryThreshold))
+	}
+
+	if len(monitors) == 0 {
+		return nil
+	}
+
+	overloadManager := &envoy_config_overload_v3.OverloadManager{
+		RefreshInterval:  durationpb.New(250 * time.Millisecond),
+		ResourceMonitors: monitors,
+	}
+	if len(shrinkHeap) > 0 {
+		overloadManager.Actions = append(overloadManager.Actions, &envoy_config_overload_v3.OverloadAction{
+			Name:     "envoy.overload_actions.shrink_heap",
+			Triggers: shrinkHeap,
+		})
+	}
+	overloadManager.Actions = append(overloadManager.Actions, &envoy_config_overload_v3.OverloadAction{
+		Name:     "envoy.overload_actions.stop_accepting_requests",
+		Triggers: stopAcceptingRequests,
+	})
+	return overloadManager
+}
+
+// thresholdTrigger returns a trigger that saturates when the named
+// resource monitor reports a pressure of at least value.
+func thresholdTrigger(monitor string, value float64) *envoy_config_overload_v3.Trigger {
+	return &envoy_config_overload_v3.Trigger{
+		Name: monitor,
+		TriggerOneof: &envoy_config_overload_v3.Trigger_Threshold{
+			Threshold: &envoy_config_overload_v3.ThresholdTrigger{
+				Value: value,
+			},
+		},
 	}
-	return bootstrap
 }
 
 func adminAccessLog(logPath string) []*envoy_config_accesslog_v3.AccessLog {
@@ -18,8 +18,11 @@ import (
 	"testing"
 
 	envoy_config_bootstrap_v3 "github.com/envoyproxy/go-control-plane/envoy/config/bootstrap/v3"
+	envoy_config_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 
@@ -396,7 +399,7 @@ func TestBootstrap(t *testing.T) {
   }
 }`,
 		},
-		"AdminAccessLogPath": { // TODO(dfc) doesn't appear to be exposed via contour bootstrap
+		"AdminAccessLogPath": {
 			config: envoy.BootstrapConfig{
 				Path:               "envoy.json",
 				AdminAccessLogPath: "/var/log/admin.log",
@@ -2003,6 +2006,211 @@ func TestBootstrap(t *testing.T) {
             }
           ]
         }
+      }`,
+		},
+		"Enable cgroup memory overload monitor by specifying --overload-cgroup-memory-threshold=0.9": {
+			config: envoy.BootstrapConfig{
+				Path:                          "envoy.json",
+				Namespace:                     "projectcontour",
+				OverloadCgroupMemoryThreshold: 0.9,
+			},
+			wantedBootstrapConfig: `{
+        "static_resources": {
+          "clusters": [
+            {
+              "name": "contour",
+              "alt_stat_name": "projectcontour_contour_8001",
+              "type": "STATIC",
+              "connect_timeout": "5s",
+              "load_assignment": {
+                "cluster_name": "contour",
+                "endpoints": [
+                  {
+                    "lb_endpoints": [
+                      {
+                        "endpoint": {
+                          "address": {
+                            "socket_address": {
+                              "address": "127.0.0.1",
+                              "port_value": 8001
+                            }
+                          }
+                        }
+                      }
+                    ]
+                  }
+                ]
+              },
+              "circuit_breakers": {
+                "thresholds": [
+                  {
+                    "priority": "HIGH",
+                    "max_connections": 100000,
+                    "max_pending_requests": 100000,
+                    "max_requests": 60000000,
+                    "max_retries": 50,
+                    "track_remaining": true
+                  },
+                  {
+                    "max_connections": 100000,
+                    "max_pending_requests": 100000,
+                    "max_requests": 60000000,
+                    "max_retries": 50,
+                    "track_remaining": true
+                  }
+                ]
+              },
+              "typed_extension_protocol_options": {
+                "envoy.extensions.upstreams.http.v3.HttpProtocolOptions": {
+                  "@type": "type.googleapis.com/envoy.extensions.upstreams.http.v3.HttpProtocolOptions",
+                  "explicit_http_config": {
+                    "http2_protocol_options": {}
+                  }
+                }
+              },
+              "upstream_connection_options": {
+                "tcp_keepalive": {
+                  "keepalive_probes": 3,
+                  "keepalive_time": 30,
+                  "keepalive_interval": 5
+                }
+              }
+            },
+            {
+              "name": "envoy-admin",
+              "alt_stat_name": "projectcontour_envoy-admin_9001",
+              "type": "STATIC",
+              "connect_timeout": "0.250s",
+              "load_assignment": {
+                "cluster_name": "envoy-admin",
+                "endpoints": [
+                  {
+                    "lb_endpoints": [
+                      {
+                        "endpoint": {
+                          "address": {
+                            "pipe": {
+                              "path": "/admin/admin.sock",
+                              "mode": 420
+                            }
+                          }
+                        }
+                      }
+                    ]
+                  }
+                ]
+              }
+            }
+          ]
+        },
+        "default_regex_engine": {
+          "name": "envoy.regex_engines.google_re2",
+          "typed_config": {
+            "@type": "type.googleapis.com/envoy.extensions.regex_engines.v3.GoogleRE2"
+          }
+        },
+        "dynamic_resources": {
+          "lds_config": {
+            "api_config_source": {
+              "api_type": "GRPC",
+              "transport_api_version": "V3",
+              "grpc_services": [
+                {
+                  "envoy_grpc": {
+                    "cluster_name": "contour",
+                    "authority": "contour"
+                  }
+                }
+              ]
+            },
+            "resource_api_version": "V3"
+          },
+          "cds_config": {
+            "api_config_source": {
+              "api_type": "GRPC",
+              "transport_api_version": "V3",
+              "grpc_services": [
+                {
+                  "envoy_grpc": {
+                    "cluster_name": "contour",
+                    "authority": "contour"
+                  }
+                }
+              ]
+            },
+            "resource_api_version": "V3"
+          }
+        },
+        "layered_runtime": {
+          "layers": [
+            {
+              "name": "dynamic",
+              "rtds_layer": {
+                "name": "dynamic",
+                "rtds_config": {
+                  "api_config_source": {
+                    "api_type": "GRPC",
+                    "transport_api_version": "V3",
+                    "grpc_services": [
+                      {
+                        "envoy_grpc": {
+                          "cluster_name": "contour",
+                          "authority": "contour"
+                        }
+                      }
+                    ]
+                  },
+                  "resource_api_version": "V3"
+                }
+              }
+            },
+            {
+              "name": "admin",
+              "admin_layer": {}
+            }
+          ]
+        },
+        "admin": {
+          "access_log": [
+            {
+              "name": "envoy.access_loggers.file",
+              "typed_config": {
+                "@type": "type.googleapis.com/envoy.extensions.access_loggers.file.v3.FileAccessLog",
+                "path": "/dev/null"
+              }
+            }
+          ],
+          "address": {
+            "pipe": {
+              "path": "/admin/admin.sock",
+              "mode": 420
+            }
+          }
+        },
+        "overload_manager": {
+          "refresh_interval": "0.250s",
+          "resource_monitors": [
+            {
+              "name": "envoy.resource_monitors.cgroup_memory",
+              "typed_config": {
+                "@type": "type.googleapis.com/envoy.extensions.resource_monitors.cgroup_memory.v3.CgroupMemoryConfig"
+              }
+            }
+          ],
+          "actions": [
+            {
+              "name": "envoy.overload_actions.stop_accepting_requests",
+              "triggers": [
+                {
+                  "name": "envoy.resource_monitors.cgroup_memory",
+                  "threshold": {
+                    "value": 0.9
+                  }
+                }
+              ]
+            }
+          ]
+        }
       }`,
 		},
 	}
@@ -2050,6 +2258,100 @@ func TestBootstrap(t *testing.T) {
 	}
 }
 
+func TestBootstrapOverloadManagerThresholds(t *testing.T) {
+	tests := map[string]struct {
+		config envoy.BootstrapConfig
+		want   map[string]float64
+	}{
+		"overload manager disabled": {
+			config: envoy.BootstrapConfig{
+				OverloadShrinkHeapThreshold:            0.5,
+				OverloadStopAcceptingRequestsThreshold: 0.6,
+			},
+			want: nil,
+		},
+		"default thresholds": {
+			config: envoy.BootstrapConfig{
+				MaximumHeapSizeBytes: 1073741824,
+			},
+			want: map[string]float64{
+				"envoy.overload_actions.shrink_heap":             0.95,
+				"envoy.overload_actions.stop_accepting_requests": 0.98,
+			},
+		},
+		"custom thresholds": {
+			config: envoy.BootstrapConfig{
+				MaximumHeapSizeBytes:                   1073741824,
+				OverloadShrinkHeapThreshold:            0.8,
+				OverloadStopAcceptingRequestsThreshold: 0.9,
+			},
+			want: map[string]float64{
+				"envoy.overload_actions.shrink_heap":             0.8,
+				"envoy.overload_actions.stop_accepting_requests": 0.9,
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			om := bootstrapConfig(&tc.config).GetOverloadManager()
+			if tc.want == nil {
+				assert.Nil(t, om)
+				return
+			}
+
+			got := map[string]float64{}
+			for _, action := range om.GetActions() {
+				for _, trigger := range action.GetTriggers() {
+					assert.Equal(t, "envoy.resource_monitors.fixed_heap", trigger.GetName())
+					got[action.GetName()] = trigger.GetThreshold().GetValue()
+				}
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestBootstrapXDSDelta(t *testing.T) {
+	tests := map[string]struct {
+		config envoy.BootstrapConfig
+		want   envoy_config_core_v3.ApiConfigSource_ApiType
+	}{
+		"state of the world": {
+			config: envoy.BootstrapConfig{},
+			want:   envoy_config_core_v3.ApiConfigSource_GRPC,
+		},
+		"delta": {
+			config: envoy.BootstrapConfig{XDSDelta: true},
+			want:   envoy_config_core_v3.ApiConfigSource_DELTA_GRPC,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			b := bootstrapConfig(&tc.config)
+			assert.Equal(t, tc.want, b.GetDynamicResources().GetLdsConfig().GetApiConfigSource().GetApiType())
+			assert.Equal(t, tc.want, b.GetDynamicResources().GetCdsConfig().GetApiConfigSource().GetApiType())
+			assert.Equal(t, tc.want, b.GetLayeredRuntime().GetLayers()[0].GetRtdsLayer().GetRtdsConfig().GetApiConfigSource().GetApiType())
+		})
+	}
+}
+
+func TestBootstrapXDSUnixSocket(t *testing.T) {
+	b := bootstrapConfig(&envoy.BootstrapConfig{
+		XDSAddress:  "unix:///var/run/contour/xds.sock",
+		XDSGRPCPort: 9200,
+	})
+
+	contour := b.GetStaticResources().GetClusters()[0]
+	require.Equal(t, "contour", contour.GetName())
+	assert.Equal(t, envoy_config_cluster_v3.Cluster_STATIC, contour.GetType())
+
+	address := contour.GetLoadAssignment().GetEndpoints()[0].GetLbEndpoints()[0].GetEndpoint().GetAddress()
+	assert.Equal(t, "/var/run/contour/xds.sock", address.GetPipe().GetPath())
+	assert.Nil(t, address.GetSocketAddress())
+}
+
 func unmarshal(t *testing.T, data string, pb proto.Message) {
 	err := protojson.Unmarshal([]byte(data), pb)
 	checkErr(t, err)
@@ -20,6 +20,7 @@ import (
 
 	envoy_config_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_clusters_aggregate_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/clusters/aggregate/v3"
 	envoy_upstream_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3"
 	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"google.golang.org/protobuf/types/known/anypb"
@@ -87,49 +88,90 @@ func Cluster(c *dag.Cluster) *envoy_config_cluster_v3.Cluster {
 		cluster.TransportSocket = UpstreamTLSTransportSocket(
 			UpstreamTLSContext(
 				c.UpstreamValidation,
-				c.SNI,
+				upstreamSNI(c),
 				c.ClientCertificate,
 				c.UpstreamTLS,
+				c.ALPNProtocols...,
 			),
 		)
 	case "h2":
 		httpVersion = HTTPVersion2
+		alpnProtocols := c.ALPNProtocols
+		if len(alpnProtocols) == 0 {
+			alpnProtocols = []string{"h2"}
+		}
 		cluster.TransportSocket = UpstreamTLSTransportSocket(
 			UpstreamTLSContext(
 				c.UpstreamValidation,
-				c.SNI,
+				upstreamSNI(c),
 				c.ClientCertificate,
 				c.UpstreamTLS,
-				"h2",
+				alpnProtocols...,
 			),
 		)
 	case "h2c":
 		httpVersion = HTTPVersion2
 	}
 
+	if c.UpstreamProxyProtocol != "" {
+		cluster.TransportSocket = UpstreamProxyProtocolTransportSocket(c.UpstreamProxyProtocol, cluster.TransportSocket)
+	}
+
 	if c.TimeoutPolicy.ConnectTimeout > time.Duration(0) {
 		cluster.ConnectTimeout = durationpb.New(c.TimeoutPolicy.ConnectTimeout)
 	}
 
-	cluster.TypedExtensionProtocolOptions = protocolOptions(httpVersion, c.TimeoutPolicy.IdleConnectionTimeout, c.MaxRequestsPerConnection)
+	cluster.TypedExtensionProtocolOptions = protocolOptions(httpVersion, c.TimeoutPolicy.IdleConnectionTimeout, c.MaxRequestsPerConnection, c.HTTP2Config)
 
-	if c.SlowStartConfig != nil {
-		switch cluster.LbPolicy {
-		case envoy_config_cluster_v3.Cluster_LEAST_REQUEST:
+	// Slow start is only supported for round robin and weighted least request.
+	switch cluster.LbPolicy {
+	case envoy_config_cluster_v3.Cluster_LEAST_REQUEST:
+		if c.SlowStartConfig != nil || c.LeastRequestConfig != nil {
+			lrc := &envoy_config_cluster_v3.Cluster_LeastRequestLbConfig{}
+			if c.SlowStartConfig != nil {
+				lrc.SlowStartConfig = slowStartConfig(c.SlowStartConfig)
+			}
+			if c.LeastRequestConfig != nil {
+				lrc.ActiveRequestBias = &envoy_config_core_v3.RuntimeDouble{
+					DefaultValue: c.LeastRequestConfig.ActiveRequestBias,
+					RuntimeKey:   "contour.leastrequest.activerequestbias",
+				}
+			}
 			cluster.LbConfig = &envoy_config_cluster_v3.Cluster_LeastRequestLbConfig_{
-				LeastRequestLbConfig: &envoy_config_cluster_v3.Cluster_LeastRequestLbConfig{
-					SlowStartConfig: slowStartConfig(c.SlowStartConfig),
-				},
+				LeastRequestLbConfig: lrc,
 			}
-		case envoy_config_cluster_v3.Cluster_ROUND_ROBIN:
+		}
+	case envoy_config_cluster_v3.Cluster_ROUND_ROBIN:
+		if c.SlowStartConfig != nil {
 			cluster.LbConfig = &envoy_config_cluster_v3.Cluster_RoundRobinLbConfig_{
 				RoundRobinLbConfig: &envoy_config_cluster_v3.Cluster_RoundRobinLbConfig{
 					SlowStartConfig: slowStartConfig(c.SlowStartConfig),
 				},
 			}
-		default:
-			// Slow start is only supported for round robin and weighted least request.
 		}
+	case envoy_config_cluster_v3.Cluster_RING_HASH:
+		if c.RingHashConfig != nil {
+			rhc := &envoy_config_cluster_v3.Cluster_RingHashLbConfig{}
+			if c.RingHashConfig.MinimumRingSize > 0 {
+				rhc.MinimumRingSize = wrapperspb.UInt64(c.RingHashConfig.MinimumRingSize)
+			}
+			if c.RingHashConfig.MaximumRingSize > 0 {
+				rhc.MaximumRingSize = wrapperspb.UInt64(c.RingHashConfig.MaximumRingSize)
+			}
+			cluster.LbConfig = &envoy_config_cluster_v3.Cluster_RingHashLbConfig_{
+				RingHashLbConfig: rhc,
+			}
+		}
+	case envoy_config_cluster_v3.Cluster_MAGLEV:
+		if c.MaglevConfig != nil {
+			cluster.LbConfig = &envoy_config_cluster_v3.Cluster_MaglevLbConfig_{
+				MaglevLbConfig: &envoy_config_cluster_v3.Cluster_MaglevLbConfig{
+					TableSize: wrapperspb.UInt64(c.MaglevConfig.TableSize),
+				},
+			}
+		}
+	default:
+		// Other load balancer policies have no policy specific configuration.
 	}
 
 	return cluster
@@ -182,13 +224,40 @@ func ExtensionCluster(ext *dag.ExtensionCluster) *envoy_config_cluster_v3.Cluste
 	if ext.ClusterTimeoutPolicy.ConnectTimeout > time.Duration(0) {
 		cluster.ConnectTimeout = durationpb.New(ext.ClusterTimeoutPolicy.ConnectTimeout)
 	}
-	cluster.TypedExtensionProtocolOptions = protocolOptions(http2Version, ext.ClusterTimeoutPolicy.IdleConnectionTimeout, nil)
+	cluster.TypedExtensionProtocolOptions = protocolOptions(http2Version, ext.ClusterTimeoutPolicy.IdleConnectionTimeout, nil, nil)
 
 	applyCircuitBreakers(cluster, ext.CircuitBreakers)
 
 	return cluster
 }
 
+// FailoverCluster builds an aggregate envoy_config_cluster_v3.Cluster
+// that sends connections to the first of the clusters of the given
+// *dag.TCPProxy that has healthy endpoints.
+func FailoverCluster(proxy *dag.TCPProxy) *envoy_config_cluster_v3.Cluster {
+	clusters := make([]string, 0, len(proxy.Clusters))
+	for _, c := range proxy.Clusters {
+		clusters = append(clusters, envoy.Clustername(c))
+	}
+
+	name := envoy.FailoverClustername(proxy)
+
+	return &envoy_config_cluster_v3.Cluster{
+		Name:           name,
+		AltStatName:    strings.ReplaceAll(name, "/", "_"),
+		ConnectTimeout: durationpb.New(2 * time.Second),
+		LbPolicy:       envoy_config_cluster_v3.Cluster_CLUSTER_PROVIDED,
+		ClusterDiscoveryType: &envoy_config_cluster_v3.Cluster_ClusterType{
+			ClusterType: &envoy_config_cluster_v3.Cluster_CustomClusterType{
+				Name: "envoy.clusters.aggregate",
+				TypedConfig: protobuf.MustMarshalAny(&envoy_clusters_aggregate_v3.ClusterConfig{
+					Clusters: clusters,
+				}),
+			},
+		},
+	}
+}
+
 func applyCircuitBreakers(cluster *envoy_config_cluster_v3.Cluster, settings dag.CircuitBreakers) {
 	if envoy.AnyPositive(settings.MaxConnections, settings.MaxPendingRequests, settings.MaxRequests, settings.MaxRetries, settings.PerHostMaxConnections) {
 		cluster.CircuitBreakers = &envoy_config_cluster_v3.CircuitBreakers{
@@ -249,6 +318,8 @@ func lbPolicy(strategy string) envoy_config_cluster_v3.Cluster_LbPolicy {
 		return envoy_config_cluster_v3.Cluster_RANDOM
 	case dag.LoadBalancerPolicyCookie, dag.LoadBalancerPolicyRequestHash:
 		return envoy_config_cluster_v3.Cluster_RING_HASH
+	case dag.LoadBalancerPolicyMaglev:
+		return envoy_config_cluster_v3.Cluster_MAGLEV
 	default:
 		return envoy_config_cluster_v3.Cluster_ROUND_ROBIN
 	}
@@ -281,11 +352,21 @@ func ClusterCommonLBConfig() *envoy_config_cluster_v3.Cluster_CommonLbConfig {
 
 // ConfigSource returns a *envoy_config_core_v3.ConfigSource for cluster.
 func ConfigSource(cluster string) *envoy_config_core_v3.ConfigSource {
+	return configSource(cluster, envoy_config_core_v3.ApiConfigSource_GRPC)
+}
+
+// DeltaConfigSource returns a *envoy_config_core_v3.ConfigSource for cluster
+// that uses the incremental (delta) xDS protocol.
+func DeltaConfigSource(cluster string) *envoy_config_core_v3.ConfigSource {
+	return configSource(cluster, envoy_config_core_v3.ApiConfigSource_DELTA_GRPC)
+}
+
+func configSource(cluster string, apiType envoy_config_core_v3.ApiConfigSource_ApiType) *envoy_config_core_v3.ConfigSource {
 	return &envoy_config_core_v3.ConfigSource{
 		ResourceApiVersion: envoy_config_core_v3.ApiVersion_V3,
 		ConfigSourceSpecifier: &envoy_config_core_v3.ConfigSource_ApiConfigSource{
 			ApiConfigSource: &envoy_config_core_v3.ApiConfigSource{
-				ApiType:             envoy_config_core_v3.ApiConfigSource_GRPC,
+				ApiType:             apiType,
 				TransportApiVersion: envoy_config_core_v3.ApiVersion_V3,
 				GrpcServices: []*envoy_config_core_v3.GrpcService{
 					GrpcService(cluster, "", timeout.DefaultSetting()),
@@ -324,7 +405,7 @@ func parseDNSLookupFamily(value string) envoy_config_cluster_v3.Cluster_DnsLooku
 	return envoy_config_cluster_v3.Cluster_AUTO
 }
 
-func protocolOptions(explicitHTTPVersion HTTPVersionType, idleConnectionTimeout timeout.Setting, maxRequestsPerConnection *uint32) map[string]*anypb.Any {
+func protocolOptions(explicitHTTPVersion HTTPVersionType, idleConnectionTimeout timeout.Setting, maxRequestsPerConnection *uint32, http2 *dag.HTTP2Config) map[string]*anypb.Any {
 	// Keep Envoy defaults by not setting protocol options at all if not necessary.
 	if explicitHTTPVersion == HTTPVersionAuto && idleConnectionTimeout.UseDefault() && maxRequestsPerConnection == nil {
 		return nil
@@ -343,7 +424,9 @@ func protocolOptions(explicitHTTPVersion HTTPVersionType, idleConnectionTimeout
 	case HTTPVersion2:
 		options.UpstreamProtocolOptions = &envoy_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig_{
 			ExplicitHttpConfig: &envoy_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig{
-				ProtocolConfig: &envoy_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig_Http2ProtocolOptions{},
+				ProtocolConfig: &envoy_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig_Http2ProtocolOptions{
+					Http2ProtocolOptions: http2ProtocolOptions(http2),
+				},
 			},
 		}
 	case HTTPVersion3:
@@ -373,6 +456,40 @@ func protocolOptions(explicitHTTPVersion HTTPVersionType, idleConnectionTimeout
 	}
 }
 
+// http2ProtocolOptions returns the HTTP/2 options for the given
+// settings, or nil to use Envoy's defaults.
+func http2ProtocolOptions(http2 *dag.HTTP2Config) *envoy_config_core_v3.Http2ProtocolOptions {
+	if http2 == nil {
+		return nil
+	}
+
+	options := &envoy_config_core_v3.Http2ProtocolOptions{}
+	if http2.InitialStreamWindowSize != nil {
+		options.InitialStreamWindowSize = wrapperspb.UInt32(*http2.InitialStreamWindowSize)
+	}
+	if http2.InitialConnectionWindowSize != nil {
+		options.InitialConnectionWindowSize = wrapperspb.UInt32(*http2.InitialConnectionWindowSize)
+	}
+	if http2.MaxConcurrentStreams != nil {
+		options.MaxConcurrentStreams = wrapperspb.UInt32(*http2.MaxConcurrentStreams)
+	}
+	return options
+}
+
+// upstreamSNI returns the SNI to use for TLS connections to the cluster.
+// An explicitly configured SNI takes precedence, otherwise the first
+// subject name used for upstream validation is used so that the SNI
+// matches the hostname being validated.
+func upstreamSNI(c *dag.Cluster) string {
+	if c.SNI != "" {
+		return c.SNI
+	}
+	if c.UpstreamValidation != nil && len(c.UpstreamValidation.SubjectNames) > 0 {
+		return c.UpstreamValidation.SubjectNames[0]
+	}
+	return ""
+}
+
 // slowStartConfig returns the slow start configuration.
 func slowStartConfig(slowStartConfig *dag.SlowStartConfig) *envoy_config_cluster_v3.Cluster_SlowStartConfig {
 	return &envoy_config_cluster_v3.Cluster_SlowStartConfig{
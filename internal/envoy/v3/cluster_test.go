@@ -20,6 +20,7 @@ import (
 	envoy_config_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_config_endpoint_v3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	envoy_clusters_aggregate_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/clusters/aggregate/v3"
 	envoy_upstream_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3"
 	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/stretchr/testify/assert"
@@ -208,6 +209,127 @@ func TestCluster(t *testing.T) {
 				},
 			},
 		},
+		"h2 upstream with alpn protocols": {
+			cluster: &dag.Cluster{
+				Upstream:      service(s1, "h2"),
+				Protocol:      "h2",
+				ALPNProtocols: []string{"h2", "custom"},
+			},
+			want: &envoy_config_cluster_v3.Cluster{
+				Name:                 "default/kuard/443/6d35de2b1a",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_config_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_config_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				TransportSocket: UpstreamTLSTransportSocket(
+					UpstreamTLSContext(nil, "", nil, nil, "h2", "custom"),
+				),
+				TypedExtensionProtocolOptions: map[string]*anypb.Any{
+					"envoy.extensions.upstreams.http.v3.HttpProtocolOptions": protobuf.MustMarshalAny(
+						&envoy_upstream_http_v3.HttpProtocolOptions{
+							UpstreamProtocolOptions: &envoy_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig_{
+								ExplicitHttpConfig: &envoy_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig{
+									ProtocolConfig: &envoy_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig_Http2ProtocolOptions{},
+								},
+							},
+						}),
+				},
+			},
+		},
+		"h2c upstream with http2 options": {
+			cluster: &dag.Cluster{
+				Upstream: service(s1, "h2c"),
+				Protocol: "h2c",
+				HTTP2Config: &dag.HTTP2Config{
+					InitialStreamWindowSize:     ptr.To(uint32(1048576)),
+					InitialConnectionWindowSize: ptr.To(uint32(4194304)),
+					MaxConcurrentStreams:        ptr.To(uint32(100)),
+				},
+			},
+			want: &envoy_config_cluster_v3.Cluster{
+				Name:                 "default/kuard/443/f62bc2c825",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_config_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_config_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				TypedExtensionProtocolOptions: map[string]*anypb.Any{
+					"envoy.extensions.upstreams.http.v3.HttpProtocolOptions": protobuf.MustMarshalAny(
+						&envoy_upstream_http_v3.HttpProtocolOptions{
+							UpstreamProtocolOptions: &envoy_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig_{
+								ExplicitHttpConfig: &envoy_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig{
+									ProtocolConfig: &envoy_upstream_http_v3.HttpProtocolOptions_ExplicitHttpConfig_Http2ProtocolOptions{
+										Http2ProtocolOptions: &envoy_config_core_v3.Http2ProtocolOptions{
+											InitialStreamWindowSize:     wrapperspb.UInt32(1048576),
+											InitialConnectionWindowSize: wrapperspb.UInt32(4194304),
+											MaxConcurrentStreams:        wrapperspb.UInt32(100),
+										},
+									},
+								},
+							},
+						}),
+				},
+			},
+		},
+		"tls upstream with alpn protocols": {
+			cluster: &dag.Cluster{
+				Upstream:      service(s1, "tls"),
+				Protocol:      "tls",
+				ALPNProtocols: []string{"custom"},
+			},
+			want: &envoy_config_cluster_v3.Cluster{
+				Name:                 "default/kuard/443/39ef28d1ce",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_config_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_config_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				TransportSocket: UpstreamTLSTransportSocket(
+					UpstreamTLSContext(nil, "", nil, nil, "custom"),
+				),
+			},
+		},
+		"upstream proxy protocol": {
+			cluster: &dag.Cluster{
+				Upstream:              service(s1),
+				UpstreamProxyProtocol: "v2",
+			},
+			want: &envoy_config_cluster_v3.Cluster{
+				Name:                 "default/kuard/443/a1047eab10",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_config_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_config_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				TransportSocket: UpstreamProxyProtocolTransportSocket("v2", nil),
+			},
+		},
+		"tls upstream with upstream proxy protocol": {
+			cluster: &dag.Cluster{
+				Upstream:              service(s1, "tls"),
+				Protocol:              "tls",
+				UpstreamProxyProtocol: "v1",
+			},
+			want: &envoy_config_cluster_v3.Cluster{
+				Name:                 "default/kuard/443/d7cdcda189",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_config_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_config_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				TransportSocket: UpstreamProxyProtocolTransportSocket("v1",
+					UpstreamTLSTransportSocket(
+						UpstreamTLSContext(nil, "", nil, nil),
+					),
+				),
+			},
+		},
 		"externalName service": {
 			cluster: &dag.Cluster{
 				Upstream: service(s2),
@@ -356,7 +478,41 @@ func TestCluster(t *testing.T) {
 							},
 							SubjectNames: []string{"foo.bar.io"},
 						},
-						"",
+						"foo.bar.io",
+						nil,
+						nil),
+				),
+			},
+		},
+		"verify tls upstream with san and explicit sni": {
+			cluster: &dag.Cluster{
+				Upstream: service(s1, "tls"),
+				Protocol: "tls",
+				SNI:      "override.bar.io",
+				UpstreamValidation: &dag.PeerValidationContext{
+					CACertificates: []*dag.Secret{
+						secret,
+					},
+					SubjectNames: []string{"foo.bar.io"},
+				},
+			},
+			want: &envoy_config_cluster_v3.Cluster{
+				Name:                 "default/kuard/443/1b8b8e55e5",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_config_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_config_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				TransportSocket: UpstreamTLSTransportSocket(
+					UpstreamTLSContext(
+						&dag.PeerValidationContext{
+							CACertificates: []*dag.Secret{
+								secret,
+							},
+							SubjectNames: []string{"foo.bar.io"},
+						},
+						"override.bar.io",
 						nil,
 						nil),
 				),
@@ -393,7 +549,7 @@ func TestCluster(t *testing.T) {
 							},
 							SubjectNames: []string{"foo.bar.io"},
 						},
-						"",
+						"foo.bar.io",
 						nil,
 						&dag.UpstreamTLS{
 							MinimumProtocolVersion: "1.3",
@@ -782,6 +938,125 @@ func TestCluster(t *testing.T) {
 				},
 			},
 		},
+		"slow start mode: LB policy LEAST_REQUEST with active request bias": {
+			cluster: &dag.Cluster{
+				Upstream: service(s1),
+				SlowStartConfig: &dag.SlowStartConfig{
+					Window:           10 * time.Second,
+					Aggression:       1.0,
+					MinWeightPercent: 10,
+				},
+				LeastRequestConfig: &dag.LeastRequestConfig{
+					ActiveRequestBias: 0.5,
+				},
+				LoadBalancerPolicy: dag.LoadBalancerPolicyWeightedLeastRequest,
+			},
+			want: &envoy_config_cluster_v3.Cluster{
+				Name:                 "default/kuard/443/3fe7b3410c",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_config_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_config_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				LbPolicy: envoy_config_cluster_v3.Cluster_LEAST_REQUEST,
+				LbConfig: &envoy_config_cluster_v3.Cluster_LeastRequestLbConfig_{
+					LeastRequestLbConfig: &envoy_config_cluster_v3.Cluster_LeastRequestLbConfig{
+						ActiveRequestBias: &envoy_config_core_v3.RuntimeDouble{
+							DefaultValue: 0.5,
+							RuntimeKey:   "contour.leastrequest.activerequestbias",
+						},
+						SlowStartConfig: &envoy_config_cluster_v3.Cluster_SlowStartConfig{
+							SlowStartWindow: durationpb.New(10 * time.Second),
+							Aggression: &envoy_config_core_v3.RuntimeDouble{
+								DefaultValue: 1.0,
+								RuntimeKey:   "contour.slowstart.aggression",
+							},
+							MinWeightPercent: &envoy_type_v3.Percent{
+								Value: 10.0,
+							},
+						},
+					},
+				},
+			},
+		},
+		"LB policy LEAST_REQUEST with active request bias": {
+			cluster: &dag.Cluster{
+				Upstream: service(s1),
+				LeastRequestConfig: &dag.LeastRequestConfig{
+					ActiveRequestBias: 2.0,
+				},
+				LoadBalancerPolicy: dag.LoadBalancerPolicyWeightedLeastRequest,
+			},
+			want: &envoy_config_cluster_v3.Cluster{
+				Name:                 "default/kuard/443/f44391ab49",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_config_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_config_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				LbPolicy: envoy_config_cluster_v3.Cluster_LEAST_REQUEST,
+				LbConfig: &envoy_config_cluster_v3.Cluster_LeastRequestLbConfig_{
+					LeastRequestLbConfig: &envoy_config_cluster_v3.Cluster_LeastRequestLbConfig{
+						ActiveRequestBias: &envoy_config_core_v3.RuntimeDouble{
+							DefaultValue: 2.0,
+							RuntimeKey:   "contour.leastrequest.activerequestbias",
+						},
+					},
+				},
+			},
+		},
+		"LB policy RING_HASH with ring sizes": {
+			cluster: &dag.Cluster{
+				Upstream: service(s1),
+				RingHashConfig: &dag.RingHashConfig{
+					MinimumRingSize: 2048,
+					MaximumRingSize: 4096,
+				},
+				LoadBalancerPolicy: dag.LoadBalancerPolicyRequestHash,
+			},
+			want: &envoy_config_cluster_v3.Cluster{
+				Name:                 "default/kuard/443/8c31dbcb69",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_config_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_config_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				LbPolicy: envoy_config_cluster_v3.Cluster_RING_HASH,
+				LbConfig: &envoy_config_cluster_v3.Cluster_RingHashLbConfig_{
+					RingHashLbConfig: &envoy_config_cluster_v3.Cluster_RingHashLbConfig{
+						MinimumRingSize: wrapperspb.UInt64(2048),
+						MaximumRingSize: wrapperspb.UInt64(4096),
+					},
+				},
+			},
+		},
+		"LB policy MAGLEV with table size": {
+			cluster: &dag.Cluster{
+				Upstream: service(s1),
+				MaglevConfig: &dag.MaglevConfig{
+					TableSize: 131071,
+				},
+				LoadBalancerPolicy: dag.LoadBalancerPolicyMaglev,
+			},
+			want: &envoy_config_cluster_v3.Cluster{
+				Name:                 "default/kuard/443/8d23ae0f28",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(envoy_config_cluster_v3.Cluster_EDS),
+				EdsClusterConfig: &envoy_config_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				LbPolicy: envoy_config_cluster_v3.Cluster_MAGLEV,
+				LbConfig: &envoy_config_cluster_v3.Cluster_MaglevLbConfig_{
+					MaglevLbConfig: &envoy_config_cluster_v3.Cluster_MaglevLbConfig{
+						TableSize: wrapperspb.UInt64(131071),
+					},
+				},
+			},
+		},
 		"cluster with per connection buffer limit bytes set": {
 			cluster: &dag.Cluster{
 				Upstream:                      service(s1),
@@ -1063,6 +1338,57 @@ func TestDNSNameCluster(t *testing.T) {
 	}
 }
 
+func TestFailoverCluster(t *testing.T) {
+	cluster := func(name string) *dag.Cluster {
+		return &dag.Cluster{
+			Upstream: &dag.Service{
+				Weighted: dag.WeightedService{
+					Weight:           1,
+					ServiceName:      name,
+					ServiceNamespace: "default",
+					ServicePort: core_v1.ServicePort{
+						Protocol:   "TCP",
+						Port:       5432,
+						TargetPort: intstr.FromInt(5432),
+					},
+				},
+			},
+		}
+	}
+
+	proxy := &dag.TCPProxy{
+		Clusters: []*dag.Cluster{cluster("primary"), cluster("standby")},
+		Failover: true,
+	}
+
+	want := &envoy_config_cluster_v3.Cluster{
+		Name:           "failover/default/primary/d21ca0e529",
+		AltStatName:    "failover_default_primary_d21ca0e529",
+		ConnectTimeout: durationpb.New(2 * time.Second),
+		LbPolicy:       envoy_config_cluster_v3.Cluster_CLUSTER_PROVIDED,
+		ClusterDiscoveryType: &envoy_config_cluster_v3.Cluster_ClusterType{
+			ClusterType: &envoy_config_cluster_v3.Cluster_CustomClusterType{
+				Name: "envoy.clusters.aggregate",
+				TypedConfig: protobuf.MustMarshalAny(&envoy_clusters_aggregate_v3.ClusterConfig{
+					Clusters: []string{
+						"default/primary/5432/da39a3ee5e",
+						"default/standby/5432/da39a3ee5e",
+					},
+				}),
+			},
+		},
+	}
+
+	protobuf.ExpectEqual(t, want, FailoverCluster(proxy))
+
+	// The name depends on the order of the clusters.
+	reversed := &dag.TCPProxy{
+		Clusters: []*dag.Cluster{cluster("standby"), cluster("primary")},
+		Failover: true,
+	}
+	assert.NotEqual(t, envoy.FailoverClustername(proxy), envoy.FailoverClustername(reversed))
+}
+
 func TestClusterLoadAssignmentName(t *testing.T) {
 	assert.Equal(t, "ns/svc/port", xds.ClusterLoadAssignmentName(types.NamespacedName{Namespace: "ns", Name: "svc"}, "port"))
 	assert.Equal(t, "ns/svc", xds.ClusterLoadAssignmentName(types.NamespacedName{Namespace: "ns", Name: "svc"}, ""))
@@ -1198,6 +1524,32 @@ func TestClustername(t *testing.T) {
 		cluster: cluster1,
 		want:    "default/backend/80/50abc1400c",
 	})
+
+	ringHashCluster := func(minimum, maximum uint64) *dag.Cluster {
+		return &dag.Cluster{
+			Upstream: &dag.Service{
+				Weighted: dag.WeightedService{
+					Weight:           1,
+					ServiceName:      "backend",
+					ServiceNamespace: "default",
+					ServicePort: core_v1.ServicePort{
+						Name:       "http",
+						Protocol:   "TCP",
+						Port:       80,
+						TargetPort: intstr.FromInt(6502),
+					},
+				},
+			},
+			LoadBalancerPolicy: dag.LoadBalancerPolicyRequestHash,
+			RingHashConfig: &dag.RingHashConfig{
+				MinimumRingSize: minimum,
+				MaximumRingSize: maximum,
+			},
+		}
+	}
+
+	// Ring sizes of (1, 10) and (11, unset) must not produce the same name.
+	assert.NotEqual(t, envoy.Clustername(ringHashCluster(1, 10)), envoy.Clustername(ringHashCluster(11, 0)))
 }
 
 func TestLBPolicy(t *testing.T) {
@@ -1209,11 +1561,11 @@ func TestLBPolicy(t *testing.T) {
 		"unknown":              envoy_config_cluster_v3.Cluster_ROUND_ROBIN,
 		"Cookie":               envoy_config_cluster_v3.Cluster_RING_HASH,
 		"RequestHash":          envoy_config_cluster_v3.Cluster_RING_HASH,
+		"Maglev":               envoy_config_cluster_v3.Cluster_MAGLEV,
 
-		// RingHash and Maglev were removed as options in 0.13.
+		// RingHash was removed as an option in 0.13.
 		// See #1150
 		"RingHash": envoy_config_cluster_v3.Cluster_ROUND_ROBIN,
-		"Maglev":   envoy_config_cluster_v3.Cluster_ROUND_ROBIN,
 	}
 
 	for policy, want := range tests {
@@ -16,17 +16,21 @@ package v3
 import (
 	"errors"
 	"fmt"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
 	envoy_config_accesslog_v3 "github.com/envoyproxy/go-control-plane/envoy/config/accesslog/v3"
+	envoy_config_common_mutation_rules_v3 "github.com/envoyproxy/go-control-plane/envoy/config/common/mutation_rules/v3"
 	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_config_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	envoy_compression_gzip_compressor_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/compression/gzip/compressor/v3"
 	envoy_filter_http_compressor_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/compressor/v3"
 	envoy_filter_http_cors_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/cors/v3"
 	envoy_filter_http_ext_authz_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ext_authz/v3"
+	envoy_filter_http_fault_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/fault/v3"
 	envoy_filter_http_grpc_stats_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/grpc_stats/v3"
 	envoy_filter_http_grpc_web_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/grpc_web/v3"
 	envoy_filter_http_jwt_authn_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/jwt_authn/v3"
@@ -34,15 +38,19 @@ import (
 	envoy_filter_http_lua_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/lua/v3"
 	envoy_filter_http_rbac_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/rbac/v3"
 	envoy_filter_http_router_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/router/v3"
+	envoy_filter_http_stateful_session_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/stateful_session/v3"
 	envoy_filter_listener_proxy_protocol_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/listener/proxy_protocol/v3"
 	envoy_filter_listener_tls_inspector_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/listener/tls_inspector/v3"
 	envoy_filter_network_http_connection_manager_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	envoy_filter_network_tcp_proxy_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
 	envoy_transport_socket_tls_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	envoy_matcher_v3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
 	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
+	"k8s.io/utils/ptr"
 
 	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 	"github.com/projectcontour/contour/internal/dag"
@@ -121,11 +129,31 @@ func TLSInspector() *envoy_config_listener_v3.ListenerFilter {
 }
 
 // ProxyProtocol returns a new Proxy Protocol listener filter.
-func ProxyProtocol() *envoy_config_listener_v3.ListenerFilter {
+// If allowRequestsWithoutProxyProtocol is true, connections that
+// do not start with a PROXY protocol header are accepted as is.
+// If versions is not empty, only the listed versions ("v1" or
+// "v2") are accepted.
+func ProxyProtocol(allowRequestsWithoutProxyProtocol bool, versions []string) *envoy_config_listener_v3.ListenerFilter {
+	var disallowed []envoy_config_core_v3.ProxyProtocolConfig_Version
+	if len(versions) > 0 {
+		for v, name := range map[envoy_config_core_v3.ProxyProtocolConfig_Version]string{
+			envoy_config_core_v3.ProxyProtocolConfig_V1: "v1",
+			envoy_config_core_v3.ProxyProtocolConfig_V2: "v2",
+		} {
+			if !slices.Contains(versions, name) {
+				disallowed = append(disallowed, v)
+			}
+		}
+		slices.Sort(disallowed)
+	}
+
 	return &envoy_config_listener_v3.ListenerFilter{
 		Name: wellknown.ProxyProtocol,
 		ConfigType: &envoy_config_listener_v3.ListenerFilter_TypedConfig{
-			TypedConfig: protobuf.MustMarshalAny(&envoy_filter_listener_proxy_protocol_v3.ProxyProtocol{}),
+			TypedConfig: protobuf.MustMarshalAny(&envoy_filter_listener_proxy_protocol_v3.ProxyProtocol{
+				AllowRequestsWithoutProxyProtocol: allowRequestsWithoutProxyProtocol,
+				DisallowedVersions:                disallowed,
+			}),
 		},
 	}
 }
@@ -154,6 +182,37 @@ func Listener(name, address string, port int, perConnectionBufferLimitBytes *uin
 	return l
 }
 
+// ListenerFiltersTimeout sets the maximum time the listener filters of l
+// may take to inspect a new connection. If continueOnTimeout is true,
+// connections whose listener filters time out are passed on to a filter
+// chain rather than closed.
+func ListenerFiltersTimeout(l *envoy_config_listener_v3.Listener, d timeout.Setting, continueOnTimeout bool) {
+	l.ListenerFiltersTimeout = envoy.Timeout(d)
+	l.ContinueOnListenerFiltersTimeout = continueOnTimeout
+}
+
+// QUICListener returns a new envoy_config_listener_v3.Listener that
+// accepts HTTP/3 connections over UDP on the supplied address and port.
+// Filter chains must be added with FilterChainQUIC.
+func QUICListener(name, address string, port int, perConnectionBufferLimitBytes *uint32) *envoy_config_listener_v3.Listener {
+	l := &envoy_config_listener_v3.Listener{
+		Name:    name,
+		Address: UDPSocketAddress(address, port),
+		UdpListenerConfig: &envoy_config_listener_v3.UdpListenerConfig{
+			QuicOptions: &envoy_config_listener_v3.QuicProtocolOptions{},
+			DownstreamSocketConfig: &envoy_config_core_v3.UdpSocketConfig{
+				PreferGro: wrapperspb.Bool(true),
+			},
+		},
+	}
+
+	if perConnectionBufferLimitBytes != nil {
+		l.PerConnectionBufferLimitBytes = wrapperspb.UInt32(*perConnectionBufferLimitBytes)
+	}
+
+	return l
+}
+
 const (
 	CORSFilterName            string = "envoy.filters.http.cors"
 	LocalRateLimitFilterName  string = "envoy.filters.http.local_ratelimit"
@@ -165,8 +224,14 @@ const (
 	CompressorFilterName      string = "envoy.filters.http.compressor"
 	GRPCWebFilterName         string = "envoy.filters.http.grpc_web"
 	GRPCStatsFilterName       string = "envoy.filters.http.grpc_stats"
+	FaultFilterName           string = "envoy.filters.http.fault"
+	StatefulSessionFilterName string = "envoy.filters.http.stateful_session"
 )
 
+// JWTPayloadMetadataKey is the key within the JWTAuthnFilterName dynamic
+// metadata namespace that verified JWT payloads are written to.
+const JWTPayloadMetadataKey = "jwt_payload"
+
 type httpConnectionManagerBuilder struct {
 	routeConfigName               string
 	metricsPrefix                 string
@@ -180,14 +245,26 @@ type httpConnectionManagerBuilder struct {
 	filters                       []*envoy_filter_network_http_connection_manager_v3.HttpFilter
 	codec                         HTTPVersionType // Note the zero value is AUTO, which is the default we want.
 	allowChunkedLength            bool
+	allowAbsoluteURL              *bool
+	disableAcceptHTTP10           bool
+	http10DefaultHost             string
+	deltaXDS                      bool
 	mergeSlashes                  bool
+	stripAnyHostPort              bool
+	stripMatchingHostPort         bool
 	serverHeaderTransformation    envoy_filter_network_http_connection_manager_v3.HttpConnectionManager_ServerHeaderTransformation
+	serverName                    string
+	useRemoteAddress              *bool
+	via                           string
 	forwardClientCertificate      *dag.ClientCertificateDetails
 	numTrustedHops                uint32
 	tracingConfig                 *envoy_filter_network_http_connection_manager_v3.HttpConnectionManager_Tracing
 	maxRequestsPerConnection      *uint32
 	http2MaxConcurrentStreams     *uint32
+	http2KeepaliveInterval        timeout.Setting
+	http2KeepaliveTimeout         timeout.Setting
 	enableWebsockets              bool
+	localReplyConfig              *envoy_filter_network_http_connection_manager_v3.LocalReplyConfig
 }
 
 func (b *httpConnectionManagerBuilder) EnableWebsockets(enable bool) *httpConnectionManagerBuilder {
@@ -195,6 +272,88 @@ func (b *httpConnectionManagerBuilder) EnableWebsockets(enable bool) *httpConnec
 	return b
 }
 
+// LocalReplyConfig sets the configuration used to customize replies
+// generated by Envoy itself, such as direct responses.
+func (b *httpConnectionManagerBuilder) LocalReplyConfig(cfg *envoy_filter_network_http_connection_manager_v3.LocalReplyConfig) *httpConnectionManagerBuilder {
+	b.localReplyConfig = cfg
+	return b
+}
+
+// LocalReplyConfig returns the local reply config of an HTTP connection
+// manager serving the given virtual hosts. Its mappers render the vhosts'
+// templated direct response bodies, followed by those of the global local
+// reply policy. It returns nil if there are no mappers.
+func LocalReplyConfig(policy *contour_v1alpha1.LocalReplyPolicy, vhosts ...*dag.VirtualHost) *envoy_filter_network_http_connection_manager_v3.LocalReplyConfig {
+	cfg := DirectResponseLocalReplyConfig(vhosts...)
+
+	mappers := localReplyPolicyMappers(policy)
+	if len(mappers) == 0 {
+		return cfg
+	}
+
+	if cfg == nil {
+		cfg = &envoy_filter_network_http_connection_manager_v3.LocalReplyConfig{}
+	}
+	cfg.Mappers = append(cfg.Mappers, mappers...)
+	return cfg
+}
+
+// localReplyPolicyMappers returns a mapper for each override of the local
+// reply policy, followed by one for its default format. Direct responses
+// are excluded so that route bodies are left alone.
+func localReplyPolicyMappers(policy *contour_v1alpha1.LocalReplyPolicy) []*envoy_filter_network_http_connection_manager_v3.ResponseMapper {
+	if policy == nil {
+		return nil
+	}
+
+	const notDirectResponse = `response.code_details != "direct_response"`
+
+	var mappers []*envoy_filter_network_http_connection_manager_v3.ResponseMapper
+	for _, o := range policy.Overrides {
+		mappers = append(mappers, &envoy_filter_network_http_connection_manager_v3.ResponseMapper{
+			Filter:             celAccessLogFilter(fmt.Sprintf("response.code == %d && %s", o.StatusCode, notDirectResponse)),
+			BodyFormatOverride: localReplyFormat(&o.Format),
+		})
+	}
+
+	if policy.Format != nil {
+		mappers = append(mappers, &envoy_filter_network_http_connection_manager_v3.ResponseMapper{
+			Filter:             celAccessLogFilter(notDirectResponse),
+			BodyFormatOverride: localReplyFormat(policy.Format),
+		})
+	}
+
+	return mappers
+}
+
+// localReplyFormat converts a local reply body format to
+// an Envoy substitution format string.
+func localReplyFormat(format *contour_v1alpha1.LocalReplyFormat) *envoy_config_core_v3.SubstitutionFormatString {
+	sfs := &envoy_config_core_v3.SubstitutionFormatString{
+		ContentType: format.ContentType,
+	}
+
+	if len(format.JSON) > 0 {
+		fields := map[string]*structpb.Value{}
+		for key, val := range format.JSON {
+			fields[key] = structpb.NewStringValue(val)
+		}
+		sfs.Format = &envoy_config_core_v3.SubstitutionFormatString_JsonFormat{
+			JsonFormat: &structpb.Struct{Fields: fields},
+		}
+		return sfs
+	}
+
+	sfs.Format = &envoy_config_core_v3.SubstitutionFormatString_TextFormatSource{
+		TextFormatSource: &envoy_config_core_v3.DataSource{
+			Specifier: &envoy_config_core_v3.DataSource_InlineString{
+				InlineString: format.Text,
+			},
+		},
+	}
+	return sfs
+}
+
 // RouteConfigName sets the name of the RDS element that contains
 // the routing table for this manager.
 func (b *httpConnectionManagerBuilder) RouteConfigName(name string) *httpConnectionManagerBuilder {
@@ -265,12 +424,53 @@ func (b *httpConnectionManagerBuilder) AllowChunkedLength(enabled bool) *httpCon
 	return b
 }
 
+// AllowAbsoluteURL sets whether HTTP/1 requests with an absolute URL
+// target are accepted. If nil, Envoy's default is used.
+func (b *httpConnectionManagerBuilder) AllowAbsoluteURL(allow *bool) *httpConnectionManagerBuilder {
+	b.allowAbsoluteURL = allow
+	return b
+}
+
+// DisableAcceptHTTP10 makes the connection manager reject HTTP/1.0 requests.
+func (b *httpConnectionManagerBuilder) DisableAcceptHTTP10(disabled bool) *httpConnectionManagerBuilder {
+	b.disableAcceptHTTP10 = disabled
+	return b
+}
+
+// HTTP10DefaultHost sets the host used to route HTTP/1.0 requests
+// that do not have a Host header.
+func (b *httpConnectionManagerBuilder) HTTP10DefaultHost(host string) *httpConnectionManagerBuilder {
+	b.http10DefaultHost = host
+	return b
+}
+
+// DeltaXDS makes the connection manager fetch its route
+// configuration with the incremental (delta) xDS protocol.
+func (b *httpConnectionManagerBuilder) DeltaXDS(enabled bool) *httpConnectionManagerBuilder {
+	b.deltaXDS = enabled
+	return b
+}
+
 // MergeSlashes toggles Envoy's non-standard merge_slashes path transformation option on the connection manager.
 func (b *httpConnectionManagerBuilder) MergeSlashes(enabled bool) *httpConnectionManagerBuilder {
 	b.mergeSlashes = enabled
 	return b
 }
 
+// StripAnyHostPort toggles removal of any port from the Host/:authority header
+// before virtual host matching on the connection manager.
+func (b *httpConnectionManagerBuilder) StripAnyHostPort(enabled bool) *httpConnectionManagerBuilder {
+	b.stripAnyHostPort = enabled
+	return b
+}
+
+// StripMatchingHostPort toggles removal of the port from the Host/:authority header
+// before virtual host matching, when it matches the listener port, on the connection manager.
+func (b *httpConnectionManagerBuilder) StripMatchingHostPort(enabled bool) *httpConnectionManagerBuilder {
+	b.stripMatchingHostPort = enabled
+	return b
+}
+
 func (b *httpConnectionManagerBuilder) ServerHeaderTransformation(value contour_v1alpha1.ServerHeaderTransformationType) *httpConnectionManagerBuilder {
 	switch value {
 	case contour_v1alpha1.OverwriteServerHeader:
@@ -283,6 +483,27 @@ func (b *httpConnectionManagerBuilder) ServerHeaderTransformation(value contour_
 	return b
 }
 
+// ServerName sets the value of the Server response header. If empty,
+// Envoy's default is used.
+func (b *httpConnectionManagerBuilder) ServerName(name string) *httpConnectionManagerBuilder {
+	b.serverName = name
+	return b
+}
+
+// UseRemoteAddress sets whether the address of the downstream connection
+// is used as the client address. If nil, it is used.
+func (b *httpConnectionManagerBuilder) UseRemoteAddress(enabled *bool) *httpConnectionManagerBuilder {
+	b.useRemoteAddress = enabled
+	return b
+}
+
+// Via sets the value appended to the via header of requests and
+// responses. If empty, no via header is added.
+func (b *httpConnectionManagerBuilder) Via(value string) *httpConnectionManagerBuilder {
+	b.via = value
+	return b
+}
+
 func (b *httpConnectionManagerBuilder) ForwardClientCertificate(details *dag.ClientCertificateDetails) *httpConnectionManagerBuilder {
 	b.forwardClientCertificate = details
 	return b
@@ -304,6 +525,14 @@ func (b *httpConnectionManagerBuilder) HTTP2MaxConcurrentStreams(http2MaxConcurr
 	return b
 }
 
+// HTTP2Keepalive sets the interval and timeout of HTTP/2 keepalive PINGs
+// sent to the downstream. PINGs are only sent if the interval is set.
+func (b *httpConnectionManagerBuilder) HTTP2Keepalive(interval, timeout timeout.Setting) *httpConnectionManagerBuilder {
+	b.http2KeepaliveInterval = interval
+	b.http2KeepaliveTimeout = timeout
+	return b
+}
+
 func (b *httpConnectionManagerBuilder) DefaultFilters() *httpConnectionManagerBuilder {
 	// Add a default set of ordered http filters.
 	// The names are not required to match anything and are
@@ -485,12 +714,17 @@ func (b *httpConnectionManagerBuilder) Get() *envoy_config_listener_v3.Filter {
 		panic(err.Error())
 	}
 
+	rdsConfigSource := ConfigSource("contour")
+	if b.deltaXDS {
+		rdsConfigSource = DeltaConfigSource("contour")
+	}
+
 	cm := &envoy_filter_network_http_connection_manager_v3.HttpConnectionManager{
 		CodecType: b.codec,
 		RouteSpecifier: &envoy_filter_network_http_connection_manager_v3.HttpConnectionManager_Rds{
 			Rds: &envoy_filter_network_http_connection_manager_v3.Rds{
 				RouteConfigName: b.routeConfigName,
-				ConfigSource:    ConfigSource("contour"),
+				ConfigSource:    rdsConfigSource,
 			},
 		},
 		Tracing:     b.tracingConfig,
@@ -501,11 +735,13 @@ func (b *httpConnectionManagerBuilder) Get() *envoy_config_listener_v3.Filter {
 		HttpProtocolOptions: &envoy_config_core_v3.Http1ProtocolOptions{
 			// Enable support for HTTP/1.0 requests that carry
 			// a Host: header. See #537.
-			AcceptHttp_10:      true,
-			AllowChunkedLength: b.allowChunkedLength,
+			AcceptHttp_10:         !b.disableAcceptHTTP10,
+			DefaultHostForHttp_10: b.http10DefaultHost,
+			AllowChunkedLength:    b.allowChunkedLength,
 		},
 
-		UseRemoteAddress:  wrapperspb.Bool(true),
+		UseRemoteAddress:  wrapperspb.Bool(ptr.Deref(b.useRemoteAddress, true)),
+		Via:               b.via,
 		XffNumTrustedHops: b.numTrustedHops,
 
 		NormalizePath: wrapperspb.Bool(true),
@@ -513,12 +749,20 @@ func (b *httpConnectionManagerBuilder) Get() *envoy_config_listener_v3.Filter {
 		// issue #1487 pass through X-Request-Id if provided.
 		PreserveExternalRequestId:  true,
 		MergeSlashes:               b.mergeSlashes,
+		StripMatchingHostPort:      b.stripMatchingHostPort,
 		ServerHeaderTransformation: b.serverHeaderTransformation,
+		ServerName:                 b.serverName,
 
 		RequestTimeout:      envoy.Timeout(b.requestTimeout),
 		StreamIdleTimeout:   envoy.Timeout(b.streamIdleTimeout),
 		DrainTimeout:        envoy.Timeout(b.connectionShutdownGracePeriod),
 		DelayedCloseTimeout: envoy.Timeout(b.delayedCloseTimeout),
+
+		LocalReplyConfig: b.localReplyConfig,
+	}
+
+	if b.allowAbsoluteURL != nil {
+		cm.HttpProtocolOptions.AllowAbsoluteUrl = wrapperspb.Bool(*b.allowAbsoluteURL)
 	}
 
 	// Max connection duration is infinite/disabled by default in Envoy, so if the timeout setting
@@ -562,6 +806,22 @@ func (b *httpConnectionManagerBuilder) Get() *envoy_config_listener_v3.Filter {
 		}
 	}
 
+	if !b.http2KeepaliveInterval.IsDisabled() && !b.http2KeepaliveInterval.UseDefault() {
+		if cm.Http2ProtocolOptions == nil {
+			cm.Http2ProtocolOptions = &envoy_config_core_v3.Http2ProtocolOptions{}
+		}
+		cm.Http2ProtocolOptions.ConnectionKeepalive = &envoy_config_core_v3.KeepaliveSettings{
+			Interval: durationpb.New(b.http2KeepaliveInterval.Duration()),
+			Timeout:  durationpb.New(b.http2KeepaliveTimeout.Duration()),
+		}
+	}
+
+	if b.stripAnyHostPort {
+		cm.StripPortMode = &envoy_filter_network_http_connection_manager_v3.HttpConnectionManager_StripAnyHostPort{
+			StripAnyHostPort: true,
+		}
+	}
+
 	if b.enableWebsockets {
 		cm.UpgradeConfigs = append(cm.UpgradeConfigs,
 			&envoy_filter_network_http_connection_manager_v3.HttpConnectionManager_UpgradeConfig{
@@ -608,6 +868,14 @@ func TCPProxy(statPrefix string, proxy *dag.TCPProxy, accesslogger []*envoy_conf
 		IdleTimeout: durationpb.New(9001 * time.Second),
 	}
 
+	if !proxy.IdleTimeout.UseDefault() {
+		tcpProxy.IdleTimeout = envoy.Timeout(proxy.IdleTimeout)
+	}
+
+	if proxy.MaxConnectAttempts > 0 {
+		tcpProxy.MaxConnectAttempts = wrapperspb.UInt32(proxy.MaxConnectAttempts)
+	}
+
 	var totalWeight uint32
 	var keepClusters []*dag.Cluster
 
@@ -632,9 +900,14 @@ func TCPProxy(statPrefix string, proxy *dag.TCPProxy, accesslogger []*envoy_conf
 	}
 
 	// Set either Cluster or WeightedClusters based on whether
-	// there's one or more than one cluster to include.
-	switch len(keepClusters) {
-	case 1:
+	// there's one or more than one cluster to include. Clusters
+	// that fail over are reached through a single aggregate cluster.
+	switch {
+	case proxy.Failover:
+		tcpProxy.ClusterSpecifier = &envoy_filter_network_tcp_proxy_v3.TcpProxy_Cluster{
+			Cluster: envoy.FailoverClustername(proxy),
+		}
+	case len(keepClusters) == 1:
 		tcpProxy.ClusterSpecifier = &envoy_filter_network_tcp_proxy_v3.TcpProxy_Cluster{
 			Cluster: envoy.Clustername(keepClusters[0]),
 		}
@@ -685,12 +958,21 @@ func UnixSocketAddress(address string) *envoy_config_core_v3.Address {
 
 // SocketAddress creates a new TCP envoy_config_core_v3.Address.
 func SocketAddress(address string, port int) *envoy_config_core_v3.Address {
+	return socketAddress(address, port, envoy_config_core_v3.SocketAddress_TCP)
+}
+
+// UDPSocketAddress creates a new UDP envoy_config_core_v3.Address.
+func UDPSocketAddress(address string, port int) *envoy_config_core_v3.Address {
+	return socketAddress(address, port, envoy_config_core_v3.SocketAddress_UDP)
+}
+
+func socketAddress(address string, port int, protocol envoy_config_core_v3.SocketAddress_Protocol) *envoy_config_core_v3.Address {
 	portValue := uint32(port) //nolint:gosec // disable G115
 	if address == "::" {
 		return &envoy_config_core_v3.Address{
 			Address: &envoy_config_core_v3.Address_SocketAddress{
 				SocketAddress: &envoy_config_core_v3.SocketAddress{
-					Protocol:   envoy_config_core_v3.SocketAddress_TCP,
+					Protocol:   protocol,
 					Address:    address,
 					Ipv4Compat: true,
 					PortSpecifier: &envoy_config_core_v3.SocketAddress_PortValue{
@@ -703,7 +985,7 @@ func SocketAddress(address string, port int) *envoy_config_core_v3.Address {
 	return &envoy_config_core_v3.Address{
 		Address: &envoy_config_core_v3.Address_SocketAddress{
 			SocketAddress: &envoy_config_core_v3.SocketAddress{
-				Protocol: envoy_config_core_v3.SocketAddress_TCP,
+				Protocol: protocol,
 				Address:  address,
 				PortSpecifier: &envoy_config_core_v3.SocketAddress_PortValue{
 					PortValue: portValue,
@@ -738,6 +1020,46 @@ func FilterChains(filters ...*envoy_config_listener_v3.Filter) []*envoy_config_l
 	}
 }
 
+// FilterFault returns a `fault` filter that injects the faults configured
+// per-route, or nil if no route has a fault injection policy.
+func FilterFault(vhosts ...*dag.VirtualHost) *envoy_filter_network_http_connection_manager_v3.HttpFilter {
+	for _, vh := range vhosts {
+		for _, route := range vh.Routes {
+			if route.FaultInjectionPolicy != nil {
+				return &envoy_filter_network_http_connection_manager_v3.HttpFilter{
+					Name: FaultFilterName,
+					ConfigType: &envoy_filter_network_http_connection_manager_v3.HttpFilter_TypedConfig{
+						// Faults are only injected by the per-route configuration.
+						TypedConfig: protobuf.MustMarshalAny(&envoy_filter_http_fault_v3.HTTPFault{}),
+					},
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// FilterStatefulSession returns a `stateful_session` filter that keeps
+// the sessions configured per-route, or nil if no route has a session
+// persistence policy.
+func FilterStatefulSession(vhosts ...*dag.VirtualHost) *envoy_filter_network_http_connection_manager_v3.HttpFilter {
+	for _, vh := range vhosts {
+		for _, route := range vh.Routes {
+			if route.SessionPersistence != nil {
+				return &envoy_filter_network_http_connection_manager_v3.HttpFilter{
+					Name: StatefulSessionFilterName,
+					ConfigType: &envoy_filter_network_http_connection_manager_v3.HttpFilter_TypedConfig{
+						// Without a session state the filter is disabled,
+						// so sessions are only kept by the per-route configuration.
+						TypedConfig: protobuf.MustMarshalAny(&envoy_filter_http_stateful_session_v3.StatefulSession{}),
+					},
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func FilterMisdirectedRequests(fqdn string) *envoy_filter_network_http_connection_manager_v3.HttpFilter {
 	var target string
 
@@ -819,6 +1141,21 @@ func FilterExternalAuthz(externalAuthorization *dag.ExternalAuthorization) *envo
 		}
 	}
 
+	authConfig.AllowedHeaders = headerNameListMatcher(externalAuthorization.AuthorizationRequestAllowedHeaders)
+	authConfig.DisallowedHeaders = headerNameListMatcher(externalAuthorization.AuthorizationRequestDisallowedHeaders)
+
+	if len(externalAuthorization.AuthorizationResponseDisallowedHeaders) > 0 {
+		var quoted []string
+		for _, name := range externalAuthorization.AuthorizationResponseDisallowedHeaders {
+			quoted = append(quoted, regexp.QuoteMeta(name))
+		}
+		authConfig.DecoderHeaderMutationRules = &envoy_config_common_mutation_rules_v3.HeaderMutationRules{
+			DisallowExpression: &envoy_matcher_v3.RegexMatcher{
+				Regex: "^(?i)(" + strings.Join(quoted, "|") + ")$",
+			},
+		}
+	}
+
 	return &envoy_filter_network_http_connection_manager_v3.HttpFilter{
 		Name: ExtAuthzFilterName,
 		ConfigType: &envoy_filter_network_http_connection_manager_v3.HttpFilter_TypedConfig{
@@ -827,6 +1164,23 @@ func FilterExternalAuthz(externalAuthorization *dag.ExternalAuthorization) *envo
 	}
 }
 
+// headerNameListMatcher returns a case-insensitive matcher for the
+// given header names, or nil if there are none.
+func headerNameListMatcher(names []string) *envoy_matcher_v3.ListStringMatcher {
+	if len(names) == 0 {
+		return nil
+	}
+
+	matcher := &envoy_matcher_v3.ListStringMatcher{}
+	for _, name := range names {
+		matcher.Patterns = append(matcher.Patterns, &envoy_matcher_v3.StringMatcher{
+			MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{Exact: name},
+			IgnoreCase:   true,
+		})
+	}
+	return matcher
+}
+
 // FilterJWTAuthN returns a `jwt_authn` filter configured with the
 // requested parameters.
 func FilterJWTAuthN(jwtProviders []dag.JWTProvider) *envoy_filter_network_http_connection_manager_v3.HttpFilter {
@@ -862,6 +1216,10 @@ func FilterJWTAuthN(jwtProviders []dag.JWTProvider) *envoy_filter_network_http_c
 				},
 			},
 			Forward: provider.ForwardJWT,
+			// Write the verified JWT payload to dynamic metadata so that
+			// other filters, e.g. global rate limit descriptors, can use
+			// its claims.
+			PayloadInMetadata: JWTPayloadMetadataKey,
 		}
 
 		// Set up a requirement map so that per-route filter config can refer
@@ -911,6 +1269,28 @@ func FilterChainTLS(domain string, downstream *envoy_transport_socket_tls_v3.Dow
 	return fc
 }
 
+// FilterChainQUIC returns a QUIC enabled envoy_config_listener_v3.FilterChain.
+func FilterChainQUIC(domain string, downstream *envoy_transport_socket_tls_v3.DownstreamTlsContext, filters []*envoy_config_listener_v3.Filter) *envoy_config_listener_v3.FilterChain {
+	fc := &envoy_config_listener_v3.FilterChain{
+		Filters:         filters,
+		TransportSocket: DownstreamQUICTransportSocket(downstream),
+	}
+
+	// As with TLS, a wildcard domain can't be matched on SNI, so
+	// match any QUIC connection to this listener instead.
+	if domain == "*" {
+		fc.FilterChainMatch = &envoy_config_listener_v3.FilterChainMatch{
+			TransportProtocol: "quic",
+		}
+	} else {
+		fc.FilterChainMatch = &envoy_config_listener_v3.FilterChainMatch{
+			ServerNames: []string{domain},
+		}
+	}
+
+	return fc
+}
+
 // FilterChainTLSFallback returns a TLS enabled envoy_config_listener_v3.FilterChain configured for FallbackCertificate.
 func FilterChainTLSFallback(downstream *envoy_transport_socket_tls_v3.DownstreamTlsContext, filters []*envoy_config_listener_v3.Filter) *envoy_config_listener_v3.FilterChain {
 	fc := &envoy_config_listener_v3.FilterChain{
@@ -23,12 +23,15 @@ import (
 	envoy_compression_gzip_compressor_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/compression/gzip/compressor/v3"
 	envoy_filter_http_compressor_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/compressor/v3"
 	envoy_filter_http_cors_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/cors/v3"
+	envoy_filter_http_fault_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/fault/v3"
 	envoy_filter_http_grpc_stats_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/grpc_stats/v3"
 	envoy_filter_http_grpc_web_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/grpc_web/v3"
 	envoy_filter_http_local_ratelimit_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/local_ratelimit/v3"
 	envoy_filter_http_lua_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/lua/v3"
 	envoy_filter_http_rbac_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/rbac/v3"
 	envoy_filter_http_router_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/router/v3"
+	envoy_filter_http_stateful_session_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/stateful_session/v3"
+	envoy_filter_listener_proxy_protocol_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/listener/proxy_protocol/v3"
 	envoy_filter_network_http_connection_manager_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	envoy_filter_network_tcp_proxy_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
 	envoy_transport_socket_tls_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
@@ -37,6 +40,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 	core_v1 "k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -107,7 +111,7 @@ func TestListener(t *testing.T) {
 			address: "0.0.0.0",
 			port:    9000,
 			lf: []*envoy_config_listener_v3.ListenerFilter{
-				ProxyProtocol(),
+				ProxyProtocol(false, nil),
 			},
 			f: []*envoy_config_listener_v3.Filter{
 				HTTPConnectionManager("http-proxy", FileAccessLogEnvoy("/dev/null", "", nil, contour_v1alpha1.LogLevelInfo), 0),
@@ -116,7 +120,7 @@ func TestListener(t *testing.T) {
 				Name:    "http-proxy",
 				Address: SocketAddress("0.0.0.0", 9000),
 				ListenerFilters: ListenerFilters(
-					ProxyProtocol(),
+					ProxyProtocol(false, nil),
 				),
 				FilterChains: FilterChains(
 					HTTPConnectionManager("http-proxy", FileAccessLogEnvoy("/dev/null", "", nil, contour_v1alpha1.LogLevelInfo), 0),
@@ -145,14 +149,14 @@ func TestListener(t *testing.T) {
 			address: "0.0.0.0",
 			port:    9000,
 			lf: ListenerFilters(
-				ProxyProtocol(),
+				ProxyProtocol(false, nil),
 				TLSInspector(),
 			),
 			want: &envoy_config_listener_v3.Listener{
 				Name:    "https-proxy",
 				Address: SocketAddress("0.0.0.0", 9000),
 				ListenerFilters: ListenerFilters(
-					ProxyProtocol(),
+					ProxyProtocol(false, nil),
 					TLSInspector(),
 				),
 				SocketOptions: NewSocketOptions().TCPKeepalive().Build(),
@@ -385,6 +389,10 @@ func TestDownstreamTLSContext(t *testing.T) {
 		},
 		OptionalClientCertificate: true,
 	}
+	peerValidationContextOptionalClientCertSkipValidation := &dag.PeerValidationContext{
+		SkipClientCertValidation:  true,
+		OptionalClientCertificate: true,
+	}
 	peerValidationContextWithCRLCheck := &dag.PeerValidationContext{
 		CACertificates: []*dag.Secret{
 			{
@@ -543,6 +551,18 @@ func TestDownstreamTLSContext(t *testing.T) {
 				RequireClientCertificate: wrapperspb.Bool(false),
 			},
 		},
+		"optional client cert without validation": {
+			DownstreamTLSContext(serverSecret, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, cipherSuites, peerValidationContextOptionalClientCertSkipValidation, "h2", "http/1.1"),
+			&envoy_transport_socket_tls_v3.DownstreamTlsContext{
+				CommonTlsContext: &envoy_transport_socket_tls_v3.CommonTlsContext{
+					TlsParams:                      tlsParams,
+					TlsCertificateSdsSecretConfigs: tlsCertificateSdsSecretConfigs,
+					AlpnProtocols:                  alpnProtocols,
+					ValidationContextType:          validationContextSkipVerify,
+				},
+				RequireClientCertificate: wrapperspb.Bool(false),
+			},
+		},
 		"Downstream validation with CRL check": {
 			DownstreamTLSContext(serverSecret, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, cipherSuites, peerValidationContextWithCRLCheck, "h2", "http/1.1"),
 			&envoy_transport_socket_tls_v3.DownstreamTlsContext{
@@ -659,11 +679,22 @@ func TestHTTPConnectionManager(t *testing.T) {
 		connectionShutdownGracePeriod timeout.Setting
 		allowChunkedLength            bool
 		mergeSlashes                  bool
+		stripAnyHostPort              bool
+		stripMatchingHostPort         bool
 		serverHeaderTranformation     contour_v1alpha1.ServerHeaderTransformationType
+		serverName                    string
+		useRemoteAddress              *bool
+		via                           string
 		forwardClientCertificate      *dag.ClientCertificateDetails
 		xffNumTrustedHops             uint32
 		maxRequestsPerConnection      *uint32
 		http2MaxConcurrentStreams     *uint32
+		http2KeepaliveInterval        timeout.Setting
+		http2KeepaliveTimeout         timeout.Setting
+		allowAbsoluteURL              *bool
+		disableAcceptHTTP10           bool
+		http10DefaultHost             string
+		deltaXDS                      bool
 		want                          *envoy_config_listener_v3.Filter
 	}{
 		"default": {
@@ -1146,6 +1177,102 @@ func TestHTTPConnectionManager(t *testing.T) {
 				},
 			},
 		},
+		"enable strip any host port": {
+			routename:        "default/kuard",
+			accesslogger:     FileAccessLogEnvoy("/dev/stdout", "", nil, contour_v1alpha1.LogLevelInfo),
+			stripAnyHostPort: true,
+			want: &envoy_config_listener_v3.Filter{
+				Name: wellknown.HTTPConnectionManager,
+				ConfigType: &envoy_config_listener_v3.Filter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&envoy_filter_network_http_connection_manager_v3.HttpConnectionManager{
+						StatPrefix: "default/kuard",
+						RouteSpecifier: &envoy_filter_network_http_connection_manager_v3.HttpConnectionManager_Rds{
+							Rds: &envoy_filter_network_http_connection_manager_v3.Rds{
+								RouteConfigName: "default/kuard",
+								ConfigSource: &envoy_config_core_v3.ConfigSource{
+									ResourceApiVersion: envoy_config_core_v3.ApiVersion_V3,
+									ConfigSourceSpecifier: &envoy_config_core_v3.ConfigSource_ApiConfigSource{
+										ApiConfigSource: &envoy_config_core_v3.ApiConfigSource{
+											ApiType:             envoy_config_core_v3.ApiConfigSource_GRPC,
+											TransportApiVersion: envoy_config_core_v3.ApiVersion_V3,
+											GrpcServices: []*envoy_config_core_v3.GrpcService{{
+												TargetSpecifier: &envoy_config_core_v3.GrpcService_EnvoyGrpc_{
+													EnvoyGrpc: &envoy_config_core_v3.GrpcService_EnvoyGrpc{
+														ClusterName: "contour",
+														Authority:   "contour",
+													},
+												},
+											}},
+										},
+									},
+								},
+							},
+						},
+						HttpFilters: defaultHTTPFilters,
+						HttpProtocolOptions: &envoy_config_core_v3.Http1ProtocolOptions{
+							// Enable support for HTTP/1.0 requests that carry
+							// a Host: header. See #537.
+							AcceptHttp_10: true,
+						},
+						CommonHttpProtocolOptions: &envoy_config_core_v3.HttpProtocolOptions{},
+						AccessLog:                 FileAccessLogEnvoy("/dev/stdout", "", nil, contour_v1alpha1.LogLevelInfo),
+						UseRemoteAddress:          wrapperspb.Bool(true),
+						NormalizePath:             wrapperspb.Bool(true),
+						PreserveExternalRequestId: true,
+						StripPortMode: &envoy_filter_network_http_connection_manager_v3.HttpConnectionManager_StripAnyHostPort{
+							StripAnyHostPort: true,
+						},
+					}),
+				},
+			},
+		},
+		"enable strip matching host port": {
+			routename:             "default/kuard",
+			accesslogger:          FileAccessLogEnvoy("/dev/stdout", "", nil, contour_v1alpha1.LogLevelInfo),
+			stripMatchingHostPort: true,
+			want: &envoy_config_listener_v3.Filter{
+				Name: wellknown.HTTPConnectionManager,
+				ConfigType: &envoy_config_listener_v3.Filter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&envoy_filter_network_http_connection_manager_v3.HttpConnectionManager{
+						StatPrefix: "default/kuard",
+						RouteSpecifier: &envoy_filter_network_http_connection_manager_v3.HttpConnectionManager_Rds{
+							Rds: &envoy_filter_network_http_connection_manager_v3.Rds{
+								RouteConfigName: "default/kuard",
+								ConfigSource: &envoy_config_core_v3.ConfigSource{
+									ResourceApiVersion: envoy_config_core_v3.ApiVersion_V3,
+									ConfigSourceSpecifier: &envoy_config_core_v3.ConfigSource_ApiConfigSource{
+										ApiConfigSource: &envoy_config_core_v3.ApiConfigSource{
+											ApiType:             envoy_config_core_v3.ApiConfigSource_GRPC,
+											TransportApiVersion: envoy_config_core_v3.ApiVersion_V3,
+											GrpcServices: []*envoy_config_core_v3.GrpcService{{
+												TargetSpecifier: &envoy_config_core_v3.GrpcService_EnvoyGrpc_{
+													EnvoyGrpc: &envoy_config_core_v3.GrpcService_EnvoyGrpc{
+														ClusterName: "contour",
+														Authority:   "contour",
+													},
+												},
+											}},
+										},
+									},
+								},
+							},
+						},
+						HttpFilters: defaultHTTPFilters,
+						HttpProtocolOptions: &envoy_config_core_v3.Http1ProtocolOptions{
+							// Enable support for HTTP/1.0 requests that carry
+							// a Host: header. See #537.
+							AcceptHttp_10: true,
+						},
+						CommonHttpProtocolOptions: &envoy_config_core_v3.HttpProtocolOptions{},
+						AccessLog:                 FileAccessLogEnvoy("/dev/stdout", "", nil, contour_v1alpha1.LogLevelInfo),
+						UseRemoteAddress:          wrapperspb.Bool(true),
+						NormalizePath:             wrapperspb.Bool(true),
+						PreserveExternalRequestId: true,
+						StripMatchingHostPort:     true,
+					}),
+				},
+			},
+		},
 		"server header transform set to pass through": {
 			routename:                 "default/kuard",
 			accesslogger:              FileAccessLogEnvoy("/dev/stdout", "", nil, contour_v1alpha1.LogLevelInfo),
@@ -1193,6 +1320,101 @@ func TestHTTPConnectionManager(t *testing.T) {
 				},
 			},
 		},
+		"server name set": {
+			routename:    "default/kuard",
+			accesslogger: FileAccessLogEnvoy("/dev/stdout", "", nil, contour_v1alpha1.LogLevelInfo),
+			serverName:   "example",
+			want: &envoy_config_listener_v3.Filter{
+				Name: wellknown.HTTPConnectionManager,
+				ConfigType: &envoy_config_listener_v3.Filter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&envoy_filter_network_http_connection_manager_v3.HttpConnectionManager{
+						StatPrefix: "default/kuard",
+						RouteSpecifier: &envoy_filter_network_http_connection_manager_v3.HttpConnectionManager_Rds{
+							Rds: &envoy_filter_network_http_connection_manager_v3.Rds{
+								RouteConfigName: "default/kuard",
+								ConfigSource: &envoy_config_core_v3.ConfigSource{
+									ResourceApiVersion: envoy_config_core_v3.ApiVersion_V3,
+									ConfigSourceSpecifier: &envoy_config_core_v3.ConfigSource_ApiConfigSource{
+										ApiConfigSource: &envoy_config_core_v3.ApiConfigSource{
+											ApiType:             envoy_config_core_v3.ApiConfigSource_GRPC,
+											TransportApiVersion: envoy_config_core_v3.ApiVersion_V3,
+											GrpcServices: []*envoy_config_core_v3.GrpcService{{
+												TargetSpecifier: &envoy_config_core_v3.GrpcService_EnvoyGrpc_{
+													EnvoyGrpc: &envoy_config_core_v3.GrpcService_EnvoyGrpc{
+														ClusterName: "contour",
+														Authority:   "contour",
+													},
+												},
+											}},
+										},
+									},
+								},
+							},
+						},
+						HttpFilters: defaultHTTPFilters,
+						HttpProtocolOptions: &envoy_config_core_v3.Http1ProtocolOptions{
+							// Enable support for HTTP/1.0 requests that carry
+							// a Host: header. See #537.
+							AcceptHttp_10: true,
+						},
+						CommonHttpProtocolOptions: &envoy_config_core_v3.HttpProtocolOptions{},
+						AccessLog:                 FileAccessLogEnvoy("/dev/stdout", "", nil, contour_v1alpha1.LogLevelInfo),
+						UseRemoteAddress:          wrapperspb.Bool(true),
+						NormalizePath:             wrapperspb.Bool(true),
+						PreserveExternalRequestId: true,
+						ServerName:                "example",
+					}),
+				},
+			},
+		},
+		"use remote address disabled and via set": {
+			routename:        "default/kuard",
+			accesslogger:     FileAccessLogEnvoy("/dev/stdout", "", nil, contour_v1alpha1.LogLevelInfo),
+			useRemoteAddress: ptr.To(false),
+			via:              "1.1 contour",
+			want: &envoy_config_listener_v3.Filter{
+				Name: wellknown.HTTPConnectionManager,
+				ConfigType: &envoy_config_listener_v3.Filter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&envoy_filter_network_http_connection_manager_v3.HttpConnectionManager{
+						StatPrefix: "default/kuard",
+						RouteSpecifier: &envoy_filter_network_http_connection_manager_v3.HttpConnectionManager_Rds{
+							Rds: &envoy_filter_network_http_connection_manager_v3.Rds{
+								RouteConfigName: "default/kuard",
+								ConfigSource: &envoy_config_core_v3.ConfigSource{
+									ResourceApiVersion: envoy_config_core_v3.ApiVersion_V3,
+									ConfigSourceSpecifier: &envoy_config_core_v3.ConfigSource_ApiConfigSource{
+										ApiConfigSource: &envoy_config_core_v3.ApiConfigSource{
+											ApiType:             envoy_config_core_v3.ApiConfigSource_GRPC,
+											TransportApiVersion: envoy_config_core_v3.ApiVersion_V3,
+											GrpcServices: []*envoy_config_core_v3.GrpcService{{
+												TargetSpecifier: &envoy_config_core_v3.GrpcService_EnvoyGrpc_{
+													EnvoyGrpc: &envoy_config_core_v3.GrpcService_EnvoyGrpc{
+														ClusterName: "contour",
+														Authority:   "contour",
+													},
+												},
+											}},
+										},
+									},
+								},
+							},
+						},
+						HttpFilters: defaultHTTPFilters,
+						HttpProtocolOptions: &envoy_config_core_v3.Http1ProtocolOptions{
+							// Enable support for HTTP/1.0 requests that carry
+							// a Host: header. See #537.
+							AcceptHttp_10: true,
+						},
+						CommonHttpProtocolOptions: &envoy_config_core_v3.HttpProtocolOptions{},
+						AccessLog:                 FileAccessLogEnvoy("/dev/stdout", "", nil, contour_v1alpha1.LogLevelInfo),
+						UseRemoteAddress:          wrapperspb.Bool(false),
+						NormalizePath:             wrapperspb.Bool(true),
+						PreserveExternalRequestId: true,
+						Via:                       "1.1 contour",
+					}),
+				},
+			},
+		},
 		"enable xfcc": {
 			routename:    "default/kuard",
 			accesslogger: FileAccessLogEnvoy("/dev/stdout", "", nil, contour_v1alpha1.LogLevelInfo),
@@ -1458,6 +1680,226 @@ func TestHTTPConnectionManager(t *testing.T) {
 				},
 			},
 		},
+		"http2 keepalive set": {
+			routename:              "default/kuard",
+			accesslogger:           FileAccessLogEnvoy("/dev/stdout", "", nil, contour_v1alpha1.LogLevelInfo),
+			http2KeepaliveInterval: timeout.DurationSetting(30 * time.Second),
+			http2KeepaliveTimeout:  timeout.DurationSetting(5 * time.Second),
+			want: &envoy_config_listener_v3.Filter{
+				Name: wellknown.HTTPConnectionManager,
+				ConfigType: &envoy_config_listener_v3.Filter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&envoy_filter_network_http_connection_manager_v3.HttpConnectionManager{
+						StatPrefix: "default/kuard",
+						RouteSpecifier: &envoy_filter_network_http_connection_manager_v3.HttpConnectionManager_Rds{
+							Rds: &envoy_filter_network_http_connection_manager_v3.Rds{
+								RouteConfigName: "default/kuard",
+								ConfigSource: &envoy_config_core_v3.ConfigSource{
+									ResourceApiVersion: envoy_config_core_v3.ApiVersion_V3,
+									ConfigSourceSpecifier: &envoy_config_core_v3.ConfigSource_ApiConfigSource{
+										ApiConfigSource: &envoy_config_core_v3.ApiConfigSource{
+											ApiType:             envoy_config_core_v3.ApiConfigSource_GRPC,
+											TransportApiVersion: envoy_config_core_v3.ApiVersion_V3,
+											GrpcServices: []*envoy_config_core_v3.GrpcService{{
+												TargetSpecifier: &envoy_config_core_v3.GrpcService_EnvoyGrpc_{
+													EnvoyGrpc: &envoy_config_core_v3.GrpcService_EnvoyGrpc{
+														ClusterName: "contour",
+														Authority:   "contour",
+													},
+												},
+											}},
+										},
+									},
+								},
+							},
+						},
+						HttpFilters: defaultHTTPFilters,
+						HttpProtocolOptions: &envoy_config_core_v3.Http1ProtocolOptions{
+							// Enable support for HTTP/1.0 requests that carry
+							// a Host: header. See #537.
+							AcceptHttp_10: true,
+						},
+						CommonHttpProtocolOptions: &envoy_config_core_v3.HttpProtocolOptions{},
+						Http2ProtocolOptions: &envoy_config_core_v3.Http2ProtocolOptions{
+							ConnectionKeepalive: &envoy_config_core_v3.KeepaliveSettings{
+								Interval: durationpb.New(30 * time.Second),
+								Timeout:  durationpb.New(5 * time.Second),
+							},
+						},
+						AccessLog:                 FileAccessLogEnvoy("/dev/stdout", "", nil, contour_v1alpha1.LogLevelInfo),
+						UseRemoteAddress:          wrapperspb.Bool(true),
+						NormalizePath:             wrapperspb.Bool(true),
+						PreserveExternalRequestId: true,
+						MergeSlashes:              false,
+					}),
+				},
+			},
+		},
+		"delta xds": {
+			routename:    "default/kuard",
+			accesslogger: FileAccessLogEnvoy("/dev/stdout", "", nil, contour_v1alpha1.LogLevelInfo),
+			deltaXDS:     true,
+			want: &envoy_config_listener_v3.Filter{
+				Name: wellknown.HTTPConnectionManager,
+				ConfigType: &envoy_config_listener_v3.Filter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&envoy_filter_network_http_connection_manager_v3.HttpConnectionManager{
+						StatPrefix: "default/kuard",
+						RouteSpecifier: &envoy_filter_network_http_connection_manager_v3.HttpConnectionManager_Rds{
+							Rds: &envoy_filter_network_http_connection_manager_v3.Rds{
+								RouteConfigName: "default/kuard",
+								ConfigSource:    DeltaConfigSource("contour"),
+							},
+						},
+						HttpFilters: defaultHTTPFilters,
+						HttpProtocolOptions: &envoy_config_core_v3.Http1ProtocolOptions{
+							// Enable support for HTTP/1.0 requests that carry
+							// a Host: header. See #537.
+							AcceptHttp_10: true,
+						},
+						CommonHttpProtocolOptions: &envoy_config_core_v3.HttpProtocolOptions{},
+						AccessLog:                 FileAccessLogEnvoy("/dev/stdout", "", nil, contour_v1alpha1.LogLevelInfo),
+						UseRemoteAddress:          wrapperspb.Bool(true),
+						NormalizePath:             wrapperspb.Bool(true),
+						PreserveExternalRequestId: true,
+						MergeSlashes:              false,
+					}),
+				},
+			},
+		},
+		"allow absolute url set": {
+			routename:        "default/kuard",
+			accesslogger:     FileAccessLogEnvoy("/dev/stdout", "", nil, contour_v1alpha1.LogLevelInfo),
+			allowAbsoluteURL: ptr.To(true),
+			want: &envoy_config_listener_v3.Filter{
+				Name: wellknown.HTTPConnectionManager,
+				ConfigType: &envoy_config_listener_v3.Filter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&envoy_filter_network_http_connection_manager_v3.HttpConnectionManager{
+						StatPrefix: "default/kuard",
+						RouteSpecifier: &envoy_filter_network_http_connection_manager_v3.HttpConnectionManager_Rds{
+							Rds: &envoy_filter_network_http_connection_manager_v3.Rds{
+								RouteConfigName: "default/kuard",
+								ConfigSource: &envoy_config_core_v3.ConfigSource{
+									ResourceApiVersion: envoy_config_core_v3.ApiVersion_V3,
+									ConfigSourceSpecifier: &envoy_config_core_v3.ConfigSource_ApiConfigSource{
+										ApiConfigSource: &envoy_config_core_v3.ApiConfigSource{
+											ApiType:             envoy_config_core_v3.ApiConfigSource_GRPC,
+											TransportApiVersion: envoy_config_core_v3.ApiVersion_V3,
+											GrpcServices: []*envoy_config_core_v3.GrpcService{{
+												TargetSpecifier: &envoy_config_core_v3.GrpcService_EnvoyGrpc_{
+													EnvoyGrpc: &envoy_config_core_v3.GrpcService_EnvoyGrpc{
+														ClusterName: "contour",
+														Authority:   "contour",
+													},
+												},
+											}},
+										},
+									},
+								},
+							},
+						},
+						HttpFilters: defaultHTTPFilters,
+						HttpProtocolOptions: &envoy_config_core_v3.Http1ProtocolOptions{
+							AcceptHttp_10:    true,
+							AllowAbsoluteUrl: wrapperspb.Bool(true),
+						},
+						CommonHttpProtocolOptions: &envoy_config_core_v3.HttpProtocolOptions{},
+						AccessLog:                 FileAccessLogEnvoy("/dev/stdout", "", nil, contour_v1alpha1.LogLevelInfo),
+						UseRemoteAddress:          wrapperspb.Bool(true),
+						NormalizePath:             wrapperspb.Bool(true),
+						PreserveExternalRequestId: true,
+						MergeSlashes:              false,
+					}),
+				},
+			},
+		},
+		"http/1.0 default host set": {
+			routename:         "default/kuard",
+			accesslogger:      FileAccessLogEnvoy("/dev/stdout", "", nil, contour_v1alpha1.LogLevelInfo),
+			http10DefaultHost: "example.com",
+			want: &envoy_config_listener_v3.Filter{
+				Name: wellknown.HTTPConnectionManager,
+				ConfigType: &envoy_config_listener_v3.Filter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&envoy_filter_network_http_connection_manager_v3.HttpConnectionManager{
+						StatPrefix: "default/kuard",
+						RouteSpecifier: &envoy_filter_network_http_connection_manager_v3.HttpConnectionManager_Rds{
+							Rds: &envoy_filter_network_http_connection_manager_v3.Rds{
+								RouteConfigName: "default/kuard",
+								ConfigSource: &envoy_config_core_v3.ConfigSource{
+									ResourceApiVersion: envoy_config_core_v3.ApiVersion_V3,
+									ConfigSourceSpecifier: &envoy_config_core_v3.ConfigSource_ApiConfigSource{
+										ApiConfigSource: &envoy_config_core_v3.ApiConfigSource{
+											ApiType:             envoy_config_core_v3.ApiConfigSource_GRPC,
+											TransportApiVersion: envoy_config_core_v3.ApiVersion_V3,
+											GrpcServices: []*envoy_config_core_v3.GrpcService{{
+												TargetSpecifier: &envoy_config_core_v3.GrpcService_EnvoyGrpc_{
+													EnvoyGrpc: &envoy_config_core_v3.GrpcService_EnvoyGrpc{
+														ClusterName: "contour",
+														Authority:   "contour",
+													},
+												},
+											}},
+										},
+									},
+								},
+							},
+						},
+						HttpFilters: defaultHTTPFilters,
+						HttpProtocolOptions: &envoy_config_core_v3.Http1ProtocolOptions{
+							AcceptHttp_10:         true,
+							DefaultHostForHttp_10: "example.com",
+						},
+						CommonHttpProtocolOptions: &envoy_config_core_v3.HttpProtocolOptions{},
+						AccessLog:                 FileAccessLogEnvoy("/dev/stdout", "", nil, contour_v1alpha1.LogLevelInfo),
+						UseRemoteAddress:          wrapperspb.Bool(true),
+						NormalizePath:             wrapperspb.Bool(true),
+						PreserveExternalRequestId: true,
+						MergeSlashes:              false,
+					}),
+				},
+			},
+		},
+		"http/1.0 disabled": {
+			routename:           "default/kuard",
+			accesslogger:        FileAccessLogEnvoy("/dev/stdout", "", nil, contour_v1alpha1.LogLevelInfo),
+			disableAcceptHTTP10: true,
+			want: &envoy_config_listener_v3.Filter{
+				Name: wellknown.HTTPConnectionManager,
+				ConfigType: &envoy_config_listener_v3.Filter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&envoy_filter_network_http_connection_manager_v3.HttpConnectionManager{
+						StatPrefix: "default/kuard",
+						RouteSpecifier: &envoy_filter_network_http_connection_manager_v3.HttpConnectionManager_Rds{
+							Rds: &envoy_filter_network_http_connection_manager_v3.Rds{
+								RouteConfigName: "default/kuard",
+								ConfigSource: &envoy_config_core_v3.ConfigSource{
+									ResourceApiVersion: envoy_config_core_v3.ApiVersion_V3,
+									ConfigSourceSpecifier: &envoy_config_core_v3.ConfigSource_ApiConfigSource{
+										ApiConfigSource: &envoy_config_core_v3.ApiConfigSource{
+											ApiType:             envoy_config_core_v3.ApiConfigSource_GRPC,
+											TransportApiVersion: envoy_config_core_v3.ApiVersion_V3,
+											GrpcServices: []*envoy_config_core_v3.GrpcService{{
+												TargetSpecifier: &envoy_config_core_v3.GrpcService_EnvoyGrpc_{
+													EnvoyGrpc: &envoy_config_core_v3.GrpcService_EnvoyGrpc{
+														ClusterName: "contour",
+														Authority:   "contour",
+													},
+												},
+											}},
+										},
+									},
+								},
+							},
+						},
+						HttpFilters:               defaultHTTPFilters,
+						HttpProtocolOptions:       &envoy_config_core_v3.Http1ProtocolOptions{},
+						CommonHttpProtocolOptions: &envoy_config_core_v3.HttpProtocolOptions{},
+						AccessLog:                 FileAccessLogEnvoy("/dev/stdout", "", nil, contour_v1alpha1.LogLevelInfo),
+						UseRemoteAddress:          wrapperspb.Bool(true),
+						NormalizePath:             wrapperspb.Bool(true),
+						PreserveExternalRequestId: true,
+						MergeSlashes:              false,
+					}),
+				},
+			},
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -1473,11 +1915,21 @@ func TestHTTPConnectionManager(t *testing.T) {
 				ConnectionShutdownGracePeriod(tc.connectionShutdownGracePeriod).
 				AllowChunkedLength(tc.allowChunkedLength).
 				MergeSlashes(tc.mergeSlashes).
+				StripAnyHostPort(tc.stripAnyHostPort).
+				StripMatchingHostPort(tc.stripMatchingHostPort).
 				ServerHeaderTransformation(tc.serverHeaderTranformation).
+				ServerName(tc.serverName).
+				UseRemoteAddress(tc.useRemoteAddress).
+				Via(tc.via).
 				NumTrustedHops(tc.xffNumTrustedHops).
 				ForwardClientCertificate(tc.forwardClientCertificate).
 				MaxRequestsPerConnection(tc.maxRequestsPerConnection).
 				HTTP2MaxConcurrentStreams(tc.http2MaxConcurrentStreams).
+				HTTP2Keepalive(tc.http2KeepaliveInterval, tc.http2KeepaliveTimeout).
+				AllowAbsoluteURL(tc.allowAbsoluteURL).
+				DisableAcceptHTTP10(tc.disableAcceptHTTP10).
+				HTTP10DefaultHost(tc.http10DefaultHost).
+				DeltaXDS(tc.deltaXDS).
 				DefaultFilters().
 				Get()
 
@@ -1486,6 +1938,46 @@ func TestHTTPConnectionManager(t *testing.T) {
 	}
 }
 
+func TestProxyProtocol(t *testing.T) {
+	tests := map[string]struct {
+		allowRequestsWithoutProxyProtocol bool
+		versions                          []string
+		want                              *envoy_filter_listener_proxy_protocol_v3.ProxyProtocol
+	}{
+		"defaults": {
+			want: &envoy_filter_listener_proxy_protocol_v3.ProxyProtocol{},
+		},
+		"allow requests without proxy protocol": {
+			allowRequestsWithoutProxyProtocol: true,
+			want: &envoy_filter_listener_proxy_protocol_v3.ProxyProtocol{
+				AllowRequestsWithoutProxyProtocol: true,
+			},
+		},
+		"v2 only": {
+			versions: []string{"v2"},
+			want: &envoy_filter_listener_proxy_protocol_v3.ProxyProtocol{
+				DisallowedVersions: []envoy_config_core_v3.ProxyProtocolConfig_Version{envoy_config_core_v3.ProxyProtocolConfig_V1},
+			},
+		},
+		"all versions": {
+			versions: []string{"v1", "v2"},
+			want:     &envoy_filter_listener_proxy_protocol_v3.ProxyProtocol{},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			want := &envoy_config_listener_v3.ListenerFilter{
+				Name: wellknown.ProxyProtocol,
+				ConfigType: &envoy_config_listener_v3.ListenerFilter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(tc.want),
+				},
+			}
+			protobuf.ExpectEqual(t, want, ProxyProtocol(tc.allowRequestsWithoutProxyProtocol, tc.versions))
+		})
+	}
+}
+
 func TestTCPProxy(t *testing.T) {
 	const (
 		statPrefix    = "ingress_https"
@@ -1575,6 +2067,65 @@ func TestTCPProxy(t *testing.T) {
 				},
 			},
 		},
+		"failover clusters": {
+			proxy: &dag.TCPProxy{
+				Clusters: []*dag.Cluster{c1, c4},
+				Failover: true,
+			},
+			want: &envoy_config_listener_v3.Filter{
+				Name: wellknown.TCPProxy,
+				ConfigType: &envoy_config_listener_v3.Filter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&envoy_filter_network_tcp_proxy_v3.TcpProxy{
+						StatPrefix: statPrefix,
+						ClusterSpecifier: &envoy_filter_network_tcp_proxy_v3.TcpProxy_Cluster{
+							Cluster: envoy.FailoverClustername(&dag.TCPProxy{Clusters: []*dag.Cluster{c1, c4}}),
+						},
+						AccessLog:   FileAccessLogEnvoy(accessLogPath, "", nil, contour_v1alpha1.LogLevelInfo),
+						IdleTimeout: durationpb.New(9001 * time.Second),
+					}),
+				},
+			},
+		},
+		"single cluster with idle timeout and max connect attempts": {
+			proxy: &dag.TCPProxy{
+				Clusters:           []*dag.Cluster{c1},
+				IdleTimeout:        timeout.DurationSetting(5 * time.Minute),
+				MaxConnectAttempts: 3,
+			},
+			want: &envoy_config_listener_v3.Filter{
+				Name: wellknown.TCPProxy,
+				ConfigType: &envoy_config_listener_v3.Filter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&envoy_filter_network_tcp_proxy_v3.TcpProxy{
+						StatPrefix: statPrefix,
+						ClusterSpecifier: &envoy_filter_network_tcp_proxy_v3.TcpProxy_Cluster{
+							Cluster: envoy.Clustername(c1),
+						},
+						AccessLog:          FileAccessLogEnvoy(accessLogPath, "", nil, contour_v1alpha1.LogLevelInfo),
+						IdleTimeout:        durationpb.New(5 * time.Minute),
+						MaxConnectAttempts: wrapperspb.UInt32(3),
+					}),
+				},
+			},
+		},
+		"single cluster with idle timeout disabled": {
+			proxy: &dag.TCPProxy{
+				Clusters:    []*dag.Cluster{c1},
+				IdleTimeout: timeout.DisabledSetting(),
+			},
+			want: &envoy_config_listener_v3.Filter{
+				Name: wellknown.TCPProxy,
+				ConfigType: &envoy_config_listener_v3.Filter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&envoy_filter_network_tcp_proxy_v3.TcpProxy{
+						StatPrefix: statPrefix,
+						ClusterSpecifier: &envoy_filter_network_tcp_proxy_v3.TcpProxy_Cluster{
+							Cluster: envoy.Clustername(c1),
+						},
+						AccessLog:   FileAccessLogEnvoy(accessLogPath, "", nil, contour_v1alpha1.LogLevelInfo),
+						IdleTimeout: durationpb.New(0),
+					}),
+				},
+			},
+		},
 		"three clusters, one has no weight specified": {
 			proxy: &dag.TCPProxy{
 				Clusters: []*dag.Cluster{c1, c2, c3},
@@ -1932,3 +2483,159 @@ func authzFilter(extras ...any) *envoy_filter_network_http_connection_manager_v3
 		AuthorizationServerWithRequestBody: body,
 	})
 }
+
+func TestFilterFault(t *testing.T) {
+	plain := &dag.VirtualHost{
+		Name: "www.example.com",
+		Routes: map[string]*dag.Route{
+			"/": {PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/"}},
+		},
+	}
+	faulty := &dag.VirtualHost{
+		Name: "chaos.example.com",
+		Routes: map[string]*dag.Route{
+			"/": {
+				PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/"},
+				FaultInjectionPolicy: &dag.FaultInjectionPolicy{
+					Abort: &dag.FaultAbort{Percentage: 10, StatusCode: 503},
+				},
+			},
+		},
+	}
+
+	assert.Nil(t, FilterFault())
+	assert.Nil(t, FilterFault(plain))
+	protobuf.ExpectEqual(t, &envoy_filter_network_http_connection_manager_v3.HttpFilter{
+		Name: FaultFilterName,
+		ConfigType: &envoy_filter_network_http_connection_manager_v3.HttpFilter_TypedConfig{
+			TypedConfig: protobuf.MustMarshalAny(&envoy_filter_http_fault_v3.HTTPFault{}),
+		},
+	}, FilterFault(plain, faulty))
+}
+
+func TestFilterStatefulSession(t *testing.T) {
+	plain := &dag.VirtualHost{
+		Name: "www.example.com",
+		Routes: map[string]*dag.Route{
+			"/": {PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/"}},
+		},
+	}
+	sticky := &dag.VirtualHost{
+		Name: "sticky.example.com",
+		Routes: map[string]*dag.Route{
+			"/": {
+				PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/"},
+				SessionPersistence: &dag.SessionPersistence{HeaderName: "x-session"},
+			},
+		},
+	}
+
+	assert.Nil(t, FilterStatefulSession())
+	assert.Nil(t, FilterStatefulSession(plain))
+	protobuf.ExpectEqual(t, &envoy_filter_network_http_connection_manager_v3.HttpFilter{
+		Name: StatefulSessionFilterName,
+		ConfigType: &envoy_filter_network_http_connection_manager_v3.HttpFilter_TypedConfig{
+			TypedConfig: protobuf.MustMarshalAny(&envoy_filter_http_stateful_session_v3.StatefulSession{}),
+		},
+	}, FilterStatefulSession(plain, sticky))
+}
+
+func TestLocalReplyConfig(t *testing.T) {
+	templated := &dag.DirectResponse{StatusCode: 503, Body: "%RESPONSE_CODE% unavailable", BodyTemplate: true}
+	vhost := &dag.VirtualHost{
+		Name: "www.example.com",
+		Routes: map[string]*dag.Route{"/": {
+			PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/"},
+			DirectResponse:     templated,
+		}},
+	}
+
+	textFormat := func(text string) *envoy_config_core_v3.SubstitutionFormatString {
+		return &envoy_config_core_v3.SubstitutionFormatString{
+			Format: &envoy_config_core_v3.SubstitutionFormatString_TextFormatSource{
+				TextFormatSource: &envoy_config_core_v3.DataSource{
+					Specifier: &envoy_config_core_v3.DataSource_InlineString{
+						InlineString: text,
+					},
+				},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		policy *contour_v1alpha1.LocalReplyPolicy
+		vhosts []*dag.VirtualHost
+		want   *envoy_filter_network_http_connection_manager_v3.LocalReplyConfig
+	}{
+		"no policy or direct responses": {
+			want: nil,
+		},
+		"empty policy": {
+			policy: &contour_v1alpha1.LocalReplyPolicy{},
+			want:   nil,
+		},
+		"json format": {
+			policy: &contour_v1alpha1.LocalReplyPolicy{
+				Format: &contour_v1alpha1.LocalReplyFormat{
+					JSON: map[string]string{
+						"code":  "%RESPONSE_CODE%",
+						"error": "%LOCAL_REPLY_BODY%",
+					},
+				},
+			},
+			want: &envoy_filter_network_http_connection_manager_v3.LocalReplyConfig{
+				Mappers: []*envoy_filter_network_http_connection_manager_v3.ResponseMapper{{
+					Filter: celAccessLogFilter(`response.code_details != "direct_response"`),
+					BodyFormatOverride: &envoy_config_core_v3.SubstitutionFormatString{
+						Format: &envoy_config_core_v3.SubstitutionFormatString_JsonFormat{
+							JsonFormat: &structpb.Struct{
+								Fields: map[string]*structpb.Value{
+									"code":  structpb.NewStringValue("%RESPONSE_CODE%"),
+									"error": structpb.NewStringValue("%LOCAL_REPLY_BODY%"),
+								},
+							},
+						},
+					},
+				}},
+			},
+		},
+		"overrides precede the format and follow direct responses": {
+			policy: &contour_v1alpha1.LocalReplyPolicy{
+				Format: &contour_v1alpha1.LocalReplyFormat{
+					Text: "error %RESPONSE_CODE%",
+				},
+				Overrides: []contour_v1alpha1.LocalReplyOverride{{
+					StatusCode: 503,
+					Format: contour_v1alpha1.LocalReplyFormat{
+						Text:        "<h1>unavailable</h1>",
+						ContentType: "text/html; charset=UTF-8",
+					},
+				}},
+			},
+			vhosts: []*dag.VirtualHost{vhost},
+			want: &envoy_filter_network_http_connection_manager_v3.LocalReplyConfig{
+				Mappers: []*envoy_filter_network_http_connection_manager_v3.ResponseMapper{{
+					Filter:             celAccessLogFilter(`xds.route_name == "` + directResponseRouteName(templated) + `"`),
+					BodyFormatOverride: textFormat("%RESPONSE_CODE% unavailable"),
+				}, {
+					Filter: celAccessLogFilter(`response.code == 503 && response.code_details != "direct_response"`),
+					BodyFormatOverride: func() *envoy_config_core_v3.SubstitutionFormatString {
+						f := textFormat("<h1>unavailable</h1>")
+						f.ContentType = "text/html; charset=UTF-8"
+						return f
+					}(),
+				}, {
+					Filter:             celAccessLogFilter(`response.code_details != "direct_response"`),
+					BodyFormatOverride: textFormat("error %RESPONSE_CODE%"),
+				}},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := LocalReplyConfig(tc.policy, tc.vhosts...)
+			protobuf.ExpectEqual(t, tc.want, got)
+		})
+	}
+}
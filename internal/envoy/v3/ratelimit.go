@@ -14,12 +14,16 @@
 package v3
 
 import (
+	"regexp"
+	"strings"
+
 	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_config_ratelimit_v3 "github.com/envoyproxy/go-control-plane/envoy/config/ratelimit/v3"
 	envoy_config_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	envoy_filter_http_local_ratelimit_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/local_ratelimit/v3"
 	envoy_filter_http_ratelimit_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ratelimit/v3"
 	envoy_filter_network_http_connection_manager_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_type_metadata_v3 "github.com/envoyproxy/go-control-plane/envoy/type/metadata/v3"
 	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"google.golang.org/protobuf/types/known/anypb"
@@ -76,6 +80,10 @@ func GlobalRateLimits(descriptors []*dag.RateLimitDescriptor) []*envoy_config_ro
 	for _, descriptor := range descriptors {
 		var rl envoy_config_route_v3.RateLimit
 
+		if len(descriptor.Methods) > 0 {
+			rl.Actions = append(rl.Actions, methodRateLimitAction(descriptor.Methods))
+		}
+
 		for _, entry := range descriptor.Entries {
 			switch {
 			case entry.GenericKey != nil:
@@ -112,6 +120,50 @@ func GlobalRateLimits(descriptors []*dag.RateLimitDescriptor) []*envoy_config_ro
 						RemoteAddress: &envoy_config_route_v3.RateLimit_Action_RemoteAddress{},
 					},
 				})
+			case entry.SourceCluster != nil:
+				rl.Actions = append(rl.Actions, &envoy_config_route_v3.RateLimit_Action{
+					ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_SourceCluster_{
+						SourceCluster: &envoy_config_route_v3.RateLimit_Action_SourceCluster{},
+					},
+				})
+			case entry.DestinationCluster != nil:
+				rl.Actions = append(rl.Actions, &envoy_config_route_v3.RateLimit_Action{
+					ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_DestinationCluster_{
+						DestinationCluster: &envoy_config_route_v3.RateLimit_Action_DestinationCluster{},
+					},
+				})
+			case entry.Metadata != nil:
+				var path []*envoy_type_metadata_v3.MetadataKey_PathSegment
+				for _, key := range entry.Metadata.Path {
+					path = append(path, &envoy_type_metadata_v3.MetadataKey_PathSegment{
+						Segment: &envoy_type_metadata_v3.MetadataKey_PathSegment_Key{Key: key},
+					})
+				}
+				rl.Actions = append(rl.Actions, &envoy_config_route_v3.RateLimit_Action{
+					ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_Metadata{
+						Metadata: &envoy_config_route_v3.RateLimit_Action_MetaData{
+							DescriptorKey: entry.Metadata.Key,
+							MetadataKey: &envoy_type_metadata_v3.MetadataKey{
+								Key:  entry.Metadata.Namespace,
+								Path: path,
+							},
+							DefaultValue: entry.Metadata.DefaultValue,
+							Source:       envoy_config_route_v3.RateLimit_Action_MetaData_DYNAMIC,
+						},
+					},
+				})
+			case entry.GRPCMethod != nil:
+				// gRPC carries the service and method in the :path
+				// pseudo-header, so the entry is a request header
+				// descriptor keyed on it.
+				rl.Actions = append(rl.Actions, &envoy_config_route_v3.RateLimit_Action{
+					ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_RequestHeaders_{
+						RequestHeaders: &envoy_config_route_v3.RateLimit_Action_RequestHeaders{
+							HeaderName:    ":path",
+							DescriptorKey: entry.GRPCMethod.Key,
+						},
+					},
+				})
 			}
 		}
 
@@ -121,6 +173,33 @@ func GlobalRateLimits(descriptors []*dag.RateLimitDescriptor) []*envoy_config_ro
 	return rateLimits
 }
 
+// methodRateLimitAction returns a rate limit action that generates a
+// descriptor entry with a key of "method" only for requests using one of
+// the given HTTP methods. Since Envoy skips a rate limit descriptor when any
+// of its actions doesn't produce an entry, this scopes the whole descriptor
+// to those methods.
+func methodRateLimitAction(methods []string) *envoy_config_route_v3.RateLimit_Action {
+	quoted := make([]string, 0, len(methods))
+	for _, m := range methods {
+		quoted = append(quoted, regexp.QuoteMeta(m))
+	}
+
+	return &envoy_config_route_v3.RateLimit_Action{
+		ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_HeaderValueMatch_{
+			HeaderValueMatch: &envoy_config_route_v3.RateLimit_Action_HeaderValueMatch{
+				DescriptorKey:   "method",
+				DescriptorValue: strings.Join(methods, ","),
+				ExpectMatch:     wrapperspb.Bool(true),
+				Headers: headerMatcher([]dag.HeaderMatchCondition{{
+					Name:      ":method",
+					MatchType: dag.HeaderMatchTypeRegex,
+					Value:     "^(" + strings.Join(quoted, "|") + ")$",
+				}}),
+			},
+		},
+	}
+}
+
 // GlobalRateLimitConfig stores configuration for
 // an HTTP global rate limiting filter.
 type GlobalRateLimitConfig struct {
@@ -24,6 +24,7 @@ import (
 	envoy_filter_http_ratelimit_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ratelimit/v3"
 	envoy_filter_network_http_connection_manager_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	envoy_matcher_v3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	envoy_type_metadata_v3 "github.com/envoyproxy/go-control-plane/envoy/type/metadata/v3"
 	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/stretchr/testify/assert"
@@ -235,6 +236,213 @@ func TestGlobalRateLimits(t *testing.T) {
 				},
 			},
 		},
+		"source and destination cluster descriptor": {
+			descriptors: []*dag.RateLimitDescriptor{
+				{
+					Entries: []dag.RateLimitDescriptorEntry{
+						{
+							SourceCluster: &dag.SourceClusterDescriptorEntry{},
+						},
+						{
+							DestinationCluster: &dag.DestinationClusterDescriptorEntry{},
+						},
+					},
+				},
+			},
+			want: []*envoy_config_route_v3.RateLimit{
+				{
+					Actions: []*envoy_config_route_v3.RateLimit_Action{
+						{
+							ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_SourceCluster_{
+								SourceCluster: &envoy_config_route_v3.RateLimit_Action_SourceCluster{},
+							},
+						},
+						{
+							ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_DestinationCluster_{
+								DestinationCluster: &envoy_config_route_v3.RateLimit_Action_DestinationCluster{},
+							},
+						},
+					},
+				},
+			},
+		},
+		"metadata descriptor": {
+			descriptors: []*dag.RateLimitDescriptor{
+				{
+					Entries: []dag.RateLimitDescriptorEntry{
+						{
+							Metadata: &dag.MetadataDescriptorEntry{
+								Key:          "subject",
+								Namespace:    "envoy.filters.http.jwt_authn",
+								Path:         []string{"jwt_payload", "sub"},
+								DefaultValue: "anonymous",
+							},
+						},
+					},
+				},
+			},
+			want: []*envoy_config_route_v3.RateLimit{
+				{
+					Actions: []*envoy_config_route_v3.RateLimit_Action{
+						{
+							ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_Metadata{
+								Metadata: &envoy_config_route_v3.RateLimit_Action_MetaData{
+									DescriptorKey: "subject",
+									MetadataKey: &envoy_type_metadata_v3.MetadataKey{
+										Key: "envoy.filters.http.jwt_authn",
+										Path: []*envoy_type_metadata_v3.MetadataKey_PathSegment{
+											{
+												Segment: &envoy_type_metadata_v3.MetadataKey_PathSegment_Key{Key: "jwt_payload"},
+											},
+											{
+												Segment: &envoy_type_metadata_v3.MetadataKey_PathSegment_Key{Key: "sub"},
+											},
+										},
+									},
+									DefaultValue: "anonymous",
+									Source:       envoy_config_route_v3.RateLimit_Action_MetaData_DYNAMIC,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"grpc method descriptor": {
+			descriptors: []*dag.RateLimitDescriptor{
+				{
+					Entries: []dag.RateLimitDescriptorEntry{
+						{
+							GRPCMethod: &dag.GRPCMethodDescriptorEntry{
+								Key: "grpc_method",
+							},
+						},
+					},
+				},
+			},
+			want: []*envoy_config_route_v3.RateLimit{
+				{
+					Actions: []*envoy_config_route_v3.RateLimit_Action{
+						{
+							ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_RequestHeaders_{
+								RequestHeaders: &envoy_config_route_v3.RateLimit_Action_RequestHeaders{
+									HeaderName:    ":path",
+									DescriptorKey: "grpc_method",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"method-scoped descriptor": {
+			descriptors: []*dag.RateLimitDescriptor{
+				{
+					Methods: []string{"POST", "PUT", "DELETE"},
+					Entries: []dag.RateLimitDescriptorEntry{
+						{
+							GenericKey: &dag.GenericKeyDescriptorEntry{
+								Value: "writes",
+							},
+						},
+					},
+				},
+			},
+			want: []*envoy_config_route_v3.RateLimit{
+				{
+					Actions: []*envoy_config_route_v3.RateLimit_Action{
+						{
+							ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_HeaderValueMatch_{
+								HeaderValueMatch: &envoy_config_route_v3.RateLimit_Action_HeaderValueMatch{
+									Headers: []*envoy_config_route_v3.HeaderMatcher{
+										{
+											Name: ":method",
+											HeaderMatchSpecifier: &envoy_config_route_v3.HeaderMatcher_StringMatch{
+												StringMatch: &envoy_matcher_v3.StringMatcher{
+													MatchPattern: &envoy_matcher_v3.StringMatcher_SafeRegex{
+														SafeRegex: &envoy_matcher_v3.RegexMatcher{
+															Regex: "^(POST|PUT|DELETE)$",
+														},
+													},
+												},
+											},
+										},
+									},
+									ExpectMatch:     wrapperspb.Bool(true),
+									DescriptorKey:   "method",
+									DescriptorValue: "POST,PUT,DELETE",
+								},
+							},
+						},
+						{
+							ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_GenericKey_{
+								GenericKey: &envoy_config_route_v3.RateLimit_Action_GenericKey{
+									DescriptorValue: "writes",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"descriptor and entry order is preserved": {
+			descriptors: []*dag.RateLimitDescriptor{
+				{
+					Entries: []dag.RateLimitDescriptorEntry{
+						{
+							GenericKey: &dag.GenericKeyDescriptorEntry{
+								Key:   "second",
+								Value: "b",
+							},
+						},
+						{
+							GenericKey: &dag.GenericKeyDescriptorEntry{
+								Key:   "first",
+								Value: "a",
+							},
+						},
+					},
+				},
+				{
+					Entries: []dag.RateLimitDescriptorEntry{
+						{
+							RemoteAddress: &dag.RemoteAddressDescriptorEntry{},
+						},
+					},
+				},
+			},
+			want: []*envoy_config_route_v3.RateLimit{
+				{
+					Actions: []*envoy_config_route_v3.RateLimit_Action{
+						{
+							ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_GenericKey_{
+								GenericKey: &envoy_config_route_v3.RateLimit_Action_GenericKey{
+									DescriptorKey:   "second",
+									DescriptorValue: "b",
+								},
+							},
+						},
+						{
+							ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_GenericKey_{
+								GenericKey: &envoy_config_route_v3.RateLimit_Action_GenericKey{
+									DescriptorKey:   "first",
+									DescriptorValue: "a",
+								},
+							},
+						},
+					},
+				},
+				{
+					Actions: []*envoy_config_route_v3.RateLimit_Action{
+						{
+							ActionSpecifier: &envoy_config_route_v3.RateLimit_Action_RemoteAddress_{
+								RemoteAddress: &envoy_config_route_v3.RateLimit_Action_RemoteAddress{},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
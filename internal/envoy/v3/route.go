@@ -15,29 +15,41 @@ package v3
 
 import (
 	"bytes"
+	"crypto/sha1" // nolint:gosec
 	"fmt"
 	"net/http"
 	"sort"
 	"strings"
 	"text/template"
 
+	envoy_config_accesslog_v3 "github.com/envoyproxy/go-control-plane/envoy/config/accesslog/v3"
 	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_config_rbac_v3 "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
 	envoy_config_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_access_loggers_filters_cel_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/filters/cel/v3"
+	envoy_filter_fault_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/common/fault/v3"
 	envoy_filter_http_cors_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/cors/v3"
 	envoy_filter_http_ext_authz_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ext_authz/v3"
+	envoy_filter_http_fault_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/fault/v3"
 	envoy_filter_http_jwt_authn_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/jwt_authn/v3"
 	envoy_filter_http_lua_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/lua/v3"
 	envoy_filter_http_rbac_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/rbac/v3"
+	envoy_filter_http_stateful_session_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/stateful_session/v3"
+	envoy_filter_network_http_connection_manager_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_http_stateful_session_cookie_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/http/stateful_session/cookie/v3"
+	envoy_http_stateful_session_header_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/http/stateful_session/header/v3"
 	envoy_internal_redirect_previous_routes_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/internal_redirect/previous_routes/v3"
 	envoy_internal_redirect_safe_cross_scheme_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/internal_redirect/safe_cross_scheme/v3"
+	envoy_type_http_v3 "github.com/envoyproxy/go-control-plane/envoy/type/http/v3"
 	envoy_matcher_v3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
 	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 
+	"github.com/projectcontour/contour/internal/build"
 	"github.com/projectcontour/contour/internal/dag"
 	"github.com/projectcontour/contour/internal/envoy"
 	"github.com/projectcontour/contour/internal/protobuf"
@@ -45,10 +57,21 @@ import (
 )
 
 // VirtualHostAndRoutes converts a DAG virtual host and routes to an Envoy virtual host.
-func VirtualHostAndRoutes(vh *dag.VirtualHost, dagRoutes []*dag.Route, secure bool) *envoy_config_route_v3.VirtualHost {
+// If routeIdentifierHeader is not empty, requests proxied by the routes have that
+// header set to the identifier of the route.
+func VirtualHostAndRoutes(vh *dag.VirtualHost, dagRoutes []*dag.Route, secure bool, routeIdentifierHeader string) *envoy_config_route_v3.VirtualHost {
 	var envoyRoutes []*envoy_config_route_v3.Route
 	for _, route := range dagRoutes {
-		envoyRoutes = append(envoyRoutes, buildRoute(route, vh.Name, secure))
+		if route.DirectResponse != nil && len(route.DirectResponse.WeightedBodies) > 0 {
+			envoyRoutes = append(envoyRoutes, weightedDirectResponseRoutes(route, vh.Name, secure)...)
+			continue
+		}
+		envoyRoutes = append(envoyRoutes, buildRoute(route, vh.Name, secure, routeIdentifierHeader))
+	}
+
+	// The not found route matches every request, so it must come last.
+	if vh.NotFoundResponse != nil {
+		envoyRoutes = append(envoyRoutes, notFoundRoute(vh.NotFoundResponse))
 	}
 
 	evh := VirtualHost(vh.Name, envoyRoutes...)
@@ -79,9 +102,71 @@ func VirtualHostAndRoutes(vh *dag.VirtualHost, dagRoutes []*dag.Route, secure bo
 		)
 	}
 
+	if secure && vh.HSTS != nil {
+		evh.ResponseHeadersToAdd = append(evh.ResponseHeadersToAdd, hstsHeader(vh.HSTS))
+	}
+
+	// Envoy only populates the x-envoy-attempt-count request header when
+	// asked to, so enable it for virtual hosts with routes that forward it.
+	evh.IncludeRequestAttemptCount = referencesAttemptCount(dagRoutes)
+
 	return evh
 }
 
+// hstsHeader returns a HeaderValueOption that sets the
+// Strict-Transport-Security header for the supplied policy.
+func hstsHeader(hsts *dag.HSTSPolicy) *envoy_config_core_v3.HeaderValueOption {
+	value := fmt.Sprintf("max-age=%d", hsts.MaxAge)
+	if hsts.IncludeSubDomains {
+		value += "; includeSubDomains"
+	}
+	if hsts.Preload {
+		value += "; preload"
+	}
+
+	return &envoy_config_core_v3.HeaderValueOption{
+		Header: &envoy_config_core_v3.HeaderValue{
+			Key:   "Strict-Transport-Security",
+			Value: value,
+		},
+		AppendAction: envoy_config_core_v3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+	}
+}
+
+// attemptCountHeaderVar is the header value that forwards Envoy's
+// per-request attempt count to the upstream.
+const attemptCountHeaderVar = "%req(x-envoy-attempt-count)%"
+
+// referencesAttemptCount returns true if any of the supplied routes, or their
+// clusters, set a request header from the x-envoy-attempt-count header.
+func referencesAttemptCount(dagRoutes []*dag.Route) bool {
+	references := func(policy *dag.HeadersPolicy) bool {
+		if policy == nil {
+			return false
+		}
+		for _, values := range []map[string]string{policy.Set, policy.Add} {
+			for _, v := range values {
+				if strings.Contains(strings.ToLower(v), attemptCountHeaderVar) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	for _, route := range dagRoutes {
+		if references(route.RequestHeadersPolicy) {
+			return true
+		}
+		for _, cluster := range route.Clusters {
+			if references(cluster.RequestHeadersPolicy) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func getRouteMetadata(dagRoute *dag.Route) *envoy_config_core_v3.Metadata {
 	metadataFields := map[string]*structpb.Value{}
 	if len(dagRoute.Kind) > 0 {
@@ -108,7 +193,7 @@ func getRouteMetadata(dagRoute *dag.Route) *envoy_config_core_v3.Metadata {
 }
 
 // buildRoute converts a DAG route to an Envoy route.
-func buildRoute(dagRoute *dag.Route, vhostName string, secure bool) *envoy_config_route_v3.Route {
+func buildRoute(dagRoute *dag.Route, vhostName string, secure bool, routeIdentifierHeader string) *envoy_config_route_v3.Route {
 	route := &envoy_config_route_v3.Route{
 		Match:    RouteMatch(dagRoute),
 		Metadata: getRouteMetadata(dagRoute),
@@ -136,6 +221,12 @@ func buildRoute(dagRoute *dag.Route, vhostName string, secure bool) *envoy_confi
 		}
 
 		route.Action = routeDirectResponse(dagRoute.DirectResponse)
+
+		// Name the route so the HTTP connection manager's local reply
+		// config can find the body template for it.
+		if dagRoute.DirectResponse.BodyTemplate {
+			route.Name = directResponseRouteName(dagRoute.DirectResponse)
+		}
 	case dagRoute.Redirect != nil:
 		// TODO request/response headers?
 		route.Action = routeRedirect(dagRoute.Redirect)
@@ -146,6 +237,10 @@ func buildRoute(dagRoute *dag.Route, vhostName string, secure bool) *envoy_confi
 			route.RequestHeadersToAdd = append(headerValueList(dagRoute.RequestHeadersPolicy.Set, false), headerValueList(dagRoute.RequestHeadersPolicy.Add, true)...)
 			route.RequestHeadersToRemove = dagRoute.RequestHeadersPolicy.Remove
 		}
+		if routeIdentifierHeader != "" {
+			route.RequestHeadersToAdd = append(route.RequestHeadersToAdd,
+				headerValueList(map[string]string{routeIdentifierHeader: escapeRouteIdentifier(dagRoute.Identifier())}, false)...)
+		}
 		if dagRoute.ResponseHeadersPolicy != nil {
 			route.ResponseHeadersToAdd = append(headerValueList(dagRoute.ResponseHeadersPolicy.Set, false), headerValueList(dagRoute.ResponseHeadersPolicy.Add, true)...)
 			route.ResponseHeadersToRemove = dagRoute.ResponseHeadersPolicy.Remove
@@ -169,6 +264,19 @@ func buildRoute(dagRoute *dag.Route, vhostName string, secure bool) *envoy_confi
 			route.TypedPerFilterConfig[ExtAuthzFilterName] = routeAuthzContext(dagRoute.AuthContext)
 		}
 
+		// A per-route CORS policy replaces the virtual host's.
+		if dagRoute.CORSPolicy != nil {
+			route.TypedPerFilterConfig[CORSFilterName] = protobuf.MustMarshalAny(corsPolicy(dagRoute.CORSPolicy))
+		}
+
+		if dagRoute.FaultInjectionPolicy != nil {
+			route.TypedPerFilterConfig[FaultFilterName] = protobuf.MustMarshalAny(faultInjection(dagRoute.FaultInjectionPolicy))
+		}
+
+		if dagRoute.SessionPersistence != nil {
+			route.TypedPerFilterConfig[StatefulSessionFilterName] = protobuf.MustMarshalAny(statefulSession(dagRoute.SessionPersistence))
+		}
+
 		// If JWT verification is enabled, add per-route filter
 		// config referencing a requirement in the main filter
 		// config.
@@ -194,6 +302,75 @@ func buildRoute(dagRoute *dag.Route, vhostName string, secure bool) *envoy_confi
 	return route
 }
 
+// faultInjection returns the fault filter configuration for a
+// route's fault injection policy.
+func faultInjection(fp *dag.FaultInjectionPolicy) *envoy_filter_http_fault_v3.HTTPFault {
+	fault := &envoy_filter_http_fault_v3.HTTPFault{}
+
+	if fp.Delay != nil {
+		fault.Delay = &envoy_filter_fault_v3.FaultDelay{
+			FaultDelaySecifier: &envoy_filter_fault_v3.FaultDelay_FixedDelay{
+				FixedDelay: durationpb.New(fp.Delay.Duration),
+			},
+			Percentage: &envoy_type_v3.FractionalPercent{
+				Numerator:   fp.Delay.Percentage,
+				Denominator: envoy_type_v3.FractionalPercent_HUNDRED,
+			},
+		}
+	}
+
+	if fp.Abort != nil {
+		fault.Abort = &envoy_filter_http_fault_v3.FaultAbort{
+			ErrorType: &envoy_filter_http_fault_v3.FaultAbort_HttpStatus{
+				HttpStatus: fp.Abort.StatusCode,
+			},
+			Percentage: &envoy_type_v3.FractionalPercent{
+				Numerator:   fp.Abort.Percentage,
+				Denominator: envoy_type_v3.FractionalPercent_HUNDRED,
+			},
+		}
+	}
+
+	return fault
+}
+
+// statefulSession returns the stateful session filter configuration
+// for a route's session persistence policy.
+func statefulSession(sp *dag.SessionPersistence) *envoy_filter_http_stateful_session_v3.StatefulSessionPerRoute {
+	var sessionState *envoy_config_core_v3.TypedExtensionConfig
+	if sp.HeaderName != "" {
+		sessionState = &envoy_config_core_v3.TypedExtensionConfig{
+			Name: "envoy.http.stateful_session.header",
+			TypedConfig: protobuf.MustMarshalAny(&envoy_http_stateful_session_header_v3.HeaderBasedSessionState{
+				Name: sp.HeaderName,
+			}),
+		}
+	} else {
+		cookie := &envoy_type_http_v3.Cookie{
+			Name: sp.CookieName,
+			Path: sp.CookiePath,
+		}
+		if sp.CookieTTL > 0 {
+			cookie.Ttl = durationpb.New(sp.CookieTTL)
+		}
+		sessionState = &envoy_config_core_v3.TypedExtensionConfig{
+			Name: "envoy.http.stateful_session.cookie",
+			TypedConfig: protobuf.MustMarshalAny(&envoy_http_stateful_session_cookie_v3.CookieBasedSessionState{
+				Cookie: cookie,
+			}),
+		}
+	}
+
+	return &envoy_filter_http_stateful_session_v3.StatefulSessionPerRoute{
+		Override: &envoy_filter_http_stateful_session_v3.StatefulSessionPerRoute_StatefulSession{
+			StatefulSession: &envoy_filter_http_stateful_session_v3.StatefulSession{
+				SessionState: sessionState,
+				Strict:       sp.Strict,
+			},
+		},
+	}
+}
+
 // routeAuthzDisabled returns a per-route config to disable authorization.
 func routeAuthzDisabled() *anypb.Any {
 	return protobuf.MustMarshalAny(
@@ -325,6 +502,50 @@ func PathRouteMatch(pathMatchCondition dag.MatchCondition) *envoy_config_route_v
 	}
 }
 
+// weightedDirectResponseRoutes expands a DAG route with weighted direct
+// response bodies into one Envoy route per body. Each route but the last
+// matches a cumulative runtime fraction of requests; since Envoy evaluates
+// every runtime fraction in a request against the same random value, the
+// routes split traffic according to the body weights and the last route
+// catches the remainder.
+func weightedDirectResponseRoutes(dagRoute *dag.Route, vhostName string, secure bool) []*envoy_config_route_v3.Route {
+	base := buildRoute(dagRoute, vhostName, secure, "")
+	if _, ok := base.Action.(*envoy_config_route_v3.Route_DirectResponse); !ok {
+		return []*envoy_config_route_v3.Route{base}
+	}
+
+	var bodies []dag.WeightedDirectResponseBody
+	for _, wb := range dagRoute.DirectResponse.WeightedBodies {
+		if wb.Weight > 0 {
+			bodies = append(bodies, wb)
+		}
+	}
+
+	var routes []*envoy_config_route_v3.Route
+	var cumulative uint32
+	for i, wb := range bodies {
+		route := proto.Clone(base).(*envoy_config_route_v3.Route)
+		route.Action = routeDirectResponse(&dag.DirectResponse{
+			StatusCode: dagRoute.DirectResponse.StatusCode,
+			Body:       wb.Body,
+		})
+
+		cumulative += wb.Weight
+		if i < len(bodies)-1 {
+			route.Match.RuntimeFraction = &envoy_config_core_v3.RuntimeFractionalPercent{
+				DefaultValue: &envoy_type_v3.FractionalPercent{
+					Numerator:   cumulative,
+					Denominator: envoy_type_v3.FractionalPercent_HUNDRED,
+				},
+			}
+		}
+
+		routes = append(routes, route)
+	}
+
+	return routes
+}
+
 // routeDirectResponse creates a *envoy_config_route_v3.Route_DirectResponse for the
 // http status code and body supplied. This allows a direct response to a route request
 // with an HTTP status code without needing to route to a specific cluster.
@@ -344,6 +565,97 @@ func routeDirectResponse(response *dag.DirectResponse) *envoy_config_route_v3.Ro
 	return r
 }
 
+// notFoundRoute returns a catch-all route that responds directly with
+// the supplied not found response.
+func notFoundRoute(response *dag.NotFoundResponse) *envoy_config_route_v3.Route {
+	route := &envoy_config_route_v3.Route{
+		Match: PathRouteMatch(&dag.PrefixMatchCondition{Prefix: "/"}),
+		Action: routeDirectResponse(&dag.DirectResponse{
+			StatusCode: response.StatusCode,
+			Body:       response.Body,
+		}),
+	}
+	if response.ContentType != "" {
+		route.ResponseHeadersToAdd = []*envoy_config_core_v3.HeaderValueOption{{
+			Header: &envoy_config_core_v3.HeaderValue{
+				Key:   "Content-Type",
+				Value: response.ContentType,
+			},
+			AppendAction: envoy_config_core_v3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+		}}
+	}
+	return route
+}
+
+// directResponseRouteName returns the route name used to select the local
+// reply mapper for a templated direct response.
+func directResponseRouteName(response *dag.DirectResponse) string {
+	// This isn't a crypto hash, we just want a unique name.
+	hash := sha1.Sum([]byte(fmt.Sprintf("%d/%s", response.StatusCode, response.Body))) // nolint:gosec
+	return fmt.Sprintf("directresponse/%x", hash[:5])
+}
+
+// DirectResponseLocalReplyConfig returns a local reply config that renders
+// the templated direct response bodies of the given virtual hosts' routes,
+// or nil if there are none. Each mapper is selected by the name of the
+// route that generated the direct response.
+func DirectResponseLocalReplyConfig(vhosts ...*dag.VirtualHost) *envoy_filter_network_http_connection_manager_v3.LocalReplyConfig {
+	bodies := map[string]string{}
+	for _, vh := range vhosts {
+		for _, route := range vh.Routes {
+			if route.DirectResponse == nil || !route.DirectResponse.BodyTemplate {
+				continue
+			}
+			bodies[directResponseRouteName(route.DirectResponse)] = route.DirectResponse.Body
+		}
+	}
+
+	if len(bodies) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(bodies))
+	for name := range bodies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	cfg := &envoy_filter_network_http_connection_manager_v3.LocalReplyConfig{}
+	for _, name := range names {
+		cfg.Mappers = append(cfg.Mappers, &envoy_filter_network_http_connection_manager_v3.ResponseMapper{
+			Filter: celAccessLogFilter(fmt.Sprintf("xds.route_name == %q", name)),
+			BodyFormatOverride: &envoy_config_core_v3.SubstitutionFormatString{
+				Format: &envoy_config_core_v3.SubstitutionFormatString_TextFormatSource{
+					TextFormatSource: &envoy_config_core_v3.DataSource{
+						Specifier: &envoy_config_core_v3.DataSource_InlineString{
+							InlineString: bodies[name],
+						},
+					},
+				},
+			},
+		})
+	}
+
+	return cfg
+}
+
+// celAccessLogFilter returns an access log filter matching requests
+// for which the CEL expression is true.
+func celAccessLogFilter(expr string) *envoy_config_accesslog_v3.AccessLogFilter {
+	return &envoy_config_accesslog_v3.AccessLogFilter{
+		FilterSpecifier: &envoy_config_accesslog_v3.AccessLogFilter_ExtensionFilter{
+			ExtensionFilter: &envoy_config_accesslog_v3.ExtensionFilter{
+				Name: "envoy.access_loggers.extension_filters.cel",
+				ConfigType: &envoy_config_accesslog_v3.ExtensionFilter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&envoy_access_loggers_filters_cel_v3.ExpressionFilter{
+						Expression: expr,
+					}),
+				},
+			},
+		},
+	}
+}
+
 // routeRedirect creates a *envoy_config_route_v3.Route_Redirect for the
 // redirect specified. This allows a redirect to be returned to the
 // client.
@@ -609,6 +921,13 @@ func DisabledExtAuthConfig() map[string]*anypb.Any {
 	}
 }
 
+// escapeRouteIdentifier escapes the literal %'s in a route identifier, which
+// may come from user supplied match conditions, so that Envoy does not
+// interpret them as header formatting commands.
+func escapeRouteIdentifier(id string) string {
+	return strings.ReplaceAll(id, "%", "%%")
+}
+
 // headerValueList creates a list of Envoy HeaderValueOptions from the provided map.
 func headerValueList(hvm map[string]string, app bool) []*envoy_config_core_v3.HeaderValueOption {
 	var hvs []*envoy_config_core_v3.HeaderValueOption
@@ -713,6 +1032,39 @@ func RouteConfiguration(name string, virtualhosts ...*envoy_config_route_v3.Virt
 	}
 }
 
+// ContourVersionHeader is the name of the response header that carries
+// the version of Contour that generated the Envoy configuration.
+const ContourVersionHeader = "X-Contour-Version"
+
+// VersionHeader returns a HeaderValueOption that sets the ContourVersionHeader
+// to the version of Contour recorded in the build information.
+func VersionHeader() *envoy_config_core_v3.HeaderValueOption {
+	version := build.Version
+	if version == "" {
+		version = "dev"
+	}
+
+	return &envoy_config_core_v3.HeaderValueOption{
+		Header: &envoy_config_core_v3.HeaderValue{
+			Key:   ContourVersionHeader,
+			Value: version,
+		},
+		AppendAction: envoy_config_core_v3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+	}
+}
+
+// AltSvcHeader returns a HeaderValueOption that advertises HTTP/3 on
+// the supplied UDP port to clients with an alt-svc response header.
+func AltSvcHeader(port int) *envoy_config_core_v3.HeaderValueOption {
+	return &envoy_config_core_v3.HeaderValueOption{
+		Header: &envoy_config_core_v3.HeaderValue{
+			Key:   "alt-svc",
+			Value: fmt.Sprintf(`h3=":%d"; ma=86400`, port),
+		},
+		AppendAction: envoy_config_core_v3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+	}
+}
+
 // corsPolicy returns a *envoy_filter_http_cors_v3.CorsPolicy
 func corsPolicy(cp *dag.CORSPolicy) *envoy_filter_http_cors_v3.CorsPolicy {
 	if cp == nil {
@@ -15,16 +15,24 @@ package v3
 
 import (
 	"net"
+	"net/http"
 	"testing"
 	"time"
 
+	envoy_config_accesslog_v3 "github.com/envoyproxy/go-control-plane/envoy/config/accesslog/v3"
 	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_config_rbac_v3 "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
 	envoy_config_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_access_loggers_filters_cel_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/filters/cel/v3"
 	envoy_filter_http_cors_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/cors/v3"
 	envoy_filter_http_rbac_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/rbac/v3"
+	envoy_filter_http_stateful_session_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/stateful_session/v3"
+	envoy_filter_network_http_connection_manager_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_http_stateful_session_cookie_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/http/stateful_session/cookie/v3"
+	envoy_http_stateful_session_header_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/http/stateful_session/header/v3"
 	envoy_internal_redirect_previous_routes_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/internal_redirect/previous_routes/v3"
 	envoy_internal_redirect_safe_cross_scheme_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/internal_redirect/safe_cross_scheme/v3"
+	envoy_type_http_v3 "github.com/envoyproxy/go-control-plane/envoy/type/http/v3"
 	envoy_matcher_v3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
 	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/stretchr/testify/assert"
@@ -34,6 +42,7 @@ import (
 	core_v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
+	"github.com/projectcontour/contour/internal/build"
 	"github.com/projectcontour/contour/internal/dag"
 	"github.com/projectcontour/contour/internal/fixture"
 	"github.com/projectcontour/contour/internal/protobuf"
@@ -993,6 +1002,74 @@ func TestRouteDirectResponse(t *testing.T) {
 	}
 }
 
+func TestDirectResponseLocalReplyConfig(t *testing.T) {
+	templated := &dag.DirectResponse{StatusCode: 503, Body: "%RESPONSE_CODE% unavailable", BodyTemplate: true}
+
+	tests := map[string]struct {
+		vhosts []*dag.VirtualHost
+		want   *envoy_filter_network_http_connection_manager_v3.LocalReplyConfig
+	}{
+		"no direct responses": {
+			vhosts: []*dag.VirtualHost{{
+				Name:   "www.example.com",
+				Routes: map[string]*dag.Route{"/": {PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/"}}},
+			}},
+			want: nil,
+		},
+		"static direct response": {
+			vhosts: []*dag.VirtualHost{{
+				Name: "www.example.com",
+				Routes: map[string]*dag.Route{"/": {
+					PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/"},
+					DirectResponse:     &dag.DirectResponse{StatusCode: 503, Body: "unavailable"},
+				}},
+			}},
+			want: nil,
+		},
+		"templated direct response": {
+			vhosts: []*dag.VirtualHost{{
+				Name: "www.example.com",
+				Routes: map[string]*dag.Route{"/": {
+					PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/"},
+					DirectResponse:     templated,
+				}},
+			}},
+			want: &envoy_filter_network_http_connection_manager_v3.LocalReplyConfig{
+				Mappers: []*envoy_filter_network_http_connection_manager_v3.ResponseMapper{{
+					Filter: &envoy_config_accesslog_v3.AccessLogFilter{
+						FilterSpecifier: &envoy_config_accesslog_v3.AccessLogFilter_ExtensionFilter{
+							ExtensionFilter: &envoy_config_accesslog_v3.ExtensionFilter{
+								Name: "envoy.access_loggers.extension_filters.cel",
+								ConfigType: &envoy_config_accesslog_v3.ExtensionFilter_TypedConfig{
+									TypedConfig: protobuf.MustMarshalAny(&envoy_access_loggers_filters_cel_v3.ExpressionFilter{
+										Expression: `xds.route_name == "` + directResponseRouteName(templated) + `"`,
+									}),
+								},
+							},
+						},
+					},
+					BodyFormatOverride: &envoy_config_core_v3.SubstitutionFormatString{
+						Format: &envoy_config_core_v3.SubstitutionFormatString_TextFormatSource{
+							TextFormatSource: &envoy_config_core_v3.DataSource{
+								Specifier: &envoy_config_core_v3.DataSource_InlineString{
+									InlineString: "%RESPONSE_CODE% unavailable",
+								},
+							},
+						},
+					},
+				}},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := DirectResponseLocalReplyConfig(tc.vhosts...)
+			protobuf.ExpectEqual(t, tc.want, got)
+		})
+	}
+}
+
 func TestBuildRouteWithDirectResponse(t *testing.T) {
 	tests := map[string]struct {
 		dagRoute  *dag.Route
@@ -1062,7 +1139,111 @@ func TestBuildRouteWithDirectResponse(t *testing.T) {
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			got := buildRoute(tc.dagRoute, tc.vhostName, tc.secure)
+			got := buildRoute(tc.dagRoute, tc.vhostName, tc.secure, "")
+			protobuf.ExpectEqual(t, tc.want, got)
+		})
+	}
+}
+
+func TestWeightedDirectResponseRoutes(t *testing.T) {
+	prefixMatch := func(fraction uint32) *envoy_config_route_v3.RouteMatch {
+		m := &envoy_config_route_v3.RouteMatch{
+			PathSpecifier: &envoy_config_route_v3.RouteMatch_Prefix{
+				Prefix: "/",
+			},
+		}
+		if fraction > 0 {
+			m.RuntimeFraction = &envoy_config_core_v3.RuntimeFractionalPercent{
+				DefaultValue: &envoy_type_v3.FractionalPercent{
+					Numerator:   fraction,
+					Denominator: envoy_type_v3.FractionalPercent_HUNDRED,
+				},
+			}
+		}
+		return m
+	}
+
+	tests := map[string]struct {
+		dagRoute *dag.Route
+		secure   bool
+		want     []*envoy_config_route_v3.Route
+	}{
+		"two bodies": {
+			dagRoute: &dag.Route{
+				PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/"},
+				DirectResponse: &dag.DirectResponse{
+					StatusCode: 200,
+					WeightedBodies: []dag.WeightedDirectResponseBody{
+						{Body: "A", Weight: 30},
+						{Body: "B", Weight: 70},
+					},
+				},
+			},
+			secure: true,
+			want: []*envoy_config_route_v3.Route{{
+				Match:                prefixMatch(30),
+				Action:               routeDirectResponse(&dag.DirectResponse{StatusCode: 200, Body: "A"}),
+				TypedPerFilterConfig: map[string]*anypb.Any{},
+			}, {
+				Match:                prefixMatch(0),
+				Action:               routeDirectResponse(&dag.DirectResponse{StatusCode: 200, Body: "B"}),
+				TypedPerFilterConfig: map[string]*anypb.Any{},
+			}},
+		},
+		"cumulative fractions skip zero weights": {
+			dagRoute: &dag.Route{
+				PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/"},
+				DirectResponse: &dag.DirectResponse{
+					StatusCode: 200,
+					WeightedBodies: []dag.WeightedDirectResponseBody{
+						{Body: "A", Weight: 20},
+						{Body: "unused", Weight: 0},
+						{Body: "B", Weight: 30},
+						{Body: "C", Weight: 50},
+					},
+				},
+			},
+			secure: true,
+			want: []*envoy_config_route_v3.Route{{
+				Match:                prefixMatch(20),
+				Action:               routeDirectResponse(&dag.DirectResponse{StatusCode: 200, Body: "A"}),
+				TypedPerFilterConfig: map[string]*anypb.Any{},
+			}, {
+				Match:                prefixMatch(50),
+				Action:               routeDirectResponse(&dag.DirectResponse{StatusCode: 200, Body: "B"}),
+				TypedPerFilterConfig: map[string]*anypb.Any{},
+			}, {
+				Match:                prefixMatch(0),
+				Action:               routeDirectResponse(&dag.DirectResponse{StatusCode: 200, Body: "C"}),
+				TypedPerFilterConfig: map[string]*anypb.Any{},
+			}},
+		},
+		"https upgrade is not split": {
+			dagRoute: &dag.Route{
+				PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/"},
+				HTTPSUpgrade:       true,
+				DirectResponse: &dag.DirectResponse{
+					StatusCode: 200,
+					WeightedBodies: []dag.WeightedDirectResponseBody{
+						{Body: "A", Weight: 50},
+						{Body: "B", Weight: 50},
+					},
+				},
+			},
+			secure: false,
+			want: []*envoy_config_route_v3.Route{{
+				Match:  prefixMatch(0),
+				Action: UpgradeHTTPS(),
+				TypedPerFilterConfig: map[string]*anypb.Any{
+					ExtAuthzFilterName: routeAuthzDisabled(),
+				},
+			}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := weightedDirectResponseRoutes(tc.dagRoute, "example", tc.secure)
 			protobuf.ExpectEqual(t, tc.want, got)
 		})
 	}
@@ -1259,6 +1440,38 @@ func TestRouteConfiguration(t *testing.T) {
 	}
 }
 
+func TestVersionHeader(t *testing.T) {
+	defer func(version string) { build.Version = version }(build.Version)
+
+	tests := map[string]struct {
+		version string
+		want    string
+	}{
+		"release version": {
+			version: "v1.30.0",
+			want:    "v1.30.0",
+		},
+		"no version recorded": {
+			version: "",
+			want:    "dev",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			build.Version = tc.version
+			want := &envoy_config_core_v3.HeaderValueOption{
+				Header: &envoy_config_core_v3.HeaderValue{
+					Key:   "X-Contour-Version",
+					Value: tc.want,
+				},
+				AppendAction: envoy_config_core_v3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+			}
+			protobuf.ExpectEqual(t, want, VersionHeader())
+		})
+	}
+}
+
 func TestVirtualHost(t *testing.T) {
 	tests := map[string]struct {
 		hostname string
@@ -1298,6 +1511,219 @@ func TestVirtualHost(t *testing.T) {
 	}
 }
 
+func TestVirtualHostAndRoutesAttemptCount(t *testing.T) {
+	route := func(routePolicy, clusterPolicy *dag.HeadersPolicy) *dag.Route {
+		return &dag.Route{
+			PathMatchCondition:   &dag.PrefixMatchCondition{Prefix: "/"},
+			RequestHeadersPolicy: routePolicy,
+			Clusters: []*dag.Cluster{{
+				Upstream: &dag.Service{
+					Weighted: dag.WeightedService{
+						Weight:           1,
+						ServiceName:      "kuard",
+						ServiceNamespace: "default",
+						ServicePort:      core_v1.ServicePort{Port: 8080},
+					},
+				},
+				RequestHeadersPolicy: clusterPolicy,
+			}},
+		}
+	}
+
+	tests := map[string]struct {
+		routes []*dag.Route
+		want   bool
+	}{
+		"no header policies": {
+			routes: []*dag.Route{route(nil, nil)},
+			want:   false,
+		},
+		"unrelated request header": {
+			routes: []*dag.Route{route(&dag.HeadersPolicy{
+				Set: map[string]string{"X-Foo": "%REQ(x-foo)%"},
+			}, nil)},
+			want: false,
+		},
+		"route sets attempt count header": {
+			routes: []*dag.Route{route(&dag.HeadersPolicy{
+				Set: map[string]string{"X-Attempt": "%REQ(X-Envoy-Attempt-Count)%"},
+			}, nil)},
+			want: true,
+		},
+		"cluster adds attempt count header": {
+			routes: []*dag.Route{
+				route(nil, nil),
+				route(nil, &dag.HeadersPolicy{
+					Add: map[string]string{"X-Attempt": "attempt-%REQ(x-envoy-attempt-count)%"},
+				}),
+			},
+			want: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := VirtualHostAndRoutes(&dag.VirtualHost{Name: "www.example.com"}, tc.routes, false, "")
+			assert.Equal(t, tc.want, got.IncludeRequestAttemptCount)
+		})
+	}
+}
+
+func TestVirtualHostAndRoutesHSTS(t *testing.T) {
+	tests := map[string]struct {
+		hsts   *dag.HSTSPolicy
+		secure bool
+		want   []*envoy_config_core_v3.HeaderValueOption
+	}{
+		"no policy": {
+			secure: true,
+			want:   nil,
+		},
+		"insecure virtual host": {
+			hsts:   &dag.HSTSPolicy{MaxAge: 31536000},
+			secure: false,
+			want:   nil,
+		},
+		"max age": {
+			hsts:   &dag.HSTSPolicy{MaxAge: 31536000},
+			secure: true,
+			want: []*envoy_config_core_v3.HeaderValueOption{{
+				Header: &envoy_config_core_v3.HeaderValue{
+					Key:   "Strict-Transport-Security",
+					Value: "max-age=31536000",
+				},
+				AppendAction: envoy_config_core_v3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+			}},
+		},
+		"include subdomains and preload": {
+			hsts:   &dag.HSTSPolicy{MaxAge: 63072000, IncludeSubDomains: true, Preload: true},
+			secure: true,
+			want: []*envoy_config_core_v3.HeaderValueOption{{
+				Header: &envoy_config_core_v3.HeaderValue{
+					Key:   "Strict-Transport-Security",
+					Value: "max-age=63072000; includeSubDomains; preload",
+				},
+				AppendAction: envoy_config_core_v3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+			}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := VirtualHostAndRoutes(&dag.VirtualHost{Name: "www.example.com", HSTS: tc.hsts}, nil, tc.secure, "")
+			protobuf.ExpectEqual(t, tc.want, got.ResponseHeadersToAdd)
+		})
+	}
+}
+
+func TestVirtualHostAndRoutesNotFoundResponse(t *testing.T) {
+	routes := []*dag.Route{{
+		PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/api"},
+		DirectResponse:     &dag.DirectResponse{StatusCode: http.StatusOK},
+	}}
+
+	tests := map[string]struct {
+		nfr  *dag.NotFoundResponse
+		want []*envoy_config_route_v3.Route
+	}{
+		"no response": {
+			want: []*envoy_config_route_v3.Route{{
+				Match:  PathRouteMatch(&dag.PrefixMatchCondition{Prefix: "/api"}),
+				Action: routeDirectResponse(&dag.DirectResponse{StatusCode: http.StatusOK}),
+			}},
+		},
+		"response without content type": {
+			nfr: &dag.NotFoundResponse{StatusCode: http.StatusNotFound, Body: "not found"},
+			want: []*envoy_config_route_v3.Route{{
+				Match:  PathRouteMatch(&dag.PrefixMatchCondition{Prefix: "/api"}),
+				Action: routeDirectResponse(&dag.DirectResponse{StatusCode: http.StatusOK}),
+			}, {
+				Match:  PathRouteMatch(&dag.PrefixMatchCondition{Prefix: "/"}),
+				Action: routeDirectResponse(&dag.DirectResponse{StatusCode: http.StatusNotFound, Body: "not found"}),
+			}},
+		},
+		"response with content type": {
+			nfr: &dag.NotFoundResponse{StatusCode: http.StatusNotFound, Body: `{"error":"not found"}`, ContentType: "application/json"},
+			want: []*envoy_config_route_v3.Route{{
+				Match:  PathRouteMatch(&dag.PrefixMatchCondition{Prefix: "/api"}),
+				Action: routeDirectResponse(&dag.DirectResponse{StatusCode: http.StatusOK}),
+			}, {
+				Match:  PathRouteMatch(&dag.PrefixMatchCondition{Prefix: "/"}),
+				Action: routeDirectResponse(&dag.DirectResponse{StatusCode: http.StatusNotFound, Body: `{"error":"not found"}`}),
+				ResponseHeadersToAdd: []*envoy_config_core_v3.HeaderValueOption{{
+					Header: &envoy_config_core_v3.HeaderValue{
+						Key:   "Content-Type",
+						Value: "application/json",
+					},
+					AppendAction: envoy_config_core_v3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+				}},
+			}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := VirtualHostAndRoutes(&dag.VirtualHost{Name: "www.example.com", NotFoundResponse: tc.nfr}, routes, true, "")
+			protobuf.ExpectEqual(t, tc.want, got.Routes)
+		})
+	}
+}
+
+func TestVirtualHostAndRoutesRouteIdentifier(t *testing.T) {
+	routes := []*dag.Route{{
+		Kind:               "HTTPProxy",
+		Namespace:          "default",
+		Name:               "kuard",
+		PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/api"},
+		HeaderMatchConditions: []dag.HeaderMatchCondition{{
+			Name:      "x-discount",
+			Value:     "50%",
+			MatchType: dag.HeaderMatchTypeExact,
+		}},
+		Clusters: []*dag.Cluster{{
+			Upstream: &dag.Service{
+				Weighted: dag.WeightedService{
+					Weight:           1,
+					ServiceName:      "kuard",
+					ServiceNamespace: "default",
+					ServicePort:      core_v1.ServicePort{Port: 8080},
+				},
+			},
+		}},
+	}, {
+		Kind:               "HTTPProxy",
+		Namespace:          "default",
+		Name:               "kuard",
+		PathMatchCondition: &dag.PrefixMatchCondition{Prefix: "/static"},
+		DirectResponse:     &dag.DirectResponse{StatusCode: http.StatusOK},
+	}}
+
+	tests := map[string]struct {
+		header string
+		want   []*envoy_config_core_v3.HeaderValueOption
+	}{
+		"no header": {},
+		// The % in the header match is escaped so Envoy does not treat
+		// it as a format command.
+		"route identifier header": {
+			header: "x-contour-route",
+			want: []*envoy_config_core_v3.HeaderValueOption{{
+				Header: &envoy_config_core_v3.HeaderValue{
+					Key:   "x-contour-route",
+					Value: "HTTPProxy/default/kuard/prefix=/api,header:x-discount:exact=50%%",
+				},
+				AppendAction: envoy_config_core_v3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+			}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := VirtualHostAndRoutes(&dag.VirtualHost{Name: "www.example.com"}, routes, false, tc.header)
+			assert.Len(t, got.Routes, 2)
+			protobuf.ExpectEqual(t, tc.want, got.Routes[0].RequestHeadersToAdd)
+			// Direct responses are not proxied upstream, so get no header.
+			assert.Empty(t, got.Routes[1].RequestHeadersToAdd)
+		})
+	}
+}
+
 func TestCORSVirtualHost(t *testing.T) {
 	tests := map[string]struct {
 		hostname string
@@ -2109,6 +2535,31 @@ func TestRouteMatch(t *testing.T) {
 				}},
 			},
 		},
+		"contains match -- treat missing as empty is ignored": {
+			route: &dag.Route{
+				HeaderMatchConditions: []dag.HeaderMatchCondition{{
+					Name:                "x-header",
+					Value:               "foo",
+					MatchType:           "contains",
+					Invert:              false,
+					TreatMissingAsEmpty: true,
+				}},
+			},
+			want: &envoy_config_route_v3.RouteMatch{
+				Headers: []*envoy_config_route_v3.HeaderMatcher{{
+					Name:                      "x-header",
+					InvertMatch:               false,
+					TreatMissingHeaderAsEmpty: false,
+					HeaderMatchSpecifier: &envoy_config_route_v3.HeaderMatcher_StringMatch{
+						StringMatch: &envoy_matcher_v3.StringMatcher{
+							MatchPattern: &envoy_matcher_v3.StringMatcher_Contains{
+								Contains: "foo",
+							},
+						},
+					},
+				}},
+			},
+		},
 		"path prefix string prefix": {
 			route: &dag.Route{
 				PathMatchCondition: &dag.PrefixMatchCondition{
@@ -2666,6 +3117,48 @@ func TestRouteMatch(t *testing.T) {
 	}
 }
 
+func TestStatefulSession(t *testing.T) {
+	tests := map[string]struct {
+		sp   *dag.SessionPersistence
+		want *envoy_config_core_v3.TypedExtensionConfig
+	}{
+		"cookie": {
+			sp: &dag.SessionPersistence{CookieName: "session", CookiePath: "/app", CookieTTL: time.Hour},
+			want: &envoy_config_core_v3.TypedExtensionConfig{
+				Name: "envoy.http.stateful_session.cookie",
+				TypedConfig: protobuf.MustMarshalAny(&envoy_http_stateful_session_cookie_v3.CookieBasedSessionState{
+					Cookie: &envoy_type_http_v3.Cookie{
+						Name: "session",
+						Path: "/app",
+						Ttl:  durationpb.New(time.Hour),
+					},
+				}),
+			},
+		},
+		"header": {
+			sp: &dag.SessionPersistence{HeaderName: "x-session"},
+			want: &envoy_config_core_v3.TypedExtensionConfig{
+				Name: "envoy.http.stateful_session.header",
+				TypedConfig: protobuf.MustMarshalAny(&envoy_http_stateful_session_header_v3.HeaderBasedSessionState{
+					Name: "x-session",
+				}),
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			protobuf.ExpectEqual(t, &envoy_filter_http_stateful_session_v3.StatefulSessionPerRoute{
+				Override: &envoy_filter_http_stateful_session_v3.StatefulSessionPerRoute_StatefulSession{
+					StatefulSession: &envoy_filter_http_stateful_session_v3.StatefulSession{
+						SessionState: tc.want,
+					},
+				},
+			}, statefulSession(tc.sp))
+		})
+	}
+}
+
 func TestRouteRedirect(t *testing.T) {
 	tests := map[string]struct {
 		redirect *dag.Redirect
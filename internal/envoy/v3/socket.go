@@ -15,6 +15,9 @@ package v3
 
 import (
 	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_transport_socket_proxy_protocol_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/proxy_protocol/v3"
+	envoy_transport_socket_quic_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/quic/v3"
+	envoy_transport_socket_raw_buffer_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/raw_buffer/v3"
 	envoy_transport_socket_tls_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 
 	"github.com/projectcontour/contour/internal/protobuf"
@@ -39,3 +42,46 @@ func DownstreamTLSTransportSocket(tls *envoy_transport_socket_tls_v3.DownstreamT
 		},
 	}
 }
+
+// DownstreamQUICTransportSocket returns a QUIC transport socket wrapping the DownstreamTlsContext provided.
+func DownstreamQUICTransportSocket(tls *envoy_transport_socket_tls_v3.DownstreamTlsContext) *envoy_config_core_v3.TransportSocket {
+	return &envoy_config_core_v3.TransportSocket{
+		Name: "envoy.transport_sockets.quic",
+		ConfigType: &envoy_config_core_v3.TransportSocket_TypedConfig{
+			TypedConfig: protobuf.MustMarshalAny(&envoy_transport_socket_quic_v3.QuicDownstreamTransport{
+				DownstreamTlsContext: tls,
+			}),
+		},
+	}
+}
+
+// UpstreamProxyProtocolTransportSocket returns a transport socket that sends a
+// PROXY protocol header of the given version ("v1" or "v2") before handing the
+// connection to the wrapped transport socket. A nil wrapped socket means plaintext.
+func UpstreamProxyProtocolTransportSocket(version string, wrapped *envoy_config_core_v3.TransportSocket) *envoy_config_core_v3.TransportSocket {
+	if wrapped == nil {
+		wrapped = &envoy_config_core_v3.TransportSocket{
+			Name: "envoy.transport_sockets.raw_buffer",
+			ConfigType: &envoy_config_core_v3.TransportSocket_TypedConfig{
+				TypedConfig: protobuf.MustMarshalAny(&envoy_transport_socket_raw_buffer_v3.RawBuffer{}),
+			},
+		}
+	}
+
+	ppVersion := envoy_config_core_v3.ProxyProtocolConfig_V1
+	if version == "v2" {
+		ppVersion = envoy_config_core_v3.ProxyProtocolConfig_V2
+	}
+
+	return &envoy_config_core_v3.TransportSocket{
+		Name: "envoy.transport_sockets.upstream_proxy_protocol",
+		ConfigType: &envoy_config_core_v3.TransportSocket_TypedConfig{
+			TypedConfig: protobuf.MustMarshalAny(&envoy_transport_socket_proxy_protocol_v3.ProxyProtocolUpstreamTransport{
+				Config: &envoy_config_core_v3.ProxyProtocolConfig{
+					Version: ppVersion,
+				},
+				TransportSocket: wrapped,
+			}),
+		},
+	}
+}
@@ -17,6 +17,8 @@ import (
 	"testing"
 
 	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_transport_socket_proxy_protocol_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/proxy_protocol/v3"
+	envoy_transport_socket_raw_buffer_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/raw_buffer/v3"
 	envoy_transport_socket_tls_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	core_v1 "k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -49,6 +51,59 @@ func TestUpstreamTLSTransportSocket(t *testing.T) {
 	}
 }
 
+func TestUpstreamProxyProtocolTransportSocket(t *testing.T) {
+	rawBuffer := &envoy_config_core_v3.TransportSocket{
+		Name: "envoy.transport_sockets.raw_buffer",
+		ConfigType: &envoy_config_core_v3.TransportSocket_TypedConfig{
+			TypedConfig: protobuf.MustMarshalAny(&envoy_transport_socket_raw_buffer_v3.RawBuffer{}),
+		},
+	}
+	tlsSocket := UpstreamTLSTransportSocket(UpstreamTLSContext(nil, "", nil, nil))
+
+	tests := map[string]struct {
+		version string
+		wrapped *envoy_config_core_v3.TransportSocket
+		want    *envoy_config_core_v3.TransportSocket
+	}{
+		"v1 plaintext": {
+			version: "v1",
+			want: &envoy_config_core_v3.TransportSocket{
+				Name: "envoy.transport_sockets.upstream_proxy_protocol",
+				ConfigType: &envoy_config_core_v3.TransportSocket_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&envoy_transport_socket_proxy_protocol_v3.ProxyProtocolUpstreamTransport{
+						Config: &envoy_config_core_v3.ProxyProtocolConfig{
+							Version: envoy_config_core_v3.ProxyProtocolConfig_V1,
+						},
+						TransportSocket: rawBuffer,
+					}),
+				},
+			},
+		},
+		"v2 tls": {
+			version: "v2",
+			wrapped: tlsSocket,
+			want: &envoy_config_core_v3.TransportSocket{
+				Name: "envoy.transport_sockets.upstream_proxy_protocol",
+				ConfigType: &envoy_config_core_v3.TransportSocket_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&envoy_transport_socket_proxy_protocol_v3.ProxyProtocolUpstreamTransport{
+						Config: &envoy_config_core_v3.ProxyProtocolConfig{
+							Version: envoy_config_core_v3.ProxyProtocolConfig_V2,
+						},
+						TransportSocket: tlsSocket,
+					}),
+				},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := UpstreamProxyProtocolTransportSocket(tc.version, tc.wrapped)
+			protobuf.ExpectEqual(t, tc.want, got)
+		})
+	}
+}
+
 func TestDownstreamTLSTransportSocket(t *testing.T) {
 	serverSecret := &dag.Secret{
 		Object: &core_v1.Secret{
@@ -90,11 +90,14 @@ func StatsListeners(metrics contour_v1alpha1.MetricsConfig, health contour_v1alp
 	return listeners
 }
 
+// AdminListenerName is the name of the listener returned by AdminListener.
+const AdminListenerName = "envoy-admin"
+
 // AdminListener returns a *envoy_config_listener_v3.Listener configured to serve Envoy
 // debug routes from the admin webpage.
 func AdminListener(port int) *envoy_config_listener_v3.Listener {
 	return &envoy_config_listener_v3.Listener{
-		Name:    "envoy-admin",
+		Name:    AdminListenerName,
 		Address: SocketAddress("127.0.0.1", port),
 		FilterChains: filterChain("envoy-admin", nil,
 			routeForAdminInterface(
@@ -0,0 +1,187 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3
+
+import (
+	"testing"
+
+	envoy_config_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
+	"github.com/projectcontour/contour/internal/featuretests"
+	"github.com/projectcontour/contour/internal/fixture"
+)
+
+func TestAllowHTTP01Challenge(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	rh.OnAdd(fixture.NewService("svc1").
+		WithPorts(core_v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)}),
+	)
+	rh.OnAdd(fixture.NewService("svc2").
+		WithPorts(core_v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)}),
+	)
+	rh.OnAdd(featuretests.TLSSecret(t, "secret", &featuretests.ServerCertificate))
+
+	p := fixture.NewProxy("simple").WithSpec(
+		contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn:                 "hello.world",
+				AllowHTTP01Challenge: true,
+				TLS: &contour_v1.TLS{
+					SecretName: "secret",
+				},
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: "svc1",
+					Port: 80,
+				}},
+			}},
+		})
+	rh.OnAdd(p)
+
+	// The challenge path is served over HTTP, every other path
+	// redirects to HTTPS.
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: routeResources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("hello.world",
+					&envoy_config_route_v3.Route{
+						Match:  routePrefix("/.well-known/acme-challenge/"),
+						Action: routecluster("default/svc1/80/da39a3ee5e"),
+					},
+					&envoy_config_route_v3.Route{
+						Match:                routePrefix("/"),
+						Action:               envoy_v3.UpgradeHTTPS(),
+						TypedPerFilterConfig: envoy_v3.DisabledExtAuthConfig(),
+					}),
+			),
+			envoy_v3.RouteConfiguration("https/hello.world",
+				envoy_v3.VirtualHost("hello.world",
+					&envoy_config_route_v3.Route{
+						Match:  routePrefix("/"),
+						Action: routecluster("default/svc1/80/da39a3ee5e"),
+					}),
+			),
+		),
+		TypeUrl: routeType,
+	}).Status(p).IsValid()
+
+	// More specific routes under the challenge path are not redirected,
+	// and the challenge path is served by the longest covering prefix.
+	specific := fixture.NewProxy("simple").WithSpec(
+		contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn:                 "hello.world",
+				AllowHTTP01Challenge: true,
+				TLS: &contour_v1.TLS{
+					SecretName: "secret",
+				},
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: "svc1",
+					Port: 80,
+				}},
+			}, {
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/.well-known/",
+				}},
+				Services: []contour_v1.Service{{
+					Name: "svc2",
+					Port: 80,
+				}},
+			}, {
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/.well-known/acme-challenge/token",
+				}},
+				Services: []contour_v1.Service{{
+					Name: "svc1",
+					Port: 80,
+				}},
+			}},
+		})
+	rh.OnUpdate(p, specific)
+
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: routeResources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("hello.world",
+					&envoy_config_route_v3.Route{
+						Match:  routePrefix("/.well-known/acme-challenge/token"),
+						Action: routecluster("default/svc1/80/da39a3ee5e"),
+					},
+					&envoy_config_route_v3.Route{
+						Match:  routePrefix("/.well-known/acme-challenge/"),
+						Action: routecluster("default/svc2/80/da39a3ee5e"),
+					},
+					&envoy_config_route_v3.Route{
+						Match:                routePrefix("/.well-known/"),
+						Action:               envoy_v3.UpgradeHTTPS(),
+						TypedPerFilterConfig: envoy_v3.DisabledExtAuthConfig(),
+					},
+					&envoy_config_route_v3.Route{
+						Match:                routePrefix("/"),
+						Action:               envoy_v3.UpgradeHTTPS(),
+						TypedPerFilterConfig: envoy_v3.DisabledExtAuthConfig(),
+					}),
+			),
+			envoy_v3.RouteConfiguration("https/hello.world",
+				envoy_v3.VirtualHost("hello.world",
+					&envoy_config_route_v3.Route{
+						Match:  routePrefix("/.well-known/acme-challenge/token"),
+						Action: routecluster("default/svc1/80/da39a3ee5e"),
+					},
+					&envoy_config_route_v3.Route{
+						Match:  routePrefix("/.well-known/"),
+						Action: routecluster("default/svc2/80/da39a3ee5e"),
+					},
+					&envoy_config_route_v3.Route{
+						Match:  routePrefix("/"),
+						Action: routecluster("default/svc1/80/da39a3ee5e"),
+					}),
+			),
+		),
+		TypeUrl: routeType,
+	}).Status(specific).IsValid()
+
+	invalid := fixture.NewProxy("simple").WithSpec(
+		contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn:                 "hello.world",
+				AllowHTTP01Challenge: true,
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: "svc1",
+					Port: 80,
+				}},
+			}},
+		})
+	rh.OnUpdate(specific, invalid)
+
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: routeResources(t,
+			envoy_v3.RouteConfiguration("ingress_http"),
+		),
+		TypeUrl: routeType,
+	}).Status(invalid).HasError(contour_v1.ConditionTypeVirtualHostError, "HTTP01ChallengeNotValid",
+		"Spec.VirtualHost.AllowHTTP01Challenge requires Spec.VirtualHost.TLS to be configured")
+}
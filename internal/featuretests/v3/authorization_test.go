@@ -19,11 +19,13 @@ import (
 	"testing"
 	"time"
 
+	envoy_config_common_mutation_rules_v3 "github.com/envoyproxy/go-control-plane/envoy/config/common/mutation_rules/v3"
 	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_config_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	envoy_config_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	envoy_filter_http_ext_authz_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ext_authz/v3"
 	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	envoy_matcher_v3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
 	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"google.golang.org/protobuf/types/known/durationpb"
 	core_v1 "k8s.io/api/core/v1"
@@ -136,6 +138,35 @@ func authzInvalidResponseTimeout(t *testing.T, rh ResourceEventHandlerWrapper, c
 	}).Status(p).HasError(contour_v1.ConditionTypeAuthError, "AuthResponseTimeoutInvalid", `Spec.Virtualhost.Authorization.ResponseTimeout is invalid: unable to parse timeout string "invalid-timeout": time: invalid duration "invalid-timeout"`)
 }
 
+func authzRequestBodyTooLarge(t *testing.T, rh ResourceEventHandlerWrapper, c *Contour) {
+	const fqdn = "buffersettings.projectcontour.io"
+
+	p := fixture.NewProxy("proxy").
+		WithFQDN(fqdn).
+		WithCertificate("certificate").
+		WithAuthServer(contour_v1.AuthorizationServer{
+			ExtensionServiceRef: contour_v1.ExtensionServiceReference{
+				Namespace: "auth",
+				Name:      "extension",
+			},
+			WithRequestBody: &contour_v1.AuthorizationServerBufferSettings{
+				MaxRequestBytes: 8*1024*1024 + 1,
+			},
+		}).
+		WithSpec(contour_v1.HTTPProxySpec{
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{Name: "app-server", Port: 80}},
+			}},
+		})
+
+	rh.OnAdd(p)
+
+	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		TypeUrl:   listenerType,
+		Resources: resources(t, statsListener()),
+	}).Status(p).HasError(contour_v1.ConditionTypeAuthError, "AuthRequestBodyTooLarge", "Spec.Virtualhost.Authorization.WithRequestBody.MaxRequestBytes 8388609 exceeds the maximum of 8388608 bytes")
+}
+
 func authzFailOpen(t *testing.T, rh ResourceEventHandlerWrapper, c *Contour) {
 	const fqdn = "failopen.projectcontour.io"
 
@@ -562,6 +593,82 @@ func authzWithRequestBodyBufferSettings(t *testing.T, rh ResourceEventHandlerWra
 	}).Status(p).IsValid()
 }
 
+func authzWithHeaderLists(t *testing.T, rh ResourceEventHandlerWrapper, c *Contour) {
+	const fqdn = "headerlists.projectcontour.io"
+
+	p := fixture.NewProxy("proxy").
+		WithFQDN(fqdn).
+		WithCertificate("certificate").
+		WithAuthServer(contour_v1.AuthorizationServer{
+			ExtensionServiceRef: contour_v1.ExtensionServiceReference{
+				Namespace: "auth",
+				Name:      "extension",
+			},
+			AuthorizationRequest: &contour_v1.AuthorizationRequestHeaders{
+				AllowedHeaders:    []string{"Authorization"},
+				DisallowedHeaders: []string{"Cookie"},
+			},
+			AuthorizationResponse: &contour_v1.AuthorizationResponseHeaders{
+				DisallowedHeaders: []string{"X-Internal"},
+			},
+		}).
+		WithSpec(contour_v1.HTTPProxySpec{
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{Name: "app-server", Port: 80}},
+			}},
+		})
+
+	rh.OnAdd(p)
+
+	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		TypeUrl: listenerType,
+		Resources: resources(t,
+			defaultHTTPListener(),
+			&envoy_config_listener_v3.Listener{
+				Name:    "ingress_https",
+				Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
+				ListenerFilters: envoy_v3.ListenerFilters(
+					envoy_v3.TLSInspector(),
+				),
+				FilterChains: []*envoy_config_listener_v3.FilterChain{
+					filterchaintls(fqdn, featuretests.TLSSecret(t, "certificate", &featuretests.ServerCertificate),
+						authzFilterFor(
+							fqdn,
+							&envoy_filter_http_ext_authz_v3.ExtAuthz{
+								Services:               grpcCluster("extension/auth/extension"),
+								ClearRouteCache:        true,
+								IncludePeerCertificate: true,
+								StatusOnError: &envoy_type_v3.HttpStatus{
+									Code: envoy_type_v3.StatusCode_Forbidden,
+								},
+								TransportApiVersion: envoy_config_core_v3.ApiVersion_V3,
+								AllowedHeaders: &envoy_matcher_v3.ListStringMatcher{
+									Patterns: []*envoy_matcher_v3.StringMatcher{{
+										MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{Exact: "Authorization"},
+										IgnoreCase:   true,
+									}},
+								},
+								DisallowedHeaders: &envoy_matcher_v3.ListStringMatcher{
+									Patterns: []*envoy_matcher_v3.StringMatcher{{
+										MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{Exact: "Cookie"},
+										IgnoreCase:   true,
+									}},
+								},
+								DecoderHeaderMutationRules: &envoy_config_common_mutation_rules_v3.HeaderMutationRules{
+									DisallowExpression: &envoy_matcher_v3.RegexMatcher{
+										Regex: "^(?i)(X-Internal)$",
+									},
+								},
+							},
+						),
+						nil, "h2", "http/1.1"),
+				},
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			},
+			statsListener()),
+	}).Status(p).IsValid()
+}
+
 func TestAuthorization(t *testing.T) {
 	subtests := map[string]func(*testing.T, ResourceEventHandlerWrapper, *Contour){
 		"MissingExtension":                   authzInvalidReference,
@@ -572,6 +679,8 @@ func TestAuthorization(t *testing.T) {
 		"ResponseTimeout":                    authzResponseTimeout,
 		"InvalidResponseTimeout":             authzInvalidResponseTimeout,
 		"AuthzWithRequestBodyBufferSettings": authzWithRequestBodyBufferSettings,
+		"AuthzWithHeaderLists":               authzWithHeaderLists,
+		"AuthzRequestBodyTooLarge":           authzRequestBodyTooLarge,
 	}
 
 	for n, f := range subtests {
@@ -20,6 +20,7 @@ import (
 	envoy_filter_http_cors_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/cors/v3"
 	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	envoy_matcher_v3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 	core_v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -27,6 +28,7 @@ import (
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
 	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/protobuf"
 )
 
 func TestCorsPolicy(t *testing.T) {
@@ -468,4 +470,133 @@ func TestCorsPolicy(t *testing.T) {
 			envoy_v3.RouteConfiguration("ingress_http")),
 		TypeUrl: routeType,
 	}).Status(invvhost).IsInvalid()
+
+	// Virtual hosts with an allowed origin that is neither an exact
+	// origin nor a valid regex are not added
+	invregex := &contour_v1.HTTPProxy{
+		ObjectMeta: fixture.ObjectMeta("simple"),
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "hello.world",
+				CORSPolicy: &contour_v1.CORSPolicy{
+					AllowOrigin:  []string{`https://example-[abcd\.org`},
+					AllowMethods: []contour_v1.CORSHeaderValue{"GET"},
+				},
+			}, Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: "svc1",
+					Port: 80,
+				}},
+			}},
+		},
+	}
+
+	rh.OnUpdate(invvhost, invregex)
+
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http")),
+		TypeUrl: routeType,
+	}).Status(invregex).IsInvalid()
+}
+
+func TestCorsPolicyRouteOverride(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	rh.OnAdd(fixture.NewService("svc1").
+		WithPorts(core_v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)}),
+	)
+
+	vhostCORSPolicy := &envoy_filter_http_cors_v3.CorsPolicy{
+		AllowCredentials:          &wrapperspb.BoolValue{Value: false},
+		AllowPrivateNetworkAccess: &wrapperspb.BoolValue{Value: false},
+		AllowOriginStringMatch: []*envoy_matcher_v3.StringMatcher{{
+			MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{
+				Exact: "https://www.example.com",
+			},
+			IgnoreCase: true,
+		}},
+		AllowMethods: "GET",
+	}
+
+	// The /api route replaces the virtual host's policy, the
+	// / route uses the virtual host's policy.
+	p := fixture.NewProxy("simple").WithSpec(
+		contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "hello.world",
+				CORSPolicy: &contour_v1.CORSPolicy{
+					AllowOrigin:  []string{"https://www.example.com"},
+					AllowMethods: []contour_v1.CORSHeaderValue{"GET"},
+				},
+			}, Routes: []contour_v1.Route{{
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/api",
+				}},
+				CORSPolicy: &contour_v1.CORSPolicy{
+					AllowOrigin:  []string{"https://www.example.com", "https://api.example.com"},
+					AllowMethods: []contour_v1.CORSHeaderValue{"GET", "POST"},
+				},
+				Services: []contour_v1.Service{{
+					Name: "svc1",
+					Port: 80,
+				}},
+			}, {
+				Services: []contour_v1.Service{{
+					Name: "svc1",
+					Port: 80,
+				}},
+			}},
+		})
+	rh.OnAdd(p)
+
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.CORSVirtualHost("hello.world",
+					vhostCORSPolicy,
+					&envoy_config_route_v3.Route{
+						Match:  routePrefix("/api"),
+						Action: routecluster("default/svc1/80/da39a3ee5e"),
+						TypedPerFilterConfig: map[string]*anypb.Any{
+							envoy_v3.CORSFilterName: protobuf.MustMarshalAny(&envoy_filter_http_cors_v3.CorsPolicy{
+								AllowCredentials:          &wrapperspb.BoolValue{Value: false},
+								AllowPrivateNetworkAccess: &wrapperspb.BoolValue{Value: false},
+								AllowOriginStringMatch: []*envoy_matcher_v3.StringMatcher{{
+									MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{
+										Exact: "https://www.example.com",
+									},
+									IgnoreCase: true,
+								}, {
+									MatchPattern: &envoy_matcher_v3.StringMatcher_Exact{
+										Exact: "https://api.example.com",
+									},
+									IgnoreCase: true,
+								}},
+								AllowMethods: "GET,POST",
+							}),
+						},
+					},
+					&envoy_config_route_v3.Route{
+						Match:  routePrefix("/"),
+						Action: routecluster("default/svc1/80/da39a3ee5e"),
+					}),
+			),
+		),
+		TypeUrl: routeType,
+	}).Status(p).IsValid()
+
+	// A route with an invalid policy makes the proxy invalid.
+	invalid := p.DeepCopy()
+	invalid.ObjectMeta = fixture.ObjectMeta("simple")
+	invalid.Spec.Routes[0].CORSPolicy.MaxAge = "-10m"
+	rh.OnUpdate(p, invalid)
+
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http"),
+		),
+		TypeUrl: routeType,
+	}).Status(invalid).HasError(contour_v1.ConditionTypeCORSError, "PolicyDidNotParse", `Spec.Routes.CORSPolicy: invalid max age value "-10m"`)
 }
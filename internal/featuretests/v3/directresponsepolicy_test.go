@@ -16,15 +16,24 @@ package v3
 import (
 	"testing"
 
+	envoy_config_accesslog_v3 "github.com/envoyproxy/go-control-plane/envoy/config/accesslog/v3"
 	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_config_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	envoy_config_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_access_loggers_filters_cel_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/filters/cel/v3"
+	envoy_filter_network_http_connection_manager_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
 	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/k8s"
+	"github.com/projectcontour/contour/internal/protobuf"
 )
 
 func TestDirectResponsePolicy_HTTProxy(t *testing.T) {
@@ -125,4 +134,207 @@ func TestDirectResponsePolicy_HTTProxy(t *testing.T) {
 		),
 		TypeUrl: routeType,
 	})
+
+	proxyWeighted := fixture.NewProxy("simple-weighted").WithSpec(
+		contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{Fqdn: "directresponse.projectcontour.io"},
+			Routes: []contour_v1.Route{{
+				DirectResponsePolicy: &contour_v1.HTTPDirectResponsePolicy{
+					StatusCode: 200,
+					WeightedBodies: []contour_v1.WeightedDirectResponseBody{
+						{Body: "A", Weight: 25},
+						{Body: "B", Weight: 75},
+					},
+				},
+			}},
+		})
+
+	rh.OnUpdate(proxyInvalid, proxyWeighted)
+
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("directresponse.projectcontour.io",
+					&envoy_config_route_v3.Route{
+						Match: &envoy_config_route_v3.RouteMatch{
+							PathSpecifier: routePrefix("/").PathSpecifier,
+							RuntimeFraction: &envoy_config_core_v3.RuntimeFractionalPercent{
+								DefaultValue: &envoy_type_v3.FractionalPercent{
+									Numerator:   25,
+									Denominator: envoy_type_v3.FractionalPercent_HUNDRED,
+								},
+							},
+						},
+						Action: &envoy_config_route_v3.Route_DirectResponse{
+							DirectResponse: &envoy_config_route_v3.DirectResponseAction{
+								Status: 200,
+								Body: &envoy_config_core_v3.DataSource{
+									Specifier: &envoy_config_core_v3.DataSource_InlineString{
+										InlineString: "A",
+									},
+								},
+							},
+						},
+					},
+					&envoy_config_route_v3.Route{
+						Match: routePrefix("/"),
+						Action: &envoy_config_route_v3.Route_DirectResponse{
+							DirectResponse: &envoy_config_route_v3.DirectResponseAction{
+								Status: 200,
+								Body: &envoy_config_core_v3.DataSource{
+									Specifier: &envoy_config_core_v3.DataSource_InlineString{
+										InlineString: "B",
+									},
+								},
+							},
+						},
+					},
+				),
+			),
+		),
+		TypeUrl: routeType,
+	})
+
+	proxyWeightedInvalid := fixture.NewProxy("simple-weighted").WithSpec(
+		contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{Fqdn: "directresponse.projectcontour.io"},
+			Routes: []contour_v1.Route{{
+				DirectResponsePolicy: &contour_v1.HTTPDirectResponsePolicy{
+					StatusCode: 200,
+					WeightedBodies: []contour_v1.WeightedDirectResponseBody{
+						{Body: "A", Weight: 25},
+						{Body: "B", Weight: 25},
+					},
+				},
+			}},
+		})
+
+	rh.OnUpdate(proxyWeighted, proxyWeightedInvalid)
+
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http"),
+		),
+		TypeUrl: routeType,
+	})
+}
+
+func TestDirectResponsePolicy_ConfigMapTemplate(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	const body = "request %REQ(x-request-id)% failed with %RESPONSE_CODE%"
+
+	unlabelled := &core_v1.ConfigMap{
+		ObjectMeta: fixture.ObjectMeta("default/error-pages"),
+		Data: map[string]string{
+			"503.txt": body,
+		},
+	}
+	rh.OnAdd(unlabelled)
+
+	proxy := fixture.NewProxy("templated").WithSpec(
+		contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{Fqdn: "directresponse.projectcontour.io"},
+			Routes: []contour_v1.Route{{
+				DirectResponsePolicy: &contour_v1.HTTPDirectResponsePolicy{
+					StatusCode: 503,
+					BodyConfigMapRef: &contour_v1.DirectResponseConfigMapRef{
+						Name: "error-pages",
+						Key:  "503.txt",
+					},
+				},
+			}},
+		})
+
+	rh.OnAdd(proxy)
+
+	// The ConfigMap isn't labelled, so it is ignored.
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http"),
+		),
+		TypeUrl: routeType,
+	}).Status(proxy).HasError(contour_v1.ConditionTypeRouteError, "DirectResponseBodyNotFound",
+		`route.directResponsePolicy.bodyConfigMapRef default/error-pages is invalid: ConfigMap not found or missing the "projectcontour.io/direct-response" label`)
+
+	rh.OnUpdate(unlabelled, &core_v1.ConfigMap{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Namespace: "default",
+			Name:      "error-pages",
+			Labels:    map[string]string{k8s.DirectResponseConfigMapLabel: ""},
+		},
+		Data: map[string]string{
+			"503.txt": body,
+		},
+	})
+
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("directresponse.projectcontour.io",
+					&envoy_config_route_v3.Route{
+						Name:  "directresponse/462962088d",
+						Match: routePrefix("/"),
+						Action: &envoy_config_route_v3.Route_DirectResponse{
+							DirectResponse: &envoy_config_route_v3.DirectResponseAction{
+								Status: 503,
+								Body: &envoy_config_core_v3.DataSource{
+									Specifier: &envoy_config_core_v3.DataSource_InlineString{
+										InlineString: body,
+									},
+								},
+							},
+						},
+					},
+				),
+			),
+		),
+		TypeUrl: routeType,
+	}).Status(proxy).IsValid()
+
+	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			&envoy_config_listener_v3.Listener{
+				Name:    "ingress_http",
+				Address: envoy_v3.SocketAddress("0.0.0.0", 8080),
+				FilterChains: envoy_v3.FilterChains(
+					envoy_v3.HTTPConnectionManagerBuilder().
+						RouteConfigName("ingress_http").
+						MetricsPrefix("ingress_http").
+						AccessLoggers(envoy_v3.FileAccessLogEnvoy("/dev/stdout", "", nil, contour_v1alpha1.LogLevelInfo)).
+						DefaultFilters().
+						LocalReplyConfig(&envoy_filter_network_http_connection_manager_v3.LocalReplyConfig{
+							Mappers: []*envoy_filter_network_http_connection_manager_v3.ResponseMapper{{
+								Filter: &envoy_config_accesslog_v3.AccessLogFilter{
+									FilterSpecifier: &envoy_config_accesslog_v3.AccessLogFilter_ExtensionFilter{
+										ExtensionFilter: &envoy_config_accesslog_v3.ExtensionFilter{
+											Name: "envoy.access_loggers.extension_filters.cel",
+											ConfigType: &envoy_config_accesslog_v3.ExtensionFilter_TypedConfig{
+												TypedConfig: protobuf.MustMarshalAny(&envoy_access_loggers_filters_cel_v3.ExpressionFilter{
+													Expression: `xds.route_name == "directresponse/462962088d"`,
+												}),
+											},
+										},
+									},
+								},
+								BodyFormatOverride: &envoy_config_core_v3.SubstitutionFormatString{
+									Format: &envoy_config_core_v3.SubstitutionFormatString_TextFormatSource{
+										TextFormatSource: &envoy_config_core_v3.DataSource{
+											Specifier: &envoy_config_core_v3.DataSource_InlineString{
+												InlineString: body,
+											},
+										},
+									},
+								},
+							}},
+						}).
+						Get(),
+				),
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			},
+			statsListener(),
+		),
+		TypeUrl: listenerType,
+	})
 }
@@ -196,6 +196,11 @@ func tlsCluster(c *envoy_config_cluster_v3.Cluster, ca *core_v1.Secret, subjectN
 		s = []*dag.Secret{{Object: ca}}
 	}
 
+	// The SNI defaults to the validated subject name.
+	if sni == "" {
+		sni = subjectName
+	}
+
 	c.TransportSocket = envoy_v3.UpstreamTLSTransportSocket(
 		envoy_v3.UpstreamTLSContext(
 			&dag.PeerValidationContext{
@@ -0,0 +1,173 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3
+
+import (
+	"testing"
+	"time"
+
+	envoy_config_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_filter_fault_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/common/fault/v3"
+	envoy_filter_http_fault_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/fault/v3"
+	envoy_filter_network_http_connection_manager_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"google.golang.org/protobuf/types/known/durationpb"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
+	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
+	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/protobuf"
+)
+
+func TestFaultInjectionPolicy(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	rh.OnAdd(fixture.NewService("svc1").
+		WithPorts(core_v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)}),
+	)
+
+	p := fixture.NewProxy("simple").WithSpec(
+		contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "hello.world",
+			},
+			Routes: []contour_v1.Route{{
+				Conditions: []contour_v1.MatchCondition{{
+					Prefix: "/chaos",
+				}},
+				FaultInjectionPolicy: &contour_v1.FaultInjectionPolicy{
+					Delay: &contour_v1.FaultDelay{
+						Percentage: 10,
+						Duration:   "2s",
+					},
+					Abort: &contour_v1.FaultAbort{
+						Percentage: 5,
+						StatusCode: 503,
+					},
+				},
+				Services: []contour_v1.Service{{
+					Name: "svc1",
+					Port: 80,
+				}},
+			}, {
+				Services: []contour_v1.Service{{
+					Name: "svc1",
+					Port: 80,
+				}},
+			}},
+		})
+	rh.OnAdd(p)
+
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("hello.world",
+					&envoy_config_route_v3.Route{
+						Match:  routePrefix("/chaos"),
+						Action: routecluster("default/svc1/80/da39a3ee5e"),
+						TypedPerFilterConfig: withFilterConfig(envoy_v3.FaultFilterName, &envoy_filter_http_fault_v3.HTTPFault{
+							Delay: &envoy_filter_fault_v3.FaultDelay{
+								FaultDelaySecifier: &envoy_filter_fault_v3.FaultDelay_FixedDelay{
+									FixedDelay: durationpb.New(2 * time.Second),
+								},
+								Percentage: &envoy_type_v3.FractionalPercent{
+									Numerator:   10,
+									Denominator: envoy_type_v3.FractionalPercent_HUNDRED,
+								},
+							},
+							Abort: &envoy_filter_http_fault_v3.FaultAbort{
+								ErrorType: &envoy_filter_http_fault_v3.FaultAbort_HttpStatus{
+									HttpStatus: 503,
+								},
+								Percentage: &envoy_type_v3.FractionalPercent{
+									Numerator:   5,
+									Denominator: envoy_type_v3.FractionalPercent_HUNDRED,
+								},
+							},
+						}),
+					},
+					&envoy_config_route_v3.Route{
+						Match:  routePrefix("/"),
+						Action: routecluster("default/svc1/80/da39a3ee5e"),
+					}),
+			),
+		),
+		TypeUrl: routeType,
+	}).Status(p).IsValid()
+
+	// The fault filter is only added when a route uses it.
+	httpListener := defaultHTTPListener()
+	httpListener.FilterChains = envoy_v3.FilterChains(
+		envoy_v3.HTTPConnectionManagerBuilder().
+			RouteConfigName("ingress_http").
+			MetricsPrefix("ingress_http").
+			AccessLoggers(envoy_v3.FileAccessLogEnvoy("/dev/stdout", "", nil, contour_v1alpha1.LogLevelInfo)).
+			DefaultFilters().
+			AddFilter(&envoy_filter_network_http_connection_manager_v3.HttpFilter{
+				Name: envoy_v3.FaultFilterName,
+				ConfigType: &envoy_filter_network_http_connection_manager_v3.HttpFilter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&envoy_filter_http_fault_v3.HTTPFault{}),
+				},
+			}).
+			Get(),
+	)
+
+	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			httpListener,
+			statsListener(),
+		),
+		TypeUrl: listenerType,
+	})
+
+	invalid := fixture.NewProxy("simple").WithSpec(
+		contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "hello.world",
+			},
+			Routes: []contour_v1.Route{{
+				FaultInjectionPolicy: &contour_v1.FaultInjectionPolicy{
+					Abort: &contour_v1.FaultAbort{
+						Percentage: 101,
+						StatusCode: 503,
+					},
+				},
+				Services: []contour_v1.Service{{
+					Name: "svc1",
+					Port: 80,
+				}},
+			}},
+		})
+	rh.OnUpdate(p, invalid)
+
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http"),
+		),
+		TypeUrl: routeType,
+	}).Status(invalid).HasError(contour_v1.ConditionTypeRouteError, "FaultInjectionPolicyNotValid",
+		"route.faultInjectionPolicy is invalid: abort percentage 101 must be between 0 and 100")
+
+	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			statsListener(),
+		),
+		TypeUrl: listenerType,
+	})
+}
@@ -110,10 +110,11 @@ func setup(t *testing.T, opts ...any) (ResourceEventHandlerWrapper, *Contour, fu
 		}
 	}
 
-	snapshotHandler := xdscache_v3.NewSnapshotHandler(resources, log)
-	et.SetObserver(snapshotHandler)
-
 	registry := prometheus.NewRegistry()
+	contourMetrics := metrics.NewMetrics(registry)
+
+	snapshotHandler := xdscache_v3.NewSnapshotHandler(context.Background(), resources, 0, contourMetrics, log)
+	et.SetObserver(snapshotHandler)
 
 	builder := &dag.Builder{
 		Source: dag.KubernetesCache{
@@ -153,7 +154,8 @@ func setup(t *testing.T, opts ...any) (ResourceEventHandlerWrapper, *Contour, fu
 		//nolint:gosec
 		HoldoffMaxDelay: time.Duration(rand.Intn(500)) * time.Millisecond,
 		Observer: contour.NewRebuildMetricsObserver(
-			metrics.NewMetrics(registry),
+			contourMetrics,
+			nil,
 			dag.ComposeObservers(append(xdscache.ObserversOf(resources), snapshotHandler)...),
 		),
 		Builder: builder,
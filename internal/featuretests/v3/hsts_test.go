@@ -0,0 +1,121 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3
+
+import (
+	"testing"
+
+	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_config_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
+	"github.com/projectcontour/contour/internal/featuretests"
+	"github.com/projectcontour/contour/internal/fixture"
+)
+
+func TestHSTSPolicy(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	rh.OnAdd(fixture.NewService("svc1").
+		WithPorts(core_v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)}),
+	)
+	rh.OnAdd(featuretests.TLSSecret(t, "secret", &featuretests.ServerCertificate))
+
+	p := fixture.NewProxy("simple").WithSpec(
+		contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "hello.world",
+				TLS: &contour_v1.TLS{
+					SecretName: "secret",
+					HSTS: &contour_v1.HSTSPolicy{
+						MaxAge:            31536000,
+						IncludeSubDomains: true,
+						Preload:           true,
+					},
+				},
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: "svc1",
+					Port: 80,
+				}},
+			}},
+		})
+	rh.OnAdd(p)
+
+	secureVirtualHost := envoy_v3.VirtualHost("hello.world",
+		&envoy_config_route_v3.Route{
+			Match:  routePrefix("/"),
+			Action: routecluster("default/svc1/80/da39a3ee5e"),
+		},
+	)
+	secureVirtualHost.ResponseHeadersToAdd = []*envoy_config_core_v3.HeaderValueOption{{
+		Header: &envoy_config_core_v3.HeaderValue{
+			Key:   "Strict-Transport-Security",
+			Value: "max-age=31536000; includeSubDomains; preload",
+		},
+		AppendAction: envoy_config_core_v3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+	}}
+
+	// The header is only added to the secure virtual host; the
+	// insecure virtual host redirects to HTTPS.
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: routeResources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("hello.world",
+					&envoy_config_route_v3.Route{
+						Match:                routePrefix("/"),
+						Action:               envoy_v3.UpgradeHTTPS(),
+						TypedPerFilterConfig: envoy_v3.DisabledExtAuthConfig(),
+					}),
+			),
+			envoy_v3.RouteConfiguration("https/hello.world", secureVirtualHost),
+		),
+		TypeUrl: routeType,
+	}).Status(p).IsValid()
+
+	invalid := fixture.NewProxy("simple").WithSpec(
+		contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "hello.world",
+				TLS: &contour_v1.TLS{
+					SecretName: "secret",
+					HSTS: &contour_v1.HSTSPolicy{
+						MaxAge:  31536000,
+						Preload: true,
+					},
+				},
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: "svc1",
+					Port: 80,
+				}},
+			}},
+		})
+	rh.OnUpdate(p, invalid)
+
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: routeResources(t,
+			envoy_v3.RouteConfiguration("ingress_http"),
+		),
+		TypeUrl: routeType,
+	}).Status(invalid).HasError(contour_v1.ConditionTypeTLSError, "HSTSPolicyNotValid",
+		"Spec.VirtualHost.TLS.HSTS: preload requires includeSubDomains")
+}
@@ -29,6 +29,7 @@ import (
 
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
+	"github.com/projectcontour/contour/internal/featuretests"
 	"github.com/projectcontour/contour/internal/fixture"
 )
 
@@ -218,6 +219,112 @@ func TestIPFilterPolicy(t *testing.T) {
 	rh.OnDelete(hp3)
 }
 
+func TestIPFilterPolicyTLSVirtualHost(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	s1 := fixture.NewService("backend").
+		WithPorts(core_v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)})
+	rh.OnAdd(s1)
+	rh.OnAdd(featuretests.TLSSecret(t, "secret", &featuretests.ServerCertificate))
+
+	// The virtual host allows a private range, the admin
+	// route narrows it to a single address.
+	hp := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "vhfilter",
+			Namespace: s1.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "test1.test.com",
+				TLS: &contour_v1.TLS{
+					SecretName: "secret",
+				},
+				IPAllowFilterPolicy: []contour_v1.IPFilterPolicy{{
+					Source: contour_v1.IPFilterSourcePeer,
+					CIDR:   "10.0.0.0/8",
+				}},
+			},
+			Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: s1.Name,
+					Port: 80,
+				}},
+			}, {
+				Conditions: matchconditions(prefixMatchCondition("/admin")),
+				Services: []contour_v1.Service{{
+					Name: s1.Name,
+					Port: 80,
+				}},
+				IPAllowFilterPolicy: []contour_v1.IPFilterPolicy{{
+					Source: contour_v1.IPFilterSourcePeer,
+					CIDR:   "10.1.2.3",
+				}},
+			}},
+		},
+	}
+	rh.OnAdd(hp)
+
+	ipRules := func(prefix string, prefixLen uint32) map[string]*anypb.Any {
+		return withFilterConfig(envoy_v3.RBACFilterName, &envoy_filter_http_rbac_v3.RBACPerRoute{Rbac: &envoy_filter_http_rbac_v3.RBAC{
+			Rules: &envoy_config_rbac_v3.RBAC{
+				Action: envoy_config_rbac_v3.RBAC_ALLOW,
+				Policies: map[string]*envoy_config_rbac_v3.Policy{
+					"ip-rules": {
+						Permissions: []*envoy_config_rbac_v3.Permission{
+							{
+								Rule: &envoy_config_rbac_v3.Permission_Any{Any: true},
+							},
+						},
+						Principals: []*envoy_config_rbac_v3.Principal{{
+							Identifier: &envoy_config_rbac_v3.Principal_DirectRemoteIp{
+								DirectRemoteIp: &envoy_config_core_v3.CidrRange{
+									AddressPrefix: prefix,
+									PrefixLen:     wrapperspb.UInt32(prefixLen),
+								},
+							},
+						}},
+					},
+				},
+			},
+		}})
+	}
+
+	// The virtual host filter applies to both the insecure and the
+	// secure virtual host. The route filter replaces it on the admin
+	// route of the secure virtual host; the insecure virtual host
+	// only redirects to HTTPS.
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: routeResources(t,
+			envoy_v3.RouteConfiguration("ingress_http", virtualHostWithFilters(envoy_v3.VirtualHost(hp.Spec.VirtualHost.Fqdn,
+				&envoy_config_route_v3.Route{
+					Match:                routePrefix("/admin"),
+					Action:               envoy_v3.UpgradeHTTPS(),
+					TypedPerFilterConfig: envoy_v3.DisabledExtAuthConfig(),
+				},
+				&envoy_config_route_v3.Route{
+					Match:                routePrefix("/"),
+					Action:               envoy_v3.UpgradeHTTPS(),
+					TypedPerFilterConfig: envoy_v3.DisabledExtAuthConfig(),
+				},
+			), ipRules("10.0.0.0", 8))),
+			envoy_v3.RouteConfiguration("https/test1.test.com", virtualHostWithFilters(envoy_v3.VirtualHost(hp.Spec.VirtualHost.Fqdn,
+				&envoy_config_route_v3.Route{
+					Match:                routePrefix("/admin"),
+					Action:               routeCluster("default/backend/80/da39a3ee5e"),
+					TypedPerFilterConfig: ipRules("10.1.2.3", 32),
+				},
+				&envoy_config_route_v3.Route{
+					Match:  routePrefix("/"),
+					Action: routeCluster("default/backend/80/da39a3ee5e"),
+				},
+			), ipRules("10.0.0.0", 8))),
+		),
+		TypeUrl: routeType,
+	}).Status(hp).IsValid()
+}
+
 func virtualHostWithFilters(vh *envoy_config_route_v3.VirtualHost, typedPerFilterConfig map[string]*anypb.Any) *envoy_config_route_v3.VirtualHost {
 	vh.TypedPerFilterConfig = typedPerFilterConfig
 	return vh
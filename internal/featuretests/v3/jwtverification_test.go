@@ -148,6 +148,7 @@ func TestJWTVerification(t *testing.T) {
 											CacheDuration: durationpb.New(30 * time.Second),
 										},
 									},
+									PayloadInMetadata: envoy_v3.JWTPayloadMetadataKey,
 								},
 							},
 							RequirementMap: map[string]*envoy_filter_http_jwt_authn_v3.JwtRequirement{
@@ -301,6 +302,7 @@ func TestJWTVerification(t *testing.T) {
 											CacheDuration: durationpb.New(30 * time.Second),
 										},
 									},
+									PayloadInMetadata: envoy_v3.JWTPayloadMetadataKey,
 								},
 							},
 							RequirementMap: map[string]*envoy_filter_http_jwt_authn_v3.JwtRequirement{
@@ -409,6 +411,7 @@ func TestJWTVerification(t *testing.T) {
 											CacheDuration: durationpb.New(30 * time.Second),
 										},
 									},
+									PayloadInMetadata: envoy_v3.JWTPayloadMetadataKey,
 								},
 							},
 							RequirementMap: map[string]*envoy_filter_http_jwt_authn_v3.JwtRequirement{
@@ -519,6 +522,7 @@ func TestJWTVerification(t *testing.T) {
 											CacheDuration: durationpb.New(30 * time.Second),
 										},
 									},
+									PayloadInMetadata: envoy_v3.JWTPayloadMetadataKey,
 								},
 								"provider-2": {
 									Issuer: "issuer.jwt.example.com",
@@ -534,6 +538,7 @@ func TestJWTVerification(t *testing.T) {
 											CacheDuration: durationpb.New(30 * time.Second),
 										},
 									},
+									PayloadInMetadata: envoy_v3.JWTPayloadMetadataKey,
 								},
 							},
 							RequirementMap: map[string]*envoy_filter_http_jwt_authn_v3.JwtRequirement{
@@ -633,6 +638,7 @@ func TestJWTVerification(t *testing.T) {
 											CacheDuration: durationpb.New(30 * time.Second),
 										},
 									},
+									PayloadInMetadata: envoy_v3.JWTPayloadMetadataKey,
 								},
 							},
 							RequirementMap: map[string]*envoy_filter_http_jwt_authn_v3.JwtRequirement{
@@ -781,6 +787,7 @@ func TestJWTVerification(t *testing.T) {
 											CacheDuration: durationpb.New(30 * time.Second),
 										},
 									},
+									PayloadInMetadata: envoy_v3.JWTPayloadMetadataKey,
 								},
 							},
 							RequirementMap: map[string]*envoy_filter_http_jwt_authn_v3.JwtRequirement{
@@ -945,6 +952,7 @@ func TestJWTVerification(t *testing.T) {
 											CacheDuration: durationpb.New(30 * time.Second),
 										},
 									},
+									PayloadInMetadata: envoy_v3.JWTPayloadMetadataKey,
 								},
 							},
 							RequirementMap: map[string]*envoy_filter_http_jwt_authn_v3.JwtRequirement{
@@ -1090,7 +1098,8 @@ func TestJWTVerification(t *testing.T) {
 											CacheDuration: durationpb.New(30 * time.Second),
 										},
 									},
-									Forward: true,
+									Forward:           true,
+									PayloadInMetadata: envoy_v3.JWTPayloadMetadataKey,
 								},
 							},
 							RequirementMap: map[string]*envoy_filter_http_jwt_authn_v3.JwtRequirement{
@@ -1305,6 +1314,7 @@ func TestJWTVerification_Inclusion(t *testing.T) {
 											CacheDuration: durationpb.New(30 * time.Second),
 										},
 									},
+									PayloadInMetadata: envoy_v3.JWTPayloadMetadataKey,
 								},
 							},
 							RequirementMap: map[string]*envoy_filter_http_jwt_authn_v3.JwtRequirement{
@@ -1468,6 +1478,7 @@ func TestJWTVerification_Inclusion(t *testing.T) {
 											CacheDuration: durationpb.New(30 * time.Second),
 										},
 									},
+									PayloadInMetadata: envoy_v3.JWTPayloadMetadataKey,
 								},
 							},
 							RequirementMap: map[string]*envoy_filter_http_jwt_authn_v3.JwtRequirement{
@@ -1586,6 +1597,7 @@ func TestJWTVerification_Inclusion(t *testing.T) {
 											CacheDuration: durationpb.New(30 * time.Second),
 										},
 									},
+									PayloadInMetadata: envoy_v3.JWTPayloadMetadataKey,
 								},
 							},
 							RequirementMap: map[string]*envoy_filter_http_jwt_authn_v3.JwtRequirement{
@@ -1706,6 +1718,7 @@ func TestJWTVerification_Inclusion(t *testing.T) {
 											CacheDuration: durationpb.New(30 * time.Second),
 										},
 									},
+									PayloadInMetadata: envoy_v3.JWTPayloadMetadataKey,
 								},
 								"provider-2": {
 									Issuer: "issuer.jwt.example.com",
@@ -1721,6 +1734,7 @@ func TestJWTVerification_Inclusion(t *testing.T) {
 											CacheDuration: durationpb.New(30 * time.Second),
 										},
 									},
+									PayloadInMetadata: envoy_v3.JWTPayloadMetadataKey,
 								},
 							},
 							RequirementMap: map[string]*envoy_filter_http_jwt_authn_v3.JwtRequirement{
@@ -0,0 +1,132 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3
+
+import (
+	"testing"
+
+	envoy_config_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/dag"
+	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
+	"github.com/projectcontour/contour/internal/fixture"
+)
+
+func TestLabelSelector(t *testing.T) {
+	rh, c, done := setup(t, func(b *dag.Builder) {
+		b.Source.LabelSelector = labels.SelectorFromSet(labels.Set{"shard": "a"})
+	})
+	defer done()
+
+	svc := fixture.NewService("kuard").
+		WithPorts(core_v1.ServicePort{Port: 8080, TargetPort: intstr.FromInt(8080)})
+	rh.OnAdd(svc)
+
+	proxy := func(shard, resourceVersion string) *contour_v1.HTTPProxy {
+		hp := &contour_v1.HTTPProxy{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:            "simple",
+				Namespace:       svc.Namespace,
+				ResourceVersion: resourceVersion,
+			},
+			Spec: contour_v1.HTTPProxySpec{
+				VirtualHost: &contour_v1.VirtualHost{
+					Fqdn: "hp1.example.com",
+				},
+				Routes: []contour_v1.Route{{
+					Conditions: matchconditions(prefixMatchCondition("/")),
+					Services: []contour_v1.Service{{
+						Name: svc.Name,
+						Port: 8080,
+					}},
+				}},
+			},
+		}
+		if shard != "" {
+			hp.Labels = map[string]string{"shard": shard}
+		}
+		return hp
+	}
+
+	// hp1 has no labels, so it is ignored.
+	hp1 := proxy("", "1")
+	rh.OnAdd(hp1)
+
+	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			statsListener(),
+		),
+		TypeUrl: listenerType,
+	})
+
+	// hp2 belongs to another shard, so it is ignored.
+	hp2 := proxy("b", "2")
+	rh.OnUpdate(hp1, hp2)
+
+	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			statsListener(),
+		),
+		TypeUrl: listenerType,
+	})
+
+	// assert that the route tables are empty.
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: nil,
+		TypeUrl:   routeType,
+	})
+
+	// hp3 matches the selector, so it creates the port 80 listener.
+	hp3 := proxy("a", "3")
+	rh.OnUpdate(hp2, hp3)
+
+	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			defaultHTTPListener(),
+			statsListener(),
+		),
+		TypeUrl: listenerType,
+	})
+
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("hp1.example.com",
+					&envoy_config_route_v3.Route{
+						Match:  routePrefix("/"),
+						Action: routeCluster("default/kuard/8080/da39a3ee5e"),
+					},
+				),
+			),
+		),
+		TypeUrl: routeType,
+	})
+
+	// Moving the proxy to another shard removes its listener.
+	hp4 := proxy("b", "4")
+	rh.OnUpdate(hp3, hp4)
+
+	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			statsListener(),
+		),
+		TypeUrl: listenerType,
+	})
+}
@@ -621,7 +621,7 @@ func TestLDSIngressHTTPUseProxyProtocol(t *testing.T) {
 	// assert that we now have a ingress_http listener using
 	// the proxy protocol
 	httpListener := defaultHTTPListener()
-	httpListener.ListenerFilters = envoy_v3.ListenerFilters(envoy_v3.ProxyProtocol())
+	httpListener.ListenerFilters = envoy_v3.ListenerFilters(envoy_v3.ProxyProtocol(false, nil))
 
 	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
 		VersionInfo: "1",
@@ -687,13 +687,13 @@ func TestLDSIngressHTTPSUseProxyProtocol(t *testing.T) {
 	// assert the existence of ingress_http and ingres_https and both
 	// are using proxy protocol
 	httpListener := defaultHTTPListener()
-	httpListener.ListenerFilters = envoy_v3.ListenerFilters(envoy_v3.ProxyProtocol())
+	httpListener.ListenerFilters = envoy_v3.ListenerFilters(envoy_v3.ProxyProtocol(false, nil))
 
 	httpsListener := &envoy_config_listener_v3.Listener{
 		Name:    "ingress_https",
 		Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
 		ListenerFilters: envoy_v3.ListenerFilters(
-			envoy_v3.ProxyProtocol(),
+			envoy_v3.ProxyProtocol(false, nil),
 			envoy_v3.TLSInspector(),
 		),
 		FilterChains: []*envoy_config_listener_v3.FilterChain{
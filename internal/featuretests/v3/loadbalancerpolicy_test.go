@@ -15,10 +15,12 @@ package v3
 
 import (
 	"testing"
+	"time"
 
 	envoy_config_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	envoy_config_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"google.golang.org/protobuf/types/known/durationpb"
 	core_v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
@@ -152,6 +154,76 @@ func TestLoadBalancerPolicySessionAffinity(t *testing.T) {
 	})
 }
 
+func TestLoadBalancerPolicySessionAffinityCookiePolicy(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	s1 := fixture.NewService("app").WithPorts(
+		core_v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)})
+	rh.OnAdd(s1)
+
+	rh.OnAdd(fixture.NewProxy("simple").
+		WithFQDN("www.example.com").
+		WithSpec(contour_v1.HTTPProxySpec{
+			Routes: []contour_v1.Route{{
+				Conditions: matchconditions(prefixMatchCondition("/cart")),
+				LoadBalancerPolicy: &contour_v1.LoadBalancerPolicy{
+					Strategy: "Cookie",
+					CookiePolicy: &contour_v1.CookiePolicy{
+						Name: "X-Cart-Affinity",
+						TTL:  "1h",
+						Path: "/cart",
+					},
+				},
+				Services: []contour_v1.Service{{
+					Name: s1.Name,
+					Port: 80,
+				}},
+			}},
+		}))
+
+	c.Request(clusterType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			DefaultCluster(&envoy_config_cluster_v3.Cluster{
+				Name:                 s1.Namespace + "/" + s1.Name + "/80/e4f81994fe",
+				ClusterDiscoveryType: envoy_v3.ClusterDiscoveryType(envoy_config_cluster_v3.Cluster_EDS),
+				AltStatName:          s1.Namespace + "_" + s1.Name + "_80",
+				EdsClusterConfig: &envoy_config_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   envoy_v3.ConfigSource("contour"),
+					ServiceName: s1.Namespace + "/" + s1.Name,
+				},
+				LbPolicy: envoy_config_cluster_v3.Cluster_RING_HASH,
+			}),
+		),
+		TypeUrl: clusterType,
+	})
+
+	action := routeCluster("default/app/80/e4f81994fe")
+	action.Route.HashPolicy = []*envoy_config_route_v3.RouteAction_HashPolicy{{
+		PolicySpecifier: &envoy_config_route_v3.RouteAction_HashPolicy_Cookie_{
+			Cookie: &envoy_config_route_v3.RouteAction_HashPolicy_Cookie{
+				Name: "X-Cart-Affinity",
+				Ttl:  durationpb.New(time.Hour),
+				Path: "/cart",
+			},
+		},
+	}}
+
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("www.example.com",
+					&envoy_config_route_v3.Route{
+						Match:  routePrefix("/cart"),
+						Action: action,
+					},
+				),
+			),
+		),
+		TypeUrl: routeType,
+	})
+}
+
 // Request hash load balancing is only available in httpproxy.
 func TestLoadBalancerPolicyRequestHashHeader(t *testing.T) {
 	rh, c, done := setup(t)
@@ -368,3 +440,93 @@ func TestLoadBalancerPolicyRequestHashQueryParameter(t *testing.T) {
 		TypeUrl: routeType,
 	})
 }
+
+func TestLoadBalancerPolicyMaglev(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	s1 := fixture.NewService("app").WithPorts(
+		core_v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)})
+	rh.OnAdd(s1)
+
+	proxy1 := fixture.NewProxy("simple").
+		WithFQDN("www.example.com").
+		WithSpec(contour_v1.HTTPProxySpec{
+			Routes: []contour_v1.Route{{
+				Conditions: matchconditions(prefixMatchCondition("/cart")),
+				LoadBalancerPolicy: &contour_v1.LoadBalancerPolicy{
+					Strategy: "Maglev",
+					RequestHashPolicies: []contour_v1.RequestHashPolicy{
+						{
+							HeaderHashOptions: &contour_v1.HeaderHashOptions{
+								HeaderName: "X-Some-Header",
+							},
+						},
+					},
+				},
+				Services: []contour_v1.Service{{
+					Name: s1.Name,
+					Port: 80,
+				}},
+			}},
+		})
+	rh.OnAdd(proxy1)
+
+	c.Request(clusterType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			DefaultCluster(&envoy_config_cluster_v3.Cluster{
+				Name:                 s1.Namespace + "/" + s1.Name + "/80/843e4ded8f",
+				ClusterDiscoveryType: envoy_v3.ClusterDiscoveryType(envoy_config_cluster_v3.Cluster_EDS),
+				AltStatName:          s1.Namespace + "_" + s1.Name + "_80",
+				EdsClusterConfig: &envoy_config_cluster_v3.Cluster_EdsClusterConfig{
+					EdsConfig:   envoy_v3.ConfigSource("contour"),
+					ServiceName: s1.Namespace + "/" + s1.Name,
+				},
+				LbPolicy: envoy_config_cluster_v3.Cluster_MAGLEV,
+			}),
+		),
+		TypeUrl: clusterType,
+	})
+
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("www.example.com",
+					&envoy_config_route_v3.Route{
+						Match: routePrefix("/cart"),
+						Action: withRequestHashPolicySpecifiers(
+							routeCluster("default/app/80/843e4ded8f"),
+							hashPolicySpecifier{headerName: "X-Some-Header"},
+						),
+					},
+				),
+			),
+		),
+		TypeUrl: routeType,
+	}).Status(proxy1).IsValid()
+
+	// Maglev without any hash policy is rejected.
+	proxy2 := fixture.NewProxy("simple").
+		WithFQDN("www.example.com").
+		WithSpec(contour_v1.HTTPProxySpec{
+			Routes: []contour_v1.Route{{
+				Conditions: matchconditions(prefixMatchCondition("/cart")),
+				LoadBalancerPolicy: &contour_v1.LoadBalancerPolicy{
+					Strategy: "Maglev",
+				},
+				Services: []contour_v1.Service{{
+					Name: s1.Name,
+					Port: 80,
+				}},
+			}},
+		})
+	rh.OnUpdate(proxy1, proxy2)
+
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http"),
+		),
+		TypeUrl: routeType,
+	}).Status(proxy2).HasError(contour_v1.ConditionTypeRouteError, "LoadBalancerPolicyNotValid",
+		"route.loadBalancerPolicy is invalid: Maglev strategy requires at least one valid request hash policy")
+}
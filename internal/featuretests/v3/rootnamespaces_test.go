@@ -20,6 +20,7 @@ import (
 	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	core_v1 "k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
@@ -140,3 +141,100 @@ func TestRootNamespaces(t *testing.T) {
 		TypeUrl: routeType,
 	})
 }
+
+func TestRootNamespaceSelector(t *testing.T) {
+	rh, c, done := setup(t, func(b *dag.Builder) {
+		b.Source.RootNamespaces = []string{"roots"}
+		b.Source.RootNamespaceSelector = labels.SelectorFromSet(labels.Set{"tenant": "true"})
+	})
+	defer done()
+
+	svc := fixture.NewService("tenant1/kuard").
+		WithPorts(core_v1.ServicePort{Port: 8080, TargetPort: intstr.FromInt(8080)})
+	rh.OnAdd(svc)
+
+	// The tenant1 namespace is not labeled as a tenant yet.
+	ns1 := &core_v1.Namespace{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:            "tenant1",
+			ResourceVersion: "1",
+		},
+	}
+	rh.OnAdd(ns1)
+
+	hp := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "simple",
+			Namespace: svc.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "tenant1.example.com",
+			},
+			Routes: []contour_v1.Route{{
+				Conditions: matchconditions(prefixMatchCondition("/")),
+				Services: []contour_v1.Service{{
+					Name: svc.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+	rh.OnAdd(hp)
+
+	// assert that hp has no effect on the listener set.
+	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			statsListener(),
+		),
+		TypeUrl: listenerType,
+	})
+
+	// Labeling the namespace makes hp eligible as a root.
+	ns2 := &core_v1.Namespace{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:            "tenant1",
+			ResourceVersion: "2",
+			Labels:          map[string]string{"tenant": "true"},
+		},
+	}
+	rh.OnUpdate(ns1, ns2)
+
+	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			defaultHTTPListener(),
+			statsListener(),
+		),
+		TypeUrl: listenerType,
+	})
+
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("tenant1.example.com",
+					&envoy_config_route_v3.Route{
+						Match:  routePrefix("/"),
+						Action: routeCluster("tenant1/kuard/8080/da39a3ee5e"),
+					},
+				),
+			),
+		),
+		TypeUrl: routeType,
+	})
+
+	// Removing the label revokes it again.
+	ns3 := &core_v1.Namespace{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:            "tenant1",
+			ResourceVersion: "3",
+		},
+	}
+	rh.OnUpdate(ns2, ns3)
+
+	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			statsListener(),
+		),
+		TypeUrl: listenerType,
+	})
+}
@@ -1678,6 +1678,155 @@ func TestHTTPProxyRouteWithTLS_InsecurePaths_DisablePermitInsecureTrue(t *testin
 	})
 }
 
+func TestHTTPProxyRouteWithTLS_VirtualHostPermitInsecure(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	rh.OnAdd(fixture.NewService("kuard").
+		WithPorts(core_v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)}))
+
+	rh.OnAdd(fixture.NewService("svc2").
+		WithPorts(core_v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)}))
+
+	rh.OnAdd(featuretests.TLSSecret(t, "example-tls", &featuretests.ServerCertificate))
+
+	// /insecure inherits the virtual host's permitInsecure, /secure
+	// overrides it.
+	proxy1 := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "simple",
+			Namespace: "default",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn:           "test2.test.com",
+				PermitInsecure: true,
+				TLS: &contour_v1.TLS{
+					SecretName: "example-tls",
+				},
+			},
+			Routes: []contour_v1.Route{{
+				Conditions: conditions(prefixCondition("/insecure")),
+				Services: []contour_v1.Service{{
+					Name: "kuard",
+					Port: 80,
+				}},
+			}, {
+				Conditions: conditions(prefixCondition("/secure")),
+				RequireTLS: true,
+				Services: []contour_v1.Service{{
+					Name: "svc2",
+					Port: 80,
+				}},
+			}},
+		},
+	}
+
+	rh.OnAdd(proxy1)
+
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		VersionInfo: "1",
+		Resources: routeResources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("test2.test.com",
+					&envoy_config_route_v3.Route{
+						Match:  routePrefix("/insecure"),
+						Action: routecluster("default/kuard/80/da39a3ee5e"),
+					},
+					&envoy_config_route_v3.Route{
+						Match:                routePrefix("/secure"),
+						Action:               envoy_v3.UpgradeHTTPS(),
+						TypedPerFilterConfig: envoy_v3.DisabledExtAuthConfig(),
+					},
+				),
+			),
+			envoy_v3.RouteConfiguration("https/test2.test.com",
+				envoy_v3.VirtualHost("test2.test.com",
+					&envoy_config_route_v3.Route{
+						Match:  routePrefix("/insecure"),
+						Action: routecluster("default/kuard/80/da39a3ee5e"),
+					},
+					&envoy_config_route_v3.Route{
+						Match:  routePrefix("/secure"),
+						Action: routecluster("default/svc2/80/da39a3ee5e"),
+					},
+				),
+			),
+		),
+		TypeUrl: routeType,
+		Nonce:   "1",
+	})
+}
+
+func TestHTTPProxyRouteWithTLS_VirtualHostPermitInsecure_DisablePermitInsecureTrue(t *testing.T) {
+	rh, c, done := setup(t, func(b *dag.Builder) {
+		b.Processors = []dag.Processor{
+			&dag.ListenerProcessor{},
+			&dag.IngressProcessor{},
+			&dag.HTTPProxyProcessor{
+				DisablePermitInsecure: true,
+			},
+		}
+	})
+
+	defer done()
+
+	rh.OnAdd(fixture.NewService("kuard").
+		WithPorts(core_v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)}))
+
+	rh.OnAdd(featuretests.TLSSecret(t, "example-tls", &featuretests.ServerCertificate))
+
+	proxy1 := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "simple",
+			Namespace: "default",
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn:           "test2.test.com",
+				PermitInsecure: true,
+				TLS: &contour_v1.TLS{
+					SecretName: "example-tls",
+				},
+			},
+			Routes: []contour_v1.Route{{
+				Conditions: conditions(prefixCondition("/insecure")),
+				Services: []contour_v1.Service{{
+					Name: "kuard",
+					Port: 80,
+				}},
+			}},
+		},
+	}
+
+	rh.OnAdd(proxy1)
+
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		VersionInfo: "1",
+		Resources: routeResources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("test2.test.com",
+					&envoy_config_route_v3.Route{
+						Match:                routePrefix("/insecure"),
+						Action:               envoy_v3.UpgradeHTTPS(),
+						TypedPerFilterConfig: envoy_v3.DisabledExtAuthConfig(),
+					},
+				),
+			),
+			envoy_v3.RouteConfiguration("https/test2.test.com",
+				envoy_v3.VirtualHost("test2.test.com",
+					&envoy_config_route_v3.Route{
+						Match:  routePrefix("/insecure"),
+						Action: routecluster("default/kuard/80/da39a3ee5e"),
+					},
+				),
+			),
+		),
+		TypeUrl: routeType,
+		Nonce:   "1",
+	})
+}
+
 func TestRDSHTTPProxyRootCannotDelegateToAnotherRoot(t *testing.T) {
 	rh, c, done := setup(t)
 	defer done()
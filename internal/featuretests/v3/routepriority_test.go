@@ -0,0 +1,142 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3
+
+import (
+	"testing"
+
+	envoy_config_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/dag"
+	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
+	"github.com/projectcontour/contour/internal/fixture"
+)
+
+func TestRoutePriority(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	rh.OnAdd(fixture.NewService("svc-a").
+		WithPorts(core_v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)}),
+	)
+	rh.OnAdd(fixture.NewService("svc-b").
+		WithPorts(core_v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)}),
+	)
+
+	teamA := func(priority int32) *contour_v1.HTTPProxy {
+		return fixture.NewProxy("team-a").WithSpec(
+			contour_v1.HTTPProxySpec{
+				Routes: []contour_v1.Route{{
+					Conditions: matchconditions(
+						prefixMatchCondition("/api"),
+						headerPresentMatchCondition("x-team-a"),
+						headerPresentMatchCondition("x-canary"),
+					),
+					Priority: priority,
+					Services: []contour_v1.Service{{
+						Name: "svc-a",
+						Port: 80,
+					}},
+				}},
+			})
+	}
+
+	rh.OnAdd(fixture.NewProxy("root").WithSpec(
+		contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "hello.world",
+			},
+			Includes: []contour_v1.Include{
+				{Name: "team-a"},
+				{Name: "team-b"},
+			},
+		}),
+	)
+	rh.OnAdd(fixture.NewProxy("team-b").WithSpec(
+		contour_v1.HTTPProxySpec{
+			Routes: []contour_v1.Route{{
+				Conditions: matchconditions(
+					prefixMatchCondition("/api"),
+					headerPresentMatchCondition("x-team-b"),
+				),
+				Services: []contour_v1.Service{{
+					Name: "svc-b",
+					Port: 80,
+				}},
+			}},
+		}),
+	)
+
+	routeA := &envoy_config_route_v3.Route{
+		Match: routePrefixWithHeaderConditions("/api", dag.HeaderMatchCondition{
+			Name:      "x-canary",
+			MatchType: "present",
+		}, dag.HeaderMatchCondition{
+			Name:      "x-team-a",
+			MatchType: "present",
+		}),
+		Action: routeCluster("default/svc-a/80/da39a3ee5e"),
+	}
+	routeB := &envoy_config_route_v3.Route{
+		Match: routePrefixWithHeaderConditions("/api", dag.HeaderMatchCondition{
+			Name:      "x-team-b",
+			MatchType: "present",
+		}),
+		Action: routeCluster("default/svc-b/80/da39a3ee5e"),
+	}
+
+	// With equal priorities, the route with more header conditions sorts first.
+	p := teamA(0)
+	rh.OnAdd(p)
+
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("hello.world", routeA, routeB),
+			),
+		),
+		TypeUrl: routeType,
+	}).Status(p).IsValid()
+
+	// A higher priority value moves the team-a route after the team-b route,
+	// even though it has more header conditions.
+	lower := teamA(10)
+	rh.OnUpdate(p, lower)
+
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("hello.world", routeB, routeA),
+			),
+		),
+		TypeUrl: routeType,
+	}).Status(lower).IsValid()
+
+	invalid := teamA(256)
+	rh.OnUpdate(lower, invalid)
+
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("hello.world", routeB),
+			),
+		),
+		TypeUrl: routeType,
+	}).Status(invalid).HasError(contour_v1.ConditionTypeRouteError, "PriorityNotValid",
+		"route.priority 256 must be between 0 and 255")
+}
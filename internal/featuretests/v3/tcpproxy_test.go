@@ -15,10 +15,14 @@ package v3
 
 import (
 	"testing"
+	"time"
 
+	envoy_config_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	envoy_config_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	envoy_config_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_clusters_aggregate_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/clusters/aggregate/v3"
 	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"google.golang.org/protobuf/types/known/durationpb"
 	core_v1 "k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -27,6 +31,7 @@ import (
 	envoy_v3 "github.com/projectcontour/contour/internal/envoy/v3"
 	"github.com/projectcontour/contour/internal/featuretests"
 	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/protobuf"
 )
 
 func TestTCPProxy(t *testing.T) {
@@ -897,3 +902,89 @@ func TestTCPProxyInvalidLoadBalancerPolicy(t *testing.T) {
 		TypeUrl: clusterType,
 	})
 }
+
+func TestTCPProxyFailover(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	primary := fixture.NewService("primary").
+		WithPorts(core_v1.ServicePort{Port: 5432, TargetPort: intstr.FromInt(5432)})
+	standby := fixture.NewService("standby").
+		WithPorts(core_v1.ServicePort{Port: 5432, TargetPort: intstr.FromInt(5432)})
+
+	rh.OnAdd(primary)
+	rh.OnAdd(standby)
+
+	rh.OnAdd(&contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "simple",
+			Namespace: primary.Namespace,
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn: "db.example.com",
+				TLS: &contour_v1.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &contour_v1.TCPProxy{
+				Services: []contour_v1.Service{{
+					Name:             standby.Name,
+					Port:             5432,
+					FailoverPriority: 1,
+				}, {
+					Name: primary.Name,
+					Port: 5432,
+				}},
+			},
+		},
+	})
+
+	c.Request(listenerType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			&envoy_config_listener_v3.Listener{
+				Name:    "ingress_https",
+				Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
+				FilterChains: []*envoy_config_listener_v3.FilterChain{{
+					Filters: envoy_v3.Filters(
+						tcpproxy("ingress_https", "failover/default/primary/d21ca0e529"),
+					),
+					FilterChainMatch: &envoy_config_listener_v3.FilterChainMatch{
+						ServerNames: []string{"db.example.com"},
+					},
+				}},
+				ListenerFilters: envoy_v3.ListenerFilters(
+					envoy_v3.TLSInspector(),
+				),
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			},
+			statsListener(),
+		),
+		TypeUrl: listenerType,
+	})
+
+	c.Request(clusterType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			cluster("default/primary/5432/da39a3ee5e", "default/primary", "default_primary_5432"),
+			cluster("default/standby/5432/da39a3ee5e", "default/standby", "default_standby_5432"),
+			&envoy_config_cluster_v3.Cluster{
+				Name:           "failover/default/primary/d21ca0e529",
+				AltStatName:    "failover_default_primary_d21ca0e529",
+				ConnectTimeout: durationpb.New(2 * time.Second),
+				LbPolicy:       envoy_config_cluster_v3.Cluster_CLUSTER_PROVIDED,
+				ClusterDiscoveryType: &envoy_config_cluster_v3.Cluster_ClusterType{
+					ClusterType: &envoy_config_cluster_v3.Cluster_CustomClusterType{
+						Name: "envoy.clusters.aggregate",
+						TypedConfig: protobuf.MustMarshalAny(&envoy_clusters_aggregate_v3.ClusterConfig{
+							Clusters: []string{
+								"default/primary/5432/da39a3ee5e",
+								"default/standby/5432/da39a3ee5e",
+							},
+						}),
+					},
+				},
+			},
+		),
+		TypeUrl: clusterType,
+	})
+}
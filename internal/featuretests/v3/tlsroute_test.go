@@ -363,3 +363,166 @@ func TestTLSRoute_TLSTermination(t *testing.T) {
 		),
 	})
 }
+
+func TestTLSRoute_TLSPassthroughAndTermination(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	rh.OnAdd(fixture.NewService("svc1").
+		WithPorts(core_v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)}),
+	)
+
+	rh.OnAdd(fixture.NewService("svc2").
+		WithPorts(core_v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)}),
+	)
+
+	sec1 := featuretests.TLSSecret(t, "projectcontour/tlscert", &featuretests.ServerCertificate)
+	rh.OnAdd(sec1)
+
+	rh.OnAdd(gc)
+
+	// A terminating and a passthrough listener share port 443,
+	// and are served by a single Envoy listener.
+	rh.OnAdd(&gatewayapi_v1.Gateway{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "contour",
+			Namespace: "projectcontour",
+		},
+		Spec: gatewayapi_v1.GatewaySpec{
+			GatewayClassName: gatewayapi_v1.ObjectName(gc.Name),
+			Listeners: []gatewayapi_v1.Listener{
+				{
+					Name:     "https",
+					Port:     443,
+					Protocol: gatewayapi_v1.HTTPSProtocolType,
+					TLS: &gatewayapi_v1.GatewayTLSConfig{
+						Mode: ptr.To(gatewayapi_v1.TLSModeTerminate),
+						CertificateRefs: []gatewayapi_v1.SecretObjectReference{
+							gatewayapi.CertificateRef("tlscert", ""),
+						},
+					},
+					Hostname: ptr.To(gatewayapi_v1.Hostname("test.projectcontour.io")),
+					AllowedRoutes: &gatewayapi_v1.AllowedRoutes{
+						Namespaces: &gatewayapi_v1.RouteNamespaces{
+							From: ptr.To(gatewayapi_v1.NamespacesFromAll),
+						},
+					},
+				},
+				{
+					Name:     "tls-passthrough",
+					Port:     443,
+					Protocol: gatewayapi_v1.TLSProtocolType,
+					TLS: &gatewayapi_v1.GatewayTLSConfig{
+						Mode: ptr.To(gatewayapi_v1.TLSModePassthrough),
+					},
+					Hostname: ptr.To(gatewayapi_v1.Hostname("*.projectcontour.io")),
+					AllowedRoutes: &gatewayapi_v1.AllowedRoutes{
+						Namespaces: &gatewayapi_v1.RouteNamespaces{
+							From: ptr.To(gatewayapi_v1.NamespacesFromAll),
+						},
+					},
+				},
+			},
+		},
+	})
+
+	rh.OnAdd(&gatewayapi_v1.HTTPRoute{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "terminate",
+			Namespace: "default",
+		},
+		Spec: gatewayapi_v1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayapi_v1.CommonRouteSpec{
+				ParentRefs: []gatewayapi_v1.ParentReference{
+					gatewayapi.GatewayParentRef("projectcontour", "contour"),
+				},
+			},
+			Hostnames: []gatewayapi_v1.Hostname{"test.projectcontour.io"},
+			Rules: []gatewayapi_v1.HTTPRouteRule{{
+				Matches:     gatewayapi.HTTPRouteMatch(gatewayapi_v1.PathMatchPathPrefix, "/"),
+				BackendRefs: gatewayapi.HTTPBackendRef("svc1", 80, 1),
+			}},
+		},
+	})
+
+	passthrough := &gatewayapi_v1alpha2.TLSRoute{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "passthrough",
+			Namespace: "default",
+		},
+		Spec: gatewayapi_v1alpha2.TLSRouteSpec{
+			CommonRouteSpec: gatewayapi_v1.CommonRouteSpec{
+				ParentRefs: []gatewayapi_v1.ParentReference{
+					gatewayapi.GatewayParentRef("projectcontour", "contour"),
+				},
+			},
+			Hostnames: []gatewayapi_v1.Hostname{"tcp.projectcontour.io"},
+			Rules: []gatewayapi_v1alpha2.TLSRouteRule{{
+				BackendRefs: gatewayapi.TLSRouteBackendRef("svc2", 80, nil),
+			}},
+		},
+	}
+	rh.OnAdd(passthrough)
+
+	// Passthrough SNIs get a TCP proxy filter chain and terminating
+	// SNIs get an HTTP connection manager filter chain.
+	c.Request(listenerType, "https-443").Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		TypeUrl: listenerType,
+		Resources: resources(t,
+			&envoy_config_listener_v3.Listener{
+				Name:    "https-443",
+				Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
+				ListenerFilters: envoy_v3.ListenerFilters(
+					envoy_v3.TLSInspector(),
+				),
+				FilterChains: appendFilterChains(
+					&envoy_config_listener_v3.FilterChain{
+						Filters: envoy_v3.Filters(
+							tcpproxy("https-443", "default/svc2/80/da39a3ee5e"),
+						),
+						FilterChainMatch: &envoy_config_listener_v3.FilterChainMatch{
+							ServerNames: []string{"tcp.projectcontour.io"},
+						},
+					},
+					filterchaintls("test.projectcontour.io", sec1,
+						httpsFilterForGateway("https-443", "test.projectcontour.io"),
+						nil, "h2", "http/1.1"),
+				),
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			},
+		),
+	})
+
+	// A passthrough route for a hostname that is already
+	// terminated does not replace the terminating filter chain.
+	conflicting := passthrough.DeepCopy()
+	conflicting.Spec.Hostnames = append(conflicting.Spec.Hostnames, "test.projectcontour.io")
+	rh.OnUpdate(passthrough, conflicting)
+
+	c.Request(listenerType, "https-443").Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		TypeUrl: listenerType,
+		Resources: resources(t,
+			&envoy_config_listener_v3.Listener{
+				Name:    "https-443",
+				Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
+				ListenerFilters: envoy_v3.ListenerFilters(
+					envoy_v3.TLSInspector(),
+				),
+				FilterChains: appendFilterChains(
+					&envoy_config_listener_v3.FilterChain{
+						Filters: envoy_v3.Filters(
+							tcpproxy("https-443", "default/svc2/80/da39a3ee5e"),
+						),
+						FilterChainMatch: &envoy_config_listener_v3.FilterChainMatch{
+							ServerNames: []string{"tcp.projectcontour.io"},
+						},
+					},
+					filterchaintls("test.projectcontour.io", sec1,
+						httpsFilterForGateway("https-443", "test.projectcontour.io"),
+						nil, "h2", "http/1.1"),
+				),
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			},
+		),
+	})
+}
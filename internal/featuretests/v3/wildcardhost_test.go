@@ -169,6 +169,79 @@ func TestHTTPProxyWildcardFQDN(t *testing.T) {
 	})
 }
 
+func TestHTTPProxyWildcardFQDNRegex(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	svc := fixture.NewService("svc").
+		WithPorts(core_v1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)})
+	rh.OnAdd(svc)
+
+	p := fixture.NewProxy("wildcard").WithSpec(
+		contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn:      "*.projectcontour.io",
+				FqdnRegex: `[a-z]+\.(eu|us)\.projectcontour\.io`,
+			}, Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: "svc",
+					Port: 80,
+				}},
+			}},
+		})
+	rh.OnAdd(p)
+
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http",
+				envoy_v3.VirtualHost("*.projectcontour.io", &envoy_config_route_v3.Route{
+					Match: &envoy_config_route_v3.RouteMatch{
+						PathSpecifier: &envoy_config_route_v3.RouteMatch_Prefix{
+							Prefix: "/",
+						},
+						Headers: []*envoy_config_route_v3.HeaderMatcher{{
+							Name: ":authority",
+							HeaderMatchSpecifier: &envoy_config_route_v3.HeaderMatcher_StringMatch{
+								StringMatch: &envoy_matcher_v3.StringMatcher{
+									MatchPattern: &envoy_matcher_v3.StringMatcher_SafeRegex{
+										SafeRegex: &envoy_matcher_v3.RegexMatcher{
+											Regex: "(?:[a-z]+\\.(eu|us)\\.projectcontour\\.io)(:[0-9]+)?",
+										},
+									},
+								},
+							},
+						}},
+					},
+					Action: routecluster("default/svc/80/da39a3ee5e"),
+				}),
+			),
+		),
+		TypeUrl: routeType,
+	}).Status(p).IsValid()
+
+	invalid := fixture.NewProxy("wildcard").WithSpec(
+		contour_v1.HTTPProxySpec{
+			VirtualHost: &contour_v1.VirtualHost{
+				Fqdn:      "*.projectcontour.io",
+				FqdnRegex: "[a-z",
+			}, Routes: []contour_v1.Route{{
+				Services: []contour_v1.Service{{
+					Name: "svc",
+					Port: 80,
+				}},
+			}},
+		})
+	rh.OnUpdate(p, invalid)
+
+	c.Request(routeType).Equals(&envoy_service_discovery_v3.DiscoveryResponse{
+		Resources: resources(t,
+			envoy_v3.RouteConfiguration("ingress_http"),
+		),
+		TypeUrl: routeType,
+	}).Status(invalid).HasError(contour_v1.ConditionTypeVirtualHostError, "FQDNRegexNotValid",
+		"Spec.VirtualHost.FqdnRegex \"[a-z\" is invalid: error parsing regexp: missing closing ]: `[a-z`")
+}
+
 // Test Ingress with wildcard host and TLS secret for the same wildcard generates
 // the correct filter chain and secret.
 func TestIngressWildcardHostHTTPSWildcardSecret(t *testing.T) {
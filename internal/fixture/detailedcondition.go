@@ -66,6 +66,20 @@ func (dcb *DetailedConditionBuilder) WithErrorf(errorType, reason, formatmsg str
 	return *dc
 }
 
+func (dcb *DetailedConditionBuilder) WithRouteError(route, errorType, reason, message string) contour_v1.DetailedCondition {
+	dc := (*contour_v1.DetailedCondition)(dcb)
+	dc.AddRouteError(route, errorType, reason, message)
+
+	return *dc
+}
+
+func (dcb *DetailedConditionBuilder) WithRouteErrorf(route, errorType, reason, formatmsg string, args ...any) contour_v1.DetailedCondition {
+	dc := (*contour_v1.DetailedCondition)(dcb)
+	dc.AddRouteErrorf(route, errorType, reason, formatmsg, args...)
+
+	return *dc
+}
+
 func (dcb *DetailedConditionBuilder) WithWarning(errorType, reason, message string) contour_v1.DetailedCondition {
 	dc := (*contour_v1.DetailedCondition)(dcb)
 	dc.AddWarning(errorType, reason, message)
@@ -35,3 +35,16 @@ func Handler(client *kubernetes.Clientset) http.Handler {
 		fmt.Fprintln(w, "OK")
 	})
 }
+
+// ReadyHandler returns a http Handler for a readiness endpoint, which
+// fails until ready returns true.
+func ReadyHandler(ready func() bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if !ready() {
+			http.Error(w, "Not Ready: waiting for the initial xDS snapshot", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "OK")
+	})
+}
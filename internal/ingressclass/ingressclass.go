@@ -39,13 +39,21 @@ func MatchesIngress(obj *networking_v1.Ingress, ingressClassNames []string) bool
 
 // MatchesHTTPProxy returns true if the passed in HTTPProxy annotations
 // or Spec.IngressClassName match the passed in ingress class name.
-// Annotations take precedence over spec field if both are set.
+// The spec field takes precedence over annotations if both are set.
 func MatchesHTTPProxy(obj *contour_v1.HTTPProxy, ingressClassNames []string) bool {
-	if annotationClass := annotation.IngressClass(obj); annotationClass != "" {
-		return matches(annotationClass, ingressClassNames)
+	if obj.Spec.IngressClassName != "" {
+		return matches(obj.Spec.IngressClassName, ingressClassNames)
 	}
 
-	return matches(obj.Spec.IngressClassName, ingressClassNames)
+	return matches(annotation.IngressClass(obj), ingressClassNames)
+}
+
+// HTTPProxyConflict returns true if the passed in HTTPProxy sets both
+// Spec.IngressClassName and an ingress class annotation, to different
+// ingress classes.
+func HTTPProxyConflict(obj *contour_v1.HTTPProxy) bool {
+	annotationClass := annotation.IngressClass(obj)
+	return obj.Spec.IngressClassName != "" && annotationClass != "" && obj.Spec.IngressClassName != annotationClass
 }
 
 func matches(objIngressClass string, contourIngressClasses []string) bool {
@@ -207,7 +207,7 @@ func TestMatchesHTTPProxy(t *testing.T) {
 		},
 	}, []string{"something"}))
 	// Annotation set, spec field set, class configured
-	assert.True(t, MatchesHTTPProxy(&contour_v1.HTTPProxy{
+	assert.False(t, MatchesHTTPProxy(&contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
 			Annotations: map[string]string{
 				"kubernetes.io/ingress.class": "something",
@@ -218,7 +218,7 @@ func TestMatchesHTTPProxy(t *testing.T) {
 		},
 	}, []string{"something"}))
 	// Annotation set, spec field set, class configured
-	assert.False(t, MatchesHTTPProxy(&contour_v1.HTTPProxy{
+	assert.True(t, MatchesHTTPProxy(&contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
 			Annotations: map[string]string{
 				"kubernetes.io/ingress.class": "foo",
@@ -257,7 +257,7 @@ func TestMatchesHTTPProxy(t *testing.T) {
 		},
 	}, []string{"somethingelse", "something"}))
 	// Multiple classes: Annotation set, spec field set, class configured
-	assert.True(t, MatchesHTTPProxy(&contour_v1.HTTPProxy{
+	assert.False(t, MatchesHTTPProxy(&contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
 			Annotations: map[string]string{
 				"kubernetes.io/ingress.class": "something",
@@ -268,7 +268,7 @@ func TestMatchesHTTPProxy(t *testing.T) {
 		},
 	}, []string{"somethingelse", "something"}))
 	// Multiple classes: Annotation set, spec field set, class configured
-	assert.False(t, MatchesHTTPProxy(&contour_v1.HTTPProxy{
+	assert.True(t, MatchesHTTPProxy(&contour_v1.HTTPProxy{
 		ObjectMeta: meta_v1.ObjectMeta{
 			Annotations: map[string]string{
 				"kubernetes.io/ingress.class": "foo",
@@ -279,3 +279,44 @@ func TestMatchesHTTPProxy(t *testing.T) {
 		},
 	}, []string{"something", "somethingelse"}))
 }
+
+func TestHTTPProxyConflict(t *testing.T) {
+	// No annotation, no spec field set
+	assert.False(t, HTTPProxyConflict(&contour_v1.HTTPProxy{}))
+	// Annotation set, no spec field set
+	assert.False(t, HTTPProxyConflict(&contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Annotations: map[string]string{
+				"projectcontour.io/ingress.class": "something",
+			},
+		},
+	}))
+	// No annotation set, spec field set
+	assert.False(t, HTTPProxyConflict(&contour_v1.HTTPProxy{
+		Spec: contour_v1.HTTPProxySpec{
+			IngressClassName: "something",
+		},
+	}))
+	// Annotation and spec field set to the same class
+	assert.False(t, HTTPProxyConflict(&contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Annotations: map[string]string{
+				"kubernetes.io/ingress.class": "something",
+			},
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			IngressClassName: "something",
+		},
+	}))
+	// Annotation and spec field set to different classes
+	assert.True(t, HTTPProxyConflict(&contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Annotations: map[string]string{
+				"projectcontour.io/ingress.class": "foo",
+			},
+		},
+		Spec: contour_v1.HTTPProxySpec{
+			IngressClassName: "something",
+		},
+	}))
+}
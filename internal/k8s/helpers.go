@@ -93,6 +93,17 @@ func isStatusEqual(objA, objB any) bool {
 	return false
 }
 
+// DirectResponseConfigMapLabel is the label that must be set on a
+// ConfigMap for it to be used as a source of direct response bodies.
+const DirectResponseConfigMapLabel = "projectcontour.io/direct-response"
+
+// IsDirectResponseConfigMap returns true if the ConfigMap is labelled
+// as a source of direct response bodies.
+func IsDirectResponseConfigMap(configMap *core_v1.ConfigMap) bool {
+	_, ok := configMap.Labels[DirectResponseConfigMapLabel]
+	return ok
+}
+
 // IsObjectEqual checks if objects received during update are equal.
 //
 // Make an attempt to avoid comparing full objects since it can be very CPU intensive.
@@ -110,11 +121,11 @@ func IsObjectEqual(oldObj, newObj client.Object) (bool, error) {
 	// Status/annotations/labels changes are ignored.
 	// Generation is implemented in CRDs, Ingress and IngressClass.
 	case *contour_v1alpha1.ExtensionService,
+		*contour_v1alpha1.ContourConfiguration,
 		*contour_v1.TLSCertificateDelegation:
 		return isGenerationEqual(oldObj, newObj), nil
 
 	case *gatewayapi_v1.GatewayClass,
-		*gatewayapi_v1.Gateway,
 		*gatewayapi_v1beta1.ReferenceGrant,
 		*gatewayapi_v1.HTTPRoute,
 		*gatewayapi_v1alpha2.TLSRoute,
@@ -123,18 +134,26 @@ func IsObjectEqual(oldObj, newObj client.Object) (bool, error) {
 		*gatewayapi_v1alpha3.BackendTLSPolicy:
 		return isGenerationEqual(oldObj, newObj), nil
 
+	// Labels may select Gateways for a Contour instance.
+	case *gatewayapi_v1.Gateway:
+		return isGenerationEqual(oldObj, newObj) &&
+			apiequality.Semantic.DeepEqual(oldObj.GetLabels(), newObj.GetLabels()), nil
+
 	// Slow path: compare the content of the objects.
 	case *contour_v1.HTTPProxy,
 		*networking_v1.Ingress:
 		return isGenerationEqual(oldObj, newObj) &&
-			apiequality.Semantic.DeepEqual(oldObj.GetAnnotations(), newObj.GetAnnotations()), nil
+			apiequality.Semantic.DeepEqual(oldObj.GetAnnotations(), newObj.GetAnnotations()) &&
+			apiequality.Semantic.DeepEqual(oldObj.GetLabels(), newObj.GetLabels()), nil
 	case *core_v1.Secret:
 		if newObj, ok := newObj.(*core_v1.Secret); ok {
 			return reflect.DeepEqual(oldObj.Data, newObj.Data), nil
 		}
 	case *core_v1.ConfigMap:
 		if newObj, ok := newObj.(*core_v1.ConfigMap); ok {
-			return reflect.DeepEqual(oldObj.Data, newObj.Data), nil
+			// A label opts ConfigMaps in to being used for direct responses.
+			return reflect.DeepEqual(oldObj.Data, newObj.Data) &&
+				IsDirectResponseConfigMap(oldObj) == IsDirectResponseConfigMap(newObj), nil
 		}
 	case *core_v1.Service:
 		if newObj, ok := newObj.(*core_v1.Service); ok {
@@ -60,6 +60,11 @@ func TestIsObjectEqual(t *testing.T) {
 			filename: "testdata/configmap-metadata-change.yaml",
 			equals:   true,
 		},
+		{
+			name:     "ConfigMap with direct response label change",
+			filename: "testdata/configmap-direct-response-label-change.yaml",
+			equals:   false,
+		},
 		{
 			name:     "Service with status change",
 			filename: "testdata/service-status-change.yaml",
@@ -80,6 +85,11 @@ func TestIsObjectEqual(t *testing.T) {
 			filename: "testdata/httpproxy-annotation-change.yaml",
 			equals:   false,
 		},
+		{
+			name:     "HTTPProxy with label change",
+			filename: "testdata/httpproxy-label-change.yaml",
+			equals:   false,
+		},
 		{
 			name:     "Ingress with annotation change",
 			filename: "testdata/ingress-annotation-change.yaml",
@@ -24,6 +24,9 @@ package k8s
 
 // +kubebuilder:rbac:groups="",resources=secrets;endpoints;services;namespaces;configmaps,verbs=get;list;watch
 
+// Add RBAC policy to record Events on invalid HTTPProxies.
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
 // Add RBAC policy to support leader election.
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;get;update,namespace=projectcontour
 // +kubebuilder:rbac:groups="coordination.k8s.io",resources=leases,verbs=create;get;update,namespace=projectcontour
@@ -15,12 +15,16 @@ package k8s
 
 import (
 	"context"
+	"reflect"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -69,26 +73,134 @@ type StatusMetrics interface {
 	SetStatusUpdateDuration(duration time.Duration, kind string, onError bool)
 }
 
+const (
+	// maxStatusUpdateRetries is the number of times a failed status
+	// update is retried before it is dropped.
+	maxStatusUpdateRetries = 5
+)
+
+// statusUpdateKey identifies the status updates that supersede each
+// other: those of the same object made by the same kind of mutator.
+// Different kinds of mutators, e.g. the DAG's conditions and the load
+// balancer addresses, update different parts of an object's status.
+type statusUpdateKey struct {
+	types.NamespacedName
+	resource reflect.Type
+	mutator  reflect.Type
+}
+
+func statusUpdateKeyOf(upd StatusUpdate) statusUpdateKey {
+	return statusUpdateKey{
+		NamespacedName: upd.NamespacedName,
+		resource:       reflect.TypeOf(upd.Resource),
+		mutator:        reflect.TypeOf(upd.Mutator),
+	}
+}
+
 // StatusUpdateHandler holds the details required to actually write an Update back to the referenced object.
+// Updates are written from a work queue at a limited rate. Updates that are superseded before they are
+// written are coalesced, so only the latest is written.
 type StatusUpdateHandler struct {
-	log           logrus.FieldLogger
-	client        client.Client
-	metrics       StatusMetrics
-	sendUpdates   chan struct{}
-	updateChannel chan StatusUpdate
+	log         logrus.FieldLogger
+	client      client.Client
+	metrics     StatusMetrics
+	sendUpdates chan struct{}
+
+	// queue holds the keys of the pending updates.
+	queue workqueue.TypedRateLimitingInterface[statusUpdateKey]
+
+	// limiter limits the rate of status writes, including the first
+	// write of each update, which the queue does not rate limit.
+	limiter *rate.Limiter
+
+	// pending holds the latest update for each key in queue.
+	pendingLock sync.Mutex
+	pending     map[statusUpdateKey]StatusUpdate
 }
 
-func NewStatusUpdateHandler(log logrus.FieldLogger, client client.Client, metrics StatusMetrics) *StatusUpdateHandler {
+// NewStatusUpdateHandler returns a StatusUpdateHandler that writes at most
+// qps status updates per second with the given burst, normally those of the
+// client's REST config. A qps of zero or less leaves rate limiting to the client.
+func NewStatusUpdateHandler(log logrus.FieldLogger, client client.Client, metrics StatusMetrics, qps float32, burst int) *StatusUpdateHandler {
+	limiter := rate.NewLimiter(rate.Inf, 0)
+	if qps > 0 {
+		limiter = rate.NewLimiter(rate.Limit(qps), max(burst, 1))
+	}
+
 	return &StatusUpdateHandler{
-		log:           log,
-		client:        client,
-		metrics:       metrics,
-		sendUpdates:   make(chan struct{}),
-		updateChannel: make(chan StatusUpdate, 100),
+		log:         log,
+		client:      client,
+		metrics:     metrics,
+		sendUpdates: make(chan struct{}),
+		queue: workqueue.NewTypedRateLimitingQueueWithConfig(
+			workqueue.DefaultTypedControllerRateLimiter[statusUpdateKey](),
+			workqueue.TypedRateLimitingQueueConfig[statusUpdateKey]{Name: "status_update"},
+		),
+		limiter: limiter,
+		pending: map[statusUpdateKey]StatusUpdate{},
 	}
 }
 
-func (suh *StatusUpdateHandler) apply(upd StatusUpdate) {
+// enqueue queues the update, replacing any pending update it supersedes.
+func (suh *StatusUpdateHandler) enqueue(upd StatusUpdate) {
+	key := statusUpdateKeyOf(upd)
+
+	suh.pendingLock.Lock()
+	suh.pending[key] = upd
+	suh.pendingLock.Unlock()
+
+	suh.queue.Add(key)
+}
+
+// processNextUpdate waits for the rate limiter, then writes the latest
+// update for the next key in the queue. It returns false once the queue
+// has been shut down or ctx is done.
+func (suh *StatusUpdateHandler) processNextUpdate(ctx context.Context) bool {
+	key, shutdown := suh.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer suh.queue.Done(key)
+
+	// Updates queued while waiting supersede this one, so the
+	// latest update is read once the limiter allows the write.
+	if err := suh.limiter.Wait(ctx); err != nil {
+		return false
+	}
+
+	suh.pendingLock.Lock()
+	upd, ok := suh.pending[key]
+	delete(suh.pending, key)
+	suh.pendingLock.Unlock()
+
+	if !ok {
+		// Already written when an earlier copy of the key was processed.
+		suh.queue.Forget(key)
+		return true
+	}
+
+	suh.log.WithField("name", upd.NamespacedName.Name).
+		WithField("namespace", upd.NamespacedName.Namespace).
+		Debug("received a status update")
+
+	err := suh.apply(upd)
+	if err == nil || errors.IsNotFound(err) || suh.queue.NumRequeues(key) >= maxStatusUpdateRetries {
+		suh.queue.Forget(key)
+		return true
+	}
+
+	// Retry the update, unless it has been superseded in the meantime.
+	suh.pendingLock.Lock()
+	if _, superseded := suh.pending[key]; !superseded {
+		suh.pending[key] = upd
+		suh.queue.AddRateLimited(key)
+	}
+	suh.pendingLock.Unlock()
+
+	return true
+}
+
+func (suh *StatusUpdateHandler) apply(upd StatusUpdate) error {
 	var statusUpdateErr error
 	objKind := KindOf(upd.Resource)
 	log := suh.log.WithField("name", upd.NamespacedName.Name).
@@ -136,6 +248,8 @@ func (suh *StatusUpdateHandler) apply(upd StatusUpdate) {
 	}); statusUpdateErr != nil {
 		log.WithError(statusUpdateErr).Error("unable to update status")
 	}
+
+	return statusUpdateErr
 }
 
 func (suh *StatusUpdateHandler) NeedLeaderElection() bool {
@@ -150,25 +264,22 @@ func (suh *StatusUpdateHandler) Start(ctx context.Context) error {
 	// Enable StatusUpdaters to start sending updates to this handler.
 	close(suh.sendUpdates)
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		case upd := <-suh.updateChannel:
-			suh.log.WithField("name", upd.NamespacedName.Name).
-				WithField("namespace", upd.NamespacedName.Namespace).
-				Debug("received a status update")
+	go func() {
+		<-ctx.Done()
+		suh.queue.ShutDown()
+	}()
 
-			suh.apply(upd)
-		}
+	for suh.processNextUpdate(ctx) {
 	}
+
+	return nil
 }
 
 // Writer retrieves the interface that should be used to write to the StatusUpdateHandler.
 func (suh *StatusUpdateHandler) Writer() StatusUpdater {
 	return &StatusUpdateWriter{
-		enabled:       suh.sendUpdates,
-		updateChannel: suh.updateChannel,
+		enabled: suh.sendUpdates,
+		enqueue: suh.enqueue,
 	}
 }
 
@@ -177,18 +288,18 @@ type StatusUpdater interface {
 	Send(su StatusUpdate)
 }
 
-// StatusUpdateWriter takes status updates and sends these to the StatusUpdateHandler via a channel.
+// StatusUpdateWriter takes status updates and queues these for writing by the StatusUpdateHandler.
 type StatusUpdateWriter struct {
-	enabled       <-chan struct{}
-	updateChannel chan<- StatusUpdate
+	enabled <-chan struct{}
+	enqueue func(StatusUpdate)
 }
 
-// Send sends the given StatusUpdate off to the update channel for writing by the StatusUpdateHandler.
+// Send queues the given StatusUpdate for writing by the StatusUpdateHandler.
 func (suw *StatusUpdateWriter) Send(update StatusUpdate) {
 	// Non-blocking receive to see if we should pass along update.
 	select {
 	case <-suw.enabled:
-		suw.updateChannel <- update
+		suw.enqueue(update)
 	default:
 	}
 }
@@ -14,14 +14,20 @@
 package k8s
 
 import (
+	"context"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	networking_v1 "k8s.io/api/networking/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"github.com/projectcontour/contour/internal/fixture"
@@ -44,7 +50,7 @@ func TestStatusUpdateHandlerApplyOutputsMetrics(t *testing.T) {
 
 	mockStatusMetrics := mocks.NewStatusMetrics(t)
 
-	suh := NewStatusUpdateHandler(fixture.NewTestLogger(t), c.Build(), mockStatusMetrics)
+	suh := NewStatusUpdateHandler(fixture.NewTestLogger(t), c.Build(), mockStatusMetrics, 0, 0)
 
 	// Ingress with no status changes.
 	mockStatusMetrics.On("SetStatusUpdateTotal", "Ingress").Once()
@@ -93,3 +99,201 @@ func TestStatusUpdateHandlerApplyOutputsMetrics(t *testing.T) {
 		}),
 	))
 }
+
+type nopStatusMetrics struct{}
+
+func (nopStatusMetrics) SetStatusUpdateTotal(string)                         {}
+func (nopStatusMetrics) SetStatusUpdateSuccess(string)                       {}
+func (nopStatusMetrics) SetStatusUpdateNoop(string)                          {}
+func (nopStatusMetrics) SetStatusUpdateFailed(string)                        {}
+func (nopStatusMetrics) SetStatusUpdateConflict(string)                      {}
+func (nopStatusMetrics) SetStatusUpdateDuration(time.Duration, string, bool) {}
+
+// ingressLBMutator sets the Ingress load balancer IP.
+type ingressLBMutator string
+
+func (ip ingressLBMutator) Mutate(obj client.Object) client.Object {
+	i := obj.DeepCopyObject().(*networking_v1.Ingress)
+	i.Status.LoadBalancer.Ingress = []networking_v1.IngressLoadBalancerIngress{{IP: string(ip)}}
+	return i
+}
+
+func TestStatusUpdateHandlerCoalescesUpdates(t *testing.T) {
+	// run queues updates with before, then starts the handler and
+	// queues more updates with during. If blockFirstWrite is set, the
+	// first write blocks until during returns.
+	run := func(t *testing.T, blockFirstWrite bool, before func(suh *StatusUpdateHandler), during func(suh *StatusUpdateHandler, waitForFirstWrite func())) ([]string, *networking_v1.Ingress) {
+		t.Helper()
+
+		ingress := &networking_v1.Ingress{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "somens",
+			},
+		}
+
+		var (
+			lock    sync.Mutex
+			writes  []string
+			blocked = make(chan struct{})
+			release = make(chan struct{})
+		)
+		if !blockFirstWrite {
+			close(blocked)
+			close(release)
+		}
+
+		c := fake.NewClientBuilder().
+			WithObjects(ingress).
+			WithStatusSubresource(ingress).
+			WithInterceptorFuncs(interceptor.Funcs{
+				SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+					lock.Lock()
+					first := len(writes) == 0
+					writes = append(writes, obj.(*networking_v1.Ingress).Status.LoadBalancer.Ingress[0].IP)
+					lock.Unlock()
+
+					if first && blockFirstWrite {
+						close(blocked)
+						<-release
+					}
+					return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+				},
+			}).
+			Build()
+
+		suh := NewStatusUpdateHandler(fixture.NewTestLogger(t), c, nopStatusMetrics{}, 0, 0)
+		if before != nil {
+			before(suh)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_ = suh.Start(ctx)
+		}()
+
+		if during != nil {
+			during(suh, func() {
+				<-blocked
+			})
+		}
+		if blockFirstWrite {
+			close(release)
+		}
+
+		// Wait for the queue to drain before stopping the handler.
+		require.Eventually(t, func() bool {
+			return suh.queue.Len() == 0
+		}, 5*time.Second, 10*time.Millisecond)
+		cancel()
+		<-done
+
+		got := &networking_v1.Ingress{}
+		require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(ingress), got))
+
+		lock.Lock()
+		defer lock.Unlock()
+		return writes, got
+	}
+
+	update := func(ip string) StatusUpdate {
+		return NewStatusUpdate("foo", "somens", &networking_v1.Ingress{}, ingressLBMutator(ip))
+	}
+
+	t.Run("rapid updates coalesce to one write", func(t *testing.T) {
+		writes, got := run(t, false, func(suh *StatusUpdateHandler) {
+			for i := 1; i <= 10; i++ {
+				suh.enqueue(update(fmt.Sprintf("10.0.0.%d", i)))
+			}
+		}, nil)
+
+		assert.Equal(t, []string{"10.0.0.10"}, writes)
+		assert.Equal(t, "10.0.0.10", got.Status.LoadBalancer.Ingress[0].IP)
+	})
+
+	t.Run("updates during a write coalesce to one more write", func(t *testing.T) {
+		writes, got := run(t, true, nil, func(suh *StatusUpdateHandler, waitForFirstWrite func()) {
+			suh.enqueue(update("10.0.0.1"))
+			waitForFirstWrite()
+
+			for i := 2; i <= 10; i++ {
+				suh.enqueue(update(fmt.Sprintf("10.0.0.%d", i)))
+			}
+		})
+
+		assert.Equal(t, []string{"10.0.0.1", "10.0.0.10"}, writes)
+		assert.Equal(t, "10.0.0.10", got.Status.LoadBalancer.Ingress[0].IP)
+	})
+
+	t.Run("updates by different mutators are not coalesced", func(t *testing.T) {
+		writes, _ := run(t, true, nil, func(suh *StatusUpdateHandler, waitForFirstWrite func()) {
+			suh.enqueue(update("10.0.0.1"))
+			waitForFirstWrite()
+
+			suh.enqueue(update("10.0.0.2"))
+			suh.enqueue(NewStatusUpdate("foo", "somens", &networking_v1.Ingress{}, StatusMutatorFunc(ingressLBMutator("10.0.0.3").Mutate)))
+		})
+
+		assert.ElementsMatch(t, []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}, writes)
+	})
+}
+
+func TestStatusUpdateHandlerRateLimitsWrites(t *testing.T) {
+	var objs []client.Object
+	for i := 0; i < 3; i++ {
+		objs = append(objs, &networking_v1.Ingress{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      fmt.Sprintf("foo-%d", i),
+				Namespace: "somens",
+			},
+		})
+	}
+
+	var (
+		lock   sync.Mutex
+		writes []time.Time
+	)
+
+	c := fake.NewClientBuilder().
+		WithObjects(objs...).
+		WithStatusSubresource(objs...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				lock.Lock()
+				writes = append(writes, time.Now())
+				lock.Unlock()
+
+				return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	// Allow one write immediately and one more every 100ms.
+	suh := NewStatusUpdateHandler(fixture.NewTestLogger(t), c, nopStatusMetrics{}, 10, 1)
+
+	// Each update is the first for its object, so none of
+	// them is rate limited by the queue itself.
+	for i := range objs {
+		suh.enqueue(NewStatusUpdate(fmt.Sprintf("foo-%d", i), "somens", &networking_v1.Ingress{}, ingressLBMutator("10.0.0.1")))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = suh.Start(ctx)
+	}()
+
+	require.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(writes) == len(objs)
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+
+	// The limiter spaces the three writes over at least 200ms.
+	assert.GreaterOrEqual(t, writes[2].Sub(writes[0]), 190*time.Millisecond)
+}
@@ -21,6 +21,7 @@ import (
 	core_v1 "k8s.io/api/core/v1"
 	networking_v1 "k8s.io/api/networking/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -42,6 +43,7 @@ type StatusAddressUpdater struct {
 	Cache             cache.Cache
 	LBStatus          core_v1.LoadBalancerStatus
 	IngressClassNames []string
+	LabelSelector     labels.Selector
 	GatewayRef        *types.NamespacedName
 	StatusUpdater     StatusUpdater
 
@@ -81,6 +83,16 @@ func (s *StatusAddressUpdater) OnAdd(obj any, _ bool) {
 			Debug("unmatched ingress class, skipping status address update")
 	}
 
+	// Objects whose labels do not match are handled by another Contour.
+	if o, ok := obj.(meta_v1.Object); ok && s.LabelSelector != nil && !s.LabelSelector.Matches(labels.Set(o.GetLabels())) {
+		s.Logger.WithField("name", o.GetName()).
+			WithField("namespace", o.GetNamespace()).
+			WithField("kind", KindOf(o)).
+			WithField("label-selector", s.LabelSelector.String()).
+			Debug("unmatched labels, skipping status address update")
+		return
+	}
+
 	switch o := obj.(type) {
 	case *networking_v1.Ingress:
 		if !ingressclass.MatchesIngress(o, s.IngressClassNames) {
@@ -20,9 +20,12 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	core_v1 "k8s.io/api/core/v1"
 	networking_v1 "k8s.io/api/networking/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -197,6 +200,7 @@ func TestStatusAddressUpdater(t *testing.T) {
 	testCases := map[string]struct {
 		status           core_v1.LoadBalancerStatus
 		ingressClassName string
+		labelSelector    labels.Selector
 		preop            client.Object
 		postop           client.Object
 	}{
@@ -230,6 +234,18 @@ func TestStatusAddressUpdater(t *testing.T) {
 			preop:            simpleProxyGenerator(objName, "phony", emptyLBStatus),
 			postop:           simpleProxyGenerator(objName, "phony", ipLBStatus),
 		},
+		"proxy: non-matching label selector should not update": {
+			status:        ipLBStatus,
+			labelSelector: labels.SelectorFromSet(labels.Set{"shard": "a"}),
+			preop:         simpleProxyGenerator(objName, "", emptyLBStatus),
+			postop:        simpleProxyGenerator(objName, "", emptyLBStatus),
+		},
+		"proxy: matching label selector should update": {
+			status:        ipLBStatus,
+			labelSelector: labels.NewSelector().Add(mustRequirement(t, "shard", selection.DoesNotExist)),
+			preop:         simpleProxyGenerator(objName, "", emptyLBStatus),
+			postop:        simpleProxyGenerator(objName, "", ipLBStatus),
+		},
 		"ingress: no-op update": {
 			status:           emptyLBStatus,
 			ingressClassName: "",
@@ -302,6 +318,12 @@ func TestStatusAddressUpdater(t *testing.T) {
 			preop:            simpleIngressGenerator(objName, "other", "phony", emptyLBStatus),
 			postop:           simpleIngressGenerator(objName, "other", "phony", emptyLBStatus),
 		},
+		"ingress: non-matching label selector should not update": {
+			status:        ipLBStatus,
+			labelSelector: labels.SelectorFromSet(labels.Set{"shard": "a"}),
+			preop:         simpleIngressGenerator(objName, "", "", emptyLBStatus),
+			postop:        simpleIngressGenerator(objName, "", "", emptyLBStatus),
+		},
 		"ingress: matching ingressclass spec field should update, overrides spec field": {
 			status:           ipLBStatus,
 			ingressClassName: "phony",
@@ -318,6 +340,7 @@ func TestStatusAddressUpdater(t *testing.T) {
 			isu := StatusAddressUpdater{
 				Logger:        log,
 				LBStatus:      tc.status,
+				LabelSelector: tc.labelSelector,
 				StatusUpdater: &suc,
 			}
 			if len(tc.ingressClassName) > 0 {
@@ -337,6 +360,7 @@ func TestStatusAddressUpdater(t *testing.T) {
 			isu := StatusAddressUpdater{
 				Logger:        log,
 				LBStatus:      tc.status,
+				LabelSelector: tc.labelSelector,
 				StatusUpdater: &suc,
 			}
 			if len(tc.ingressClassName) > 0 {
@@ -351,6 +375,14 @@ func TestStatusAddressUpdater(t *testing.T) {
 	}
 }
 
+func mustRequirement(t *testing.T, key string, op selection.Operator, vals ...string) labels.Requirement {
+	t.Helper()
+
+	r, err := labels.NewRequirement(key, op, vals)
+	require.NoError(t, err)
+	return *r
+}
+
 func TestStatusAddressUpdater_Gateway(t *testing.T) {
 	log := fixture.NewTestLogger(t)
 	log.SetLevel(logrus.DebugLevel)
@@ -0,0 +1,168 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	listenerStatPrefix           = "listener."
+	downstreamActiveConnsStat    = ".downstream_cx_active"
+	listenerActiveConnsStatRegex = `^listener\..*\.downstream_cx_active$`
+)
+
+// EnvoyStatsCollector periodically polls an Envoy /stats endpoint and
+// records the number of active downstream connections of each listener
+// Contour has configured.
+type EnvoyStatsCollector struct {
+	// URL is the address of the Envoy /stats endpoint.
+	URL string
+
+	// Interval is the time between polls.
+	Interval time.Duration
+
+	// Client is used to poll Envoy. If nil, http.DefaultClient is used.
+	Client *http.Client
+
+	// ListenerNames returns the names of Contour's listeners keyed by
+	// the prefix Envoy uses for their stats. Stats of listeners not
+	// returned, such as Envoy's admin listener, are dropped.
+	ListenerNames func() map[string]string
+
+	// Metrics records the polled values.
+	Metrics *Metrics
+
+	logrus.FieldLogger
+}
+
+// NeedLeaderElection is included to implement manager.LeaderElectionRunnable
+func (c *EnvoyStatsCollector) NeedLeaderElection() bool {
+	return false
+}
+
+// Start polls Envoy every Interval until the context is done.
+func (c *EnvoyStatsCollector) Start(ctx context.Context) error {
+	c.WithField("url", c.URL).WithField("interval", c.Interval).Info("started envoy stats collector")
+	defer c.Info("stopped envoy stats collector")
+
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.poll(ctx); err != nil {
+				c.WithError(err).Warn("failed to poll envoy stats")
+				// Drop values that can no longer be refreshed.
+				c.Metrics.SetListenerActiveConnections(nil)
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// poll fetches the listener stats from Envoy and records them.
+func (c *EnvoyStatsCollector) poll(ctx context.Context) error {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return err
+	}
+	query := u.Query()
+	query.Set("filter", listenerActiveConnsStatRegex)
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %q from %s", resp.Status, c.URL)
+	}
+
+	connections, err := parseListenerActiveConnections(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	c.Metrics.SetListenerActiveConnections(byListenerName(connections, c.ListenerNames()))
+	return nil
+}
+
+// parseListenerActiveConnections parses the text output of Envoy's /stats
+// endpoint and returns the active downstream connections keyed by the
+// listener's stats prefix. Per-worker stats are ignored.
+func parseListenerActiveConnections(r io.Reader) (map[string]float64, error) {
+	connections := map[string]float64{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(scanner.Text(), ": ")
+		if !ok {
+			continue
+		}
+
+		listener, ok := strings.CutPrefix(name, listenerStatPrefix)
+		if !ok {
+			continue
+		}
+		listener, ok = strings.CutSuffix(listener, downstreamActiveConnsStat)
+		if !ok || strings.Contains(listener, ".worker_") {
+			continue
+		}
+
+		count, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for stat %q: %v", name, err)
+		}
+		connections[listener] = count
+	}
+
+	return connections, scanner.Err()
+}
+
+// byListenerName re-keys connections from Envoy stats prefixes to
+// listener names, dropping prefixes that have no name.
+func byListenerName(connections map[string]float64, names map[string]string) map[string]float64 {
+	named := make(map[string]float64, len(connections))
+	for prefix, count := range connections {
+		if name, ok := names[prefix]; ok {
+			named[name] = count
+		}
+	}
+	return named
+}
@@ -0,0 +1,96 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectcontour/contour/internal/fixture"
+)
+
+func TestParseListenerActiveConnections(t *testing.T) {
+	stats := `listener.0.0.0.0_8080.downstream_cx_active: 3
+listener.0.0.0.0_8080.downstream_cx_total: 120
+listener.0.0.0.0_8443.downstream_cx_active: 12
+listener.0.0.0.0_8443.worker_0.downstream_cx_active: 7
+listener.admin.downstream_cx_active: 1
+cluster.default/kuard/80.upstream_cx_active: 4
+`
+
+	got, err := parseListenerActiveConnections(strings.NewReader(stats))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{
+		"0.0.0.0_8080": 3,
+		"0.0.0.0_8443": 12,
+		"admin":        1,
+	}, got)
+
+	_, err = parseListenerActiveConnections(strings.NewReader("listener.0.0.0.0_8080.downstream_cx_active: many\n"))
+	require.Error(t, err)
+}
+
+func TestEnvoyStatsCollectorPoll(t *testing.T) {
+	var filter string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filter = r.URL.Query().Get("filter")
+		fmt.Fprintln(w, "listener.0.0.0.0_8080.downstream_cx_active: 5")
+		fmt.Fprintln(w, "listener.[__]_8443.downstream_cx_active: 2")
+		fmt.Fprintln(w, "listener.0.0.0.0_8002.downstream_cx_active: 1")
+		fmt.Fprintln(w, "listener.admin.downstream_cx_active: 1")
+	}))
+	defer srv.Close()
+
+	r := prometheus.NewRegistry()
+	c := &EnvoyStatsCollector{
+		URL:      srv.URL + "/stats",
+		Interval: time.Second,
+		ListenerNames: func() map[string]string {
+			return map[string]string{
+				"0.0.0.0_8080": "ingress_http",
+				"[__]_8443":    "ingress_https",
+			}
+		},
+		Metrics:     NewMetrics(r),
+		FieldLogger: fixture.NewTestLogger(t),
+	}
+	require.NoError(t, c.poll(context.Background()))
+	assert.Equal(t, listenerActiveConnsStatRegex, filter)
+
+	gathering, err := r.Gather()
+	require.NoError(t, err)
+
+	got := map[string]float64{}
+	for _, mf := range gathering {
+		if mf.GetName() != ListenerActiveConnectionsGauge {
+			continue
+		}
+		for _, m := range mf.Metric {
+			got[m.Label[0].GetValue()] = m.GetGauge().GetValue()
+		}
+	}
+	assert.Equal(t, map[string]float64{
+		"ingress_http":  5,
+		"ingress_https": 2,
+	}, got)
+}
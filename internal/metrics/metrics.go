@@ -16,6 +16,7 @@ package metrics
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -34,10 +35,13 @@ type Metrics struct {
 	proxyValidGauge     *prometheus.GaugeVec
 	proxyOrphanedGauge  *prometheus.GaugeVec
 
+	proxyInvalidReasonGauge *prometheus.GaugeVec
+
 	dagRebuildGauge             prometheus.Gauge
 	dagCacheObjectGauge         *prometheus.GaugeVec
 	dagRebuildTotal             prometheus.Counter
 	DAGRebuildSeconds           prometheus.Summary
+	dagRebuildSnapshotTotal     *prometheus.CounterVec
 	CacheHandlerOnUpdateSummary prometheus.Summary
 	EventHandlerOperations      *prometheus.CounterVec
 
@@ -48,6 +52,13 @@ type Metrics struct {
 	statusUpdateNoop            *prometheus.CounterVec
 	statusUpdateDurationSeconds *prometheus.SummaryVec
 
+	tlsCertificateExpiringGauge *prometheus.GaugeVec
+
+	listenerActiveConnectionsGauge *prometheus.GaugeVec
+
+	xdsSnapshotResourcesGauge   *prometheus.GaugeVec
+	xdsSnapshotVersionInfoGauge *prometheus.GaugeVec
+
 	// Keep a local cache of metrics for comparison on updates
 	proxyMetricCache *RouteMetric
 }
@@ -75,10 +86,13 @@ const (
 	HTTPProxyValidGauge     = "contour_httpproxy_valid"
 	HTTPProxyOrphanedGauge  = "contour_httpproxy_orphaned"
 
+	HTTPProxyInvalidReasonGauge = "contour_httpproxy_invalid_reason"
+
 	DAGCacheObjectGauge         = "contour_dag_cache_object"
 	DAGRebuildGauge             = "contour_dagrebuild_timestamp"
 	DAGRebuildTotal             = "contour_dagrebuild_total"
 	DAGRebuildSeconds           = "contour_dagrebuild_seconds"
+	DAGRebuildSnapshotTotal     = "contour_dag_rebuild_snapshot_total"
 	cacheHandlerOnUpdateSummary = "contour_cachehandler_onupdate_duration_seconds"
 	eventHandlerOperations      = "contour_eventhandler_operation_total"
 
@@ -88,8 +102,23 @@ const (
 	statusUpdateConflict        = "contour_status_update_conflict_total"
 	statusUpdateNoop            = "contour_status_update_noop_total"
 	statusUpdateDurationSeconds = "contour_status_update_duration_seconds"
+
+	TLSCertificateExpiringGauge = "contour_tls_certificate_expiring"
+
+	ListenerActiveConnectionsGauge = "contour_listener_downstream_active_connections"
+
+	XDSSnapshotResourcesGauge   = "contour_xds_snapshot_resources"
+	XDSSnapshotVersionInfoGauge = "contour_xds_snapshot_version_info"
 )
 
+// TLSCertificateExpiryBuckets are the windows, keyed by bucket label, used to
+// count TLS certificates that are close to expiry. Buckets are cumulative, so
+// a certificate expiring within 7 days is also counted in the 30 day bucket.
+var TLSCertificateExpiryBuckets = map[string]time.Duration{
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+}
+
 // NewMetrics creates a new set of metrics and registers them with
 // the supplied registry.
 //
@@ -141,6 +170,13 @@ func NewMetrics(registry *prometheus.Registry) *Metrics {
 			},
 			[]string{"namespace"},
 		),
+		proxyInvalidReasonGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: HTTPProxyInvalidReasonGauge,
+				Help: "Number of invalid HTTPProxies by the reason of their errors, as of the last DAG rebuild.",
+			},
+			[]string{"reason"},
+		),
 		dagRebuildGauge: prometheus.NewGauge(
 			prometheus.GaugeOpts{
 				Name: DAGRebuildGauge,
@@ -176,6 +212,13 @@ func NewMetrics(registry *prometheus.Registry) *Metrics {
 				},
 			},
 		),
+		dagRebuildSnapshotTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: DAGRebuildSnapshotTotal,
+				Help: "Total number of DAG rebuilds by whether the rebuild changed the xDS resources sent to Envoy.",
+			},
+			[]string{"changed"},
+		),
 		CacheHandlerOnUpdateSummary: prometheus.NewSummary(prometheus.SummaryOpts{
 			Name:       cacheHandlerOnUpdateSummary,
 			Help:       "Histogram for the runtime of xDS cache regeneration.",
@@ -240,6 +283,34 @@ func NewMetrics(registry *prometheus.Registry) *Metrics {
 			},
 			[]string{"kind", "error"},
 		),
+		tlsCertificateExpiringGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: TLSCertificateExpiringGauge,
+				Help: "Number of TLS certificates referenced by virtual hosts that expire within the bucket duration, including already expired certificates. Recomputed on every DAG rebuild and every minute.",
+			},
+			[]string{"bucket"},
+		),
+		listenerActiveConnectionsGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: ListenerActiveConnectionsGauge,
+				Help: "Number of active downstream connections of each Envoy listener, as last polled from Envoy's stats endpoint.",
+			},
+			[]string{"listener"},
+		),
+		xdsSnapshotResourcesGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: XDSSnapshotResourcesGauge,
+				Help: "Number of resources of each xDS type in the current xDS snapshot.",
+			},
+			[]string{"type"},
+		),
+		xdsSnapshotVersionInfoGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: XDSSnapshotVersionInfoGauge,
+				Help: "Version of the current xDS snapshot of each snapshot cache. The value is always 1.",
+			},
+			[]string{"cache", "version"},
+		),
 	}
 	m.buildInfoGauge.WithLabelValues(build.Branch, build.Sha, build.Version).Set(1)
 	m.register(registry)
@@ -255,10 +326,12 @@ func (m *Metrics) register(registry *prometheus.Registry) {
 		m.proxyInvalidGauge,
 		m.proxyValidGauge,
 		m.proxyOrphanedGauge,
+		m.proxyInvalidReasonGauge,
 		m.dagRebuildGauge,
 		m.dagRebuildTotal,
 		m.dagCacheObjectGauge,
 		m.DAGRebuildSeconds,
+		m.dagRebuildSnapshotTotal,
 		m.CacheHandlerOnUpdateSummary,
 		m.EventHandlerOperations,
 		m.statusUpdateTotal,
@@ -267,6 +340,10 @@ func (m *Metrics) register(registry *prometheus.Registry) {
 		m.statusUpdateConflict,
 		m.statusUpdateNoop,
 		m.statusUpdateDurationSeconds,
+		m.tlsCertificateExpiringGauge,
+		m.listenerActiveConnectionsGauge,
+		m.xdsSnapshotResourcesGauge,
+		m.xdsSnapshotVersionInfoGauge,
 	)
 }
 
@@ -289,6 +366,7 @@ func (m *Metrics) Zero() {
 
 	m.SetDAGLastRebuilt(time.Now())
 	m.SetHTTPProxyMetric(zeroes)
+	m.SetHTTPProxyInvalidReasonMetric(map[string]int{"reason": 0})
 	m.EventHandlerOperations.WithLabelValues("add", "Secret").Inc()
 	m.SetDAGCacheObjectMetric("kind", 1)
 	m.SetStatusUpdateTotal("kind")
@@ -297,9 +375,13 @@ func (m *Metrics) Zero() {
 	m.SetStatusUpdateFailed("kind")
 	m.SetStatusUpdateConflict("kind")
 	m.SetStatusUpdateDuration(time.Nanosecond, "kind", false)
+	m.tlsCertificateExpiringGauge.WithLabelValues("bucket").Set(0)
+	m.SetListenerActiveConnections(map[string]float64{"listener": 0})
+	m.SetXDSSnapshot("cache", "version", map[string]int{"type": 0})
 
 	m.CacheHandlerOnUpdateSummary.Observe(0)
 	m.DAGRebuildSeconds.Observe(0)
+	m.SetDAGRebuildSnapshot(true)
 }
 
 // SetDAGLastRebuilt records the last time the DAG was rebuilt.
@@ -312,6 +394,15 @@ func (m *Metrics) SetDAGRebuiltTotal() {
 	m.dagRebuildTotal.Inc()
 }
 
+// SetDAGRebuildSnapshot records whether the xDS snapshot taken after a
+// DAG rebuild changed the resources sent to Envoy.
+func (m *Metrics) SetDAGRebuildSnapshot(changed bool) {
+	if m == nil {
+		return
+	}
+	m.dagRebuildSnapshotTotal.WithLabelValues(strconv.FormatBool(changed)).Inc()
+}
+
 // SetDAGCacheObjectMetric records the total number of items that are currently in the DAG cache.
 func (m *Metrics) SetDAGCacheObjectMetric(kind string, count int) {
 	if m == nil {
@@ -320,6 +411,42 @@ func (m *Metrics) SetDAGCacheObjectMetric(kind string, count int) {
 	m.dagCacheObjectGauge.WithLabelValues(kind).Set(float64(count))
 }
 
+// SetTLSCertificateExpiringMetric records the number of TLS certificates
+// expiring within each bucket in TLSCertificateExpiryBuckets.
+func (m *Metrics) SetTLSCertificateExpiringMetric(counts map[string]int) {
+	if m == nil {
+		return
+	}
+	for bucket := range TLSCertificateExpiryBuckets {
+		m.tlsCertificateExpiringGauge.WithLabelValues(bucket).Set(float64(counts[bucket]))
+	}
+}
+
+// SetListenerActiveConnections records the number of active downstream
+// connections of each Envoy listener. Listeners not present are removed.
+func (m *Metrics) SetListenerActiveConnections(connections map[string]float64) {
+	if m == nil {
+		return
+	}
+	m.listenerActiveConnectionsGauge.Reset()
+	for listener, count := range connections {
+		m.listenerActiveConnectionsGauge.WithLabelValues(listener).Set(count)
+	}
+}
+
+// SetXDSSnapshot records the version and the number of resources of
+// each type of the snapshot just stored in the named snapshot cache.
+func (m *Metrics) SetXDSSnapshot(cache, version string, resources map[string]int) {
+	if m == nil {
+		return
+	}
+	m.xdsSnapshotVersionInfoGauge.DeletePartialMatch(prometheus.Labels{"cache": cache})
+	m.xdsSnapshotVersionInfoGauge.WithLabelValues(cache, version).Set(1)
+	for resourceType, count := range resources {
+		m.xdsSnapshotResourcesGauge.WithLabelValues(resourceType).Set(float64(count))
+	}
+}
+
 // SetHTTPProxyMetric sets metric values for a set of HTTPProxies
 func (m *Metrics) SetHTTPProxyMetric(metrics RouteMetric) {
 	// Process metrics
@@ -370,6 +497,19 @@ func (m *Metrics) SetHTTPProxyMetric(metrics RouteMetric) {
 	}
 }
 
+// SetHTTPProxyInvalidReasonMetric sets the number of invalid HTTPProxies
+// for each error reason. Reasons not present are removed so that series
+// from earlier DAG rebuilds do not linger.
+func (m *Metrics) SetHTTPProxyInvalidReasonMetric(reasons map[string]int) {
+	if m == nil {
+		return
+	}
+	m.proxyInvalidReasonGauge.Reset()
+	for reason, count := range reasons {
+		m.proxyInvalidReasonGauge.WithLabelValues(reason).Set(float64(count))
+	}
+}
+
 func (m *Metrics) SetStatusUpdateTotal(kind string) {
 	m.statusUpdateTotal.With(prometheus.Labels{"kind": kind}).Inc()
 }
@@ -748,3 +748,28 @@ func TestSetDAGCacheObjectMetric(t *testing.T) {
 		})
 	}
 }
+
+func TestSetDAGRebuildSnapshot(t *testing.T) {
+	r := prometheus.NewRegistry()
+	m := NewMetrics(r)
+	m.SetDAGRebuildSnapshot(true)
+	m.SetDAGRebuildSnapshot(false)
+	m.SetDAGRebuildSnapshot(true)
+
+	gathering, err := r.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshots := map[string]float64{}
+	for _, mf := range gathering {
+		if mf.GetName() != DAGRebuildSnapshotTotal {
+			continue
+		}
+		for _, metric := range mf.Metric {
+			snapshots[metric.Label[0].GetValue()] = metric.GetCounter().GetValue()
+		}
+	}
+
+	assert.Equal(t, map[string]float64{"true": 2, "false": 1}, snapshots)
+}
@@ -14,6 +14,8 @@
 package model
 
 import (
+	"strings"
+
 	apps_v1 "k8s.io/api/apps/v1"
 	core_v1 "k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -135,6 +137,27 @@ func (c *Contour) EnvoyTolerationsExist() bool {
 	return false
 }
 
+// HTTP3Ports returns the UDP ports Envoy serves HTTP/3 on, one for each
+// HTTPS port, or nil if HTTP/3 is not enabled in the runtime settings.
+func (c *Contour) HTTP3Ports() []Port {
+	rs := c.Spec.RuntimeSettings
+	if rs == nil || rs.Envoy == nil || rs.Envoy.Listener == nil ||
+		rs.Envoy.Listener.HTTP3 == nil || !ptr.Deref(rs.Envoy.Listener.HTTP3.Enabled, false) {
+		return nil
+	}
+
+	var ports []Port
+	for _, port := range c.Spec.NetworkPublishing.Envoy.Ports {
+		if !strings.HasPrefix(port.Name, "https") {
+			continue
+		}
+		port.Name = "http3" + strings.TrimPrefix(port.Name, "https")
+		ports = append(ports, port)
+	}
+
+	return ports
+}
+
 func (c *Contour) WatchAllNamespaces() bool {
 	return len(c.Spec.WatchNamespaces) == 0
 }
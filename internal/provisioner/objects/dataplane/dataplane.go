@@ -163,6 +163,13 @@ func desiredContainers(contour *model.Contour, contourImage, envoyImage string)
 		ContainerPort: metricsPort,
 		Protocol:      core_v1.ProtocolTCP,
 	}}
+	for _, port := range contour.HTTP3Ports() {
+		ports = append(ports, core_v1.ContainerPort{
+			Name:          port.Name,
+			ContainerPort: port.ContainerPort,
+			Protocol:      core_v1.ProtocolUDP,
+		})
+	}
 
 	containers := []core_v1.Container{
 		{
@@ -22,6 +22,7 @@ import (
 	core_v1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
 
 	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 	"github.com/projectcontour/contour/internal/provisioner/model"
@@ -439,3 +440,29 @@ func TestEnvoyCustomPorts(t *testing.T) {
 	container := checkDaemonSetHasContainer(t, ds, EnvoyContainerName, true)
 	checkContainerHasReadinessPort(t, container, 8020)
 }
+
+func TestEnvoyHTTP3Ports(t *testing.T) {
+	name := "envoy-http3-ports"
+	cntr := model.Default(fmt.Sprintf("%s-ns", name), name)
+	cntr.Spec.NetworkPublishing.Envoy.Ports = []model.Port{
+		{Name: "http-80", ServicePort: 80, ContainerPort: 8080},
+		{Name: "https-443", ServicePort: 443, ContainerPort: 8443},
+	}
+	cntr.Spec.RuntimeSettings = &contour_v1alpha1.ContourConfigurationSpec{
+		Envoy: &contour_v1alpha1.EnvoyConfig{
+			Listener: &contour_v1alpha1.EnvoyListenerConfig{
+				HTTP3: &contour_v1alpha1.HTTP3Config{
+					Enabled: ptr.To(true),
+				},
+			},
+		},
+	}
+
+	ds := DesiredDaemonSet(cntr, "ghcr.io/projectcontour/contour:test", "docker.io/envoyproxy/envoy:test")
+	container := checkDaemonSetHasContainer(t, ds, EnvoyContainerName, true)
+	require.Contains(t, container.Ports, core_v1.ContainerPort{
+		Name:          "http3-443",
+		ContainerPort: 8443,
+		Protocol:      core_v1.ProtocolUDP,
+	})
+}
@@ -186,10 +186,10 @@ func DesiredDeployment(contour *model.Contour, image string) *apps_v1.Deployment
 		},
 		ReadinessProbe: &core_v1.Probe{
 			ProbeHandler: core_v1.ProbeHandler{
-				TCPSocket: &core_v1.TCPSocketAction{
-					Port: intstr.IntOrString{
-						IntVal: xdsPort,
-					},
+				HTTPGet: &core_v1.HTTPGetAction{
+					Scheme: core_v1.URISchemeHTTP,
+					Path:   "/readyz",
+					Port:   intstr.IntOrString{IntVal: int32(metricsPort)},
 				},
 			},
 			TimeoutSeconds:   int32(1),
@@ -38,6 +38,7 @@ var (
 
 var (
 	createGetUpdate = []string{"create", "get", "update"}
+	createPatch     = []string{"create", "patch"}
 	getListWatch    = []string{"get", "list", "watch"}
 	update          = []string{"update"}
 )
@@ -59,6 +60,9 @@ func NamespacedResourcePolicyRules(resourcesToSkip []contour_v1.Feature) []rbac_
 		// Core Contour-watched resources.
 		PolicyRuleFor(core_v1.GroupName, getListWatch, "secrets", "endpoints", "services", "configmaps"),
 
+		// Events recorded on invalid HTTPProxies.
+		PolicyRuleFor(core_v1.GroupName, createPatch, "events"),
+
 		// Discovery Contour-watched resources.
 		PolicyRuleFor(discovery_v1.GroupName, getListWatch, "endpointslices"),
 
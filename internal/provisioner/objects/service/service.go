@@ -16,6 +16,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	core_v1 "k8s.io/api/core/v1"
@@ -191,6 +192,16 @@ func DesiredEnvoyService(contour *model.Contour) *core_v1.Service {
 		})
 	}
 
+	// HTTP/3 is served over UDP on the same ports as HTTPS.
+	for _, port := range contour.HTTP3Ports() {
+		ports = append(ports, core_v1.ServicePort{
+			Name:       port.Name,
+			Protocol:   core_v1.ProtocolUDP,
+			Port:       port.ServicePort,
+			TargetPort: intstr.IntOrString{IntVal: port.ContainerPort},
+		})
+	}
+
 	svc := &core_v1.Service{
 		ObjectMeta: meta_v1.ObjectMeta{
 			Namespace:   contour.Namespace,
@@ -273,7 +284,7 @@ func DesiredEnvoyService(contour *model.Contour) *core_v1.Service {
 	case model.NodePortServicePublishingType:
 		svc.Spec.Type = core_v1.ServiceTypeNodePort
 
-		for _, p := range contour.Spec.NetworkPublishing.Envoy.Ports {
+		for _, p := range slices.Concat(contour.Spec.NetworkPublishing.Envoy.Ports, contour.HTTP3Ports()) {
 			if p.NodePort == 0 {
 				continue
 			}
@@ -18,9 +18,12 @@ import (
 	"sort"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	core_v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 
+	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 	"github.com/projectcontour/contour/internal/provisioner/model"
 	"github.com/projectcontour/contour/internal/provisioner/objects"
 )
@@ -284,3 +287,60 @@ func TestDesiredEnvoyService(t *testing.T) {
 	checkServiceHasType(t, svc, core_v1.ServiceTypeClusterIP)
 	checkServiceHasAnnotations(t, svc) // passing no keys means we expect no annotations
 }
+
+func TestDesiredEnvoyServiceHTTP3(t *testing.T) {
+	name := "svc-http3-test"
+	cntr := model.Default(fmt.Sprintf("%s-ns", name), name)
+	cntr.Spec.NetworkPublishing.Envoy.Type = model.NodePortServicePublishingType
+	cntr.Spec.NetworkPublishing.Envoy.Ports = []model.Port{
+		{
+			Name:          "http-80",
+			ServicePort:   80,
+			ContainerPort: 8080,
+		},
+		{
+			Name:          "https-443",
+			ServicePort:   443,
+			ContainerPort: 8443,
+			NodePort:      30444,
+		},
+	}
+
+	// Without HTTP/3 enabled, only TCP ports are exposed.
+	svc := DesiredEnvoyService(cntr)
+	assert.Len(t, svc.Spec.Ports, 2)
+
+	cntr.Spec.RuntimeSettings = &contour_v1alpha1.ContourConfigurationSpec{
+		Envoy: &contour_v1alpha1.EnvoyConfig{
+			Listener: &contour_v1alpha1.EnvoyListenerConfig{
+				HTTP3: &contour_v1alpha1.HTTP3Config{
+					Enabled: ptr.To(true),
+				},
+			},
+		},
+	}
+
+	svc = DesiredEnvoyService(cntr)
+	assert.Equal(t, []core_v1.ServicePort{
+		{
+			Name:       "http-80",
+			Protocol:   core_v1.ProtocolTCP,
+			Port:       80,
+			TargetPort: intstr.IntOrString{IntVal: 8080},
+		},
+		{
+			Name:       "https-443",
+			Protocol:   core_v1.ProtocolTCP,
+			Port:       443,
+			TargetPort: intstr.IntOrString{IntVal: 8443},
+			NodePort:   30444,
+		},
+		{
+			Name:       "http3-443",
+			Protocol:   core_v1.ProtocolUDP,
+			Port:       443,
+			TargetPort: intstr.IntOrString{IntVal: 8443},
+			NodePort:   30444,
+		},
+	}, svc.Spec.Ports)
+}
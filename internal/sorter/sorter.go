@@ -209,12 +209,19 @@ func (s queryParamMatchConditionSorter) Less(i, j int) bool {
 	}
 }
 
-// compareRoutesByMethodHeaderQueryParams compares any HTTP method match
-// (:method header which is then excluded from the rest of the header match
+// compareRoutesByMethodHeaderQueryParams compares the MatchPriority, any HTTP
+// method match (:method header which is then excluded from the rest of the header match
 // comparisons), HeaderMatchConditions, and QueryParamMatchConditions slices
 // for lhs and rhs and returns true if the conditions for the lhs Route mean
 // it should sort first.
 func compareRoutesByMethodHeaderQueryParams(lhs, rhs *dag.Route) bool {
+	// An explicit match priority takes precedence over the specificity of
+	// the remaining conditions.
+	// Note: lower values mean a higher priority.
+	if lhs.MatchPriority != rhs.MatchPriority {
+		return lhs.MatchPriority < rhs.MatchPriority
+	}
+
 	// Find if method matches exist. Should only ever be one.
 	// If found, exclude from HeaderMatchConditions slices we will
 	// compare.
@@ -266,6 +266,43 @@ func TestSortRoutesPriority(t *testing.T) {
 	shuffleAndCheckSort(t, want)
 }
 
+// Routes with a lower MatchPriority should be sorted first when compared to
+// others that have identical path matches, regardless of their method, header
+// and query matches.
+func TestSortRoutesMatchPriority(t *testing.T) {
+	want := []*dag.Route{
+		{
+			// Longer prefixes still sort first, regardless of match priority.
+			MatchPriority:      3,
+			PathMatchCondition: matchPrefixSegment("/a"),
+		},
+		{
+			// Sorts first despite having no header or query matches.
+			MatchPriority:      0,
+			PathMatchCondition: matchPrefixSegment("/"),
+		},
+		{
+			// Sorts ahead of the next one since the match priority is
+			// higher, even though the method match, header and query
+			// matches would normally sort it after.
+			MatchPriority:      1,
+			PathMatchCondition: matchPrefixSegment("/"),
+		},
+		{
+			MatchPriority:      2,
+			PathMatchCondition: matchPrefixSegment("/"),
+			HeaderMatchConditions: []dag.HeaderMatchCondition{
+				exactHeader(":method", "GET"),
+				presentHeader("a-header-name"),
+			},
+			QueryParamMatchConditions: []dag.QueryParamMatchCondition{
+				exactQueryParam("a-query-param", "query-value"),
+			},
+		},
+	}
+	shuffleAndCheckSort(t, want)
+}
+
 func TestSortRoutesPathMatch(t *testing.T) {
 	want := []*dag.Route{
 		// Note that exact matches sort before regex matches.
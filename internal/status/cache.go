@@ -217,6 +217,7 @@ func (c *Cache) GatewayStatusAccessor(nsName types.NamespacedName, generation in
 func (c *Cache) ProxyAccessor(proxy *contour_v1.HTTPProxy) (*ProxyUpdate, func()) {
 	pu := &ProxyUpdate{
 		Fullname:       k8s.NamespacedNameOf(proxy),
+		UID:            proxy.UID,
 		Generation:     proxy.Generation,
 		TransitionTime: meta_v1.NewTime(time.Now()),
 		Conditions:     make(map[ConditionType]*contour_v1.DetailedCondition),
@@ -0,0 +1,92 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+)
+
+// ProxyEventRecorder records a Kubernetes Warning Event on each
+// HTTPProxy whose Valid condition transitions to false.
+type ProxyEventRecorder struct {
+	recorder record.EventRecorder
+
+	// invalid holds whether each HTTPProxy was invalid
+	// as of the previous call to Record.
+	invalid map[types.NamespacedName]bool
+}
+
+// NewProxyEventRecorder returns a ProxyEventRecorder that
+// records Events with the given EventRecorder.
+func NewProxyEventRecorder(recorder record.EventRecorder) *ProxyEventRecorder {
+	return &ProxyEventRecorder{
+		recorder: recorder,
+		invalid:  map[types.NamespacedName]bool{},
+	}
+}
+
+// Record records a Warning Event for each HTTPProxy in updates
+// that has become invalid since the previous call. An Event is
+// recorded for each distinct error on the Valid condition, or
+// for the condition itself if it has no errors.
+func (r *ProxyEventRecorder) Record(updates []*ProxyUpdate) {
+	if r == nil {
+		return
+	}
+
+	invalid := make(map[types.NamespacedName]bool, len(updates))
+
+	for _, u := range updates {
+		validCond, ok := u.Conditions[ValidCondition]
+		if !ok || validCond.Status != contour_v1.ConditionFalse {
+			continue
+		}
+
+		invalid[u.Fullname] = true
+		if r.invalid[u.Fullname] {
+			continue
+		}
+
+		ref := &core_v1.ObjectReference{
+			Kind:       "HTTPProxy",
+			APIVersion: contour_v1.GroupVersion.String(),
+			Namespace:  u.Fullname.Namespace,
+			Name:       u.Fullname.Name,
+			UID:        u.UID,
+		}
+
+		if len(validCond.Errors) == 0 {
+			r.recorder.Event(ref, core_v1.EventTypeWarning, validCond.Reason, validCond.Message)
+			continue
+		}
+
+		seen := map[contour_v1.SubCondition]bool{}
+		for _, e := range validCond.Errors {
+			key := contour_v1.SubCondition{Reason: e.Reason, Message: e.Message}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			r.recorder.Event(ref, core_v1.EventTypeWarning, e.Reason, e.Message)
+		}
+	}
+
+	// Proxies that are valid again, or no longer exist, will
+	// have an Event recorded if they later become invalid.
+	r.invalid = invalid
+}
@@ -0,0 +1,135 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	contour_v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/k8s"
+)
+
+func TestProxyEventRecorder(t *testing.T) {
+	valid := func(name string) *ProxyUpdate {
+		pu := &ProxyUpdate{
+			Fullname:   k8s.NamespacedNameFrom(name),
+			Conditions: make(map[ConditionType]*contour_v1.DetailedCondition),
+		}
+		pu.ConditionFor(ValidCondition)
+		return pu
+	}
+
+	invalid := func(name string) *ProxyUpdate {
+		pu := valid(name)
+		cond := pu.ConditionFor(ValidCondition)
+		cond.AddError(contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Service "default/missing" not found`)
+		cond.AddError(contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Service "default/missing" not found`)
+		return pu
+	}
+
+	drain := func(recorder *record.FakeRecorder) []string {
+		var events []string
+		for {
+			select {
+			case e := <-recorder.Events:
+				events = append(events, e)
+			default:
+				return events
+			}
+		}
+	}
+
+	fake := record.NewFakeRecorder(10)
+	r := NewProxyEventRecorder(fake)
+
+	// A newly invalid proxy has an Event recorded for its error.
+	r.Record([]*ProxyUpdate{valid("default/valid"), invalid("default/invalid")})
+	assert.Equal(t, []string{
+		`Warning ServiceUnresolvedReference Service "default/missing" not found`,
+	}, drain(fake))
+
+	// A proxy that stays invalid does not have another Event recorded.
+	r.Record([]*ProxyUpdate{valid("default/valid"), invalid("default/invalid")})
+	assert.Empty(t, drain(fake))
+
+	// A proxy that becomes valid and then invalid again does.
+	r.Record([]*ProxyUpdate{valid("default/valid"), valid("default/invalid")})
+	assert.Empty(t, drain(fake))
+	r.Record([]*ProxyUpdate{invalid("default/valid"), invalid("default/invalid")})
+	assert.Len(t, drain(fake), 2)
+
+	// As does a proxy that is deleted and recreated.
+	r.Record(nil)
+	r.Record([]*ProxyUpdate{invalid("default/invalid")})
+	assert.Len(t, drain(fake), 1)
+
+	// A Valid condition without errors records its own reason and message.
+	pu := valid("default/other")
+	cond := pu.ConditionFor(ValidCondition)
+	cond.Status = contour_v1.ConditionFalse
+	cond.Reason = "NotReconciled"
+	cond.Message = "Waiting for the DAG to be rebuilt"
+	r.Record([]*ProxyUpdate{pu})
+	assert.Equal(t, []string{"Warning NotReconciled Waiting for the DAG to be rebuilt"}, drain(fake))
+
+	var nilRecorder *ProxyEventRecorder
+	nilRecorder.Record([]*ProxyUpdate{invalid("default/invalid")})
+}
+
+// objectRecorder is a record.EventRecorder that keeps the
+// objects that Events are recorded on.
+type objectRecorder struct {
+	objects []runtime.Object
+}
+
+func (o *objectRecorder) Event(object runtime.Object, _, _, _ string) {
+	o.objects = append(o.objects, object)
+}
+
+func (o *objectRecorder) Eventf(object runtime.Object, _, _, _ string, _ ...any) {
+	o.objects = append(o.objects, object)
+}
+
+func (o *objectRecorder) AnnotatedEventf(object runtime.Object, _ map[string]string, _, _, _ string, _ ...any) {
+	o.objects = append(o.objects, object)
+}
+
+func TestProxyEventRecorderObjectReference(t *testing.T) {
+	pu := &ProxyUpdate{
+		Fullname:   k8s.NamespacedNameFrom("default/invalid"),
+		UID:        "2b3c8a0e-6f5d-4c4e-9f0a-1d2e3f4a5b6c",
+		Conditions: make(map[ConditionType]*contour_v1.DetailedCondition),
+	}
+	pu.ConditionFor(ValidCondition).AddError(contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", `Service "default/missing" not found`)
+
+	recorder := &objectRecorder{}
+	NewProxyEventRecorder(recorder).Record([]*ProxyUpdate{pu})
+
+	// The UID ties the Event to this HTTPProxy, so it is not
+	// shown on a later HTTPProxy with the same name.
+	assert.Equal(t, []runtime.Object{
+		&core_v1.ObjectReference{
+			Kind:       "HTTPProxy",
+			APIVersion: contour_v1.GroupVersion.String(),
+			Namespace:  "default",
+			Name:       "invalid",
+			UID:        "2b3c8a0e-6f5d-4c4e-9f0a-1d2e3f4a5b6c",
+		},
+	}, recorder.objects)
+}
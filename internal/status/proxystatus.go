@@ -15,6 +15,7 @@ package status
 
 import (
 	"fmt"
+	"strings"
 
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -34,6 +35,7 @@ const (
 // ProxyUpdate holds status updates for a particular HTTPProxy object
 type ProxyUpdate struct {
 	Fullname       types.NamespacedName
+	UID            types.UID
 	Generation     int64
 	TransitionTime meta_v1.Time
 	Vhost          string
@@ -42,6 +44,28 @@ type ProxyUpdate struct {
 	// keyed by the Type (since that's what the apiserver will end up
 	// doing.)
 	Conditions map[ConditionType]*contour_v1.DetailedCondition
+
+	// ValidationErrors holds the referenced objects that could
+	// not be resolved.
+	ValidationErrors []contour_v1.ValidationError
+}
+
+// AddValidationError records that the object of the given kind and
+// name referenced by the HTTPProxy could not be resolved. Each object
+// is only recorded once.
+func (pu *ProxyUpdate) AddValidationError(kind string, name types.NamespacedName, message string) {
+	for _, ve := range pu.ValidationErrors {
+		if ve.Kind == kind && ve.Namespace == name.Namespace && ve.Name == name.Name {
+			return
+		}
+	}
+
+	pu.ValidationErrors = append(pu.ValidationErrors, contour_v1.ValidationError{
+		Kind:      kind,
+		Namespace: name.Namespace,
+		Name:      name.Name,
+		Message:   message,
+	})
 }
 
 // ConditionFor returns a DetailedCondition for a given ConditionType.
@@ -65,6 +89,100 @@ func (pu *ProxyUpdate) ConditionFor(cond ConditionType) *contour_v1.DetailedCond
 	return dc
 }
 
+// InvalidProxyReasons returns the number of invalid HTTPProxies for each
+// error reason on their Valid condition. An HTTPProxy with several errors
+// is counted once for each distinct reason.
+func InvalidProxyReasons(updates []*ProxyUpdate) map[string]int {
+	reasons := map[string]int{}
+
+	for _, u := range updates {
+		validCond, ok := u.Conditions[ValidCondition]
+		if !ok || validCond.Status != contour_v1.ConditionFalse {
+			continue
+		}
+
+		seen := map[string]bool{}
+		for _, e := range validCond.Errors {
+			if seen[e.Reason] {
+				continue
+			}
+			seen[e.Reason] = true
+			reasons[e.Reason]++
+		}
+	}
+
+	return reasons
+}
+
+// RouteMatch returns a description of the route with the given match
+// conditions, suitable for identifying the route in a SubCondition.
+// A route without a path condition matches the prefix "/".
+func RouteMatch(conditions []contour_v1.MatchCondition) string {
+	var path string
+	var matches []string
+
+	for _, cond := range conditions {
+		switch {
+		case cond.Prefix != "":
+			path = "prefix: " + cond.Prefix
+		case cond.Exact != "":
+			path = "exact: " + cond.Exact
+		case cond.Regex != "":
+			path = "regex: " + cond.Regex
+		case cond.Header != nil:
+			matches = append(matches, "header: "+headerMatch(cond.Header))
+		case cond.QueryParameter != nil:
+			matches = append(matches, "queryParameter: "+queryParameterMatch(cond.QueryParameter))
+		}
+	}
+
+	if path == "" {
+		path = "prefix: /"
+	}
+
+	return strings.Join(append([]string{path}, matches...), ", ")
+}
+
+func headerMatch(h *contour_v1.HeaderMatchCondition) string {
+	switch {
+	case h.Present:
+		return h.Name + " present"
+	case h.NotPresent:
+		return h.Name + " notpresent"
+	case h.Contains != "":
+		return h.Name + " contains " + h.Contains
+	case h.NotContains != "":
+		return h.Name + " notcontains " + h.NotContains
+	case h.Exact != "":
+		return h.Name + " exact " + h.Exact
+	case h.NotExact != "":
+		return h.Name + " notexact " + h.NotExact
+	case h.Regex != "":
+		return h.Name + " regex " + h.Regex
+	default:
+		return h.Name
+	}
+}
+
+func queryParameterMatch(q *contour_v1.QueryParameterMatchCondition) string {
+	switch {
+	case q.Present:
+		return q.Name + " present"
+	case q.Exact != "":
+		return q.Name + " exact " + q.Exact
+	case q.Prefix != "":
+		return q.Name + " prefix " + q.Prefix
+	case q.Suffix != "":
+		return q.Name + " suffix " + q.Suffix
+	case q.Regex != "":
+		return q.Name + " regex " + q.Regex
+	case q.Contains != "":
+		return q.Name + " contains " + q.Contains
+	default:
+		return q.Name
+	}
+}
+
 func (pu *ProxyUpdate) Mutate(obj client.Object) client.Object {
 	o, ok := obj.(*contour_v1.HTTPProxy)
 	if !ok {
@@ -115,5 +233,7 @@ func (pu *ProxyUpdate) Mutate(obj client.Object) client.Object {
 		proxy.Status.Description = validCond.Message
 	}
 
+	proxy.Status.ValidationErrors = pu.ValidationErrors
+
 	return proxy
 }
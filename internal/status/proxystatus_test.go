@@ -59,6 +59,34 @@ func TestConditionFor(t *testing.T) {
 	assert.Equal(t, newDc, *gotEmpty)
 }
 
+func TestInvalidProxyReasons(t *testing.T) {
+	newUpdate := func() *ProxyUpdate {
+		return &ProxyUpdate{
+			Fullname:   k8s.NamespacedNameFrom("test/test"),
+			Conditions: make(map[ConditionType]*contour_v1.DetailedCondition),
+		}
+	}
+
+	valid := newUpdate()
+	valid.ConditionFor(ValidCondition)
+
+	tlsError := newUpdate()
+	tlsError.ConditionFor(ValidCondition).AddError(contour_v1.ConditionTypeTLSError, "SecretNotValid", "invalid secret")
+
+	// Errors sharing a reason are counted once.
+	serviceErrors := newUpdate()
+	serviceErrors.ConditionFor(ValidCondition).AddError(contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", "missing service")
+	serviceErrors.ConditionFor(ValidCondition).AddError(contour_v1.ConditionTypeRouteError, "ServiceUnresolvedReference", "missing service")
+	serviceErrors.ConditionFor(ValidCondition).AddError(contour_v1.ConditionTypeTLSError, "SecretNotValid", "invalid secret")
+
+	assert.Equal(t, map[string]int{
+		"SecretNotValid":             2,
+		"ServiceUnresolvedReference": 1,
+	}, InvalidProxyReasons([]*ProxyUpdate{valid, tlsError, serviceErrors}))
+
+	assert.Empty(t, InvalidProxyReasons([]*ProxyUpdate{valid}))
+}
+
 func TestStatusMutator(t *testing.T) {
 	type testcase struct {
 		testProxy         contour_v1.HTTPProxy
@@ -312,3 +340,77 @@ func TestStatusMutator(t *testing.T) {
 
 	run("Test updating existing Valid Condition", updateExistingValidCond)
 }
+
+func TestStatusMutatorValidationErrors(t *testing.T) {
+	pu := ProxyUpdate{
+		Fullname:   k8s.NamespacedNameFrom("test/test"),
+		Conditions: make(map[ConditionType]*contour_v1.DetailedCondition),
+	}
+	pu.ConditionFor(ValidCondition).AddError(contour_v1.ConditionTypeServiceError, "ServiceUnresolvedReference", "service not found")
+	pu.AddValidationError("Service", k8s.NamespacedNameFrom("test/missing"), `service "test/missing" not found`)
+	pu.AddValidationError("Service", k8s.NamespacedNameFrom("test/missing"), `service "test/missing" not found`)
+	pu.AddValidationError("Secret", k8s.NamespacedNameFrom("certs/missing"), "Secret not found")
+
+	want := []contour_v1.ValidationError{{
+		Kind:      "Service",
+		Namespace: "test",
+		Name:      "missing",
+		Message:   `service "test/missing" not found`,
+	}, {
+		Kind:      "Secret",
+		Namespace: "certs",
+		Name:      "missing",
+		Message:   "Secret not found",
+	}}
+
+	proxy := &contour_v1.HTTPProxy{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "test", Namespace: "test"},
+		Status: contour_v1.HTTPProxyStatus{
+			ValidationErrors: []contour_v1.ValidationError{{Kind: "Service", Namespace: "test", Name: "stale"}},
+		},
+	}
+
+	got := pu.Mutate(proxy).(*contour_v1.HTTPProxy)
+	assert.Equal(t, want, got.Status.ValidationErrors)
+
+	// Resolving the references clears the previous entries.
+	pu.ValidationErrors = nil
+	got = pu.Mutate(got).(*contour_v1.HTTPProxy)
+	assert.Empty(t, got.Status.ValidationErrors)
+}
+
+func TestRouteMatch(t *testing.T) {
+	tests := map[string]struct {
+		conditions []contour_v1.MatchCondition
+		want       string
+	}{
+		"no conditions": {
+			want: "prefix: /",
+		},
+		"prefix": {
+			conditions: []contour_v1.MatchCondition{{Prefix: "/api"}},
+			want:       "prefix: /api",
+		},
+		"exact with headers and query parameters": {
+			conditions: []contour_v1.MatchCondition{
+				{Exact: "/login"},
+				{Header: &contour_v1.HeaderMatchCondition{Name: "x-env", Exact: "staging"}},
+				{Header: &contour_v1.HeaderMatchCondition{Name: "x-debug", Present: true}},
+				{QueryParameter: &contour_v1.QueryParameterMatchCondition{Name: "user", Prefix: "admin"}},
+			},
+			want: "exact: /login, header: x-env exact staging, header: x-debug present, queryParameter: user prefix admin",
+		},
+		"header only": {
+			conditions: []contour_v1.MatchCondition{
+				{Header: &contour_v1.HeaderMatchCondition{Name: "x-env", NotContains: "prod"}},
+			},
+			want: "prefix: /, header: x-env notcontains prod",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, RouteMatch(tc.conditions))
+		})
+	}
+}
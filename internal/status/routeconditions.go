@@ -39,9 +39,12 @@ const (
 	ReasonInvalidGateway                  gatewayapi_v1.RouteConditionReason = "InvalidGateway"
 	ReasonRouteRuleMatchConflict          gatewayapi_v1.RouteConditionReason = "RuleMatchConflict"
 	ReasonRouteRuleMatchPartiallyConflict gatewayapi_v1.RouteConditionReason = "RuleMatchPartiallyConflict"
+	ReasonHostnameConflict                gatewayapi_v1.RouteConditionReason = "HostnameConflict"
 
 	MessageRouteRuleMatchConflict          string = "%s's Match has conflict with other %s's Match"
 	MessageRouteRuleMatchPartiallyConflict string = "Dropped Rule: some of %s's rule(s) has(ve) been dropped because of conflict against other %s's rule(s)"
+	MessageHostnameConflict                string = "hostname(s) %s already terminate TLS on this listener and cannot also be passed through"
+	MessagePassthroughHostnameConflict     string = "hostname(s) %s are already passed through on this listener and cannot also terminate TLS"
 )
 
 // RouteStatusUpdate represents an atomic update to a
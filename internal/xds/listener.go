@@ -0,0 +1,81 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
+)
+
+// UnixSocketMode is the file mode of the Unix domain sockets created by
+// NewListener. Envoy must run as the same user or in the same group as
+// Contour to connect.
+const UnixSocketMode fs.FileMode = 0o660
+
+// NewListener returns a listener for the xDS server. If address has the
+// "unix://" prefix, the listener is bound to the Unix domain socket at
+// that path and port is ignored. Otherwise it is bound to the TCP
+// address and port.
+func NewListener(address string, port int) (net.Listener, error) {
+	path, ok := strings.CutPrefix(address, contour_v1alpha1.XDSUnixSocketPrefix)
+	if !ok {
+		return net.Listen("tcp", net.JoinHostPort(address, strconv.Itoa(port)))
+	}
+
+	if err := contour_v1alpha1.ValidateXDSAddress(address); err != nil {
+		return nil, err
+	}
+
+	dir, err := os.Stat(filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("invalid unix socket path %q: %w", path, err)
+	}
+	if !dir.IsDir() {
+		return nil, fmt.Errorf("invalid unix socket path %q: %s is not a directory", path, filepath.Dir(path))
+	}
+
+	// A socket left behind by a previous run would make the bind
+	// fail, so remove it. Refuse to remove anything else.
+	switch fi, err := os.Lstat(path); {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("invalid unix socket path %q: %w", path, err)
+	case fi.Mode().Type() != fs.ModeSocket:
+		return nil, fmt.Errorf("invalid unix socket path %q: file exists and is not a socket", path)
+	default:
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale unix socket %q: %w", path, err)
+		}
+	}
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Chmod(path, UnixSocketMode); err != nil {
+		l.Close()
+		return nil, fmt.Errorf("failed to set permissions of unix socket %q: %w", path, err)
+	}
+
+	return l, nil
+}
@@ -0,0 +1,95 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewListenerTCP(t *testing.T) {
+	l, err := NewListener("127.0.0.1", 0)
+	require.NoError(t, err)
+	defer l.Close()
+
+	assert.Equal(t, "tcp", l.Addr().Network())
+}
+
+func TestNewListenerUnixSocket(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "xds.sock")
+
+	l, err := NewListener("unix://"+path, 8001)
+	require.NoError(t, err)
+
+	assert.Equal(t, "unix", l.Addr().Network())
+	assert.Equal(t, path, l.Addr().String())
+
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, fs.ModeSocket, fi.Mode().Type())
+	assert.Equal(t, UnixSocketMode, fi.Mode().Perm())
+
+	go func() {
+		if c, err := l.Accept(); err == nil {
+			c.Close()
+		}
+	}()
+	c, err := net.Dial("unix", path)
+	require.NoError(t, err)
+	c.Close()
+
+	// A stale socket from a previous listener is replaced. Keep the
+	// socket file around when closing to simulate an unclean exit.
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, l.Close())
+
+	l, err = NewListener("unix://"+path, 8001)
+	require.NoError(t, err)
+	require.NoError(t, l.Close())
+}
+
+func TestNewListenerUnixSocketInvalid(t *testing.T) {
+	dir := t.TempDir()
+
+	regular := filepath.Join(dir, "regular")
+	require.NoError(t, os.WriteFile(regular, nil, 0o600))
+
+	tests := map[string]string{
+		"relative path":        "unix://xds.sock",
+		"missing directory":    "unix://" + filepath.Join(dir, "missing", "xds.sock"),
+		"parent is a file":     "unix://" + filepath.Join(regular, "xds.sock"),
+		"path is regular file": "unix://" + regular,
+	}
+
+	for name, address := range tests {
+		t.Run(name, func(t *testing.T) {
+			l, err := NewListener(address, 8001)
+			if l != nil {
+				l.Close()
+			}
+			require.Error(t, err)
+		})
+	}
+
+	// The regular file is never removed.
+	_, err := os.Stat(regular)
+	require.NoError(t, err)
+}
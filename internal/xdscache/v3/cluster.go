@@ -31,6 +31,10 @@ import (
 
 // ClusterCache manages the contents of the gRPC CDS cache.
 type ClusterCache struct {
+	// DeltaXDS makes EDS clusters fetch their endpoints with
+	// the incremental (delta) xDS protocol.
+	DeltaXDS bool
+
 	mu     sync.Mutex
 	values map[string]*envoy_config_cluster_v3.Cluster
 	contour.Cond
@@ -93,6 +97,13 @@ func (c *ClusterCache) OnChange(root *dag.DAG) {
 		}
 	}
 
+	for _, proxy := range root.GetFailoverTCPProxies() {
+		name := envoy.FailoverClustername(proxy)
+		if _, ok := clusters[name]; !ok {
+			clusters[name] = envoy_v3.FailoverCluster(proxy)
+		}
+	}
+
 	for _, cluster := range root.GetDNSNameClusters() {
 		name := envoy.DNSNameClusterName(cluster)
 		if _, ok := clusters[name]; !ok {
@@ -100,5 +111,13 @@ func (c *ClusterCache) OnChange(root *dag.DAG) {
 		}
 	}
 
+	if c.DeltaXDS {
+		for _, cluster := range clusters {
+			if eds := cluster.GetEdsClusterConfig(); eds != nil {
+				eds.EdsConfig = envoy_v3.DeltaConfigSource("contour")
+			}
+		}
+	}
+
 	c.Update(clusters)
 }
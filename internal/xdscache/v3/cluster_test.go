@@ -833,6 +833,40 @@ func TestClusterVisit(t *testing.T) {
 	}
 }
 
+func TestClusterVisitDeltaXDS(t *testing.T) {
+	objs := []any{
+		&networking_v1.Ingress{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "kuard",
+				Namespace: "default",
+			},
+			Spec: networking_v1.IngressSpec{
+				DefaultBackend: backend("kuard", 443),
+			},
+		},
+		service("default", "kuard",
+			core_v1.ServicePort{
+				Protocol:   "TCP",
+				Port:       443,
+				TargetPort: intstr.FromInt(8443),
+			},
+		),
+	}
+
+	cc := ClusterCache{DeltaXDS: true}
+	cc.OnChange(buildDAG(t, objs...))
+	protobuf.ExpectEqual(t, clustermap(
+		&envoy_config_cluster_v3.Cluster{
+			Name:                 "default/kuard/443/da39a3ee5e",
+			AltStatName:          "default_kuard_443",
+			ClusterDiscoveryType: envoy_v3.ClusterDiscoveryType(envoy_config_cluster_v3.Cluster_EDS),
+			EdsClusterConfig: &envoy_config_cluster_v3.Cluster_EdsClusterConfig{
+				EdsConfig:   envoy_v3.DeltaConfigSource("contour"),
+				ServiceName: "default/kuard",
+			},
+		}), cc.values)
+}
+
 func service(ns, name string, ports ...core_v1.ServicePort) *core_v1.Service {
 	return serviceWithAnnotations(ns, name, nil, ports...)
 }
@@ -15,13 +15,17 @@ package v3
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"sort"
+	"strconv"
 	"sync"
 
 	envoy_config_endpoint_v3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
 	resource "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 	core_v1 "k8s.io/api/core/v1"
 	discovery_v1 "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -35,6 +39,28 @@ import (
 	"github.com/projectcontour/contour/internal/sorter"
 )
 
+// CanaryWeightLabel is the EndpointSlice label used to set the load
+// balancing weight of every endpoint in the slice. Endpoints in slices
+// without the label have Envoy's default weight of 1.
+const CanaryWeightLabel = "projectcontour.io/canary-weight"
+
+// canaryWeight returns the load balancing weight set by the
+// CanaryWeightLabel on the EndpointSlice, or nil if the label is
+// missing or is not a positive integer.
+func canaryWeight(endpointSlice *discovery_v1.EndpointSlice) *wrapperspb.UInt32Value {
+	val, ok := endpointSlice.Labels[CanaryWeightLabel]
+	if !ok {
+		return nil
+	}
+
+	weight, err := strconv.ParseUint(val, 10, 32)
+	if err != nil || weight == 0 {
+		return nil
+	}
+
+	return wrapperspb.UInt32(uint32(weight))
+}
+
 // RecalculateEndpoints generates a slice of LoadBalancingEndpoint
 // resources by matching the given service port to the given discovery_v1.EndpointSlice.
 // endpointSliceMap may be nil, in which case, the result is also nil.
@@ -43,11 +69,17 @@ func (c *EndpointSliceCache) RecalculateEndpoints(port, healthPort core_v1.Servi
 	uniqueEndpoints := make(map[string]struct{}, 0)
 	var healthCheckPort int32
 
-	for _, endpointSlice := range endpointSliceMap {
+	// Visit the EndpointSlices in a stable order so that endpoints
+	// duplicated across slices consistently take their weight from
+	// the same slice.
+	for _, name := range slices.Sorted(maps.Keys(endpointSliceMap)) {
+		endpointSlice := endpointSliceMap[name]
 		sort.Slice(endpointSlice.Endpoints, func(i, j int) bool {
 			return endpointSlice.Endpoints[i].Addresses[0] < endpointSlice.Endpoints[j].Addresses[0]
 		})
 
+		weight := canaryWeight(endpointSlice)
+
 		for _, endpoint := range endpointSlice.Endpoints {
 			// Skip if the endpointSlice is not marked as ready.
 			if endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready {
@@ -98,7 +130,9 @@ func (c *EndpointSliceCache) RecalculateEndpoints(port, healthPort core_v1.Servi
 				// Hence, we need to ensure that the endpoints we add to []*LoadBalancingEndpoint aren't duplicated.
 				endpointKey := fmt.Sprintf("%s:%d", endpoint.Addresses[0], *endpointPort.Port)
 				if _, exists := uniqueEndpoints[endpointKey]; !exists {
-					lb = append(lb, envoy_v3.LBEndpoint(addr))
+					lbEndpoint := envoy_v3.LBEndpoint(addr)
+					lbEndpoint.LoadBalancingWeight = weight
+					lb = append(lb, lbEndpoint)
 					uniqueEndpoints[endpointKey] = struct{}{}
 				}
 			}
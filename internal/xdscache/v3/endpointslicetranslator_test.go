@@ -19,6 +19,7 @@ import (
 	envoy_config_endpoint_v3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 	core_v1 "k8s.io/api/core/v1"
 	discovery_v1 "k8s.io/api/discovery/v1"
 	"k8s.io/utils/ptr"
@@ -1198,3 +1199,66 @@ func TestEndpointSliceTranslatorDefaultWeightedService(t *testing.T) {
 
 	protobuf.ExpectEqual(t, want, endpointSliceTranslator.Contents())
 }
+
+func TestEndpointSliceTranslatorCanaryWeight(t *testing.T) {
+	endpointSliceTranslator := NewEndpointSliceTranslator(fixture.NewTestLogger(t))
+	clusters := []*dag.ServiceCluster{
+		{
+			ClusterName: "default/simple",
+			Services: []dag.WeightedService{{
+				Weight:           1,
+				ServiceName:      "simple",
+				ServiceNamespace: "default",
+				ServicePort:      core_v1.ServicePort{},
+			}},
+		},
+	}
+
+	require.NoError(t, endpointSliceTranslator.cache.SetClusters(clusters))
+
+	ports := []discovery_v1.EndpointPort{
+		{
+			Port:     ptr.To[int32](8080),
+			Protocol: ptr.To[core_v1.Protocol]("TCP"),
+		},
+	}
+
+	stable := endpointSlice("default", "simple-eps-stable", "simple", discovery_v1.AddressTypeIPv4, []discovery_v1.Endpoint{
+		{Addresses: []string{"10.0.0.1"}},
+		{Addresses: []string{"10.0.0.2"}},
+	}, ports)
+
+	canary := endpointSlice("default", "simple-eps-canary", "simple", discovery_v1.AddressTypeIPv4, []discovery_v1.Endpoint{
+		{Addresses: []string{"10.0.0.3"}},
+	}, ports)
+	canary.Labels[CanaryWeightLabel] = "10"
+
+	invalid := endpointSlice("default", "simple-eps-invalid", "simple", discovery_v1.AddressTypeIPv4, []discovery_v1.Endpoint{
+		{Addresses: []string{"10.0.0.4"}},
+	}, ports)
+	invalid.Labels[CanaryWeightLabel] = "-1"
+
+	endpointSliceTranslator.OnAdd(stable, false)
+	endpointSliceTranslator.OnAdd(canary, false)
+	endpointSliceTranslator.OnAdd(invalid, false)
+
+	canaryEndpoint := envoy_v3.LBEndpoint(envoy_v3.SocketAddress("10.0.0.3", 8080))
+	canaryEndpoint.LoadBalancingWeight = wrapperspb.UInt32(10)
+
+	want := []proto.Message{
+		&envoy_config_endpoint_v3.ClusterLoadAssignment{
+			ClusterName: "default/simple",
+			Endpoints: []*envoy_config_endpoint_v3.LocalityLbEndpoints{{
+				LoadBalancingWeight: wrapperspb.UInt32(1),
+				LbEndpoints: []*envoy_config_endpoint_v3.LbEndpoint{
+					canaryEndpoint,
+					envoy_v3.LBEndpoint(envoy_v3.SocketAddress("10.0.0.4", 8080)),
+					envoy_v3.LBEndpoint(envoy_v3.SocketAddress("10.0.0.1", 8080)),
+					envoy_v3.LBEndpoint(envoy_v3.SocketAddress("10.0.0.2", 8080)),
+				},
+			}},
+		},
+	}
+
+	protobuf.ExpectEqual(t, want, endpointSliceTranslator.Contents())
+}
@@ -14,16 +14,21 @@
 package v3
 
 import (
+	"net"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 
 	envoy_config_accesslog_v3 "github.com/envoyproxy/go-control-plane/envoy/config/accesslog/v3"
+	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_config_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	envoy_filter_network_http_connection_manager_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	envoy_transport_socket_tls_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	resource "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
 	"google.golang.org/protobuf/proto"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 
 	contour_v1alpha1 "github.com/projectcontour/contour/apis/projectcontour/v1alpha1"
 	"github.com/projectcontour/contour/internal/contour"
@@ -66,6 +71,15 @@ type ListenerConfig struct {
 	// If not set, defaults to false.
 	UseProxyProto bool
 
+	// AllowRequestsWithoutProxyProto accepts connections that do not
+	// start with a PROXY preamble when UseProxyProto is set.
+	AllowRequestsWithoutProxyProto bool
+
+	// ProxyProtoVersions restricts the PROXY preamble versions
+	// accepted when UseProxyProto is set. If empty, both V1 and
+	// V2 are accepted.
+	ProxyProtoVersions []string
+
 	// MinimumTLSVersion defines the minimum TLS protocol version the proxy should accept.
 	MinimumTLSVersion string
 
@@ -76,6 +90,10 @@ type ListenerConfig struct {
 	// negotiating TLS 1.2.
 	CipherSuites []string
 
+	// DisableSessionTickets stops Envoy TLS listeners issuing TLS
+	// session tickets. Individual vhosts can also disable them.
+	DisableSessionTickets bool
+
 	// DefaultHTTPVersions defines the default set of HTTP
 	// versions the proxy should accept. If not specified, all
 	// supported versions are accepted. This is applied to both
@@ -110,12 +128,51 @@ type ListenerConfig struct {
 	// listeners.
 	AllowChunkedLength bool
 
+	// AllowAbsoluteURL sets allow_absolute_url on the HTTP1 options for
+	// all listeners. If nil, Envoy's default is used.
+	AllowAbsoluteURL *bool
+
+	// DisableAcceptHTTP10 unsets accept_http_10 on the HTTP1 options
+	// for all listeners, so that HTTP/1.0 requests are rejected.
+	DisableAcceptHTTP10 bool
+
+	// HTTP10DefaultHost sets default_host_for_http_10 on the HTTP1
+	// options for all listeners.
+	HTTP10DefaultHost string
+
+	// DeltaXDS makes all listeners fetch their route configuration
+	// with the incremental (delta) xDS protocol.
+	DeltaXDS bool
+
 	// MergeSlashes toggles Envoy's non-standard merge_slashes path transformation option for all listeners.
 	MergeSlashes bool
 
+	// StripAnyHostPort removes any port from the Host/:authority header before
+	// virtual host matching on all listeners.
+	StripAnyHostPort bool
+
+	// StripMatchingHostPort removes the port from the Host/:authority header before
+	// virtual host matching on all listeners, when it matches the listener port.
+	StripMatchingHostPort bool
+
 	// ServerHeaderTransformation defines the action to be applied to the Server header on the response path.
 	ServerHeaderTransformation contour_v1alpha1.ServerHeaderTransformationType
 
+	// ServerName is the value of the Server response header. If empty,
+	// Envoy's default is used.
+	ServerName string
+
+	// UseRemoteAddress sets whether Envoy uses the downstream connection's
+	// address as the client address. If nil, it defaults to true.
+	UseRemoteAddress *bool
+
+	// Via is the value appended to the via header. If empty,
+	// no via header is added.
+	Via string
+
+	// LocalReplyPolicy customizes the bodies of responses generated by Envoy.
+	LocalReplyPolicy *contour_v1alpha1.LocalReplyPolicy
+
 	// XffNumTrustedHops sets the number of additional ingress proxy hops from the
 	// right side of the x-forwarded-for HTTP header to trust.
 	XffNumTrustedHops uint32
@@ -130,8 +187,16 @@ type ListenerConfig struct {
 	// if not specified there is no limit set.
 	MaxRequestsPerConnection *uint32
 
+	// HTTP2MaxConcurrentStreams limits the number of concurrent streams
+	// on each downstream HTTP/2 connection. If not specified there is no limit set.
 	HTTP2MaxConcurrentStreams *uint32
 
+	// HTTP2KeepaliveInterval and HTTP2KeepaliveTimeout configure HTTP/2
+	// keepalive PINGs on downstream connections. If not specified,
+	// no PINGs are sent.
+	HTTP2KeepaliveInterval timeout.Setting
+	HTTP2KeepaliveTimeout  timeout.Setting
+
 	// PerConnectionBufferLimitBytes defines the soft limit on size of the listener’s new connection read and write buffers
 	// If unspecified, an implementation defined default is applied (1MiB).
 	PerConnectionBufferLimitBytes *uint32
@@ -150,6 +215,19 @@ type ListenerConfig struct {
 
 	// SocketOptions configures socket options HTTP and HTTPS listeners.
 	SocketOptions *contour_v1alpha1.SocketOptions
+
+	// ListenerFiltersTimeout is the maximum time the listener filters
+	// may take to inspect a new connection.
+	ListenerFiltersTimeout timeout.Setting
+
+	// ContinueOnListenerFiltersTimeout passes connections whose listener
+	// filters time out on to a filter chain, instead of closing them.
+	ContinueOnListenerFiltersTimeout bool
+
+	// HTTP3 adds a UDP QUIC listener alongside each listener that has
+	// TLS virtual hosts, so that those virtual hosts are also served
+	// over HTTP/3.
+	HTTP3 bool
 }
 
 type ExtensionServiceConfig struct {
@@ -199,6 +277,10 @@ type GlobalExternalAuthConfig struct {
 	FailOpen        bool
 	Context         map[string]string
 	WithRequestBody *dag.AuthorizationServerBufferSettings
+
+	RequestAllowedHeaders     []string
+	RequestDisallowedHeaders  []string
+	ResponseDisallowedHeaders []string
 }
 
 // httpAccessLog returns the access log for the HTTP (non TLS)
@@ -275,6 +357,15 @@ func (lvc *ListenerConfig) maxTLSVersion() envoy_transport_socket_tls_v3.TlsPara
 	return envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3
 }
 
+// cipherSuites returns the TLS 1.2 cipher suites requested by the
+// secure virtual host, or the configured cipher suites if it has none.
+func (lvc *ListenerConfig) cipherSuites(vh *dag.SecureVirtualHost) []string {
+	if len(vh.CipherSuites) > 0 {
+		return vh.CipherSuites
+	}
+	return lvc.CipherSuites
+}
+
 // ListenerCache manages the contents of the gRPC LDS cache.
 type ListenerCache struct {
 	mu           sync.Mutex
@@ -362,6 +453,40 @@ func (c *ListenerCache) Query(names []string) []proto.Message {
 
 func (*ListenerCache) TypeURL() string { return resource.ListenerType }
 
+// ListenerStatPrefixes returns the names of the TCP listeners in the
+// ListenerCache keyed by the prefix Envoy uses for their listener stats,
+// which is the listener's address with colons replaced by underscores.
+// The Envoy admin listener is not included.
+func (c *ListenerCache) ListenerStatPrefixes() map[string]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prefixes := map[string]string{}
+	for _, values := range []map[string]*envoy_config_listener_v3.Listener{c.staticValues, c.values} {
+		for name, v := range values {
+			if name == envoy_v3.AdminListenerName {
+				continue
+			}
+			addr := v.GetAddress().GetSocketAddress()
+			if addr == nil || addr.GetProtocol() != envoy_config_core_v3.SocketAddress_TCP {
+				continue
+			}
+			hostport := net.JoinHostPort(addr.GetAddress(), strconv.FormatUint(uint64(addr.GetPortValue()), 10))
+			prefixes[strings.ReplaceAll(hostport, ":", "_")] = name
+		}
+	}
+	return prefixes
+}
+
+// listenerVirtualHosts returns all the HTTP virtual hosts bound to the listener.
+func listenerVirtualHosts(listener *dag.Listener) []*dag.VirtualHost {
+	vhosts := append([]*dag.VirtualHost{}, listener.VirtualHosts...)
+	for _, svh := range listener.SecureVirtualHosts {
+		vhosts = append(vhosts, &svh.VirtualHost)
+	}
+	return vhosts
+}
+
 func (c *ListenerCache) OnChange(root *dag.DAG) {
 	cfg := c.Config
 	listeners := map[string]*envoy_config_listener_v3.Listener{}
@@ -387,6 +512,12 @@ func (c *ListenerCache) OnChange(root *dag.DAG) {
 
 			continue
 		}
+		// Templated direct response bodies and the local reply policy
+		// are rendered by the HTTP connection manager, so every
+		// connection manager on the listener carries the mappers for
+		// all of its vhosts.
+		localReplyConfig := envoy_v3.LocalReplyConfig(cfg.LocalReplyPolicy, listenerVirtualHosts(listener)...)
+
 		// If there are non-TLS vhosts bound to the listener,
 		// add a listener with a single filter chain.
 		// Note: Ensure the filter chain order matches with the filter chain
@@ -405,15 +536,28 @@ func (c *ListenerCache) OnChange(root *dag.DAG) {
 				MaxConnectionDuration(cfg.Timeouts.MaxConnectionDuration).
 				ConnectionShutdownGracePeriod(cfg.Timeouts.ConnectionShutdownGracePeriod).
 				AllowChunkedLength(cfg.AllowChunkedLength).
+				AllowAbsoluteURL(cfg.AllowAbsoluteURL).
+				DisableAcceptHTTP10(cfg.DisableAcceptHTTP10).
+				HTTP10DefaultHost(cfg.HTTP10DefaultHost).
+				DeltaXDS(cfg.DeltaXDS).
 				MergeSlashes(cfg.MergeSlashes).
+				StripAnyHostPort(cfg.StripAnyHostPort).
+				StripMatchingHostPort(cfg.StripMatchingHostPort).
 				ServerHeaderTransformation(cfg.ServerHeaderTransformation).
+				ServerName(cfg.ServerName).
+				UseRemoteAddress(cfg.UseRemoteAddress).
+				Via(cfg.Via).
 				NumTrustedHops(cfg.XffNumTrustedHops).
 				MaxRequestsPerConnection(cfg.MaxRequestsPerConnection).
 				HTTP2MaxConcurrentStreams(cfg.HTTP2MaxConcurrentStreams).
+				HTTP2Keepalive(cfg.HTTP2KeepaliveInterval, cfg.HTTP2KeepaliveTimeout).
 				AddFilter(httpGlobalExternalAuthConfig(cfg.GlobalExternalAuthConfig)).
 				Tracing(envoy_v3.TracingConfig(envoyTracingConfig(cfg.TracingConfig))).
 				AddFilter(envoy_v3.GlobalRateLimitFilter(envoyGlobalRateLimitConfig(cfg.RateLimitConfig))).
+				AddFilter(envoy_v3.FilterFault(listener.VirtualHosts...)).
+				AddFilter(envoy_v3.FilterStatefulSession(listener.VirtualHosts...)).
 				EnableWebsockets(listener.EnableWebsockets).
+				LocalReplyConfig(localReplyConfig).
 				Get()
 
 			listeners[listener.Name] = envoy_v3.Listener(
@@ -422,7 +566,7 @@ func (c *ListenerCache) OnChange(root *dag.DAG) {
 				listener.Port,
 				cfg.PerConnectionBufferLimitBytes,
 				socketOptions,
-				proxyProtocol(cfg.UseProxyProto),
+				cfg.proxyProtocol(),
 				cm,
 			)
 		}
@@ -437,7 +581,7 @@ func (c *ListenerCache) OnChange(root *dag.DAG) {
 				listener.Port,
 				cfg.PerConnectionBufferLimitBytes,
 				socketOptions,
-				secureProxyProtocol(cfg.UseProxyProto),
+				cfg.secureProxyProtocol(),
 			)
 		}
 
@@ -464,7 +608,7 @@ func (c *ListenerCache) OnChange(root *dag.DAG) {
 				// metrics prefix to keep compatibility with previous
 				// Contour versions since the metrics prefix will be
 				// coded into monitoring dashboards.
-				cm := envoy_v3.HTTPConnectionManagerBuilder().
+				cmb := envoy_v3.HTTPConnectionManagerBuilder().
 					Codec(envoy_v3.CodecForVersions(cfg.DefaultHTTPVersions...)).
 					AddFilter(envoy_v3.FilterMisdirectedRequests(vh.VirtualHost.Name)).
 					DefaultFilters().
@@ -480,20 +624,61 @@ func (c *ListenerCache) OnChange(root *dag.DAG) {
 					MaxConnectionDuration(cfg.Timeouts.MaxConnectionDuration).
 					ConnectionShutdownGracePeriod(cfg.Timeouts.ConnectionShutdownGracePeriod).
 					AllowChunkedLength(cfg.AllowChunkedLength).
-					MergeSlashes(cfg.MergeSlashes).
+					AllowAbsoluteURL(cfg.AllowAbsoluteURL).
+					DisableAcceptHTTP10(cfg.DisableAcceptHTTP10).
+					HTTP10DefaultHost(cfg.HTTP10DefaultHost).
+					DeltaXDS(cfg.DeltaXDS).
+					MergeSlashes(ptr.Deref(vh.MergeSlashes, cfg.MergeSlashes)).
+					StripAnyHostPort(cfg.StripAnyHostPort).
+					StripMatchingHostPort(cfg.StripMatchingHostPort).
 					ServerHeaderTransformation(cfg.ServerHeaderTransformation).
+					ServerName(cfg.ServerName).
+					UseRemoteAddress(cfg.UseRemoteAddress).
+					Via(cfg.Via).
 					NumTrustedHops(cfg.XffNumTrustedHops).
 					Tracing(envoy_v3.TracingConfig(envoyTracingConfig(cfg.TracingConfig))).
 					AddFilter(envoy_v3.GlobalRateLimitFilter(envoyGlobalRateLimitConfig(cfg.RateLimitConfig))).
+					AddFilter(envoy_v3.FilterFault(&vh.VirtualHost)).
+					AddFilter(envoy_v3.FilterStatefulSession(&vh.VirtualHost)).
 					ForwardClientCertificate(forwardClientCertificate).
 					MaxRequestsPerConnection(cfg.MaxRequestsPerConnection).
 					HTTP2MaxConcurrentStreams(cfg.HTTP2MaxConcurrentStreams).
+					HTTP2Keepalive(cfg.HTTP2KeepaliveInterval, cfg.HTTP2KeepaliveTimeout).
 					EnableWebsockets(listener.EnableWebsockets).
-					Get()
+					LocalReplyConfig(localReplyConfig)
 
-				filters = envoy_v3.Filters(cm)
+				filters = envoy_v3.Filters(cmb.Get())
 
 				alpnProtos = envoy_v3.ProtoNamesForVersions(cfg.DefaultHTTPVersions...)
+
+				// The QUIC listener shares the connection manager
+				// settings, but always speaks HTTP/3 and requires
+				// TLS 1.3.
+				if cfg.HTTP3 && http3Enabled(vh) {
+					name := quicListenerName(listener.Name)
+					if listeners[name] == nil {
+						listeners[name] = envoy_v3.QUICListener(
+							name,
+							listener.Address,
+							listener.Port,
+							cfg.PerConnectionBufferLimitBytes,
+						)
+					}
+
+					quicTLS := envoy_v3.DownstreamTLSContext(
+						vh.Secret,
+						envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3,
+						envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3,
+						cfg.cipherSuites(vh),
+						vh.DownstreamValidation,
+						"h3")
+					if cfg.DisableSessionTickets || vh.DisableSessionTickets {
+						envoy_v3.DisableSessionTickets(quicTLS)
+					}
+
+					listeners[name].FilterChains = append(listeners[name].FilterChains,
+						envoy_v3.FilterChainQUIC(vh.VirtualHost.Name, quicTLS, envoy_v3.Filters(cmb.Codec(envoy_v3.HTTPVersion3).Get())))
+				}
 			} else {
 				filters = envoy_v3.Filters(envoy_v3.TCPProxy(listener.Name, vh.TCPProxy, cfg.newSecureAccessLog()))
 
@@ -519,9 +704,12 @@ func (c *ListenerCache) OnChange(root *dag.DAG) {
 					vh.Secret,
 					minVer,
 					maxVer,
-					cfg.CipherSuites,
+					cfg.cipherSuites(vh),
 					vh.DownstreamValidation,
 					alpnProtos...)
+				if cfg.DisableSessionTickets || vh.DisableSessionTickets {
+					envoy_v3.DisableSessionTickets(downstreamTLS)
+				}
 			}
 
 			listeners[listener.Name].FilterChains = append(listeners[listener.Name].FilterChains, envoy_v3.FilterChainTLS(vh.VirtualHost.Name, downstreamTLS, filters))
@@ -542,6 +730,9 @@ func (c *ListenerCache) OnChange(root *dag.DAG) {
 					vh.DownstreamValidation,
 					alpnProtos...,
 				)
+				if cfg.DisableSessionTickets {
+					envoy_v3.DisableSessionTickets(downstreamTLS)
+				}
 
 				var authzFilter *envoy_filter_network_http_connection_manager_v3.HttpFilter
 				if vh.ExternalAuthorization != nil {
@@ -561,15 +752,28 @@ func (c *ListenerCache) OnChange(root *dag.DAG) {
 					MaxConnectionDuration(cfg.Timeouts.MaxConnectionDuration).
 					ConnectionShutdownGracePeriod(cfg.Timeouts.ConnectionShutdownGracePeriod).
 					AllowChunkedLength(cfg.AllowChunkedLength).
+					AllowAbsoluteURL(cfg.AllowAbsoluteURL).
+					DisableAcceptHTTP10(cfg.DisableAcceptHTTP10).
+					HTTP10DefaultHost(cfg.HTTP10DefaultHost).
+					DeltaXDS(cfg.DeltaXDS).
 					MergeSlashes(cfg.MergeSlashes).
+					StripAnyHostPort(cfg.StripAnyHostPort).
+					StripMatchingHostPort(cfg.StripMatchingHostPort).
 					ServerHeaderTransformation(cfg.ServerHeaderTransformation).
+					ServerName(cfg.ServerName).
+					UseRemoteAddress(cfg.UseRemoteAddress).
+					Via(cfg.Via).
 					NumTrustedHops(cfg.XffNumTrustedHops).
 					Tracing(envoy_v3.TracingConfig(envoyTracingConfig(cfg.TracingConfig))).
 					AddFilter(envoy_v3.GlobalRateLimitFilter(envoyGlobalRateLimitConfig(cfg.RateLimitConfig))).
+					AddFilter(envoy_v3.FilterFault(listenerVirtualHosts(listener)...)).
+					AddFilter(envoy_v3.FilterStatefulSession(listenerVirtualHosts(listener)...)).
 					ForwardClientCertificate(forwardClientCertificate).
 					MaxRequestsPerConnection(cfg.MaxRequestsPerConnection).
 					HTTP2MaxConcurrentStreams(cfg.HTTP2MaxConcurrentStreams).
+					HTTP2Keepalive(cfg.HTTP2KeepaliveInterval, cfg.HTTP2KeepaliveTimeout).
 					EnableWebsockets(listener.EnableWebsockets).
+					LocalReplyConfig(localReplyConfig).
 					Get()
 
 				// Default filter chain
@@ -587,6 +791,10 @@ func (c *ListenerCache) OnChange(root *dag.DAG) {
 			// to ensure that the LDS entries are identical.
 			sort.Stable(sorter.For(listener.FilterChains))
 		}
+
+		if quic := listeners[quicListenerName(listener.Name)]; quic != nil {
+			sort.Stable(sorter.For(quic.FilterChains))
+		}
 	}
 
 	// support more params of envoy listener
@@ -594,6 +802,11 @@ func (c *ListenerCache) OnChange(root *dag.DAG) {
 	// 1. connection balancer
 	if cfg.ConnectionBalancer == "exact" {
 		for _, listener := range listeners {
+			// Connection balancing only applies to TCP listeners.
+			if listener.UdpListenerConfig != nil {
+				continue
+			}
+
 			listener.ConnectionBalanceConfig = &envoy_config_listener_v3.Listener_ConnectionBalanceConfig{
 				BalanceType: &envoy_config_listener_v3.Listener_ConnectionBalanceConfig_ExactBalance_{
 					ExactBalance: &envoy_config_listener_v3.Listener_ConnectionBalanceConfig_ExactBalance{},
@@ -602,6 +815,13 @@ func (c *ListenerCache) OnChange(root *dag.DAG) {
 		}
 	}
 
+	// 2. listener filters timeout
+	for _, listener := range listeners {
+		if len(listener.ListenerFilters) > 0 {
+			envoy_v3.ListenerFiltersTimeout(listener, cfg.ListenerFiltersTimeout, cfg.ContinueOnListenerFiltersTimeout)
+		}
+	}
+
 	c.Update(listeners)
 }
 
@@ -615,9 +835,12 @@ func httpGlobalExternalAuthConfig(config *GlobalExternalAuthConfig) *envoy_filte
 			Name: dag.ExtensionClusterName(config.ExtensionServiceConfig.ExtensionService),
 			SNI:  config.ExtensionServiceConfig.SNI,
 		},
-		AuthorizationFailOpen:              config.FailOpen,
-		AuthorizationResponseTimeout:       config.ExtensionServiceConfig.Timeout,
-		AuthorizationServerWithRequestBody: config.WithRequestBody,
+		AuthorizationFailOpen:                  config.FailOpen,
+		AuthorizationResponseTimeout:           config.ExtensionServiceConfig.Timeout,
+		AuthorizationServerWithRequestBody:     config.WithRequestBody,
+		AuthorizationRequestAllowedHeaders:     config.RequestAllowedHeaders,
+		AuthorizationRequestDisallowedHeaders:  config.RequestDisallowedHeaders,
+		AuthorizationResponseDisallowedHeaders: config.ResponseDisallowedHeaders,
 	})
 }
 
@@ -669,15 +892,30 @@ func envoyTracingConfigCustomTag(tags []*CustomTag) []*envoy_v3.CustomTag {
 	return customTags
 }
 
-func proxyProtocol(useProxy bool) []*envoy_config_listener_v3.ListenerFilter {
-	if useProxy {
+// quicListenerName returns the name of the HTTP/3 listener
+// that accompanies the named TLS listener.
+func quicListenerName(name string) string {
+	return name + "_quic"
+}
+
+// http3Enabled returns true if the secure virtual host can be
+// served over HTTP/3. QUIC needs Envoy to terminate TLS 1.3, so
+// TLS passthrough, TCP proxying and virtual hosts capped at TLS 1.2
+// are excluded.
+func http3Enabled(vh *dag.SecureVirtualHost) bool {
+	return vh.Secret != nil && vh.TCPProxy == nil &&
+		envoy_v3.ParseTLSVersion(vh.MaxTLSVersion) != envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2
+}
+
+func (lvc *ListenerConfig) proxyProtocol() []*envoy_config_listener_v3.ListenerFilter {
+	if lvc.UseProxyProto {
 		return envoy_v3.ListenerFilters(
-			envoy_v3.ProxyProtocol(),
+			envoy_v3.ProxyProtocol(lvc.AllowRequestsWithoutProxyProto, lvc.ProxyProtoVersions),
 		)
 	}
 	return nil
 }
 
-func secureProxyProtocol(useProxy bool) []*envoy_config_listener_v3.ListenerFilter {
-	return append(proxyProtocol(useProxy), envoy_v3.TLSInspector())
+func (lvc *ListenerConfig) secureProxyProtocol() []*envoy_config_listener_v3.ListenerFilter {
+	return append(lvc.proxyProtocol(), envoy_v3.TLSInspector())
 }
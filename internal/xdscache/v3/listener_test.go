@@ -26,6 +26,7 @@ import (
 	envoy_filter_network_http_connection_manager_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	envoy_transport_socket_tls_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/stretchr/testify/assert"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
@@ -144,6 +145,28 @@ func TestListenerCacheQuery(t *testing.T) {
 	}
 }
 
+func TestListenerCacheListenerStatPrefixes(t *testing.T) {
+	metricsConfig := contour_v1alpha1.MetricsConfig{Address: "0.0.0.0", Port: 8002}
+	healthConfig := contour_v1alpha1.HealthConfig{Address: "0.0.0.0", Port: 8002}
+	lc := NewListenerCache(ListenerConfig{}, metricsConfig, healthConfig, 9001)
+	lc.Update(listenermap(&envoy_config_listener_v3.Listener{
+		Name:    ENVOY_HTTP_LISTENER,
+		Address: envoy_v3.SocketAddress("0.0.0.0", 8080),
+	}, &envoy_config_listener_v3.Listener{
+		Name:    ENVOY_HTTPS_LISTENER,
+		Address: envoy_v3.SocketAddress("::", 8443),
+	}, &envoy_config_listener_v3.Listener{
+		Name:    "ingress_https-http3",
+		Address: envoy_v3.UDPSocketAddress("::", 8443),
+	}))
+
+	assert.Equal(t, map[string]string{
+		"0.0.0.0_8080": ENVOY_HTTP_LISTENER,
+		"[__]_8443":    ENVOY_HTTPS_LISTENER,
+		"0.0.0.0_8002": "stats-health",
+	}, lc.ListenerStatPrefixes())
+}
+
 func TestListenerVisit(t *testing.T) {
 	httpsFilterFor := func(vhost string) *envoy_config_listener_v3.Filter {
 		return envoy_v3.HTTPConnectionManagerBuilder().
@@ -474,6 +497,233 @@ func TestListenerVisit(t *testing.T) {
 			}),
 		},
 
+		"httpproxy with secret and http3 enabled": {
+			ListenerConfig: ListenerConfig{
+				HTTP3: true,
+			},
+			objs: []any{
+				&contour_v1.HTTPProxy{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: contour_v1.HTTPProxySpec{
+						VirtualHost: &contour_v1.VirtualHost{
+							Fqdn: "www.example.com",
+							TLS: &contour_v1.TLS{
+								SecretName: "secret",
+							},
+						},
+						Routes: []contour_v1.Route{{
+							Services: []contour_v1.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				&contour_v1.HTTPProxy{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "tls12",
+						Namespace: "default",
+					},
+					Spec: contour_v1.HTTPProxySpec{
+						VirtualHost: &contour_v1.VirtualHost{
+							Fqdn: "tls12.example.com",
+							TLS: &contour_v1.TLS{
+								SecretName:             "secret",
+								MaximumProtocolVersion: "1.2",
+							},
+						},
+						Routes: []contour_v1.Route{{
+							Services: []contour_v1.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				secret,
+				service,
+			},
+			want: listenermap(&envoy_config_listener_v3.Listener{
+				Name:          ENVOY_HTTP_LISTENER,
+				Address:       envoy_v3.SocketAddress("0.0.0.0", 8080),
+				FilterChains:  envoy_v3.FilterChains(envoy_v3.HTTPConnectionManager(ENVOY_HTTP_LISTENER, envoy_v3.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG, "", nil, contour_v1alpha1.LogLevelInfo), 0)),
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			}, &envoy_config_listener_v3.Listener{
+				Name:    ENVOY_HTTPS_LISTENER,
+				Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
+				FilterChains: []*envoy_config_listener_v3.FilterChain{{
+					FilterChainMatch: &envoy_config_listener_v3.FilterChainMatch{
+						ServerNames: []string{"tls12.example.com"},
+					},
+					TransportSocket: transportSocket("secret", envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, nil, "h2", "http/1.1"),
+					Filters:         envoy_v3.Filters(httpsFilterFor("tls12.example.com")),
+				}, {
+					FilterChainMatch: &envoy_config_listener_v3.FilterChainMatch{
+						ServerNames: []string{"www.example.com"},
+					},
+					TransportSocket: transportSocket("secret", envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, nil, "h2", "http/1.1"),
+					Filters:         envoy_v3.Filters(httpsFilterFor("www.example.com")),
+				}},
+				ListenerFilters: envoy_v3.ListenerFilters(
+					envoy_v3.TLSInspector(),
+				),
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			}, &envoy_config_listener_v3.Listener{
+				// Only the virtual host that allows TLS 1.3 is
+				// served over HTTP/3.
+				Name:    ENVOY_HTTPS_LISTENER + "_quic",
+				Address: envoy_v3.UDPSocketAddress("0.0.0.0", 8443),
+				UdpListenerConfig: &envoy_config_listener_v3.UdpListenerConfig{
+					QuicOptions: &envoy_config_listener_v3.QuicProtocolOptions{},
+					DownstreamSocketConfig: &envoy_config_core_v3.UdpSocketConfig{
+						PreferGro: wrapperspb.Bool(true),
+					},
+				},
+				FilterChains: []*envoy_config_listener_v3.FilterChain{{
+					FilterChainMatch: &envoy_config_listener_v3.FilterChainMatch{
+						ServerNames: []string{"www.example.com"},
+					},
+					TransportSocket: quicTransportSocket("secret"),
+					Filters: envoy_v3.Filters(envoy_v3.HTTPConnectionManagerBuilder().
+						Codec(envoy_v3.HTTPVersion3).
+						AddFilter(envoy_v3.FilterMisdirectedRequests("www.example.com")).
+						DefaultFilters().
+						MetricsPrefix(ENVOY_HTTPS_LISTENER).
+						RouteConfigName(path.Join("https", "www.example.com")).
+						AccessLoggers(envoy_v3.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG, "", nil, contour_v1alpha1.LogLevelInfo)).
+						Get(),
+					),
+				}},
+			}),
+		},
+
+		"httpproxy with session tickets disabled": {
+			objs: []any{
+				&contour_v1.HTTPProxy{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: contour_v1.HTTPProxySpec{
+						VirtualHost: &contour_v1.VirtualHost{
+							Fqdn: "www.example.com",
+							TLS: &contour_v1.TLS{
+								SecretName:            "secret",
+								DisableSessionTickets: true,
+							},
+						},
+						Routes: []contour_v1.Route{{
+							Services: []contour_v1.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				&contour_v1.HTTPProxy{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "tickets",
+						Namespace: "default",
+					},
+					Spec: contour_v1.HTTPProxySpec{
+						VirtualHost: &contour_v1.VirtualHost{
+							Fqdn: "tickets.example.com",
+							TLS: &contour_v1.TLS{
+								SecretName: "secret",
+							},
+						},
+						Routes: []contour_v1.Route{{
+							Services: []contour_v1.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				secret,
+				service,
+			},
+			want: listenermap(&envoy_config_listener_v3.Listener{
+				Name:          ENVOY_HTTP_LISTENER,
+				Address:       envoy_v3.SocketAddress("0.0.0.0", 8080),
+				FilterChains:  envoy_v3.FilterChains(envoy_v3.HTTPConnectionManager(ENVOY_HTTP_LISTENER, envoy_v3.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG, "", nil, contour_v1alpha1.LogLevelInfo), 0)),
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			}, &envoy_config_listener_v3.Listener{
+				Name:    ENVOY_HTTPS_LISTENER,
+				Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
+				FilterChains: []*envoy_config_listener_v3.FilterChain{{
+					FilterChainMatch: &envoy_config_listener_v3.FilterChainMatch{
+						ServerNames: []string{"tickets.example.com"},
+					},
+					TransportSocket: transportSocket("secret", envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, nil, "h2", "http/1.1"),
+					Filters:         envoy_v3.Filters(httpsFilterFor("tickets.example.com")),
+				}, {
+					FilterChainMatch: &envoy_config_listener_v3.FilterChainMatch{
+						ServerNames: []string{"www.example.com"},
+					},
+					TransportSocket: transportSocketWithoutSessionTickets("secret", "h2", "http/1.1"),
+					Filters:         envoy_v3.Filters(httpsFilterFor("www.example.com")),
+				}},
+				ListenerFilters: envoy_v3.ListenerFilters(
+					envoy_v3.TLSInspector(),
+				),
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			}),
+		},
+
+		"session tickets disabled from config": {
+			ListenerConfig: ListenerConfig{
+				DisableSessionTickets: true,
+			},
+			objs: []any{
+				&contour_v1.HTTPProxy{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: contour_v1.HTTPProxySpec{
+						VirtualHost: &contour_v1.VirtualHost{
+							Fqdn: "www.example.com",
+							TLS: &contour_v1.TLS{
+								SecretName: "secret",
+							},
+						},
+						Routes: []contour_v1.Route{{
+							Services: []contour_v1.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				secret,
+				service,
+			},
+			want: listenermap(&envoy_config_listener_v3.Listener{
+				Name:          ENVOY_HTTP_LISTENER,
+				Address:       envoy_v3.SocketAddress("0.0.0.0", 8080),
+				FilterChains:  envoy_v3.FilterChains(envoy_v3.HTTPConnectionManager(ENVOY_HTTP_LISTENER, envoy_v3.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG, "", nil, contour_v1alpha1.LogLevelInfo), 0)),
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			}, &envoy_config_listener_v3.Listener{
+				Name:    ENVOY_HTTPS_LISTENER,
+				Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
+				FilterChains: []*envoy_config_listener_v3.FilterChain{{
+					FilterChainMatch: &envoy_config_listener_v3.FilterChainMatch{
+						ServerNames: []string{"www.example.com"},
+					},
+					TransportSocket: transportSocketWithoutSessionTickets("secret", "h2", "http/1.1"),
+					Filters:         envoy_v3.Filters(httpsFilterFor("www.example.com")),
+				}},
+				ListenerFilters: envoy_v3.ListenerFilters(
+					envoy_v3.TLSInspector(),
+				),
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			}),
+		},
+
 		"ingress with allow-http: false": {
 			objs: []any{
 				&networking_v1.Ingress{
@@ -571,7 +821,7 @@ func TestListenerVisit(t *testing.T) {
 				Name:    ENVOY_HTTP_LISTENER,
 				Address: envoy_v3.SocketAddress("0.0.0.0", 8080),
 				ListenerFilters: envoy_v3.ListenerFilters(
-					envoy_v3.ProxyProtocol(),
+					envoy_v3.ProxyProtocol(false, nil),
 				),
 				FilterChains:  envoy_v3.FilterChains(envoy_v3.HTTPConnectionManager(ENVOY_HTTP_LISTENER, envoy_v3.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG, "", nil, contour_v1alpha1.LogLevelInfo), 0)),
 				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
@@ -579,9 +829,64 @@ func TestListenerVisit(t *testing.T) {
 				Name:    ENVOY_HTTPS_LISTENER,
 				Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
 				ListenerFilters: envoy_v3.ListenerFilters(
-					envoy_v3.ProxyProtocol(),
+					envoy_v3.ProxyProtocol(false, nil),
+					envoy_v3.TLSInspector(),
+				),
+				FilterChains: []*envoy_config_listener_v3.FilterChain{{
+					FilterChainMatch: &envoy_config_listener_v3.FilterChainMatch{
+						ServerNames: []string{"whatever.example.com"},
+					},
+					TransportSocket: transportSocket("secret", envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, nil, "h2", "http/1.1"),
+					Filters:         envoy_v3.Filters(httpsFilterFor("whatever.example.com")),
+				}},
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			}),
+		},
+		"listener filters timeout": {
+			ListenerConfig: ListenerConfig{
+				ListenerFiltersTimeout:           timeout.DurationSetting(30 * time.Second),
+				ContinueOnListenerFiltersTimeout: true,
+			},
+			objs: []any{
+				&networking_v1.Ingress{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: networking_v1.IngressSpec{
+						TLS: []networking_v1.IngressTLS{{
+							Hosts:      []string{"whatever.example.com"},
+							SecretName: "secret",
+						}},
+						Rules: []networking_v1.IngressRule{{
+							Host: "whatever.example.com",
+							IngressRuleValue: networking_v1.IngressRuleValue{
+								HTTP: &networking_v1.HTTPIngressRuleValue{
+									Paths: []networking_v1.HTTPIngressPath{{
+										Backend: *backend("kuard", 8080),
+									}},
+								},
+							},
+						}},
+					},
+				},
+				secret,
+				service,
+			},
+			// Only listeners with listener filters have the timeout set.
+			want: listenermap(&envoy_config_listener_v3.Listener{
+				Name:          ENVOY_HTTP_LISTENER,
+				Address:       envoy_v3.SocketAddress("0.0.0.0", 8080),
+				FilterChains:  envoy_v3.FilterChains(envoy_v3.HTTPConnectionManager(ENVOY_HTTP_LISTENER, envoy_v3.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG, "", nil, contour_v1alpha1.LogLevelInfo), 0)),
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			}, &envoy_config_listener_v3.Listener{
+				Name:    ENVOY_HTTPS_LISTENER,
+				Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
+				ListenerFilters: envoy_v3.ListenerFilters(
 					envoy_v3.TLSInspector(),
 				),
+				ListenerFiltersTimeout:           durationpb.New(30 * time.Second),
+				ContinueOnListenerFiltersTimeout: true,
 				FilterChains: []*envoy_config_listener_v3.FilterChain{{
 					FilterChainMatch: &envoy_config_listener_v3.FilterChainMatch{
 						ServerNames: []string{"whatever.example.com"},
@@ -713,27 +1018,148 @@ func TestListenerVisit(t *testing.T) {
 			objs: []any{
 				&networking_v1.Ingress{
 					ObjectMeta: meta_v1.ObjectMeta{
-						Name:      "simple",
+						Name:      "simple",
+						Namespace: "default",
+						Annotations: map[string]string{
+							"projectcontour.io/tls-minimum-protocol-version": "1.3",
+							"projectcontour.io/tls-maximum-protocol-version": "1.3",
+						},
+					},
+					Spec: networking_v1.IngressSpec{
+						TLS: []networking_v1.IngressTLS{{
+							Hosts:      []string{"whatever.example.com"},
+							SecretName: "secret",
+						}},
+						Rules: []networking_v1.IngressRule{{
+							Host: "whatever.example.com",
+							IngressRuleValue: networking_v1.IngressRuleValue{
+								HTTP: &networking_v1.HTTPIngressRuleValue{
+									Paths: []networking_v1.HTTPIngressPath{{
+										Backend: *backend("kuard", 8080),
+									}},
+								},
+							},
+						}},
+					},
+				},
+				secret,
+				service,
+			},
+			want: listenermap(&envoy_config_listener_v3.Listener{
+				Name:          ENVOY_HTTP_LISTENER,
+				Address:       envoy_v3.SocketAddress("0.0.0.0", 8080),
+				FilterChains:  envoy_v3.FilterChains(envoy_v3.HTTPConnectionManager(ENVOY_HTTP_LISTENER, envoy_v3.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG, "", nil, contour_v1alpha1.LogLevelInfo), 0)),
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			}, &envoy_config_listener_v3.Listener{
+				Name:    ENVOY_HTTPS_LISTENER,
+				Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
+				FilterChains: []*envoy_config_listener_v3.FilterChain{{
+					FilterChainMatch: &envoy_config_listener_v3.FilterChainMatch{
+						ServerNames: []string{"whatever.example.com"},
+					},
+					TransportSocket: transportSocket("secret", envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, nil, "h2", "http/1.1"), // note, cannot downgrade from the configured version
+					Filters:         envoy_v3.Filters(httpsFilterFor("whatever.example.com")),
+				}},
+				ListenerFilters: envoy_v3.ListenerFilters(
+					envoy_v3.TLSInspector(),
+				),
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			}),
+		},
+
+		"tls-protocol-version from config overridden by httpproxy": {
+			ListenerConfig: ListenerConfig{
+				MinimumTLSVersion: "1.2",
+				MaximumTLSVersion: "1.3",
+			},
+			objs: []any{
+				&contour_v1.HTTPProxy{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: contour_v1.HTTPProxySpec{
+						VirtualHost: &contour_v1.VirtualHost{
+							Fqdn: "www.example.com",
+							TLS: &contour_v1.TLS{
+								SecretName:             "secret",
+								MinimumProtocolVersion: "1.3",
+								MaximumProtocolVersion: "1.3",
+							},
+						},
+						Routes: []contour_v1.Route{{
+							Services: []contour_v1.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				secret,
+				service,
+			},
+			want: listenermap(&envoy_config_listener_v3.Listener{
+				Name:          ENVOY_HTTP_LISTENER,
+				Address:       envoy_v3.SocketAddress("0.0.0.0", 8080),
+				FilterChains:  envoy_v3.FilterChains(envoy_v3.HTTPConnectionManager(ENVOY_HTTP_LISTENER, envoy_v3.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG, "", nil, contour_v1alpha1.LogLevelInfo), 0)),
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			}, &envoy_config_listener_v3.Listener{
+				Name:    ENVOY_HTTPS_LISTENER,
+				Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
+				FilterChains: []*envoy_config_listener_v3.FilterChain{{
+					FilterChainMatch: &envoy_config_listener_v3.FilterChainMatch{
+						ServerNames: []string{"www.example.com"},
+					},
+					TransportSocket: transportSocket("secret", envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, nil, "h2", "http/1.1"), // note, cannot downgrade from the configured version
+					Filters:         envoy_v3.Filters(httpsFilterFor("www.example.com")),
+				}},
+				ListenerFilters: envoy_v3.ListenerFilters(
+					envoy_v3.TLSInspector(),
+				),
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			}),
+		},
+		"tls-protocol-versions from httpproxies produce distinct filter chains": {
+			objs: []any{
+				&contour_v1.HTTPProxy{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "modern",
+						Namespace: "default",
+					},
+					Spec: contour_v1.HTTPProxySpec{
+						VirtualHost: &contour_v1.VirtualHost{
+							Fqdn: "modern.example.com",
+							TLS: &contour_v1.TLS{
+								SecretName:             "secret",
+								MinimumProtocolVersion: "1.3",
+							},
+						},
+						Routes: []contour_v1.Route{{
+							Services: []contour_v1.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				&contour_v1.HTTPProxy{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "legacy",
 						Namespace: "default",
-						Annotations: map[string]string{
-							"projectcontour.io/tls-minimum-protocol-version": "1.3",
-							"projectcontour.io/tls-maximum-protocol-version": "1.3",
-						},
 					},
-					Spec: networking_v1.IngressSpec{
-						TLS: []networking_v1.IngressTLS{{
-							Hosts:      []string{"whatever.example.com"},
-							SecretName: "secret",
-						}},
-						Rules: []networking_v1.IngressRule{{
-							Host: "whatever.example.com",
-							IngressRuleValue: networking_v1.IngressRuleValue{
-								HTTP: &networking_v1.HTTPIngressRuleValue{
-									Paths: []networking_v1.HTTPIngressPath{{
-										Backend: *backend("kuard", 8080),
-									}},
-								},
+					Spec: contour_v1.HTTPProxySpec{
+						VirtualHost: &contour_v1.VirtualHost{
+							Fqdn: "legacy.example.com",
+							TLS: &contour_v1.TLS{
+								SecretName:             "secret",
+								MaximumProtocolVersion: "1.2",
 							},
+						},
+						Routes: []contour_v1.Route{{
+							Services: []contour_v1.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
 						}},
 					},
 				},
@@ -748,12 +1174,20 @@ func TestListenerVisit(t *testing.T) {
 			}, &envoy_config_listener_v3.Listener{
 				Name:    ENVOY_HTTPS_LISTENER,
 				Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
+				// Each vhost gets its own filter chain, so each
+				// negotiates its own TLS versions.
 				FilterChains: []*envoy_config_listener_v3.FilterChain{{
 					FilterChainMatch: &envoy_config_listener_v3.FilterChainMatch{
-						ServerNames: []string{"whatever.example.com"},
+						ServerNames: []string{"legacy.example.com"},
 					},
-					TransportSocket: transportSocket("secret", envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, nil, "h2", "http/1.1"), // note, cannot downgrade from the configured version
-					Filters:         envoy_v3.Filters(httpsFilterFor("whatever.example.com")),
+					TransportSocket: transportSocket("secret", envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, nil, "h2", "http/1.1"),
+					Filters:         envoy_v3.Filters(httpsFilterFor("legacy.example.com")),
+				}, {
+					FilterChainMatch: &envoy_config_listener_v3.FilterChainMatch{
+						ServerNames: []string{"modern.example.com"},
+					},
+					TransportSocket: transportSocket("secret", envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, nil, "h2", "http/1.1"),
+					Filters:         envoy_v3.Filters(httpsFilterFor("modern.example.com")),
 				}},
 				ListenerFilters: envoy_v3.ListenerFilters(
 					envoy_v3.TLSInspector(),
@@ -762,10 +1196,10 @@ func TestListenerVisit(t *testing.T) {
 			}),
 		},
 
-		"tls-protocol-version from config overridden by httpproxy": {
+		"tls-maximum-protocol-version from config not overridden by httpproxy": {
 			ListenerConfig: ListenerConfig{
 				MinimumTLSVersion: "1.2",
-				MaximumTLSVersion: "1.3",
+				MaximumTLSVersion: "1.2",
 			},
 			objs: []any{
 				&contour_v1.HTTPProxy{
@@ -778,7 +1212,7 @@ func TestListenerVisit(t *testing.T) {
 							Fqdn: "www.example.com",
 							TLS: &contour_v1.TLS{
 								SecretName:             "secret",
-								MinimumProtocolVersion: "1.3",
+								MinimumProtocolVersion: "1.2",
 								MaximumProtocolVersion: "1.3",
 							},
 						},
@@ -805,7 +1239,7 @@ func TestListenerVisit(t *testing.T) {
 					FilterChainMatch: &envoy_config_listener_v3.FilterChainMatch{
 						ServerNames: []string{"www.example.com"},
 					},
-					TransportSocket: transportSocket("secret", envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, nil, "h2", "http/1.1"), // note, cannot downgrade from the configured version
+					TransportSocket: transportSocket("secret", envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, nil, "h2", "http/1.1"), // note, cannot downgrade from the configured version
 					Filters:         envoy_v3.Filters(httpsFilterFor("www.example.com")),
 				}},
 				ListenerFilters: envoy_v3.ListenerFilters(
@@ -814,10 +1248,12 @@ func TestListenerVisit(t *testing.T) {
 				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
 			}),
 		},
-		"tls-maximum-protocol-version from config not overridden by httpproxy": {
+		"tls-cipher-suites from config": {
 			ListenerConfig: ListenerConfig{
-				MinimumTLSVersion: "1.2",
-				MaximumTLSVersion: "1.2",
+				CipherSuites: []string{
+					"ECDHE-ECDSA-AES256-GCM-SHA384",
+					"ECDHE-RSA-AES256-GCM-SHA384",
+				},
 			},
 			objs: []any{
 				&contour_v1.HTTPProxy{
@@ -829,9 +1265,7 @@ func TestListenerVisit(t *testing.T) {
 						VirtualHost: &contour_v1.VirtualHost{
 							Fqdn: "www.example.com",
 							TLS: &contour_v1.TLS{
-								SecretName:             "secret",
-								MinimumProtocolVersion: "1.2",
-								MaximumProtocolVersion: "1.3",
+								SecretName: "secret",
 							},
 						},
 						Routes: []contour_v1.Route{{
@@ -857,7 +1291,7 @@ func TestListenerVisit(t *testing.T) {
 					FilterChainMatch: &envoy_config_listener_v3.FilterChainMatch{
 						ServerNames: []string{"www.example.com"},
 					},
-					TransportSocket: transportSocket("secret", envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, nil, "h2", "http/1.1"), // note, cannot downgrade from the configured version
+					TransportSocket: transportSocket("secret", envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, []string{"ECDHE-ECDSA-AES256-GCM-SHA384", "ECDHE-RSA-AES256-GCM-SHA384"}, "h2", "http/1.1"),
 					Filters:         envoy_v3.Filters(httpsFilterFor("www.example.com")),
 				}},
 				ListenerFilters: envoy_v3.ListenerFilters(
@@ -866,7 +1300,8 @@ func TestListenerVisit(t *testing.T) {
 				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
 			}),
 		},
-		"tls-cipher-suites from config": {
+
+		"tls-cipher-suites from config overridden by httpproxy": {
 			ListenerConfig: ListenerConfig{
 				CipherSuites: []string{
 					"ECDHE-ECDSA-AES256-GCM-SHA384",
@@ -882,6 +1317,27 @@ func TestListenerVisit(t *testing.T) {
 					Spec: contour_v1.HTTPProxySpec{
 						VirtualHost: &contour_v1.VirtualHost{
 							Fqdn: "www.example.com",
+							TLS: &contour_v1.TLS{
+								SecretName:   "secret",
+								CipherSuites: []string{"ECDHE-RSA-AES128-SHA"},
+							},
+						},
+						Routes: []contour_v1.Route{{
+							Services: []contour_v1.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				&contour_v1.HTTPProxy{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "other",
+						Namespace: "default",
+					},
+					Spec: contour_v1.HTTPProxySpec{
+						VirtualHost: &contour_v1.VirtualHost{
+							Fqdn: "other.example.com",
 							TLS: &contour_v1.TLS{
 								SecretName: "secret",
 							},
@@ -907,9 +1363,15 @@ func TestListenerVisit(t *testing.T) {
 				Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
 				FilterChains: []*envoy_config_listener_v3.FilterChain{{
 					FilterChainMatch: &envoy_config_listener_v3.FilterChainMatch{
-						ServerNames: []string{"www.example.com"},
+						ServerNames: []string{"other.example.com"},
 					},
 					TransportSocket: transportSocket("secret", envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, []string{"ECDHE-ECDSA-AES256-GCM-SHA384", "ECDHE-RSA-AES256-GCM-SHA384"}, "h2", "http/1.1"),
+					Filters:         envoy_v3.Filters(httpsFilterFor("other.example.com")),
+				}, {
+					FilterChainMatch: &envoy_config_listener_v3.FilterChainMatch{
+						ServerNames: []string{"www.example.com"},
+					},
+					TransportSocket: transportSocket("secret", envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, []string{"ECDHE-RSA-AES128-SHA"}, "h2", "http/1.1"),
 					Filters:         envoy_v3.Filters(httpsFilterFor("www.example.com")),
 				}},
 				ListenerFilters: envoy_v3.ListenerFilters(
@@ -2088,6 +2550,66 @@ func TestListenerVisit(t *testing.T) {
 				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
 			}),
 		},
+		"httpproxy with path normalization policy overriding merge_slashes": {
+			ListenerConfig: ListenerConfig{
+				MergeSlashes: true,
+			},
+			objs: []any{
+				&contour_v1.HTTPProxy{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: contour_v1.HTTPProxySpec{
+						VirtualHost: &contour_v1.VirtualHost{
+							Fqdn: "www.example.com",
+							TLS: &contour_v1.TLS{
+								SecretName: "secret",
+							},
+							PathNormalizationPolicy: &contour_v1.PathNormalizationPolicy{
+								MergeSlashes: ptr.To(false),
+							},
+						},
+						Routes: []contour_v1.Route{{
+							Services: []contour_v1.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				secret,
+				service,
+			},
+			want: listenermap(&envoy_config_listener_v3.Listener{
+				Name:    ENVOY_HTTP_LISTENER,
+				Address: envoy_v3.SocketAddress("0.0.0.0", 8080),
+				FilterChains: envoy_v3.FilterChains(
+					envoy_v3.HTTPConnectionManagerBuilder().
+						RouteConfigName(ENVOY_HTTP_LISTENER).
+						MetricsPrefix(ENVOY_HTTP_LISTENER).
+						AccessLoggers(envoy_v3.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG, "", nil, contour_v1alpha1.LogLevelInfo)).
+						DefaultFilters().
+						MergeSlashes(true).
+						Get(),
+				),
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			}, &envoy_config_listener_v3.Listener{
+				Name:    ENVOY_HTTPS_LISTENER,
+				Address: envoy_v3.SocketAddress("0.0.0.0", 8443),
+				FilterChains: []*envoy_config_listener_v3.FilterChain{{
+					FilterChainMatch: &envoy_config_listener_v3.FilterChainMatch{
+						ServerNames: []string{"www.example.com"},
+					},
+					TransportSocket: transportSocket("secret", envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, nil, "h2", "http/1.1"),
+					Filters:         envoy_v3.Filters(httpsFilterFor("www.example.com")),
+				}},
+				ListenerFilters: envoy_v3.ListenerFilters(
+					envoy_v3.TLSInspector(),
+				),
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			}),
+		},
 		"httpproxy with server_header_transformation set to pass through in listener config": {
 			ListenerConfig: ListenerConfig{
 				ServerHeaderTransformation: contour_v1alpha1.PassThroughServerHeader,
@@ -2131,6 +2653,94 @@ func TestListenerVisit(t *testing.T) {
 				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
 			}),
 		},
+		"httpproxy with server_name set in listener config": {
+			ListenerConfig: ListenerConfig{
+				ServerName: "example",
+			},
+			objs: []any{
+				&contour_v1.HTTPProxy{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: contour_v1.HTTPProxySpec{
+						VirtualHost: &contour_v1.VirtualHost{
+							Fqdn: "www.example.com",
+						},
+						Routes: []contour_v1.Route{{
+							Conditions: []contour_v1.MatchCondition{{
+								Prefix: "/",
+							}},
+							Services: []contour_v1.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				service,
+			},
+
+			want: listenermap(&envoy_config_listener_v3.Listener{
+				Name:    ENVOY_HTTP_LISTENER,
+				Address: envoy_v3.SocketAddress("0.0.0.0", 8080),
+				FilterChains: envoy_v3.FilterChains(
+					envoy_v3.HTTPConnectionManagerBuilder().
+						RouteConfigName(ENVOY_HTTP_LISTENER).
+						MetricsPrefix(ENVOY_HTTP_LISTENER).
+						AccessLoggers(envoy_v3.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG, "", nil, contour_v1alpha1.LogLevelInfo)).
+						DefaultFilters().
+						ServerName("example").
+						Get(),
+				),
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			}),
+		},
+		"httpproxy with use_remote_address and via set in listener config": {
+			ListenerConfig: ListenerConfig{
+				UseRemoteAddress: ptr.To(false),
+				Via:              "1.1 contour",
+			},
+			objs: []any{
+				&contour_v1.HTTPProxy{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: contour_v1.HTTPProxySpec{
+						VirtualHost: &contour_v1.VirtualHost{
+							Fqdn: "www.example.com",
+						},
+						Routes: []contour_v1.Route{{
+							Conditions: []contour_v1.MatchCondition{{
+								Prefix: "/",
+							}},
+							Services: []contour_v1.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				service,
+			},
+
+			want: listenermap(&envoy_config_listener_v3.Listener{
+				Name:    ENVOY_HTTP_LISTENER,
+				Address: envoy_v3.SocketAddress("0.0.0.0", 8080),
+				FilterChains: envoy_v3.FilterChains(
+					envoy_v3.HTTPConnectionManagerBuilder().
+						RouteConfigName(ENVOY_HTTP_LISTENER).
+						MetricsPrefix(ENVOY_HTTP_LISTENER).
+						AccessLoggers(envoy_v3.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG, "", nil, contour_v1alpha1.LogLevelInfo)).
+						DefaultFilters().
+						UseRemoteAddress(ptr.To(false)).
+						Via("1.1 contour").
+						Get(),
+				),
+				SocketOptions: envoy_v3.NewSocketOptions().TCPKeepalive().Build(),
+			}),
+		},
 		"httpproxy with XffNumTrustedHops set in listener config": {
 			ListenerConfig: ListenerConfig{
 				XffNumTrustedHops: 1,
@@ -3262,6 +3872,40 @@ func transportSocket(secretName string, tlsMinProtoVersion, tlsMaxProtoVersion e
 	)
 }
 
+func transportSocketWithoutSessionTickets(secretName string, alpnprotos ...string) *envoy_config_core_v3.TransportSocket {
+	secret := &dag.Secret{
+		Object: &core_v1.Secret{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      secretName,
+				Namespace: "default",
+			},
+			Type: core_v1.SecretTypeTLS,
+			Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+		},
+	}
+	return envoy_v3.DownstreamTLSTransportSocket(
+		envoy_v3.DisableSessionTickets(
+			envoy_v3.DownstreamTLSContext(secret, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_2, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, nil, nil, alpnprotos...),
+		),
+	)
+}
+
+func quicTransportSocket(secretName string) *envoy_config_core_v3.TransportSocket {
+	secret := &dag.Secret{
+		Object: &core_v1.Secret{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      secretName,
+				Namespace: "default",
+			},
+			Type: core_v1.SecretTypeTLS,
+			Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+		},
+	}
+	return envoy_v3.DownstreamQUICTransportSocket(
+		envoy_v3.DownstreamTLSContext(secret, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, envoy_transport_socket_tls_v3.TlsParameters_TLSv1_3, nil, nil, "h3"),
+	)
+}
+
 func listenermap(listeners ...*envoy_config_listener_v3.Listener) map[string]*envoy_config_listener_v3.Listener {
 	m := make(map[string]*envoy_config_listener_v3.Listener)
 	for _, l := range listeners {
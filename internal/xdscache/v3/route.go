@@ -31,6 +31,19 @@ import (
 
 // RouteCache manages the contents of the gRPC RDS cache.
 type RouteCache struct {
+	// ContourVersionHeader determines if every RouteConfiguration
+	// adds the X-Contour-Version response header.
+	ContourVersionHeader bool
+
+	// RouteIdentifierHeader, if set, is the name of the request header
+	// that is set to the identifier of the matched route.
+	RouteIdentifierHeader string
+
+	// HTTP3AdvertisedPort, if non-zero, adds an alt-svc response
+	// header advertising HTTP/3 on this port to the route
+	// configurations of TLS virtual hosts served over HTTP/3.
+	HTTP3AdvertisedPort int
+
 	mu     sync.Mutex
 	values map[string]*envoy_config_route_v3.RouteConfiguration
 	contour.Cond
@@ -118,7 +131,7 @@ func (c *RouteCache) OnChange(root *dag.DAG) {
 				sortRoutes(routes)
 
 				routeConfigs[routeConfigName].VirtualHosts = append(routeConfigs[routeConfigName].VirtualHosts,
-					envoy_v3.VirtualHostAndRoutes(vhost, routes, false),
+					envoy_v3.VirtualHostAndRoutes(vhost, routes, false, c.RouteIdentifierHeader),
 				)
 			}
 		}
@@ -143,7 +156,12 @@ func (c *RouteCache) OnChange(root *dag.DAG) {
 				sortRoutes(routes)
 
 				routeConfigs[routeConfigName].VirtualHosts = append(routeConfigs[routeConfigName].VirtualHosts,
-					envoy_v3.VirtualHostAndRoutes(&vhost.VirtualHost, routes, true))
+					envoy_v3.VirtualHostAndRoutes(&vhost.VirtualHost, routes, true, c.RouteIdentifierHeader))
+
+				if c.HTTP3AdvertisedPort > 0 && http3Enabled(vhost) {
+					routeConfigs[routeConfigName].ResponseHeadersToAdd = append(routeConfigs[routeConfigName].ResponseHeadersToAdd,
+						envoy_v3.AltSvcHeader(c.HTTP3AdvertisedPort))
+				}
 
 				// A fallback route configuration contains routes for all the vhosts that have the fallback certificate enabled.
 				// When a request is received, the default TLS filterchain will accept the connection,
@@ -156,7 +174,7 @@ func (c *RouteCache) OnChange(root *dag.DAG) {
 					}
 
 					routeConfigs[routeConfigName].VirtualHosts = append(routeConfigs[routeConfigName].VirtualHosts,
-						envoy_v3.VirtualHostAndRoutes(&vhost.VirtualHost, routes, true))
+						envoy_v3.VirtualHostAndRoutes(&vhost.VirtualHost, routes, true, c.RouteIdentifierHeader))
 				}
 			}
 		}
@@ -164,6 +182,10 @@ func (c *RouteCache) OnChange(root *dag.DAG) {
 
 	for _, routeConfig := range routeConfigs {
 		sort.Stable(sorter.For(routeConfig.VirtualHosts))
+
+		if c.ContourVersionHeader {
+			routeConfig.ResponseHeadersToAdd = append(routeConfig.ResponseHeadersToAdd, envoy_v3.VersionHeader())
+		}
 	}
 
 	c.Update(routeConfigs)
@@ -3421,6 +3421,122 @@ func TestRouteVisit(t *testing.T) {
 	}
 }
 
+func TestRouteVisit_ContourVersionHeader(t *testing.T) {
+	objs := []any{
+		&networking_v1.Ingress{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "kuard",
+				Namespace: "default",
+			},
+			Spec: networking_v1.IngressSpec{
+				DefaultBackend: backend("kuard", 8080),
+			},
+		},
+		&core_v1.Service{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "kuard",
+				Namespace: "default",
+			},
+			Spec: core_v1.ServiceSpec{
+				Ports: []core_v1.ServicePort{{
+					Protocol:   "TCP",
+					Port:       8080,
+					TargetPort: intstr.FromInt(8080),
+				}},
+			},
+		},
+	}
+
+	want := envoy_v3.RouteConfiguration("ingress_http",
+		envoy_v3.VirtualHost("*",
+			&envoy_config_route_v3.Route{
+				Match:  routePrefix("/"),
+				Action: routecluster("default/kuard/8080/da39a3ee5e"),
+			},
+		),
+	)
+	want.ResponseHeadersToAdd = append(want.ResponseHeadersToAdd, envoy_v3.VersionHeader())
+
+	rc := RouteCache{ContourVersionHeader: true}
+	rc.OnChange(buildDAG(t, objs...))
+	protobuf.ExpectEqual(t, routeConfigurations(want), rc.values)
+}
+
+func TestRouteVisit_HTTP3AltSvc(t *testing.T) {
+	objs := []any{
+		&networking_v1.Ingress{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "kuard",
+				Namespace: "default",
+			},
+			Spec: networking_v1.IngressSpec{
+				TLS: []networking_v1.IngressTLS{{
+					Hosts:      []string{"www.example.com"},
+					SecretName: "secret",
+				}},
+				Rules: []networking_v1.IngressRule{{
+					Host: "www.example.com",
+					IngressRuleValue: networking_v1.IngressRuleValue{
+						HTTP: &networking_v1.HTTPIngressRuleValue{
+							Paths: []networking_v1.HTTPIngressPath{{
+								Backend: *backend("kuard", 8080),
+							}},
+						},
+					},
+				}},
+			},
+		},
+		&core_v1.Secret{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "secret",
+				Namespace: "default",
+			},
+			Type: "kubernetes.io/tls",
+			Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+		},
+		&core_v1.Service{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "kuard",
+				Namespace: "default",
+			},
+			Spec: core_v1.ServiceSpec{
+				Ports: []core_v1.ServicePort{{
+					Protocol:   "TCP",
+					Port:       8080,
+					TargetPort: intstr.FromInt(8080),
+				}},
+			},
+		},
+	}
+
+	secure := envoy_v3.RouteConfiguration("https/www.example.com",
+		envoy_v3.VirtualHost("www.example.com",
+			&envoy_config_route_v3.Route{
+				Match:  routePrefix("/"),
+				Action: routecluster("default/kuard/8080/da39a3ee5e"),
+			},
+		),
+	)
+	secure.ResponseHeadersToAdd = append(secure.ResponseHeadersToAdd, envoy_v3.AltSvcHeader(443))
+
+	// HTTP/3 is only advertised on the TLS route configuration.
+	want := routeConfigurations(
+		envoy_v3.RouteConfiguration("ingress_http",
+			envoy_v3.VirtualHost("www.example.com",
+				&envoy_config_route_v3.Route{
+					Match:  routePrefix("/"),
+					Action: routecluster("default/kuard/8080/da39a3ee5e"),
+				},
+			),
+		),
+		secure,
+	)
+
+	rc := RouteCache{HTTP3AdvertisedPort: 443}
+	rc.OnChange(buildDAG(t, objs...))
+	protobuf.ExpectEqual(t, want, rc.values)
+}
+
 func TestRouteVisit_GlobalExternalAuthorization(t *testing.T) {
 	tests := map[string]struct {
 		objs                []any
@@ -15,6 +15,9 @@ package v3
 
 import (
 	"context"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	envoy_types "github.com/envoyproxy/go-control-plane/pkg/cache/types"
@@ -25,6 +28,7 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/metrics"
 	contour_xds_v3 "github.com/projectcontour/contour/internal/xds/v3"
 	"github.com/projectcontour/contour/internal/xdscache"
 )
@@ -37,14 +41,39 @@ type SnapshotHandler struct {
 	defaultCache envoy_cache_v3.SnapshotCache
 	edsCache     envoy_cache_v3.SnapshotCache
 	mux          *envoy_cache_v3.MuxCache
+	metrics      *metrics.Metrics
 	log          logrus.FieldLogger
+
+	// resourceTTL, if positive, is set as the TTL of the runtime
+	// resources in the snapshots.
+	resourceTTL time.Duration
+
+	// current holds the resources of the last snapshot stored
+	// in defaultCache.
+	current map[envoy_resource_v3.Type][]envoy_types.Resource
+
+	// initialSnapshot is set once defaultCache holds a snapshot
+	// generated from a built DAG.
+	initialSnapshot atomic.Bool
+
+	// version is the version of the snapshot in defaultCache.
+	version atomic.Pointer[string]
 }
 
-// NewSnapshotHandler returns an instance of SnapshotHandler.
-func NewSnapshotHandler(resources []xdscache.ResourceCache, log logrus.FieldLogger) *SnapshotHandler {
+// NewSnapshotHandler returns an instance of SnapshotHandler. If
+// resourceTTL is positive, the runtime resources are given that TTL
+// and the default cache sends heartbeats for them every half TTL,
+// until ctx is done, so that Envoy does not expire them while Contour
+// is healthy.
+//
+// Other resource types never expire: Envoy removes expired resources,
+// so an expired listener, cluster, route, endpoint or secret would take
+// the traffic it serves down, while Envoy falls back to the default
+// value of an expired runtime key.
+func NewSnapshotHandler(ctx context.Context, resources []xdscache.ResourceCache, resourceTTL time.Duration, metrics *metrics.Metrics, log logrus.FieldLogger) *SnapshotHandler {
 	var (
-		defaultCache = envoy_cache_v3.NewSnapshotCache(false, &contour_xds_v3.Hash, log.WithField("context", "defaultCache"))
-		edsCache     = envoy_cache_v3.NewSnapshotCache(false, &contour_xds_v3.Hash, log.WithField("context", "edsCache"))
+		defaultCache = newSnapshotCache(ctx, resourceTTL, log.WithField("context", "defaultCache"))
+		edsCache     = newSnapshotCache(ctx, 0, log.WithField("context", "edsCache"))
 
 		mux = &envoy_cache_v3.MuxCache{
 			Caches: map[string]envoy_cache_v3.Cache{},
@@ -70,7 +99,9 @@ func NewSnapshotHandler(resources []xdscache.ResourceCache, log logrus.FieldLogg
 		defaultCache: defaultCache,
 		edsCache:     edsCache,
 		mux:          mux,
+		metrics:      metrics,
 		log:          log,
+		resourceTTL:  resourceTTL,
 	}
 
 	// Trigger an initial snapshot, based on any static values
@@ -95,7 +126,7 @@ func (s *SnapshotHandler) Refresh() {
 		envoy_resource_v3.EndpointType: asResources(s.resources[envoy_resource_v3.EndpointType].Contents()),
 	}
 
-	snapshot, err := envoy_cache_v3.NewSnapshot(version, resources)
+	snapshot, err := s.newSnapshot(version, resources)
 	if err != nil {
 		s.log.Errorf("failed to generate snapshot version %q: %s", version, err)
 		return
@@ -105,12 +136,14 @@ func (s *SnapshotHandler) Refresh() {
 		s.log.Errorf("failed to store snapshot version %q: %s", version, err)
 		return
 	}
+
+	s.recordSnapshot("endpoints", version, resources)
 }
 
 // OnChange is called when the DAG is rebuilt and a new snapshot is needed.
 // It creates and caches a new go-control-plane Snapshot based on the
 // contents of the Contour xDS resource caches.
-func (s *SnapshotHandler) OnChange(*dag.DAG) {
+func (s *SnapshotHandler) OnChange(root *dag.DAG) {
 	// Generate new snapshot version.
 	version := uuid.NewString()
 
@@ -126,7 +159,11 @@ func (s *SnapshotHandler) OnChange(*dag.DAG) {
 		resources[resourceType] = asResources(resourceCache.Contents())
 	}
 
-	snapshot, err := envoy_cache_v3.NewSnapshot(version, resources)
+	// Record whether the resources changed since the last snapshot,
+	// for the DAG rebuild snapshot metric.
+	changed := s.current == nil || !resourcesEqual(s.current, resources)
+
+	snapshot, err := s.newSnapshot(version, resources)
 	if err != nil {
 		s.log.Errorf("failed to generate snapshot version %q: %s", version, err)
 		return
@@ -136,6 +173,100 @@ func (s *SnapshotHandler) OnChange(*dag.DAG) {
 		s.log.Errorf("failed to store snapshot version %q: %s", version, err)
 		return
 	}
+
+	s.current = resources
+	s.version.Store(&version)
+	s.recordSnapshot("default", version, resources)
+
+	// The initial snapshot is not the result of a DAG rebuild.
+	if root != nil {
+		s.metrics.SetDAGRebuildSnapshot(changed)
+		s.initialSnapshot.Store(true)
+	}
+}
+
+// HasInitialSnapshot returns true once the xDS cache holds a snapshot
+// generated from a built DAG, rather than only the static resources
+// present when the SnapshotHandler was created.
+func (s *SnapshotHandler) HasInitialSnapshot() bool {
+	return s.initialSnapshot.Load()
+}
+
+// Version returns the version of the most recent snapshot of the
+// non-endpoint resources, or the empty string if there is none.
+func (s *SnapshotHandler) Version() string {
+	if version := s.version.Load(); version != nil {
+		return *version
+	}
+	return ""
+}
+
+// newSnapshot returns a snapshot of the given resources, with the
+// handler's resource TTL set on the runtime resources if it is positive.
+func (s *SnapshotHandler) newSnapshot(version string, resources map[envoy_resource_v3.Type][]envoy_types.Resource) (*envoy_cache_v3.Snapshot, error) {
+	if s.resourceTTL <= 0 {
+		return envoy_cache_v3.NewSnapshot(version, resources)
+	}
+
+	withTTLs := make(map[envoy_resource_v3.Type][]envoy_types.ResourceWithTTL, len(resources))
+	for resourceType, rs := range resources {
+		var ttl *time.Duration
+		if resourceType == envoy_resource_v3.RuntimeType {
+			ttl = &s.resourceTTL
+		}
+		for _, r := range rs {
+			withTTLs[resourceType] = append(withTTLs[resourceType], envoy_types.ResourceWithTTL{
+				Resource: r,
+				TTL:      ttl,
+			})
+		}
+	}
+
+	return envoy_cache_v3.NewSnapshotWithTTLs(version, withTTLs)
+}
+
+// newSnapshotCache returns a snapshot cache which, if resourceTTL is
+// positive, sends heartbeats every half TTL until ctx is done.
+func newSnapshotCache(ctx context.Context, resourceTTL time.Duration, log logrus.FieldLogger) envoy_cache_v3.SnapshotCache {
+	if resourceTTL <= 0 {
+		return envoy_cache_v3.NewSnapshotCache(false, &contour_xds_v3.Hash, log)
+	}
+
+	return envoy_cache_v3.NewSnapshotCacheWithHeartbeating(ctx, false, &contour_xds_v3.Hash, log, resourceTTL/2)
+}
+
+// recordSnapshot records the version and resource counts of a snapshot
+// stored in the named cache.
+func (s *SnapshotHandler) recordSnapshot(cache, version string, resources map[envoy_resource_v3.Type][]envoy_types.Resource) {
+	counts := make(map[string]int, len(resources))
+	for resourceType, r := range resources {
+		// Use the unqualified message name, e.g. "Cluster".
+		counts[resourceType[strings.LastIndex(resourceType, ".")+1:]] = len(r)
+	}
+	s.metrics.SetXDSSnapshot(cache, version, counts)
+}
+
+// resourcesEqual returns true if a and b hold equal resources
+// in the same order for every resource type.
+func resourcesEqual(a, b map[envoy_resource_v3.Type][]envoy_types.Resource) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for resourceType, as := range a {
+		bs, ok := b[resourceType]
+		if !ok || len(as) != len(bs) {
+			return false
+		}
+
+		for i := range as {
+			if !proto.Equal(as[i], bs[i]) {
+				return false
+			}
+		}
+	}
+
+	return true
 }
 
 // asResources converts the given slice of values (that implement the envoy_types.Resource
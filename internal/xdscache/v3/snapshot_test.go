@@ -0,0 +1,310 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	envoy_config_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoy_config_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoy_config_route_v3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoy_service_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/service/cluster/v3"
+	envoy_service_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	envoy_service_runtime_v3 "github.com/envoyproxy/go-control-plane/envoy/service/runtime/v3"
+	envoy_resource_v3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	envoy_server_v3 "github.com/envoyproxy/go-control-plane/pkg/server/v3"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/fixture"
+	"github.com/projectcontour/contour/internal/metrics"
+	contour_xds_v3 "github.com/projectcontour/contour/internal/xds/v3"
+	"github.com/projectcontour/contour/internal/xdscache"
+)
+
+func TestSnapshotHandlerMetrics(t *testing.T) {
+	clusters := &ClusterCache{}
+	routes := &RouteCache{}
+	endpoints := NewEndpointsTranslator(fixture.NewTestLogger(t))
+
+	registry := prometheus.NewRegistry()
+	sh := NewSnapshotHandler(
+		context.Background(),
+		[]xdscache.ResourceCache{clusters, routes, &SecretCache{}, endpoints},
+		0,
+		metrics.NewMetrics(registry),
+		fixture.NewTestLogger(t),
+	)
+
+	gather := func() (map[string]float64, map[string]string, map[string]float64) {
+		families, err := registry.Gather()
+		require.NoError(t, err)
+
+		counts := map[string]float64{}
+		versions := map[string]string{}
+		rebuilds := map[string]float64{}
+		for _, mf := range families {
+			for _, m := range mf.Metric {
+				labels := map[string]string{}
+				for _, l := range m.Label {
+					labels[l.GetName()] = l.GetValue()
+				}
+				switch mf.GetName() {
+				case metrics.XDSSnapshotResourcesGauge:
+					counts[labels["type"]] = m.GetGauge().GetValue()
+				case metrics.XDSSnapshotVersionInfoGauge:
+					versions[labels["cache"]] = labels["version"]
+				case metrics.DAGRebuildSnapshotTotal:
+					rebuilds[labels["changed"]] = m.GetCounter().GetValue()
+				}
+			}
+		}
+		return counts, versions, rebuilds
+	}
+
+	counts, versions, _ := gather()
+	assert.Equal(t, map[string]float64{"Cluster": 0, "RouteConfiguration": 0, "Secret": 0}, counts)
+	assert.NotEmpty(t, versions["default"])
+	initialVersion := versions["default"]
+
+	clusters.Update(map[string]*envoy_config_cluster_v3.Cluster{
+		"default/a/80": {Name: "default/a/80"},
+		"default/b/80": {Name: "default/b/80"},
+	})
+	routes.Update(map[string]*envoy_config_route_v3.RouteConfiguration{
+		"ingress_http": {Name: "ingress_http"},
+	})
+	sh.OnChange(&dag.DAG{})
+	sh.Refresh()
+
+	counts, versions, rebuilds := gather()
+	assert.Equal(t, map[string]float64{
+		"Cluster":               2,
+		"RouteConfiguration":    1,
+		"Secret":                0,
+		"ClusterLoadAssignment": 0,
+	}, counts)
+	assert.NotEqual(t, initialVersion, versions["default"])
+	assert.NotEmpty(t, versions["endpoints"])
+	assert.Equal(t, map[string]float64{"true": 1}, rebuilds)
+
+	// A rebuild that leaves the resources unchanged is counted separately.
+	sh.OnChange(&dag.DAG{})
+
+	_, _, rebuilds = gather()
+	assert.Equal(t, map[string]float64{"true": 1, "false": 1}, rebuilds)
+}
+
+func TestSnapshotHandlerHasInitialSnapshot(t *testing.T) {
+	sh := NewSnapshotHandler(
+		context.Background(),
+		[]xdscache.ResourceCache{&ClusterCache{}, NewEndpointsTranslator(fixture.NewTestLogger(t))},
+		0,
+		metrics.NewMetrics(prometheus.NewRegistry()),
+		fixture.NewTestLogger(t),
+	)
+
+	// The static snapshot stored on creation does not count.
+	assert.False(t, sh.HasInitialSnapshot())
+
+	// Neither does an endpoints update.
+	sh.Refresh()
+	assert.False(t, sh.HasInitialSnapshot())
+
+	// A snapshot of a built DAG does, even if it is unchanged.
+	sh.OnChange(&dag.DAG{})
+	assert.True(t, sh.HasInitialSnapshot())
+}
+
+func TestSnapshotHandlerVersion(t *testing.T) {
+	sh := NewSnapshotHandler(
+		context.Background(),
+		[]xdscache.ResourceCache{&ClusterCache{}, NewEndpointsTranslator(fixture.NewTestLogger(t))},
+		0,
+		metrics.NewMetrics(prometheus.NewRegistry()),
+		fixture.NewTestLogger(t),
+	)
+
+	// The static snapshot stored on creation has a version.
+	version := sh.Version()
+	assert.NotEmpty(t, version)
+
+	// Endpoints updates do not change it.
+	sh.Refresh()
+	assert.Equal(t, version, sh.Version())
+
+	// A rebuild stores a new snapshot, even if the resources are unchanged.
+	sh.OnChange(&dag.DAG{})
+	assert.NotEqual(t, version, sh.Version())
+}
+
+func TestSnapshotHandlerDeltaXDS(t *testing.T) {
+	log := fixture.NewTestLogger(t)
+	clusters := &ClusterCache{DeltaXDS: true}
+	sh := NewSnapshotHandler(
+		context.Background(),
+		[]xdscache.ResourceCache{clusters},
+		0,
+		metrics.NewMetrics(prometheus.NewRegistry()),
+		log,
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	g := grpc.NewServer()
+	contour_xds_v3.RegisterServer(envoy_server_v3.NewServer(ctx, sh.GetCache(), contour_xds_v3.NewRequestLoggingCallbacks(log)), g)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		_ = g.Serve(l)
+	}()
+	defer g.Stop()
+
+	conn, err := grpc.NewClient(l.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	stream, err := envoy_service_cluster_v3.NewClusterDiscoveryServiceClient(conn).DeltaClusters(ctx)
+	require.NoError(t, err)
+
+	// Subscribe to all clusters.
+	require.NoError(t, stream.Send(&envoy_service_discovery_v3.DeltaDiscoveryRequest{
+		Node:    &envoy_config_core_v3.Node{Id: "envoy"},
+		TypeUrl: envoy_resource_v3.ClusterType,
+	}))
+
+	// Adding a cluster sends only that cluster.
+	clusters.Update(map[string]*envoy_config_cluster_v3.Cluster{
+		"default/a/80": {Name: "default/a/80"},
+	})
+	sh.OnChange(&dag.DAG{})
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	require.Len(t, resp.GetResources(), 1)
+	assert.Equal(t, "default/a/80", resp.GetResources()[0].GetName())
+	assert.Empty(t, resp.GetRemovedResources())
+
+	require.NoError(t, stream.Send(&envoy_service_discovery_v3.DeltaDiscoveryRequest{
+		TypeUrl:       envoy_resource_v3.ClusterType,
+		ResponseNonce: resp.GetNonce(),
+	}))
+
+	// Adding a second cluster sends only the new cluster.
+	clusters.Update(map[string]*envoy_config_cluster_v3.Cluster{
+		"default/a/80": {Name: "default/a/80"},
+		"default/b/80": {Name: "default/b/80"},
+	})
+	sh.OnChange(&dag.DAG{})
+
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	require.Len(t, resp.GetResources(), 1)
+	assert.Equal(t, "default/b/80", resp.GetResources()[0].GetName())
+	assert.Empty(t, resp.GetRemovedResources())
+
+	require.NoError(t, stream.Send(&envoy_service_discovery_v3.DeltaDiscoveryRequest{
+		TypeUrl:       envoy_resource_v3.ClusterType,
+		ResponseNonce: resp.GetNonce(),
+	}))
+
+	// Removing a cluster sends only its name.
+	clusters.Update(map[string]*envoy_config_cluster_v3.Cluster{
+		"default/b/80": {Name: "default/b/80"},
+	})
+	sh.OnChange(&dag.DAG{})
+
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Empty(t, resp.GetResources())
+	assert.Equal(t, []string{"default/a/80"}, resp.GetRemovedResources())
+}
+
+func TestSnapshotHandlerResourceTTL(t *testing.T) {
+	log := fixture.NewTestLogger(t)
+	clusters := &ClusterCache{}
+	clusters.Update(map[string]*envoy_config_cluster_v3.Cluster{
+		"default/a/80": {Name: "default/a/80"},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sh := NewSnapshotHandler(
+		ctx,
+		[]xdscache.ResourceCache{clusters, NewRuntimeCache(ConfigurableRuntimeSettings{})},
+		30*time.Second,
+		metrics.NewMetrics(prometheus.NewRegistry()),
+		log,
+	)
+
+	g := grpc.NewServer()
+	contour_xds_v3.RegisterServer(envoy_server_v3.NewServer(ctx, sh.GetCache(), contour_xds_v3.NewRequestLoggingCallbacks(log)), g)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		_ = g.Serve(l)
+	}()
+	defer g.Stop()
+
+	conn, err := grpc.NewClient(l.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Runtime resources are wrapped in a Resource carrying the TTL.
+	runtimeStream, err := envoy_service_runtime_v3.NewRuntimeDiscoveryServiceClient(conn).StreamRuntime(ctx)
+	require.NoError(t, err)
+	require.NoError(t, runtimeStream.Send(&envoy_service_discovery_v3.DiscoveryRequest{
+		Node:          &envoy_config_core_v3.Node{Id: "envoy"},
+		TypeUrl:       envoy_resource_v3.RuntimeType,
+		ResourceNames: []string{"dynamic"},
+	}))
+
+	resp, err := runtimeStream.Recv()
+	require.NoError(t, err)
+	require.Len(t, resp.GetResources(), 1)
+
+	var wrapped envoy_service_discovery_v3.Resource
+	require.NoError(t, resp.GetResources()[0].UnmarshalTo(&wrapped))
+	assert.Equal(t, "dynamic", wrapped.GetName())
+	assert.Equal(t, 30*time.Second, wrapped.GetTtl().AsDuration())
+	assert.NotNil(t, wrapped.GetResource())
+
+	// Other resources never expire, so they are sent without a TTL.
+	clusterStream, err := envoy_service_cluster_v3.NewClusterDiscoveryServiceClient(conn).StreamClusters(ctx)
+	require.NoError(t, err)
+	require.NoError(t, clusterStream.Send(&envoy_service_discovery_v3.DiscoveryRequest{
+		Node:    &envoy_config_core_v3.Node{Id: "envoy"},
+		TypeUrl: envoy_resource_v3.ClusterType,
+	}))
+
+	resp, err = clusterStream.Recv()
+	require.NoError(t, err)
+	require.Len(t, resp.GetResources(), 1)
+	assert.Equal(t, envoy_resource_v3.ClusterType, resp.GetResources()[0].GetTypeUrl())
+
+	var cluster envoy_config_cluster_v3.Cluster
+	require.NoError(t, resp.GetResources()[0].UnmarshalTo(&cluster))
+	assert.Equal(t, "default/a/80", cluster.GetName())
+}
@@ -196,6 +196,10 @@ type TLSParameters struct {
 	// to be used when establishing TLS connection to upstream
 	// cluster.
 	ClientCertificate NamespacedName `yaml:"envoy-client-certificate,omitempty"`
+
+	// DisableSessionTickets stops Envoy TLS listeners issuing
+	// TLS session tickets.
+	DisableSessionTickets bool `yaml:"disable-session-tickets,omitempty"`
 }
 
 // ProtocolParameters holds configuration details for TLS protocol specifics.
@@ -245,6 +249,23 @@ type ServerParameters struct {
 	// Deprecated: this field will be removed in a future release when
 	// the `contour` xDS server implementation is removed.
 	XDSServerType ServerType `yaml:"xds-server-type,omitempty"`
+
+	// XDSDelta makes the resources served by the xDS server refer
+	// to the route and endpoint resources they depend on with the
+	// incremental (delta) xDS protocol. Requires the "envoy" xDS
+	// server type.
+	XDSDelta bool `yaml:"xds-delta,omitempty"`
+
+	// XDSResourceTTL is how long Envoy keeps the runtime settings it
+	// received from the xDS server once the server stops refreshing
+	// them. Other resources never expire. Requires the "envoy" xDS
+	// server type. Unset by default, so runtime settings never expire.
+	XDSResourceTTL string `yaml:"xds-resource-ttl,omitempty"`
+
+	// ShutdownDrainTime is how long Contour keeps serving xDS after
+	// it receives SIGTERM, while reporting itself as not ready so that
+	// it is removed from Service endpoints. Defaults to 0s.
+	ShutdownDrainTime string `yaml:"shutdown-drain-time,omitempty"`
 }
 
 // GatewayParameters holds the configuration for Gateway API controllers.
@@ -398,6 +419,14 @@ type PolicyParameters struct {
 
 	// ApplyToIngress determines if the Policies will apply to ingress objects
 	ApplyToIngress bool `yaml:"applyToIngress,omitempty"`
+
+	// ContourVersionHeader determines if an X-Contour-Version header is
+	// added to all responses.
+	ContourVersionHeader bool `yaml:"contour-version-header,omitempty"`
+
+	// RouteIdentifierHeader is the name of a request header that is set
+	// on every proxied request to an identifier of the matched route.
+	RouteIdentifierHeader string `yaml:"route-identifier-header,omitempty"`
 }
 
 // Validate the header parameters.
@@ -405,7 +434,10 @@ func (h PolicyParameters) Validate() error {
 	if err := h.RequestHeadersPolicy.Validate(); err != nil {
 		return err
 	}
-	return h.ResponseHeadersPolicy.Validate()
+	if err := h.ResponseHeadersPolicy.Validate(); err != nil {
+		return err
+	}
+	return contour_v1alpha1.ValidateRouteIdentifierHeader(h.RouteIdentifierHeader)
 }
 
 // ClusterParameters holds various configurable cluster values.
@@ -523,11 +555,70 @@ type ListenerParameters struct {
 	// unlimited.
 	HTTP2MaxConcurrentStreams *uint32 `yaml:"http2-max-concurrent-streams,omitempty"`
 
+	// HTTP2KeepaliveInterval is how often Envoy sends an HTTP/2 PING frame
+	// on each idle downstream HTTP/2 connection. It must be set together
+	// with HTTP2KeepaliveTimeout. The default is to not send PINGs.
+	HTTP2KeepaliveInterval string `yaml:"http2-keepalive-interval,omitempty"`
+
+	// HTTP2KeepaliveTimeout is how long Envoy waits for a response to an
+	// HTTP/2 keepalive PING before closing the connection.
+	HTTP2KeepaliveTimeout string `yaml:"http2-keepalive-timeout,omitempty"`
+
 	// Defines the limit on number of active connections to a listener. The limit is applied
 	// per listener. The default value when this is not set is unlimited.
 	//
 	// +optional
 	MaxConnectionsPerListener *uint32 `yaml:"max-connections-per-listener,omitempty"`
+
+	// StripPortFromHost removes any port from the Host/:authority header
+	// before virtual host matching. Cannot be combined with StripMatchingHostPort.
+	StripPortFromHost bool `yaml:"strip-port-from-host,omitempty"`
+
+	// StripMatchingHostPort removes the port from the Host/:authority header
+	// before virtual host matching when it matches the port Envoy's listener
+	// binds to (8080 or 8443 by default), not the Service port clients
+	// connect to. Cannot be combined with StripPortFromHost.
+	StripMatchingHostPort bool `yaml:"strip-matching-host-port,omitempty"`
+
+	// ProxyProtocol configures the PROXY protocol listener filter used
+	// when the --use-proxy-protocol flag is set.
+	ProxyProtocol ProxyProtocolParameters `yaml:"proxy-protocol,omitempty"`
+
+	// HTTP3 configures Envoy to also serve TLS virtual hosts
+	// over HTTP/3 (QUIC).
+	HTTP3 HTTP3Parameters `yaml:"http3,omitempty"`
+
+	// ListenerFiltersTimeout is the maximum time the listener filters,
+	// such as the TLS inspector, may take to inspect a new connection.
+	// Set to "infinity" to disable the timeout. If unset, Envoy's
+	// default of 15s is used.
+	ListenerFiltersTimeout string `yaml:"listener-filters-timeout,omitempty"`
+
+	// ContinueOnListenerFiltersTimeout passes connections whose listener
+	// filters time out on to a filter chain, instead of closing them.
+	ContinueOnListenerFiltersTimeout bool `yaml:"continue-on-listener-filters-timeout,omitempty"`
+
+	// UseRemoteAddress makes Envoy use the address of the downstream
+	// connection as the client address. Disable it when Envoy runs behind
+	// another proxy that sets x-forwarded-for.
+	//
+	// Contour's default is true.
+	UseRemoteAddress *bool `yaml:"use-remote-address,omitempty"`
+
+	// Via is the value Envoy appends to the via header of requests
+	// and responses. If unset, no via header is added.
+	Via string `yaml:"via,omitempty"`
+
+	// AllowAbsoluteURL makes Envoy accept HTTP/1 requests whose target is
+	// an absolute URL. If unset, Envoy's default is used.
+	AllowAbsoluteURL *bool `yaml:"allow-absolute-url,omitempty"`
+
+	// DisableAcceptHTTP10 makes Envoy reject HTTP/1.0 requests.
+	DisableAcceptHTTP10 bool `yaml:"disable-accept-http-10,omitempty"`
+
+	// HTTP10DefaultHost is the host used to route HTTP/1.0 requests
+	// that do not have a Host header.
+	HTTP10DefaultHost string `yaml:"http-10-default-host,omitempty"`
 }
 
 func (p *ListenerParameters) Validate() error {
@@ -551,17 +642,95 @@ func (p *ListenerParameters) Validate() error {
 		return fmt.Errorf("invalid max connections per IO cycle value %q set on listener, minimum value is 1", *p.MaxRequestsPerIOCycle)
 	}
 
-	if p.HTTP2MaxConcurrentStreams != nil && *p.HTTP2MaxConcurrentStreams < 1 {
-		return fmt.Errorf("invalid max HTTP/2 concurrent streams value %q set on listener, minimum value is 1", *p.HTTP2MaxConcurrentStreams)
+	if err := contour_v1alpha1.ValidateHTTP2MaxConcurrentStreams(p.HTTP2MaxConcurrentStreams); err != nil {
+		return fmt.Errorf("invalid listener configuration: http2-max-concurrent-streams: %v", err)
+	}
+
+	if err := contour_v1alpha1.ValidateHTTP2Keepalive(p.HTTP2KeepaliveInterval, p.HTTP2KeepaliveTimeout); err != nil {
+		return err
 	}
 
 	if p.MaxConnectionsPerListener != nil && *p.MaxConnectionsPerListener < 1 {
 		return fmt.Errorf("invalid max connections per listener value %q set on listener, minimum value is 1", *p.MaxConnectionsPerListener)
 	}
 
+	if p.StripPortFromHost && p.StripMatchingHostPort {
+		return fmt.Errorf("invalid listener configuration: strip-port-from-host and strip-matching-host-port cannot both be enabled")
+	}
+
+	if err := contour_v1alpha1.ValidateHeaderValue("listener via", p.Via); err != nil {
+		return err
+	}
+
+	if err := p.ProxyProtocol.Validate(); err != nil {
+		return err
+	}
+
+	if err := contour_v1alpha1.ValidateTimeout("listener filters timeout", p.ListenerFiltersTimeout); err != nil {
+		return err
+	}
+
+	if err := contour_v1alpha1.ValidateHTTP10DefaultHost(!p.DisableAcceptHTTP10, p.HTTP10DefaultHost); err != nil {
+		return err
+	}
+
+	if err := p.HTTP3.Validate(); err != nil {
+		return err
+	}
+
 	return p.SocketOptions.Validate()
 }
 
+// HTTP3Parameters holds the HTTP/3 listener configuration.
+type HTTP3Parameters struct {
+	// Enabled adds a UDP QUIC listener alongside each HTTPS listener
+	// and advertises it to clients with an alt-svc response header.
+	Enabled bool `yaml:"enabled,omitempty"`
+
+	// AdvertisedPort is the UDP port advertised to clients in the
+	// alt-svc response header. Defaults to 443.
+	AdvertisedPort int `yaml:"advertised-port,omitempty"`
+}
+
+func (p *HTTP3Parameters) Validate() error {
+	if p == nil {
+		return nil
+	}
+
+	if p.AdvertisedPort < 0 || p.AdvertisedPort > 65535 {
+		return fmt.Errorf("invalid listener HTTP/3 advertised port %d, must be between 1 and 65535", p.AdvertisedPort)
+	}
+
+	return nil
+}
+
+// ProxyProtocolParameters holds the PROXY protocol listener filter configuration.
+type ProxyProtocolParameters struct {
+	// AllowRequestsWithoutProxyProtocol accepts connections that do not
+	// start with a PROXY protocol header.
+	AllowRequestsWithoutProxyProtocol bool `yaml:"allow-requests-without-proxy-protocol,omitempty"`
+
+	// Versions lists the PROXY protocol versions to accept, "v1" or "v2".
+	// If empty, both versions are accepted.
+	Versions []string `yaml:"versions,omitempty"`
+}
+
+func (p *ProxyProtocolParameters) Validate() error {
+	if p == nil {
+		return nil
+	}
+
+	for _, v := range p.Versions {
+		switch v {
+		case "v1", "v2":
+		default:
+			return fmt.Errorf("invalid listener PROXY protocol version %q, must be one of \"v1\" or \"v2\"", v)
+		}
+	}
+
+	return nil
+}
+
 // SocketOptions defines configurable socket options for Envoy listeners.
 type SocketOptions struct {
 	// Defines the value for IPv4 TOS field (including 6 bit DSCP field) for IP packets originating from Envoy listeners.
@@ -659,6 +828,12 @@ type Parameters struct {
 	// Contour's default is overwrite.
 	ServerHeaderTransformation ServerHeaderTransformationType `yaml:"serverHeaderTransformation,omitempty"`
 
+	// ServerName is the value Envoy uses for the Server response header
+	// when ServerHeaderTransformation overwrites or adds it.
+	//
+	// Contour's default is "envoy".
+	ServerName string `yaml:"serverName,omitempty"`
+
 	// EnableExternalNameService allows processing of ExternalNameServices
 	// Defaults to disabled for security reasons.
 	// TODO(youngnick): put a link to the issue and CVE here.
@@ -858,6 +1033,20 @@ type RateLimitService struct {
 type MetricsParameters struct {
 	Contour MetricsServerParameters `yaml:"contour,omitempty"`
 	Envoy   MetricsServerParameters `yaml:"envoy,omitempty"`
+
+	// EnvoyStats configures Contour to poll an Envoy stats endpoint
+	// and re-export per-listener active connection counts.
+	EnvoyStats EnvoyStatsParameters `yaml:"envoy-stats,omitempty"`
+}
+
+// EnvoyStatsParameters defines how Contour polls Envoy for stats.
+type EnvoyStatsParameters struct {
+	// URL is the address of an Envoy `/stats` endpoint.
+	URL string `yaml:"url,omitempty"`
+
+	// PollInterval defines how often Contour polls the URL.
+	// Polling is disabled if unset or zero.
+	PollInterval string `yaml:"poll-interval,omitempty"`
 }
 
 // MetricsServerParameters defines configuration for metrics server.
@@ -892,6 +1081,13 @@ func (p *MetricsParameters) Validate() error {
 	if err := p.Envoy.Validate(); err != nil {
 		return fmt.Errorf("metrics.envoy: %v", err)
 	}
+	collector := contour_v1alpha1.EnvoyStatsCollectorConfig{
+		URL:          p.EnvoyStats.URL,
+		PollInterval: p.EnvoyStats.PollInterval,
+	}
+	if err := collector.Validate(); err != nil {
+		return fmt.Errorf("metrics.envoy-stats: %v", err)
+	}
 
 	return nil
 }
@@ -976,6 +1172,21 @@ func (p *Parameters) Validate() error {
 		return err
 	}
 
+	if p.Server.XDSDelta && p.Server.XDSServerType == ContourServerType {
+		return fmt.Errorf("server.xds-delta requires the %q xDS server type", EnvoyServerType)
+	}
+
+	if err := contour_v1alpha1.ValidateXDSResourceTTL(p.Server.XDSResourceTTL); err != nil {
+		return err
+	}
+	if p.Server.XDSResourceTTL != "" && p.Server.XDSServerType == ContourServerType {
+		return fmt.Errorf("server.xds-resource-ttl requires the %q xDS server type", EnvoyServerType)
+	}
+
+	if err := contour_v1alpha1.ValidateShutdownDrainTime(p.Server.ShutdownDrainTime); err != nil {
+		return err
+	}
+
 	if err := p.GatewayConfig.Validate(); err != nil {
 		return err
 	}
@@ -996,10 +1207,18 @@ func (p *Parameters) Validate() error {
 		return err
 	}
 
+	if err := contour_v1alpha1.ValidateHeaderValue("serverName", p.ServerName); err != nil {
+		return err
+	}
+
 	if err := p.TLS.Validate(); err != nil {
 		return err
 	}
 
+	if p.Listener.HTTP3.Enabled && p.TLS.MaximumProtocolVersion == "1.2" {
+		return fmt.Errorf("invalid listener configuration: http3 requires TLS 1.3 but tls.maximum-protocol-version is 1.2")
+	}
+
 	if err := p.Timeouts.Validate(); err != nil {
 		return err
 	}
@@ -1059,6 +1278,7 @@ func Defaults() Parameters {
 			RequestHeadersPolicy:  HeadersPolicy{},
 			ResponseHeadersPolicy: HeadersPolicy{},
 			ApplyToIngress:        false,
+			ContourVersionHeader:  false,
 		},
 		EnvoyServiceName:      "envoy",
 		EnvoyServiceNamespace: contourNamespace,
@@ -1080,26 +1300,118 @@ func Defaults() Parameters {
 // not specified by the input are according to Defaults().
 func Parse(in io.Reader) (*Parameters, error) {
 	conf := Defaults()
+
+	if err := decode(&conf, in); err != nil {
+		return nil, err
+	}
+
+	normalize(&conf)
+
+	return &conf, nil
+}
+
+// ParseFiles reads parameters from the YAML files at the given paths and
+// merges them in order. A path may also name a directory, in which case
+// the ".yaml" and ".yml" files it contains are read in lexical order.
+//
+// Each file is decoded on top of the result of the previous ones, so:
+//   - scalar fields set in a later file override earlier values,
+//   - nested structures and map fields are merged key by key,
+//   - list fields set in a later file replace earlier lists.
+//
+// Any parameters not specified by the files are according to Defaults().
+func ParseFiles(paths ...string) (*Parameters, error) {
+	files, err := expandConfigPaths(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	conf := Defaults()
+
+	for _, file := range files {
+		if err := decodeFile(&conf, file); err != nil {
+			return nil, err
+		}
+	}
+
+	normalize(&conf)
+
+	return &conf, nil
+}
+
+func decodeFile(conf *Parameters, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := decode(conf, f); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	return nil
+}
+
+func decode(conf *Parameters, in io.Reader) error {
 	decoder := yaml.NewDecoder(in)
 
 	decoder.KnownFields(true)
 
-	if err := decoder.Decode(&conf); err != nil {
+	if err := decoder.Decode(conf); err != nil {
 		// The YAML decoder will return EOF if there are
 		// no YAML nodes in the results. In this case, we just
-		// want to succeed and return the defaults.
+		// want to succeed and keep the existing values.
 		if err != io.EOF {
-			return nil, fmt.Errorf("failed to parse configuration: %w", err)
+			return fmt.Errorf("failed to parse configuration: %w", err)
 		}
 	}
 
+	return nil
+}
+
+func normalize(conf *Parameters) {
 	// Force the version string to match the lowercase version
 	// constants (assuming that it will match).
 	for i, v := range conf.DefaultHTTPVersions {
 		conf.DefaultHTTPVersions[i] = HTTPVersionType(strings.ToLower(string(v)))
 	}
+}
 
-	return &conf, nil
+// expandConfigPaths replaces each directory in paths with the
+// YAML files it contains, sorted lexically.
+func expandConfigPaths(paths []string) ([]string, error) {
+	var files []string
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+
+		// os.ReadDir returns entries sorted by filename.
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			switch filepath.Ext(entry.Name()) {
+			case ".yaml", ".yml":
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+
+	return files, nil
 }
 
 // GetenvOr reads an environment or return a default value
@@ -15,6 +15,7 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -121,6 +122,102 @@ policy:
 	assert.Equal(t, &wanted, conf)
 }
 
+func TestParseFiles(t *testing.T) {
+	writeFile := func(t *testing.T, dir, name, contents string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+		return path
+	}
+
+	dir := t.TempDir()
+
+	base := writeFile(t, dir, "base.yaml", `
+disablePermitInsecure: true
+accesslog-format: json
+timeouts:
+  request-timeout: 30s
+  connect-timeout: 5s
+policy:
+  request-headers:
+    set:
+      X-Env: base
+      X-Team: platform
+    remove:
+    - X-Base-Removed
+tls:
+  cipher-suites:
+  - ECDHE-ECDSA-AES128-GCM-SHA256
+  - ECDHE-RSA-AES128-GCM-SHA256
+`)
+
+	override := writeFile(t, dir, "override.yaml", `
+accesslog-format: envoy
+timeouts:
+  request-timeout: 10s
+policy:
+  request-headers:
+    set:
+      X-Env: staging
+    remove:
+    - X-Override-Removed
+tls:
+  cipher-suites:
+  - ECDHE-RSA-AES256-GCM-SHA384
+`)
+
+	conf, err := ParseFiles(base, override)
+	require.NoError(t, err)
+
+	wanted := Defaults()
+	// Set only in the base file.
+	wanted.DisablePermitInsecure = true
+	wanted.Timeouts.ConnectTimeout = "5s"
+	// Scalars from the later file override.
+	wanted.AccessLogFormat = EnvoyAccessLog
+	wanted.Timeouts.RequestTimeout = "10s"
+	// Maps are merged key by key.
+	wanted.Policy.RequestHeadersPolicy.Set = map[string]string{
+		"X-Env":  "staging",
+		"X-Team": "platform",
+	}
+	// Lists are replaced.
+	wanted.Policy.RequestHeadersPolicy.Remove = []string{"X-Override-Removed"}
+	wanted.TLS.CipherSuites = TLSCiphers{"ECDHE-RSA-AES256-GCM-SHA384"}
+
+	assert.Equal(t, &wanted, conf)
+
+	// Reversing the order reverses the precedence.
+	conf, err = ParseFiles(override, base)
+	require.NoError(t, err)
+	assert.Equal(t, JSONAccessLog, conf.AccessLogFormat)
+	assert.Equal(t, "30s", conf.Timeouts.RequestTimeout)
+	assert.Equal(t, map[string]string{"X-Env": "base", "X-Team": "platform"}, conf.Policy.RequestHeadersPolicy.Set)
+	assert.Equal(t, []string{"X-Base-Removed"}, conf.Policy.RequestHeadersPolicy.Remove)
+
+	// Parsing a later file does not modify the defaults.
+	assert.Equal(t, DefaultFields, Defaults().AccessLogFields)
+
+	// A directory is expanded to its YAML files in lexical order.
+	confDir := t.TempDir()
+	writeFile(t, confDir, "10-base.yaml", "accesslog-format: json\ndisablePermitInsecure: true\n")
+	writeFile(t, confDir, "20-override.yml", "accesslog-format: envoy\n")
+	writeFile(t, confDir, "README.md", "not: yaml: at all")
+	require.NoError(t, os.Mkdir(filepath.Join(confDir, "nested.yaml"), 0o700))
+
+	conf, err = ParseFiles(confDir)
+	require.NoError(t, err)
+	assert.Equal(t, EnvoyAccessLog, conf.AccessLogFormat)
+	assert.True(t, conf.DisablePermitInsecure)
+
+	// Errors name the offending file.
+	bad := writeFile(t, dir, "bad.yaml", "foo: bad\n")
+	_, err = ParseFiles(base, bad)
+	require.ErrorContains(t, err, bad)
+
+	_, err = ParseFiles(filepath.Join(dir, "missing.yaml"))
+	require.Error(t, err)
+}
+
 func TestValidateClusterDNSFamilyType(t *testing.T) {
 	require.Error(t, ClusterDNSFamilyType("").Validate())
 	require.Error(t, ClusterDNSFamilyType("foo").Validate())
@@ -344,6 +441,33 @@ server:
   xds-server-type: magic
 `)
 
+	check(`
+server:
+  xds-server-type: contour
+  xds-delta: true
+`)
+
+	check(`
+server:
+  xds-resource-ttl: 100ms
+`)
+
+	check(`
+server:
+  xds-server-type: contour
+  xds-resource-ttl: 30s
+`)
+
+	check(`
+server:
+  shutdown-drain-time: -5s
+`)
+
+	check(`
+server:
+  shutdown-drain-time: 1h
+`)
+
 	check(`
 accesslog-format: /dev/null
 `)
@@ -393,6 +517,46 @@ default-http-versions:
 listener:
   connection-balancer: notexact
 `)
+
+	check(`
+tls:
+  maximum-protocol-version: "1.2"
+listener:
+  http3:
+    enabled: true
+`)
+
+	check(`
+serverName: " envoy "
+`)
+
+	check(`
+listener:
+  via: "1.1 contour\r\n"
+`)
+
+	check(`
+listener:
+  listener-filters-timeout: 10
+`)
+
+	check(`
+policy:
+  route-identifier-header: "x contour route"
+`)
+
+	check(`
+listener:
+  disable-accept-http-10: true
+  http-10-default-host: example.com
+`)
+
+	check(`
+metrics:
+  envoy-stats:
+    url: envoy:8002/stats
+    poll-interval: 10s
+`)
 }
 
 func TestConfigFileDefaultOverrideImport(t *testing.T) {
@@ -495,6 +659,41 @@ listener:
   max-connections-per-listener: 1
 `)
 
+	check(func(t *testing.T, conf *Parameters) {
+		assert.True(t, conf.Listener.StripPortFromHost)
+	}, `
+listener:
+  strip-port-from-host: true
+`)
+
+	check(func(t *testing.T, conf *Parameters) {
+		assert.True(t, conf.TLS.DisableSessionTickets)
+	}, `
+tls:
+  disable-session-tickets: true
+`)
+
+	check(func(t *testing.T, conf *Parameters) {
+		assert.True(t, conf.Listener.HTTP3.Enabled)
+		assert.Equal(t, 8443, conf.Listener.HTTP3.AdvertisedPort)
+	}, `
+listener:
+  http3:
+    enabled: true
+    advertised-port: 8443
+`)
+
+	check(func(t *testing.T, conf *Parameters) {
+		assert.True(t, conf.Listener.ProxyProtocol.AllowRequestsWithoutProxyProtocol)
+		assert.Equal(t, []string{"v2"}, conf.Listener.ProxyProtocol.Versions)
+	}, `
+listener:
+  proxy-protocol:
+    allow-requests-without-proxy-protocol: true
+    versions:
+    - v2
+`)
+
 	check(func(t *testing.T, conf *Parameters) {
 		assert.Equal(t, ptr.To(uint32(1)), conf.Cluster.MaxRequestsPerConnection)
 	}, `
@@ -618,6 +817,24 @@ func TestListenerValidation(t *testing.T) {
 	l = &ListenerParameters{
 		HTTP2MaxConcurrentStreams: ptr.To(uint32(0)),
 	}
+	require.EqualError(t, l.Validate(), "invalid listener configuration: http2-max-concurrent-streams: invalid max HTTP/2 concurrent streams value 0, must be between 1 and 2147483647")
+	l = &ListenerParameters{
+		HTTP2MaxConcurrentStreams: ptr.To(uint32(2147483648)),
+	}
+	require.Error(t, l.Validate())
+	l = &ListenerParameters{
+		HTTP2KeepaliveInterval: "30s",
+		HTTP2KeepaliveTimeout:  "5s",
+	}
+	require.NoError(t, l.Validate())
+	l = &ListenerParameters{
+		HTTP2KeepaliveInterval: "30s",
+	}
+	require.Error(t, l.Validate())
+	l = &ListenerParameters{
+		HTTP2KeepaliveInterval: "30s",
+		HTTP2KeepaliveTimeout:  "0s",
+	}
 	require.Error(t, l.Validate())
 	l = &ListenerParameters{
 		SocketOptions: SocketOptions{
@@ -643,6 +860,23 @@ func TestListenerValidation(t *testing.T) {
 		MaxConnectionsPerListener: ptr.To(uint32(0)),
 	}
 	require.Error(t, l.Validate())
+
+	l = &ListenerParameters{StripPortFromHost: true}
+	require.NoError(t, l.Validate())
+	l = &ListenerParameters{StripMatchingHostPort: true}
+	require.NoError(t, l.Validate())
+	l = &ListenerParameters{StripPortFromHost: true, StripMatchingHostPort: true}
+	require.Error(t, l.Validate())
+
+	l = &ListenerParameters{ProxyProtocol: ProxyProtocolParameters{Versions: []string{"v1", "v2"}}}
+	require.NoError(t, l.Validate())
+	l = &ListenerParameters{ProxyProtocol: ProxyProtocolParameters{Versions: []string{"v3"}}}
+	require.Error(t, l.Validate())
+
+	l = &ListenerParameters{HTTP3: HTTP3Parameters{Enabled: true, AdvertisedPort: 443}}
+	require.NoError(t, l.Validate())
+	l = &ListenerParameters{HTTP3: HTTP3Parameters{Enabled: true, AdvertisedPort: 65536}}
+	require.Error(t, l.Validate())
 }
 
 func TestClusterParametersValidation(t *testing.T) {